@@ -0,0 +1,15 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha1
+
+// Hub marks v1alpha1 as the version that the other versions of Cluster are
+// converted to and from. It is the storage version, and the version the
+// controllers work with.
+func (*Cluster) Hub() {}
@@ -10,12 +10,17 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -26,6 +31,10 @@ const (
 	InternalListenerName = "kafka"
 	// ExternalListenerName is name of external listener
 	ExternalListenerName = "kafka-external"
+
+	// DefaultCanarySoakDuration is how long the canary broker of an image
+	// upgrade runs alone by default
+	DefaultCanarySoakDuration = 10 * time.Minute
 )
 
 // RedpandaResourceRequirements extends corev1.ResourceRequirements
@@ -94,10 +103,28 @@ type ClusterSpec struct {
 
 	// If specified, Redpanda Pod annotations
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// CommonLabels are added to every resource generated for the cluster,
+	// e.g. for cost allocation. The labels set by the operator take
+	// precedence.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+	// CommonAnnotations are added to every resource generated for the
+	// cluster, e.g. for policy controllers. The annotations set by the
+	// operator or by more specific fields take precedence.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
 	// Image is the fully qualified name of the Redpanda container
 	Image string `json:"image,omitempty"`
 	// Version is the Redpanda container tag
 	Version string `json:"version,omitempty"`
+	// ImagePullSecrets reference Secrets in the namespace of the cluster
+	// used to pull the images of the Redpanda pods from private registries
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageRegistry is the prefix of a registry mirror, e.g.
+	// "registry.example.com/mirror", from which the Redpanda and
+	// configurator images are pulled. The registry of the images, if any,
+	// is replaced by the prefix: "vectorized/redpanda" and
+	// "docker.io/vectorized/redpanda" are both pulled as
+	// "registry.example.com/mirror/vectorized/redpanda".
+	ImageRegistry string `json:"imageRegistry,omitempty"`
 	// Replicas determine how big the cluster will be.
 	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -125,10 +152,20 @@ type ClusterSpec struct {
 	Storage StorageSpec `json:"storage,omitempty"`
 	// Cloud storage configuration for cluster
 	CloudStorage CloudStorageConfig `json:"cloudStorage,omitempty"`
+	// PVCRetentionPolicy is what happens to the PersistentVolumeClaims of
+	// the brokers, for the data and the cloud storage cache, when the
+	// cluster is deleted. Retain, the default, keeps them so that a cluster
+	// created again with the same name reuses the data.
+	PVCRetentionPolicy PVCRetentionPolicy `json:"pvcRetentionPolicy,omitempty"`
 	// List of superusers
 	Superusers []Superuser `json:"superUsers,omitempty"`
-	// SASL enablement flag
+	// SASL enablement flag. It requires SASL on every Kafka API listener,
+	// unless the listener sets its own authenticationMethod.
 	EnableSASL bool `json:"enableSasl,omitempty"`
+	// AdminAPIAuthentication requires the clients of the admin API to
+	// authenticate with the basic auth credentials of a superuser. The
+	// operator authenticates with the credentials of its own superuser.
+	AdminAPIAuthentication *AdminAPIAuthentication `json:"adminApiAuthentication,omitempty"`
 	// For configuration parameters not exposed, a map can be provided for string values.
 	// Such values are passed transparently to Redpanda. The key format is "<subsystem>.field", e.g.,
 	//
@@ -153,6 +190,201 @@ type ClusterSpec struct {
 	DNSTrailingDotDisabled bool `json:"dnsTrailingDotDisabled,omitempty"`
 	// RestartConfig allows to control the behavior of the cluster when restarting
 	RestartConfig *RestartConfig `json:"restartConfig,omitempty"`
+	// LicenseRef references a Secret holding a Redpanda enterprise license.
+	// The operator uploads the license to the cluster and reports its
+	// expiration in the status. The license is read from the "license" key
+	// of the Secret, unless a different key is specified.
+	LicenseRef *SecretKeyRef `json:"licenseRef,omitempty"`
+	// Configurator customizes the init container that generates the
+	// configuration of each Redpanda node
+	Configurator *ConfiguratorConfig `json:"configurator,omitempty"`
+	// ServiceAccount configures the ServiceAccount of the Redpanda pods and
+	// the permissions granted to it
+	ServiceAccount *ServiceAccountConfig `json:"serviceAccount,omitempty"`
+	// ValidateClusterConfiguration makes the operator validate the cluster
+	// configuration properties it is about to apply against the
+	// configuration schema of the running cluster. If any property is
+	// unknown or has an invalid value, nothing is applied and the errors are
+	// reported in status.configurationErrors.
+	ValidateClusterConfiguration bool `json:"validateClusterConfiguration,omitempty"`
+	// Networking configures the IP families of the cluster, e.g. to run on
+	// IPv6-only or dual-stack Kubernetes clusters
+	Networking *NetworkingConfig `json:"networking,omitempty"`
+	// DiskValidation runs a disk benchmark on a volume of the storage class
+	// of the cluster before the brokers are started, and reports the
+	// results in status.diskValidation
+	DiskValidation *DiskValidationConfig `json:"diskValidation,omitempty"`
+	// MaintenanceWindows restrict the disruptive operations on the cluster
+	// to the time they are open: rolling updates, whether they are caused
+	// by an upgrade, a configuration change or the restart annotation, and
+	// the decommission of brokers when downscaling. Operations outside of
+	// the windows are deferred and reported in status.pendingMaintenance.
+	// Disruptive operations are never deferred if no window is set.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// SharedCA makes a CA shared by several clusters sign the node and
+	// client certificates of all the TLS enabled APIs, instead of a CA
+	// generated for each cluster and API. Clients then trust every cluster
+	// with a single truststore. It cannot be changed after the cluster is
+	// created.
+	SharedCA *SharedCAConfig `json:"sharedCA,omitempty"`
+	// Bootstrap designates the brokers that form a new cluster. When unset,
+	// the cluster is formed by its first broker alone, and every broker is
+	// a seed server of the others, which restarts all of them on every
+	// scaling.
+	Bootstrap *BootstrapConfig `json:"bootstrap,omitempty"`
+	// ConnectionProfile makes the operator publish how to connect to the
+	// Kafka API listeners of the cluster in a Secret, which application pods
+	// can mount instead of looking up the Services and certificates of the
+	// cluster
+	ConnectionProfile *ConnectionProfileConfig `json:"connectionProfile,omitempty"`
+}
+
+// BootstrapConfig designates the seed servers of the cluster.
+//
+// The brokers of ordinals 0 to seedReplicas-1 are the seed servers of every
+// broker. They start together and form the cluster, which requires Redpanda
+// v22.3 or later: the seed servers then never start a cluster of their own,
+// unlike older versions, where a broker with no seed server does, even when
+// it only lost its data. The other brokers are only started once the
+// controller of the cluster has a leader and every seed server has joined.
+type BootstrapConfig struct {
+	// SeedReplicas is the number of seed servers. It must not exceed the
+	// replicas of the cluster, which then cannot be downscaled below it, and
+	// cannot be changed once set. An odd number, e.g. 3, lets the controller
+	// of the cluster form while a seed server is down.
+	// +kubebuilder:validation:Minimum=1
+	SeedReplicas int32 `json:"seedReplicas"`
+}
+
+// ConnectionProfileConfig configures the connection profile of the cluster.
+//
+// The profile is a Secret named after the cluster with the
+// -connection-profile suffix. For the internal Kafka API listener, it holds
+// an rpk configuration in rpk.yaml, the properties of a Kafka client in
+// client.properties and the comma separated addresses of the brokers in
+// brokers. The external listener has the same keys with the -external
+// suffix, e.g. rpk-external.yaml. If a listener has TLS, the CA certificate
+// of the brokers is in ca.crt. The profile has no credentials: clients of
+// listeners requiring SASL or client certificates add their own.
+type ConnectionProfileConfig struct {
+	// Enabled publishes the connection profile
+	Enabled bool `json:"enabled"`
+	// MountPath is the directory at which the profile is expected to be
+	// mounted, which the configurations reference ca.crt from. It defaults
+	// to /etc/redpanda-profile.
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+	// ConfigMap also publishes the profile in a ConfigMap of the same name,
+	// for clients that can only mount ConfigMaps
+	// +optional
+	ConfigMap bool `json:"configMap,omitempty"`
+}
+
+// SharedCAConfig references the issuer of a CA shared by several clusters.
+//
+// Every client certificate signed by the CA is trusted by the APIs that
+// require client authentication, including the client certificates of other
+// clusters sharing the CA.
+type SharedCAConfig struct {
+	// IssuerRef references a cert-manager ClusterIssuer, e.g. a CA issuer
+	// backed by the CA of an organization, which is the only kind of issuer
+	// that clusters of different namespaces can share.
+	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
+}
+
+// MaintenanceWindow is a recurring period of time during which disruptive
+// operations are allowed. A rolling update that does not complete within a
+// window is paused between two pods until the next window opens, so windows
+// should be long enough to restart all the brokers.
+type MaintenanceWindow struct {
+	// Schedule is when the window opens, in the cron format "minute hour
+	// day-of-month month day-of-week", e.g. "0 2 * * 1-5" for 2am on
+	// weekdays
+	Schedule string `json:"schedule"`
+	// Duration of the window, e.g. 4h
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone of the schedule, as an IANA time zone name, e.g.
+	// Europe/Paris. Defaults to UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DiskValidationConfig configures the disk benchmark run before the brokers
+// of a new cluster are started. The benchmark runs "rpk iotune" in a Job, on
+// a temporary volume of the storage class and capacity of spec.storage. If the
+// storage does not meet the expectations, a warning event is emitted; the
+// brokers are started regardless.
+type DiskValidationConfig struct {
+	// MinWriteBandwidth is the expected write throughput of the storage,
+	// in bytes per second, e.g. 100Mi
+	MinWriteBandwidth *resource.Quantity `json:"minWriteBandwidth,omitempty"`
+	// MinWriteIOPS is the expected write IOPS of the storage
+	// +kubebuilder:validation:Minimum=0
+	MinWriteIOPS int64 `json:"minWriteIOPS,omitempty"`
+	// Duration of the benchmark, 30s by default
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// NetworkingConfig configures the IP families of the cluster Services and
+// of the Redpanda listeners. When IPv6 is one of the families, the listeners
+// bind to the IPv6 wildcard address, which also accepts IPv4 connections on
+// dual-stack nodes.
+type NetworkingConfig struct {
+	// IPFamilies of the cluster Services, in order of preference. The
+	// Kubernetes default is used if not set. The primary (first) family
+	// cannot be changed once the cluster is created.
+	// +kubebuilder:validation:MaxItems=2
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+	// IPFamilyPolicy of the cluster Services. The Kubernetes default is used
+	// if not set.
+	IPFamilyPolicy *corev1.IPFamilyPolicyType `json:"ipFamilyPolicy,omitempty"`
+}
+
+// ServiceAccountConfig configures the ServiceAccount of the Redpanda pods. By
+// default, the operator creates a dedicated ServiceAccount for each cluster
+// with external connectivity, which is allowed to get Kubernetes nodes so that
+// the configurator can look up the node addresses. When this config is
+// specified, the dedicated ServiceAccount is created regardless of external
+// connectivity.
+type ServiceAccountConfig struct {
+	// Name of an existing ServiceAccount used by the Redpanda pods instead of
+	// the dedicated one. The operator does not create nor modify this
+	// ServiceAccount; Annotations and Rules must be empty.
+	Name string `json:"name,omitempty"`
+	// Annotations of the dedicated ServiceAccount
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Rules granted to the ServiceAccount in the namespace of the cluster,
+	// through a Role and RoleBinding dedicated to the cluster. The operator
+	// must itself hold the permissions it grants.
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// ConfiguratorConfig customizes the configurator init container. The image
+// can be overridden independently of the Redpanda image, and hooks can run
+// custom scripts before and after the node configuration is generated, e.g.
+// to fetch certificates from a secret store or detect the node topology.
+type ConfiguratorConfig struct {
+	// Image overrides the configurator image set in the operator flags
+	Image string `json:"image,omitempty"`
+	// Tag overrides the configurator tag set in the operator flags
+	Tag string `json:"tag,omitempty"`
+	// PreStartHook runs before the configurator generates the node
+	// configuration
+	PreStartHook *ConfiguratorHook `json:"preStartHook,omitempty"`
+	// PostStartHook runs after the configurator has written the node
+	// configuration, which it can modify
+	PostStartHook *ConfiguratorHook `json:"postStartHook,omitempty"`
+}
+
+// ConfiguratorHook is a shell script run by the configurator init container,
+// which requires the configurator image to contain /bin/sh. Exactly one of
+// Script and ConfigMapRef must be specified. The script inherits the
+// environment of the configurator, and the init container fails if the script
+// exits with a non-zero status.
+type ConfiguratorHook struct {
+	// Script is the inline content of the script
+	Script string `json:"script,omitempty"`
+	// ConfigMapRef references the key of a ConfigMap holding the script
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
 }
 
 // RestartConfig contains strategies to configure how the cluster behaves when restarting, because of upgrades
@@ -160,6 +392,49 @@ type ClusterSpec struct {
 type RestartConfig struct {
 	// DisableMaintenanceModeHooks deactivates the preStop and postStart hooks that force nodes to enter maintenance mode when stopping and exit maintenance mode when up again
 	DisableMaintenanceModeHooks *bool `json:"disableMaintenanceModeHooks,omitempty"`
+	// UpdateStrategy controls how many pods can be restarted at the same time
+	// during a rolling update
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+// UpdateStrategy configures the parallelism of rolling updates. By default
+// pods are restarted one at a time. When MaxUnavailable is greater than 1,
+// the operator restarts up to MaxUnavailable pods in parallel, but only if
+// they are all scheduled in the same rack and the cluster health overview
+// reports no nodes down, no leaderless and no under-replicated partitions.
+// Otherwise it falls back to restarting a single pod.
+type UpdateStrategy struct {
+	// MaxUnavailable is the maximum number of pods that can be restarted at
+	// the same time. Defaults to 1. Pods are evicted, so the parallelism is
+	// also capped by the PodDisruptionBudget: raise
+	// spec.podDisruptionBudget.maxUnavailable too, which defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+	// RackLabel is the Kubernetes node label used to group brokers in racks.
+	// Pods scheduled on nodes without this label are always restarted one at
+	// a time. Defaults to topology.kubernetes.io/zone
+	RackLabel string `json:"rackLabel,omitempty"`
+	// Canary upgrades a single broker first when the image changes, and
+	// soaks it before the other brokers are upgraded
+	Canary *CanaryUpgrade `json:"canary,omitempty"`
+}
+
+// CanaryUpgrade configures canary image upgrades. When the image of the
+// brokers changes, the operator records the cluster health overview as a
+// baseline and upgrades the first outdated broker alone. The canary is then
+// soaked for SoakDuration, and fails if its pod restarts, if it is not ready
+// at the end of the soak, or if the cluster is less healthy than the
+// baseline, i.e. has more nodes down or more leaderless or under-replicated
+// partitions. The other brokers are upgraded after a successful soak. A failed
+// canary is rolled back to the previous image, and the upgrade stops until the
+// image of the cluster changes again. Clusters with a single replica are
+// upgraded without a canary.
+type CanaryUpgrade struct {
+	// Enabled turns on canary upgrades
+	Enabled bool `json:"enabled,omitempty"`
+	// SoakDuration is how long the canary runs before the other brokers
+	// are upgraded. Defaults to 10m
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
 }
 
 // PDBConfig specifies how the PodDisruptionBudget should be created for the
@@ -207,6 +482,15 @@ type Superuser struct {
 	Username string `json:"username"`
 }
 
+// AdminAPIAuthentication configures how the operator authenticates to the
+// admin API
+type AdminAPIAuthentication struct {
+	// CredentialsSecretRef references a Secret of type kubernetes.io/basic-auth,
+	// in the namespace of the cluster, with the username and password of the
+	// operator. The operator bootstraps the user and makes it a superuser.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+}
+
 // CloudStorageConfig configures the Data Archiving feature in Redpanda
 // https://vectorized.io/docs/data-archiving
 type CloudStorageConfig struct {
@@ -237,8 +521,78 @@ type CloudStorageConfig struct {
 	APIEndpointPort int `json:"apiEndpointPort,omitempty"`
 	// Cache directory that will be mounted for Redpanda
 	CacheStorage *StorageSpec `json:"cacheStorage,omitempty"`
+	// CredentialsSource is where Redpanda gets the credentials to access
+	// the bucket from. With config_file (the default), AccessKey and
+	// SecretKeyRef are used. The other sources do not need any secret:
+	// aws_instance_metadata uses the IAM role of the Kubernetes node, sts
+	// the IAM role of the ServiceAccount (IRSA on EKS), and
+	// gcp_instance_metadata the Google service account of the node or of
+	// the ServiceAccount (Workload Identity on GKE).
+	CredentialsSource CloudStorageCredentialsSource `json:"credentialsSource,omitempty"`
+	// IAMRole is the cloud identity the Redpanda pods assume, when
+	// CredentialsSource is sts or gcp_instance_metadata: the ARN of an IAM
+	// role, or the email of a Google service account. It is set as an
+	// annotation of the dedicated ServiceAccount of the cluster.
+	IAMRole string `json:"iamRole,omitempty"`
+}
+
+// CloudStorageCredentialsSource is a source of cloud storage credentials
+// +kubebuilder:validation:Enum=config_file;aws_instance_metadata;sts;gcp_instance_metadata
+type CloudStorageCredentialsSource string
+
+// These are the valid sources of cloud storage credentials
+const (
+	CredentialsSourceConfigFile          CloudStorageCredentialsSource = "config_file"
+	CredentialsSourceAWSInstanceMetadata CloudStorageCredentialsSource = "aws_instance_metadata"
+	CredentialsSourceSTS                 CloudStorageCredentialsSource = "sts"
+	CredentialsSourceGCPInstanceMetadata CloudStorageCredentialsSource = "gcp_instance_metadata"
+)
+
+// Annotations of the ServiceAccount binding it to a cloud identity
+const (
+	// IRSARoleAnnotation is the annotation of IAM roles for service
+	// accounts on EKS
+	IRSARoleAnnotation = "eks.amazonaws.com/role-arn"
+	// WorkloadIdentityAnnotation is the annotation of Workload Identity on
+	// GKE
+	WorkloadIdentityAnnotation = "iam.gke.io/gcp-service-account"
+)
+
+// UsesStaticCredentials returns whether the access and secret keys are used
+// to access the cloud storage, rather than a cloud identity
+func (c *CloudStorageConfig) UsesStaticCredentials() bool {
+	return c.CredentialsSource == "" || c.CredentialsSource == CredentialsSourceConfigFile
 }
 
+// ServiceAccountAnnotations returns the annotations binding the ServiceAccount
+// of the cluster to IAMRole, if any
+func (c *CloudStorageConfig) ServiceAccountAnnotations() map[string]string {
+	if !c.Enabled || c.IAMRole == "" {
+		return nil
+	}
+	switch c.CredentialsSource {
+	case CredentialsSourceSTS:
+		return map[string]string{IRSARoleAnnotation: c.IAMRole}
+	case CredentialsSourceGCPInstanceMetadata:
+		return map[string]string{WorkloadIdentityAnnotation: c.IAMRole}
+	}
+	return nil
+}
+
+// PVCRetentionPolicy is what happens to the PersistentVolumeClaims of the
+// brokers when the cluster is deleted
+// +kubebuilder:validation:Enum=Retain;Delete
+type PVCRetentionPolicy string
+
+const (
+	// PVCRetentionPolicyRetain keeps the PersistentVolumeClaims after the
+	// cluster is deleted
+	PVCRetentionPolicyRetain PVCRetentionPolicy = "Retain"
+	// PVCRetentionPolicyDelete deletes the PersistentVolumeClaims once the
+	// brokers of the deleted cluster are gone
+	PVCRetentionPolicyDelete PVCRetentionPolicy = "Delete"
+)
+
 // StorageSpec defines the storage specification of the Cluster
 type StorageSpec struct {
 	// Storage capacity requested
@@ -294,6 +648,32 @@ type ExternalConnectivityConfig struct {
 	PreferredAddressType string `json:"preferredAddressType,omitempty"`
 	// Configures a load balancer for bootstrapping
 	Bootstrap *LoadBalancerConfig `json:"bootstrapLoadBalancer,omitempty"`
+	// AdvertisedPortBase overrides the port advertised by each broker for
+	// the external Kafka API. When set, the broker with index N advertises
+	// the port AdvertisedPortBase+N instead of the node port shared by all
+	// brokers. This is useful when an external load balancer maps a stable
+	// port range to the brokers, e.g. when node ports are remapped.
+	// The load balancer is responsible for forwarding each advertised port
+	// to the node port of the corresponding broker.
+	// This option is only available for the Kafka API.
+	AdvertisedPortBase int `json:"advertisedPortBase,omitempty"`
+	// ExternalDNS configures the annotations read by external-dns
+	// (https://github.com/kubernetes-sigs/external-dns), so that DNS records
+	// are created for the bootstrap load balancer of the Kafka API and for
+	// the ingress of the Pandaproxy API. For the Kafka API, a record is also
+	// created for the endpoint of each broker, <endpoint>.<subdomain>,
+	// pointing to the bootstrap load balancer as well.
+	ExternalDNS *ExternalDNSConfig `json:"externalDNS,omitempty"`
+}
+
+// ExternalDNSConfig defines the DNS records requested from external-dns
+type ExternalDNSConfig struct {
+	// Hostname is the DNS name of the record. Defaults to the subdomain of
+	// the listener.
+	Hostname string `json:"hostname,omitempty"`
+	// TTL is the time to live of the record in seconds. The default TTL of
+	// the DNS provider is used if not set.
+	TTL int `json:"ttl,omitempty"`
 }
 
 // LoadBalancerConfig defines the load balancer specification
@@ -336,9 +716,192 @@ type ClusterStatus struct {
 	// Current version of the cluster.
 	// +optional
 	Version string `json:"version"`
+	// License loaded in the cluster, when referenced by LicenseRef
+	// +optional
+	License *LicenseStatus `json:"license,omitempty"`
 	// Current state of the cluster.
 	// +optional
 	Conditions []ClusterCondition `json:"conditions,omitempty"`
+	// ConfigurationErrors lists the cluster configuration properties that
+	// failed validation, when ValidateClusterConfiguration is enabled
+	// +optional
+	ConfigurationErrors []ConfigurationError `json:"configurationErrors,omitempty"`
+	// DebugLogLevels are the temporary log levels applied to the brokers
+	// through the debug annotation
+	// +optional
+	DebugLogLevels *DebugLogLevelsStatus `json:"debugLogLevels,omitempty"`
+	// RestartedAt is when the last rolling restart requested through the
+	// restart annotation started
+	// +optional
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty"`
+	// DiskValidation is the result of the disk benchmark of
+	// spec.diskValidation
+	// +optional
+	DiskValidation *DiskValidationStatus `json:"diskValidation,omitempty"`
+	// PendingMaintenance lists the disruptive operations deferred until
+	// the next maintenance window of spec.maintenanceWindows
+	// +optional
+	PendingMaintenance *PendingMaintenanceStatus `json:"pendingMaintenance,omitempty"`
+	// Endpoints is the reachability of the external endpoints of the
+	// Pandaproxy Ingress and of the schema registry, as probed from the
+	// operator
+	// +optional
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+	// Canary is the progress of the last canary upgrade
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+}
+
+// CanaryStatus is the progress of a canary upgrade
+type CanaryStatus struct {
+	// Image the canary is upgraded to
+	Image string `json:"image"`
+	// BaselineImage is the image of the brokers before the upgrade, which
+	// the canary is rolled back to if it fails
+	BaselineImage string `json:"baselineImage"`
+	// Phase of the canary upgrade, Pending, Soaking, Succeeded or Failed
+	Phase string `json:"phase"`
+	// Pod of the canary broker, once it is restarted
+	// +optional
+	Pod string `json:"pod,omitempty"`
+	// StartedAt is when the canary pod was restarted with the new image
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Baseline is the cluster health before the canary is restarted
+	Baseline CanaryHealth `json:"baseline"`
+	// Message explains why the canary failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CanaryHealth is the cluster health overview compared before and after the
+// canary upgrade
+type CanaryHealth struct {
+	// NodesDown is the number of brokers reported down
+	NodesDown int `json:"nodesDown"`
+	// LeaderlessPartitions is the number of partitions without a leader
+	LeaderlessPartitions int `json:"leaderlessPartitions"`
+	// UnderReplicatedPartitions is the number of under-replicated
+	// partitions, when reported by the brokers
+	UnderReplicatedPartitions int `json:"underReplicatedPartitions"`
+}
+
+// These are the phases of a canary upgrade
+const (
+	// CanaryPhasePending is before the canary pod is restarted
+	CanaryPhasePending = "Pending"
+	// CanaryPhaseSoaking is while the canary runs the new image alone
+	CanaryPhaseSoaking = "Soaking"
+	// CanaryPhaseSucceeded is once the other brokers can be upgraded
+	CanaryPhaseSucceeded = "Succeeded"
+	// CanaryPhaseFailed is once the canary is rolled back
+	CanaryPhaseFailed = "Failed"
+)
+
+// EndpointStatus is the result of the last probe of an external endpoint,
+// which resolves its host name, connects to it, completes the TLS handshake
+// and sends it an HTTP request
+type EndpointStatus struct {
+	// Name of the endpoint, PandaproxyIngress or SchemaRegistry
+	Name string `json:"name"`
+	// URL that was probed
+	URL string `json:"url"`
+	// Reachable is true if the probe succeeded
+	Reachable bool `json:"reachable"`
+	// FailedStage is the step of the probe that failed, one of DNS,
+	// Connection, TLS or HTTP
+	// +optional
+	FailedStage string `json:"failedStage,omitempty"`
+	// Message describes the failure
+	// +optional
+	Message string `json:"message,omitempty"`
+	// HTTPStatusCode is the status code of the answer of the endpoint
+	// +optional
+	HTTPStatusCode int `json:"httpStatusCode,omitempty"`
+	// LastProbeTime is when the endpoint was last probed
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+}
+
+// These are the names of the probed endpoints
+const (
+	// EndpointPandaproxyIngress is the Pandaproxy API exposed through the
+	// Ingress of spec.configuration.pandaproxyApi[].external.subdomain
+	EndpointPandaproxyIngress = "PandaproxyIngress"
+	// EndpointSchemaRegistry is the schema registry exposed on
+	// spec.configuration.schemaRegistry.external.subdomain
+	EndpointSchemaRegistry = "SchemaRegistry"
+)
+
+// PendingMaintenanceStatus describes the disruptive operations waiting for a
+// maintenance window
+type PendingMaintenanceStatus struct {
+	// Operations deferred, RollingUpdate or Decommission
+	Operations []string `json:"operations"`
+	// NextWindow is when the next maintenance window opens
+	NextWindow metav1.Time `json:"nextWindow"`
+}
+
+// These are the disruptive operations deferred to the maintenance windows
+const (
+	// MaintenanceOperationRollingUpdate restarts the brokers one after the
+	// other
+	MaintenanceOperationRollingUpdate = "RollingUpdate"
+	// MaintenanceOperationDecommission decommissions a broker to downscale
+	// the cluster
+	MaintenanceOperationDecommission = "Decommission"
+)
+
+// DiskValidationStatus is the result of the disk benchmark
+type DiskValidationStatus struct {
+	// ReadIOPS measured by the benchmark
+	ReadIOPS int64 `json:"readIOPS,omitempty"`
+	// ReadBandwidth measured by the benchmark, in bytes per second
+	ReadBandwidth int64 `json:"readBandwidth,omitempty"`
+	// WriteIOPS measured by the benchmark
+	WriteIOPS int64 `json:"writeIOPS,omitempty"`
+	// WriteBandwidth measured by the benchmark, in bytes per second
+	WriteBandwidth int64 `json:"writeBandwidth,omitempty"`
+	// Passed is true if the storage meets the expectations of
+	// spec.diskValidation
+	Passed bool `json:"passed"`
+	// Message explains why the validation did not pass
+	Message string `json:"message,omitempty"`
+	// CompletedAt is when the benchmark finished
+	CompletedAt metav1.Time `json:"completedAt,omitempty"`
+}
+
+// ConfigurationError describes an invalid cluster configuration property
+type ConfigurationError struct {
+	// Key is the name of the property
+	Key string `json:"key"`
+	// Message describes why the property is invalid
+	Message string `json:"message"`
+}
+
+// DebugLogLevelsStatus describes the log level overrides applied from the
+// debug annotation
+type DebugLogLevelsStatus struct {
+	// Levels is the value of the debug annotation that was applied
+	Levels string `json:"levels"`
+	// Duration is how long the overrides last
+	Duration metav1.Duration `json:"duration"`
+	// Expiration is the time at which the brokers revert the overrides
+	Expiration metav1.Time `json:"expiration"`
+}
+
+// LicenseStatus describes the enterprise license loaded in the cluster
+type LicenseStatus struct {
+	// Organization the license was issued to
+	Organization string `json:"organization,omitempty"`
+	// Type of the license
+	Type string `json:"type,omitempty"`
+	// Expiration time of the license
+	Expiration *metav1.Time `json:"expiration,omitempty"`
+	// Expired is true once the license is past its expiration time
+	Expired bool `json:"expired,omitempty"`
+	// Checksum is the hex encoded sha256 of the license uploaded by the
+	// operator, used to detect changes to the referenced Secret
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // ClusterCondition contains details for the current conditions of the cluster
@@ -359,13 +922,22 @@ type ClusterCondition struct {
 }
 
 // ClusterConditionType is a valid value for ClusterCondition.Type
-// +kubebuilder:validation:Enum=ClusterConfigured
+// +kubebuilder:validation:Enum=ClusterConfigured;CloudStorageConnected;KubernetesCompatible;LicenseValid
 type ClusterConditionType string
 
 // These are valid conditions of the cluster.
 const (
 	// ClusterConfiguredConditionType indicates whether the Redpanda cluster configuration is in sync with the desired one
 	ClusterConfiguredConditionType ClusterConditionType = "ClusterConfigured"
+	// CloudStorageConnectedConditionType indicates whether the brokers
+	// succeed in transferring data to and from the cloud storage bucket
+	CloudStorageConnectedConditionType ClusterConditionType = "CloudStorageConnected"
+	// KubernetesCompatibleConditionType indicates whether the Kubernetes
+	// cluster serves the APIs needed by the resources of the cluster
+	KubernetesCompatibleConditionType ClusterConditionType = "KubernetesCompatible"
+	// LicenseValidConditionType indicates whether the license referenced by
+	// the cluster is loaded and not expired
+	LicenseValidConditionType ClusterConditionType = "LicenseValid"
 )
 
 // GetCondition return the condition of the given type
@@ -433,6 +1005,48 @@ func (s *ClusterStatus) SetConditionUsingClock(
 	return true
 }
 
+// SetPendingMaintenance records the operation as deferred until the next
+// maintenance window. The return value indicates if a change has happened.
+func (s *ClusterStatus) SetPendingMaintenance(
+	operation string, nextWindow time.Time,
+) bool {
+	if s.PendingMaintenance == nil {
+		s.PendingMaintenance = &PendingMaintenanceStatus{}
+	}
+	changed := !s.PendingMaintenance.NextWindow.Time.Equal(nextWindow)
+	s.PendingMaintenance.NextWindow = metav1.NewTime(nextWindow)
+	for _, op := range s.PendingMaintenance.Operations {
+		if op == operation {
+			return changed
+		}
+	}
+	s.PendingMaintenance.Operations = append(s.PendingMaintenance.Operations, operation)
+	return true
+}
+
+// ClearPendingMaintenance removes the operation from the ones deferred until
+// the next maintenance window. The return value indicates if a change has
+// happened.
+func (s *ClusterStatus) ClearPendingMaintenance(operation string) bool {
+	if s.PendingMaintenance == nil {
+		return false
+	}
+	ops := s.PendingMaintenance.Operations[:0]
+	for _, op := range s.PendingMaintenance.Operations {
+		if op != operation {
+			ops = append(ops, op)
+		}
+	}
+	if len(ops) == len(s.PendingMaintenance.Operations) {
+		return false
+	}
+	s.PendingMaintenance.Operations = ops
+	if len(ops) == 0 {
+		s.PendingMaintenance = nil
+	}
+	return true
+}
+
 // These are valid reasons for ClusterConfigured
 const (
 	// ClusterConfiguredReasonUpdating indicates that the desired configuration is being applied to the running cluster
@@ -441,6 +1055,44 @@ const (
 	ClusterConfiguredReasonDrift = "Drift"
 	// ClusterConfiguredReasonError signals an error when applying the configuration to the Redpanda cluster
 	ClusterConfiguredReasonError = "Error"
+	// ClusterConfiguredReasonValidationFailed indicates that the desired configuration was not applied because some
+	// properties do not match the configuration schema of the cluster
+	ClusterConfiguredReasonValidationFailed = "ValidationFailed"
+)
+
+// These are valid reasons for CloudStorageConnected
+const (
+	// CloudStorageConnectedReasonTransfersSucceeding indicates that the brokers transferred data to or from the bucket
+	CloudStorageConnectedReasonTransfersSucceeding = "TransfersSucceeding"
+	// CloudStorageConnectedReasonTransfersFailing indicates that all the transfers of the brokers failed, e.g. because
+	// the credentials are not accepted or the bucket is not reachable
+	CloudStorageConnectedReasonTransfersFailing = "TransfersFailing"
+	// CloudStorageConnectedReasonNoTransfers indicates that the brokers did not transfer any data yet
+	CloudStorageConnectedReasonNoTransfers = "NoTransfers"
+)
+
+// These are valid reasons for KubernetesCompatible
+const (
+	// KubernetesCompatibleReasonSupported indicates that the Kubernetes cluster serves the APIs of all the resources
+	KubernetesCompatibleReasonSupported = "Supported"
+	// KubernetesCompatibleReasonDegraded indicates that some resources are created with an older API
+	// of the Kubernetes cluster, e.g. networking.k8s.io/v1beta1 Ingresses
+	KubernetesCompatibleReasonDegraded = "Degraded"
+	// KubernetesCompatibleReasonIngressUnsupported indicates that the Kubernetes cluster serves no
+	// supported Ingress API, so the Ingress of the cluster is not created
+	KubernetesCompatibleReasonIngressUnsupported = "IngressUnsupported"
+)
+
+// These are valid reasons for LicenseValid
+const (
+	// LicenseValidReasonValid indicates that the license is loaded and does not expire soon
+	LicenseValidReasonValid = "Valid"
+	// LicenseValidReasonExpiringSoon indicates that the license expires in less than 30 days
+	LicenseValidReasonExpiringSoon = "ExpiringSoon"
+	// LicenseValidReasonExpired indicates that the license is past its expiration time
+	LicenseValidReasonExpired = "Expired"
+	// LicenseValidReasonNotLoaded indicates that the cluster reports no loaded license
+	LicenseValidReasonNotLoaded = "NotLoaded"
 )
 
 // NodesList shows where client of Cluster custom resource can reach
@@ -457,6 +1109,7 @@ type NodesList struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 
 // Cluster is the Schema for the clusters API
 type Cluster struct {
@@ -484,6 +1137,13 @@ type RedpandaConfig struct {
 	PandaproxyAPI  []PandaproxyAPI    `json:"pandaproxyApi,omitempty"`
 	SchemaRegistry *SchemaRegistryAPI `json:"schemaRegistry,omitempty"`
 	DeveloperMode  bool               `json:"developerMode,omitempty"`
+	// DefaultLogLevel is the level of the loggers that are not listed in
+	// LogLevels. Defaults to debug in developer mode and info otherwise.
+	// +kubebuilder:validation:Enum=error;warn;info;debug;trace
+	DefaultLogLevel string `json:"defaultLogLevel,omitempty"`
+	// LogLevels maps the names of loggers to the level they start with,
+	// overriding DefaultLogLevel
+	LogLevels map[string]string `json:"logLevels,omitempty"`
 	// Number of partitions in the internal group membership topic
 	GroupTopicPartitions int `json:"groupTopicPartitions,omitempty"`
 	// Enable auto-creation of topics. Reference https://kafka.apache.org/documentation/#brokerconfigs_auto.create.topics.enable
@@ -510,8 +1170,26 @@ type KafkaAPI struct {
 	External ExternalConnectivityConfig `json:"external,omitempty"`
 	// Configuration of TLS for Kafka API
 	TLS KafkaAPITLS `json:"tls,omitempty"`
+	// AuthenticationMethod is how clients of the listener authenticate,
+	// either none, sasl, or mtls_identity to authenticate clients with the
+	// identity of their TLS certificate, which requires TLS with
+	// requireClientAuth. If not set, the listener uses sasl if enableSasl is
+	// set and none otherwise.
+	AuthenticationMethod KafkaAuthenticationMethod `json:"authenticationMethod,omitempty"`
 }
 
+// KafkaAuthenticationMethod is the authentication method of a Kafka API
+// listener.
+// +kubebuilder:validation:Enum=none;sasl;mtls_identity
+type KafkaAuthenticationMethod string
+
+// These are the valid authentication methods of Kafka API listeners
+const (
+	KafkaAuthenticationNone         KafkaAuthenticationMethod = "none"
+	KafkaAuthenticationSASL         KafkaAuthenticationMethod = "sasl"
+	KafkaAuthenticationMTLSIdentity KafkaAuthenticationMethod = "mtls_identity"
+)
+
 // PandaproxyAPI configures listener for the Pandaproxy API
 type PandaproxyAPI struct {
 	Port int `json:"port,omitempty"`
@@ -593,6 +1271,14 @@ type KafkaAPITLS struct {
 	// is not enough and you need to have a verifiable chain with a proper CA
 	// certificate.
 	IssuerRef *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
+	// IssuerNamespace is the namespace of the Issuer referenced by
+	// IssuerRef, if it is not in the namespace of the cluster. Certificates
+	// can only reference Issuers of their own namespace, so the node
+	// certificate is then issued in the namespace of the Issuer and its
+	// Secret copied to the namespace of the cluster, without the JKS and
+	// PKCS#12 stores. The operator must be allowed to manage Certificates
+	// and Secrets in that namespace.
+	IssuerNamespace string `json:"issuerNamespace,omitempty"`
 	// If provided, operator uses certificate in this secret instead of
 	// issuing its own node certificate. The secret is expected to provide
 	// the following keys: 'ca.crt', 'tls.key' and 'tls.crt'
@@ -674,6 +1360,14 @@ type SchemaRegistryAPITLS struct {
 	// is not enough and you need to have a verifiable chain with a proper CA
 	// certificate.
 	IssuerRef *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
+	// IssuerNamespace is the namespace of the Issuer referenced by
+	// IssuerRef, if it is not in the namespace of the cluster. Certificates
+	// can only reference Issuers of their own namespace, so the node
+	// certificate is then issued in the namespace of the Issuer and its
+	// Secret copied to the namespace of the cluster, without the JKS and
+	// PKCS#12 stores. The operator must be allowed to manage Certificates
+	// and Secrets in that namespace.
+	IssuerNamespace string `json:"issuerNamespace,omitempty"`
 	// If provided, operator uses certificate in this secret instead of
 	// issuing its own node certificate. The secret is expected to provide
 	// the following keys: 'ca.crt', 'tls.key' and 'tls.crt'
@@ -691,6 +1385,115 @@ type SocketAddress struct {
 	Port int `json:"port,omitempty"`
 }
 
+const (
+	// DebugLogLevelsAnnotation temporarily overrides the level of the listed
+	// loggers on all brokers, e.g. "raft=trace,kafka=debug"
+	DebugLogLevelsAnnotation = "redpanda.vectorized.io/debug"
+	// DebugLogLevelsExpiryAnnotation is how long the overrides of the debug
+	// annotation last, e.g. "30m"
+	DebugLogLevelsExpiryAnnotation = "redpanda.vectorized.io/debug-expiry"
+	// DefaultDebugLogLevelsExpiry is how long the overrides of the debug
+	// annotation last when no expiry is annotated
+	DefaultDebugLogLevelsExpiry = 15 * time.Minute
+)
+
+var errInvalidDebugAnnotation = errors.New("invalid debug annotation")
+
+// These annotations trigger one-shot operations on the cluster. The operator
+// removes the restart and rebalance annotations once it has acted on them.
+const (
+	// PauseReconcileAnnotation stops the reconciliation of the cluster while
+	// it is "true", e.g. to investigate an issue by hand
+	PauseReconcileAnnotation = "redpanda.vectorized.io/pause-reconcile"
+	// RestartAnnotation triggers a rolling restart of the brokers. Its value
+	// is only informative, e.g. "now"
+	RestartAnnotation = "redpanda.vectorized.io/restart"
+	// RebalanceAnnotation triggers a rebalance of the cluster. The only
+	// supported value is RebalancePartitions
+	RebalanceAnnotation = "redpanda.vectorized.io/rebalance"
+	// RebalancePartitions asks the partition balancer to move partitions
+	// right away
+	RebalancePartitions = "partitions"
+	// ForceDownscaleAnnotation acknowledges the loss of data of downscaling
+	// the cluster below the replication factor of its topics. Its value must
+	// be the number of replicas the cluster is downscaled to, e.g. "2", so
+	// that a forgotten annotation does not allow a later downscale
+	ForceDownscaleAnnotation = "redpanda.vectorized.io/force-downscale"
+)
+
+// IsReconcilePaused returns true if the reconciliation of the cluster is
+// paused through the pause annotation
+func (r *Cluster) IsReconcilePaused() bool {
+	return r.Annotations[PauseReconcileAnnotation] == "true"
+}
+
+// IsDownscaleForced returns true if the force downscale annotation
+// acknowledges the loss of data of downscaling the cluster to replicas
+func (r *Cluster) IsDownscaleForced(replicas int32) bool {
+	return r.Annotations[ForceDownscaleAnnotation] == strconv.Itoa(int(replicas))
+}
+
+// MaintenanceWindowAt returns whether a maintenance window of the cluster is
+// open at the given time and, if none is, when the next one opens. Clusters
+// without maintenance windows are always open to disruptive operations.
+func (r *Cluster) MaintenanceWindowAt(
+	now time.Time,
+) (open bool, next time.Time, err error) {
+	if len(r.Spec.MaintenanceWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+	for i := range r.Spec.MaintenanceWindows {
+		w := &r.Spec.MaintenanceWindows[i]
+		schedule, loc, err := w.parse()
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		// The window is open if it opened less than its duration ago.
+		start := schedule.Next(now.In(loc).Add(-w.Duration.Duration))
+		if start.IsZero() {
+			continue
+		}
+		if !start.After(now) {
+			return true, time.Time{}, nil
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	if next.IsZero() {
+		return false, next, fmt.Errorf("no maintenance window of cluster %s opens: %w", r.Name, utils.ErrCronNeverActivates)
+	}
+	return false, next, nil
+}
+
+func (w *MaintenanceWindow) parse() (*utils.CronSchedule, *time.Location, error) {
+	schedule, err := utils.ParseCronSchedule(w.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := time.UTC
+	if w.TimeZone != "" {
+		if loc, err = time.LoadLocation(w.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone %q: %w", w.TimeZone, err)
+		}
+	}
+	return schedule, loc, nil
+}
+
+// MinimumReplicas returns the number of replicas the cluster cannot be
+// downscaled below, given the highest replication factor of its topics. The
+// replication factors of the internal topics and of the new topics set in the
+// additional configuration are enforced too.
+func (r *Cluster) MinimumReplicas(maxReplicationFactor int) int {
+	minimum := maxReplicationFactor
+	for k := range defaultAdditionalConfiguration {
+		if v, err := strconv.Atoi(r.Spec.AdditionalConfiguration[k]); err == nil && v > minimum {
+			minimum = v
+		}
+	}
+	return minimum
+}
+
 const (
 	// MinimumMemoryPerCore the minimum amount of memory needed per core
 	MinimumMemoryPerCore = 2 * gb
@@ -704,7 +1507,25 @@ func init() {
 
 // FullImageName returns image name including version
 func (r *Cluster) FullImageName() string {
-	return fmt.Sprintf("%s:%s", r.Spec.Image, r.Spec.Version)
+	return fmt.Sprintf("%s:%s", r.MirroredImage(r.Spec.Image), r.Spec.Version)
+}
+
+// MirroredImage returns the image pulled from the registry mirror of the
+// cluster, if one is set.
+func (r *Cluster) MirroredImage(image string) string {
+	mirror := strings.TrimSuffix(r.Spec.ImageRegistry, "/")
+	if mirror == "" || strings.HasPrefix(image, mirror+"/") {
+		return image
+	}
+	// As in Docker, the first component of the image is a registry if it
+	// looks like a host.
+	if i := strings.Index(image, "/"); i > 0 {
+		first := image[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			image = image[i+1:]
+		}
+	}
+	return mirror + "/" + image
 }
 
 // ExternalListener returns external listener if found in configuration. Returns
@@ -754,6 +1575,49 @@ func (r *Cluster) KafkaTLSListeners() []ListenerWithName {
 	return res
 }
 
+// KafkaAuthenticationMethodOf returns the authentication method of the given
+// Kafka API listener, which defaults to sasl if SASL is enabled for the
+// cluster and to none otherwise.
+func (r *Cluster) KafkaAuthenticationMethodOf(l *KafkaAPI) KafkaAuthenticationMethod {
+	if l != nil && l.AuthenticationMethod != "" {
+		return l.AuthenticationMethod
+	}
+	if r.Spec.EnableSASL {
+		return KafkaAuthenticationSASL
+	}
+	return KafkaAuthenticationNone
+}
+
+// HasPerListenerAuthentication returns true if any Kafka API listener sets
+// its own authentication method, in which case authentication is configured
+// per listener rather than with the cluster wide enable_sasl property.
+func (r *Cluster) HasPerListenerAuthentication() bool {
+	for _, l := range r.Spec.Configuration.KafkaAPI {
+		if l.AuthenticationMethod != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSASLOnInternalEnabled returns true if the internal Kafka API listener
+// requires SASL, in which case the clients of the internal listener, such as
+// Pandaproxy, Schema Registry and the operator itself, authenticate with the
+// SCRAM credentials of their superuser.
+func (r *Cluster) IsSASLOnInternalEnabled() bool {
+	return r.KafkaAuthenticationMethodOf(r.InternalListener()) == KafkaAuthenticationSASL
+}
+
+// AdminAPICredentialsSecretName returns the name of the Secret with the
+// credentials the operator authenticates to the admin API with, or an empty
+// string if the admin API does not require authentication.
+func (r *Cluster) AdminAPICredentialsSecretName() string {
+	if r.Spec.AdminAPIAuthentication == nil {
+		return ""
+	}
+	return r.Spec.AdminAPIAuthentication.CredentialsSecretRef.Name
+}
+
 // AdminAPIInternal returns internal admin listener
 func (r *Cluster) AdminAPIInternal() *AdminAPI {
 	for _, el := range r.Spec.Configuration.AdminAPI {
@@ -875,6 +1739,178 @@ func (r *Cluster) IsUsingMaintenanceModeHooks() bool {
 	return true
 }
 
+// MaxUnavailableDuringUpdate returns the maximum number of pods that can be
+// restarted at the same time during a rolling update. It is capped by the
+// disruptions allowed by the PodDisruptionBudget, as pods are evicted, but
+// one pod can always be restarted.
+func (r *Cluster) MaxUnavailableDuringUpdate() int32 {
+	maxUnavailable := int32(1)
+	if r.Spec.RestartConfig != nil && r.Spec.RestartConfig.UpdateStrategy != nil &&
+		r.Spec.RestartConfig.UpdateStrategy.MaxUnavailable != nil {
+		maxUnavailable = *r.Spec.RestartConfig.UpdateStrategy.MaxUnavailable
+	}
+	if allowed, ok := r.allowedDisruptions(); ok && allowed < maxUnavailable {
+		maxUnavailable = allowed
+	}
+	if maxUnavailable < 1 {
+		return 1
+	}
+	return maxUnavailable
+}
+
+// allowedDisruptions returns how many pods the PodDisruptionBudget of the
+// cluster allows to be unavailable, computed like the disruption controller
+// does, and false if there is no budget
+func (r *Cluster) allowedDisruptions() (int32, bool) {
+	pdb := r.Spec.PodDisruptionBudget
+	if pdb == nil || !pdb.Enabled || r.Spec.Replicas == nil {
+		return 0, false
+	}
+	replicas := int(*r.Spec.Replicas)
+	switch {
+	case pdb.MaxUnavailable != nil:
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.MaxUnavailable, replicas, true)
+		if err != nil {
+			return 0, false
+		}
+		return int32(maxUnavailable), true
+	case pdb.MinAvailable != nil:
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.MinAvailable, replicas, true)
+		if err != nil {
+			return 0, false
+		}
+		return int32(replicas - minAvailable), true
+	default:
+		return 0, false
+	}
+}
+
+// UpdateRackLabel returns the node label used to group brokers in racks during
+// parallel rolling updates
+func (r *Cluster) UpdateRackLabel() string {
+	if r.Spec.RestartConfig != nil && r.Spec.RestartConfig.UpdateStrategy != nil &&
+		r.Spec.RestartConfig.UpdateStrategy.RackLabel != "" {
+		return r.Spec.RestartConfig.UpdateStrategy.RackLabel
+	}
+	return corev1.LabelTopologyZone
+}
+
+// CanaryUpgradeEnabled returns whether image upgrades start with a canary
+// broker
+func (r *Cluster) CanaryUpgradeEnabled() bool {
+	return r.Spec.RestartConfig != nil && r.Spec.RestartConfig.UpdateStrategy != nil &&
+		r.Spec.RestartConfig.UpdateStrategy.Canary != nil &&
+		r.Spec.RestartConfig.UpdateStrategy.Canary.Enabled
+}
+
+// CanarySoakDuration returns how long the canary broker runs before the other
+// brokers are upgraded
+func (r *Cluster) CanarySoakDuration() time.Duration {
+	if r.CanaryUpgradeEnabled() && r.Spec.RestartConfig.UpdateStrategy.Canary.SoakDuration != nil {
+		return r.Spec.RestartConfig.UpdateStrategy.Canary.SoakDuration.Duration
+	}
+	return DefaultCanarySoakDuration
+}
+
+// IPFamilies returns the IP families of the cluster Services, or nil to use
+// the Kubernetes defaults
+func (r *Cluster) IPFamilies() []corev1.IPFamily {
+	if r.Spec.Networking == nil {
+		return nil
+	}
+	return r.Spec.Networking.IPFamilies
+}
+
+// IPFamilyPolicy returns the IP family policy of the cluster Services, or nil
+// to use the Kubernetes default
+func (r *Cluster) IPFamilyPolicy() *corev1.IPFamilyPolicyType {
+	if r.Spec.Networking == nil {
+		return nil
+	}
+	return r.Spec.Networking.IPFamilyPolicy
+}
+
+// IsIPv6Enabled tells if IPv6 is one of the IP families of the cluster
+func (r *Cluster) IsIPv6Enabled() bool {
+	for _, f := range r.IPFamilies() {
+		if f == corev1.IPv6Protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// ListenerBindAddress returns the wildcard address the Redpanda listeners
+// bind to
+func (r *Cluster) ListenerBindAddress() string {
+	if r.IsIPv6Enabled() {
+		return "::"
+	}
+	return "0.0.0.0"
+}
+
+// LogLevelOf returns the level the given logger starts with
+func (r *Cluster) LogLevelOf(logger string) string {
+	if level, ok := r.Spec.Configuration.LogLevels[logger]; ok {
+		return level
+	}
+	return r.DefaultLogLevel()
+}
+
+// DefaultLogLevel returns the level of the loggers that are not configured in
+// LogLevels
+func (r *Cluster) DefaultLogLevel() string {
+	switch {
+	case r.Spec.Configuration.DefaultLogLevel != "":
+		return r.Spec.Configuration.DefaultLogLevel
+	case r.Spec.Configuration.DeveloperMode:
+		return "debug"
+	default:
+		return "info"
+	}
+}
+
+// DebugLogLevels parses the debug annotation, which lists the loggers to
+// temporarily override as comma separated logger=level pairs, and the
+// optional expiry annotation. It returns nil levels if there is no debug
+// annotation.
+func (r *Cluster) DebugLogLevels() (map[string]string, time.Duration, error) {
+	value, ok := r.Annotations[DebugLogLevelsAnnotation]
+	if !ok {
+		return nil, 0, nil
+	}
+	levels := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, 0, fmt.Errorf("%w: %q is not logger=level", errInvalidDebugAnnotation, pair)
+		}
+		logger, level := strings.TrimSpace(kv[0]), strings.ToLower(strings.TrimSpace(kv[1]))
+		if !IsValidLogLevel(level) {
+			return nil, 0, fmt.Errorf("%w: unknown level %q for logger %q", errInvalidDebugAnnotation, level, logger)
+		}
+		levels[logger] = level
+	}
+
+	expiry := DefaultDebugLogLevelsExpiry
+	if raw, ok := r.Annotations[DebugLogLevelsExpiryAnnotation]; ok {
+		var err error
+		if expiry, err = time.ParseDuration(raw); err != nil || expiry <= 0 {
+			return nil, 0, fmt.Errorf("%w: invalid expiry %q", errInvalidDebugAnnotation, raw)
+		}
+	}
+	return levels, expiry, nil
+}
+
+// IsValidLogLevel tells if the level is known to Redpanda
+func IsValidLogLevel(level string) bool {
+	switch level {
+	case "error", "warn", "info", "debug", "trace":
+		return true
+	}
+	return false
+}
+
 // ClusterStatus
 
 // IsRestarting tells if the cluster is restarting due to a change in configuration or an upgrade in progress
@@ -910,8 +1946,39 @@ func (r *Cluster) ComputeInitialCurrentReplicasField() int32 {
 		// A cluster seems to be already running, we start from the existing amount of replicas
 		return *r.Spec.Replicas
 	}
-	// Clusters start from a single replica, then upscale
-	return 1
+	// Clusters start from their seed servers, then upscale
+	return r.BootstrapReplicas()
+}
+
+// BootstrapReplicas returns the number of brokers that form a new cluster,
+// before it is upscaled to spec.replicas.
+func (r *Cluster) BootstrapReplicas() int32 {
+	if r.Spec.Bootstrap == nil {
+		return 1
+	}
+	if r.Spec.Replicas != nil && *r.Spec.Replicas < r.Spec.Bootstrap.SeedReplicas {
+		return *r.Spec.Replicas
+	}
+	return r.Spec.Bootstrap.SeedReplicas
+}
+
+// SeedServerReplicas returns the number of seed servers of every broker,
+// which are the brokers of ordinals 0 to SeedServerReplicas()-1.
+//
+// Designated seed servers never change. Otherwise every broker is a seed
+// server, and the list follows the scaling of the cluster, except that a
+// broker being decommissioned stays a seed server until it has left the
+// cluster: the seed servers must never point at brokers that do not exist,
+// nor leave out brokers that are still members of the cluster.
+func (r *Cluster) SeedServerReplicas() int32 {
+	if r.Spec.Bootstrap != nil {
+		return r.Spec.Bootstrap.SeedReplicas
+	}
+	replicas := r.GetCurrentReplicas()
+	if node := r.Status.DecommissioningNode; node != nil && *node+1 > replicas {
+		replicas = *node + 1
+	}
+	return replicas
 }
 
 // TLSConfig is a generic TLS configuration
@@ -919,6 +1986,7 @@ type TLSConfig struct {
 	Enabled           bool                    `json:"enabled,omitempty"`
 	RequireClientAuth bool                    `json:"requireClientAuth,omitempty"`
 	IssuerRef         *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
+	IssuerNamespace   string                  `json:"issuerNamespace,omitempty"`
 	NodeSecretRef     *corev1.ObjectReference `json:"nodeSecretRef,omitempty"`
 }
 
@@ -939,6 +2007,7 @@ func (k KafkaAPI) GetTLS() *TLSConfig {
 		Enabled:           k.TLS.Enabled,
 		RequireClientAuth: k.TLS.RequireClientAuth,
 		IssuerRef:         k.TLS.IssuerRef,
+		IssuerNamespace:   k.TLS.IssuerNamespace,
 		NodeSecretRef:     k.TLS.NodeSecretRef,
 	}
 }
@@ -997,6 +2066,7 @@ func (s SchemaRegistryAPI) GetTLS() *TLSConfig {
 		Enabled:           s.TLS.Enabled,
 		RequireClientAuth: s.TLS.RequireClientAuth,
 		IssuerRef:         s.TLS.IssuerRef,
+		IssuerNamespace:   s.TLS.IssuerNamespace,
 		NodeSecretRef:     s.TLS.NodeSecretRef,
 	}
 }
@@ -18,6 +18,8 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
 
@@ -232,4 +234,231 @@ func TestInitialReplicas(t *testing.T) {
 	assert.Equal(t, int32(3), cluster.GetCurrentReplicas())
 	cluster.Status.Nodes.Internal = nil
 	assert.Equal(t, int32(1), cluster.GetCurrentReplicas())
+
+	// Clusters with designated seed servers start from all of them
+	cluster.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 3}
+	cluster.Spec.Replicas = pointer.Int32(5)
+	assert.Equal(t, int32(3), cluster.GetCurrentReplicas())
+	cluster.Spec.Replicas = pointer.Int32(2)
+	assert.Equal(t, int32(2), cluster.GetCurrentReplicas())
+}
+
+func TestSeedServerReplicas(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	cluster.Spec.Replicas = pointer.Int32(3)
+	cluster.Status.Replicas = 3
+	cluster.Status.CurrentReplicas = 3
+	assert.Equal(t, int32(3), cluster.SeedServerReplicas())
+
+	// A broker being decommissioned is a seed server until it has left
+	cluster.Spec.Replicas = pointer.Int32(2)
+	cluster.Status.CurrentReplicas = 2
+	cluster.Status.DecommissioningNode = pointer.Int32(2)
+	assert.Equal(t, int32(3), cluster.SeedServerReplicas())
+	cluster.Status.DecommissioningNode = nil
+	assert.Equal(t, int32(2), cluster.SeedServerReplicas())
+
+	// Designated seed servers stay the same when the cluster is scaled
+	cluster.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 3}
+	cluster.Spec.Replicas = pointer.Int32(5)
+	cluster.Status.CurrentReplicas = 5
+	assert.Equal(t, int32(3), cluster.SeedServerReplicas())
+}
+
+func TestMaxUnavailableDuringUpdate(t *testing.T) {
+	intOrPercent := func(s string) *intstr.IntOrString {
+		v := intstr.Parse(s)
+		return &v
+	}
+	tests := []struct {
+		name     string
+		strategy *v1alpha1.UpdateStrategy
+		pdb      *v1alpha1.PDBConfig
+		expected int32
+	}{
+		{name: "default", expected: 1},
+		{name: "no budget", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, expected: 3},
+		{name: "disabled budget", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, pdb: &v1alpha1.PDBConfig{MaxUnavailable: intOrPercent("1")}, expected: 3},
+		{name: "default budget", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, pdb: &v1alpha1.PDBConfig{Enabled: true, MaxUnavailable: intOrPercent("1")}, expected: 1},
+		{name: "raised budget", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, pdb: &v1alpha1.PDBConfig{Enabled: true, MaxUnavailable: intOrPercent("5")}, expected: 3},
+		{name: "percent budget", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, pdb: &v1alpha1.PDBConfig{Enabled: true, MaxUnavailable: intOrPercent("25%")}, expected: 2},
+		{name: "min available", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, pdb: &v1alpha1.PDBConfig{Enabled: true, MinAvailable: intOrPercent("4")}, expected: 2},
+		{name: "no disruption allowed", strategy: &v1alpha1.UpdateStrategy{MaxUnavailable: pointer.Int32(3)}, pdb: &v1alpha1.PDBConfig{Enabled: true, MaxUnavailable: intOrPercent("0")}, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := v1alpha1.Cluster{}
+			cluster.Spec.Replicas = pointer.Int32(6)
+			cluster.Spec.RestartConfig = &v1alpha1.RestartConfig{UpdateStrategy: tt.strategy}
+			cluster.Spec.PodDisruptionBudget = tt.pdb
+			assert.Equal(t, tt.expected, cluster.MaxUnavailableDuringUpdate())
+		})
+	}
+}
+
+func TestLogLevelOf(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	assert.Equal(t, "info", cluster.LogLevelOf("raft"))
+	cluster.Spec.Configuration.DeveloperMode = true
+	assert.Equal(t, "debug", cluster.LogLevelOf("raft"))
+	cluster.Spec.Configuration.DefaultLogLevel = "warn"
+	cluster.Spec.Configuration.LogLevels = map[string]string{"raft": "trace"}
+	assert.Equal(t, "trace", cluster.LogLevelOf("raft"))
+	assert.Equal(t, "warn", cluster.LogLevelOf("kafka"))
+}
+
+func TestDebugLogLevels(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	levels, _, err := cluster.DebugLogLevels()
+	require.NoError(t, err)
+	assert.Nil(t, levels)
+
+	cluster.Annotations = map[string]string{v1alpha1.DebugLogLevelsAnnotation: "raft=TRACE, kafka = debug"}
+	levels, expiry, err := cluster.DebugLogLevels()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"raft": "trace", "kafka": "debug"}, levels)
+	assert.Equal(t, v1alpha1.DefaultDebugLogLevelsExpiry, expiry)
+
+	cluster.Annotations[v1alpha1.DebugLogLevelsExpiryAnnotation] = "1h"
+	_, expiry, err = cluster.DebugLogLevels()
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, expiry)
+
+	for _, invalid := range []map[string]string{
+		{v1alpha1.DebugLogLevelsAnnotation: "raft"},
+		{v1alpha1.DebugLogLevelsAnnotation: "=debug"},
+		{v1alpha1.DebugLogLevelsAnnotation: "raft=verbose"},
+		{v1alpha1.DebugLogLevelsAnnotation: "raft=debug", v1alpha1.DebugLogLevelsExpiryAnnotation: "-1m"},
+	} {
+		cluster.Annotations = invalid
+		_, _, err = cluster.DebugLogLevels()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestListenerBindAddress(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	assert.False(t, cluster.IsIPv6Enabled())
+	assert.Equal(t, "0.0.0.0", cluster.ListenerBindAddress())
+
+	cluster.Spec.Networking = &v1alpha1.NetworkingConfig{
+		IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+	}
+	assert.True(t, cluster.IsIPv6Enabled())
+	assert.Equal(t, "::", cluster.ListenerBindAddress())
+}
+
+func TestKafkaAuthenticationMethodOf(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	cluster.Spec.Configuration.KafkaAPI = []v1alpha1.KafkaAPI{
+		{Port: 9092},
+		{Port: 30092, External: v1alpha1.ExternalConnectivityConfig{Enabled: true}},
+	}
+	assert.False(t, cluster.HasPerListenerAuthentication())
+	assert.False(t, cluster.IsSASLOnInternalEnabled())
+	assert.Equal(t, v1alpha1.KafkaAuthenticationNone, cluster.KafkaAuthenticationMethodOf(cluster.ExternalListener()))
+
+	cluster.Spec.EnableSASL = true
+	assert.True(t, cluster.IsSASLOnInternalEnabled())
+	assert.Equal(t, v1alpha1.KafkaAuthenticationSASL, cluster.KafkaAuthenticationMethodOf(cluster.ExternalListener()))
+
+	cluster.Spec.Configuration.KafkaAPI[0].AuthenticationMethod = v1alpha1.KafkaAuthenticationNone
+	cluster.Spec.Configuration.KafkaAPI[1].AuthenticationMethod = v1alpha1.KafkaAuthenticationMTLSIdentity
+	assert.True(t, cluster.HasPerListenerAuthentication())
+	assert.False(t, cluster.IsSASLOnInternalEnabled())
+	assert.Equal(t, v1alpha1.KafkaAuthenticationMTLSIdentity, cluster.KafkaAuthenticationMethodOf(cluster.ExternalListener()))
+}
+
+func TestCloudStorageServiceAccountAnnotations(t *testing.T) {
+	cs := v1alpha1.CloudStorageConfig{Enabled: true, IAMRole: "role"}
+	assert.True(t, cs.UsesStaticCredentials())
+	assert.Nil(t, cs.ServiceAccountAnnotations())
+
+	cs.CredentialsSource = v1alpha1.CredentialsSourceSTS
+	assert.False(t, cs.UsesStaticCredentials())
+	assert.Equal(t, map[string]string{v1alpha1.IRSARoleAnnotation: "role"}, cs.ServiceAccountAnnotations())
+
+	cs.CredentialsSource = v1alpha1.CredentialsSourceGCPInstanceMetadata
+	assert.Equal(t, map[string]string{v1alpha1.WorkloadIdentityAnnotation: "role"}, cs.ServiceAccountAnnotations())
+
+	cs.Enabled = false
+	assert.Nil(t, cs.ServiceAccountAnnotations())
+}
+
+func TestMirroredImage(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	cluster.Spec.Image = "vectorized/redpanda"
+	cluster.Spec.Version = "v22.2.1"
+	assert.Equal(t, "vectorized/redpanda:v22.2.1", cluster.FullImageName())
+
+	cluster.Spec.ImageRegistry = "registry.example.com/mirror/"
+	assert.Equal(t, "registry.example.com/mirror/vectorized/redpanda:v22.2.1", cluster.FullImageName())
+	for image, exp := range map[string]string{
+		"vectorized/configurator":                      "registry.example.com/mirror/vectorized/configurator",
+		"docker.io/vectorized/configurator":            "registry.example.com/mirror/vectorized/configurator",
+		"localhost:5000/configurator":                  "registry.example.com/mirror/configurator",
+		"localhost/configurator":                       "registry.example.com/mirror/configurator",
+		"busybox":                                      "registry.example.com/mirror/busybox",
+		"registry.example.com/mirror/vectorized/other": "registry.example.com/mirror/vectorized/other",
+	} {
+		assert.Equal(t, exp, cluster.MirroredImage(image), image)
+	}
+}
+
+func TestMaintenanceWindowAt(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	// Monday
+	now := time.Date(2022, time.August, 1, 10, 30, 0, 0, time.UTC)
+
+	open, _, err := cluster.MaintenanceWindowAt(now)
+	require.NoError(t, err)
+	assert.True(t, open, "clusters without windows are always open")
+
+	cluster.Spec.MaintenanceWindows = []v1alpha1.MaintenanceWindow{
+		{Schedule: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		{Schedule: "0 1 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "America/New_York"},
+	}
+	for _, tt := range []struct {
+		now  time.Time
+		open bool
+		next time.Time
+	}{
+		{now, false, time.Date(2022, time.August, 1, 22, 0, 0, 0, time.UTC)},
+		{time.Date(2022, time.August, 1, 22, 0, 0, 0, time.UTC), true, time.Time{}},
+		{time.Date(2022, time.August, 2, 1, 59, 0, 0, time.UTC), true, time.Time{}},
+		{time.Date(2022, time.August, 2, 2, 0, 0, 0, time.UTC), false, time.Date(2022, time.August, 2, 22, 0, 0, 0, time.UTC)},
+		// Friday night, the next window is on Saturday at 1am in New York
+		{time.Date(2022, time.August, 6, 2, 0, 0, 0, time.UTC), false, time.Date(2022, time.August, 6, 5, 0, 0, 0, time.UTC)},
+		{time.Date(2022, time.August, 6, 5, 30, 0, 0, time.UTC), true, time.Time{}},
+	} {
+		open, next, err := cluster.MaintenanceWindowAt(tt.now)
+		require.NoError(t, err)
+		assert.Equal(t, tt.open, open, tt.now)
+		assert.True(t, tt.next.Equal(next), "expected next window at %s, got %s", tt.next, next)
+	}
+
+	cluster.Spec.MaintenanceWindows[0].TimeZone = "Not/AZone"
+	_, _, err = cluster.MaintenanceWindowAt(now)
+	assert.Error(t, err)
+}
+
+func TestPendingMaintenance(t *testing.T) {
+	var status v1alpha1.ClusterStatus
+	next := time.Date(2022, time.August, 1, 22, 0, 0, 0, time.UTC)
+
+	assert.False(t, status.ClearPendingMaintenance(v1alpha1.MaintenanceOperationRollingUpdate))
+	assert.True(t, status.SetPendingMaintenance(v1alpha1.MaintenanceOperationRollingUpdate, next))
+	assert.False(t, status.SetPendingMaintenance(v1alpha1.MaintenanceOperationRollingUpdate, next))
+	assert.True(t, status.SetPendingMaintenance(v1alpha1.MaintenanceOperationDecommission, next))
+	assert.Equal(t, []string{v1alpha1.MaintenanceOperationRollingUpdate, v1alpha1.MaintenanceOperationDecommission},
+		status.PendingMaintenance.Operations)
+
+	later := next.Add(24 * time.Hour)
+	assert.True(t, status.SetPendingMaintenance(v1alpha1.MaintenanceOperationDecommission, later))
+	assert.True(t, later.Equal(status.PendingMaintenance.NextWindow.Time))
+
+	assert.True(t, status.ClearPendingMaintenance(v1alpha1.MaintenanceOperationRollingUpdate))
+	assert.Equal(t, []string{v1alpha1.MaintenanceOperationDecommission}, status.PendingMaintenance.Operations)
+	assert.True(t, status.ClearPendingMaintenance(v1alpha1.MaintenanceOperationDecommission))
+	assert.Nil(t, status.PendingMaintenance)
 }
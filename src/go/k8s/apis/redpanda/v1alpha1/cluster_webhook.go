@@ -10,11 +10,16 @@
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/featuregates"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -42,6 +47,8 @@ const (
 	idAllocatorReplicationKey            = "redpanda.id_allocator_replication"
 
 	defaultSchemaRegistryPort = 8081
+
+	clusterIssuerKind = "ClusterIssuer"
 )
 
 // AllowDownscalingInWebhook controls the downscaling alpha feature in the Cluster custom resource.
@@ -49,6 +56,16 @@ const (
 // that has previously been decommissioned can cause issues.
 var AllowDownscalingInWebhook = false
 
+// MaxReplicationFactorInWebhook returns the highest replication factor among
+// the topics of a cluster. When set, the webhook refuses to downscale a
+// cluster below it, unless the downscale is forced with the
+// ForceDownscaleAnnotation.
+var MaxReplicationFactorInWebhook func(ctx context.Context, cluster *Cluster) (int, error)
+
+// replicationFactorCheckTimeout bounds the query of the topics of the cluster
+// in the webhook
+const replicationFactorCheckTimeout = 10 * time.Second
+
 type resourceField struct {
 	resources *corev1.ResourceRequirements
 	path      *field.Path
@@ -166,6 +183,28 @@ func (r *Cluster) ValidateCreate() error {
 
 	allErrs = append(allErrs, r.validatePodDisruptionBudget()...)
 
+	allErrs = append(allErrs, r.validateConfigurator()...)
+
+	allErrs = append(allErrs, r.validateImages()...)
+
+	allErrs = append(allErrs, r.validateMaintenanceWindows()...)
+
+	allErrs = append(allErrs, r.validateCanaryUpgrade()...)
+
+	allErrs = append(allErrs, r.validateServiceAccount()...)
+
+	allErrs = append(allErrs, r.validateLogLevels()...)
+
+	allErrs = append(allErrs, r.validateNetworking()...)
+
+	allErrs = append(allErrs, r.validateSharedCA()...)
+
+	allErrs = append(allErrs, r.validateBootstrap()...)
+
+	allErrs = append(allErrs, r.validateAdminAPIAuthentication()...)
+
+	allErrs = append(allErrs, r.validateOperationAnnotations()...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -209,6 +248,34 @@ func (r *Cluster) ValidateUpdate(old runtime.Object) error {
 
 	allErrs = append(allErrs, r.validatePodDisruptionBudget()...)
 
+	allErrs = append(allErrs, r.validateConfigurator()...)
+
+	allErrs = append(allErrs, r.validateImages()...)
+
+	allErrs = append(allErrs, r.validateMaintenanceWindows()...)
+
+	allErrs = append(allErrs, r.validateCanaryUpgrade()...)
+
+	allErrs = append(allErrs, r.validateServiceAccount()...)
+
+	allErrs = append(allErrs, r.validateLogLevels()...)
+
+	allErrs = append(allErrs, r.validateNetworking()...)
+
+	allErrs = append(allErrs, r.validateSharedCA()...)
+
+	allErrs = append(allErrs, r.validateBootstrap()...)
+
+	allErrs = append(allErrs, r.validateAdminAPIAuthentication()...)
+
+	allErrs = append(allErrs, r.validateOperationAnnotations()...)
+
+	allErrs = append(allErrs, r.validatePrimaryIPFamily(oldCluster)...)
+
+	allErrs = append(allErrs, r.validateSharedCAUpdate(oldCluster)...)
+
+	allErrs = append(allErrs, r.validateBootstrapUpdate(oldCluster)...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -243,6 +310,31 @@ func (r *Cluster) validateDownscaling(old *Cluster) field.ErrorList {
 				r.Spec.Replicas,
 				"downscaling is an alpha feature: set --allow-downscaling in the controller parameters to enable it"))
 	}
+	if len(allErrs) > 0 || old.Spec.Replicas == nil || r.Spec.Replicas == nil ||
+		*r.Spec.Replicas >= *old.Spec.Replicas || r.IsDownscaleForced(*r.Spec.Replicas) {
+		return allErrs
+	}
+
+	path := field.NewPath("spec").Child("replicas")
+	var maxReplicationFactor int
+	if MaxReplicationFactorInWebhook != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), replicationFactorCheckTimeout)
+		defer cancel()
+		rf, err := MaxReplicationFactorInWebhook(ctx, r)
+		if err != nil {
+			return append(allErrs,
+				field.InternalError(path,
+					fmt.Errorf("unable to check the replication factor of the topics before downscaling, annotate the cluster with %s=%d to downscale anyway: %w",
+						ForceDownscaleAnnotation, *r.Spec.Replicas, err)))
+		}
+		maxReplicationFactor = rf
+	}
+	if minimum := r.MinimumReplicas(maxReplicationFactor); int(*r.Spec.Replicas) < minimum {
+		allErrs = append(allErrs,
+			field.Forbidden(path,
+				fmt.Sprintf("downscaling to %d replicas loses data of topics replicated on %d brokers: to accept the loss of data, annotate the cluster with %s=%d",
+					*r.Spec.Replicas, minimum, ForceDownscaleAnnotation, *r.Spec.Replicas)))
+	}
 	return allErrs
 }
 
@@ -284,6 +376,18 @@ func (r *Cluster) validateAdminListeners() field.ErrorList {
 				r.Spec.Configuration.AdminAPI,
 				"cannot provide an endpoint template for admin listener"))
 	}
+	if externalAdmin != nil && externalAdmin.External.AdvertisedPortBase != 0 {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec").Child("configuration").Child("adminApi"),
+				r.Spec.Configuration.AdminAPI,
+				"cannot provide an advertised port base for admin listener"))
+	}
+	if externalAdmin != nil && externalAdmin.External.ExternalDNS != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec").Child("configuration").Child("adminApi"),
+				r.Spec.Configuration.AdminAPI,
+				"cannot provide external DNS for admin listener"))
+	}
 
 	// for now only one listener can have TLS to be backward compatible with v1alpha1 API
 	foundListenerWithTLS := false
@@ -337,6 +441,26 @@ func (r *Cluster) validateKafkaListeners() field.ErrorList {
 			&p.External,
 			field.NewPath("spec").Child("configuration").Child("kafkaApi").Index(i).Child("external"))
 		allErrs = append(allErrs, tlsErrs...)
+		allErrs = append(allErrs, validateIssuerNamespace(
+			p.TLS.IssuerRef,
+			p.TLS.IssuerNamespace,
+			field.NewPath("spec").Child("configuration").Child("kafkaApi").Index(i).Child("tls"))...)
+
+		if p.AuthenticationMethod == KafkaAuthenticationMTLSIdentity {
+			path := field.NewPath("spec").Child("configuration").Child("kafkaApi").Index(i).Child("authenticationMethod")
+			if !p.TLS.Enabled || !p.TLS.RequireClientAuth {
+				allErrs = append(allErrs,
+					field.Invalid(path,
+						p.AuthenticationMethod,
+						"mtls_identity requires TLS with requireClientAuth on the listener"))
+			}
+			if !p.External.Enabled && (r.PandaproxyAPIInternal() != nil || r.Spec.Configuration.SchemaRegistry != nil) {
+				allErrs = append(allErrs,
+					field.Invalid(path,
+						p.AuthenticationMethod,
+						"Pandaproxy and Schema Registry authenticate to the internal listener with SASL, it cannot use mtls_identity"))
+			}
+		}
 	}
 
 	allErrs = append(allErrs,
@@ -366,6 +490,34 @@ func (r *Cluster) validateKafkaListeners() field.ErrorList {
 				r.Spec.Configuration.KafkaAPI,
 				"bootstrap port cannot be empty"))
 	}
+	if external != nil && external.External.AdvertisedPortBase != 0 {
+		var replicas int
+		if r.Spec.Replicas != nil {
+			replicas = int(*r.Spec.Replicas)
+		}
+		if base := external.External.AdvertisedPortBase; base < 1 || base+replicas-1 > 65535 {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec").Child("configuration").Child("kafkaApi").Index(externalIdx).Child("external").Child("advertisedPortBase"),
+					external.External.AdvertisedPortBase,
+					"the advertised ports of all brokers must be in the following range: 1-65535"))
+		}
+	}
+	if external != nil && external.External.ExternalDNS != nil {
+		path := field.NewPath("spec").Child("configuration").Child("kafkaApi").Index(externalIdx).Child("external").Child("externalDNS")
+		if external.External.Bootstrap == nil {
+			allErrs = append(allErrs,
+				field.Invalid(path,
+					external.External.ExternalDNS,
+					"external DNS requires a bootstrap loadbalancer"))
+		}
+		if tmpl := external.External.EndpointTemplate; tmpl != "" && endpointTemplateUsesHostIP(tmpl) {
+			allErrs = append(allErrs,
+				field.Invalid(path,
+					external.External.ExternalDNS,
+					"external DNS cannot create the broker records of an endpoint template that uses the host IP"))
+		}
+		allErrs = append(allErrs, validateExternalDNS(external.External.ExternalDNS, external.External.Subdomain, path)...)
+	}
 	//nolint:dupl // not identical
 	if external != nil && external.External.EndpointTemplate != "" {
 		if external.External.Subdomain == "" {
@@ -388,6 +540,25 @@ func (r *Cluster) validateKafkaListeners() field.ErrorList {
 	return allErrs
 }
 
+func validateExternalDNS(
+	cfg *ExternalDNSConfig, subdomain string, path *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	if cfg.Hostname == "" && subdomain == "" {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("hostname"),
+				cfg.Hostname,
+				"hostname is required when the listener has no subdomain"))
+	}
+	if cfg.TTL < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("ttl"),
+				cfg.TTL,
+				"ttl cannot be negative"))
+	}
+	return allErrs
+}
+
 func checkValidEndpointTemplate(tmpl string) error {
 	// Using an example input to ensure that the template expression is allowed
 	data := utils.NewEndpointTemplateData(0, "1.2.3.4")
@@ -395,6 +566,14 @@ func checkValidEndpointTemplate(tmpl string) error {
 	return err
 }
 
+// endpointTemplateUsesHostIP returns whether the endpoints computed from the
+// template depend on the host IP of the brokers
+func endpointTemplateUsesHostIP(tmpl string) bool {
+	a, errA := utils.ComputeEndpoint(tmpl, utils.NewEndpointTemplateData(0, "1.2.3.4"))
+	b, errB := utils.ComputeEndpoint(tmpl, utils.NewEndpointTemplateData(0, "5.6.7.8"))
+	return errA == nil && errB == nil && a != b
+}
+
 //nolint:funlen,gocyclo // it's a sequence of checks
 func (r *Cluster) validatePandaproxyListeners() field.ErrorList {
 	var allErrs field.ErrorList
@@ -429,6 +608,22 @@ func (r *Cluster) validatePandaproxyListeners() field.ErrorList {
 					r.Spec.Configuration.PandaproxyAPI[i],
 					"bootstrap loadbalancer not available for pandaproxy"))
 		}
+		if proxyExternal.External.AdvertisedPortBase != 0 {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec").Child("configuration").Child("pandaproxyApi").Index(i).Child("external").Child("advertisedPortBase"),
+					r.Spec.Configuration.PandaproxyAPI[i].External.AdvertisedPortBase,
+					"cannot provide an advertised port base for pandaproxy"))
+		}
+		if proxyExternal.External.ExternalDNS != nil {
+			path := field.NewPath("spec").Child("configuration").Child("pandaproxyApi").Index(i).Child("external").Child("externalDNS")
+			if proxyExternal.External.Subdomain == "" {
+				allErrs = append(allErrs,
+					field.Invalid(path,
+						proxyExternal.External.ExternalDNS,
+						"external DNS requires a pandaproxy subdomain"))
+			}
+			allErrs = append(allErrs, validateExternalDNS(proxyExternal.External.ExternalDNS, proxyExternal.External.Subdomain, path)...)
+		}
 		if (kafkaExternal == nil || !kafkaExternal.External.Enabled) && (proxyExternal != nil && proxyExternal.External.Enabled) {
 			allErrs = append(allErrs,
 				field.Invalid(field.NewPath("spec").Child("configuration").Child("pandaproxyApi").Index(i),
@@ -529,6 +724,10 @@ func (r *Cluster) validateSchemaRegistryListener() field.ErrorList {
 			field.NewPath("spec").Child("configuration").Child("schemaRegistry").Child("external"),
 		)
 		allErrs = append(allErrs, tlsErrs...)
+		allErrs = append(allErrs, validateIssuerNamespace(
+			schemaRegistry.TLS.IssuerRef,
+			schemaRegistry.TLS.IssuerNamespace,
+			field.NewPath("spec").Child("configuration").Child("schemaRegistry").Child("tls"))...)
 	}
 	if !r.IsSchemaRegistryExternallyAvailable() {
 		return allErrs
@@ -570,6 +769,18 @@ func (r *Cluster) validateSchemaRegistryListener() field.ErrorList {
 				r.Spec.Configuration.SchemaRegistry.External.EndpointTemplate,
 				"cannot provide an endpoint template for schema registry"))
 	}
+	if schemaRegistry.External.AdvertisedPortBase != 0 {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec").Child("configuration").Child("schemaRegistry").Child("external").Child("advertisedPortBase"),
+				r.Spec.Configuration.SchemaRegistry.External.AdvertisedPortBase,
+				"cannot provide an advertised port base for schema registry"))
+	}
+	if schemaRegistry.External.ExternalDNS != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec").Child("configuration").Child("schemaRegistry").Child("external").Child("externalDNS"),
+				r.Spec.Configuration.SchemaRegistry.External.ExternalDNS,
+				"cannot provide external DNS for schema registry"))
+	}
 
 	return allErrs
 }
@@ -728,7 +939,8 @@ func hasDifferentIssuers(listener1, listener2 KafkaAPITLS) bool {
 	}
 	return listener1.IssuerRef.Group != listener2.IssuerRef.Group ||
 		listener1.IssuerRef.Kind != listener2.IssuerRef.Kind ||
-		listener1.IssuerRef.Name != listener2.IssuerRef.Name
+		listener1.IssuerRef.Name != listener2.IssuerRef.Name ||
+		listener1.IssuerNamespace != listener2.IssuerNamespace
 }
 
 func hasDifferentNodeSecret(listener1, listener2 KafkaAPITLS) bool {
@@ -745,6 +957,31 @@ func hasDifferentNodeSecret(listener1, listener2 KafkaAPITLS) bool {
 		listener1.NodeSecretRef.Name != listener2.NodeSecretRef.Name
 }
 
+// validateIssuerNamespace checks that the namespace of an issuer is only set
+// for an Issuer, ClusterIssuers are not namespaced
+func validateIssuerNamespace(
+	issuerRef *cmmeta.ObjectReference, issuerNamespace string, path *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	if issuerNamespace == "" {
+		return allErrs
+	}
+	if issuerRef == nil {
+		allErrs = append(allErrs,
+			field.Invalid(
+				path.Child("issuerNamespace"),
+				issuerNamespace,
+				"issuerNamespace requires issuerRef"))
+	} else if issuerRef.Kind == clusterIssuerKind {
+		allErrs = append(allErrs,
+			field.Invalid(
+				path.Child("issuerNamespace"),
+				issuerNamespace,
+				"issuerNamespace cannot be set for a ClusterIssuer"))
+	}
+	return allErrs
+}
+
 func validateListener(
 	tlsEnabled, requireClientAuth bool,
 	issuerRef *cmmeta.ObjectReference,
@@ -807,40 +1044,60 @@ func (r *Cluster) validateArchivalStorage() field.ErrorList {
 	if !r.Spec.CloudStorage.Enabled {
 		return allErrs
 	}
-	if r.Spec.CloudStorage.AccessKey == "" {
-		allErrs = append(allErrs,
-			field.Invalid(
-				field.NewPath("spec").Child("configuration").Child("cloudStorage").Child("accessKey"),
-				r.Spec.CloudStorage.AccessKey,
-				"AccessKey has to be provided for cloud storage to be enabled"))
+	path := field.NewPath("spec").Child("configuration").Child("cloudStorage")
+	if r.Spec.CloudStorage.UsesStaticCredentials() {
+		if r.Spec.CloudStorage.AccessKey == "" {
+			allErrs = append(allErrs,
+				field.Invalid(
+					path.Child("accessKey"),
+					r.Spec.CloudStorage.AccessKey,
+					"AccessKey has to be provided for cloud storage to be enabled"))
+		}
+		if r.Spec.CloudStorage.SecretKeyRef.Name == "" {
+			allErrs = append(allErrs,
+				field.Invalid(
+					path.Child("secretKeyRef").Child("name"),
+					r.Spec.CloudStorage.SecretKeyRef.Name,
+					"SecretKeyRef name has to be provided for cloud storage to be enabled"))
+		}
+		if r.Spec.CloudStorage.SecretKeyRef.Namespace == "" {
+			allErrs = append(allErrs,
+				field.Invalid(
+					path.Child("secretKeyRef").Child("namespace"),
+					r.Spec.CloudStorage.SecretKeyRef.Namespace,
+					"SecretKeyRef namespace has to be provided for cloud storage to be enabled"))
+		}
 	}
 	if r.Spec.CloudStorage.Bucket == "" {
 		allErrs = append(allErrs,
 			field.Invalid(
-				field.NewPath("spec").Child("configuration").Child("cloudStorage").Child("bucket"),
+				path.Child("bucket"),
 				r.Spec.CloudStorage.Bucket,
 				"Bucket has to be provided for cloud storage to be enabled"))
 	}
 	if r.Spec.CloudStorage.Region == "" {
 		allErrs = append(allErrs,
 			field.Invalid(
-				field.NewPath("spec").Child("configuration").Child("cloudStorage").Child("region"),
+				path.Child("region"),
 				r.Spec.CloudStorage.Region,
 				"Region has to be provided for cloud storage to be enabled"))
 	}
-	if r.Spec.CloudStorage.SecretKeyRef.Name == "" {
-		allErrs = append(allErrs,
-			field.Invalid(
-				field.NewPath("spec").Child("configuration").Child("cloudStorage").Child("secretKeyRef").Child("name"),
-				r.Spec.CloudStorage.SecretKeyRef.Name,
-				"SecretKeyRef name has to be provided for cloud storage to be enabled"))
-	}
-	if r.Spec.CloudStorage.SecretKeyRef.Namespace == "" {
-		allErrs = append(allErrs,
-			field.Invalid(
-				field.NewPath("spec").Child("configuration").Child("cloudStorage").Child("secretKeyRef").Child("namespace"),
-				r.Spec.CloudStorage.SecretKeyRef.Namespace,
-				"SecretKeyRef namespace has to be provided for cloud storage to be enabled"))
+	if r.Spec.CloudStorage.IAMRole != "" {
+		switch {
+		case r.Spec.CloudStorage.CredentialsSource != CredentialsSourceSTS &&
+			r.Spec.CloudStorage.CredentialsSource != CredentialsSourceGCPInstanceMetadata:
+			allErrs = append(allErrs,
+				field.Invalid(
+					path.Child("iamRole"),
+					r.Spec.CloudStorage.IAMRole,
+					"IAMRole can only be used with the sts and gcp_instance_metadata credentials sources"))
+		case r.Spec.ServiceAccount != nil && r.Spec.ServiceAccount.Name != "":
+			allErrs = append(allErrs,
+				field.Invalid(
+					path.Child("iamRole"),
+					r.Spec.CloudStorage.IAMRole,
+					"IAMRole cannot be set on an existing ServiceAccount, annotate it instead"))
+		}
 	}
 	return allErrs
 }
@@ -868,6 +1125,316 @@ func (r *Cluster) validatePodDisruptionBudget() field.ErrorList {
 	return allErrs
 }
 
+func (r *Cluster) validateConfigurator() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.Configurator == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("configurator")
+	for _, h := range []struct {
+		name string
+		hook *ConfiguratorHook
+	}{
+		{"preStartHook", r.Spec.Configurator.PreStartHook},
+		{"postStartHook", r.Spec.Configurator.PostStartHook},
+	} {
+		name, hook := h.name, h.hook
+		if hook == nil {
+			continue
+		}
+		if (hook.Script == "") == (hook.ConfigMapRef == nil) {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child(name),
+					hook,
+					"exactly one of script and configMapRef must be specified"))
+			continue
+		}
+		if hook.ConfigMapRef != nil && (hook.ConfigMapRef.Name == "" || hook.ConfigMapRef.Key == "") {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child(name).Child("configMapRef"),
+					hook.ConfigMapRef,
+					"the name and key of the ConfigMap must be specified"))
+		}
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateImages() field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec")
+	if reg := r.Spec.ImageRegistry; reg != "" {
+		lastComponent := reg[strings.LastIndex(reg, "/")+1:]
+		if strings.Contains(reg, "://") || strings.ContainsAny(reg, " @") || strings.Contains(lastComponent, ":") {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("imageRegistry"),
+					reg,
+					"must be a registry host optionally followed by a path, without scheme, tag or digest"))
+		}
+	}
+	for i, s := range r.Spec.ImagePullSecrets {
+		if s.Name == "" {
+			allErrs = append(allErrs,
+				field.Required(path.Child("imagePullSecrets").Index(i).Child("name"),
+					"the name of the Secret must be specified"))
+		}
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateCanaryUpgrade() field.ErrorList {
+	if !r.CanaryUpgradeEnabled() {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if d := r.Spec.RestartConfig.UpdateStrategy.Canary.SoakDuration; d != nil && d.Duration <= 0 {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec").Child("restartConfig").Child("updateStrategy").Child("canary").Child("soakDuration"),
+				d.String(), "must be positive"))
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateMaintenanceWindows() field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range r.Spec.MaintenanceWindows {
+		w := &r.Spec.MaintenanceWindows[i]
+		path := field.NewPath("spec").Child("maintenanceWindows").Index(i)
+		if w.Duration.Duration <= 0 {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("duration"), w.Duration.String(), "must be positive"))
+		}
+		schedule, err := utils.ParseCronSchedule(w.Schedule)
+		if err == nil {
+			err = schedule.Validate()
+		}
+		if err != nil {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("schedule"), w.Schedule, err.Error()))
+		}
+		if w.TimeZone != "" {
+			if _, err := time.LoadLocation(w.TimeZone); err != nil {
+				allErrs = append(allErrs,
+					field.Invalid(path.Child("timeZone"), w.TimeZone, "must be an IANA time zone name"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateServiceAccount() field.ErrorList {
+	var allErrs field.ErrorList
+	sa := r.Spec.ServiceAccount
+	if sa == nil || sa.Name == "" {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("serviceAccount")
+	if len(sa.Annotations) > 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("annotations"),
+				sa.Annotations,
+				"annotations cannot be set on an existing ServiceAccount referenced by name"))
+	}
+	if len(sa.Rules) > 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("rules"),
+				sa.Rules,
+				"rules cannot be granted to an existing ServiceAccount referenced by name"))
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateLogLevels() field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec").Child("configuration").Child("logLevels")
+	for logger, level := range r.Spec.Configuration.LogLevels {
+		if !IsValidLogLevel(level) {
+			allErrs = append(allErrs,
+				field.NotSupported(path.Key(logger),
+					level,
+					[]string{"error", "warn", "info", "debug", "trace"}))
+		}
+	}
+	if _, _, err := r.DebugLogLevels(); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("metadata").Child("annotations").Key(DebugLogLevelsAnnotation),
+				r.Annotations[DebugLogLevelsAnnotation],
+				err.Error()))
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateOperationAnnotations() field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("metadata").Child("annotations")
+	if v, ok := r.Annotations[PauseReconcileAnnotation]; ok && v != "true" && v != "false" {
+		allErrs = append(allErrs,
+			field.NotSupported(path.Key(PauseReconcileAnnotation), v, []string{"true", "false"}))
+	}
+	if v, ok := r.Annotations[RebalanceAnnotation]; ok && v != RebalancePartitions {
+		allErrs = append(allErrs,
+			field.NotSupported(path.Key(RebalanceAnnotation), v, []string{RebalancePartitions}))
+	}
+	if v, ok := r.Annotations[ForceDownscaleAnnotation]; ok {
+		if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+			allErrs = append(allErrs,
+				field.Invalid(path.Key(ForceDownscaleAnnotation), v, "must be the number of replicas the cluster is downscaled to"))
+		}
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateNetworking() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.Networking == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("networking")
+	families := r.Spec.Networking.IPFamilies
+	for i, f := range families {
+		if f != corev1.IPv4Protocol && f != corev1.IPv6Protocol {
+			allErrs = append(allErrs,
+				field.NotSupported(path.Child("ipFamilies").Index(i),
+					f,
+					[]string{string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)}))
+		}
+	}
+	if len(families) == 2 && families[0] == families[1] {
+		allErrs = append(allErrs,
+			field.Duplicate(path.Child("ipFamilies").Index(1), families[1]))
+	}
+	if policy := r.Spec.Networking.IPFamilyPolicy; policy != nil {
+		switch *policy {
+		case corev1.IPFamilyPolicySingleStack:
+			if len(families) > 1 {
+				allErrs = append(allErrs,
+					field.Invalid(path.Child("ipFamilies"),
+						families,
+						"only one IP family can be used with the SingleStack policy"))
+			}
+		case corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack:
+		default:
+			allErrs = append(allErrs,
+				field.NotSupported(path.Child("ipFamilyPolicy"),
+					*policy,
+					[]string{
+						string(corev1.IPFamilyPolicySingleStack),
+						string(corev1.IPFamilyPolicyPreferDualStack),
+						string(corev1.IPFamilyPolicyRequireDualStack),
+					}))
+		}
+	}
+	return allErrs
+}
+
+// validatePrimaryIPFamily forbids changing the primary IP family, which
+// Kubernetes does not allow on existing Services
+func (r *Cluster) validatePrimaryIPFamily(old *Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	oldFamilies, families := old.IPFamilies(), r.IPFamilies()
+	if len(oldFamilies) == 0 {
+		return allErrs
+	}
+	if len(families) == 0 || families[0] != oldFamilies[0] {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec").Child("networking").Child("ipFamilies"),
+				fmt.Sprintf("the primary IP family %s cannot be changed", oldFamilies[0])))
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateSharedCA() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.SharedCA == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("sharedCA").Child("issuerRef")
+	issuerRef := r.Spec.SharedCA.IssuerRef
+	if issuerRef.Name == "" {
+		allErrs = append(allErrs,
+			field.Required(path.Child("name"), "the issuer of the shared CA must be named"))
+	}
+	if issuerRef.Kind != clusterIssuerKind {
+		allErrs = append(allErrs,
+			field.NotSupported(path.Child("kind"), issuerRef.Kind, []string{clusterIssuerKind}))
+	}
+	return allErrs
+}
+
+// validateSharedCAUpdate forbids changing the shared CA, which would make the
+// clients of the cluster distrust its certificates
+func (r *Cluster) validateSharedCAUpdate(old *Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	if !reflect.DeepEqual(old.Spec.SharedCA, r.Spec.SharedCA) {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec").Child("sharedCA"),
+				"the shared CA cannot be changed"))
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateBootstrap() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.Bootstrap == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("bootstrap")
+	if !featuregates.EmptySeedStartsCluster(r.Spec.Version) {
+		allErrs = append(allErrs,
+			field.Forbidden(path,
+				fmt.Sprintf("designating the seed servers requires Redpanda v22.3 or later, got version %q", r.Spec.Version)))
+	}
+	seeds := r.Spec.Bootstrap.SeedReplicas
+	if seeds < 1 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("seedReplicas"), seeds,
+				"the cluster needs at least 1 seed server"))
+	} else if r.Spec.Replicas != nil && seeds > *r.Spec.Replicas {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("seedReplicas"), seeds,
+				fmt.Sprintf("the cluster cannot have more seed servers than its %d replicas", *r.Spec.Replicas)))
+	}
+	return allErrs
+}
+
+// validateAdminAPIAuthentication requires the Secret of the credentials of
+// the operator, and a version that bootstraps its user: clusters formed with
+// the admin API requiring authentication could not be reached otherwise
+func (r *Cluster) validateAdminAPIAuthentication() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.AdminAPIAuthentication == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("adminApiAuthentication")
+	if !featuregates.BootstrapUser(r.Spec.Version) {
+		allErrs = append(allErrs,
+			field.Forbidden(path,
+				fmt.Sprintf("admin API authentication requires Redpanda v22.3 or later, got version %q", r.Spec.Version)))
+	}
+	if r.Spec.AdminAPIAuthentication.CredentialsSecretRef.Name == "" {
+		allErrs = append(allErrs,
+			field.Required(path.Child("credentialsSecretRef").Child("name"),
+				"the operator needs the Secret of its credentials"))
+	}
+	if r.AdminAPIInternal() == nil {
+		allErrs = append(allErrs,
+			field.Forbidden(path,
+				"admin API authentication requires an internal admin API listener"))
+	}
+	return allErrs
+}
+
+// validateBootstrapUpdate forbids changing the seed servers once designated,
+// which could let a new set of seed servers form a second cluster
+func (r *Cluster) validateBootstrapUpdate(old *Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	if old.Spec.Bootstrap != nil && !reflect.DeepEqual(old.Spec.Bootstrap, r.Spec.Bootstrap) {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec").Child("bootstrap"),
+				"the seed servers cannot be changed once designated"))
+	}
+	return allErrs
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateDelete() error {
 	log.Info("validate delete", "name", r.Name)
@@ -10,15 +10,18 @@
 package v1alpha1_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1134,6 +1137,40 @@ func TestCreation(t *testing.T) {
 		err := rp.ValidateCreate()
 		assert.NoError(t, err)
 	})
+	t.Run("valid advertised port base in kafka API", func(t *testing.T) {
+		rp := redpandaCluster.DeepCopy()
+
+		rp.Spec.Configuration.KafkaAPI = append(rp.Spec.Configuration.KafkaAPI, v1alpha1.KafkaAPI{External: v1alpha1.ExternalConnectivityConfig{
+			Enabled:            true,
+			AdvertisedPortBase: 9092,
+		}})
+		err := rp.ValidateCreate()
+		assert.NoError(t, err)
+	})
+	t.Run("advertised port base out of range in kafka API", func(t *testing.T) {
+		rp := redpandaCluster.DeepCopy()
+
+		rp.Spec.Configuration.KafkaAPI = append(rp.Spec.Configuration.KafkaAPI, v1alpha1.KafkaAPI{External: v1alpha1.ExternalConnectivityConfig{
+			Enabled:            true,
+			AdvertisedPortBase: 65535,
+		}})
+		rp.Spec.Replicas = pointer.Int32Ptr(3)
+		err := rp.ValidateCreate()
+		assert.Error(t, err)
+	})
+	t.Run("advertised port base not allowed in pandaproxy API", func(t *testing.T) {
+		rp := redpandaCluster.DeepCopy()
+
+		rp.Spec.Configuration.KafkaAPI = append(rp.Spec.Configuration.KafkaAPI, v1alpha1.KafkaAPI{External: v1alpha1.ExternalConnectivityConfig{
+			Enabled: true,
+		}})
+		rp.Spec.Configuration.PandaproxyAPI = append(rp.Spec.Configuration.PandaproxyAPI, v1alpha1.PandaproxyAPI{External: v1alpha1.ExternalConnectivityConfig{
+			Enabled:            true,
+			AdvertisedPortBase: 8082,
+		}})
+		err := rp.ValidateCreate()
+		assert.Error(t, err)
+	})
 }
 
 func TestSchemaRegistryValidations(t *testing.T) {
@@ -1309,6 +1346,68 @@ func TestPodDisruptionBudget(t *testing.T) {
 	})
 }
 
+func TestConfiguratorHooks(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	configMapRef := &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "hooks"},
+		Key:                  "pre-start.sh",
+	}
+
+	t.Run("image override without hooks is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configurator = &v1alpha1.ConfiguratorConfig{
+			Image: "registry.local/configurator",
+			Tag:   "v22.2.1",
+		}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("inline and configmap hooks are valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configurator = &v1alpha1.ConfiguratorConfig{
+			PreStartHook:  &v1alpha1.ConfiguratorHook{ConfigMapRef: configMapRef},
+			PostStartHook: &v1alpha1.ConfiguratorHook{Script: "echo done"},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("hook with both script and configmap is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configurator = &v1alpha1.ConfiguratorConfig{
+			PreStartHook: &v1alpha1.ConfiguratorHook{Script: "echo", ConfigMapRef: configMapRef},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("empty hook is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configurator = &v1alpha1.ConfiguratorConfig{
+			PostStartHook: &v1alpha1.ConfiguratorHook{},
+		}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.Error(t, err)
+	})
+
+	t.Run("configmap without key is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configurator = &v1alpha1.ConfiguratorConfig{
+			PreStartHook: &v1alpha1.ConfiguratorHook{ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "hooks"},
+			}},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+}
+
 func TestExternalKafkaPortSpecified(t *testing.T) {
 	rpCluster := validRedpandaCluster()
 
@@ -1342,6 +1441,39 @@ func TestExternalKafkaPortSpecified(t *testing.T) {
 	})
 }
 
+func TestKafkaAuthenticationMethod(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Configuration.KafkaAPI = append(rpCluster.Spec.Configuration.KafkaAPI,
+		v1alpha1.KafkaAPI{Port: 30001, External: v1alpha1.ExternalConnectivityConfig{Enabled: true, Subdomain: "redpanda.com"}})
+
+	t.Run("sasl on the internal listener only", func(t *testing.T) {
+		newRp := rpCluster.DeepCopy()
+		newRp.Spec.Configuration.KafkaAPI[0].AuthenticationMethod = v1alpha1.KafkaAuthenticationSASL
+		assert.NoError(t, newRp.ValidateCreate())
+	})
+
+	t.Run("mtls_identity requires client authentication", func(t *testing.T) {
+		newRp := rpCluster.DeepCopy()
+		newRp.Spec.Configuration.KafkaAPI[1].AuthenticationMethod = v1alpha1.KafkaAuthenticationMTLSIdentity
+		newRp.Spec.Configuration.KafkaAPI[1].TLS = v1alpha1.KafkaAPITLS{Enabled: true}
+		assert.Error(t, newRp.ValidateCreate())
+
+		newRp.Spec.Configuration.KafkaAPI[1].TLS.RequireClientAuth = true
+		assert.NoError(t, newRp.ValidateCreate())
+	})
+
+	t.Run("mtls_identity on the internal listener with pandaproxy", func(t *testing.T) {
+		newRp := rpCluster.DeepCopy()
+		newRp.Spec.Configuration.KafkaAPI[0].AuthenticationMethod = v1alpha1.KafkaAuthenticationMTLSIdentity
+		newRp.Spec.Configuration.KafkaAPI[0].TLS = v1alpha1.KafkaAPITLS{Enabled: true, RequireClientAuth: true}
+		assert.Error(t, newRp.ValidateCreate())
+
+		newRp.Spec.Configuration.PandaproxyAPI = nil
+		newRp.Spec.Configuration.SchemaRegistry = nil
+		assert.NoError(t, newRp.ValidateCreate())
+	})
+}
+
 func TestKafkaTLSRules(t *testing.T) {
 	rpCluster := validRedpandaCluster()
 
@@ -1437,3 +1569,679 @@ func TestKafkaTLSRules(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestIssuers(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	t.Run("issuer in another namespace", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[0].TLS = v1alpha1.KafkaAPITLS{
+			Enabled: true,
+			IssuerRef: &cmmeta.ObjectReference{
+				Name: "issuer",
+				Kind: "Issuer",
+			},
+			IssuerNamespace: "cert-manager",
+		}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("issuer namespace without issuer", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[0].TLS = v1alpha1.KafkaAPITLS{
+			Enabled:         true,
+			IssuerNamespace: "cert-manager",
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("issuer namespace of a cluster issuer", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.SchemaRegistry.TLS = &v1alpha1.SchemaRegistryAPITLS{
+			Enabled: true,
+			IssuerRef: &cmmeta.ObjectReference{
+				Name: "issuer",
+				Kind: "ClusterIssuer",
+			},
+			IssuerNamespace: "cert-manager",
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("shared CA", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.SharedCA = &v1alpha1.SharedCAConfig{
+			IssuerRef: cmmeta.ObjectReference{
+				Name: "organization-ca",
+				Kind: "ClusterIssuer",
+			},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+
+		rpc.Spec.SharedCA.IssuerRef.Kind = "Issuer"
+		err = rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("shared CA cannot change", func(t *testing.T) {
+		old := rpCluster.DeepCopy()
+		old.Spec.SharedCA = &v1alpha1.SharedCAConfig{
+			IssuerRef: cmmeta.ObjectReference{
+				Name: "organization-ca",
+				Kind: "ClusterIssuer",
+			},
+		}
+		rpc := old.DeepCopy()
+		rpc.Spec.SharedCA.IssuerRef.Name = "other-ca"
+
+		err := rpc.ValidateUpdate(old)
+		assert.Error(t, err)
+
+		rpc.Spec.SharedCA = nil
+		err = rpc.ValidateUpdate(old)
+		assert.Error(t, err)
+	})
+}
+
+func TestBootstrap(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Version = "v22.3.1"
+	rpCluster.Spec.Replicas = pointer.Int32Ptr(5)
+
+	t.Run("seed servers", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 3}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("more seed servers than replicas", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 7}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("no seed servers", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Bootstrap = &v1alpha1.BootstrapConfig{}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Version = "v22.2.7"
+		rpc.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 3}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("seed servers can be designated on an existing cluster", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 3}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.NoError(t, err)
+	})
+
+	t.Run("seed servers cannot change", func(t *testing.T) {
+		old := rpCluster.DeepCopy()
+		old.Spec.Bootstrap = &v1alpha1.BootstrapConfig{SeedReplicas: 3}
+		rpc := old.DeepCopy()
+		rpc.Spec.Bootstrap.SeedReplicas = 5
+
+		err := rpc.ValidateUpdate(old)
+		assert.Error(t, err)
+
+		rpc.Spec.Bootstrap = nil
+		err = rpc.ValidateUpdate(old)
+		assert.Error(t, err)
+	})
+}
+
+func TestExternalDNS(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Replicas = pointer.Int32Ptr(3)
+	rpCluster.Spec.Configuration.KafkaAPI = append(rpCluster.Spec.Configuration.KafkaAPI,
+		v1alpha1.KafkaAPI{External: v1alpha1.ExternalConnectivityConfig{
+			Enabled:     true,
+			Subdomain:   "kafka.example.com",
+			Bootstrap:   &v1alpha1.LoadBalancerConfig{Port: 9092},
+			ExternalDNS: &v1alpha1.ExternalDNSConfig{TTL: 60},
+		}})
+
+	t.Run("kafka with subdomain and bootstrap load balancer", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("kafka with hostname and no subdomain", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[1].External.Subdomain = ""
+		rpc.Spec.Configuration.KafkaAPI[1].External.ExternalDNS.Hostname = "bootstrap.example.com"
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("kafka without hostname and subdomain", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[1].External.Subdomain = ""
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "hostname is required when the listener has no subdomain")
+	})
+
+	t.Run("kafka without bootstrap load balancer", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[1].External.Bootstrap = nil
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "external DNS requires a bootstrap loadbalancer")
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[1].External.ExternalDNS.TTL = -1
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ttl cannot be negative")
+	})
+
+	t.Run("kafka with endpoint template of the index", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[1].External.EndpointTemplate = "broker-{{.Index}}"
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("kafka with endpoint template of the host IP", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.KafkaAPI[1].External.EndpointTemplate = "{{.Index}}-{{.HostIP | sha256sum | substr 0 8}}"
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "endpoint template that uses the host IP")
+	})
+
+	t.Run("pandaproxy with subdomain", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.PandaproxyAPI = append(rpc.Spec.Configuration.PandaproxyAPI,
+			v1alpha1.PandaproxyAPI{External: v1alpha1.ExternalConnectivityConfig{
+				Enabled:     true,
+				Subdomain:   "kafka.example.com",
+				ExternalDNS: &v1alpha1.ExternalDNSConfig{},
+			}})
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("pandaproxy without subdomain", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.PandaproxyAPI = append(rpc.Spec.Configuration.PandaproxyAPI,
+			v1alpha1.PandaproxyAPI{External: v1alpha1.ExternalConnectivityConfig{
+				Enabled:     true,
+				ExternalDNS: &v1alpha1.ExternalDNSConfig{Hostname: "proxy.example.com"},
+			}})
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "external DNS requires a pandaproxy subdomain")
+	})
+
+	t.Run("admin api", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.AdminAPI = append(rpc.Spec.Configuration.AdminAPI,
+			v1alpha1.AdminAPI{External: v1alpha1.ExternalConnectivityConfig{
+				Enabled:     true,
+				ExternalDNS: &v1alpha1.ExternalDNSConfig{Hostname: "admin.example.com"},
+			}})
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot provide external DNS for admin listener")
+	})
+
+	t.Run("schema registry", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.SchemaRegistry.External = &v1alpha1.ExternalConnectivityConfig{
+			Enabled:     true,
+			ExternalDNS: &v1alpha1.ExternalDNSConfig{Hostname: "schema.example.com"},
+		}
+
+		err := rpc.ValidateCreate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot provide external DNS for schema registry")
+	})
+}
+
+func TestServiceAccount(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	rules := []rbacv1.PolicyRule{{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get"},
+	}}
+
+	t.Run("dedicated account with rules is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.ServiceAccount = &v1alpha1.ServiceAccountConfig{
+			Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn"},
+			Rules:       rules,
+		}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("existing account is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.ServiceAccount = &v1alpha1.ServiceAccountConfig{Name: "existing"}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.NoError(t, err)
+	})
+
+	t.Run("existing account with rules is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.ServiceAccount = &v1alpha1.ServiceAccountConfig{Name: "existing", Rules: rules}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("existing account with annotations is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.ServiceAccount = &v1alpha1.ServiceAccountConfig{
+			Name:        "existing",
+			Annotations: map[string]string{"foo": "bar"},
+		}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.Error(t, err)
+	})
+}
+
+func TestCloudStorageCredentialsSource(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.CloudStorage = v1alpha1.CloudStorageConfig{
+		Enabled: true,
+		Bucket:  "bucket",
+		Region:  "us-east-1",
+	}
+
+	t.Run("static credentials are required by default", func(t *testing.T) {
+		err := rpCluster.DeepCopy().ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("irsa without secret is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.CloudStorage.CredentialsSource = v1alpha1.CredentialsSourceSTS
+		rpc.Spec.CloudStorage.IAMRole = "arn:aws:iam::123456789012:role/redpanda"
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("node role without secret is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.CloudStorage.CredentialsSource = v1alpha1.CredentialsSourceAWSInstanceMetadata
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.NoError(t, err)
+	})
+
+	t.Run("iam role with node role is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.CloudStorage.CredentialsSource = v1alpha1.CredentialsSourceAWSInstanceMetadata
+		rpc.Spec.CloudStorage.IAMRole = "arn:aws:iam::123456789012:role/redpanda"
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("iam role with existing account is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.CloudStorage.CredentialsSource = v1alpha1.CredentialsSourceGCPInstanceMetadata
+		rpc.Spec.CloudStorage.IAMRole = "redpanda@project.iam.gserviceaccount.com"
+		rpc.Spec.ServiceAccount = &v1alpha1.ServiceAccountConfig{Name: "existing"}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+}
+
+func TestLogLevels(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	t.Run("valid levels", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.LogLevels = map[string]string{"raft": "trace"}
+		rpc.Annotations = map[string]string{v1alpha1.DebugLogLevelsAnnotation: "kafka=debug"}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Configuration.LogLevels = map[string]string{"raft": "verbose"}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid debug annotation", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Annotations = map[string]string{v1alpha1.DebugLogLevelsAnnotation: "raft"}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.Error(t, err)
+	})
+}
+
+func TestImages(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	for _, test := range []struct {
+		name     string
+		registry string
+		secrets  []corev1.LocalObjectReference
+		valid    bool
+	}{
+		{"registry host", "registry.example.com", nil, true},
+		{"registry with port and path", "registry.example.com:5000/mirror/", []corev1.LocalObjectReference{{Name: "pull"}}, true},
+		{"registry with scheme", "https://registry.example.com", nil, false},
+		{"registry with tag", "registry.example.com/mirror:v1", nil, false},
+		{"unnamed pull secret", "", []corev1.LocalObjectReference{{}}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rpc := rpCluster.DeepCopy()
+			rpc.Spec.ImageRegistry = test.registry
+			rpc.Spec.ImagePullSecrets = test.secrets
+
+			err := rpc.ValidateCreate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	for _, test := range []struct {
+		name   string
+		window v1alpha1.MaintenanceWindow
+		valid  bool
+	}{
+		{"nightly", v1alpha1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}, true},
+		{"with time zone", v1alpha1.MaintenanceWindow{Schedule: "30 1 * * 6,7", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Paris"}, true},
+		{"invalid schedule", v1alpha1.MaintenanceWindow{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}}, false},
+		{"never opens", v1alpha1.MaintenanceWindow{Schedule: "0 2 30 2 *", Duration: metav1.Duration{Duration: time.Hour}}, false},
+		{"no duration", v1alpha1.MaintenanceWindow{Schedule: "0 2 * * *"}, false},
+		{"unknown time zone", v1alpha1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rpc := rpCluster.DeepCopy()
+			rpc.Spec.MaintenanceWindows = []v1alpha1.MaintenanceWindow{test.window}
+
+			err := rpc.ValidateCreate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCanaryUpgrade(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	for _, test := range []struct {
+		name   string
+		canary v1alpha1.CanaryUpgrade
+		valid  bool
+	}{
+		{"default soak", v1alpha1.CanaryUpgrade{Enabled: true}, true},
+		{"soak", v1alpha1.CanaryUpgrade{Enabled: true, SoakDuration: &metav1.Duration{Duration: time.Hour}}, true},
+		{"no soak", v1alpha1.CanaryUpgrade{Enabled: true, SoakDuration: &metav1.Duration{}}, false},
+		{"disabled", v1alpha1.CanaryUpgrade{SoakDuration: &metav1.Duration{}}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rpc := rpCluster.DeepCopy()
+			canary := test.canary
+			rpc.Spec.RestartConfig = &v1alpha1.RestartConfig{
+				UpdateStrategy: &v1alpha1.UpdateStrategy{Canary: &canary},
+			}
+
+			err := rpc.ValidateCreate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNetworking(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	singleStack := corev1.IPFamilyPolicySingleStack
+	dualStack := corev1.IPFamilyPolicyRequireDualStack
+
+	t.Run("dual stack", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Networking = &v1alpha1.NetworkingConfig{
+			IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			IPFamilyPolicy: &dualStack,
+		}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown family", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Networking = &v1alpha1.NetworkingConfig{
+			IPFamilies: []corev1.IPFamily{"IPv5"},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate family", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Networking = &v1alpha1.NetworkingConfig{
+			IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv6Protocol},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("two families with single stack", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Networking = &v1alpha1.NetworkingConfig{
+			IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			IPFamilyPolicy: &singleStack,
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("primary family cannot change", func(t *testing.T) {
+		old := rpCluster.DeepCopy()
+		old.Spec.Networking = &v1alpha1.NetworkingConfig{
+			IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+		}
+		rpc := old.DeepCopy()
+		rpc.Spec.Networking.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+
+		err := rpc.ValidateUpdate(old)
+		assert.Error(t, err)
+
+		rpc.Spec.Networking.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+		err = rpc.ValidateUpdate(old)
+		assert.NoError(t, err)
+	})
+}
+
+func TestOperationAnnotations(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	t.Run("valid annotations", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Annotations = map[string]string{
+			v1alpha1.PauseReconcileAnnotation: "false",
+			v1alpha1.RestartAnnotation:        "now",
+			v1alpha1.RebalanceAnnotation:      v1alpha1.RebalancePartitions,
+		}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid pause value", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Annotations = map[string]string{v1alpha1.PauseReconcileAnnotation: "yes"}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported rebalance", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Annotations = map[string]string{v1alpha1.RebalanceAnnotation: "racks"}
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.Error(t, err)
+	})
+}
+
+func TestDownscalingProtection(t *testing.T) {
+	v1alpha1.AllowDownscalingInWebhook = true
+	defer func() {
+		v1alpha1.AllowDownscalingInWebhook = false
+		v1alpha1.MaxReplicationFactorInWebhook = nil
+	}()
+
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Replicas = pointer.Int32Ptr(5)
+	downscaled := func(replicas int32) *v1alpha1.Cluster {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Replicas = pointer.Int32Ptr(replicas)
+		return rpc
+	}
+	v1alpha1.MaxReplicationFactorInWebhook = func(context.Context, *v1alpha1.Cluster) (int, error) {
+		return 3, nil
+	}
+
+	t.Run("above the replication factor", func(t *testing.T) {
+		assert.NoError(t, downscaled(3).ValidateUpdate(rpCluster))
+	})
+
+	t.Run("below the replication factor", func(t *testing.T) {
+		err := downscaled(2).ValidateUpdate(rpCluster)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), v1alpha1.ForceDownscaleAnnotation)
+	})
+
+	t.Run("below the configured replication", func(t *testing.T) {
+		rpc := downscaled(3)
+		rpc.Spec.AdditionalConfiguration = map[string]string{"redpanda.default_topic_replications": "5"}
+		assert.Error(t, rpc.ValidateUpdate(rpCluster))
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		rpc := downscaled(2)
+		rpc.Annotations = map[string]string{v1alpha1.ForceDownscaleAnnotation: "2"}
+		assert.NoError(t, rpc.ValidateUpdate(rpCluster))
+	})
+
+	t.Run("forced to another number of replicas", func(t *testing.T) {
+		rpc := downscaled(1)
+		rpc.Annotations = map[string]string{v1alpha1.ForceDownscaleAnnotation: "2"}
+		assert.Error(t, rpc.ValidateUpdate(rpCluster))
+	})
+
+	t.Run("invalid force annotation", func(t *testing.T) {
+		rpc := downscaled(3)
+		rpc.Annotations = map[string]string{v1alpha1.ForceDownscaleAnnotation: "yes"}
+		assert.Error(t, rpc.ValidateUpdate(rpCluster))
+	})
+
+	t.Run("unable to list topics", func(t *testing.T) {
+		v1alpha1.MaxReplicationFactorInWebhook = func(context.Context, *v1alpha1.Cluster) (int, error) {
+			return 0, fmt.Errorf("connection refused")
+		}
+		assert.Error(t, downscaled(4).ValidateUpdate(rpCluster))
+	})
+}
+
+func TestAdminAPIAuthentication(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Version = "v22.3.1"
+	auth := &v1alpha1.AdminAPIAuthentication{
+		CredentialsSecretRef: corev1.LocalObjectReference{Name: "operator-credentials"},
+	}
+
+	t.Run("credentials", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.AdminAPIAuthentication = auth.DeepCopy()
+
+		assert.NoError(t, rpc.ValidateCreate())
+		assert.NoError(t, rpc.ValidateUpdate(rpCluster))
+	})
+
+	t.Run("no Secret", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.AdminAPIAuthentication = &v1alpha1.AdminAPIAuthentication{}
+
+		assert.Error(t, rpc.ValidateCreate())
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Version = "v22.2.7"
+		rpc.Spec.AdminAPIAuthentication = auth.DeepCopy()
+
+		assert.Error(t, rpc.ValidateCreate())
+	})
+
+	t.Run("no internal admin API", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.AdminAPIAuthentication = auth.DeepCopy()
+		rpc.Spec.Configuration.AdminAPI[0].External.Enabled = true
+
+		assert.Error(t, rpc.ValidateCreate())
+	})
+}
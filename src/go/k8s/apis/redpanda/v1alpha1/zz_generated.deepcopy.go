@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	metav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apismetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -39,6 +40,22 @@ func (in *AdminAPI) DeepCopy() *AdminAPI {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminAPIAuthentication) DeepCopyInto(out *AdminAPIAuthentication) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminAPIAuthentication.
+func (in *AdminAPIAuthentication) DeepCopy() *AdminAPIAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminAPIAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdminAPITLS) DeepCopyInto(out *AdminAPITLS) {
 	*out = *in
@@ -54,6 +71,76 @@ func (in *AdminAPITLS) DeepCopy() *AdminAPITLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapConfig) DeepCopyInto(out *BootstrapConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapConfig.
+func (in *BootstrapConfig) DeepCopy() *BootstrapConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryHealth) DeepCopyInto(out *CanaryHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryHealth.
+func (in *CanaryHealth) DeepCopy() *CanaryHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	out.Baseline = in.Baseline
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgrade) DeepCopyInto(out *CanaryUpgrade) {
+	*out = *in
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(apismetav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgrade.
+func (in *CanaryUpgrade) DeepCopy() *CanaryUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudStorageConfig) DeepCopyInto(out *CloudStorageConfig) {
 	*out = *in
@@ -160,6 +247,25 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
@@ -194,6 +300,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = make([]Superuser, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdminAPIAuthentication != nil {
+		in, out := &in.AdminAPIAuthentication, &out.AdminAPIAuthentication
+		*out = new(AdminAPIAuthentication)
+		**out = **in
+	}
 	if in.AdditionalConfiguration != nil {
 		in, out := &in.AdditionalConfiguration, &out.AdditionalConfiguration
 		*out = make(map[string]string, len(*in))
@@ -206,6 +317,51 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(RestartConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LicenseRef != nil {
+		in, out := &in.LicenseRef, &out.LicenseRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Configurator != nil {
+		in, out := &in.Configurator, &out.Configurator
+		*out = new(ConfiguratorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ServiceAccountConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(NetworkingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskValidation != nil {
+		in, out := &in.DiskValidation, &out.DiskValidation
+		*out = new(DiskValidationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedCA != nil {
+		in, out := &in.SharedCA, &out.SharedCA
+		*out = new(SharedCAConfig)
+		**out = **in
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapConfig)
+		**out = **in
+	}
+	if in.ConnectionProfile != nil {
+		in, out := &in.ConnectionProfile, &out.ConnectionProfile
+		*out = new(ConnectionProfileConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -227,6 +383,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(LicenseStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ClusterCondition, len(*in))
@@ -234,6 +395,42 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConfigurationErrors != nil {
+		in, out := &in.ConfigurationErrors, &out.ConfigurationErrors
+		*out = make([]ConfigurationError, len(*in))
+		copy(*out, *in)
+	}
+	if in.DebugLogLevels != nil {
+		in, out := &in.DebugLogLevels, &out.DebugLogLevels
+		*out = new(DebugLogLevelsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestartedAt != nil {
+		in, out := &in.RestartedAt, &out.RestartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.DiskValidation != nil {
+		in, out := &in.DiskValidation, &out.DiskValidation
+		*out = new(DiskValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = new(PendingMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]EndpointStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -246,6 +443,66 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationError) DeepCopyInto(out *ConfigurationError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationError.
+func (in *ConfigurationError) DeepCopy() *ConfigurationError {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfiguratorConfig) DeepCopyInto(out *ConfiguratorConfig) {
+	*out = *in
+	if in.PreStartHook != nil {
+		in, out := &in.PreStartHook, &out.PreStartHook
+		*out = new(ConfiguratorHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostStartHook != nil {
+		in, out := &in.PostStartHook, &out.PostStartHook
+		*out = new(ConfiguratorHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfiguratorConfig.
+func (in *ConfiguratorConfig) DeepCopy() *ConfiguratorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfiguratorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfiguratorHook) DeepCopyInto(out *ConfiguratorHook) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfiguratorHook.
+func (in *ConfiguratorHook) DeepCopy() *ConfiguratorHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfiguratorHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Connect) DeepCopyInto(out *Connect) {
 	*out = *in
@@ -333,6 +590,21 @@ func (in *ConnectClusterTLS) DeepCopy() *ConnectClusterTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionProfileConfig) DeepCopyInto(out *ConnectionProfileConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionProfileConfig.
+func (in *ConnectionProfileConfig) DeepCopy() *ConnectionProfileConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionProfileConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Connectivity) DeepCopyInto(out *Connectivity) {
 	*out = *in
@@ -467,6 +739,23 @@ func (in *ConsoleStatus) DeepCopy() *ConsoleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DebugLogLevelsStatus) DeepCopyInto(out *DebugLogLevelsStatus) {
+	*out = *in
+	out.Duration = in.Duration
+	in.Expiration.DeepCopyInto(&out.Expiration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugLogLevelsStatus.
+func (in *DebugLogLevelsStatus) DeepCopy() *DebugLogLevelsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DebugLogLevelsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Deployment) DeepCopyInto(out *Deployment) {
 	*out = *in
@@ -482,6 +771,63 @@ func (in *Deployment) DeepCopy() *Deployment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskValidationConfig) DeepCopyInto(out *DiskValidationConfig) {
+	*out = *in
+	if in.MinWriteBandwidth != nil {
+		in, out := &in.MinWriteBandwidth, &out.MinWriteBandwidth
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(apismetav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskValidationConfig.
+func (in *DiskValidationConfig) DeepCopy() *DiskValidationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskValidationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskValidationStatus) DeepCopyInto(out *DiskValidationStatus) {
+	*out = *in
+	in.CompletedAt.DeepCopyInto(&out.CompletedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskValidationStatus.
+func (in *DiskValidationStatus) DeepCopy() *DiskValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Enterprise) DeepCopyInto(out *Enterprise) {
 	*out = *in
@@ -600,6 +946,11 @@ func (in *ExternalConnectivityConfig) DeepCopyInto(out *ExternalConnectivityConf
 		*out = new(LoadBalancerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNSConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalConnectivityConfig.
@@ -612,6 +963,21 @@ func (in *ExternalConnectivityConfig) DeepCopy() *ExternalConnectivityConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSConfig) DeepCopyInto(out *ExternalDNSConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSConfig.
+func (in *ExternalDNSConfig) DeepCopy() *ExternalDNSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaAPI) DeepCopyInto(out *KafkaAPI) {
 	*out = *in
@@ -654,6 +1020,25 @@ func (in *KafkaAPITLS) DeepCopy() *KafkaAPITLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseStatus) DeepCopyInto(out *LicenseStatus) {
+	*out = *in
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseStatus.
+func (in *LicenseStatus) DeepCopy() *LicenseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerWithName) DeepCopyInto(out *ListenerWithName) {
 	*out = *in
@@ -708,6 +1093,22 @@ func (in *LoadBalancerStatus) DeepCopy() *LoadBalancerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespaceNameRef) DeepCopyInto(out *NamespaceNameRef) {
 	*out = *in
@@ -723,6 +1124,31 @@ func (in *NamespaceNameRef) DeepCopy() *NamespaceNameRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkingConfig) DeepCopyInto(out *NetworkingConfig) {
+	*out = *in
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(v1.IPFamilyPolicyType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkingConfig.
+func (in *NetworkingConfig) DeepCopy() *NetworkingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodesList) DeepCopyInto(out *NodesList) {
 	*out = *in
@@ -830,6 +1256,27 @@ func (in *PandaproxyAPITLS) DeepCopy() *PandaproxyAPITLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingMaintenanceStatus) DeepCopyInto(out *PendingMaintenanceStatus) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.NextWindow.DeepCopyInto(&out.NextWindow)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingMaintenanceStatus.
+func (in *PendingMaintenanceStatus) DeepCopy() *PendingMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedpandaConfig) DeepCopyInto(out *RedpandaConfig) {
 	*out = *in
@@ -860,6 +1307,13 @@ func (in *RedpandaConfig) DeepCopyInto(out *RedpandaConfig) {
 		*out = new(SchemaRegistryAPI)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LogLevels != nil {
+		in, out := &in.LogLevels, &out.LogLevels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedpandaConfig.
@@ -903,6 +1357,11 @@ func (in *RestartConfig) DeepCopyInto(out *RestartConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartConfig.
@@ -1050,6 +1509,51 @@ func (in *Server) DeepCopy() *Server {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountConfig) DeepCopyInto(out *ServiceAccountConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountConfig.
+func (in *ServiceAccountConfig) DeepCopy() *ServiceAccountConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedCAConfig) DeepCopyInto(out *SharedCAConfig) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedCAConfig.
+func (in *SharedCAConfig) DeepCopy() *SharedCAConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedCAConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sidecar) DeepCopyInto(out *Sidecar) {
 	*out = *in
@@ -1160,3 +1664,28 @@ func (in *TLSConfig) DeepCopy() *TLSConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,226 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// KafkaListenerNamesAnnotation keeps the names of the Kafka API listeners of
+// a cluster in its v1alpha1 version, which has no listener names, as a comma
+// separated list in the order of the listeners. It is only set when a name
+// differs from the default name of its listener.
+const KafkaListenerNamesAnnotation = "redpanda.vectorized.io/kafka-listener-names"
+
+var _ conversion.Convertible = &Cluster{}
+
+// ConvertTo converts the cluster to the v1alpha1 hub version.
+func (r *Cluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Cluster)
+	if !ok {
+		return fmt.Errorf("unable to convert a cluster to %T", dstRaw)
+	}
+	in := r.DeepCopy()
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Status = in.Status
+
+	spec := &in.Spec
+	dst.Spec = v1alpha1.ClusterSpec{
+		Annotations:                  spec.Annotations,
+		CommonLabels:                 spec.CommonLabels,
+		CommonAnnotations:            spec.CommonAnnotations,
+		Image:                        spec.Image,
+		Version:                      spec.Version,
+		ImagePullSecrets:             spec.ImagePullSecrets,
+		ImageRegistry:                spec.ImageRegistry,
+		Replicas:                     spec.Replicas,
+		PodDisruptionBudget:          spec.PodDisruptionBudget,
+		Resources:                    spec.Resources,
+		Sidecars:                     spec.Sidecars,
+		Tolerations:                  spec.Tolerations,
+		NodeSelector:                 spec.NodeSelector,
+		Storage:                      spec.Storage,
+		CloudStorage:                 spec.CloudStorage,
+		PVCRetentionPolicy:           spec.PVCRetentionPolicy,
+		Superusers:                   spec.Superusers,
+		EnableSASL:                   spec.EnableSASL,
+		AdminAPIAuthentication:       spec.AdminAPIAuthentication,
+		AdditionalConfiguration:      spec.AdditionalConfiguration,
+		DNSTrailingDotDisabled:       spec.DNSTrailingDotDisabled,
+		RestartConfig:                spec.RestartConfig,
+		LicenseRef:                   spec.LicenseRef,
+		Configurator:                 spec.Configurator,
+		ServiceAccount:               spec.ServiceAccount,
+		ValidateClusterConfiguration: spec.ValidateClusterConfiguration,
+		Networking:                   spec.Networking,
+		DiskValidation:               spec.DiskValidation,
+		MaintenanceWindows:           spec.MaintenanceWindows,
+		SharedCA:                     spec.SharedCA,
+		Bootstrap:                    spec.Bootstrap,
+		ConnectionProfile:            spec.ConnectionProfile,
+	}
+
+	cfg := &spec.Configuration
+	dst.Spec.Configuration = v1alpha1.RedpandaConfig{
+		RPCServer:            cfg.RPCServer,
+		AdminAPI:             cfg.AdminAPI,
+		PandaproxyAPI:        cfg.PandaproxyAPI,
+		SchemaRegistry:       cfg.SchemaRegistry,
+		DeveloperMode:        cfg.DeveloperMode,
+		DefaultLogLevel:      cfg.DefaultLogLevel,
+		LogLevels:            cfg.LogLevels,
+		GroupTopicPartitions: cfg.GroupTopicPartitions,
+		AutoCreateTopics:     cfg.AutoCreateTopics,
+	}
+	names := make([]string, 0, len(cfg.KafkaListeners))
+	for _, l := range cfg.KafkaListeners {
+		names = append(names, l.Name)
+		dst.Spec.Configuration.KafkaAPI = append(dst.Spec.Configuration.KafkaAPI, v1alpha1.KafkaAPI{
+			Port:                 l.Port,
+			External:             l.External,
+			TLS:                  l.TLS,
+			AuthenticationMethod: l.AuthenticationMethod,
+		})
+	}
+
+	delete(dst.Annotations, KafkaListenerNamesAnnotation)
+	if !equalNames(names, defaultListenerNames(dst.Spec.Configuration.KafkaAPI)) {
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string)
+		}
+		dst.Annotations[KafkaListenerNamesAnnotation] = strings.Join(names, ",")
+	}
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	return nil
+}
+
+// ConvertFrom converts the cluster from the v1alpha1 hub version.
+func (r *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Cluster)
+	if !ok {
+		return fmt.Errorf("unable to convert a cluster from %T", srcRaw)
+	}
+	in := src.DeepCopy()
+	r.ObjectMeta = in.ObjectMeta
+	r.Status = in.Status
+
+	spec := &in.Spec
+	r.Spec = ClusterSpec{
+		Annotations:                  spec.Annotations,
+		CommonLabels:                 spec.CommonLabels,
+		CommonAnnotations:            spec.CommonAnnotations,
+		Image:                        spec.Image,
+		Version:                      spec.Version,
+		ImagePullSecrets:             spec.ImagePullSecrets,
+		ImageRegistry:                spec.ImageRegistry,
+		Replicas:                     spec.Replicas,
+		PodDisruptionBudget:          spec.PodDisruptionBudget,
+		Resources:                    spec.Resources,
+		Sidecars:                     spec.Sidecars,
+		Tolerations:                  spec.Tolerations,
+		NodeSelector:                 spec.NodeSelector,
+		Storage:                      spec.Storage,
+		CloudStorage:                 spec.CloudStorage,
+		PVCRetentionPolicy:           spec.PVCRetentionPolicy,
+		Superusers:                   spec.Superusers,
+		EnableSASL:                   spec.EnableSASL,
+		AdminAPIAuthentication:       spec.AdminAPIAuthentication,
+		AdditionalConfiguration:      spec.AdditionalConfiguration,
+		DNSTrailingDotDisabled:       spec.DNSTrailingDotDisabled,
+		RestartConfig:                spec.RestartConfig,
+		LicenseRef:                   spec.LicenseRef,
+		Configurator:                 spec.Configurator,
+		ServiceAccount:               spec.ServiceAccount,
+		ValidateClusterConfiguration: spec.ValidateClusterConfiguration,
+		Networking:                   spec.Networking,
+		DiskValidation:               spec.DiskValidation,
+		MaintenanceWindows:           spec.MaintenanceWindows,
+		SharedCA:                     spec.SharedCA,
+		Bootstrap:                    spec.Bootstrap,
+		ConnectionProfile:            spec.ConnectionProfile,
+	}
+
+	cfg := &spec.Configuration
+	r.Spec.Configuration = RedpandaConfig{
+		RPCServer:            cfg.RPCServer,
+		AdminAPI:             cfg.AdminAPI,
+		PandaproxyAPI:        cfg.PandaproxyAPI,
+		SchemaRegistry:       cfg.SchemaRegistry,
+		DeveloperMode:        cfg.DeveloperMode,
+		DefaultLogLevel:      cfg.DefaultLogLevel,
+		LogLevels:            cfg.LogLevels,
+		GroupTopicPartitions: cfg.GroupTopicPartitions,
+		AutoCreateTopics:     cfg.AutoCreateTopics,
+	}
+	// The names are only kept for the listeners they were set for: if
+	// listeners were since added or removed in v1alpha1, they get their
+	// default names.
+	names := defaultListenerNames(cfg.KafkaAPI)
+	if kept, ok := r.Annotations[KafkaListenerNamesAnnotation]; ok {
+		if n := strings.Split(kept, ","); len(n) == len(names) {
+			names = n
+		}
+		delete(r.Annotations, KafkaListenerNamesAnnotation)
+		if len(r.Annotations) == 0 {
+			r.Annotations = nil
+		}
+	}
+	for i, l := range cfg.KafkaAPI {
+		r.Spec.Configuration.KafkaListeners = append(r.Spec.Configuration.KafkaListeners, KafkaListener{
+			Name:                 names[i],
+			Port:                 l.Port,
+			External:             l.External,
+			TLS:                  l.TLS,
+			AuthenticationMethod: l.AuthenticationMethod,
+		})
+	}
+	return nil
+}
+
+// defaultListenerNames returns the names of the v1alpha1 listeners without
+// kept names: the internal and external listeners are named after
+// InternalListenerName and ExternalListenerName, which are their names in the
+// Redpanda configuration, followed by their index if there are several of a
+// kind.
+func defaultListenerNames(listeners []v1alpha1.KafkaAPI) []string {
+	names := make([]string, 0, len(listeners))
+	used := make(map[string]bool)
+	for i, l := range listeners {
+		name := v1alpha1.InternalListenerName
+		if l.External.Enabled {
+			name = v1alpha1.ExternalListenerName
+		}
+		if used[name] {
+			name += "-" + strconv.Itoa(i)
+		}
+		used[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha2_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fill sets every exported field reachable from v to a non-zero value, so
+// that a field that is not converted fails the round trip.
+func fill(v reflect.Value, depth int) {
+	if depth > 8 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				fill(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), depth+1)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, depth+1)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(value, depth+1)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	}
+}
+
+func TestClusterConversionRoundTrip(t *testing.T) {
+	hub := &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	fill(reflect.ValueOf(&hub.Spec).Elem(), 0)
+	fill(reflect.ValueOf(&hub.Status).Elem(), 0)
+
+	spoke := &v1alpha2.Cluster{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	require.Len(t, spoke.Spec.Configuration.KafkaListeners, 1)
+	assert.Equal(t, v1alpha1.ExternalListenerName, spoke.Spec.Configuration.KafkaListeners[0].Name)
+
+	back := &v1alpha1.Cluster{}
+	require.NoError(t, spoke.ConvertTo(back))
+	assert.Equal(t, hub, back)
+}
+
+func TestClusterConversionListenerNames(t *testing.T) {
+	spoke := &v1alpha2.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	spoke.Spec.Configuration.KafkaListeners = []v1alpha2.KafkaListener{
+		{
+			Name:                 "internal-mtls",
+			Port:                 9092,
+			TLS:                  v1alpha1.KafkaAPITLS{Enabled: true, RequireClientAuth: true},
+			AuthenticationMethod: v1alpha1.KafkaAuthenticationMTLSIdentity,
+		},
+		{
+			Name:                 "external-sasl",
+			External:             v1alpha1.ExternalConnectivityConfig{Enabled: true},
+			AuthenticationMethod: v1alpha1.KafkaAuthenticationSASL,
+		},
+	}
+
+	hub := &v1alpha1.Cluster{}
+	require.NoError(t, spoke.ConvertTo(hub))
+	require.Len(t, hub.Spec.Configuration.KafkaAPI, 2)
+	assert.Equal(t, 9092, hub.Spec.Configuration.KafkaAPI[0].Port)
+	assert.Equal(t, v1alpha1.KafkaAuthenticationSASL, hub.Spec.Configuration.KafkaAPI[1].AuthenticationMethod)
+	assert.Equal(t, "internal-mtls,external-sasl", hub.Annotations[v1alpha2.KafkaListenerNamesAnnotation])
+
+	back := &v1alpha2.Cluster{}
+	require.NoError(t, back.ConvertFrom(hub))
+	assert.Equal(t, spoke, back)
+	assert.Nil(t, back.Annotations)
+
+	// Default names are not kept
+	spoke.Spec.Configuration.KafkaListeners[0].Name = v1alpha1.InternalListenerName
+	spoke.Spec.Configuration.KafkaListeners[1].Name = v1alpha1.ExternalListenerName
+	hub = &v1alpha1.Cluster{}
+	require.NoError(t, spoke.ConvertTo(hub))
+	assert.Nil(t, hub.Annotations)
+
+	// Listeners added in v1alpha1 since the names were kept get their
+	// default names
+	hub.Annotations = map[string]string{v1alpha2.KafkaListenerNamesAnnotation: "internal-mtls,external-sasl"}
+	hub.Spec.Configuration.KafkaAPI = append(hub.Spec.Configuration.KafkaAPI, v1alpha1.KafkaAPI{Port: 9093})
+	back = &v1alpha2.Cluster{}
+	require.NoError(t, back.ConvertFrom(hub))
+	var names []string
+	for _, l := range back.Spec.Configuration.KafkaListeners {
+		names = append(names, l.Name)
+	}
+	assert.Equal(t, []string{"kafka", "kafka-external", "kafka-2"}, names)
+	assert.Nil(t, back.Annotations)
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha2
+
+import (
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSpec defines the desired state of Cluster
+type ClusterSpec struct {
+	// If specified, Redpanda Pod annotations
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// CommonLabels are added to every resource generated for the cluster,
+	// e.g. for cost allocation. The labels set by the operator take
+	// precedence.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+	// CommonAnnotations are added to every resource generated for the
+	// cluster, e.g. for policy controllers. The annotations set by the
+	// operator or by more specific fields take precedence.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+	// Image is the fully qualified name of the Redpanda container
+	Image string `json:"image,omitempty"`
+	// Version is the Redpanda container tag
+	Version string `json:"version,omitempty"`
+	// ImagePullSecrets reference Secrets in the namespace of the cluster
+	// used to pull the images of the Redpanda pods from private registries
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageRegistry is the prefix of a registry mirror, e.g.
+	// "registry.example.com/mirror", from which the Redpanda and
+	// configurator images are pulled. The registry of the images, if any,
+	// is replaced by the prefix: "vectorized/redpanda" and
+	// "docker.io/vectorized/redpanda" are both pulled as
+	// "registry.example.com/mirror/vectorized/redpanda".
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+	// Replicas determine how big the cluster will be.
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+	// PodDisruptionBudget specifies whether PDB resource should be created for
+	// the cluster and how should it be configured. By default this is enabled
+	// and defaults to MaxUnavailable=1
+	PodDisruptionBudget *v1alpha1.PDBConfig `json:"podDisruptionBudget,omitempty"`
+	// Resources used by redpanda process running in container. Beware that
+	// there are multiple containers running in the redpanda pod and these can
+	// be enabled/disabled and configured from the `sidecars` field. These
+	// containers have separate resources settings and the amount of resources
+	// assigned to these containers will be required on the cluster on top of
+	// the resources defined here
+	Resources v1alpha1.RedpandaResourceRequirements `json:"resources"`
+	// Sidecars is list of sidecars run alongside redpanda container
+	Sidecars v1alpha1.Sidecars `json:"sidecars,omitempty"`
+	// Configuration represent redpanda specific configuration
+	Configuration RedpandaConfig `json:"configuration,omitempty"`
+	// If specified, Redpanda Pod tolerations
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// If specified, Redpanda Pod node selectors. For reference please visit
+	// https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Storage spec for cluster
+	Storage v1alpha1.StorageSpec `json:"storage,omitempty"`
+	// Cloud storage configuration for cluster
+	CloudStorage v1alpha1.CloudStorageConfig `json:"cloudStorage,omitempty"`
+	// PVCRetentionPolicy is what happens to the PersistentVolumeClaims of
+	// the brokers, for the data and the cloud storage cache, when the
+	// cluster is deleted. Retain, the default, keeps them so that a cluster
+	// created again with the same name reuses the data.
+	PVCRetentionPolicy v1alpha1.PVCRetentionPolicy `json:"pvcRetentionPolicy,omitempty"`
+	// List of superusers
+	Superusers []v1alpha1.Superuser `json:"superUsers,omitempty"`
+	// SASL enablement flag. It requires SASL on every Kafka API listener,
+	// unless the listener sets its own authenticationMethod.
+	EnableSASL bool `json:"enableSasl,omitempty"`
+	// AdminAPIAuthentication requires the clients of the admin API to
+	// authenticate with the basic auth credentials of a superuser. The
+	// operator authenticates with the credentials of its own superuser.
+	AdminAPIAuthentication *v1alpha1.AdminAPIAuthentication `json:"adminApiAuthentication,omitempty"`
+	// For configuration parameters not exposed, a map can be provided for string values.
+	// Such values are passed transparently to Redpanda. The key format is "<subsystem>.field", e.g.,
+	//
+	// additionalConfiguration:
+	//   redpanda.enable_idempotence: "true"
+	//   redpanda.default_topic_partitions: "3"
+	//   pandaproxy_client.produce_batch_size_bytes: "2097152"
+	//
+	// Notes:
+	// 1. versioning is not supported for map keys
+	// 2. key names not supported by Redpanda will lead to failure on start up
+	// 3. updating this map requires a manual restart of the Redpanda pods. Please be aware of
+	// sync period when one Redpandais POD is restarted
+	// 4. cannot have keys that conflict with existing struct fields - it leads to panic
+	//
+	// By default if Replicas is 3 or more and redpanda.default_topic_partitions is not set
+	// default webhook is setting redpanda.default_topic_partitions to 3.
+	AdditionalConfiguration map[string]string `json:"additionalConfiguration,omitempty"`
+	// DNSTrailingDotDisabled gives ability to turn off the fully-qualified
+	// DNS name.
+	// http://www.dns-sd.org/trailingdotsindomainnames.html
+	DNSTrailingDotDisabled bool `json:"dnsTrailingDotDisabled,omitempty"`
+	// RestartConfig allows to control the behavior of the cluster when restarting
+	RestartConfig *v1alpha1.RestartConfig `json:"restartConfig,omitempty"`
+	// LicenseRef references a Secret holding a Redpanda enterprise license.
+	// The operator uploads the license to the cluster and reports its
+	// expiration in the status. The license is read from the "license" key
+	// of the Secret, unless a different key is specified.
+	LicenseRef *v1alpha1.SecretKeyRef `json:"licenseRef,omitempty"`
+	// Configurator customizes the init container that generates the
+	// configuration of each Redpanda node
+	Configurator *v1alpha1.ConfiguratorConfig `json:"configurator,omitempty"`
+	// ServiceAccount configures the ServiceAccount of the Redpanda pods and
+	// the permissions granted to it
+	ServiceAccount *v1alpha1.ServiceAccountConfig `json:"serviceAccount,omitempty"`
+	// ValidateClusterConfiguration makes the operator validate the cluster
+	// configuration properties it is about to apply against the
+	// configuration schema of the running cluster. If any property is
+	// unknown or has an invalid value, nothing is applied and the errors are
+	// reported in status.configurationErrors.
+	ValidateClusterConfiguration bool `json:"validateClusterConfiguration,omitempty"`
+	// Networking configures the IP families of the cluster, e.g. to run on
+	// IPv6-only or dual-stack Kubernetes clusters
+	Networking *v1alpha1.NetworkingConfig `json:"networking,omitempty"`
+	// DiskValidation runs a disk benchmark on a volume of the storage class
+	// of the cluster before the brokers are started, and reports the
+	// results in status.diskValidation
+	DiskValidation *v1alpha1.DiskValidationConfig `json:"diskValidation,omitempty"`
+	// MaintenanceWindows restrict the disruptive operations on the cluster
+	// to the time they are open: rolling updates, whether they are caused
+	// by an upgrade, a configuration change or the restart annotation, and
+	// the decommission of brokers when downscaling. Operations outside of
+	// the windows are deferred and reported in status.pendingMaintenance.
+	// Disruptive operations are never deferred if no window is set.
+	MaintenanceWindows []v1alpha1.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// SharedCA makes a CA shared by several clusters sign the node and
+	// client certificates of all the TLS enabled APIs, instead of a CA
+	// generated for each cluster and API. Clients then trust every cluster
+	// with a single truststore. It cannot be changed after the cluster is
+	// created.
+	SharedCA *v1alpha1.SharedCAConfig `json:"sharedCA,omitempty"`
+	// Bootstrap designates the brokers that form a new cluster. When unset,
+	// the cluster is formed by its first broker alone, and every broker is
+	// a seed server of the others, which restarts all of them on every
+	// scaling.
+	Bootstrap *v1alpha1.BootstrapConfig `json:"bootstrap,omitempty"`
+	// ConnectionProfile makes the operator publish how to connect to the
+	// Kafka API listeners of the cluster in a Secret, which application pods
+	// can mount instead of looking up the Services and certificates of the
+	// cluster
+	ConnectionProfile *v1alpha1.ConnectionProfileConfig `json:"connectionProfile,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+
+// Cluster is the Schema for the clusters API. It differs from v1alpha1 in
+// the Kafka API listeners, which are named. The operator stores v1alpha1
+// clusters, and converts them to and from v1alpha2 with its webhook, so
+// v1alpha2 clusters are validated like v1alpha1 ones.
+type Cluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSpec            `json:"spec,omitempty"`
+	Status v1alpha1.ClusterStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterList contains a list of Cluster
+type ClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cluster `json:"items"`
+}
+
+// RedpandaConfig is the definition of the main configuration
+type RedpandaConfig struct {
+	RPCServer v1alpha1.SocketAddress `json:"rpcServer,omitempty"`
+	// KafkaListeners are the listeners of the Kafka API, each with its own
+	// port, TLS, authentication method, and external connectivity. As in
+	// v1alpha1, there can be at most one internal and one external listener.
+	// +listType=map
+	// +listMapKey=name
+	KafkaListeners []KafkaListener             `json:"kafkaListeners,omitempty"`
+	AdminAPI       []v1alpha1.AdminAPI         `json:"adminApi,omitempty"`
+	PandaproxyAPI  []v1alpha1.PandaproxyAPI    `json:"pandaproxyApi,omitempty"`
+	SchemaRegistry *v1alpha1.SchemaRegistryAPI `json:"schemaRegistry,omitempty"`
+	DeveloperMode  bool                        `json:"developerMode,omitempty"`
+	// DefaultLogLevel is the level of the loggers that are not listed in
+	// LogLevels. Defaults to debug in developer mode and info otherwise.
+	// +kubebuilder:validation:Enum=error;warn;info;debug;trace
+	DefaultLogLevel string `json:"defaultLogLevel,omitempty"`
+	// LogLevels maps the names of loggers to the level they start with,
+	// overriding DefaultLogLevel
+	LogLevels map[string]string `json:"logLevels,omitempty"`
+	// Number of partitions in the internal group membership topic
+	GroupTopicPartitions int `json:"groupTopicPartitions,omitempty"`
+	// Enable auto-creation of topics. Reference https://kafka.apache.org/documentation/#brokerconfigs_auto.create.topics.enable
+	AutoCreateTopics bool `json:"autoCreateTopics,omitempty"`
+}
+
+// KafkaListener configures a listener of the Kafka API
+type KafkaListener struct {
+	// Name of the listener, unique among the Kafka API listeners. It only
+	// identifies the listener in this API version: the operator keeps it in
+	// the redpanda.vectorized.io/kafka-listener-names annotation, and the
+	// listeners in the Redpanda configuration are still named kafka and
+	// kafka-external.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	Port int    `json:"port,omitempty"`
+	// External enables user to expose Redpanda
+	// nodes outside of a Kubernetes cluster. For more
+	// information please go to ExternalConnectivityConfig
+	External v1alpha1.ExternalConnectivityConfig `json:"external,omitempty"`
+	// Configuration of TLS for Kafka API
+	TLS v1alpha1.KafkaAPITLS `json:"tls,omitempty"`
+	// AuthenticationMethod is how clients of the listener authenticate,
+	// either none, sasl, or mtls_identity to authenticate clients with the
+	// identity of their TLS certificate, which requires TLS with
+	// requireClientAuth. If not set, the listener uses sasl if enableSasl is
+	// set and none otherwise.
+	AuthenticationMethod v1alpha1.KafkaAuthenticationMethod `json:"authenticationMethod,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
+}
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
 	"path"
 	"strconv"
 	"strings"
@@ -43,9 +44,14 @@ const (
 	externalConnectivityAddressTypeEnvVar                = "EXTERNAL_CONNECTIVITY_ADDRESS_TYPE"
 	externalConnectivityKafkaEndpointTemplateEnvVar      = "EXTERNAL_CONNECTIVITY_KAFKA_ENDPOINT_TEMPLATE"
 	externalConnectivityPandaProxyEndpointTemplateEnvVar = "EXTERNAL_CONNECTIVITY_PANDA_PROXY_ENDPOINT_TEMPLATE"
+	externalConnectivityAdvertisedPortBaseEnvVar         = "EXTERNAL_CONNECTIVITY_ADVERTISED_PORT_BASE"
 	hostIPEnvVar                                         = "HOST_IP_ADDRESS"
 	hostPortEnvVar                                       = "HOST_PORT"
 	proxyHostPortEnvVar                                  = "PROXY_HOST_PORT"
+	preStartHookScriptEnvVar                             = "PRE_START_HOOK_SCRIPT"
+	preStartHookPathEnvVar                               = "PRE_START_HOOK_PATH"
+	postStartHookScriptEnvVar                            = "POST_START_HOOK_SCRIPT"
+	postStartHookPathEnvVar                              = "POST_START_HOOK_PATH"
 )
 
 type brokerID int
@@ -63,8 +69,11 @@ type configuratorConfig struct {
 	externalConnectivityPandaProxyEndpointTemplate string
 	redpandaRPCPort                                int
 	hostPort                                       int
+	advertisedPortBase                             int
 	proxyHostPort                                  int
 	hostIP                                         string
+	preStartHook                                   hook
+	postStartHook                                  hook
 }
 
 func (c *configuratorConfig) String() string {
@@ -79,6 +88,7 @@ func (c *configuratorConfig) String() string {
 		"externalConnectivityAddressType: %s\n"+
 		"redpandaRPCPort: %d\n"+
 		"hostPort: %d\n"+
+		"advertisedPortBase: %d\n"+
 		"proxyHostPort: %d\n",
 		c.hostName,
 		c.svcFQDN,
@@ -90,6 +100,7 @@ func (c *configuratorConfig) String() string {
 		c.externalConnectivityAddressType,
 		c.redpandaRPCPort,
 		c.hostPort,
+		c.advertisedPortBase,
 		c.proxyHostPort)
 }
 
@@ -105,6 +116,10 @@ func main() {
 
 	log.Print(c.String())
 
+	if err := c.preStartHook.run("pre-start"); err != nil {
+		log.Fatalf("%s", err)
+	}
+
 	fs := afero.NewOsFs()
 	p := config.Params{ConfigPath: path.Join(c.configSourceDir, "redpanda.yaml")}
 	cfg, err := p.Load(fs)
@@ -156,6 +171,42 @@ func main() {
 	}
 
 	log.Printf("Configuration saved to: %s", c.configDestination)
+
+	if err := c.postStartHook.run("post-start"); err != nil {
+		log.Fatalf("%s", err)
+	}
+}
+
+// hook is a shell script run by the configurator, either inline or from a
+// file mounted from a ConfigMap.
+type hook struct {
+	script string
+	path   string
+}
+
+func (h hook) run(name string) error {
+	var cmd *exec.Cmd
+	switch {
+	case h.script != "":
+		cmd = exec.Command("/bin/sh", "-c", h.script)
+	case h.path != "":
+		if _, err := os.Stat(h.path); errors.Is(err, os.ErrNotExist) {
+			// The ConfigMap of the hook is optional and missing.
+			log.Printf("Skipping the %s hook, %s does not exist", name, h.path)
+			return nil
+		}
+		cmd = exec.Command("/bin/sh", h.path)
+	default:
+		return nil
+	}
+	log.Printf("Running the %s hook", name)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("the %s hook failed: %w", name, err)
+	}
+	log.Printf("The %s hook completed", name)
+	return nil
 }
 
 var errInternalPortMissing = errors.New("port configration is missing internal port")
@@ -211,6 +262,8 @@ func registerAdvertisedKafkaAPI(
 		return nil
 	}
 
+	externalPort := advertisedKafkaAPIPort(c, index)
+
 	if len(c.subdomain) > 0 {
 		data := utils.NewEndpointTemplateData(int(index), c.hostIP)
 		ep, err := utils.ComputeEndpoint(c.externalConnectivityKafkaEndpointTemplate, data)
@@ -220,7 +273,7 @@ func registerAdvertisedKafkaAPI(
 
 		cfg.Redpanda.AdvertisedKafkaAPI = append(cfg.Redpanda.AdvertisedKafkaAPI, config.NamedSocketAddress{
 			Address: fmt.Sprintf("%s.%s", ep, c.subdomain),
-			Port:    c.hostPort,
+			Port:    externalPort,
 			Name:    "kafka-external",
 		})
 		return nil
@@ -233,13 +286,24 @@ func registerAdvertisedKafkaAPI(
 
 	cfg.Redpanda.AdvertisedKafkaAPI = append(cfg.Redpanda.AdvertisedKafkaAPI, config.NamedSocketAddress{
 		Address: networking.GetPreferredAddress(node, c.externalConnectivityAddressType),
-		Port:    c.hostPort,
+		Port:    externalPort,
 		Name:    "kafka-external",
 	})
 
 	return nil
 }
 
+// advertisedKafkaAPIPort returns the external Kafka API port advertised by the
+// broker. By default all brokers advertise the node port they are exposed on,
+// unless an advertised port base is provided, in which case each broker
+// advertises its own port of the range starting at the base.
+func advertisedKafkaAPIPort(c *configuratorConfig, index brokerID) int {
+	if c.advertisedPortBase == 0 {
+		return c.hostPort
+	}
+	return c.advertisedPortBase + int(index)
+}
+
 func registerAdvertisedPandaproxyAPI(
 	c *configuratorConfig, cfg *config.Config, index brokerID, proxyAPIPort int,
 ) error {
@@ -394,6 +458,25 @@ func checkEnvVars() (configuratorConfig, error) {
 		result = multierror.Append(result, fmt.Errorf("unable to convert host port from string to int: %w", err))
 	}
 
+	// Providing the advertised port base is optional
+	advertisedPortBase, exist := os.LookupEnv(externalConnectivityAdvertisedPortBaseEnvVar)
+	if exist && advertisedPortBase != "" {
+		c.advertisedPortBase, err = strconv.Atoi(advertisedPortBase)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("unable to convert advertised port base from string to int: %w", err))
+		}
+	}
+
+	// Providing hooks is optional
+	c.preStartHook = hook{
+		script: os.Getenv(preStartHookScriptEnvVar),
+		path:   os.Getenv(preStartHookPathEnvVar),
+	}
+	c.postStartHook = hook{
+		script: os.Getenv(postStartHookScriptEnvVar),
+		path:   os.Getenv(postStartHookPathEnvVar),
+	}
+
 	// Providing proxy host port is optional
 	proxyHostPort, exist := os.LookupEnv(proxyHostPortEnvVar)
 	if exist && proxyHostPort != "" {
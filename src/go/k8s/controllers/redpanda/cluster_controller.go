@@ -14,6 +14,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/go-logr/logr"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/networking"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
@@ -33,11 +35,15 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
 var (
@@ -54,19 +60,41 @@ type ClusterReconciler struct {
 	clusterDomain            string
 	Scheme                   *runtime.Scheme
 	AdminAPIClientFactory    adminutils.AdminAPIClientFactory
+	MaxReplicationFactor     resources.MaxReplicationFactorFunc
 	DecommissionWaitInterval time.Duration
+	EventRecorder            record.EventRecorder
+	// AuditLogger, if set, receives a structured entry for each mutating
+	// operation on a cluster, on top of the audit events
+	AuditLogger logr.Logger
+	// PodEvictor, if set, evicts the pods restarted by rolling updates, so
+	// that their PodDisruptionBudget is respected; they are deleted otherwise
+	PodEvictor resources.PodEvictor
+	// Throttle, if set, defers the reconciles of healthy clusters
+	Throttle *ReconcileThrottle
+	// MaxConcurrentReconciles is the number of clusters reconciled in
+	// parallel, 1 if unset
+	MaxConcurrentReconciles int
+	// Capabilities of the Kubernetes cluster, the latest APIs are assumed
+	// if unset
+	Capabilities    *capabilities.Capabilities
+	clusterSelector k8slabels.Selector
 }
 
 //+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=clusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=clusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;delete
-//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;
+//+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates;clusterissuers,verbs=create;get;list;watch;patch;delete;update;
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=create;get;list;watch;patch;delete;update;
@@ -97,6 +125,7 @@ func (r *ClusterReconciler) Reconcile(
 		// requeue (we'll need to wait for a new notification), and we can get them
 		// on deleted requests.
 		if apierrors.IsNotFound(err) {
+			r.Throttle.Forget(req.NamespacedName)
 			if removeError := crb.RemoveSubject(ctx, req.NamespacedName); removeError != nil {
 				return ctrl.Result{}, fmt.Errorf("unable to remove subject in ClusterroleBinding: %w", removeError)
 			}
@@ -105,84 +134,46 @@ func (r *ClusterReconciler) Reconcile(
 		return ctrl.Result{}, fmt.Errorf("unable to retrieve Cluster resource: %w", err)
 	}
 
-	if !isRedpandaClusterManaged(log, &redpandaCluster) {
+	if !isClusterSelected(log, r.clusterSelector, &redpandaCluster) {
 		return ctrl.Result{}, nil
 	}
 
-	redpandaPorts := networking.NewRedpandaPorts(&redpandaCluster)
-	nodeports := collectNodePorts(redpandaPorts)
-	headlessPorts := collectHeadlessPorts(redpandaPorts)
-	lbPorts := collectLBPorts(redpandaPorts)
-	clusterPorts := collectClusterPorts(redpandaPorts, &redpandaCluster)
+	if redpandaCluster.DeletionTimestamp != nil {
+		r.Throttle.Forget(req.NamespacedName)
+		return r.reconcileDeletion(ctx, &redpandaCluster, log)
+	}
 
-	headlessSvc := resources.NewHeadlessService(r.Client, &redpandaCluster, r.Scheme, headlessPorts, log)
-	nodeportSvc := resources.NewNodePortService(r.Client, &redpandaCluster, r.Scheme, nodeports, log)
-	bootstrapSvc := resources.NewLoadBalancerService(r.Client, &redpandaCluster, r.Scheme, lbPorts, true, log)
+	if !isRedpandaClusterManaged(log, &redpandaCluster) {
+		return ctrl.Result{}, nil
+	}
 
-	clusterSvc := resources.NewClusterService(r.Client, &redpandaCluster, r.Scheme, clusterPorts, log)
-	subdomain := ""
-	proxyAPIExternal := redpandaCluster.PandaproxyAPIExternal()
-	if proxyAPIExternal != nil {
-		subdomain = proxyAPIExternal.External.Subdomain
+	if isReconcilePaused(log, &redpandaCluster) {
+		return ctrl.Result{}, nil
 	}
-	ingress := resources.NewIngress(r.Client,
-		&redpandaCluster,
-		r.Scheme,
-		subdomain,
-		clusterSvc.Key().Name,
-		resources.PandaproxyPortExternalName,
-		log).WithAnnotations(map[string]string{resources.SSLPassthroughAnnotation: "true"})
 
-	var proxySu *resources.SuperUsersResource
-	var proxySuKey types.NamespacedName
-	if redpandaCluster.Spec.EnableSASL && redpandaCluster.PandaproxyAPIInternal() != nil {
-		proxySu = resources.NewSuperUsers(r.Client, &redpandaCluster, r.Scheme, resources.ScramPandaproxyUsername, resources.PandaProxySuffix, log)
-		proxySuKey = proxySu.Key()
+	if err := r.ensureCleanupFinalizer(ctx, &redpandaCluster); err != nil {
+		return ctrl.Result{}, err
 	}
-	var schemaRegistrySu *resources.SuperUsersResource
-	var schemaRegistrySuKey types.NamespacedName
-	if redpandaCluster.Spec.EnableSASL && redpandaCluster.Spec.Configuration.SchemaRegistry != nil {
-		schemaRegistrySu = resources.NewSuperUsers(r.Client, &redpandaCluster, r.Scheme, resources.ScramSchemaRegistryUsername, resources.SchemaRegistrySuffix, log)
-		schemaRegistrySuKey = schemaRegistrySu.Key()
+
+	if delay := r.Throttle.Delay(&redpandaCluster); delay > 0 {
+		log.Info("Deferring the reconcile of the healthy cluster", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
 	}
-	pki := certmanager.NewPki(r.Client, &redpandaCluster, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), clusterSvc.ServiceFQDN(r.clusterDomain), r.Scheme, log)
-	sa := resources.NewServiceAccount(r.Client, &redpandaCluster, r.Scheme, log)
-	configMapResource := resources.NewConfigMap(r.Client, &redpandaCluster, r.Scheme, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), proxySuKey, schemaRegistrySuKey, log)
 
-	sts := resources.NewStatefulSet(
-		r.Client,
-		&redpandaCluster,
-		r.Scheme,
-		headlessSvc.HeadlessServiceFQDN(r.clusterDomain),
-		headlessSvc.Key().Name,
-		nodeportSvc.Key(),
-		pki.StatefulSetVolumeProvider(),
-		pki.AdminAPIConfigProvider(),
-		sa.Key().Name,
-		r.configuratorSettings,
-		configMapResource.GetNodeConfigHash,
-		r.AdminAPIClientFactory,
-		r.DecommissionWaitInterval,
-		log)
+	if err := r.reconcileRestartAnnotation(ctx, &redpandaCluster, log); err != nil {
+		return ctrl.Result{}, err
+	}
 
-	toApply := []resources.Reconciler{
-		headlessSvc,
-		clusterSvc,
-		nodeportSvc,
-		ingress,
-		bootstrapSvc,
-		proxySu,
-		schemaRegistrySu,
-		configMapResource,
-		pki,
-		sa,
-		resources.NewClusterRole(r.Client, &redpandaCluster, r.Scheme, log),
-		crb,
-		resources.NewPDB(r.Client, &redpandaCluster, r.Scheme, log),
-		sts,
+	if err := r.reconcileCompatibility(ctx, &redpandaCluster); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	for _, res := range toApply {
+	cr := r.newClusterResources(&redpandaCluster, crb, log)
+	headlessSvc, clusterSvc, nodeportSvc, bootstrapSvc := cr.headlessSvc, cr.clusterSvc, cr.nodeportSvc, cr.bootstrapSvc
+	proxySu, schemaRegistrySu := cr.proxySu, cr.schemaRegistrySu
+	pki, configMapResource, sts := cr.pki, cr.configMap, cr.sts
+
+	for _, res := range cr.toApply {
 		err := res.Ensure(ctx)
 
 		var e *resources.RequeueAfterError
@@ -204,6 +195,11 @@ func (r *ClusterReconciler) Reconcile(
 	if schemaRegistrySu != nil {
 		secrets = append(secrets, schemaRegistrySu.Key())
 	}
+	if name := redpandaCluster.AdminAPICredentialsSecretName(); name != "" {
+		// The user of the operator is bootstrapped when the cluster is formed,
+		// but clusters formed before requiring authentication lack it
+		secrets = append(secrets, types.NamespacedName{Name: name, Namespace: redpandaCluster.Namespace})
+	}
 
 	err := r.setInitialSuperUserPassword(ctx, &redpandaCluster, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), pki.AdminAPIConfigProvider(), secrets)
 
@@ -250,7 +246,190 @@ func (r *ClusterReconciler) Reconcile(
 		log.Info(requeueErr.Error())
 		return ctrl.Result{RequeueAfter: requeueErr.RequeueAfter}, nil
 	}
-	return ctrl.Result{}, err
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.reconcileLicense(ctx, &redpandaCluster, pki, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), log)
+	if errors.As(err, &requeueErr) {
+		log.Info(requeueErr.Error())
+		return ctrl.Result{RequeueAfter: requeueErr.RequeueAfter}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter, err := r.reconcileDebugLogLevels(ctx, &redpandaCluster, pki, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), log)
+	if errors.As(err, &requeueErr) {
+		log.Info(requeueErr.Error())
+		return ctrl.Result{RequeueAfter: requeueErr.RequeueAfter}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.reconcileRebalanceAnnotation(ctx, &redpandaCluster, pki, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), log)
+	if errors.As(err, &requeueErr) {
+		log.Info(requeueErr.Error())
+		return ctrl.Result{RequeueAfter: requeueErr.RequeueAfter}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.reconcileCloudStorage(ctx, &redpandaCluster, pki, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.reconcileEndpoints(ctx, &redpandaCluster, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if redpandaCluster.Spec.LicenseRef != nil && (requeueAfter == 0 || licenseRecheckInterval < requeueAfter) {
+		requeueAfter = licenseRecheckInterval
+	}
+	if redpandaCluster.Spec.CloudStorage.Enabled && (requeueAfter == 0 || cloudStorageRecheckInterval < requeueAfter) {
+		requeueAfter = cloudStorageRecheckInterval
+	}
+	if next := nextEndpointProbe(&redpandaCluster.Status); next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+		requeueAfter = next
+	}
+	if pending := redpandaCluster.Status.PendingMaintenance; pending != nil {
+		// Resume the deferred operations when the next window opens
+		untilWindow := time.Until(pending.NextWindow.Time)
+		if untilWindow < time.Second {
+			untilWindow = time.Second
+		}
+		if requeueAfter == 0 || untilWindow < requeueAfter {
+			requeueAfter = untilWindow
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// clusterResources are the resources managed for a cluster, see
+// newClusterResources.
+type clusterResources struct {
+	headlessSvc      *resources.HeadlessServiceResource
+	clusterSvc       *resources.ClusterServiceResource
+	nodeportSvc      *resources.NodePortServiceResource
+	bootstrapSvc     *resources.LoadBalancerServiceResource
+	proxySu          *resources.SuperUsersResource
+	schemaRegistrySu *resources.SuperUsersResource
+	pki              *certmanager.PkiReconciler
+	configMap        *resources.ConfigMapResource
+	sts              *resources.StatefulSetResource
+
+	// toApply are the resources in the order they are ensured.
+	toApply []resources.Reconciler
+}
+
+// newClusterResources returns the resources managed for the cluster.
+func (r *ClusterReconciler) newClusterResources(
+	cluster *redpandav1alpha1.Cluster,
+	crb *resources.ClusterRoleBindingResource,
+	log logr.Logger,
+) *clusterResources {
+	redpandaPorts := networking.NewRedpandaPorts(cluster)
+	nodeports := collectNodePorts(redpandaPorts)
+	headlessPorts := collectHeadlessPorts(redpandaPorts)
+	lbPorts := collectLBPorts(redpandaPorts)
+	clusterPorts := collectClusterPorts(redpandaPorts, cluster)
+
+	headlessSvc := resources.NewHeadlessService(r.Client, cluster, r.Scheme, headlessPorts, log)
+	nodeportSvc := resources.NewNodePortService(r.Client, cluster, r.Scheme, nodeports, log)
+	bootstrapSvc := resources.NewLoadBalancerService(r.Client, cluster, r.Scheme, lbPorts, true, log)
+
+	clusterSvc := resources.NewClusterService(r.Client, cluster, r.Scheme, clusterPorts, log)
+	subdomain := ""
+	var proxyExternalDNS *redpandav1alpha1.ExternalDNSConfig
+	proxyAPIExternal := cluster.PandaproxyAPIExternal()
+	if proxyAPIExternal != nil {
+		subdomain = proxyAPIExternal.External.Subdomain
+		proxyExternalDNS = proxyAPIExternal.External.ExternalDNS
+	}
+	ingress := resources.NewIngress(r.Client,
+		cluster,
+		r.Scheme,
+		subdomain,
+		clusterSvc.Key().Name,
+		resources.PandaproxyPortExternalName,
+		log).WithAnnotations(map[string]string{resources.SSLPassthroughAnnotation: "true"}).
+		WithExternalDNS(proxyExternalDNS).
+		WithCapabilities(r.Capabilities)
+
+	var proxySu *resources.SuperUsersResource
+	var proxySuKey types.NamespacedName
+	if cluster.IsSASLOnInternalEnabled() && cluster.PandaproxyAPIInternal() != nil {
+		proxySu = resources.NewSuperUsers(r.Client, cluster, r.Scheme, resources.ScramPandaproxyUsername, resources.PandaProxySuffix, log)
+		proxySuKey = proxySu.Key()
+	}
+	var schemaRegistrySu *resources.SuperUsersResource
+	var schemaRegistrySuKey types.NamespacedName
+	if cluster.IsSASLOnInternalEnabled() && cluster.Spec.Configuration.SchemaRegistry != nil {
+		schemaRegistrySu = resources.NewSuperUsers(r.Client, cluster, r.Scheme, resources.ScramSchemaRegistryUsername, resources.SchemaRegistrySuffix, log)
+		schemaRegistrySuKey = schemaRegistrySu.Key()
+	}
+	pki := certmanager.NewPki(r.Client, cluster, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), clusterSvc.ServiceFQDN(r.clusterDomain), r.Scheme, log).
+		WithAuditor(r.auditor())
+	sa := resources.NewServiceAccount(r.Client, cluster, r.Scheme, log)
+	configMapResource := resources.NewConfigMap(r.Client, cluster, r.Scheme, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), proxySuKey, schemaRegistrySuKey, log)
+
+	sts := resources.NewStatefulSet(
+		r.Client,
+		cluster,
+		r.Scheme,
+		headlessSvc.HeadlessServiceFQDN(r.clusterDomain),
+		headlessSvc.Key().Name,
+		nodeportSvc.Key(),
+		pki.StatefulSetVolumeProvider(),
+		pki.AdminAPIConfigProvider(),
+		sa.Key().Name,
+		r.configuratorSettings,
+		configMapResource.GetNodeConfigHash,
+		r.AdminAPIClientFactory,
+		r.MaxReplicationFactor,
+		r.DecommissionWaitInterval,
+		log).WithAuditor(r.auditor()).WithPodEvictor(r.PodEvictor)
+
+	toApply := []resources.Reconciler{
+		headlessSvc,
+		clusterSvc,
+		nodeportSvc,
+		ingress,
+		bootstrapSvc,
+		proxySu,
+		schemaRegistrySu,
+		configMapResource,
+		pki,
+		sa,
+		resources.NewClusterRole(r.Client, cluster, r.Scheme, log),
+		crb,
+		resources.NewRole(r.Client, cluster, r.Scheme, log),
+		resources.NewRoleBinding(r.Client, cluster, r.Scheme, log),
+		resources.NewPDB(r.Client, cluster, r.Scheme, log),
+		resources.NewDiskValidationJob(r.Client, cluster, r.Scheme, r.EventRecorder, log),
+		sts,
+		resources.NewConnectionProfile(r.Client, cluster, r.Scheme, pki.KafkaAPICAProvider(), log),
+	}
+
+	return &clusterResources{
+		headlessSvc:      headlessSvc,
+		clusterSvc:       clusterSvc,
+		nodeportSvc:      nodeportSvc,
+		bootstrapSvc:     bootstrapSvc,
+		proxySu:          proxySu,
+		schemaRegistrySu: schemaRegistrySu,
+		pki:              pki,
+		configMap:        configMapResource,
+		sts:              sts,
+		toApply:          toApply,
+	}
+}
+
+// auditor returns the Auditor recording the mutating operations on clusters
+func (r *ClusterReconciler) auditor() *resources.Auditor {
+	return resources.NewAuditor(r.EventRecorder, r.AuditLogger)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -260,9 +439,10 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&redpandav1alpha1.Cluster{}).
+		For(&redpandav1alpha1.Cluster{}, builder.WithPredicates(clusterSelectorPredicate(r.clusterSelector))).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
@@ -383,6 +563,15 @@ func (r *ClusterReconciler) WithClusterDomain(
 	return r
 }
 
+// WithClusterSelector restricts the reconciled clusters to the ones matching
+// the label selector
+func (r *ClusterReconciler) WithClusterSelector(
+	selector k8slabels.Selector,
+) *ClusterReconciler {
+	r.clusterSelector = selector
+	return r
+}
+
 //nolint:funlen,gocyclo // External nodes list should be refactored
 func (r *ClusterReconciler) createExternalNodesList(
 	ctx context.Context,
@@ -435,18 +624,24 @@ func (r *ClusterReconciler) createExternalNodesList(
 			}
 		}
 
-		if externalKafkaListener != nil && len(externalKafkaListener.External.Subdomain) > 0 {
-			address, err := subdomainAddress(externalKafkaListener.External.EndpointTemplate, &pod, externalKafkaListener.External.Subdomain, getNodePort(&nodePortSvc, resources.ExternalListenerName))
+		if externalKafkaListener != nil {
+			kafkaPort, err := advertisedKafkaPort(externalKafkaListener.External, &pod, getNodePort(&nodePortSvc, resources.ExternalListenerName))
 			if err != nil {
 				return nil, err
 			}
-			result.External = append(result.External, address)
-		} else if externalKafkaListener != nil {
-			result.External = append(result.External,
-				fmt.Sprintf("%s:%d",
-					networking.GetPreferredAddress(&node, corev1.NodeAddressType(externalKafkaListener.External.PreferredAddressType)),
-					getNodePort(&nodePortSvc, resources.ExternalListenerName),
-				))
+			if len(externalKafkaListener.External.Subdomain) > 0 {
+				address, err := subdomainAddress(externalKafkaListener.External.EndpointTemplate, &pod, externalKafkaListener.External.Subdomain, kafkaPort)
+				if err != nil {
+					return nil, err
+				}
+				result.External = append(result.External, address)
+			} else {
+				result.External = append(result.External,
+					net.JoinHostPort(
+						networking.GetPreferredAddress(&node, corev1.NodeAddressType(externalKafkaListener.External.PreferredAddressType)),
+						strconv.Itoa(int(kafkaPort)),
+					))
+			}
 		}
 
 		if externalAdminListener != nil && len(externalAdminListener.External.Subdomain) > 0 {
@@ -457,9 +652,9 @@ func (r *ClusterReconciler) createExternalNodesList(
 			result.ExternalAdmin = append(result.ExternalAdmin, address)
 		} else if externalAdminListener != nil {
 			result.ExternalAdmin = append(result.ExternalAdmin,
-				fmt.Sprintf("%s:%d",
+				net.JoinHostPort(
 					getExternalIP(&node),
-					getNodePort(&nodePortSvc, resources.AdminPortExternalName),
+					strconv.Itoa(int(getNodePort(&nodePortSvc, resources.AdminPortExternalName))),
 				))
 		}
 
@@ -471,17 +666,17 @@ func (r *ClusterReconciler) createExternalNodesList(
 			result.ExternalPandaproxy = append(result.ExternalPandaproxy, address)
 		} else if externalProxyListener != nil {
 			result.ExternalPandaproxy = append(result.ExternalPandaproxy,
-				fmt.Sprintf("%s:%d",
+				net.JoinHostPort(
 					getExternalIP(&node),
-					getNodePort(&nodePortSvc, resources.PandaproxyPortExternalName),
+					strconv.Itoa(int(getNodePort(&nodePortSvc, resources.PandaproxyPortExternalName))),
 				))
 		}
 
 		if schemaRegistryConf != nil && schemaRegistryConf.External != nil && needExternalIP(*schemaRegistryConf.External) {
 			result.SchemaRegistry.ExternalNodeIPs = append(result.SchemaRegistry.ExternalNodeIPs,
-				fmt.Sprintf("%s:%d",
+				net.JoinHostPort(
 					getExternalIP(&node),
-					getNodePort(&nodePortSvc, resources.SchemaRegistryPortName),
+					strconv.Itoa(int(getNodePort(&nodePortSvc, resources.SchemaRegistryPortName))),
 				))
 		}
 	}
@@ -552,6 +747,24 @@ func needExternalIP(external redpandav1alpha1.ExternalConnectivityConfig) bool {
 	return external.Subdomain == ""
 }
 
+// advertisedKafkaPort returns the external Kafka API port advertised by the
+// broker running in the pod, which is the node port unless an advertised port
+// base is configured.
+func advertisedKafkaPort(
+	external redpandav1alpha1.ExternalConnectivityConfig,
+	pod *corev1.Pod,
+	nodePort int32,
+) (int32, error) {
+	if external.AdvertisedPortBase == 0 {
+		return nodePort, nil
+	}
+	index, err := strconv.Atoi(pod.Name[len(pod.GenerateName):])
+	if err != nil {
+		return 0, fmt.Errorf("could not parse node ID from pod name %s: %w", pod.Name, err)
+	}
+	return int32(external.AdvertisedPortBase + index), nil
+}
+
 func subdomainAddress(
 	tmpl string, pod *corev1.Pod, subdomain string, port int32,
 ) (string, error) {
@@ -0,0 +1,207 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	cmapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ClusterCleanupFinalizer is the finalizer of the Cluster that holds its
+// deletion until the resources that are not garbage collected through their
+// owner references are deleted
+const ClusterCleanupFinalizer = "clusters.redpanda.vectorized.io/cleanup"
+
+// ensureCleanupFinalizer adds the cleanup finalizer to the cluster
+func (r *ClusterReconciler) ensureCleanupFinalizer(
+	ctx context.Context, cluster *redpandav1alpha1.Cluster,
+) error {
+	if controllerutil.ContainsFinalizer(cluster, ClusterCleanupFinalizer) {
+		return nil
+	}
+	controllerutil.AddFinalizer(cluster, ClusterCleanupFinalizer)
+	if err := r.Update(ctx, cluster); err != nil {
+		return fmt.Errorf("unable to add the cleanup finalizer: %w", err)
+	}
+	return nil
+}
+
+// reconcileDeletion cleans up after a deleted cluster, then removes the
+// cleanup finalizer so that the deletion completes. The cleanup of a cluster
+// that is not managed by the operator is skipped, its finalizer is removed
+// so that it does not block the deletion.
+func (r *ClusterReconciler) reconcileDeletion(
+	ctx context.Context, cluster *redpandav1alpha1.Cluster, log logr.Logger,
+) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(cluster, ClusterCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if isRedpandaClusterManaged(log, cluster) {
+		if isReconcilePaused(log, cluster) {
+			return ctrl.Result{}, nil
+		}
+		err := r.cleanup(ctx, cluster, log)
+		var requeueErr *resources.RequeueAfterError
+		if errors.As(err, &requeueErr) {
+			log.Info(requeueErr.Error())
+			return ctrl.Result{RequeueAfter: requeueErr.RequeueAfter}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(cluster, ClusterCleanupFinalizer)
+	if err := r.Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to remove the cleanup finalizer: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// cleanup deletes the resources of a deleted cluster in order, waiting for
+// each step to complete before the next:
+//
+//  1. the LoadBalancer services, so that the cloud load balancers are
+//     released before anything they depend on is gone
+//  2. the StatefulSet and its pods, so that the volumes are no longer in use
+//  3. the PersistentVolumeClaims, if the retention policy is Delete
+//  4. the certificates, including those issued in the namespace of another
+//     issuer, and the secrets cert-manager issued for them, which are not
+//     owned by the cluster
+//
+// The other resources are owned by the cluster and garbage collected once
+// its deletion completes.
+func (r *ClusterReconciler) cleanup(
+	ctx context.Context, cluster *redpandav1alpha1.Cluster, log logr.Logger,
+) error {
+	selector := labels.ForCluster(cluster).AsClientSelector()
+	inNamespace := &client.ListOptions{LabelSelector: selector, Namespace: cluster.Namespace}
+
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, inNamespace); err != nil {
+		return fmt.Errorf("unable to list services: %w", err)
+	}
+	var pendingServices []string
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || !metav1.IsControlledBy(svc, cluster) {
+			continue
+		}
+		pendingServices = append(pendingServices, svc.Name)
+		if svc.DeletionTimestamp == nil {
+			if err := r.Delete(ctx, svc); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("unable to delete service %s: %w", svc.Name, err)
+			}
+		}
+	}
+	if len(pendingServices) > 0 {
+		return &resources.RequeueAfterError{
+			RequeueAfter: resources.RequeueDuration,
+			Msg:          fmt.Sprintf("wait for the load balancer services %s to be deleted", strings.Join(pendingServices, ", ")),
+		}
+	}
+
+	var sts appsv1.StatefulSet
+	err := r.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, &sts)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("unable to get statefulset: %w", err)
+	case sts.DeletionTimestamp == nil && metav1.IsControlledBy(&sts, cluster):
+		propagation := metav1.DeletePropagationForeground
+		if err := r.Delete(ctx, &sts, &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete statefulset: %w", err)
+		}
+	}
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, inNamespace); err != nil {
+		return fmt.Errorf("unable to list pods: %w", err)
+	}
+	if len(pods.Items) > 0 {
+		return &resources.RequeueAfterError{
+			RequeueAfter: resources.RequeueDuration,
+			Msg:          fmt.Sprintf("wait for the %d pods of the cluster to terminate", len(pods.Items)),
+		}
+	}
+
+	if cluster.Spec.PVCRetentionPolicy == redpandav1alpha1.PVCRetentionPolicyDelete {
+		var pvcs corev1.PersistentVolumeClaimList
+		if err := r.List(ctx, &pvcs, inNamespace); err != nil {
+			return fmt.Errorf("unable to list persistent volume claims: %w", err)
+		}
+		for i := range pvcs.Items {
+			pvc := &pvcs.Items[i]
+			if pvc.DeletionTimestamp != nil {
+				continue
+			}
+			if err := r.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("unable to delete persistent volume claim %s: %w", pvc.Name, err)
+			}
+			r.auditor().Record(cluster, resources.AuditReasonPersistentVolumeClaimDeleted, pvc.Name,
+				"PersistentVolumeClaim %s deleted with the cluster", pvc.Name)
+		}
+	}
+
+	var certs cmapiv1.CertificateList
+	err = r.List(ctx, &certs, &client.ListOptions{LabelSelector: selector})
+	if meta.IsNoMatchError(err) {
+		// cert-manager is not installed, so there is no certificate
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to list certificates: %w", err)
+	}
+	for i := range certs.Items {
+		cert := &certs.Items[i]
+		if !isClusterCertificate(cluster, cert) {
+			continue
+		}
+		if err := r.Delete(ctx, cert); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete certificate %s/%s: %w", cert.Namespace, cert.Name, err)
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: cert.Spec.SecretName, Namespace: cert.Namespace}}
+		if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete certificate secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		log.Info("Deleted certificate and its secret", "certificate", client.ObjectKeyFromObject(cert).String())
+	}
+	return nil
+}
+
+// isClusterCertificate returns whether the certificate was created for the
+// cluster. Certificates in the namespace of the cluster are owned by it,
+// those issued in the namespace of another issuer cannot be, and are named
+// after the namespace of the cluster and the cluster.
+func isClusterCertificate(
+	cluster *redpandav1alpha1.Cluster, cert *cmapiv1.Certificate,
+) bool {
+	if cert.Namespace == cluster.Namespace {
+		return metav1.IsControlledBy(cert, cluster)
+	}
+	return strings.HasPrefix(cert.Name, cluster.Namespace+"-"+cluster.Name+"-")
+}
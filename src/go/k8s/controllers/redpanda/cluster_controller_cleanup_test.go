@@ -0,0 +1,91 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("RedPandaCluster cleanup controller", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	// createPVC creates a claim like the ones of the volume claim template
+	// of the statefulset, which is not run by the test environment
+	createPVC := func(cluster *v1alpha1.Cluster) types.NamespacedName {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "datadir-" + cluster.Name + "-0",
+				Namespace: cluster.Namespace,
+				Labels:    labels.ForCluster(cluster),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), pvc)).Should(Succeed())
+		return types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}
+	}
+
+	deleteCluster := func(key types.NamespacedName) {
+		var cluster v1alpha1.Cluster
+		Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+			return cluster.Finalizers
+		}), timeout, interval).Should(ContainElement(redpanda.ClusterCleanupFinalizer))
+		Expect(k8sClient.Delete(context.Background(), &cluster)).Should(Succeed())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(context.Background(), key, &v1alpha1.Cluster{}))
+		}, timeout, interval).Should(BeTrue())
+	}
+
+	Context("When deleting a cluster", func() {
+		It("Should delete the PersistentVolumeClaims with the Delete retention policy", func() {
+			key, _, redpandaCluster := getInitialTestCluster("cleanup-delete")
+			redpandaCluster.Spec.PVCRetentionPolicy = v1alpha1.PVCRetentionPolicyDelete
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+			pvcKey := createPVC(redpandaCluster)
+
+			deleteCluster(key)
+			Eventually(func() bool {
+				var pvc corev1.PersistentVolumeClaim
+				err := k8sClient.Get(context.Background(), pvcKey, &pvc)
+				return apierrors.IsNotFound(err) || err == nil && pvc.DeletionTimestamp != nil
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("Should retain the PersistentVolumeClaims by default", func() {
+			key, _, redpandaCluster := getInitialTestCluster("cleanup-retain")
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+			pvcKey := createPVC(redpandaCluster)
+
+			deleteCluster(key)
+			var pvc corev1.PersistentVolumeClaim
+			Expect(k8sClient.Get(context.Background(), pvcKey, &pvc)).Should(Succeed())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,92 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/certmanager"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// cloudStorageRecheckInterval is how often the transfers to the bucket are
+// checked when there are no other changes to the cluster
+const cloudStorageRecheckInterval = 5 * time.Minute
+
+// reconcileCloudStorage reports in the CloudStorageConnected condition
+// whether the brokers manage to transfer data to and from the bucket, which
+// is the first place where wrong credentials or IAM role bindings show up
+func (r *ClusterReconciler) reconcileCloudStorage(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	pki *certmanager.PkiReconciler,
+	fqdn string,
+	log logr.Logger,
+) error {
+	if !redpandaCluster.Spec.CloudStorage.Enabled {
+		return nil
+	}
+	errorWithContext := newErrorWithContext(redpandaCluster.Namespace, redpandaCluster.Name)
+
+	// The condition is informational, so we do not wait for the admin API
+	// and check again on the next reconciliation.
+	available, err := adminutils.IsAvailableInPreFlight(ctx, r, redpandaCluster)
+	if err != nil {
+		return errorWithContext(err, "could not perform pre-flight check for admin API availability")
+	} else if !available {
+		log.Info("Admin API is not available yet, skipping the cloud storage check")
+		return nil
+	}
+
+	adminAPI, err := r.AdminAPIClientFactory(ctx, r, redpandaCluster, fqdn, pki.AdminAPIConfigProvider())
+	if err != nil {
+		return errorWithContext(err, "error creating the admin API client")
+	}
+	stats, err := adminAPI.CloudStorageStats(ctx)
+	if err != nil {
+		log.Info("Could not get the cloud storage metrics of the brokers", "error", err.Error())
+		return nil
+	}
+
+	status, reason, message := cloudStorageCondition(stats)
+	if redpandaCluster.Status.SetCondition(redpandav1alpha1.CloudStorageConnectedConditionType, status, reason, message) {
+		if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+			return errorWithContext(err, "could not update the cloud storage condition on cluster")
+		}
+	}
+	return nil
+}
+
+// cloudStorageCondition derives the CloudStorageConnected condition from the
+// transfer counters of the brokers: a single successful transfer shows that
+// the credentials work, while only failures usually mean that they do not
+func cloudStorageCondition(
+	stats admin.CloudStorageStats,
+) (status corev1.ConditionStatus, reason, message string) {
+	succeeded := stats.SuccessfulUploads + stats.SuccessfulDownloads
+	failed := stats.FailedUploads + stats.FailedDownloads
+	switch {
+	case succeeded > 0:
+		return corev1.ConditionTrue, redpandav1alpha1.CloudStorageConnectedReasonTransfersSucceeding,
+			fmt.Sprintf("%d transfers succeeded, %d failed", succeeded, failed)
+	case failed > 0:
+		return corev1.ConditionFalse, redpandav1alpha1.CloudStorageConnectedReasonTransfersFailing,
+			fmt.Sprintf("all %d transfers failed, check the credentials and the bucket configuration", failed)
+	default:
+		return corev1.ConditionUnknown, redpandav1alpha1.CloudStorageConnectedReasonNoTransfers,
+			"no data was transferred to or from the bucket yet"
+	}
+}
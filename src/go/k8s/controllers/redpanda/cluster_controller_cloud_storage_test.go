@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("RedPandaCluster cloud storage controller", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	Context("When cloud storage uses an IAM role", func() {
+		It("Should annotate the ServiceAccount and report the transfers", func() {
+			key, _, redpandaCluster := getInitialTestCluster("cloud-storage-iam")
+			redpandaCluster.Spec.CloudStorage = v1alpha1.CloudStorageConfig{
+				Enabled:           true,
+				CredentialsSource: v1alpha1.CredentialsSourceSTS,
+				IAMRole:           "arn:aws:iam::123456789012:role/redpanda",
+				Bucket:            "bucket",
+				Region:            "us-east-1",
+			}
+			testAdminAPI.SetCloudStorageStats(admin.CloudStorageStats{FailedUploads: 3})
+
+			By("Allowing creation of a new cluster")
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			By("Binding the IAM role to the ServiceAccount")
+			var sa corev1.ServiceAccount
+			Eventually(resourceDataGetter(key, &sa, func() interface{} {
+				return sa.Annotations[v1alpha1.IRSARoleAnnotation]
+			}), timeout, interval).Should(Equal("arn:aws:iam::123456789012:role/redpanda"))
+
+			By("Reporting the failing transfers")
+			var cluster v1alpha1.Cluster
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				return cluster.Status.GetConditionStatus(v1alpha1.CloudStorageConnectedConditionType)
+			}), timeout, interval).Should(Equal(corev1.ConditionFalse))
+			Expect(cluster.Status.GetCondition(v1alpha1.CloudStorageConnectedConditionType).Reason).
+				To(Equal(v1alpha1.CloudStorageConnectedReasonTransfersFailing))
+
+			By("Reporting the connection once a transfer succeeds")
+			testAdminAPI.SetCloudStorageStats(admin.CloudStorageStats{FailedUploads: 3, SuccessfulUploads: 1})
+			Eventually(clusterUpdater(key, func(cl *v1alpha1.Cluster) {
+				cl.Annotations = map[string]string{"test.redpanda.vectorized.io/touch": "cloud-storage"}
+			}), timeout, interval).Should(Succeed())
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				return cluster.Status.GetConditionStatus(v1alpha1.CloudStorageConnectedConditionType)
+			}), timeout, interval).Should(Equal(corev1.ConditionTrue))
+		})
+	})
+})
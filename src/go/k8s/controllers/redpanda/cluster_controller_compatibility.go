@@ -0,0 +1,63 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileCompatibility reports in the KubernetesCompatible condition how
+// the resources of the cluster are adapted to the APIs served by Kubernetes,
+// so that a missing Ingress is explained rather than silently absent
+func (r *ClusterReconciler) reconcileCompatibility(
+	ctx context.Context, redpandaCluster *redpandav1alpha1.Cluster,
+) error {
+	if r.Capabilities == nil {
+		return nil
+	}
+	status, reason, message := kubernetesCompatibleCondition(r.Capabilities, needsIngress(redpandaCluster))
+	if redpandaCluster.Status.SetCondition(redpandav1alpha1.KubernetesCompatibleConditionType, status, reason, message) {
+		if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+			return fmt.Errorf("could not update the Kubernetes compatibility condition on cluster: %w", err)
+		}
+	}
+	return nil
+}
+
+// needsIngress returns whether an Ingress is created for the cluster
+func needsIngress(cluster *redpandav1alpha1.Cluster) bool {
+	proxy := cluster.PandaproxyAPIExternal()
+	return proxy != nil && proxy.External.Subdomain != ""
+}
+
+// kubernetesCompatibleCondition derives the KubernetesCompatible condition
+// from the capabilities of Kubernetes, only the degradations affecting the
+// resources of the cluster are reported
+func kubernetesCompatibleCondition(
+	c *capabilities.Capabilities, ingress bool,
+) (status corev1.ConditionStatus, reason, message string) {
+	switch {
+	case ingress && !c.SupportsIngress():
+		return corev1.ConditionFalse, redpandav1alpha1.KubernetesCompatibleReasonIngressUnsupported,
+			fmt.Sprintf("Kubernetes %s serves no supported Ingress API, the Ingress of the external pandaproxy listener is not created", c.ServerVersion)
+	case ingress && len(c.Degradations()) > 0:
+		return corev1.ConditionTrue, redpandav1alpha1.KubernetesCompatibleReasonDegraded,
+			strings.Join(c.Degradations(), "; ")
+	default:
+		return corev1.ConditionTrue, redpandav1alpha1.KubernetesCompatibleReasonSupported,
+			fmt.Sprintf("Kubernetes %s serves the APIs of all the resources", c.ServerVersion)
+	}
+}
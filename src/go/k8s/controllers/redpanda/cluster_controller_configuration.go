@@ -14,6 +14,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -189,12 +191,29 @@ func (r *ClusterReconciler) applyPatchIfNeeded(
 	}
 
 	patch := configuration.ThreeWayMerge(log, cfg.ClusterConfiguration, clusterConfig, lastAppliedConfiguration, invalidProperties, schema)
+	if redpandaCluster.Spec.ValidateClusterConfiguration {
+		valid, err := r.validatePatch(ctx, redpandaCluster, patch, schema, log)
+		if err != nil || !valid {
+			return false, err
+		}
+	} else {
+		redpandaCluster.Status.ConfigurationErrors = nil
+	}
+
 	if patch.Empty() {
 		return true, nil
 	}
 
 	log.Info("Applying patch to the cluster configuration", "patch", patch.String())
-	wr, err := adminAPI.PatchClusterConfig(ctx, patch.Upsert, patch.Remove)
+	wr, err := adminAPI.PatchClusterConfigAtVersion(ctx, patch.Upsert, patch.Remove, status.Version())
+	if admin.IsConfigVersionConflict(err) {
+		// Someone else changed the configuration since it was read: the patch would revert their change
+		log.Info("Cluster configuration changed concurrently, recomputing the patch", "config_version", status.Version())
+		return false, &resources.RequeueAfterError{
+			RequeueAfter: resources.RequeueDuration,
+			Msg:          "cluster configuration changed concurrently",
+		}
+	}
 	if err != nil {
 		var conditionData *redpandav1alpha1.ClusterCondition
 		conditionData, err = tryMapErrorToCondition(err)
@@ -222,9 +241,54 @@ func (r *ClusterReconciler) applyPatchIfNeeded(
 		return false, nil
 	}
 	log.Info("Patch written to the cluster", "config_version", wr.ConfigVersion)
+	r.auditor().Record(redpandaCluster, resources.AuditReasonClusterConfigPatched, strconv.Itoa(wr.ConfigVersion),
+		"Cluster configuration patched to version %d: %s", wr.ConfigVersion, patch.String())
 	return true, nil
 }
 
+// validatePatch validates the properties to upsert against the schema of the
+// cluster. Validation errors are reported in the status of the cluster and
+// prevent the patch from being applied, since an invalid property that needs a
+// restart could otherwise prevent the cluster from starting.
+func (r *ClusterReconciler) validatePatch(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	patch configuration.CentralConfigurationPatch,
+	schema admin.ConfigSchema,
+	log logr.Logger,
+) (valid bool, err error) {
+	var configErrs []redpandav1alpha1.ConfigurationError
+	var keys []string
+	for _, e := range configuration.ValidateProperties(patch.Upsert, schema) {
+		configErrs = append(configErrs, redpandav1alpha1.ConfigurationError{Key: e.Key, Message: e.Message})
+		keys = append(keys, e.Key)
+	}
+
+	if len(configErrs) == 0 {
+		// Errors of a previous validation are cleared along with the
+		// condition, when the status is synchronized after the patch
+		redpandaCluster.Status.ConfigurationErrors = nil
+		return true, nil
+	}
+
+	log.Info("Cluster configuration failed validation, not applying it", "properties", keys)
+	errorsChanged := !reflect.DeepEqual(redpandaCluster.Status.ConfigurationErrors, configErrs)
+	redpandaCluster.Status.ConfigurationErrors = configErrs
+	conditionChanged := redpandaCluster.Status.SetCondition(
+		redpandav1alpha1.ClusterConfiguredConditionType,
+		corev1.ConditionFalse,
+		redpandav1alpha1.ClusterConfiguredReasonValidationFailed,
+		fmt.Sprintf("Invalid properties, see status.configurationErrors: %s", strings.Join(keys, ", ")),
+	)
+	if conditionChanged || errorsChanged {
+		if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+			return false, newErrorWithContext(redpandaCluster.Namespace, redpandaCluster.Name)(err, "could not update condition on cluster")
+		}
+	}
+	// Validation errors are user errors, so they are unrecoverable
+	return false, nil
+}
+
 func (r *ClusterReconciler) retrieveClusterState(
 	ctx context.Context,
 	redpandaCluster *redpandav1alpha1.Cluster,
@@ -236,16 +300,16 @@ func (r *ClusterReconciler) retrieveClusterState(
 	if err != nil {
 		return nil, nil, nil, errorWithContext(err, "could not get centralized configuration schema")
 	}
-	clusterConfig, err := adminAPI.Config(ctx)
-	if err != nil {
-		return nil, nil, nil, errorWithContext(err, "could not get current centralized configuration from cluster")
-	}
-
 	// We always send requests for config status to the leader to avoid inconsistencies due to config propagation delays.
+	// The status is read before the configuration, so that its version is never newer than the configuration the patch is computed from.
 	status, err := adminAPI.ClusterConfigStatus(ctx, true)
 	if err != nil {
 		return nil, nil, nil, errorWithContext(err, "could not get current centralized configuration status from cluster")
 	}
+	clusterConfig, err := adminAPI.Config(ctx)
+	if err != nil {
+		return nil, nil, nil, errorWithContext(err, "could not get current centralized configuration from cluster")
+	}
 
 	return schema, clusterConfig, status, nil
 }
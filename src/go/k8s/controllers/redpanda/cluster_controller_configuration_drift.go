@@ -24,9 +24,11 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/featuregates"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
@@ -45,6 +47,7 @@ type ClusterConfigurationDriftReconciler struct {
 	Scheme                *runtime.Scheme
 	DriftCheckPeriod      *time.Duration
 	AdminAPIClientFactory adminutils.AdminAPIClientFactory
+	clusterSelector       k8slabels.Selector
 }
 
 // Reconcile detects drift in configuration for clusters and schedules a patch.
@@ -66,6 +69,10 @@ func (r *ClusterConfigurationDriftReconciler) Reconcile(
 		return ctrl.Result{}, fmt.Errorf("unable to retrieve Cluster resource: %w", err)
 	}
 
+	if !isClusterSelected(log, r.clusterSelector, &redpandaCluster) {
+		return ctrl.Result{}, nil
+	}
+
 	if !featuregates.CentralizedConfiguration(redpandaCluster.Spec.Version) {
 		return ctrl.Result{RequeueAfter: r.getDriftCheckPeriod()}, nil
 	}
@@ -104,13 +111,13 @@ func (r *ClusterConfigurationDriftReconciler) Reconcile(
 
 	var proxySu *resources.SuperUsersResource
 	var proxySuKey types.NamespacedName
-	if redpandaCluster.Spec.EnableSASL && redpandaCluster.PandaproxyAPIInternal() != nil {
+	if redpandaCluster.IsSASLOnInternalEnabled() && redpandaCluster.PandaproxyAPIInternal() != nil {
 		proxySu = resources.NewSuperUsers(r.Client, &redpandaCluster, r.Scheme, resources.ScramPandaproxyUsername, resources.PandaProxySuffix, log)
 		proxySuKey = proxySu.Key()
 	}
 	var schemaRegistrySu *resources.SuperUsersResource
 	var schemaRegistrySuKey types.NamespacedName
-	if redpandaCluster.Spec.EnableSASL && redpandaCluster.Spec.Configuration.SchemaRegistry != nil {
+	if redpandaCluster.IsSASLOnInternalEnabled() && redpandaCluster.Spec.Configuration.SchemaRegistry != nil {
 		schemaRegistrySu = resources.NewSuperUsers(r.Client, &redpandaCluster, r.Scheme, resources.ScramSchemaRegistryUsername, resources.SchemaRegistrySuffix, log)
 		schemaRegistrySuKey = schemaRegistrySu.Key()
 	}
@@ -166,7 +173,7 @@ func (r *ClusterConfigurationDriftReconciler) SetupWithManager(
 	mgr ctrl.Manager,
 ) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&redpandav1alpha1.Cluster{}).
+		For(&redpandav1alpha1.Cluster{}, builder.WithPredicates(clusterSelectorPredicate(r.clusterSelector))).
 		WithEventFilter(createOrDeleteEventFilter{}).
 		Complete(r)
 }
@@ -179,6 +186,15 @@ func (r *ClusterConfigurationDriftReconciler) WithClusterDomain(
 	return r
 }
 
+// WithClusterSelector restricts the reconciled clusters to the ones matching
+// the label selector
+func (r *ClusterConfigurationDriftReconciler) WithClusterSelector(
+	selector k8slabels.Selector,
+) *ClusterConfigurationDriftReconciler {
+	r.clusterSelector = selector
+	return r
+}
+
 func (r *ClusterConfigurationDriftReconciler) getDriftCheckPeriod() time.Duration {
 	if r.DriftCheckPeriod != nil {
 		return *r.DriftCheckPeriod
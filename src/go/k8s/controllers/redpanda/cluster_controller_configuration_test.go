@@ -668,6 +668,56 @@ var _ = Describe("RedPandaCluster configuration controller", func() {
 			Expect(k8sClient.Delete(context.Background(), redpandaCluster)).Should(Succeed())
 		})
 
+		It("Should not apply properties that fail validation against the schema", func() {
+			By("Allowing creation of a new cluster")
+			key, baseKey, redpandaCluster := getInitialTestCluster("condition-schema-validation")
+			redpandaCluster.Spec.ValidateClusterConfiguration = true
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			By("Creating the Configmap and the statefulset")
+			Eventually(resourceGetter(baseKey, &corev1.ConfigMap{}), timeout, interval).Should(Succeed())
+			Eventually(resourceGetter(key, &appsv1.StatefulSet{}), timeout, interval).Should(Succeed())
+
+			By("Configuring the cluster")
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+			numberOfPatches := testAdminAPI.NumPatchesGetter()()
+
+			By("Rejecting an unknown property")
+			Eventually(clusterUpdater(key, func(cluster *v1alpha1.Cluster) {
+				if cluster.Spec.AdditionalConfiguration == nil {
+					cluster.Spec.AdditionalConfiguration = make(map[string]string)
+				}
+				cluster.Spec.AdditionalConfiguration["redpanda.unk"] = "nown-value"
+			}), timeout, interval).Should(Succeed())
+
+			By("Reflecting the issue in the condition and in the status")
+			Eventually(resourceDataGetter(key, redpandaCluster, func() interface{} {
+				cond := redpandaCluster.Status.GetCondition(v1alpha1.ClusterConfiguredConditionType)
+				if cond == nil {
+					return nil
+				}
+				return fmt.Sprintf("%s/%s", cond.Status, cond.Reason)
+			}), timeout, interval).Should(Equal("False/ValidationFailed"))
+			Expect(redpandaCluster.Status.ConfigurationErrors).To(Equal([]v1alpha1.ConfigurationError{
+				{Key: "unk", Message: "unknown property"},
+			}))
+
+			By("Not sending the property to the admin API")
+			Consistently(testAdminAPI.NumPatchesGetter(), timeoutShort, intervalShort).Should(Equal(numberOfPatches))
+
+			By("Restoring the state when fixing the property")
+			Eventually(clusterUpdater(key, func(cluster *v1alpha1.Cluster) {
+				delete(cluster.Spec.AdditionalConfiguration, "redpanda.unk")
+			}), timeout, interval).Should(Succeed())
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+			Eventually(resourceDataGetter(key, redpandaCluster, func() interface{} {
+				return redpandaCluster.Status.ConfigurationErrors
+			}), timeout, interval).Should(BeEmpty())
+
+			By("Deleting the cluster")
+			Expect(k8sClient.Delete(context.Background(), redpandaCluster)).Should(Succeed())
+		})
+
 		It("Should report configuration errors present in the .bootstrap.yaml file", func() {
 			// Inject property before creating the cluster, simulating .bootstrap.yaml
 			const val = "nown"
@@ -0,0 +1,208 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/certmanager"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// These are the reasons of the events emitted for the debug annotation
+const (
+	DebugLogLevelsEventReasonApplied  = "DebugLogLevelsApplied"
+	DebugLogLevelsEventReasonRestored = "DebugLogLevelsRestored"
+	DebugLogLevelsEventReasonFailed   = "DebugLogLevelsFailed"
+	DebugLogLevelsEventReasonInvalid  = "DebugLogLevelsInvalid"
+)
+
+// reconcileDebugLogLevels applies the temporary log levels requested through
+// the debug annotation to all brokers, and removes the annotation once they
+// expire. If the annotation is removed before, the levels the brokers started
+// with are restored. It returns how long until the overrides expire, or zero
+// if none are active.
+func (r *ClusterReconciler) reconcileDebugLogLevels(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	pki *certmanager.PkiReconciler,
+	fqdn string,
+	log logr.Logger,
+) (time.Duration, error) {
+	errorWithContext := newErrorWithContext(redpandaCluster.Namespace, redpandaCluster.Name)
+
+	levels, expiry, err := redpandaCluster.DebugLogLevels()
+	if err != nil {
+		// The webhook rejects invalid annotations, so this only happens if
+		// it is not deployed
+		r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeWarning, DebugLogLevelsEventReasonInvalid,
+			"Ignoring the %s annotation: %v", redpandav1alpha1.DebugLogLevelsAnnotation, err)
+		return 0, nil
+	}
+	applied := redpandaCluster.Status.DebugLogLevels
+	value := redpandaCluster.Annotations[redpandav1alpha1.DebugLogLevelsAnnotation]
+	now := time.Now()
+	var rejected *rejectedLoggersError
+
+	switch {
+	case levels == nil && applied == nil:
+		return 0, nil
+
+	case levels == nil:
+		if now.Before(applied.Expiration.Time) {
+			restore, err := appliedDebugLogLevels(applied)
+			if err != nil {
+				return 0, errorWithContext(err, "could not parse the applied debug log levels")
+			}
+			for logger := range restore {
+				restore[logger] = redpandaCluster.LogLevelOf(logger)
+			}
+			log.Info("Restoring the log levels overridden by the debug annotation", "levels", restore)
+			// Rejected loggers were never overridden, so there is nothing to
+			// restore for them
+			err = r.setLoggerLevels(ctx, redpandaCluster, pki, fqdn, restore, 0)
+			if err != nil && !errors.As(err, &rejected) {
+				return 0, errorWithContext(err, "could not restore the log levels")
+			}
+			r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeNormal, DebugLogLevelsEventReasonRestored,
+				"Restored the log levels of %s", joinLoggers(restore))
+		}
+		redpandaCluster.Status.DebugLogLevels = nil
+		if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+			return 0, errorWithContext(err, "could not clear the debug log levels status on cluster")
+		}
+		return 0, nil
+
+	case applied != nil && applied.Levels == value && applied.Duration.Duration == expiry:
+		if remaining := applied.Expiration.Sub(now); remaining > 0 {
+			return remaining, nil
+		}
+		// The brokers have reverted the overrides on their own, the
+		// annotation is removed so that it can be applied again
+		log.Info("Debug log levels expired, removing the annotation")
+		delete(redpandaCluster.Annotations, redpandav1alpha1.DebugLogLevelsAnnotation)
+		delete(redpandaCluster.Annotations, redpandav1alpha1.DebugLogLevelsExpiryAnnotation)
+		if err := r.Update(ctx, redpandaCluster); err != nil {
+			return 0, errorWithContext(err, "could not remove the expired debug annotation")
+		}
+		return 0, nil
+	}
+
+	log.Info("Applying the log levels of the debug annotation", "levels", levels, "expiry", expiry)
+	err = r.setLoggerLevels(ctx, redpandaCluster, pki, fqdn, levels, expiry)
+	switch {
+	case errors.As(err, &rejected):
+		// Retrying does not help, e.g. if a logger does not exist
+		r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeWarning, DebugLogLevelsEventReasonFailed,
+			"Unable to set the log levels of the debug annotation: %v", err)
+	case err != nil:
+		return 0, errorWithContext(err, "could not apply the debug log levels")
+	default:
+		r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeNormal, DebugLogLevelsEventReasonApplied,
+			"Set the log levels of %s for %v", joinLoggers(levels), expiry)
+	}
+
+	redpandaCluster.Status.DebugLogLevels = &redpandav1alpha1.DebugLogLevelsStatus{
+		Levels:     value,
+		Duration:   metav1.Duration{Duration: expiry},
+		Expiration: metav1.NewTime(now.Add(expiry)),
+	}
+	if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+		return 0, errorWithContext(err, "could not update the debug log levels status on cluster")
+	}
+	return expiry, nil
+}
+
+// setLoggerLevels sets the levels on every broker, as the levels are local to
+// each broker. Loggers rejected by a broker are reported as a
+// rejectedLoggersError once all brokers have been attempted.
+func (r *ClusterReconciler) setLoggerLevels(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	pki *certmanager.PkiReconciler,
+	fqdn string,
+	levels map[string]string,
+	expiry time.Duration,
+) error {
+	available, err := adminutils.IsAvailableInPreFlight(ctx, r, redpandaCluster)
+	if err != nil {
+		return fmt.Errorf("could not perform pre-flight check for admin API availability: %w", err)
+	} else if !available {
+		return &resources.RequeueAfterError{
+			RequeueAfter: resources.RequeueDuration,
+			Msg:          "admin API is not available yet",
+		}
+	}
+
+	rejected := make(map[string]error)
+	for ordinal := int32(0); ordinal < redpandaCluster.GetCurrentReplicas(); ordinal++ {
+		adminAPI, err := r.AdminAPIClientFactory(ctx, r, redpandaCluster, fqdn, pki.AdminAPIConfigProvider(), ordinal)
+		if err != nil {
+			return fmt.Errorf("error creating the admin API client for broker %d: %w", ordinal, err)
+		}
+		for logger, err := range adminAPI.SetLoggerLevels(ctx, levels, expiry) {
+			var httpErr *admin.HTTPResponseError
+			if !errors.As(err, &httpErr) || httpErr.Response.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("could not set the level of logger %q on broker %d: %w", logger, ordinal, err)
+			}
+			rejected[logger] = err
+		}
+	}
+	if len(rejected) > 0 {
+		return &rejectedLoggersError{rejected}
+	}
+	return nil
+}
+
+// rejectedLoggersError lists the loggers whose level the brokers refused to
+// set
+type rejectedLoggersError struct {
+	loggers map[string]error
+}
+
+func (e *rejectedLoggersError) Error() string {
+	var msgs []string
+	for logger, err := range e.loggers {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", logger, err))
+	}
+	sort.Strings(msgs)
+	return strings.Join(msgs, "; ")
+}
+
+// appliedDebugLogLevels parses the debug annotation recorded in the status
+func appliedDebugLogLevels(
+	applied *redpandav1alpha1.DebugLogLevelsStatus,
+) (map[string]string, error) {
+	cluster := redpandav1alpha1.Cluster{}
+	cluster.Annotations = map[string]string{redpandav1alpha1.DebugLogLevelsAnnotation: applied.Levels}
+	levels, _, err := cluster.DebugLogLevels()
+	return levels, err
+}
+
+func joinLoggers(levels map[string]string) string {
+	loggers := make([]string, 0, len(levels))
+	for logger, level := range levels {
+		loggers = append(loggers, logger+"="+level)
+	}
+	sort.Strings(loggers)
+	return strings.Join(loggers, ",")
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+)
+
+var _ = Describe("RedPandaCluster debug log levels controller", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	Context("When annotating a cluster with debug log levels", func() {
+		It("Should apply the levels and restore them once the annotation is removed", func() {
+			key, _, redpandaCluster := getInitialTestCluster("debug-log-levels")
+			redpandaCluster.Spec.Configuration.LogLevels = map[string]string{"raft": "warn"}
+			redpandaCluster.Annotations = map[string]string{
+				v1alpha1.DebugLogLevelsAnnotation:       "raft=trace",
+				v1alpha1.DebugLogLevelsExpiryAnnotation: "1h",
+			}
+
+			By("Allowing creation of a new cluster")
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			By("Applying the levels and reporting them in the status")
+			Eventually(testAdminAPI.LoggerLevelGetter("raft"), timeout, interval).Should(Equal("trace"))
+			var cluster v1alpha1.Cluster
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				if cluster.Status.DebugLogLevels == nil {
+					return ""
+				}
+				return cluster.Status.DebugLogLevels.Levels
+			}), timeout, interval).Should(Equal("raft=trace"))
+			Expect(cluster.Status.DebugLogLevels.Duration.Duration).To(Equal(time.Hour))
+
+			By("Restoring the configured level when the annotation is removed")
+			Eventually(clusterUpdater(key, func(cl *v1alpha1.Cluster) {
+				delete(cl.Annotations, v1alpha1.DebugLogLevelsAnnotation)
+			}), timeout, interval).Should(Succeed())
+			Eventually(testAdminAPI.LoggerLevelGetter("raft"), timeout, interval).Should(Equal("warn"))
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				return cluster.Status.DebugLogLevels
+			}), timeout, interval).Should(BeNil())
+		})
+	})
+})
@@ -0,0 +1,180 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/networking"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// endpointRecheckInterval is how often the reachable external
+	// endpoints are probed when there are no other changes to the cluster
+	endpointRecheckInterval = 5 * time.Minute
+	// endpointRetryInterval is how often the unreachable external
+	// endpoints are probed, which are usually waiting for their DNS record
+	// or certificate
+	endpointRetryInterval = 30 * time.Second
+	// endpointProbeTimeout bounds each probe, which runs in the reconcile
+	endpointProbeTimeout = 5 * time.Second
+)
+
+// externalEndpoint is an external HTTP endpoint of the cluster to probe
+type externalEndpoint struct {
+	name string
+	url  string
+	opts networking.ProbeOptions
+}
+
+// reconcileEndpoints probes the external endpoints of the Pandaproxy Ingress
+// and of the schema registry from the operator, and reports in
+// status.endpoints whether they are reachable. Misconfigured DNS records,
+// Ingress controllers or issuers then show up in the status of the cluster
+// rather than in the clients.
+//
+// An endpoint is probed again once its last probe is older than
+// endpointRecheckInterval, or endpointRetryInterval if it was unreachable.
+func (r *ClusterReconciler) reconcileEndpoints(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	log logr.Logger,
+) error {
+	endpoints := externalEndpoints(redpandaCluster)
+	if len(endpoints) == 0 && len(redpandaCluster.Status.Endpoints) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var statuses []redpandav1alpha1.EndpointStatus
+	for _, ep := range endpoints {
+		prev := findEndpointStatus(redpandaCluster.Status.Endpoints, ep.name)
+		if prev != nil && prev.URL == ep.url && now.Before(prev.LastProbeTime.Add(endpointRecheckAfter(prev))) {
+			statuses = append(statuses, *prev)
+			continue
+		}
+
+		code, err := networking.ProbeEndpoint(ctx, ep.url, ep.opts)
+		status := redpandav1alpha1.EndpointStatus{
+			Name:           ep.name,
+			URL:            ep.url,
+			Reachable:      err == nil,
+			HTTPStatusCode: code,
+			LastProbeTime:  metav1.NewTime(now),
+		}
+		var probeErr *networking.ProbeError
+		if errors.As(err, &probeErr) {
+			status.FailedStage = probeErr.Stage
+			status.Message = probeErr.Err.Error()
+		} else if err != nil {
+			status.Message = err.Error()
+		}
+		if !status.Reachable && (prev == nil || prev.Reachable) {
+			log.Info("External endpoint is not reachable", "endpoint", ep.name, "url", ep.url, "stage", status.FailedStage, "error", status.Message)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if reflect.DeepEqual(statuses, redpandaCluster.Status.Endpoints) {
+		return nil
+	}
+	redpandaCluster.Status.Endpoints = statuses
+	if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+		return fmt.Errorf("could not update the endpoints status of the cluster: %w", err)
+	}
+	return nil
+}
+
+// externalEndpoints returns the external HTTP endpoints of the cluster that
+// are reached through a host name, which the operator can probe
+func externalEndpoints(
+	cluster *redpandav1alpha1.Cluster,
+) []externalEndpoint {
+	var endpoints []externalEndpoint
+
+	if proxy := cluster.PandaproxyAPIExternal(); proxy != nil && proxy.External.Subdomain != "" {
+		scheme := "http"
+		var opts networking.ProbeOptions
+		if tlsProxy := cluster.PandaproxyAPITLS(); tlsProxy != nil {
+			// The certificates of the Pandaproxy API are always issued
+			// by the self-signed issuer of the operator
+			scheme = "https"
+			opts.SkipHTTP = tlsProxy.TLS.RequireClientAuth
+		}
+		opts.Timeout = endpointProbeTimeout
+		endpoints = append(endpoints, externalEndpoint{
+			name: redpandav1alpha1.EndpointPandaproxyIngress,
+			url:  fmt.Sprintf("%s://%s/topics", scheme, proxy.External.Subdomain),
+			opts: opts,
+		})
+	}
+
+	sr := cluster.Spec.Configuration.SchemaRegistry
+	if cluster.IsSchemaRegistryExternallyAvailable() && sr.External.Subdomain != "" &&
+		cluster.Status.Nodes.SchemaRegistry != nil && cluster.Status.Nodes.SchemaRegistry.External != "" {
+		opts := networking.ProbeOptions{
+			// A certificate from a user issuer or secret is expected to
+			// chain to a trusted CA, unlike the self-signed ones
+			VerifyChain: sr.TLS != nil && (sr.TLS.IssuerRef != nil || sr.TLS.NodeSecretRef != nil),
+			SkipHTTP:    cluster.IsSchemaRegistryMutualTLSEnabled(),
+			Timeout:     endpointProbeTimeout,
+		}
+		endpoints = append(endpoints, externalEndpoint{
+			name: redpandav1alpha1.EndpointSchemaRegistry,
+			url:  fmt.Sprintf("%s://%s/subjects", sr.GetHTTPScheme(), cluster.Status.Nodes.SchemaRegistry.External),
+			opts: opts,
+		})
+	}
+	return endpoints
+}
+
+func findEndpointStatus(
+	statuses []redpandav1alpha1.EndpointStatus, name string,
+) *redpandav1alpha1.EndpointStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// endpointRecheckAfter returns how long after its last probe an endpoint is
+// probed again
+func endpointRecheckAfter(status *redpandav1alpha1.EndpointStatus) time.Duration {
+	if status.Reachable {
+		return endpointRecheckInterval
+	}
+	return endpointRetryInterval
+}
+
+// nextEndpointProbe returns when the next endpoint of the cluster is due to
+// be probed, or 0 if there is none
+func nextEndpointProbe(status *redpandav1alpha1.ClusterStatus) time.Duration {
+	var next time.Duration
+	for i := range status.Endpoints {
+		ep := &status.Endpoints[i]
+		until := time.Until(ep.LastProbeTime.Add(endpointRecheckAfter(ep)))
+		if until < time.Second {
+			until = time.Second
+		}
+		if next == 0 || until < next {
+			next = until
+		}
+	}
+	return next
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/networking"
+)
+
+var _ = Describe("RedPandaCluster endpoints controller", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	Context("When the schema registry is exposed on a subdomain", func() {
+		It("Should report that an unresolved endpoint is unreachable", func() {
+			key, _, redpandaCluster := getInitialTestCluster("endpoints")
+			redpandaCluster.Spec.Configuration.KafkaAPI = append(redpandaCluster.Spec.Configuration.KafkaAPI, v1alpha1.KafkaAPI{
+				External: v1alpha1.ExternalConnectivityConfig{Enabled: true, Subdomain: "redpanda.invalid"},
+			})
+			redpandaCluster.Spec.Configuration.SchemaRegistry = &v1alpha1.SchemaRegistryAPI{
+				Port: 8081,
+				External: &v1alpha1.ExternalConnectivityConfig{
+					Enabled:   true,
+					Subdomain: "schema-registry.redpanda.invalid",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			var cluster v1alpha1.Cluster
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				return len(cluster.Status.Endpoints)
+			}), timeout, interval).Should(Equal(1))
+			endpoint := cluster.Status.Endpoints[0]
+			Expect(endpoint.Name).To(Equal(v1alpha1.EndpointSchemaRegistry))
+			Expect(endpoint.URL).To(HavePrefix("http://schema-registry.redpanda.invalid:"))
+			Expect(endpoint.Reachable).To(BeFalse())
+			Expect(endpoint.FailedStage).To(Equal(networking.ProbeStageDNS))
+			Expect(endpoint.LastProbeTime.IsZero()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,198 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/certmanager"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// licenseSecretKey is the default key of the license in the Secret
+	// referenced by LicenseRef
+	licenseSecretKey = "license"
+	// licenseExpiryWarningPeriod is how long before the expiration of the
+	// license warning events are emitted
+	licenseExpiryWarningPeriod = 30 * 24 * time.Hour
+	// licenseRecheckInterval is how often the license is checked when there
+	// are no other changes to the cluster, so that expiry is noticed in time
+	licenseRecheckInterval = time.Hour
+)
+
+// These are the reasons of the events emitted for the license
+const (
+	LicenseEventReasonUploaded     = "LicenseUploaded"
+	LicenseEventReasonUploadFailed = "LicenseUploadFailed"
+	LicenseEventReasonExpiringSoon = "LicenseExpiringSoon"
+	LicenseEventReasonExpired      = "LicenseExpired"
+)
+
+// reconcileLicense uploads the license referenced by the cluster to Redpanda
+// whenever it changes, and reports its expiration in the cluster status
+func (r *ClusterReconciler) reconcileLicense(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	pki *certmanager.PkiReconciler,
+	fqdn string,
+	log logr.Logger,
+) error {
+	ref := redpandaCluster.Spec.LicenseRef
+	if ref == nil {
+		return nil
+	}
+	errorWithContext := newErrorWithContext(redpandaCluster.Namespace, redpandaCluster.Name)
+
+	secret, err := ref.GetSecret(ctx, r.Client)
+	if err != nil {
+		return errorWithContext(err, "could not get the license Secret")
+	}
+	value, err := ref.GetValue(secret, licenseSecretKey)
+	if err != nil {
+		return errorWithContext(err, "could not read the license")
+	}
+	license := strings.TrimSpace(string(value))
+	sum := sha256.Sum256([]byte(license))
+	checksum := hex.EncodeToString(sum[:])
+
+	available, err := adminutils.IsAvailableInPreFlight(ctx, r, redpandaCluster)
+	if err != nil {
+		return errorWithContext(err, "could not perform pre-flight check for admin API availability")
+	} else if !available {
+		log.Info("Waiting for admin API to be available before uploading the license")
+		return &resources.RequeueAfterError{
+			RequeueAfter: resources.RequeueDuration,
+			Msg:          "admin API is not available yet",
+		}
+	}
+
+	adminAPI, err := r.AdminAPIClientFactory(ctx, r, redpandaCluster, fqdn, pki.AdminAPIConfigProvider())
+	if err != nil {
+		return errorWithContext(err, "error creating the admin API client")
+	}
+
+	info, err := adminAPI.GetLicenseInfo(ctx)
+	if err != nil {
+		return errorWithContext(err, "could not get the license loaded in the cluster")
+	}
+
+	// Older Redpanda versions do not report the checksum of the loaded
+	// license, in which case we rely on the one we last uploaded.
+	applied := info.Properties.Checksum
+	if applied == "" && redpandaCluster.Status.License != nil {
+		applied = redpandaCluster.Status.License.Checksum
+	}
+	if !info.Loaded || applied != checksum {
+		log.Info("Uploading license to the cluster", "secret", ref.Namespace+"/"+ref.Name)
+		if err = adminAPI.SetLicense(ctx, strings.NewReader(license)); err != nil {
+			r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeWarning, LicenseEventReasonUploadFailed,
+				"Unable to upload the license from Secret %s/%s: %v", ref.Namespace, ref.Name, err)
+			return errorWithContext(err, "could not upload the license")
+		}
+		r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeNormal, LicenseEventReasonUploaded,
+			"Uploaded the license from Secret %s/%s", ref.Namespace, ref.Name)
+		if info, err = adminAPI.GetLicenseInfo(ctx); err != nil {
+			return errorWithContext(err, "could not get the license loaded in the cluster")
+		}
+	}
+
+	now := time.Now()
+	status := licenseStatus(info, checksum, now)
+	conditionChanged := r.setLicenseCondition(redpandaCluster, info, now)
+	if conditionChanged || !equality.Semantic.DeepEqual(redpandaCluster.Status.License, status) {
+		redpandaCluster.Status.License = status
+		if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+			return errorWithContext(err, "could not update the license status on cluster")
+		}
+	}
+	return nil
+}
+
+// setLicenseCondition reports the license loaded in the cluster in the
+// LicenseValid condition. The expiration warning events are only emitted
+// when the condition changes, not on every periodic recheck of the license.
+// The return value indicates if the condition changed.
+func (r *ClusterReconciler) setLicenseCondition(
+	redpandaCluster *redpandav1alpha1.Cluster, info admin.License, now time.Time,
+) bool {
+	condition, reason, message := licenseCondition(info, now)
+	if !redpandaCluster.Status.SetCondition(redpandav1alpha1.LicenseValidConditionType, condition, reason, message) {
+		return false
+	}
+	switch reason {
+	case redpandav1alpha1.LicenseValidReasonExpired:
+		r.EventRecorder.Event(redpandaCluster, corev1.EventTypeWarning, LicenseEventReasonExpired, message)
+	case redpandav1alpha1.LicenseValidReasonExpiringSoon:
+		r.EventRecorder.Event(redpandaCluster, corev1.EventTypeWarning, LicenseEventReasonExpiringSoon, message)
+	}
+	return true
+}
+
+// licenseCondition derives the LicenseValid condition from the license
+// loaded in the cluster. The message only depends on the expiration time, so
+// that the condition does not change between two checks of the same license.
+func licenseCondition(
+	info admin.License, now time.Time,
+) (condition corev1.ConditionStatus, reason, message string) {
+	if !info.Loaded {
+		return corev1.ConditionUnknown, redpandav1alpha1.LicenseValidReasonNotLoaded,
+			"The cluster reports no loaded license"
+	}
+	if info.Properties.Expires <= 0 {
+		return corev1.ConditionTrue, redpandav1alpha1.LicenseValidReasonValid,
+			"The license does not expire"
+	}
+	expiration := time.Unix(info.Properties.Expires, 0)
+	formatted := expiration.UTC().Format(time.RFC3339)
+	switch {
+	case now.After(expiration):
+		return corev1.ConditionFalse, redpandav1alpha1.LicenseValidReasonExpired,
+			fmt.Sprintf("The license expired on %s", formatted)
+	case expiration.Sub(now) < licenseExpiryWarningPeriod:
+		return corev1.ConditionTrue, redpandav1alpha1.LicenseValidReasonExpiringSoon,
+			fmt.Sprintf("The license expires on %s", formatted)
+	default:
+		return corev1.ConditionTrue, redpandav1alpha1.LicenseValidReasonValid,
+			fmt.Sprintf("The license expires on %s", formatted)
+	}
+}
+
+// licenseStatus converts the license loaded in the cluster to its status
+func licenseStatus(
+	info admin.License, checksum string, now time.Time,
+) *redpandav1alpha1.LicenseStatus {
+	status := &redpandav1alpha1.LicenseStatus{
+		Checksum: checksum,
+	}
+	if !info.Loaded {
+		return status
+	}
+	status.Organization = info.Properties.Organization
+	status.Type = info.Properties.Type
+	if info.Properties.Expires > 0 {
+		expiration := metav1.NewTime(time.Unix(info.Properties.Expires, 0))
+		status.Expiration = &expiration
+		status.Expired = now.After(expiration.Time)
+	}
+	return status
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda //nolint:testpackage // needed to test private functions
+
+import (
+	"testing"
+	"time"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestLicenseCondition(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	license := func(expires time.Time) admin.License {
+		l := admin.License{Loaded: true}
+		l.Properties.Organization = "redpanda"
+		if !expires.IsZero() {
+			l.Properties.Expires = expires.Unix()
+		}
+		return l
+	}
+
+	tests := []struct {
+		name      string
+		info      admin.License
+		condition corev1.ConditionStatus
+		reason    string
+	}{
+		{name: "not loaded", info: admin.License{}, condition: corev1.ConditionUnknown, reason: redpandav1alpha1.LicenseValidReasonNotLoaded},
+		{name: "no expiration", info: license(time.Time{}), condition: corev1.ConditionTrue, reason: redpandav1alpha1.LicenseValidReasonValid},
+		{name: "valid", info: license(now.Add(90 * 24 * time.Hour)), condition: corev1.ConditionTrue, reason: redpandav1alpha1.LicenseValidReasonValid},
+		{name: "expiring soon", info: license(now.Add(7 * 24 * time.Hour)), condition: corev1.ConditionTrue, reason: redpandav1alpha1.LicenseValidReasonExpiringSoon},
+		{name: "expired", info: license(now.Add(-time.Hour)), condition: corev1.ConditionFalse, reason: redpandav1alpha1.LicenseValidReasonExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, reason, _ := licenseCondition(tt.info, now)
+			require.Equal(t, tt.condition, condition)
+			require.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestSetLicenseCondition(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	info := admin.License{Loaded: true}
+	info.Properties.Expires = now.Add(7 * 24 * time.Hour).Unix()
+
+	recorder := record.NewFakeRecorder(10)
+	r := &ClusterReconciler{EventRecorder: recorder}
+	cluster := &redpandav1alpha1.Cluster{}
+
+	require.True(t, r.setLicenseCondition(cluster, info, now))
+	require.Equal(t, "Warning LicenseExpiringSoon The license expires on 2022-06-08T00:00:00Z", <-recorder.Events)
+
+	// The hourly rechecks of the same license emit no new event
+	require.False(t, r.setLicenseCondition(cluster, info, now.Add(time.Hour)))
+	require.False(t, r.setLicenseCondition(cluster, info, now.Add(2*time.Hour)))
+	require.Empty(t, recorder.Events)
+
+	// Once the license expires, the condition changes and an event is emitted
+	require.True(t, r.setLicenseCondition(cluster, info, now.Add(8*24*time.Hour)))
+	require.Equal(t, "Warning LicenseExpired The license expired on 2022-06-08T00:00:00Z", <-recorder.Events)
+	require.Equal(t, corev1.ConditionFalse, cluster.Status.GetConditionStatus(redpandav1alpha1.LicenseValidConditionType))
+}
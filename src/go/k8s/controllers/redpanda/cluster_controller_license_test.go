@@ -0,0 +1,86 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RedPandaCluster license controller", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	checksumOf := func(license string) string {
+		sum := sha256.Sum256([]byte(license))
+		return hex.EncodeToString(sum[:])
+	}
+
+	licenseChecksumGetter := func(cluster *v1alpha1.Cluster) func() string {
+		return func() string {
+			if cluster.Status.License == nil {
+				return ""
+			}
+			return cluster.Status.License.Checksum
+		}
+	}
+
+	Context("When referencing a license Secret", func() {
+		It("Should upload the license and report it in the status", func() {
+			key, _, redpandaCluster := getInitialTestCluster("license")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "license-secret",
+					Namespace: key.Namespace,
+				},
+				StringData: map[string]string{"license": "first-license"},
+			}
+			Expect(k8sClient.Create(context.Background(), secret)).Should(Succeed())
+			redpandaCluster.Spec.LicenseRef = &v1alpha1.SecretKeyRef{
+				Name:      secret.Name,
+				Namespace: secret.Namespace,
+			}
+
+			By("Allowing creation of a new cluster")
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			By("Reporting the uploaded license in the status")
+			var cluster v1alpha1.Cluster
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				return licenseChecksumGetter(&cluster)()
+			}), timeout, interval).Should(Equal(checksumOf("first-license")))
+			Expect(cluster.Status.License.Organization).To(Equal("redpanda"))
+			Expect(cluster.Status.License.Expired).To(BeFalse())
+			Expect(cluster.Status.GetCondition(v1alpha1.LicenseValidConditionType).Reason).
+				To(Equal(v1alpha1.LicenseValidReasonValid))
+
+			By("Uploading the license again when the Secret changes")
+			secret.StringData = map[string]string{"license": "second-license"}
+			Expect(k8sClient.Update(context.Background(), secret)).Should(Succeed())
+			// Secrets are not watched, so trigger a new reconciliation
+			Eventually(clusterUpdater(key, func(cl *v1alpha1.Cluster) {
+				cl.Annotations = map[string]string{"test.redpanda.vectorized.io/touch": "license"}
+			}), timeout, interval).Should(Succeed())
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				return licenseChecksumGetter(&cluster)()
+			}), timeout, interval).Should(Equal(checksumOf("second-license")))
+		})
+	})
+})
@@ -0,0 +1,123 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/certmanager"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// These are the reasons of the events emitted for the operation annotations
+const (
+	OperationEventReasonPaused     = "ReconcilePaused"
+	OperationEventReasonRestart    = "RestartRequested"
+	OperationEventReasonRebalanced = "RebalanceTriggered"
+	OperationEventReasonInvalid    = "InvalidOperation"
+)
+
+func isReconcilePaused(
+	log logr.Logger, redpandaCluster *redpandav1alpha1.Cluster,
+) bool {
+	if !redpandaCluster.IsReconcilePaused() {
+		return false
+	}
+	log.Info(fmt.Sprintf("reconciliation of %s is paused; to resume it, remove the '%s' annotation",
+		redpandaCluster.Name, redpandav1alpha1.PauseReconcileAnnotation))
+	return true
+}
+
+// reconcileRestartAnnotation records the time of a restart requested through
+// the restart annotation in the status, which the statefulset carries in its
+// pod template so that the brokers are restarted one by one. The annotation
+// is then removed, so that it can be set again for the next restart.
+func (r *ClusterReconciler) reconcileRestartAnnotation(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	log logr.Logger,
+) error {
+	value, ok := redpandaCluster.Annotations[redpandav1alpha1.RestartAnnotation]
+	if !ok {
+		return nil
+	}
+	errorWithContext := newErrorWithContext(redpandaCluster.Namespace, redpandaCluster.Name)
+
+	log.Info("Rolling restart requested through the restart annotation", "value", value)
+	now := metav1.Now()
+	redpandaCluster.Status.RestartedAt = &now
+	if err := r.Status().Update(ctx, redpandaCluster); err != nil {
+		return errorWithContext(err, "could not record the requested restart in the status")
+	}
+	r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeNormal, OperationEventReasonRestart,
+		"Rolling restart requested through the %s annotation", redpandav1alpha1.RestartAnnotation)
+
+	delete(redpandaCluster.Annotations, redpandav1alpha1.RestartAnnotation)
+	if err := r.Update(ctx, redpandaCluster); err != nil {
+		return errorWithContext(err, "could not remove the restart annotation")
+	}
+	return nil
+}
+
+// reconcileRebalanceAnnotation triggers the rebalance requested through the
+// rebalance annotation and removes the annotation.
+func (r *ClusterReconciler) reconcileRebalanceAnnotation(
+	ctx context.Context,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	pki *certmanager.PkiReconciler,
+	fqdn string,
+	log logr.Logger,
+) error {
+	value, ok := redpandaCluster.Annotations[redpandav1alpha1.RebalanceAnnotation]
+	if !ok {
+		return nil
+	}
+	errorWithContext := newErrorWithContext(redpandaCluster.Namespace, redpandaCluster.Name)
+
+	switch value {
+	case redpandav1alpha1.RebalancePartitions:
+		available, err := adminutils.IsAvailableInPreFlight(ctx, r, redpandaCluster)
+		if err != nil {
+			return errorWithContext(err, "could not perform pre-flight check for admin API availability")
+		} else if !available {
+			return &resources.RequeueAfterError{
+				RequeueAfter: resources.RequeueDuration,
+				Msg:          "admin API is not available yet",
+			}
+		}
+		adminAPI, err := r.AdminAPIClientFactory(ctx, r, redpandaCluster, fqdn, pki.AdminAPIConfigProvider())
+		if err != nil {
+			return errorWithContext(err, "error creating the admin API client")
+		}
+		log.Info("Triggering a partitions rebalance through the rebalance annotation")
+		if err := adminAPI.TriggerPartitionsRebalance(ctx); err != nil {
+			return errorWithContext(err, "could not trigger the partitions rebalance")
+		}
+		r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeNormal, OperationEventReasonRebalanced,
+			"Triggered a rebalance of the %s", value)
+	default:
+		// The webhook rejects unknown values, so this only happens if it is
+		// not deployed
+		r.EventRecorder.Eventf(redpandaCluster, corev1.EventTypeWarning, OperationEventReasonInvalid,
+			"Ignoring the %s annotation: unsupported value %q", redpandav1alpha1.RebalanceAnnotation, value)
+	}
+
+	delete(redpandaCluster.Annotations, redpandav1alpha1.RebalanceAnnotation)
+	if err := r.Update(ctx, redpandaCluster); err != nil {
+		return errorWithContext(err, "could not remove the rebalance annotation")
+	}
+	return nil
+}
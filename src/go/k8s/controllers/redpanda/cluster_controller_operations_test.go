@@ -0,0 +1,66 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+var _ = Describe("RedPandaCluster operation annotations", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	Context("When annotating a cluster with one-shot operations", func() {
+		It("Should restart the brokers and trigger a rebalance", func() {
+			key, _, redpandaCluster := getInitialTestCluster("operation-annotations")
+
+			By("Allowing creation of a new cluster")
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+			var sts appsv1.StatefulSet
+			Eventually(resourceGetter(key, &sts), timeout, interval).Should(Succeed())
+
+			By("Recording the restart and removing the annotation")
+			Eventually(clusterUpdater(key, func(cl *v1alpha1.Cluster) {
+				if cl.Annotations == nil {
+					cl.Annotations = map[string]string{}
+				}
+				cl.Annotations[v1alpha1.RestartAnnotation] = "now"
+			}), timeout, interval).Should(Succeed())
+			var cluster v1alpha1.Cluster
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				_, ok := cluster.Annotations[v1alpha1.RestartAnnotation]
+				return !ok && cluster.Status.RestartedAt != nil
+			}), timeout, interval).Should(BeTrue())
+			Eventually(resourceDataGetter(key, &sts, func() interface{} {
+				return sts.Spec.Template.Annotations[resources.RestartedAtAnnotationKey]
+			}), timeout, interval).ShouldNot(BeEmpty())
+
+			By("Triggering a partitions rebalance and removing the annotation")
+			rebalances := testAdminAPI.RebalancesGetter()()
+			Eventually(clusterUpdater(key, func(cl *v1alpha1.Cluster) {
+				cl.Annotations[v1alpha1.RebalanceAnnotation] = v1alpha1.RebalancePartitions
+			}), timeout, interval).Should(Succeed())
+			Eventually(testAdminAPI.RebalancesGetter(), timeout, interval).Should(Equal(rebalances + 1))
+			Eventually(resourceDataGetter(key, &cluster, func() interface{} {
+				_, ok := cluster.Annotations[v1alpha1.RebalanceAnnotation]
+				return ok
+			}), timeout, interval).Should(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,281 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/networking"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/certmanager"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultResyncPeriod is how often the resync controller checks the
+	// clusters for out-of-band changes by default
+	DefaultResyncPeriod = 10 * time.Minute
+
+	// ResyncRequestedAnnotationKey is set on the cluster, to the time of the
+	// request, when the resync controller found drift that the cluster
+	// controller has to correct
+	ResyncRequestedAnnotationKey = "redpanda.vectorized.io/resync-requested-at"
+
+	// ResyncEventReasonDrift is the reason of the events emitted when drift is found
+	ResyncEventReasonDrift = "ResyncDriftDetected"
+)
+
+// ClusterResyncReconciler periodically checks the resources that the cluster
+// controller only creates, such as the Secrets mounted in the Redpanda pods
+// and the SCRAM users of the superusers, and requests a reconciliation of the
+// cluster when they were changed out-of-band, e.g. when a user was deleted
+// with rpk. Without it, such drift is only corrected on the next change of
+// the cluster.
+type ClusterResyncReconciler struct {
+	client.Client
+	Log                   logr.Logger
+	clusterDomain         string
+	Scheme                *runtime.Scheme
+	ResyncPeriod          time.Duration
+	AdminAPIClientFactory adminutils.AdminAPIClientFactory
+	EventRecorder         record.EventRecorder
+	clusterSelector       k8slabels.Selector
+}
+
+// Reconcile checks a cluster for drift and schedules the next check.
+func (r *ClusterResyncReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := r.Log.WithValues("redpandacluster", req.NamespacedName)
+
+	log.V(debugLogLevel).Info(fmt.Sprintf("Starting resync loop for %v", req.NamespacedName))
+	defer log.V(debugLogLevel).Info(fmt.Sprintf("Finished resync loop for %v", req.NamespacedName))
+
+	var redpandaCluster redpandav1alpha1.Cluster
+	if err := r.Get(ctx, req.NamespacedName, &redpandaCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to retrieve Cluster resource: %w", err)
+	}
+
+	if !isClusterSelected(log, r.clusterSelector, &redpandaCluster) {
+		return ctrl.Result{}, nil
+	}
+	if !isRedpandaClusterManaged(log, &redpandaCluster) || redpandaCluster.DeletionTimestamp != nil {
+		return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, nil
+	}
+
+	drift, err := r.findDrift(ctx, &redpandaCluster, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(drift) == 0 {
+		return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, nil
+	}
+
+	log.Info("Detected out-of-band changes, requesting a reconciliation of the cluster", "drift", drift)
+	r.EventRecorder.Eventf(&redpandaCluster, corev1.EventTypeWarning, ResyncEventReasonDrift,
+		"Requesting a reconciliation to correct out-of-band changes: %s", strings.Join(drift, "; "))
+
+	// Any change of the cluster triggers its reconciliation, which
+	// recreates what is missing.
+	if redpandaCluster.Annotations == nil {
+		redpandaCluster.Annotations = make(map[string]string)
+	}
+	redpandaCluster.Annotations[ResyncRequestedAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Update(ctx, &redpandaCluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not request a reconciliation of the cluster: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, nil
+}
+
+// findDrift returns a description of every out-of-band change of the cluster
+func (r *ClusterResyncReconciler) findDrift(
+	ctx context.Context, redpandaCluster *redpandav1alpha1.Cluster, log logr.Logger,
+) ([]string, error) {
+	drift, err := r.missingMountedSecrets(ctx, redpandaCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	redpandaPorts := networking.NewRedpandaPorts(redpandaCluster)
+	headlessSvc := resources.NewHeadlessService(r.Client, redpandaCluster, r.Scheme, collectHeadlessPorts(redpandaPorts), log)
+	clusterSvc := resources.NewClusterService(r.Client, redpandaCluster, r.Scheme, collectClusterPorts(redpandaPorts, redpandaCluster), log)
+
+	var superUsers []types.NamespacedName
+	if redpandaCluster.IsSASLOnInternalEnabled() && redpandaCluster.PandaproxyAPIInternal() != nil {
+		superUsers = append(superUsers, resources.NewSuperUsers(r.Client, redpandaCluster, r.Scheme, resources.ScramPandaproxyUsername, resources.PandaProxySuffix, log).Key())
+	}
+	if redpandaCluster.IsSASLOnInternalEnabled() && redpandaCluster.Spec.Configuration.SchemaRegistry != nil {
+		superUsers = append(superUsers, resources.NewSuperUsers(r.Client, redpandaCluster, r.Scheme, resources.ScramSchemaRegistryUsername, resources.SchemaRegistrySuffix, log).Key())
+	}
+	if name := redpandaCluster.AdminAPICredentialsSecretName(); name != "" {
+		superUsers = append(superUsers, types.NamespacedName{Name: name, Namespace: redpandaCluster.Namespace})
+	}
+	var usernames []string
+	for _, key := range superUsers {
+		var secret corev1.Secret
+		err := r.Get(ctx, key, &secret)
+		if apierrors.IsNotFound(err) {
+			drift = append(drift, fmt.Sprintf("superuser Secret %s is missing", key.Name))
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve superuser Secret %s: %w", key.Name, err)
+		}
+		username := string(secret.Data[corev1.BasicAuthUsernameKey])
+		if username == "" || len(secret.Data[corev1.BasicAuthPasswordKey]) == 0 {
+			drift = append(drift, fmt.Sprintf("superuser Secret %s has no credentials", key.Name))
+			continue
+		}
+		usernames = append(usernames, username)
+	}
+	if len(usernames) == 0 {
+		return drift, nil
+	}
+
+	// The users can only be checked while the cluster is available
+	if available, err := adminutils.IsAvailableInPreFlight(ctx, r, redpandaCluster); err != nil {
+		return nil, fmt.Errorf("could not perform pre-flight check for admin API availability: %w", err)
+	} else if !available {
+		return drift, nil
+	}
+	pki := certmanager.NewPki(r.Client, redpandaCluster, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), clusterSvc.ServiceFQDN(r.clusterDomain), r.Scheme, log)
+	adminAPI, err := r.AdminAPIClientFactory(ctx, r, redpandaCluster, headlessSvc.HeadlessServiceFQDN(r.clusterDomain), pki.AdminAPIConfigProvider())
+	if errors.Is(err, &adminutils.NoInternalAdminAPI{}) {
+		return drift, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not get admin API to check the users of the cluster: %w", err)
+	}
+	users, err := adminAPI.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the users of the cluster: %w", err)
+	}
+	return append(drift, missingUsers(usernames, users)...), nil
+}
+
+// missingMountedSecrets returns the Secrets that the Redpanda pods mount but
+// that do not exist anymore
+func (r *ClusterResyncReconciler) missingMountedSecrets(
+	ctx context.Context, redpandaCluster *redpandav1alpha1.Cluster,
+) ([]string, error) {
+	var sts appsv1.StatefulSet
+	err := r.Get(ctx, types.NamespacedName{Name: redpandaCluster.Name, Namespace: redpandaCluster.Namespace}, &sts)
+	if apierrors.IsNotFound(err) {
+		// Not created yet, nothing can have drifted
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to retrieve StatefulSet: %w", err)
+	}
+
+	var drift []string
+	for _, name := range mountedSecrets(&sts.Spec.Template.Spec) {
+		var secret corev1.Secret
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: redpandaCluster.Namespace}, &secret)
+		if apierrors.IsNotFound(err) {
+			drift = append(drift, fmt.Sprintf("mounted Secret %s is missing", name))
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve mounted Secret %s: %w", name, err)
+		}
+	}
+	return drift, nil
+}
+
+// mountedSecrets returns the names of the Secrets that the pod needs to start,
+// i.e. the ones of the volumes that are not optional
+func mountedSecrets(spec *corev1.PodSpec) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string, optional *bool) {
+		if name == "" || seen[name] || (optional != nil && *optional) {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for i := range spec.Volumes {
+		v := &spec.Volumes[i]
+		if v.Secret != nil {
+			add(v.Secret.SecretName, v.Secret.Optional)
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.Secret != nil {
+					add(src.Secret.Name, src.Secret.Optional)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// missingUsers returns a description of the expected users that the cluster
+// does not have
+func missingUsers(expected, actual []string) []string {
+	existing := make(map[string]bool, len(actual))
+	for _, u := range actual {
+		existing[u] = true
+	}
+	var drift []string
+	for _, u := range expected {
+		if !existing[u] {
+			drift = append(drift, fmt.Sprintf("user %s is missing", u))
+		}
+	}
+	return drift
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterResyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redpandav1alpha1.Cluster{}, builder.WithPredicates(clusterSelectorPredicate(r.clusterSelector))).
+		WithEventFilter(createOrDeleteEventFilter{}).
+		Complete(r)
+}
+
+// WithClusterDomain set the clusterDomain
+func (r *ClusterResyncReconciler) WithClusterDomain(
+	clusterDomain string,
+) *ClusterResyncReconciler {
+	r.clusterDomain = clusterDomain
+	return r
+}
+
+// WithClusterSelector restricts the reconciled clusters to the ones matching
+// the label selector
+func (r *ClusterResyncReconciler) WithClusterSelector(
+	selector k8slabels.Selector,
+) *ClusterResyncReconciler {
+	r.clusterSelector = selector
+	return r
+}
+
+func (r *ClusterResyncReconciler) getResyncPeriod() time.Duration {
+	if r.ResyncPeriod > 0 {
+		return r.ResyncPeriod
+	}
+	return DefaultResyncPeriod
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+)
+
+var _ = Describe("RedPandaCluster resync controller", func() {
+	const (
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	Context("When a superuser is deleted out-of-band", func() {
+		It("Should recreate it", func() {
+			_, _, redpandaCluster := getInitialTestCluster("resync-users")
+			redpandaCluster.Spec.EnableSASL = true
+			redpandaCluster.Spec.Configuration.PandaproxyAPI = []v1alpha1.PandaproxyAPI{{Port: 8082}}
+
+			By("Allowing creation of a new cluster")
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			By("Creating the Pandaproxy superuser")
+			Eventually(testAdminAPI.UsersGetter(), timeout, interval).Should(ContainElement(resources.ScramPandaproxyUsername))
+
+			By("Recreating the superuser after it is deleted")
+			Expect(testAdminAPI.DeleteUser(context.Background(), resources.ScramPandaproxyUsername)).To(Succeed())
+			Eventually(testAdminAPI.UsersGetter(), timeout, interval).Should(ContainElement(resources.ScramPandaproxyUsername))
+		})
+	})
+})
@@ -137,6 +137,38 @@ var _ = Describe("Redpanda cluster scale resource", func() {
 			Expect(k8sClient.Delete(context.Background(), redpandaCluster)).Should(Succeed())
 		})
 
+		It("Should not decommission nodes holding the only replicas of topics", func() {
+			By("Allowing creation of a new cluster with 3 replicas")
+			key, redpandaCluster := getClusterWithReplicas("replication-protected", 3)
+			testMaxReplicationFactors.Store(key.Name, 3)
+			Expect(k8sClient.Create(context.Background(), redpandaCluster)).Should(Succeed())
+
+			By("Scaling to 3 replicas when the brokers start to appear")
+			testAdminAPI.AddBroker(admin.Broker{NodeID: 0, MembershipStatus: admin.MembershipStatusActive})
+			testAdminAPI.AddBroker(admin.Broker{NodeID: 1, MembershipStatus: admin.MembershipStatusActive})
+			testAdminAPI.AddBroker(admin.Broker{NodeID: 2, MembershipStatus: admin.MembershipStatusActive})
+			var sts appsv1.StatefulSet
+			Eventually(resourceDataGetter(key, &sts, func() interface{} {
+				return *sts.Spec.Replicas
+			}), timeout, interval).Should(Equal(int32(3)))
+
+			By("Refusing to decommission below the replication factor of the topics")
+			Eventually(clusterUpdater(key, func(cluster *v1alpha1.Cluster) {
+				cluster.Spec.Replicas = pointer.Int32Ptr(2)
+			}), timeout, interval).Should(Succeed())
+			Consistently(testAdminAPI.BrokerStatusGetter(2), timeoutShort, intervalShort).Should(Equal(admin.MembershipStatusActive))
+
+			By("Decommissioning once the downscale is forced")
+			Eventually(clusterUpdater(key, func(cluster *v1alpha1.Cluster) {
+				cluster.Annotations = map[string]string{v1alpha1.ForceDownscaleAnnotation: "2"}
+			}), timeout, interval).Should(Succeed())
+			Eventually(testAdminAPI.BrokerStatusGetter(2), timeout, interval).Should(Equal(admin.MembershipStatusDraining))
+
+			By("Deleting the cluster")
+			Expect(k8sClient.Delete(context.Background(), redpandaCluster)).Should(Succeed())
+			testMaxReplicationFactors.Delete(key.Name)
+		})
+
 		It("Can recommission a node while decommission is in progress", func() {
 			By("Allowing creation of a new cluster with 3 replicas")
 			key, redpandaCluster := getClusterWithReplicas("recommission", 3)
@@ -713,6 +713,40 @@ var _ = Describe("RedPandaCluster controller", func() {
 		})
 	})
 
+	Context("Rendering manifests", func() {
+		It("Should render the resources of a cluster without creating them", func() {
+			key, _, redpandaCluster := getInitialTestCluster("render-manifests")
+			redpandaCluster.Spec.Configuration.KafkaAPI = append(redpandaCluster.Spec.Configuration.KafkaAPI,
+				v1alpha1.KafkaAPI{External: v1alpha1.ExternalConnectivityConfig{Enabled: true}})
+
+			r := (&redpanda.ClusterReconciler{
+				Log:    ctrl.Log,
+				Scheme: scheme.Scheme,
+			}).WithConfiguratorSettings(res.ConfiguratorSettings{
+				ConfiguratorBaseImage: "vectorized/configurator",
+				ConfiguratorTag:       "latest",
+				ImagePullPolicy:       corev1.PullIfNotPresent,
+			}).WithClusterDomain("cluster.local")
+			objs, err := r.RenderManifests(context.Background(), redpandaCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			kinds := make(map[string]int)
+			for _, obj := range objs {
+				kinds[obj.GetObjectKind().GroupVersionKind().Kind]++
+				Expect(obj.GetResourceVersion()).To(BeEmpty())
+			}
+			Expect(kinds).To(HaveKeyWithValue("StatefulSet", 1))
+			Expect(kinds).To(HaveKeyWithValue("ConfigMap", 1))
+			Expect(kinds["Service"]).To(BeNumerically(">=", 2))
+			Expect(kinds).To(HaveKey("ServiceAccount"))
+
+			By("Not creating anything in the cluster")
+			var sts appsv1.StatefulSet
+			err = k8sClient.Get(context.Background(), key, &sts)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	DescribeTable("Image pull policy tests table", func(imagePullPolicy string, matcher types2.GomegaMatcher) {
 		k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
 			Scheme:             scheme.Scheme,
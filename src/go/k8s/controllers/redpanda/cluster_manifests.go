@@ -0,0 +1,131 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cmapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// PlaceholderNodePortBase is the first node port assigned to the NodePort
+// Service by RenderManifests, where Kubernetes would allocate them.
+const PlaceholderNodePortBase = 30000
+
+// renderedLists are the kinds of resources created for a cluster, in the
+// order they are rendered.
+func renderedLists() []client.ObjectList {
+	return []client.ObjectList{
+		&corev1.ServiceAccountList{},
+		&rbacv1.ClusterRoleList{},
+		&rbacv1.ClusterRoleBindingList{},
+		&rbacv1.RoleList{},
+		&rbacv1.RoleBindingList{},
+		&corev1.SecretList{},
+		&corev1.ConfigMapList{},
+		&cmapiv1.ClusterIssuerList{},
+		&cmapiv1.IssuerList{},
+		&cmapiv1.CertificateList{},
+		&corev1.ServiceList{},
+		&netv1.IngressList{},
+		&policyv1beta1.PodDisruptionBudgetList{},
+		&batchv1.JobList{},
+		&appsv1.StatefulSetList{},
+	}
+}
+
+// RenderManifests returns the resources the reconciler would create for a new
+// cluster, without applying them: they are created in an in-memory client,
+// so that no Kubernetes API server is needed.
+//
+// Only the resources created before the brokers are started are rendered:
+// e.g. the cluster configuration applied through the admin API is not. Node
+// ports that Kubernetes would allocate are assigned from
+// PlaceholderNodePortBase, and the Secrets of the superusers contain freshly
+// generated passwords.
+func (r *ClusterReconciler) RenderManifests(
+	ctx context.Context, cluster *redpandav1alpha1.Cluster,
+) ([]client.Object, error) {
+	cluster = cluster.DeepCopy()
+	cl := fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(cluster).Build()
+
+	render := *r
+	render.Client = cl
+	crb := resources.NewClusterRoleBinding(cl, cluster, r.Scheme, r.Log)
+	cr := render.newClusterResources(cluster, crb, r.Log)
+	for _, res := range cr.toApply {
+		err := res.Ensure(ctx)
+		// Resources waiting for others, e.g. for the disk validation
+		// Job to complete, are rendered anyway.
+		var requeue *resources.RequeueAfterError
+		if err != nil && !errors.As(err, &requeue) {
+			return nil, err
+		}
+		if res == resources.Reconciler(cr.nodeportSvc) {
+			if err := assignPlaceholderNodePorts(ctx, cl, cr.nodeportSvc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var objs []client.Object
+	for _, list := range renderedLists() {
+		if err := cl.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("unable to list rendered resources: %w", err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+			if err != nil {
+				return nil, err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+			obj.SetResourceVersion("")
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+func assignPlaceholderNodePorts(
+	ctx context.Context, cl client.Client, svc *resources.NodePortServiceResource,
+) error {
+	var nodeport corev1.Service
+	if err := cl.Get(ctx, svc.Key(), &nodeport); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	for i := range nodeport.Spec.Ports {
+		if nodeport.Spec.Ports[i].NodePort == 0 {
+			nodeport.Spec.Ports[i].NodePort = int32(PlaceholderNodePortBase + i)
+		}
+	}
+	return cl.Update(ctx, &nodeport)
+}
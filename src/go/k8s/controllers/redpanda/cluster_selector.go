@@ -0,0 +1,42 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// clusterSelectorPredicate filters the events of Clusters that do not match
+// the label selector of the operator, so that several operators can share a
+// Kubernetes cluster. A nil selector selects every Cluster.
+func clusterSelectorPredicate(selector k8slabels.Selector) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return selector == nil || selector.Matches(k8slabels.Set(o.GetLabels()))
+	})
+}
+
+// isClusterSelected tells whether the Cluster is reconciled by this operator.
+// Events of owned resources are not filtered by the selector, so reconcilers
+// check it again once the Cluster is fetched.
+func isClusterSelected(
+	log logr.Logger,
+	selector k8slabels.Selector,
+	redpandaCluster *redpandav1alpha1.Cluster,
+) bool {
+	if selector == nil || selector.Matches(k8slabels.Set(redpandaCluster.Labels)) {
+		return true
+	}
+	log.V(debugLogLevel).Info("cluster does not match the cluster selector of the operator; skipping", "selector", selector.String())
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda //nolint:testpackage // needed to test private functions
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestClusterSelector(t *testing.T) {
+	cluster := func(labels map[string]string) *redpandav1alpha1.Cluster {
+		return &redpandav1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default", Labels: labels},
+		}
+	}
+	teamA, err := k8slabels.Parse("team=a")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		selector k8slabels.Selector
+		labels   map[string]string
+		expected bool
+	}{
+		{name: "no selector", selector: nil, labels: nil, expected: true},
+		{name: "matching labels", selector: teamA, labels: map[string]string{"team": "a", "env": "prod"}, expected: true},
+		{name: "other label value", selector: teamA, labels: map[string]string{"team": "b"}, expected: false},
+		{name: "no labels", selector: teamA, labels: nil, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cluster(tt.labels)
+			p := clusterSelectorPredicate(tt.selector)
+			require.Equal(t, tt.expected, p.Create(event.CreateEvent{Object: c}))
+			require.Equal(t, tt.expected, p.Update(event.UpdateEvent{ObjectOld: c, ObjectNew: c}))
+			require.Equal(t, tt.expected, p.Delete(event.DeleteEvent{Object: c}))
+			require.Equal(t, tt.expected, isClusterSelected(logr.Discard(), tt.selector, c))
+		})
+	}
+}
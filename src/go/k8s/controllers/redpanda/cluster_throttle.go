@@ -0,0 +1,188 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	deferReasonInterval = "interval"
+	deferReasonRate     = "rate"
+)
+
+var (
+	reconcilesDeferred = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "redpanda_reconciles_deferred_total",
+			Help: "Number of reconciles of healthy clusters deferred by the reconcile rate limits",
+		}, []string{"reason"},
+	)
+	reconcilesDeferredClusters = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "redpanda_reconciles_deferred_clusters",
+			Help: "Number of clusters waiting for their deferred reconcile",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcilesDeferred, reconcilesDeferredClusters)
+}
+
+// ReconcileThrottle limits how often healthy clusters are reconciled, so that
+// reconcile storms, e.g. when the operator restarts and reconciles every
+// cluster at once, do not keep it from handling the clusters that need it.
+//
+// Only the periodic resyncs of healthy clusters are throttled: they are
+// reconciled at most once per interval each, and at most at the given rate
+// overall. Clusters that are not healthy (restarting, scaling, with unready
+// brokers or with a false status condition) and clusters whose spec changed
+// since their last reconcile, i.e. whose generation changed, are never
+// throttled, so they are handled first. The depth of the work queue itself is
+// exported by controller-runtime as workqueue_depth{name="cluster"}.
+type ReconcileThrottle struct {
+	limiter  *rate.Limiter // nil if the rate is not limited
+	interval time.Duration // 0 if the clusters are not limited
+
+	mu       sync.Mutex
+	last     map[types.NamespacedName]time.Time // last admitted reconcile
+	gens     map[types.NamespacedName]int64     // generation of the last admitted reconcile
+	reserved map[types.NamespacedName]time.Time // reconciles admitted in the future
+	deferred map[types.NamespacedName]struct{}
+	now      func() time.Time
+}
+
+// NewReconcileThrottle creates a ReconcileThrottle admitting reconciles of
+// healthy clusters at the given rate per second, with the given burst, and
+// at most once per interval per cluster. A zero rate or interval disables
+// the corresponding limit.
+func NewReconcileThrottle(
+	perSecond float64, burst int, interval time.Duration,
+) *ReconcileThrottle {
+	t := &ReconcileThrottle{
+		interval: interval,
+		last:     make(map[types.NamespacedName]time.Time),
+		gens:     make(map[types.NamespacedName]int64),
+		reserved: make(map[types.NamespacedName]time.Time),
+		deferred: make(map[types.NamespacedName]struct{}),
+		now:      time.Now,
+	}
+	if perSecond > 0 {
+		if burst < 1 {
+			burst = 1
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+	}
+	return t
+}
+
+// Delay returns how long the reconcile of the cluster must be deferred, or 0
+// if it can proceed now. A nil ReconcileThrottle never defers.
+func (t *ReconcileThrottle) Delay(cluster *redpandav1alpha1.Cluster) time.Duration {
+	if t == nil {
+		return 0
+	}
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+
+	// A cluster seen for the first time, e.g. when the operator starts, is
+	// throttled: only a spec change since the last reconcile is urgent.
+	gen, seen := t.gens[key]
+	if !isClusterHealthy(cluster) || seen && gen != cluster.Generation {
+		delete(t.reserved, key)
+		return t.admit(key, cluster.Generation, now)
+	}
+
+	if t.interval > 0 {
+		if last, ok := t.last[key]; ok && now.Sub(last) < t.interval {
+			return t.deferFor(key, t.interval-now.Sub(last), deferReasonInterval)
+		}
+	}
+
+	if at, ok := t.reserved[key]; ok {
+		if now.Before(at) {
+			return at.Sub(now)
+		}
+		delete(t.reserved, key)
+		return t.admit(key, cluster.Generation, now)
+	}
+	if t.limiter != nil {
+		if d := t.limiter.ReserveN(now, 1).DelayFrom(now); d > 0 {
+			// The reservation is kept: the cluster is admitted when
+			// its reconcile comes back after the delay.
+			t.reserved[key] = now.Add(d)
+			return t.deferFor(key, d, deferReasonRate)
+		}
+	}
+	return t.admit(key, cluster.Generation, now)
+}
+
+// Forget drops the state of a deleted cluster.
+func (t *ReconcileThrottle) Forget(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.last, key)
+	delete(t.gens, key)
+	delete(t.reserved, key)
+	delete(t.deferred, key)
+	reconcilesDeferredClusters.Set(float64(len(t.deferred)))
+}
+
+func (t *ReconcileThrottle) admit(
+	key types.NamespacedName, generation int64, now time.Time,
+) time.Duration {
+	t.last[key] = now
+	t.gens[key] = generation
+	delete(t.deferred, key)
+	reconcilesDeferredClusters.Set(float64(len(t.deferred)))
+	return 0
+}
+
+func (t *ReconcileThrottle) deferFor(
+	key types.NamespacedName, d time.Duration, reason string,
+) time.Duration {
+	t.deferred[key] = struct{}{}
+	reconcilesDeferred.WithLabelValues(reason).Inc()
+	reconcilesDeferredClusters.Set(float64(len(t.deferred)))
+	return d
+}
+
+// isClusterHealthy returns whether the cluster runs all its brokers, no
+// operation is in progress and none of its status conditions is false.
+func isClusterHealthy(cluster *redpandav1alpha1.Cluster) bool {
+	if cluster.Status.IsRestarting() || cluster.Status.DecommissioningNode != nil {
+		return false
+	}
+	for i := range cluster.Status.Conditions {
+		if cluster.Status.Conditions[i].Status == corev1.ConditionFalse {
+			return false
+		}
+	}
+	if cluster.Spec.Replicas == nil {
+		return false
+	}
+	replicas := *cluster.Spec.Replicas
+	return cluster.Status.Replicas == replicas && cluster.Status.ReadyReplicas == replicas
+}
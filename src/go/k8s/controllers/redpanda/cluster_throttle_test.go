@@ -0,0 +1,94 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("ReconcileThrottle", func() {
+	cluster := func(name string, healthy bool) *v1alpha1.Cluster {
+		c := &v1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.ClusterSpec{Replicas: pointer.Int32Ptr(3)},
+			Status:     v1alpha1.ClusterStatus{Replicas: 3, ReadyReplicas: 3},
+		}
+		if !healthy {
+			c.Status.ReadyReplicas = 2
+		}
+		return c
+	}
+
+	It("Should reconcile a healthy cluster at most once per interval", func() {
+		t := redpanda.NewReconcileThrottle(0, 0, time.Hour)
+		Expect(t.Delay(cluster("a", true))).To(BeZero())
+		Expect(t.Delay(cluster("a", true))).To(BeNumerically(">", 59*time.Minute))
+		Expect(t.Delay(cluster("b", true))).To(BeZero())
+
+		By("Not throttling unhealthy clusters")
+		Expect(t.Delay(cluster("a", false))).To(BeZero())
+		Expect(t.Delay(cluster("a", false))).To(BeZero())
+
+		By("Not throttling clusters with a false condition")
+		unconfigured := cluster("a", true)
+		unconfigured.Status.Conditions = []v1alpha1.ClusterCondition{{
+			Type:   v1alpha1.ClusterConfiguredConditionType,
+			Status: corev1.ConditionFalse,
+		}}
+		Expect(t.Delay(unconfigured)).To(BeZero())
+		Expect(t.Delay(unconfigured)).To(BeZero())
+
+		By("Forgetting deleted clusters")
+		Expect(t.Delay(cluster("b", true))).NotTo(BeZero())
+		t.Forget(types.NamespacedName{Namespace: "default", Name: "b"})
+		Expect(t.Delay(cluster("b", true))).To(BeZero())
+	})
+
+	It("Should not throttle the reconcile of a spec change", func() {
+		t := redpanda.NewReconcileThrottle(0, 0, time.Hour)
+		c := cluster("a", true)
+		c.Generation = 1
+		Expect(t.Delay(c)).To(BeZero())
+		Expect(t.Delay(c)).NotTo(BeZero())
+
+		c.Generation = 2
+		Expect(t.Delay(c)).To(BeZero())
+		Expect(t.Delay(c)).NotTo(BeZero())
+	})
+
+	It("Should limit the rate of reconciles of healthy clusters", func() {
+		t := redpanda.NewReconcileThrottle(1, 1, 0)
+		Expect(t.Delay(cluster("a", true))).To(BeZero())
+		delay := t.Delay(cluster("b", true))
+		Expect(delay).To(BeNumerically(">", 0))
+		Expect(delay).To(BeNumerically("<=", time.Second))
+
+		By("Keeping the reservation of the deferred cluster")
+		Expect(t.Delay(cluster("b", true))).To(BeNumerically("<=", delay))
+		Expect(t.Delay(cluster("c", false))).To(BeZero())
+		Eventually(func() time.Duration {
+			return t.Delay(cluster("b", true))
+		}, 2*time.Second, 100*time.Millisecond).Should(BeZero())
+	})
+
+	It("Should never defer with a nil throttle", func() {
+		var t *redpanda.ReconcileThrottle
+		Expect(t.Delay(cluster("a", true))).To(BeZero())
+	})
+})
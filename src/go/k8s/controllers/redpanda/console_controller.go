@@ -17,6 +17,7 @@ import (
 	"github.com/go-logr/logr"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
 	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
 	appsv1 "k8s.io/api/apps/v1"
@@ -38,6 +39,9 @@ type ConsoleReconciler struct {
 	Store                   *consolepkg.Store
 	EventRecorder           record.EventRecorder
 	KafkaAdminClientFactory consolepkg.KafkaAdminClientFactory
+	// Capabilities of the Kubernetes cluster, the latest APIs are assumed
+	// if unset
+	Capabilities *capabilities.Capabilities
 }
 
 const (
@@ -152,6 +156,7 @@ func (r *Reconciling) Do(
 
 	// Ingress with TLS and "/debug" "/admin" paths disabled
 	ingressResource := resources.NewIngress(r.Client, console, r.Scheme, subdomain, console.GetName(), consolepkg.ServicePortName, log)
+	ingressResource = ingressResource.WithCapabilities(r.Capabilities)
 	ingressResource = ingressResource.WithTLS(resources.LEClusterIssuer, fmt.Sprintf("%s-redpanda", cluster.GetName()))
 	ingressResource = ingressResource.WithAnnotations(map[string]string{
 		"nginx.ingress.kubernetes.io/server-snippet": "if ($request_uri ~* ^/(debug|admin)) {\n\treturn 403;\n\t}",
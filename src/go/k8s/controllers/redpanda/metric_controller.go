@@ -46,11 +46,17 @@ var (
 			Help: "Number of Redpanda clusters having configuration problems",
 		}, []string{"reason"},
 	)
+	licenseExpiration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redpanda_license_expiration_timestamp_seconds",
+			Help: "Expiration time of the license loaded in the Redpanda cluster, in seconds since the epoch",
+		}, []string{"cluster"},
+	)
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(redpandaClusters, desireRedpandaNodes, actualRedpandaNodes, misconfiguredClusters)
+	metrics.Registry.MustRegister(redpandaClusters, desireRedpandaNodes, actualRedpandaNodes, misconfiguredClusters, licenseExpiration)
 }
 
 // ClusterMetricController provides metrics for nodes and cluster
@@ -95,6 +101,16 @@ func (r *ClusterMetricController) Reconcile(
 			return ctrl.Result{}, err
 		}
 		g.Set(float64(cl.Items[i].Status.ReadyReplicas))
+
+		if license := cl.Items[i].Status.License; license != nil && license.Expiration != nil {
+			g, err = licenseExpiration.GetMetricWithLabelValues(cl.Items[i].Name)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			g.Set(float64(license.Expiration.Unix()))
+		} else {
+			licenseExpiration.DeleteLabelValues(cl.Items[i].Name)
+		}
 		curLabels[cl.Items[i].Name] = struct{}{}
 		r.currentLabels[cl.Items[i].Name] = struct{}{}
 	}
@@ -104,6 +120,7 @@ func (r *ClusterMetricController) Reconcile(
 		if !exist {
 			desireRedpandaNodes.DeleteLabelValues(key)
 			actualRedpandaNodes.DeleteLabelValues(key)
+			licenseExpiration.DeleteLabelValues(key)
 		}
 	}
 
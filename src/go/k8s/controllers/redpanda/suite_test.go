@@ -12,8 +12,11 @@ package redpanda_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"path/filepath"
 	"sort"
@@ -55,8 +58,20 @@ var (
 	testStore             *consolepkg.Store
 	testKafkaAdmin        *mockKafkaAdmin
 	testKafkaAdminFactory consolepkg.KafkaAdminClientFactory
+	// testMaxReplicationFactors holds the replication factor of the topics
+	// of the clusters, by name; it defaults to 1
+	testMaxReplicationFactors sync.Map
 )
 
+func testMaxReplicationFactor(
+	_ context.Context, _ client.Client, cluster *redpandav1alpha1.Cluster,
+) (int, error) {
+	if rf, ok := testMaxReplicationFactors.Load(cluster.Name); ok {
+		return rf.(int), nil
+	}
+	return 1, nil
+}
+
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -120,7 +135,9 @@ var _ = BeforeSuite(func(done Done) {
 		Log:                      ctrl.Log.WithName("controllers").WithName("core").WithName("RedpandaCluster"),
 		Scheme:                   k8sManager.GetScheme(),
 		AdminAPIClientFactory:    testAdminAPIFactory,
+		MaxReplicationFactor:     testMaxReplicationFactor,
 		DecommissionWaitInterval: 100 * time.Millisecond,
+		EventRecorder:            k8sManager.GetEventRecorderFor("Cluster"),
 	}).WithClusterDomain("cluster.local").WithConfiguratorSettings(resources.ConfiguratorSettings{
 		ConfiguratorBaseImage: "vectorized/configurator",
 		ConfiguratorTag:       "latest",
@@ -138,6 +155,16 @@ var _ = BeforeSuite(func(done Done) {
 	}).WithClusterDomain("cluster.local").SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	err = (&redpandacontrollers.ClusterResyncReconciler{
+		Client:                k8sManager.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("core").WithName("RedpandaCluster"),
+		Scheme:                k8sManager.GetScheme(),
+		AdminAPIClientFactory: testAdminAPIFactory,
+		ResyncPeriod:          500 * time.Millisecond,
+		EventRecorder:         k8sManager.GetEventRecorderFor("Cluster"),
+	}).WithClusterDomain("cluster.local").SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&redpandacontrollers.ConsoleReconciler{
 		Client:                  k8sManager.GetClient(),
 		Scheme:                  k8sManager.GetScheme(),
@@ -196,6 +223,11 @@ type mockAdminAPI struct {
 	unknown          []string
 	directValidation bool
 	brokers          []admin.Broker
+	license          []byte
+	loggerLevels     map[string]string
+	rebalances       int
+	storageStats     admin.CloudStorageStats
+	users            map[string]bool
 	monitor          sync.Mutex
 }
 
@@ -311,16 +343,75 @@ func (m *mockAdminAPI) PatchClusterConfig(
 	return admin.ClusterConfigWriteResult{}, nil
 }
 
-func (m *mockAdminAPI) CreateUser(_ context.Context, _, _, _ string) error {
+func (m *mockAdminAPI) PatchClusterConfigAtVersion(
+	ctx context.Context, upsert map[string]interface{}, remove []string, _ int,
+) (admin.ClusterConfigWriteResult, error) {
+	// the mock does not track configuration versions
+	return m.PatchClusterConfig(ctx, upsert, remove)
+}
+
+//nolint:goerr113 // test code
+func (m *mockAdminAPI) CreateUser(_ context.Context, username, _, _ string) error {
 	m.monitor.Lock()
 	defer m.monitor.Unlock()
 	if m.unavailable {
 		return &unavailableError{}
 	}
+	if m.users[username] {
+		return fmt.Errorf("Creating user: User already exists")
+	}
+	if m.users == nil {
+		m.users = make(map[string]bool)
+	}
+	m.users[username] = true
 	return nil
 }
 
-func (m *mockAdminAPI) DeleteUser(_ context.Context, _ string) error {
+func (m *mockAdminAPI) DeleteUser(_ context.Context, username string) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return &unavailableError{}
+	}
+	delete(m.users, username)
+	return nil
+}
+
+func (m *mockAdminAPI) ListUsers(_ context.Context) ([]string, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return nil, &unavailableError{}
+	}
+	users := make([]string, 0, len(m.users))
+	for u := range m.users {
+		users = append(users, u)
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+func (m *mockAdminAPI) UsersGetter() func() []string {
+	return func() []string {
+		users, _ := m.ListUsers(context.Background())
+		return users
+	}
+}
+
+func (m *mockAdminAPI) BatchCreateUsers(
+	_ context.Context, _ []admin.UserCredentials, _ int,
+) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return &unavailableError{}
+	}
+	return nil
+}
+
+func (m *mockAdminAPI) BatchDeleteUsers(
+	_ context.Context, _ []string, _ int,
+) error {
 	m.monitor.Lock()
 	defer m.monitor.Unlock()
 	if m.unavailable {
@@ -338,6 +429,11 @@ func (m *mockAdminAPI) Clear() {
 	m.unavailable = false
 	m.directValidation = false
 	m.brokers = nil
+	m.license = nil
+	m.loggerLevels = nil
+	m.rebalances = 0
+	m.storageStats = admin.CloudStorageStats{}
+	m.users = nil
 }
 
 func (m *mockAdminAPI) GetFeatures(
@@ -355,6 +451,46 @@ func (m *mockAdminAPI) GetFeatures(
 	}, nil
 }
 
+func (m *mockAdminAPI) GetLicenseInfo(_ context.Context) (admin.License, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return admin.License{}, &unavailableError{}
+	}
+	if m.license == nil {
+		return admin.License{}, nil
+	}
+	sum := sha256.Sum256(m.license)
+	return admin.License{
+		Loaded: true,
+		Properties: admin.LicenseProperties{
+			Organization: "redpanda",
+			Type:         "enterprise",
+			Expires:      time.Now().Add(365 * 24 * time.Hour).Unix(),
+			Checksum:     hex.EncodeToString(sum[:]),
+		},
+	}, nil
+}
+
+//nolint:goerr113 // test code
+func (m *mockAdminAPI) SetLicense(_ context.Context, license interface{}) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return &unavailableError{}
+	}
+	r, ok := license.(io.Reader)
+	if !ok {
+		return fmt.Errorf("unexpected license type %T", license)
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.license = raw
+	return nil
+}
+
 //nolint:gocritic // It's test API
 func (m *mockAdminAPI) RegisterPropertySchema(
 	name string, metadata admin.ConfigPropertyMetadata,
@@ -508,6 +644,101 @@ func (m *mockAdminAPI) DisableMaintenanceMode(_ context.Context, _ int) error {
 	return nil
 }
 
+func (m *mockAdminAPI) WaitForDrain(
+	_ context.Context, _ int,
+) (admin.MaintenanceStatus, error) {
+	return admin.MaintenanceStatus{Draining: true, Finished: true}, nil
+}
+
+func (m *mockAdminAPI) SetLoggerLevels(
+	_ context.Context, levels map[string]string, _ time.Duration,
+) map[string]error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		failures := make(map[string]error, len(levels))
+		for logger := range levels {
+			failures[logger] = &unavailableError{}
+		}
+		return failures
+	}
+	if m.loggerLevels == nil {
+		m.loggerLevels = make(map[string]string)
+	}
+	for logger, level := range levels {
+		m.loggerLevels[logger] = level
+	}
+	return nil
+}
+
+func (m *mockAdminAPI) LoggerLevelGetter(logger string) func() string {
+	return func() string {
+		m.monitor.Lock()
+		defer m.monitor.Unlock()
+		return m.loggerLevels[logger]
+	}
+}
+
+func (m *mockAdminAPI) TriggerPartitionsRebalance(_ context.Context) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return &unavailableError{}
+	}
+	m.rebalances++
+	return nil
+}
+
+func (m *mockAdminAPI) RebalancesGetter() func() int {
+	return func() int {
+		m.monitor.Lock()
+		defer m.monitor.Unlock()
+		return m.rebalances
+	}
+}
+
+func (m *mockAdminAPI) GetHealthOverview(
+	_ context.Context,
+) (admin.ClusterHealthOverview, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return admin.ClusterHealthOverview{}, &unavailableError{}
+	}
+	return admin.ClusterHealthOverview{IsHealthy: true}, nil
+}
+
+func (m *mockAdminAPI) CloudStorageStats(
+	_ context.Context,
+) (admin.CloudStorageStats, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.unavailable {
+		return admin.CloudStorageStats{}, &unavailableError{}
+	}
+	return m.storageStats, nil
+}
+
+func (m *mockAdminAPI) SetCloudStorageStats(stats admin.CloudStorageStats) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.storageStats = stats
+}
+
+func (m *mockAdminAPI) CheckClusterStability(
+	ctx context.Context, opts admin.StabilityOptions,
+) (admin.StabilityVerdict, error) {
+	health, err := m.GetHealthOverview(ctx)
+	if err != nil {
+		return admin.StabilityVerdict{}, err
+	}
+	brokers, err := m.Brokers(ctx)
+	if err != nil {
+		return admin.StabilityVerdict{}, err
+	}
+	return admin.EvaluateClusterStability(health, brokers, nil, opts), nil
+}
+
 //nolint:goerr113 // test code
 func (m *mockAdminAPI) SetBrokerStatus(
 	id int, status admin.MembershipStatus,
@@ -9,23 +9,39 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 	"time"
+	// Embed the time zone database, so that the time zones of the
+	// maintenance windows do not depend on the operator image
+	_ "time/tzdata"
 
+	"github.com/go-logr/logr"
 	cmapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	redpandav1alpha2 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha2"
 	redpandacontrollers "github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
 	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
 	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
 	redpandawebhooks "github.com/redpanda-data/redpanda/src/go/k8s/webhooks/redpanda"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -44,12 +60,23 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(redpandav1alpha1.AddToScheme(scheme))
+	// The conversion webhook of the clusters is registered with the
+	// webhook of the v1alpha1 hub, for the versions in the scheme
+	utilruntime.Must(redpandav1alpha2.AddToScheme(scheme))
 	utilruntime.Must(cmapiv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 //nolint:funlen // length looks good
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "manifests" {
+		if err := runManifests(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var (
 		clusterDomain               string
 		metricsAddr                 string
@@ -60,6 +87,14 @@ func main() {
 		configuratorTag             string
 		configuratorImagePullPolicy string
 		decommissionWaitInterval    time.Duration
+		resyncPeriod                time.Duration
+		watchNamespaces             string
+		clusterSelector             string
+		auditLog                    bool
+		maxConcurrentReconciles     int
+		reconcileRate               float64
+		reconcileBurst              int
+		clusterReconcileInterval    time.Duration
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -73,6 +108,14 @@ func main() {
 	flag.StringVar(&configuratorTag, "configurator-tag", "latest", "Set the configurator tag")
 	flag.StringVar(&configuratorImagePullPolicy, "configurator-image-pull-policy", "Always", "Set the configurator image pull policy")
 	flag.DurationVar(&decommissionWaitInterval, "decommission-wait-interval", 8*time.Second, "Set the time to wait for a node decommission to happen in the cluster")
+	flag.DurationVar(&resyncPeriod, "resync-period", redpandacontrollers.DefaultResyncPeriod, "How often clusters are checked for out-of-band changes of their users and mounted Secrets, which are then corrected; 0 disables the checks")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated list of namespaces to watch; all namespaces are watched if empty")
+	flag.StringVar(&clusterSelector, "cluster-selector", "", "Only reconcile the Clusters matching this label selector (e.g. team=a,env!=dev)")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "Number of clusters reconciled in parallel")
+	flag.Float64Var(&reconcileRate, "reconcile-rate", 10, "Reconciles of healthy clusters admitted per second, across all clusters; clusters that are not healthy are never limited; 0 disables the limit")
+	flag.IntVar(&reconcileBurst, "reconcile-burst", 20, "Reconciles of healthy clusters admitted at once, on top of --reconcile-rate")
+	flag.DurationVar(&clusterReconcileInterval, "cluster-reconcile-interval", 5*time.Second, "Minimum interval between two reconciles of the same healthy cluster; 0 disables the limit")
+	flag.BoolVar(&auditLog, "audit-log", false, "Log a structured entry for each mutating operation on a cluster, on top of the audit events")
 	flag.BoolVar(&redpandav1alpha1.AllowDownscalingInWebhook, "allow-downscaling", false, "Allow to reduce the number of replicas in existing clusters (alpha feature)")
 	flag.BoolVar(&redpandav1alpha1.AllowConsoleAnyNamespace, "allow-console-any-ns", false, "Allow to create Console in any namespace. Allowing this copies Redpanda SchemaRegistry TLS Secret to namespace (alpha feature)")
 
@@ -86,32 +129,68 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	selector, err := k8slabels.Parse(clusterSelector)
+	if err != nil {
+		setupLog.Error(err, "Invalid cluster selector", "selector", clusterSelector)
+		os.Exit(1)
+	}
+
+	mgrOptions := ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "aa9fc693.vectorized.io",
-	})
+	}
+	if namespaces := splitNamespaces(watchNamespaces); len(namespaces) > 0 {
+		setupLog.Info("Watching namespaces", "namespaces", namespaces)
+		watchOnly(&mgrOptions, namespaces)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
 	if err != nil {
 		setupLog.Error(err, "Unable to start manager")
 		os.Exit(1)
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "Unable to create the Kubernetes clientset")
+		os.Exit(1)
+	}
+
+	k8sCapabilities, err := detectCapabilities(mgr.GetConfig())
+	if err != nil {
+		// The operator still works on up to date clusters
+		setupLog.Error(err, "Unable to detect the Kubernetes capabilities, assuming the latest APIs")
+	}
+
 	configurator := resources.ConfiguratorSettings{
 		ConfiguratorBaseImage: configuratorBaseImage,
 		ConfiguratorTag:       configuratorTag,
 		ImagePullPolicy:       corev1.PullPolicy(configuratorImagePullPolicy),
 	}
 
+	var auditLogger logr.Logger
+	if auditLog {
+		auditLogger = ctrl.Log.WithName("audit")
+	}
+
 	if err = (&redpandacontrollers.ClusterReconciler{
 		Client:                   mgr.GetClient(),
 		Log:                      ctrl.Log.WithName("controllers").WithName("redpanda").WithName("Cluster"),
 		Scheme:                   mgr.GetScheme(),
 		AdminAPIClientFactory:    adminutils.NewInternalAdminAPI,
+		MaxReplicationFactor:     resources.MaxReplicationFactor,
 		DecommissionWaitInterval: decommissionWaitInterval,
-	}).WithClusterDomain(clusterDomain).WithConfiguratorSettings(configurator).SetupWithManager(mgr); err != nil {
+		EventRecorder:            mgr.GetEventRecorderFor("Cluster"),
+		AuditLogger:              auditLogger,
+		PodEvictor:               resources.NewPodEvictor(clientset),
+		Throttle:                 redpandacontrollers.NewReconcileThrottle(reconcileRate, reconcileBurst, clusterReconcileInterval),
+		MaxConcurrentReconciles:  maxConcurrentReconciles,
+		Capabilities:             k8sCapabilities,
+	}).WithClusterDomain(clusterDomain).WithConfiguratorSettings(configurator).WithClusterSelector(selector).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
@@ -121,11 +200,25 @@ func main() {
 		Log:                   ctrl.Log.WithName("controllers").WithName("redpanda").WithName("ClusterConfigurationDrift"),
 		Scheme:                mgr.GetScheme(),
 		AdminAPIClientFactory: adminutils.NewInternalAdminAPI,
-	}).WithClusterDomain(clusterDomain).SetupWithManager(mgr); err != nil {
+	}).WithClusterDomain(clusterDomain).WithClusterSelector(selector).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Unable to create controller", "controller", "ClusterConfigurationDrift")
 		os.Exit(1)
 	}
 
+	if resyncPeriod > 0 {
+		if err = (&redpandacontrollers.ClusterResyncReconciler{
+			Client:                mgr.GetClient(),
+			Log:                   ctrl.Log.WithName("controllers").WithName("redpanda").WithName("ClusterResync"),
+			Scheme:                mgr.GetScheme(),
+			ResyncPeriod:          resyncPeriod,
+			AdminAPIClientFactory: adminutils.NewInternalAdminAPI,
+			EventRecorder:         mgr.GetEventRecorderFor("Cluster"),
+		}).WithClusterDomain(clusterDomain).WithClusterSelector(selector).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "Unable to create controller", "controller", "ClusterResync")
+			os.Exit(1)
+		}
+	}
+
 	if err = redpandacontrollers.NewClusterMetricsController(mgr.GetClient()).
 		SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Unable to create controller", "controller", "ClustersMetrics")
@@ -135,6 +228,9 @@ func main() {
 	// Setup webhooks
 	if webhookEnabled {
 		setupLog.Info("Setup webhook")
+		redpandav1alpha1.MaxReplicationFactorInWebhook = func(ctx context.Context, cluster *redpandav1alpha1.Cluster) (int, error) {
+			return resources.MaxReplicationFactor(ctx, mgr.GetClient(), cluster)
+		}
 		if err = (&redpandav1alpha1.Cluster{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "Unable to create webhook", "webhook", "RedpandaCluster")
 			os.Exit(1)
@@ -152,6 +248,7 @@ func main() {
 		Store:                   consolepkg.NewStore(mgr.GetClient()),
 		EventRecorder:           mgr.GetEventRecorderFor("Console"),
 		KafkaAdminClientFactory: consolepkg.NewKafkaAdmin,
+		Capabilities:            k8sCapabilities,
 	}).WithClusterDomain(clusterDomain).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Console")
 		os.Exit(1)
@@ -187,3 +284,43 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// watchOnly restricts the cache of the manager to the namespaces, so that
+// the resources of the other namespaces are neither watched nor reconciled.
+func watchOnly(opts *ctrl.Options, namespaces []string) {
+	opts.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+	// The operator is not allowed to watch cluster scoped resources
+	// through a namespaced cache, they are read from the API server.
+	opts.ClientDisableCacheFor = []client.Object{
+		&corev1.Node{},
+		&rbacv1.ClusterRole{},
+		&rbacv1.ClusterRoleBinding{},
+		&cmapiv1.ClusterIssuer{},
+	}
+}
+
+// splitNamespaces parses the comma separated list of --watch-namespaces.
+func splitNamespaces(namespaces string) []string {
+	var out []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			out = append(out, ns)
+		}
+	}
+	return out
+}
+
+// detectCapabilities detects the APIs served by the Kubernetes cluster, and
+// reports the features of the operator that degrade on it.
+func detectCapabilities(cfg *rest.Config) (*capabilities.Capabilities, error) {
+	d, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the discovery client: %w", err)
+	}
+	c, err := capabilities.Detect(d)
+	if err != nil {
+		return nil, err
+	}
+	c.Report(setupLog)
+	return c, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	redpandacontrollers "github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+func TestSplitNamespaces(t *testing.T) {
+	require.Empty(t, splitNamespaces(""))
+	require.Equal(t, []string{"a"}, splitNamespaces("a"))
+	require.Equal(t, []string{"a", "b"}, splitNamespaces(" a, ,b ,"))
+}
+
+// TestWatchScope runs the Cluster controller as main does with
+// --watch-namespaces=watched and --cluster-selector=team=a, and checks that
+// only the matching Clusters of the watched namespace are reconciled, which
+// is when the cleanup finalizer is added to them.
+func TestWatchScope(t *testing.T) {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join("config", "crd", "bases")},
+	}
+	cfg, err := testEnv.Start()
+	require.NoError(t, err)
+	defer testEnv.Stop() //nolint:errcheck // best effort
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, ns := range []string{"watched", "unwatched"} {
+		require.NoError(t, c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}))
+	}
+
+	mgrOptions := ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+	}
+	watchOnly(&mgrOptions, []string{"watched"})
+	mgr, err := ctrl.NewManager(cfg, mgrOptions)
+	require.NoError(t, err)
+
+	selector, err := k8slabels.Parse("team=a")
+	require.NoError(t, err)
+	err = (&redpandacontrollers.ClusterReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("redpanda").WithName("Cluster"),
+		Scheme: mgr.GetScheme(),
+		AdminAPIClientFactory: func(
+			context.Context, client.Reader, *redpandav1alpha1.Cluster, string, types.AdminTLSConfigProvider, ...int32,
+		) (adminutils.AdminAPIClient, error) {
+			return nil, errors.New("no admin API in this test")
+		},
+		MaxReplicationFactor: func(context.Context, client.Client, *redpandav1alpha1.Cluster) (int, error) {
+			return 1, nil
+		},
+		DecommissionWaitInterval: 100 * time.Millisecond,
+		EventRecorder:            mgr.GetEventRecorderFor("Cluster"),
+	}).WithClusterDomain("cluster.local").WithConfiguratorSettings(resources.ConfiguratorSettings{
+		ConfiguratorBaseImage: defaultConfiguratorContainerImage,
+		ConfiguratorTag:       "latest",
+		ImagePullPolicy:       corev1.PullIfNotPresent,
+	}).WithClusterSelector(selector).SetupWithManager(mgr)
+	require.NoError(t, err)
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("unable to start the manager: %v", err)
+		}
+	}()
+
+	selected := testCluster("selected", "watched", "a")
+	otherTeam := testCluster("other-team", "watched", "b")
+	unwatched := testCluster("unwatched", "unwatched", "a")
+	for _, cluster := range []*redpandav1alpha1.Cluster{selected, otherTeam, unwatched} {
+		require.NoError(t, c.Create(ctx, cluster))
+	}
+
+	hasFinalizer := func(cluster *redpandav1alpha1.Cluster) bool {
+		var got redpandav1alpha1.Cluster
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cluster), &got))
+		return controllerutil.ContainsFinalizer(&got, redpandacontrollers.ClusterCleanupFinalizer)
+	}
+	require.Eventually(t, func() bool { return hasFinalizer(selected) }, 20*time.Second, 100*time.Millisecond,
+		"the matching cluster of the watched namespace is reconciled")
+	require.Never(t, func() bool { return hasFinalizer(otherTeam) || hasFinalizer(unwatched) }, 2*time.Second, 100*time.Millisecond,
+		"clusters not matching the selector or in other namespaces are not reconciled")
+}
+
+func testCluster(name, namespace, team string) *redpandav1alpha1.Cluster {
+	return &redpandav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"team": team},
+		},
+		Spec: redpandav1alpha1.ClusterSpec{
+			Image:    "vectorized/redpanda",
+			Version:  "v22.2.1",
+			Replicas: pointer.Int32Ptr(1),
+			Configuration: redpandav1alpha1.RedpandaConfig{
+				KafkaAPI: []redpandav1alpha1.KafkaAPI{{Port: 9092}},
+				AdminAPI: []redpandav1alpha1.AdminAPI{{Port: 9644}},
+			},
+			Resources: redpandav1alpha1.RedpandaResourceRequirements{
+				ResourceRequirements: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	redpandacontrollers "github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const manifestsUsage = `Usage: manager manifests generate -f CLUSTER.yaml [flags]
+
+Render the resources the operator would create for a Cluster to stdout, as
+YAML, without applying them.
+`
+
+// runManifests runs the manifests command, whose args are the arguments that
+// follow "manifests" on the command line.
+func runManifests(args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "generate" {
+		return errors.New(manifestsUsage)
+	}
+	var (
+		file                        string
+		clusterDomain               string
+		configuratorBaseImage       string
+		configuratorTag             string
+		configuratorImagePullPolicy string
+	)
+	fs := flag.NewFlagSet("manifests generate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), manifestsUsage+"\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&file, "f", "", "File of the Cluster custom resource, - for stdin")
+	fs.StringVar(&clusterDomain, "cluster-domain", "cluster.local", "Set the Kubernetes local domain (Kubelet's --cluster-domain)")
+	fs.StringVar(&configuratorBaseImage, "configurator-base-image", defaultConfiguratorContainerImage, "Set the configurator base image")
+	fs.StringVar(&configuratorTag, "configurator-tag", "latest", "Set the configurator tag")
+	fs.StringVar(&configuratorImagePullPolicy, "configurator-image-pull-policy", "Always", "Set the configurator image pull policy")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if file == "" {
+		fs.Usage()
+		return errors.New("missing -f")
+	}
+
+	var raw []byte
+	var err error
+	if file == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the Cluster: %w", err)
+	}
+	var cluster redpandav1alpha1.Cluster
+	if _, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(raw, nil, &cluster); err != nil {
+		return fmt.Errorf("unable to decode the Cluster: %w", err)
+	}
+	if cluster.Namespace == "" {
+		cluster.Namespace = corev1.NamespaceDefault
+	}
+	// The defaults of the mutating webhook are applied, as they would be
+	// when the Cluster is created.
+	cluster.Default()
+
+	r := (&redpandacontrollers.ClusterReconciler{
+		Log:    ctrl.Log.WithName("manifests"),
+		Scheme: scheme,
+	}).WithClusterDomain(clusterDomain).WithConfiguratorSettings(resources.ConfiguratorSettings{
+		ConfiguratorBaseImage: configuratorBaseImage,
+		ConfiguratorTag:       configuratorTag,
+		ImagePullPolicy:       corev1.PullPolicy(configuratorImagePullPolicy),
+	})
+	objs, err := r.RenderManifests(context.Background(), &cluster)
+	if err != nil {
+		return fmt.Errorf("unable to render the resources of the Cluster: %w", err)
+	}
+
+	yaml := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme, scheme, json.SerializerOptions{Yaml: true})
+	for _, obj := range objs {
+		if _, err := fmt.Fprintln(stdout, "---"); err != nil {
+			return err
+		}
+		if err := yaml.Encode(obj, stdout); err != nil {
+			return fmt.Errorf("unable to encode %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -12,15 +12,21 @@ package admin
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
+	"time"
 
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/types"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ErrNoCredentials is returned when the Secret of the admin API credentials
+// of the operator has no username or password
+var ErrNoCredentials = errors.New("the Secret must have a username and a password")
+
 // NoInternalAdminAPI signal absence of the internal admin API endpoint
 type NoInternalAdminAPI struct{}
 
@@ -29,7 +35,8 @@ func (n *NoInternalAdminAPI) Error() string {
 }
 
 // NewInternalAdminAPI is used to construct an admin API client that talks to the cluster via
-// the internal interface.
+// the internal interface. If the admin API requires authentication, the client
+// authenticates as the operator.
 func NewInternalAdminAPI(
 	ctx context.Context,
 	k8sClient client.Reader,
@@ -38,42 +45,54 @@ func NewInternalAdminAPI(
 	adminTLSProvider types.AdminTLSConfigProvider,
 	ordinals ...int32,
 ) (AdminAPIClient, error) {
-	adminInternal := redpandaCluster.AdminAPIInternal()
-	if adminInternal == nil {
-		return nil, &NoInternalAdminAPI{}
+	urls, err := BrokerAdminURLs(redpandaCluster, fqdn, ordinals...)
+	if err != nil {
+		return nil, err
 	}
-
-	var tlsConfig *tls.Config
-	if adminInternal.TLS.Enabled {
-		var err error
-		tlsConfig, err = adminTLSProvider.GetTLSConfig(ctx, k8sClient)
-		if err != nil {
-			return nil, fmt.Errorf("could not create tls configuration for internal admin API: %w", err)
-		}
+	tlsConfig, err := AdminTLSConfig(ctx, k8sClient, redpandaCluster, adminTLSProvider)
+	if err != nil {
+		return nil, err
 	}
 
-	adminInternalPort := adminInternal.Port
-
-	if len(ordinals) == 0 {
-		// Not a specific node, just go through all them
-		replicas := redpandaCluster.GetCurrentReplicas()
-
-		for i := int32(0); i < replicas; i++ {
-			ordinals = append(ordinals, i)
-		}
-	}
-	urls := make([]string, 0, len(ordinals))
-	for _, on := range ordinals {
-		urls = append(urls, fmt.Sprintf("%s-%d.%s:%d", redpandaCluster.Name, on, fqdn, adminInternalPort))
+	creds, err := Credentials(ctx, k8sClient, redpandaCluster)
+	if err != nil {
+		return nil, err
 	}
 
-	adminAPI, err := admin.NewAdminAPI(urls, admin.BasicCredentials{}, tlsConfig)
+	adminAPI, err := admin.NewAdminAPI(urls, creds, tlsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error creating admin api for cluster %s/%s using urls %v (tls=%v): %w", redpandaCluster.Namespace, redpandaCluster.Name, urls, tlsConfig != nil, err)
 	}
 	return adminAPI, nil
 }
 
+// Credentials returns the credentials the operator authenticates to the admin
+// API with, from the Secret referenced by the cluster, or empty credentials if
+// the admin API does not require authentication.
+func Credentials(
+	ctx context.Context,
+	k8sClient client.Reader,
+	redpandaCluster *redpandav1alpha1.Cluster,
+) (admin.BasicCredentials, error) {
+	name := redpandaCluster.AdminAPICredentialsSecretName()
+	if name == "" {
+		return admin.BasicCredentials{}, nil
+	}
+	var secret corev1.Secret
+	key := client.ObjectKey{Name: name, Namespace: redpandaCluster.Namespace}
+	if err := k8sClient.Get(ctx, key, &secret); err != nil {
+		return admin.BasicCredentials{}, fmt.Errorf("unable to get the admin API credentials Secret %s: %w", key, err)
+	}
+	creds := admin.BasicCredentials{
+		Username: string(secret.Data[corev1.BasicAuthUsernameKey]),
+		Password: string(secret.Data[corev1.BasicAuthPasswordKey]),
+	}
+	if creds.Username == "" || creds.Password == "" {
+		return admin.BasicCredentials{}, fmt.Errorf("admin API credentials Secret %s: %w", key, ErrNoCredentials)
+	}
+	return creds, nil
+}
+
 // AdminAPIClient is a sub interface of the admin API containing what we need in the operator
 //
 
@@ -82,12 +101,21 @@ type AdminAPIClient interface {
 	ClusterConfigStatus(ctx context.Context, sendToLeader bool) (admin.ConfigStatusResponse, error)
 	ClusterConfigSchema(ctx context.Context) (admin.ConfigSchema, error)
 	PatchClusterConfig(ctx context.Context, upsert map[string]interface{}, remove []string) (admin.ClusterConfigWriteResult, error)
+	PatchClusterConfigAtVersion(ctx context.Context, upsert map[string]interface{}, remove []string, version int) (admin.ClusterConfigWriteResult, error)
 	GetNodeConfig(ctx context.Context) (admin.NodeConfig, error)
 
+	ListUsers(ctx context.Context) ([]string, error)
 	CreateUser(ctx context.Context, username, password, mechanism string) error
 	DeleteUser(ctx context.Context, username string) error
+	BatchCreateUsers(ctx context.Context, users []admin.UserCredentials, concurrency int) error
+	BatchDeleteUsers(ctx context.Context, usernames []string, concurrency int) error
 
 	GetFeatures(ctx context.Context) (admin.FeaturesResponse, error)
+	GetLicenseInfo(ctx context.Context) (admin.License, error)
+	SetLicense(ctx context.Context, license interface{}) error
+	GetHealthOverview(ctx context.Context) (admin.ClusterHealthOverview, error)
+	CheckClusterStability(ctx context.Context, opts admin.StabilityOptions) (admin.StabilityVerdict, error)
+	TriggerPartitionsRebalance(ctx context.Context) error
 
 	Brokers(ctx context.Context) ([]admin.Broker, error)
 	DecommissionBroker(ctx context.Context, node int) error
@@ -95,9 +123,19 @@ type AdminAPIClient interface {
 
 	EnableMaintenanceMode(ctx context.Context, node int) error
 	DisableMaintenanceMode(ctx context.Context, node int) error
+	WaitForDrain(ctx context.Context, node int) (admin.MaintenanceStatus, error)
+
+	SetLoggerLevels(ctx context.Context, levels map[string]string, expiry time.Duration) map[string]error
+
+	CloudStorageStats(ctx context.Context) (admin.CloudStorageStats, error)
 }
 
-var _ AdminAPIClient = &admin.AdminAPI{}
+var (
+	_ AdminAPIClient = &admin.AdminAPI{}
+	// Any client of the admin API, such as the fake of the admintest
+	// package, can be used by the operator
+	_ AdminAPIClient = admin.AdminAPIClient(nil)
+)
 
 // AdminAPIClientFactory is an abstract constructor of admin API clients
 //
@@ -0,0 +1,61 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCredentials(t *testing.T) {
+	ctx := context.Background()
+	cluster := &redpandav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator-credentials", Namespace: "default"},
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("operator"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	creds, err := adminutils.Credentials(ctx, c, cluster)
+	require.NoError(t, err)
+	require.Equal(t, admin.BasicCredentials{}, creds, "no credentials without admin API authentication")
+
+	cluster.Spec.AdminAPIAuthentication = &redpandav1alpha1.AdminAPIAuthentication{
+		CredentialsSecretRef: corev1.LocalObjectReference{Name: "operator-credentials"},
+	}
+	creds, err = adminutils.Credentials(ctx, c, cluster)
+	require.NoError(t, err)
+	require.Equal(t, admin.BasicCredentials{Username: "operator", Password: "secret"}, creds)
+
+	cluster.Spec.AdminAPIAuthentication.CredentialsSecretRef.Name = "missing"
+	_, err = adminutils.Credentials(ctx, c, cluster)
+	require.Error(t, err)
+
+	delete(secret.Data, corev1.BasicAuthPasswordKey)
+	c = fake.NewClientBuilder().WithObjects(secret).Build()
+	cluster.Spec.AdminAPIAuthentication.CredentialsSecretRef.Name = "operator-credentials"
+	_, err = adminutils.Credentials(ctx, c, cluster)
+	require.True(t, errors.Is(err, adminutils.ErrNoCredentials))
+}
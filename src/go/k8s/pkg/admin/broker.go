@@ -0,0 +1,107 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/types"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BrokerHostname returns the DNS name of a broker of the cluster, resolved
+// by the headless service of the cluster, e.g.
+// cluster-sample-0.cluster-sample.default.svc.cluster.local
+func BrokerHostname(
+	redpandaCluster *redpandav1alpha1.Cluster, fqdn string, ordinal int32,
+) string {
+	return fmt.Sprintf("%s-%d.%s", redpandaCluster.Name, ordinal, fqdn)
+}
+
+// BrokerAdminURLs returns the host:port of the internal admin API of the
+// given brokers of the cluster, or of all its current brokers if no ordinal
+// is given.
+func BrokerAdminURLs(
+	redpandaCluster *redpandav1alpha1.Cluster, fqdn string, ordinals ...int32,
+) ([]string, error) {
+	adminInternal := redpandaCluster.AdminAPIInternal()
+	if adminInternal == nil {
+		return nil, &NoInternalAdminAPI{}
+	}
+
+	if len(ordinals) == 0 {
+		// Not a specific node, just go through all them
+		replicas := redpandaCluster.GetCurrentReplicas()
+
+		for i := int32(0); i < replicas; i++ {
+			ordinals = append(ordinals, i)
+		}
+	}
+	urls := make([]string, 0, len(ordinals))
+	for _, on := range ordinals {
+		urls = append(urls, fmt.Sprintf("%s:%d", BrokerHostname(redpandaCluster, fqdn, on), adminInternal.Port))
+	}
+	return urls, nil
+}
+
+// AdminTLSConfig returns the TLS configuration to connect to the internal
+// admin API of the cluster, with the CA and the client certificate of the
+// operator, or nil if TLS is not enabled.
+func AdminTLSConfig(
+	ctx context.Context,
+	k8sClient client.Reader,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	adminTLSProvider types.AdminTLSConfigProvider,
+) (*tls.Config, error) {
+	adminInternal := redpandaCluster.AdminAPIInternal()
+	if adminInternal == nil {
+		return nil, &NoInternalAdminAPI{}
+	}
+	if !adminInternal.TLS.Enabled {
+		return nil, nil
+	}
+	tlsConfig, err := adminTLSProvider.GetTLSConfig(ctx, k8sClient)
+	if err != nil {
+		return nil, fmt.Errorf("could not create tls configuration for internal admin API: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// NewBrokerAdminAPI returns an admin API client that only talks to a single
+// broker of the cluster, through its headless service DNS name. Unlike the
+// clients of NewInternalAdminAPI, requests are never sent to the other
+// brokers, which is what node-local endpoints such as the node configuration
+// or the log levels need.
+func NewBrokerAdminAPI(
+	ctx context.Context,
+	k8sClient client.Reader,
+	redpandaCluster *redpandav1alpha1.Cluster,
+	fqdn string,
+	adminTLSProvider types.AdminTLSConfigProvider,
+	ordinal int32,
+) (*admin.AdminAPI, error) {
+	urls, err := BrokerAdminURLs(redpandaCluster, fqdn, ordinal)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := AdminTLSConfig(ctx, k8sClient, redpandaCluster, adminTLSProvider)
+	if err != nil {
+		return nil, err
+	}
+	adminAPI, err := admin.NewAdminAPI(urls, admin.BasicCredentials{}, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating admin api for broker %s (tls=%v): %w", urls[0], tlsConfig != nil, err)
+	}
+	return adminAPI, nil
+}
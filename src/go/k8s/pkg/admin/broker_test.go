@@ -0,0 +1,53 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin_test
+
+import (
+	"errors"
+	"testing"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBrokerAdminURLs(t *testing.T) {
+	cluster := &redpandav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: redpandav1alpha1.ClusterSpec{
+			Configuration: redpandav1alpha1.RedpandaConfig{
+				AdminAPI: []redpandav1alpha1.AdminAPI{
+					{Port: 9645, External: redpandav1alpha1.ExternalConnectivityConfig{Enabled: true}},
+					{Port: 9644},
+				},
+			},
+		},
+		Status: redpandav1alpha1.ClusterStatus{CurrentReplicas: 2},
+	}
+	const fqdn = "cluster.default.svc.cluster.local."
+
+	require.Equal(t, "cluster-1.cluster.default.svc.cluster.local.", adminutils.BrokerHostname(cluster, fqdn, 1))
+
+	urls, err := adminutils.BrokerAdminURLs(cluster, fqdn)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"cluster-0.cluster.default.svc.cluster.local.:9644",
+		"cluster-1.cluster.default.svc.cluster.local.:9644",
+	}, urls)
+
+	urls, err = adminutils.BrokerAdminURLs(cluster, fqdn, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cluster-1.cluster.default.svc.cluster.local.:9644"}, urls)
+
+	cluster.Spec.Configuration.AdminAPI = nil
+	_, err = adminutils.BrokerAdminURLs(cluster, fqdn)
+	require.True(t, errors.As(err, new(*adminutils.NoInternalAdminAPI)))
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package capabilities detects the APIs served by the Kubernetes cluster the
+// operator runs in, so that the resources it manages degrade gracefully on
+// clusters lacking them instead of failing with opaque API errors.
+package capabilities
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	netv1 "k8s.io/api/networking/v1"
+	netv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ingressResource      = "ingresses"
+	ingressClassResource = "ingressclasses"
+)
+
+var kubernetesInfo = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "redpanda_operator_kubernetes_info",
+		Help: "Version of the Kubernetes cluster and the APIs the operator uses for it; the ingress_api label is empty if Ingresses are not supported",
+	}, []string{"version", "ingress_api", "ingress_class"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(kubernetesInfo)
+}
+
+// Capabilities are the APIs of the Kubernetes cluster that the operator
+// adapts to. A nil Capabilities stands for a cluster serving the latest APIs.
+type Capabilities struct {
+	// ServerVersion is the version of the Kubernetes API server
+	ServerVersion string
+	// IngressAPIVersion is the most recent group version serving Ingresses,
+	// empty if the cluster serves none the operator supports
+	IngressAPIVersion string
+	// IngressClass reports whether the cluster serves IngressClasses, which
+	// replace the kubernetes.io/ingress.class annotation
+	IngressClass bool
+}
+
+// Detect discovers the capabilities of the Kubernetes cluster.
+func Detect(d discovery.DiscoveryInterface) (*Capabilities, error) {
+	version, err := d.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the Kubernetes server version: %w", err)
+	}
+	groups, err := d.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the Kubernetes API groups: %w", err)
+	}
+
+	served := make(map[string]bool)
+	for i := range groups.Groups {
+		for _, v := range groups.Groups[i].Versions {
+			served[v.GroupVersion] = true
+		}
+	}
+
+	c := &Capabilities{ServerVersion: version.GitVersion}
+	// Ordered from the most recent version
+	for _, gv := range []string{netv1.SchemeGroupVersion.String(), netv1beta1.SchemeGroupVersion.String()} {
+		if !served[gv] {
+			continue
+		}
+		resources, err := d.ServerResourcesForGroupVersion(gv)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the resources of %s: %w", gv, err)
+		}
+		for _, r := range resources.APIResources {
+			switch r.Name {
+			case ingressResource:
+				if c.IngressAPIVersion == "" {
+					c.IngressAPIVersion = gv
+				}
+			case ingressClassResource:
+				c.IngressClass = true
+			}
+		}
+	}
+	return c, nil
+}
+
+// SupportsIngress returns whether the operator can create Ingresses.
+func (c *Capabilities) SupportsIngress() bool {
+	return c == nil || c.IngressAPIVersion != ""
+}
+
+// IngressVersion returns the group version of the Ingresses to create.
+func (c *Capabilities) IngressVersion() string {
+	if c == nil {
+		return netv1.SchemeGroupVersion.String()
+	}
+	return c.IngressAPIVersion
+}
+
+// SupportsIngressClass returns whether Ingresses select their controller
+// with an IngressClass rather than an annotation.
+func (c *Capabilities) SupportsIngressClass() bool {
+	return c == nil || c.IngressClass
+}
+
+// Degradations describes the features of the operator that are disabled or
+// replaced by a fallback on the cluster, empty if there are none.
+func (c *Capabilities) Degradations() []string {
+	var d []string
+	switch c.IngressVersion() {
+	case netv1.SchemeGroupVersion.String():
+	case "":
+		d = append(d, fmt.Sprintf("Kubernetes %s serves no supported Ingress API, Ingresses are not created", c.ServerVersion))
+	default:
+		d = append(d, fmt.Sprintf("Kubernetes %s does not serve %s Ingresses, %s is used instead", c.ServerVersion, netv1.SchemeGroupVersion, c.IngressVersion()))
+	}
+	if !c.SupportsIngressClass() && c.SupportsIngress() {
+		d = append(d, fmt.Sprintf("Kubernetes %s does not serve IngressClasses, Ingresses select their controller with an annotation", c.ServerVersion))
+	}
+	return d
+}
+
+// Report logs the capabilities and the resulting degradations, and exports
+// them as the redpanda_operator_kubernetes_info metric.
+func (c *Capabilities) Report(log logr.Logger) {
+	log.Info("Detected Kubernetes capabilities", "version", c.ServerVersion, "ingress API", c.IngressAPIVersion, "ingress class", c.IngressClass)
+	for _, d := range c.Degradations() {
+		log.Info("Degrading for Kubernetes compatibility", "reason", d)
+	}
+	kubernetesInfo.Reset()
+	kubernetesInfo.WithLabelValues(c.ServerVersion, c.IngressAPIVersion, fmt.Sprint(c.IngressClass)).Set(1)
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package capabilities_test
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestDetect(t *testing.T) {
+	resources := func(gv string, names ...string) *metav1.APIResourceList {
+		list := &metav1.APIResourceList{GroupVersion: gv}
+		for _, n := range names {
+			list.APIResources = append(list.APIResources, metav1.APIResource{Name: n})
+		}
+		return list
+	}
+	for _, test := range []struct {
+		name         string
+		resources    []*metav1.APIResourceList
+		expected     capabilities.Capabilities
+		degradations int
+	}{
+		{
+			name: "1.22",
+			resources: []*metav1.APIResourceList{
+				resources("networking.k8s.io/v1", "ingresses", "ingressclasses", "networkpolicies"),
+			},
+			expected: capabilities.Capabilities{IngressAPIVersion: "networking.k8s.io/v1", IngressClass: true},
+		},
+		{
+			name: "1.18",
+			resources: []*metav1.APIResourceList{
+				resources("networking.k8s.io/v1", "networkpolicies"),
+				resources("networking.k8s.io/v1beta1", "ingresses", "ingressclasses"),
+			},
+			expected:     capabilities.Capabilities{IngressAPIVersion: "networking.k8s.io/v1beta1", IngressClass: true},
+			degradations: 1,
+		},
+		{
+			name: "1.16",
+			resources: []*metav1.APIResourceList{
+				resources("networking.k8s.io/v1", "networkpolicies"),
+				resources("networking.k8s.io/v1beta1", "ingresses"),
+			},
+			expected:     capabilities.Capabilities{IngressAPIVersion: "networking.k8s.io/v1beta1"},
+			degradations: 2,
+		},
+		{
+			name: "no ingress",
+			resources: []*metav1.APIResourceList{
+				resources("networking.k8s.io/v1", "networkpolicies"),
+			},
+			degradations: 1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			d := &fakediscovery.FakeDiscovery{
+				Fake:               &kubetesting.Fake{Resources: test.resources},
+				FakedServerVersion: &version.Info{GitVersion: "v" + test.name},
+			}
+			c, err := capabilities.Detect(d)
+			require.NoError(t, err)
+			test.expected.ServerVersion = "v" + test.name
+			assert.Equal(t, test.expected, *c)
+			assert.Len(t, c.Degradations(), test.degradations)
+		})
+	}
+}
+
+func TestUnknownCapabilities(t *testing.T) {
+	var c *capabilities.Capabilities
+	assert.True(t, c.SupportsIngress())
+	assert.True(t, c.SupportsIngressClass())
+	assert.Equal(t, "networking.k8s.io/v1", c.IngressVersion())
+	assert.Empty(t, c.Degradations())
+}
@@ -57,7 +57,7 @@ func NewKafkaAdmin(
 	ctx context.Context, cl client.Client, cluster *redpandav1alpha1.Cluster,
 ) (KafkaAdminClient, error) {
 	opts := []kgo.Opt{kgo.SeedBrokers(getBrokers(cluster)...)}
-	if cluster.Spec.EnableSASL {
+	if cluster.IsSASLOnInternalEnabled() {
 		// Use Cluster superuser to manage Kafka
 		// Console Kafka Service Account can't add ACLs to itself
 		clusterSu := types.NamespacedName{
@@ -0,0 +1,164 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package networking
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// These are the steps of an endpoint probe, which fail in this order
+const (
+	ProbeStageDNS        = "DNS"
+	ProbeStageConnection = "Connection"
+	ProbeStageTLS        = "TLS"
+	ProbeStageHTTP       = "HTTP"
+)
+
+// ProbeError is the error of the first step of a probe that failed
+type ProbeError struct {
+	Stage string
+	Err   error
+}
+
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+func (e *ProbeError) Unwrap() error {
+	return e.Err
+}
+
+// ProbeOptions configure how an endpoint is probed
+type ProbeOptions struct {
+	// VerifyChain verifies that the certificate of the endpoint chains to a
+	// root CA of the system. Otherwise only the host name and the validity
+	// period of the certificate are checked, which is what can be checked
+	// of the certificates issued with the self-signed issuer of the operator.
+	VerifyChain bool
+	// SkipHTTP stops the probe after the TLS handshake, for endpoints
+	// requiring a client certificate
+	SkipHTTP bool
+	// Timeout of the whole probe, 10 seconds if unset
+	Timeout time.Duration
+}
+
+// ProbeEndpoint checks that the HTTP endpoint at rawURL is reachable from the
+// operator: its host name resolves, it accepts connections, it completes a
+// TLS handshake for https URLs and it answers a GET request of the URL with
+// a status that is not a server error. The HTTP status code is returned, 0 if
+// the probe did not get that far. Client errors, such as 401 Unauthorized,
+// show that the endpoint is reachable and are not errors.
+//
+// The returned error is a *ProbeError telling which step failed.
+func ProbeEndpoint(
+	ctx context.Context, rawURL string, opts ProbeOptions,
+) (int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, &ProbeError{ProbeStageDNS, fmt.Errorf("invalid URL %q: %w", rawURL, err)}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return 0, &ProbeError{ProbeStageDNS, err}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addrs[0], port))
+	if err != nil {
+		return 0, &ProbeError{ProbeStageConnection, err}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) //nolint:errcheck // a failure shows up as a timeout
+	}
+
+	if u.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+			// The certificate is verified by verifyCertificate, which
+			// only verifies the chain if requested
+			InsecureSkipVerify: true, //nolint:gosec // see above
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				return verifyCertificate(cs, host, opts.VerifyChain)
+			},
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return 0, &ProbeError{ProbeStageTLS, err}
+		}
+		conn = tlsConn
+	}
+	if opts.SkipHTTP {
+		return 0, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, &ProbeError{ProbeStageHTTP, err}
+	}
+	req.Close = true
+	if err := req.Write(conn); err != nil {
+		return 0, &ProbeError{ProbeStageHTTP, err}
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return 0, &ProbeError{ProbeStageHTTP, err}
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp.StatusCode, &ProbeError{ProbeStageHTTP, fmt.Errorf("server error %s", resp.Status)}
+	}
+	return resp.StatusCode, nil
+}
+
+func verifyCertificate(cs tls.ConnectionState, host string, verifyChain bool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate presented")
+	}
+	leaf := cs.PeerCertificates[0]
+	if !verifyChain {
+		if err := leaf.VerifyHostname(host); err != nil {
+			return err
+		}
+		if now := time.Now(); now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+			return fmt.Errorf("certificate is only valid from %s to %s", leaf.NotBefore.Format(time.RFC3339), leaf.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	return err
+}
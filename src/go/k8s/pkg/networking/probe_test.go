@@ -0,0 +1,75 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package networking_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/networking"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeEndpoint(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+
+	// A port nothing listens on
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := "http://" + l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name          string
+		url           string
+		opts          networking.ProbeOptions
+		expectedCode  int
+		expectedStage string
+	}{
+		{"http", plain.URL + "/topics", networking.ProbeOptions{}, http.StatusOK, ""},
+		{"https", secure.URL + "/subjects", networking.ProbeOptions{}, http.StatusOK, ""},
+		{"client errors are reachable", secure.URL + "/unauthorized", networking.ProbeOptions{}, http.StatusUnauthorized, ""},
+		{"server errors are not", plain.URL + "/unavailable", networking.ProbeOptions{}, http.StatusServiceUnavailable, networking.ProbeStageHTTP},
+		{"untrusted chain", secure.URL, networking.ProbeOptions{VerifyChain: true}, 0, networking.ProbeStageTLS},
+		{"TLS only", secure.URL + "/unavailable", networking.ProbeOptions{SkipHTTP: true}, 0, ""},
+		{"no TLS", "https://" + plain.Listener.Addr().String(), networking.ProbeOptions{}, 0, networking.ProbeStageTLS},
+		{"unresolved host", "https://redpanda.invalid", networking.ProbeOptions{}, 0, networking.ProbeStageDNS},
+		{"closed port", closed, networking.ProbeOptions{}, 0, networking.ProbeStageConnection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := networking.ProbeEndpoint(context.Background(), tt.url, tt.opts)
+			assert.Equal(t, tt.expectedCode, code)
+			if tt.expectedStage == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var probeErr *networking.ProbeError
+			require.True(t, errors.As(err, &probeErr), "unexpected error %v", err)
+			assert.Equal(t, tt.expectedStage, probeErr.Stage)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the audit events, emitted for each mutating operation of the
+// operator on a cluster
+const (
+	// AuditReasonPodRestarted is emitted when a broker pod is deleted to be
+	// restarted with an updated spec
+	AuditReasonPodRestarted = "AuditPodRestarted"
+	// AuditReasonStatefulSetRecreated is emitted when the StatefulSet is
+	// deleted to be recreated, because an immutable field changed
+	AuditReasonStatefulSetRecreated = "AuditStatefulSetRecreated"
+	// AuditReasonBrokerDecommissioned is emitted when a broker is
+	// decommissioned through the admin API
+	AuditReasonBrokerDecommissioned = "AuditBrokerDecommissioned"
+	// AuditReasonBrokerRecommissioned is emitted when a broker is
+	// recommissioned through the admin API
+	AuditReasonBrokerRecommissioned = "AuditBrokerRecommissioned"
+	// AuditReasonMaintenanceModeDisabled is emitted when the maintenance
+	// mode of a broker is disabled through the admin API
+	AuditReasonMaintenanceModeDisabled = "AuditMaintenanceModeDisabled"
+	// AuditReasonClusterConfigPatched is emitted when the centralized
+	// configuration is patched through the admin API
+	AuditReasonClusterConfigPatched = "AuditClusterConfigPatched"
+	// AuditReasonCertificateIssued is emitted when a cert-manager
+	// Certificate is created, which issues a new certificate
+	AuditReasonCertificateIssued = "AuditCertificateIssued"
+	// AuditReasonPersistentVolumeClaimDeleted is emitted when the
+	// PersistentVolumeClaim of a broker is deleted with the cluster
+	AuditReasonPersistentVolumeClaimDeleted = "AuditPersistentVolumeClaimDeleted"
+	// AuditReasonCanaryRolledBack is emitted when the canary broker of an
+	// image upgrade fails and is rolled back to the previous image
+	AuditReasonCanaryRolledBack = "AuditCanaryRolledBack"
+
+	// auditSchemaVersion is the version of the fields of the audit log
+	// entries, bumped on incompatible changes
+	auditSchemaVersion = 1
+)
+
+// Auditor records the mutating operations of the operator on a cluster as
+// Kubernetes events on the Cluster and, if it has a logger, as structured
+// log entries with the following stable fields:
+//
+//	audit     the schema version of the entry
+//	action    the reason of the event, e.g. AuditPodRestarted
+//	cluster   the namespace/name of the Cluster
+//	target    the object of the operation, e.g. a pod name or a node ID
+//	message   the message of the event
+//
+// A nil Auditor records nothing.
+type Auditor struct {
+	recorder record.EventRecorder
+	logger   logr.Logger
+}
+
+// NewAuditor creates an Auditor. The recorder and the logger are optional.
+func NewAuditor(recorder record.EventRecorder, logger logr.Logger) *Auditor {
+	return &Auditor{recorder: recorder, logger: logger}
+}
+
+// Record records an operation of the given reason on the target of the cluster
+func (a *Auditor) Record(
+	cluster *redpandav1alpha1.Cluster,
+	reason string,
+	target string,
+	messageFmt string,
+	args ...interface{},
+) {
+	if a == nil {
+		return
+	}
+	msg := fmt.Sprintf(messageFmt, args...)
+	if a.recorder != nil {
+		a.recorder.Event(cluster, corev1.EventTypeNormal, reason, msg)
+	}
+	if a.logger != nil {
+		a.logger.Info("audit",
+			"audit", auditSchemaVersion,
+			"action", reason,
+			"cluster", cluster.Namespace+"/"+cluster.Name,
+			"target", target,
+			"message", msg,
+		)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"testing"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestAuditor(t *testing.T) {
+	cluster := &redpandav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	NewAuditor(recorder, nil).Record(cluster, AuditReasonPodRestarted, "cluster-0",
+		"Pod %s deleted to be restarted with the updated spec", "cluster-0")
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal AuditPodRestarted Pod cluster-0 deleted to be restarted with the updated spec", <-recorder.Events)
+
+	// A nil Auditor, as in resources that are not audited, records nothing
+	var auditor *Auditor
+	assert.NotPanics(t, func() {
+		auditor.Record(cluster, AuditReasonPodRestarted, "cluster-0", "restarted")
+	})
+	// Neither the recorder nor the logger are required
+	assert.NotPanics(t, func() {
+		NewAuditor(nil, nil).Record(cluster, AuditReasonPodRestarted, "cluster-0", "restarted")
+	})
+}
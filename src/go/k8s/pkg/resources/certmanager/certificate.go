@@ -52,6 +52,7 @@ type CertificateResource struct {
 	commonName     CommonName
 	isCA           bool
 	keystoreSecret *types.NamespacedName
+	auditor        *resources.Auditor
 	logger         logr.Logger
 }
 
@@ -76,6 +77,7 @@ func NewCACertificate(
 		commonName,
 		true,
 		keystoreSecret,
+		nil,
 		logger.WithValues("Kind", certificateKind()),
 	}
 }
@@ -102,6 +104,7 @@ func NewNodeCertificate(
 		commonName,
 		false,
 		keystoreSecret,
+		nil,
 		logger.WithValues("Kind", certificateKind()),
 	}
 }
@@ -128,6 +131,7 @@ func NewCertificate(
 		commonName,
 		isCA,
 		keystoreSecret,
+		nil,
 		logger.WithValues("Kind", certificateKind()),
 	}
 }
@@ -139,7 +143,11 @@ func (r *CertificateResource) Ensure(ctx context.Context) error {
 		return fmt.Errorf("unable to construct object: %w", err)
 	}
 
-	_, err = resources.CreateIfNotExists(ctx, r, obj, r.logger)
+	created, err := resources.CreateIfNotExists(ctx, r, obj, r.logger)
+	if created {
+		r.auditor.Record(r.pandaCluster, resources.AuditReasonCertificateIssued, r.key.Name,
+			"Certificate %s created for cert-manager to issue", r.key.Name)
+	}
 	return err
 }
 
@@ -173,6 +181,11 @@ func (r *CertificateResource) obj() (k8sclient.Object, error) {
 	}
 	cert.Spec.CommonName = string(r.commonName)
 
+	// owner references cannot cross namespaces, certificates issued in the
+	// namespace of an issuer are only identified by their labels
+	if cert.Namespace != r.pandaCluster.Namespace {
+		return cert, nil
+	}
 	err := controllerutil.SetControllerReference(r.pandaCluster, cert, r.scheme)
 	if err != nil {
 		return nil, err
@@ -36,6 +36,7 @@ type PkiReconciler struct {
 	pandaCluster *redpandav1alpha1.Cluster
 	internalFQDN string
 	clusterFQDN  string
+	auditor      *resources.Auditor
 	logger       logr.Logger
 
 	clusterCertificates *ClusterCertificates
@@ -51,11 +52,17 @@ func NewPki(
 	logger logr.Logger,
 ) *PkiReconciler {
 	return &PkiReconciler{
-		client, scheme, pandaCluster, fqdn, clusterFQDN, logger.WithValues("Reconciler", "pki"),
+		client, scheme, pandaCluster, fqdn, clusterFQDN, nil, logger.WithValues("Reconciler", "pki"),
 		NewClusterCertificates(pandaCluster, keyStoreKey(pandaCluster), client, fqdn, clusterFQDN, scheme, logger),
 	}
 }
 
+// WithAuditor sets the Auditor recording the issuance of certificates
+func (r *PkiReconciler) WithAuditor(auditor *resources.Auditor) *PkiReconciler {
+	r.auditor = auditor
+	return r
+}
+
 func keyStoreKey(pandaCluster *redpandav1alpha1.Cluster) types.NamespacedName {
 	return types.NamespacedName{Name: keystoreName(pandaCluster.Name), Namespace: pandaCluster.Namespace}
 }
@@ -74,6 +81,9 @@ func (r *PkiReconciler) Ensure(ctx context.Context) error {
 	toApply = append(toApply, res...)
 
 	for _, res := range toApply {
+		if cert, ok := res.(*CertificateResource); ok {
+			cert.auditor = r.auditor
+		}
 		err := res.Ensure(ctx)
 		if err != nil {
 			r.logger.Error(err, "Failed to reconcile pki")
@@ -92,3 +102,8 @@ func (r *PkiReconciler) StatefulSetVolumeProvider() resourcetypes.StatefulsetTLS
 func (r *PkiReconciler) AdminAPIConfigProvider() resourcetypes.AdminTLSConfigProvider {
 	return r.clusterCertificates
 }
+
+// KafkaAPICAProvider returns provider of the CA certificate of the Kafka API
+func (r *PkiReconciler) KafkaAPICAProvider() resourcetypes.KafkaAPICAProvider {
+	return r.clusterCertificates
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package certmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	cmmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ resources.Resource = &SecretCopyResource{}
+
+// secretCopyKeys are the keys of a certificate Secret that are copied, the
+// keystores are left out as their password Secret is namespaced too
+var secretCopyKeys = []string{cmmetav1.TLSCAKey, corev1.TLSCertKey, corev1.TLSPrivateKeyKey}
+
+// SecretCopyResource keeps a copy of a certificate Secret issued in another
+// namespace in the namespace of the cluster, where it can be mounted. The
+// copy is updated when cert-manager renews the certificate.
+type SecretCopyResource struct {
+	k8sclient.Client
+	scheme       *runtime.Scheme
+	pandaCluster *redpandav1alpha1.Cluster
+	source       types.NamespacedName
+	key          types.NamespacedName
+	logger       logr.Logger
+}
+
+// NewSecretCopy creates SecretCopyResource
+func NewSecretCopy(
+	client k8sclient.Client,
+	scheme *runtime.Scheme,
+	pandaCluster *redpandav1alpha1.Cluster,
+	source types.NamespacedName,
+	key types.NamespacedName,
+	logger logr.Logger,
+) *SecretCopyResource {
+	return &SecretCopyResource{
+		client, scheme, pandaCluster, source, key, logger.WithValues("Kind", "Secret"),
+	}
+}
+
+// Ensure copies the source Secret once it is issued, and updates the copy
+// when the source changes
+func (r *SecretCopyResource) Ensure(ctx context.Context) error {
+	var source corev1.Secret
+	err := r.Get(ctx, r.source, &source)
+	if apierrors.IsNotFound(err) {
+		return &resources.RequeueAfterError{
+			RequeueAfter: resources.RequeueDuration,
+			Msg:          fmt.Sprintf("wait for certificate secret %s to be issued", r.source),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get certificate secret %s: %w", r.source, err)
+	}
+
+	obj, err := r.obj(&source)
+	if err != nil {
+		return fmt.Errorf("unable to construct object: %w", err)
+	}
+	created, err := resources.CreateIfNotExists(ctx, r, obj, r.logger)
+	if err != nil || created {
+		return err
+	}
+
+	var current corev1.Secret
+	if err := r.Get(ctx, r.key, &current); err != nil {
+		return fmt.Errorf("unable to get secret %s: %w", r.key, err)
+	}
+	if secretDataEqual(current.Data, obj.Data) {
+		return nil
+	}
+	// resources.Update logs the diff, which would leak the private key
+	r.logger.Info(fmt.Sprintf("Certificate secret %s changed, updating copy %s", r.source, r.key))
+	current.Data = obj.Data
+	if err := r.Update(ctx, &current); err != nil {
+		return fmt.Errorf("unable to update secret %s: %w", r.key, err)
+	}
+	return nil
+}
+
+// obj returns the copy of the source Secret
+func (r *SecretCopyResource) obj(source *corev1.Secret) (*corev1.Secret, error) {
+	data := make(map[string][]byte, len(secretCopyKeys))
+	for _, k := range secretCopyKeys {
+		data[k] = source.Data[k]
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Key().Name,
+			Namespace: r.Key().Namespace,
+			Labels:    labels.ForCluster(r.pandaCluster),
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		Type: source.Type,
+		Data: data,
+	}
+
+	err := controllerutil.SetControllerReference(r.pandaCluster, secret, r.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Key returns namespace/name object that is used to identify object.
+// For reference please visit types.NamespacedName docs in k8s.io/apimachinery
+func (r *SecretCopyResource) Key() types.NamespacedName {
+	return r.key
+}
+
+func secretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	cmapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
@@ -129,8 +130,11 @@ func getTLSListeners(listeners []APIListener) []APIListener {
 type apiCertificates struct {
 	nodeCertificate    resources.Resource
 	clientCertificates []resources.Resource
-	rootResources      []resources.Resource
-	tlsEnabled         bool
+	// copy of the node certificate's Secret in the cluster namespace, when
+	// the node certificate is issued in the namespace of its issuer
+	nodeCertificateCopy resources.Resource
+	rootResources       []resources.Resource
+	tlsEnabled          bool
 
 	// CR allows to specify node certificate, if not provided this will be nil
 	externalNodeCertificate *corev1.ObjectReference
@@ -228,16 +232,28 @@ func (cc *ClusterCertificates) prepareAPI(
 	}
 	result := tlsEnabledAPICertificates(cc.pandaCluster.Namespace)
 
-	// TODO(#3550): Do not create rootIssuer if nodeSecretRef is passed and mTLS is disabled
-	toApplyRoot, rootIssuerRef := prepareRoot(rootCertSuffix, cc.client, cc.pandaCluster, cc.scheme, cc.logger)
-	result.rootResources = toApplyRoot
+	var rootIssuerRef *cmmetav1.ObjectReference
+	if sharedCA := cc.pandaCluster.Spec.SharedCA; sharedCA != nil {
+		// the shared CA signs the certificates of every cluster using it,
+		// there is no root to create
+		rootIssuerRef = &sharedCA.IssuerRef
+	} else {
+		// TODO(#3550): Do not create rootIssuer if nodeSecretRef is passed and mTLS is disabled
+		result.rootResources, rootIssuerRef = prepareRoot(rootCertSuffix, cc.client, cc.pandaCluster, cc.scheme, cc.logger)
+	}
 	nodeIssuerRef := rootIssuerRef
+	// node certificates are issued in the namespace of the cluster, unless
+	// the issuer is an Issuer of another namespace
+	nodeCertNamespace := cc.pandaCluster.Namespace
 
 	// for now we disallow having different issuer for each listener so that
 	// every time both listeners share the same set of certificates
 	if tlsListeners[0].GetTLS().IssuerRef != nil {
 		// if external issuer is provided, we will use it to generate node certificates
 		nodeIssuerRef = tlsListeners[0].GetTLS().IssuerRef
+		if ns := tlsListeners[0].GetTLS().IssuerNamespace; ns != "" && nodeIssuerRef.Kind != cmapiv1.ClusterIssuerKind {
+			nodeCertNamespace = ns
+		}
 	}
 
 	// for now we disallow having different issuer for each listener so that
@@ -257,17 +273,43 @@ func (cc *ClusterCertificates) prepareAPI(
 			dnsNames = append(dnsNames, externalTLSListener.GetExternal().Subdomain)
 		}
 
-		nodeCert := NewNodeCertificate(
-			cc.client,
-			cc.scheme,
-			cc.pandaCluster,
-			certsKey,
-			nodeIssuerRef,
-			dnsNames,
-			EmptyCommonName,
-			keystoreSecret,
-			cc.logger)
-		result.nodeCertificate = nodeCert
+		if nodeCertNamespace != cc.pandaCluster.Namespace {
+			// the certificate is issued next to the issuer and copied to the
+			// namespace of the cluster, where the keystore password is not
+			// available
+			issuedKey := types.NamespacedName{
+				Name:      cc.pandaCluster.Namespace + "-" + string(certName),
+				Namespace: nodeCertNamespace,
+			}
+			result.nodeCertificate = NewNodeCertificate(
+				cc.client,
+				cc.scheme,
+				cc.pandaCluster,
+				issuedKey,
+				nodeIssuerRef,
+				dnsNames,
+				EmptyCommonName,
+				nil,
+				cc.logger)
+			result.nodeCertificateCopy = NewSecretCopy(
+				cc.client,
+				cc.scheme,
+				cc.pandaCluster,
+				issuedKey,
+				certsKey,
+				cc.logger)
+		} else {
+			result.nodeCertificate = NewNodeCertificate(
+				cc.client,
+				cc.scheme,
+				cc.pandaCluster,
+				certsKey,
+				nodeIssuerRef,
+				dnsNames,
+				EmptyCommonName,
+				keystoreSecret,
+				cc.logger)
+		}
 	}
 
 	anyListenerWithMutualTLS := false
@@ -353,6 +395,9 @@ func (ac *apiCertificates) resources(
 	if ac.nodeCertificate != nil {
 		res = append(res, ac.nodeCertificate)
 	}
+	if ac.nodeCertificateCopy != nil {
+		res = append(res, ac.nodeCertificateCopy)
+	}
 	res = append(res, ac.clientCertificates...)
 	return res, nil
 }
@@ -403,6 +448,10 @@ func (ac *apiCertificates) nodeCertificateName() *types.NamespacedName {
 			Namespace: ac.externalNodeCertificate.Namespace,
 		}
 	}
+	if ac.nodeCertificateCopy != nil {
+		name := ac.nodeCertificateCopy.Key()
+		return &name
+	}
 	if ac.nodeCertificate != nil {
 		name := ac.nodeCertificate.Key()
 		return &name
@@ -575,3 +624,19 @@ func (cc *ClusterCertificates) GetTLSConfig(
 
 	return &tlsConfig, nil
 }
+
+// KafkaAPICA returns the CA certificate of the node certificate of the Kafka
+// API, which clients of the Kafka API trust
+func (cc *ClusterCertificates) KafkaAPICA(
+	ctx context.Context, k8sClient client.Reader,
+) ([]byte, error) {
+	nodeCertificateName := cc.kafkaAPI.nodeCertificateName()
+	if nodeCertificateName == nil {
+		return nil, nil
+	}
+	var nodeCertSecret corev1.Secret
+	if err := k8sClient.Get(ctx, *nodeCertificateName, &nodeCertSecret); err != nil {
+		return nil, fmt.Errorf("getting the node certificate of the Kafka API: %w", err)
+	}
+	return nodeCertSecret.Data[cmmetav1.TLSCAKey], nil
+}
@@ -314,6 +314,53 @@ func TestClusterCertificates(t *testing.T) {
 			[]string{"test-kafka-selfsigned-issuer", "test-kafka-root-certificate", "test-kafka-root-issuer", "test-operator-client", "test-user-client", "test-admin-client", "test-schema-registry-selfsigned-issuer", "test-schema-registry-root-certificate", "test-schema-registry-root-issuer", "test-schema-registry-client"},
 			4,
 		},
+		{"kafka tls with issuer in another namespace", &v1alpha1.Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1alpha1.ClusterSpec{
+				Configuration: v1alpha1.RedpandaConfig{
+					KafkaAPI: []v1alpha1.KafkaAPI{
+						{
+							TLS: v1alpha1.KafkaAPITLS{
+								Enabled: true,
+								IssuerRef: &cmmetav1.ObjectReference{
+									Name: "issuer",
+									Kind: "Issuer",
+								},
+								IssuerNamespace: "cert-manager",
+							},
+						},
+					},
+				},
+			},
+		}, []string{"test-kafka-selfsigned-issuer", "test-kafka-root-certificate", "test-kafka-root-issuer", "test-test-redpanda", "test-redpanda"}, 1},
+		{"shared ca", &v1alpha1.Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1alpha1.ClusterSpec{
+				SharedCA: &v1alpha1.SharedCAConfig{
+					IssuerRef: cmmetav1.ObjectReference{
+						Name: "organization-ca",
+						Kind: "ClusterIssuer",
+					},
+				},
+				Configuration: v1alpha1.RedpandaConfig{
+					KafkaAPI: []v1alpha1.KafkaAPI{
+						{
+							TLS: v1alpha1.KafkaAPITLS{
+								Enabled:           true,
+								RequireClientAuth: true,
+							},
+						},
+					},
+					AdminAPI: []v1alpha1.AdminAPI{
+						{
+							TLS: v1alpha1.AdminAPITLS{
+								Enabled: true,
+							},
+						},
+					},
+				},
+			},
+		}, []string{"test-redpanda", "test-operator-client", "test-user-client", "test-admin-client", "test-admin-api-node"}, 3},
 	}
 	for _, tt := range tests {
 		cc := certmanager.NewClusterCertificates(tt.pandaCluster,
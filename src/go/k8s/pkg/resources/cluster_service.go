@@ -97,9 +97,10 @@ func (r *ClusterServiceResource) obj() (k8sclient.Object, error) {
 	objLabels := labels.ForCluster(r.pandaCluster)
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.Key().Namespace,
-			Name:      r.Key().Name,
-			Labels:    objLabels,
+			Namespace:   r.Key().Namespace,
+			Name:        r.Key().Name,
+			Labels:      withCommonLabels(r.pandaCluster, objLabels),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Service",
@@ -110,6 +111,8 @@ func (r *ClusterServiceResource) obj() (k8sclient.Object, error) {
 			Type:                     corev1.ServiceTypeClusterIP,
 			Ports:                    ports,
 			Selector:                 objLabels.AsAPISelector().MatchLabels,
+			IPFamilies:               r.pandaCluster.IPFamilies(),
+			IPFamilyPolicy:           r.pandaCluster.IPFamilyPolicy(),
 		},
 	}
 
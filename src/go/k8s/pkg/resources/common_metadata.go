@@ -0,0 +1,55 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// withCommonLabels returns the labels of a resource generated for obj: the
+// given labels, and the common labels of the cluster that they do not set.
+// The given labels are not modified.
+func withCommonLabels(
+	obj metav1.Object, objLabels map[string]string,
+) map[string]string {
+	cluster, ok := obj.(*redpandav1alpha1.Cluster)
+	if !ok {
+		return objLabels
+	}
+	return mergeCommon(objLabels, cluster.Spec.CommonLabels)
+}
+
+// withCommonAnnotations returns the annotations of a resource generated for
+// obj: the given annotations, and the common annotations of the cluster that
+// they do not set. The given annotations are not modified.
+func withCommonAnnotations(
+	obj metav1.Object, annotations map[string]string,
+) map[string]string {
+	cluster, ok := obj.(*redpandav1alpha1.Cluster)
+	if !ok {
+		return annotations
+	}
+	return mergeCommon(annotations, cluster.Spec.CommonAnnotations)
+}
+
+func mergeCommon(own, common map[string]string) map[string]string {
+	if len(common) == 0 {
+		return own
+	}
+	merged := make(map[string]string, len(own)+len(common))
+	for k, v := range common {
+		merged[k] = v
+	}
+	for k, v := range own {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"testing"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommonMetadata(t *testing.T) {
+	cluster := &redpandav1alpha1.Cluster{
+		Spec: redpandav1alpha1.ClusterSpec{
+			CommonLabels: map[string]string{
+				"cost-center":            "data",
+				"app.kubernetes.io/name": "overridden",
+			},
+			CommonAnnotations: map[string]string{"policy/exempt": "true"},
+		},
+	}
+	own := map[string]string{"app.kubernetes.io/name": "redpanda"}
+
+	assert.Equal(t, map[string]string{
+		"cost-center":            "data",
+		"app.kubernetes.io/name": "redpanda",
+	}, withCommonLabels(cluster, own))
+	assert.Equal(t, map[string]string{"app.kubernetes.io/name": "redpanda"}, own)
+
+	assert.Equal(t, map[string]string{"policy/exempt": "true"}, withCommonAnnotations(cluster, nil))
+
+	console := &redpandav1alpha1.Console{}
+	assert.Equal(t, own, withCommonLabels(console, own))
+	assert.Nil(t, withCommonAnnotations(console, nil))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/go-logr/logr"
 	cmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/configuration"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/featuregates"
@@ -184,9 +185,10 @@ func (r *ConfigMapResource) obj(ctx context.Context) (k8sclient.Object, error) {
 
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.Key().Namespace,
-			Name:      r.Key().Name,
-			Labels:    labels.ForCluster(r.pandaCluster),
+			Namespace:   r.Key().Namespace,
+			Name:        r.Key().Name,
+			Labels:      withCommonLabels(r.pandaCluster, labels.ForCluster(r.pandaCluster)),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigMap",
@@ -226,22 +228,24 @@ func (r *ConfigMapResource) CreateConfiguration(
 	internalListener := r.pandaCluster.InternalListener()
 	cr.KafkaAPI = []config.NamedAuthNSocketAddress{} // we don't want to inherit default kafka port
 	cr.KafkaAPI = append(cr.KafkaAPI, config.NamedAuthNSocketAddress{
-		Address: "0.0.0.0",
+		Address: r.pandaCluster.ListenerBindAddress(),
 		Port:    internalListener.Port,
 		Name:    InternalListenerName,
+		AuthN:   r.listenerAuthN(internalListener),
 	})
 
-	if r.pandaCluster.ExternalListener() != nil {
+	if externalListener := r.pandaCluster.ExternalListener(); externalListener != nil {
 		cr.KafkaAPI = append(cr.KafkaAPI, config.NamedAuthNSocketAddress{
-			Address: "0.0.0.0",
-			Port:    calculateExternalPort(internalListener.Port, r.pandaCluster.ExternalListener().Port),
+			Address: r.pandaCluster.ListenerBindAddress(),
+			Port:    calculateExternalPort(internalListener.Port, externalListener.Port),
 			Name:    ExternalListenerName,
+			AuthN:   r.listenerAuthN(externalListener),
 		})
 	}
 
 	cr.RPCServer.Port = clusterCRPortOrRPKDefault(c.RPCServer.Port, cr.RPCServer.Port)
 	cr.AdvertisedRPCAPI = &config.SocketAddress{
-		Address: "0.0.0.0",
+		Address: r.pandaCluster.ListenerBindAddress(),
 		Port:    clusterCRPortOrRPKDefault(c.RPCServer.Port, cr.RPCServer.Port),
 	}
 
@@ -293,17 +297,21 @@ func (r *ConfigMapResource) CreateConfiguration(
 	}
 
 	if r.pandaCluster.Spec.CloudStorage.Enabled {
-		secretName := types.NamespacedName{
-			Name:      r.pandaCluster.Spec.CloudStorage.SecretKeyRef.Name,
-			Namespace: r.pandaCluster.Spec.CloudStorage.SecretKeyRef.Namespace,
-		}
-		// We need to retrieve the Secret containing the provided cloud storage secret key and extract the key itself.
-		secretKeyStr, err := r.getSecretValue(ctx, secretName, r.pandaCluster.Spec.CloudStorage.SecretKeyRef.Name)
-		if err != nil {
-			return nil, fmt.Errorf("cannot retrieve cloud storage secret for data archival: %w", err)
-		}
-		if secretKeyStr == "" {
-			return nil, fmt.Errorf("secret name %s, ns %s: %w", secretName.Name, secretName.Namespace, errCloudStorageSecretKeyCannotBeEmpty)
+		var secretKeyStr string
+		if r.pandaCluster.Spec.CloudStorage.UsesStaticCredentials() {
+			secretName := types.NamespacedName{
+				Name:      r.pandaCluster.Spec.CloudStorage.SecretKeyRef.Name,
+				Namespace: r.pandaCluster.Spec.CloudStorage.SecretKeyRef.Namespace,
+			}
+			// We need to retrieve the Secret containing the provided cloud storage secret key and extract the key itself.
+			var err error
+			secretKeyStr, err = r.getSecretValue(ctx, secretName, r.pandaCluster.Spec.CloudStorage.SecretKeyRef.Name)
+			if err != nil {
+				return nil, fmt.Errorf("cannot retrieve cloud storage secret for data archival: %w", err)
+			}
+			if secretKeyStr == "" {
+				return nil, fmt.Errorf("secret name %s, ns %s: %w", secretName.Name, secretName.Namespace, errCloudStorageSecretKeyCannotBeEmpty)
+			}
 		}
 		r.prepareCloudStorage(cfg, secretKeyStr)
 	}
@@ -314,7 +322,12 @@ func (r *ConfigMapResource) CreateConfiguration(
 		}
 	}
 
-	if r.pandaCluster.Spec.EnableSASL {
+	if r.pandaCluster.HasPerListenerAuthentication() {
+		// enable_sasl requires SASL on every listener regardless of their
+		// authentication method, which is enforced with authorization instead.
+		cfg.SetAdditionalRedpandaProperty("enable_sasl", false)
+		cfg.SetAdditionalRedpandaProperty("kafka_enable_authorization", r.requiresKafkaAuthentication())
+	} else if r.pandaCluster.Spec.EnableSASL {
 		cfg.SetAdditionalRedpandaProperty("enable_sasl", true)
 	}
 
@@ -332,7 +345,14 @@ func (r *ConfigMapResource) CreateConfiguration(
 
 	cfg.SetAdditionalRedpandaProperty("log_segment_size", logSegmentSize)
 
-	replicas := r.pandaCluster.GetCurrentReplicas()
+	// Designated seed servers stay the same when the cluster is scaled
+	replicas := r.pandaCluster.SeedServerReplicas()
+	if r.pandaCluster.Spec.Bootstrap != nil {
+		// The seed servers form the cluster together, and a seed server that
+		// lost its data joins the others instead of forming a new cluster.
+		// It is a node property, whatever the configuration mode.
+		configuration.GlobalConfigurationModeClassic.SetAdditionalRedpandaProperty(cfg, "empty_seed_starts_cluster", false)
+	}
 	for i := int32(0); i < replicas; i++ {
 		cr.SeedServers = append(cr.SeedServers, config.SeedServer{
 			Host: config.SocketAddress{
@@ -353,7 +373,7 @@ func (r *ConfigMapResource) CreateConfiguration(
 	if sr := r.pandaCluster.Spec.Configuration.SchemaRegistry; sr != nil {
 		cfg.NodeConfiguration.SchemaRegistry.SchemaRegistryAPI = []config.NamedSocketAddress{
 			{
-				Address: "0.0.0.0",
+				Address: r.pandaCluster.ListenerBindAddress(),
 				Port:    sr.Port,
 				Name:    SchemaRegistryPortName,
 			},
@@ -365,6 +385,10 @@ func (r *ConfigMapResource) CreateConfiguration(
 		return nil, err
 	}
 
+	if err := r.prepareAdminAPIAuthentication(ctx, cfg); err != nil {
+		return nil, err
+	}
+
 	if err := cfg.SetAdditionalFlatProperties(r.pandaCluster.Spec.AdditionalConfiguration); err != nil {
 		return nil, err
 	}
@@ -383,14 +407,45 @@ func calculateExternalPort(kafkaInternalPort, specifiedExternalPort int) int {
 	return kafkaInternalPort + 1
 }
 
+// listenerAuthN returns the authentication method of a Kafka API listener
+// to render in its configuration, which is only set when authentication is
+// configured per listener.
+func (r *ConfigMapResource) listenerAuthN(
+	l *redpandav1alpha1.KafkaAPI,
+) *string {
+	if !r.pandaCluster.HasPerListenerAuthentication() {
+		return nil
+	}
+	authN := string(r.pandaCluster.KafkaAuthenticationMethodOf(l))
+	return &authN
+}
+
+// requiresKafkaAuthentication returns true if any Kafka API listener
+// authenticates its clients.
+func (r *ConfigMapResource) requiresKafkaAuthentication() bool {
+	for i := range r.pandaCluster.Spec.Configuration.KafkaAPI {
+		l := &r.pandaCluster.Spec.Configuration.KafkaAPI[i]
+		if r.pandaCluster.KafkaAuthenticationMethodOf(l) != redpandav1alpha1.KafkaAuthenticationNone {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *ConfigMapResource) prepareCloudStorage(
 	cfg *configuration.GlobalConfiguration, secretKeyStr string,
 ) {
 	cfg.SetAdditionalRedpandaProperty("cloud_storage_enabled", r.pandaCluster.Spec.CloudStorage.Enabled)
-	cfg.SetAdditionalRedpandaProperty("cloud_storage_access_key", r.pandaCluster.Spec.CloudStorage.AccessKey)
+	if r.pandaCluster.Spec.CloudStorage.UsesStaticCredentials() {
+		cfg.SetAdditionalRedpandaProperty("cloud_storage_access_key", r.pandaCluster.Spec.CloudStorage.AccessKey)
+		cfg.SetAdditionalRedpandaProperty("cloud_storage_secret_key", secretKeyStr)
+	} else {
+		// Redpanda gets temporary credentials from the metadata of the
+		// node or from the token of the ServiceAccount.
+		cfg.SetAdditionalRedpandaProperty("cloud_storage_credentials_source", string(r.pandaCluster.Spec.CloudStorage.CredentialsSource))
+	}
 	cfg.SetAdditionalRedpandaProperty("cloud_storage_region", r.pandaCluster.Spec.CloudStorage.Region)
 	cfg.SetAdditionalRedpandaProperty("cloud_storage_bucket", r.pandaCluster.Spec.CloudStorage.Bucket)
-	cfg.SetAdditionalRedpandaProperty("cloud_storage_secret_key", secretKeyStr)
 	cfg.SetAdditionalRedpandaProperty("cloud_storage_disable_tls", r.pandaCluster.Spec.CloudStorage.DisableTLS)
 
 	interval := r.pandaCluster.Spec.CloudStorage.ReconcilicationIntervalMs
@@ -432,7 +487,7 @@ func (r *ConfigMapResource) preparePandaproxy(cfgRpk *config.Config) {
 
 	cfgRpk.Pandaproxy.PandaproxyAPI = []config.NamedSocketAddress{
 		{
-			Address: "0.0.0.0",
+			Address: r.pandaCluster.ListenerBindAddress(),
 			Port:    internal.Port,
 			Name:    PandaproxyPortInternalName,
 		},
@@ -441,7 +496,7 @@ func (r *ConfigMapResource) preparePandaproxy(cfgRpk *config.Config) {
 	if r.pandaCluster.PandaproxyAPIExternal() != nil {
 		cfgRpk.Pandaproxy.PandaproxyAPI = append(cfgRpk.Pandaproxy.PandaproxyAPI,
 			config.NamedSocketAddress{
-				Address: "0.0.0.0",
+				Address: r.pandaCluster.ListenerBindAddress(),
 				Port:    calculateExternalPort(internal.Port, 0),
 				Name:    PandaproxyPortExternalName,
 			})
@@ -464,7 +519,7 @@ func (r *ConfigMapResource) preparePandaproxyClient(
 		})
 	}
 
-	if !r.pandaCluster.Spec.EnableSASL {
+	if !r.pandaCluster.IsSASLOnInternalEnabled() {
 		return nil
 	}
 
@@ -503,7 +558,7 @@ func (r *ConfigMapResource) prepareSchemaRegistryClient(
 		})
 	}
 
-	if !r.pandaCluster.Spec.EnableSASL {
+	if !r.pandaCluster.IsSASLOnInternalEnabled() {
 		return nil
 	}
 
@@ -526,6 +581,26 @@ func (r *ConfigMapResource) prepareSchemaRegistryClient(
 	return cfg.AppendToAdditionalRedpandaProperty(superusersConfigurationKey, username)
 }
 
+// prepareAdminAPIAuthentication requires the clients of the admin API to
+// authenticate, and makes the user of the operator a superuser. The user is
+// bootstrapped by the StatefulSet.
+func (r *ConfigMapResource) prepareAdminAPIAuthentication(
+	ctx context.Context, cfg *configuration.GlobalConfiguration,
+) error {
+	if r.pandaCluster.Spec.AdminAPIAuthentication == nil {
+		return nil
+	}
+
+	creds, err := adminutils.Credentials(ctx, r, r.pandaCluster)
+	if err != nil {
+		return err
+	}
+	cfg.SetAdditionalRedpandaProperty("admin_api_require_auth", true)
+
+	// Add username as superuser
+	return cfg.AppendToAdditionalRedpandaProperty(superusersConfigurationKey, creds.Username)
+}
+
 func (r *ConfigMapResource) preparePandaproxyTLS(
 	cfgRpk *config.Config, mountPoints *resourcetypes.TLSMountPoints,
 ) {
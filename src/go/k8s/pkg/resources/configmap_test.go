@@ -22,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -163,3 +164,119 @@ func TestEnsureConfigMap_AdditionalConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureConfigMap_Bootstrap(t *testing.T) {
+	require.NoError(t, redpandav1alpha1.AddToScheme(scheme.Scheme))
+	panda := pandaCluster().DeepCopy()
+	panda.Spec.Replicas = pointer.Int32Ptr(5)
+	panda.Status.CurrentReplicas = 5
+	panda.Spec.Bootstrap = &redpandav1alpha1.BootstrapConfig{SeedReplicas: 3}
+
+	c := fake.NewClientBuilder().Build()
+	secret := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "archival",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"archival": []byte("XXX"),
+		},
+	}
+	require.NoError(t, c.Create(context.TODO(), &secret))
+	cfgRes := resources.NewConfigMap(
+		c,
+		panda,
+		scheme.Scheme,
+		"cluster.local",
+		types.NamespacedName{Name: "test", Namespace: "test"},
+		types.NamespacedName{Name: "test", Namespace: "test"},
+		ctrl.Log.WithName("test"))
+	require.NoError(t, cfgRes.Ensure(context.TODO()))
+
+	actual := &v1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), cfgRes.Key(), actual))
+	data := actual.Data["redpanda.yaml"]
+	// Only the designated seed servers are seed servers
+	require.Contains(t, data, "address: cluster-2.cluster.local")
+	require.NotContains(t, data, "address: cluster-3.cluster.local")
+	require.Contains(t, data, "empty_seed_starts_cluster: false")
+}
+
+func TestEnsureConfigMap_SeedServersWhileDecommissioning(t *testing.T) {
+	require.NoError(t, redpandav1alpha1.AddToScheme(scheme.Scheme))
+	panda := pandaCluster().DeepCopy()
+	panda.Spec.Replicas = pointer.Int32Ptr(2)
+	panda.Status.CurrentReplicas = 2
+	panda.Status.DecommissioningNode = pointer.Int32Ptr(2)
+
+	c := fake.NewClientBuilder().Build()
+	secret := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "archival",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"archival": []byte("XXX"),
+		},
+	}
+	require.NoError(t, c.Create(context.TODO(), &secret))
+	cfgRes := resources.NewConfigMap(
+		c,
+		panda,
+		scheme.Scheme,
+		"cluster.local",
+		types.NamespacedName{Name: "test", Namespace: "test"},
+		types.NamespacedName{Name: "test", Namespace: "test"},
+		ctrl.Log.WithName("test"))
+	require.NoError(t, cfgRes.Ensure(context.TODO()))
+
+	actual := &v1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), cfgRes.Key(), actual))
+	data := actual.Data["redpanda.yaml"]
+	// The broker being decommissioned is still a member of the cluster
+	require.Contains(t, data, "address: cluster-2.cluster.local")
+	require.NotContains(t, data, "address: cluster-3.cluster.local")
+}
+
+func TestEnsureConfigMap_AdminAPIAuthentication(t *testing.T) {
+	require.NoError(t, redpandav1alpha1.AddToScheme(scheme.Scheme))
+	panda := pandaCluster().DeepCopy()
+	panda.Spec.AdminAPIAuthentication = &redpandav1alpha1.AdminAPIAuthentication{
+		CredentialsSecretRef: v1.LocalObjectReference{Name: "operator-credentials"},
+	}
+
+	c := fake.NewClientBuilder().Build()
+	for _, secret := range []v1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "archival", Namespace: "default"},
+			Data:       map[string][]byte{"archival": []byte("XXX")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "operator-credentials", Namespace: "default"},
+			Type:       v1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				v1.BasicAuthUsernameKey: []byte("operator-user"),
+				v1.BasicAuthPasswordKey: []byte("operator-password"),
+			},
+		},
+	} {
+		secret := secret
+		require.NoError(t, c.Create(context.TODO(), &secret))
+	}
+	cfgRes := resources.NewConfigMap(
+		c,
+		panda,
+		scheme.Scheme,
+		"cluster.local",
+		types.NamespacedName{Name: "test", Namespace: "test"},
+		types.NamespacedName{Name: "test", Namespace: "test"},
+		ctrl.Log.WithName("test"))
+	require.NoError(t, cfgRes.Ensure(context.TODO()))
+
+	actual := &v1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), cfgRes.Key(), actual))
+	data := actual.Data["redpanda.yaml"]
+	require.Contains(t, data, "admin_api_require_auth: true")
+	require.Contains(t, data, "- operator-user")
+	require.NotContains(t, data, "operator-password")
+}
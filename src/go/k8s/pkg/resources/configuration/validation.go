@@ -0,0 +1,98 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+)
+
+// PropertyError describes why a property does not match the configuration
+// schema of the cluster
+type PropertyError struct {
+	Key     string
+	Message string
+}
+
+// ValidateProperties checks the given properties against the schema returned by
+// the admin API, returning the errors sorted by key.
+//
+// Values are validated loosely, as in PropertiesEqual: the string
+// representation of a number is a valid number, since properties coming from
+// additionalConfiguration are always strings.
+func ValidateProperties(
+	properties map[string]interface{}, schema admin.ConfigSchema,
+) []PropertyError {
+	var errs []PropertyError
+	for k, v := range properties {
+		if msg := validateProperty(k, v, schema); msg != "" {
+			errs = append(errs, PropertyError{Key: k, Message: msg})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Key < errs[j].Key
+	})
+	return errs
+}
+
+func validateProperty(
+	key string, v interface{}, schema admin.ConfigSchema,
+) string {
+	meta, ok := schema[key]
+	if !ok {
+		return "unknown property"
+	}
+	if v == nil {
+		if !meta.Nullable {
+			return "property cannot be null"
+		}
+		return ""
+	}
+	switch meta.Type {
+	case "integer":
+		if _, ok := convertibleToInt64(v); !ok {
+			return fmt.Sprintf("expected an integer, got %q", fmt.Sprint(v))
+		}
+	case "number":
+		if _, ok := convertibleToFloat64(v); !ok {
+			return fmt.Sprintf("expected a number, got %q", fmt.Sprint(v))
+		}
+	case "boolean":
+		if !isBoolean(v) {
+			return fmt.Sprintf("expected a boolean, got %q", fmt.Sprint(v))
+		}
+	}
+	if len(meta.EnumValues) > 0 {
+		s := fmt.Sprint(v)
+		for _, e := range meta.EnumValues {
+			if s == e {
+				return ""
+			}
+		}
+		return fmt.Sprintf("invalid value %q, must be one of: %s", s, strings.Join(meta.EnumValues, ", "))
+	}
+	return ""
+}
+
+func isBoolean(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return true
+	case string:
+		_, err := strconv.ParseBool(b)
+		return err == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/configuration"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProperties(t *testing.T) {
+	schema := admin.ConfigSchema{
+		"segment_bytes":           {Type: "integer"},
+		"retention_ratio":         {Type: "number"},
+		"enable_idempotence":      {Type: "boolean"},
+		"compression":             {Type: "string", EnumValues: []string{"none", "zstd"}},
+		"superusers":              {Type: "array"},
+		"cloud_storage_region":    {Type: "string", Nullable: true},
+		"cloud_storage_bucket":    {Type: "string"},
+		"log_segment_size_jitter": {Type: "integer"},
+	}
+
+	errs := configuration.ValidateProperties(map[string]interface{}{
+		"segment_bytes":           "1024",
+		"retention_ratio":         0.5,
+		"enable_idempotence":      "true",
+		"compression":             "zstd",
+		"superusers":              []interface{}{"admin"},
+		"cloud_storage_region":    nil,
+		"cloud_storage_bucket":    "bucket",
+		"log_segment_size_jitter": 5,
+	}, schema)
+	assert.Empty(t, errs)
+
+	errs = configuration.ValidateProperties(map[string]interface{}{
+		"segment_bytes":        "1KiB",
+		"retention_ratio":      "half",
+		"enable_idempotence":   "yes please",
+		"compression":          "lz5",
+		"cloud_storage_bucket": nil,
+		"unknown_property":     "value",
+	}, schema)
+	assert.Equal(t, []configuration.PropertyError{
+		{Key: "cloud_storage_bucket", Message: "property cannot be null"},
+		{Key: "compression", Message: `invalid value "lz5", must be one of: none, zstd`},
+		{Key: "enable_idempotence", Message: `expected a boolean, got "yes please"`},
+		{Key: "retention_ratio", Message: `expected a number, got "half"`},
+		{Key: "segment_bytes", Message: `expected an integer, got "1KiB"`},
+		{Key: "unknown_property", Message: "unknown property"},
+	}, errs)
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	cmmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	resourcetypes "github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/types"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ConnectionProfileSuffix is the suffix of the Secret, and of the
+	// optional ConfigMap, with the connection profile of the cluster
+	ConnectionProfileSuffix = "connection-profile"
+
+	defaultConnectionProfileMountPath = "/etc/redpanda-profile"
+	// the SCRAM mechanism of the superusers created by the operator
+	connectionProfileSASLMechanism = "SCRAM-SHA-256"
+)
+
+var _ Resource = &ConnectionProfileResource{}
+
+// ConnectionProfileResource publishes how to connect to the Kafka API
+// listeners of the cluster, see redpandav1alpha1.ConnectionProfileConfig
+type ConnectionProfileResource struct {
+	k8sclient.Client
+	scheme       *runtime.Scheme
+	pandaCluster *redpandav1alpha1.Cluster
+	caProvider   resourcetypes.KafkaAPICAProvider
+	logger       logr.Logger
+}
+
+// NewConnectionProfile creates ConnectionProfileResource
+func NewConnectionProfile(
+	client k8sclient.Client,
+	pandaCluster *redpandav1alpha1.Cluster,
+	scheme *runtime.Scheme,
+	caProvider resourcetypes.KafkaAPICAProvider,
+	logger logr.Logger,
+) *ConnectionProfileResource {
+	return &ConnectionProfileResource{
+		client,
+		scheme,
+		pandaCluster,
+		caProvider,
+		logger.WithValues("Kind", "connection profile"),
+	}
+}
+
+// Ensure creates or updates the connection profile once the addresses of the
+// brokers are known and the certificate of the Kafka API is issued
+func (r *ConnectionProfileResource) Ensure(ctx context.Context) error {
+	profile := r.pandaCluster.Spec.ConnectionProfile
+	if profile == nil || !profile.Enabled {
+		return nil
+	}
+
+	ca, err := r.caProvider.KafkaAPICA(ctx, r)
+	if apierrors.IsNotFound(err) {
+		r.logger.Info("Waiting for the certificate of the Kafka API to publish the connection profile")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := connectionProfileData(r.pandaCluster, ca)
+	if err != nil {
+		return fmt.Errorf("unable to generate the connection profile: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: r.objectMeta(),
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if err := r.ensure(ctx, secret, &corev1.Secret{}); err != nil {
+		return err
+	}
+	if !profile.ConfigMap {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: r.objectMeta(),
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		Data: make(map[string]string, len(data)),
+	}
+	for k, v := range data {
+		cm.Data[k] = string(v)
+	}
+	return r.ensure(ctx, cm, &corev1.ConfigMap{})
+}
+
+func (r *ConnectionProfileResource) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        r.Key().Name,
+		Namespace:   r.Key().Namespace,
+		Labels:      withCommonLabels(r.pandaCluster, nil),
+		Annotations: withCommonAnnotations(r.pandaCluster, nil),
+	}
+}
+
+// ensure creates obj, or updates current to it if it exists
+func (r *ConnectionProfileResource) ensure(
+	ctx context.Context, obj, current k8sclient.Object,
+) error {
+	if err := controllerutil.SetControllerReference(r.pandaCluster, obj, r.scheme); err != nil {
+		return err
+	}
+	created, err := CreateIfNotExists(ctx, r, obj, r.logger)
+	if err != nil || created {
+		return err
+	}
+	if err := r.Get(ctx, r.Key(), current); err != nil {
+		return fmt.Errorf("error while fetching the connection profile: %w", err)
+	}
+	_, err = Update(ctx, current, obj, r.Client, r.logger)
+	return err
+}
+
+// Key returns namespace/name object that is used to identify object.
+func (r *ConnectionProfileResource) Key() types.NamespacedName {
+	return types.NamespacedName{Name: resourceNameTrim(r.pandaCluster.Name, ConnectionProfileSuffix), Namespace: r.pandaCluster.Namespace}
+}
+
+// connectionProfileData returns the keys of the connection profile of the
+// listeners whose brokers have an address, and the CA certificate if any of
+// them has TLS
+func connectionProfileData(
+	cluster *redpandav1alpha1.Cluster, ca []byte,
+) (map[string][]byte, error) {
+	mountPath := cluster.Spec.ConnectionProfile.MountPath
+	if mountPath == "" {
+		mountPath = defaultConnectionProfileMountPath
+	}
+	caFile := path.Join(mountPath, cmmetav1.TLSCAKey)
+
+	listeners := []struct {
+		suffix   string
+		listener *redpandav1alpha1.KafkaAPI
+		brokers  []string
+	}{
+		{"", cluster.InternalListener(), nil},
+		// The external addresses already have ports
+		{"-external", cluster.ExternalListener(), cluster.Status.Nodes.External},
+	}
+	if l := listeners[0].listener; l != nil {
+		for _, host := range cluster.Status.Nodes.Internal {
+			listeners[0].brokers = append(listeners[0].brokers, net.JoinHostPort(host, strconv.Itoa(l.Port)))
+		}
+	}
+
+	data := make(map[string][]byte)
+	for _, l := range listeners {
+		if l.listener == nil || len(l.brokers) == 0 {
+			continue
+		}
+		tlsEnabled := l.listener.TLS.Enabled
+		sasl := cluster.KafkaAuthenticationMethodOf(l.listener) == redpandav1alpha1.KafkaAuthenticationSASL
+		// Without the CA of the operator, the certificate is expected to
+		// be trusted by the system, e.g. when issued by Let's Encrypt
+		withCA := tlsEnabled && len(ca) > 0
+
+		var rpk config.RpkConfig
+		rpk.KafkaAPI.Brokers = l.brokers
+		if tlsEnabled {
+			rpk.KafkaAPI.TLS = &config.TLS{}
+			if withCA {
+				rpk.KafkaAPI.TLS.TruststoreFile = caFile
+			}
+		}
+		if sasl {
+			rpk.KafkaAPI.SASL = &config.SASL{Mechanism: connectionProfileSASLMechanism}
+		}
+		rpkYAML, err := yaml.Marshal(struct {
+			Rpk config.RpkConfig `yaml:"rpk"`
+		}{rpk})
+		if err != nil {
+			return nil, err
+		}
+
+		protocol := "PLAINTEXT"
+		switch {
+		case tlsEnabled && sasl:
+			protocol = "SASL_SSL"
+		case tlsEnabled:
+			protocol = "SSL"
+		case sasl:
+			protocol = "SASL_PLAINTEXT"
+		}
+		properties := []string{
+			"bootstrap.servers=" + strings.Join(l.brokers, ","),
+			"security.protocol=" + protocol,
+		}
+		if sasl {
+			properties = append(properties, "sasl.mechanism="+connectionProfileSASLMechanism)
+		}
+		if withCA {
+			properties = append(properties, "ssl.truststore.type=PEM", "ssl.truststore.location="+caFile)
+		}
+
+		data["brokers"+l.suffix] = []byte(strings.Join(l.brokers, ","))
+		data["rpk"+l.suffix+".yaml"] = rpkYAML
+		data["client"+l.suffix+".properties"] = []byte(strings.Join(properties, "\n") + "\n")
+		if withCA {
+			data[cmmetav1.TLSCAKey] = ca
+		}
+	}
+	return data, nil
+}
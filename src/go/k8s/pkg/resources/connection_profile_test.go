@@ -0,0 +1,121 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources_test
+
+import (
+	"context"
+	"testing"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	res "github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type staticCA []byte
+
+func (ca staticCA) KafkaAPICA(context.Context, client.Reader) ([]byte, error) {
+	return ca, nil
+}
+
+func TestEnsure_ConnectionProfile(t *testing.T) {
+	require.NoError(t, redpandav1alpha1.AddToScheme(scheme.Scheme))
+
+	cluster := pandaCluster()
+	cluster.Spec.EnableSASL = true
+	cluster.Spec.Configuration.KafkaAPI = []redpandav1alpha1.KafkaAPI{
+		{Port: 9092, AuthenticationMethod: redpandav1alpha1.KafkaAuthenticationNone},
+		{
+			Port:     30092,
+			External: redpandav1alpha1.ExternalConnectivityConfig{Enabled: true, Subdomain: "redpanda.example.com"},
+			TLS:      redpandav1alpha1.KafkaAPITLS{Enabled: true},
+		},
+	}
+	cluster.Spec.ConnectionProfile = &redpandav1alpha1.ConnectionProfileConfig{
+		Enabled:   true,
+		MountPath: "/etc/kafka",
+		ConfigMap: true,
+	}
+	cluster.Status.Nodes.Internal = []string{"cluster-0.cluster.default.svc.cluster.local."}
+	cluster.Status.Nodes.External = []string{"0.redpanda.example.com:30092"}
+
+	c := fake.NewClientBuilder().Build()
+	profile := res.NewConnectionProfile(c, cluster, scheme.Scheme, staticCA("CA"), ctrl.Log.WithName("test"))
+	require.NoError(t, profile.Ensure(context.Background()))
+
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), profile.Key(), &secret))
+	assert.Equal(t, "cluster-connection-profile", secret.Name)
+	assert.Equal(t, map[string]string{
+		"brokers": "cluster-0.cluster.default.svc.cluster.local.:9092",
+		"rpk.yaml": `rpk:
+  kafka_api:
+    brokers:
+    - cluster-0.cluster.default.svc.cluster.local.:9092
+`,
+		"client.properties": `bootstrap.servers=cluster-0.cluster.default.svc.cluster.local.:9092
+security.protocol=PLAINTEXT
+`,
+		"brokers-external": "0.redpanda.example.com:30092",
+		"rpk-external.yaml": `rpk:
+  kafka_api:
+    brokers:
+    - 0.redpanda.example.com:30092
+    tls:
+      truststore_file: /etc/kafka/ca.crt
+    sasl:
+      type: SCRAM-SHA-256
+`,
+		"client-external.properties": `bootstrap.servers=0.redpanda.example.com:30092
+security.protocol=SASL_SSL
+sasl.mechanism=SCRAM-SHA-256
+ssl.truststore.type=PEM
+ssl.truststore.location=/etc/kafka/ca.crt
+`,
+		"ca.crt": "CA",
+	}, stringData(secret.Data))
+
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(context.Background(), profile.Key(), &cm))
+	assert.Equal(t, stringData(secret.Data), cm.Data)
+
+	// The profile follows the brokers
+	cluster.Status.Nodes.External = append(cluster.Status.Nodes.External, "1.redpanda.example.com:30092")
+	require.NoError(t, profile.Ensure(context.Background()))
+	require.NoError(t, c.Get(context.Background(), profile.Key(), &secret))
+	assert.Equal(t, "0.redpanda.example.com:30092,1.redpanda.example.com:30092", string(secret.Data["brokers-external"]))
+}
+
+func TestEnsure_ConnectionProfileDisabled(t *testing.T) {
+	cluster := pandaCluster()
+	cluster.Status.Nodes.Internal = []string{"cluster-0.cluster.default.svc.cluster.local."}
+
+	c := fake.NewClientBuilder().Build()
+	profile := res.NewConnectionProfile(c, cluster, scheme.Scheme, staticCA(nil), ctrl.Log.WithName("test"))
+	require.NoError(t, profile.Ensure(context.Background()))
+
+	var secret corev1.Secret
+	err := c.Get(context.Background(), profile.Key(), &secret)
+	assert.True(t, apierrors.IsNotFound(err), "unexpected error %v", err)
+}
+
+func stringData(data map[string][]byte) map[string]string {
+	s := make(map[string]string, len(data))
+	for k, v := range data {
+		s[k] = string(v)
+	}
+	return s
+}
@@ -0,0 +1,315 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ Resource = &DiskValidationJobResource{}
+
+const (
+	// DiskValidationEventReasonPassed is the reason of the event emitted
+	// when the storage meets the expectations of spec.diskValidation
+	DiskValidationEventReasonPassed = "DiskValidationPassed"
+	// DiskValidationEventReasonFailed is the reason of the event emitted
+	// when the storage does not meet the expectations of
+	// spec.diskValidation, or when the benchmark could not run
+	DiskValidationEventReasonFailed = "DiskValidationFailed"
+
+	diskValidationContainerName   = "disk-validation"
+	diskValidationComponent       = "disk-validation"
+	defaultDiskValidationDuration = 30 * time.Second
+	// diskValidationTimeout is how long the benchmark may take on top of
+	// its duration before the Job is failed
+	diskValidationTimeout = 10 * time.Minute
+)
+
+// DiskValidationJobResource runs the disk benchmark of spec.diskValidation
+// in a Job before the StatefulSet of a new cluster is created, and records
+// the results in the status of the cluster
+type DiskValidationJobResource struct {
+	k8sclient.Client
+	scheme       *runtime.Scheme
+	pandaCluster *redpandav1alpha1.Cluster
+	recorder     record.EventRecorder
+	logger       logr.Logger
+}
+
+// NewDiskValidationJob creates DiskValidationJobResource
+func NewDiskValidationJob(
+	client k8sclient.Client,
+	pandaCluster *redpandav1alpha1.Cluster,
+	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	logger logr.Logger,
+) *DiskValidationJobResource {
+	return &DiskValidationJobResource{
+		client,
+		scheme,
+		pandaCluster,
+		recorder,
+		logger.WithValues(
+			"Kind", "Job",
+		),
+	}
+}
+
+// Ensure runs the disk benchmark once, before the brokers are started. It
+// requeues until the Job completes, so the StatefulSet is only created
+// once the results are recorded. The results do not block the brokers: a
+// warning event is emitted when the storage does not meet the expectations.
+func (r *DiskValidationJobResource) Ensure(ctx context.Context) error {
+	if r.pandaCluster.Spec.DiskValidation == nil || r.pandaCluster.Status.DiskValidation != nil {
+		return nil
+	}
+
+	var sts appsv1.StatefulSet
+	err := r.Get(ctx, types.NamespacedName{Name: r.pandaCluster.Name, Namespace: r.pandaCluster.Namespace}, &sts)
+	if err == nil {
+		// The brokers are already running, it is too late to validate
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error while fetching StatefulSet resource: %w", err)
+	}
+
+	obj, err := r.obj()
+	if err != nil {
+		return fmt.Errorf("unable to construct object: %w", err)
+	}
+	created, err := CreateIfNotExists(ctx, r, obj, r.logger)
+	if err != nil {
+		return err
+	}
+	waiting := &RequeueAfterError{
+		RequeueAfter: RequeueDuration,
+		Msg:          fmt.Sprintf("waiting for disk validation job %s to complete", r.Key().Name),
+	}
+	if created {
+		return waiting
+	}
+
+	var job batchv1.Job
+	if err = r.Get(ctx, r.Key(), &job); err != nil {
+		return fmt.Errorf("error while fetching Job resource: %w", err)
+	}
+	var status *redpandav1alpha1.DiskValidationStatus
+	switch {
+	case job.Status.Succeeded > 0:
+		output, outputErr := r.output(ctx)
+		if outputErr == nil {
+			status, outputErr = evaluateDiskValidation(r.pandaCluster.Spec.DiskValidation, output)
+		}
+		if outputErr != nil {
+			status = &redpandav1alpha1.DiskValidationStatus{
+				Message: fmt.Sprintf("unable to read the benchmark results: %v", outputErr),
+			}
+		}
+	case job.Status.Failed > 0:
+		status = &redpandav1alpha1.DiskValidationStatus{
+			Message: fmt.Sprintf("the disk validation job %s failed", job.Name),
+		}
+	default:
+		return waiting
+	}
+
+	status.CompletedAt = metav1.Now()
+	r.pandaCluster.Status.DiskValidation = status
+	if err = r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to update cluster status with the disk validation results: %w", err)
+	}
+	if status.Passed {
+		r.recorder.Event(r.pandaCluster, corev1.EventTypeNormal, DiskValidationEventReasonPassed,
+			"storage meets the expected performance")
+	} else {
+		r.recorder.Eventf(r.pandaCluster, corev1.EventTypeWarning, DiskValidationEventReasonFailed,
+			"disk validation did not pass: %s", status.Message)
+	}
+
+	err = r.Delete(ctx, &job, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "unable to delete the disk validation job", "job", job.Name)
+	}
+	return nil
+}
+
+// output returns the termination message of the benchmark container, which
+// holds the io-config.yaml written by rpk iotune
+func (r *DiskValidationJobResource) output(ctx context.Context) (string, error) {
+	var pods corev1.PodList
+	err := r.List(ctx, &pods,
+		k8sclient.InNamespace(r.pandaCluster.Namespace),
+		k8sclient.MatchingLabels{"job-name": r.Key().Name})
+	if err != nil {
+		return "", fmt.Errorf("unable to list the pods of the disk validation job: %w", err)
+	}
+	for i := range pods.Items {
+		for _, cs := range pods.Items[i].Status.ContainerStatuses {
+			t := cs.State.Terminated
+			if cs.Name == diskValidationContainerName && t != nil && t.ExitCode == 0 {
+				return t.Message, nil
+			}
+		}
+	}
+	return "", errors.New("no completed benchmark pod found")
+}
+
+// evaluateDiskValidation parses the io-config.yaml written by rpk iotune and
+// compares the write performance with the expectations of the config
+func evaluateDiskValidation(
+	cfg *redpandav1alpha1.DiskValidationConfig, output string,
+) (*redpandav1alpha1.DiskValidationStatus, error) {
+	var results struct {
+		Disks []struct {
+			ReadIOPS       int64 `yaml:"read_iops"`
+			ReadBandwidth  int64 `yaml:"read_bandwidth"`
+			WriteIOPS      int64 `yaml:"write_iops"`
+			WriteBandwidth int64 `yaml:"write_bandwidth"`
+		} `yaml:"disks"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &results); err != nil {
+		return nil, fmt.Errorf("unable to decode the benchmark results: %w", err)
+	}
+	if len(results.Disks) == 0 {
+		return nil, errors.New("the benchmark results do not contain any disk")
+	}
+	disk := results.Disks[0]
+	status := &redpandav1alpha1.DiskValidationStatus{
+		ReadIOPS:       disk.ReadIOPS,
+		ReadBandwidth:  disk.ReadBandwidth,
+		WriteIOPS:      disk.WriteIOPS,
+		WriteBandwidth: disk.WriteBandwidth,
+	}
+
+	var problems []string
+	if minBw := cfg.MinWriteBandwidth; minBw != nil && disk.WriteBandwidth < minBw.Value() {
+		problems = append(problems, fmt.Sprintf("write bandwidth %s/s is below the expected %s/s",
+			resource.NewQuantity(disk.WriteBandwidth, resource.BinarySI), minBw))
+	}
+	if disk.WriteIOPS < cfg.MinWriteIOPS {
+		problems = append(problems, fmt.Sprintf("write IOPS %d is below the expected %d",
+			disk.WriteIOPS, cfg.MinWriteIOPS))
+	}
+	status.Passed = len(problems) == 0
+	status.Message = strings.Join(problems, "; ")
+	return status, nil
+}
+
+func (r *DiskValidationJobResource) obj() (k8sclient.Object, error) {
+	duration := defaultDiskValidationDuration
+	if d := r.pandaCluster.Spec.DiskValidation.Duration; d != nil {
+		duration = d.Duration
+	}
+
+	podLabels := labels.ForCluster(r.pandaCluster)
+	// The benchmark pod must not be selected by the Services and the
+	// PodDisruptionBudget of the brokers
+	podLabels[labels.ComponentKey] = diskValidationComponent
+
+	pvc := preparePVCResource(datadirName, r.pandaCluster.Namespace, r.pandaCluster.Spec.Storage, podLabels)
+	obj := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.Key().Name,
+			Namespace:   r.Key().Namespace,
+			Labels:      withCommonLabels(r.pandaCluster, labels.ForCluster(r.pandaCluster)),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          pointer.Int32Ptr(0),
+			ActiveDeadlineSeconds: pointer.Int64Ptr(int64((duration + diskValidationTimeout).Seconds())),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					SecurityContext: &corev1.PodSecurityContext{
+						FSGroup: pointer.Int64Ptr(fsGroup),
+					},
+					ImagePullSecrets: r.pandaCluster.Spec.ImagePullSecrets,
+					Tolerations:      r.pandaCluster.Spec.Tolerations,
+					NodeSelector:     r.pandaCluster.Spec.NodeSelector,
+					Containers: []corev1.Container{
+						{
+							Name:  diskValidationContainerName,
+							Image: r.pandaCluster.FullImageName(),
+							Command: []string{
+								"rpk", "iotune",
+								"--directories", dataDirectory,
+								"--duration", duration.String(),
+								"--timeout", diskValidationTimeout.String(),
+								"--out", corev1.TerminationMessagePathDefault,
+								"--no-confirm",
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      datadirName,
+									MountPath: dataDirectory,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: datadirName,
+							VolumeSource: corev1.VolumeSource{
+								Ephemeral: &corev1.EphemeralVolumeSource{
+									VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+										ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+										Spec:       pvc.Spec,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := controllerutil.SetControllerReference(r.pandaCluster, obj, r.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Key returns namespace/name object that is used to identify object.
+func (r *DiskValidationJobResource) Key() types.NamespacedName {
+	return types.NamespacedName{Name: r.pandaCluster.Name + "-disk-validation", Namespace: r.pandaCluster.Namespace}
+}
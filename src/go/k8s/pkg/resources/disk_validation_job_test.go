@@ -0,0 +1,80 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources //nolint:testpackage // needed to test private method
+
+import (
+	"testing"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestEvaluateDiskValidation(t *testing.T) {
+	const output = `disks:
+- mountpoint: /var/lib/redpanda/data
+  read_iops: 20000
+  read_bandwidth: 524288000
+  write_iops: 10000
+  write_bandwidth: 52428800
+`
+	minBw := resource.MustParse("100Mi")
+
+	for _, test := range []struct {
+		name       string
+		cfg        redpandav1alpha1.DiskValidationConfig
+		output     string
+		expPassed  bool
+		expMessage string
+		expErr     bool
+	}{
+		{
+			name:      "no expectations",
+			output:    output,
+			expPassed: true,
+		},
+		{
+			name:      "meets expectations",
+			cfg:       redpandav1alpha1.DiskValidationConfig{MinWriteIOPS: 5000},
+			output:    output,
+			expPassed: true,
+		},
+		{
+			name:       "below expectations",
+			cfg:        redpandav1alpha1.DiskValidationConfig{MinWriteBandwidth: &minBw, MinWriteIOPS: 20000},
+			output:     output,
+			expMessage: "write bandwidth 50Mi/s is below the expected 100Mi/s; write IOPS 10000 is below the expected 20000",
+		},
+		{
+			name:   "no disks",
+			output: "disks: []",
+			expErr: true,
+		},
+		{
+			name:   "invalid output",
+			output: "iotune failed",
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.cfg
+			status, err := evaluateDiskValidation(&cfg, test.output)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int64(52428800), status.WriteBandwidth)
+			require.Equal(t, int64(20000), status.ReadIOPS)
+			require.Equal(t, test.expPassed, status.Passed)
+			require.Equal(t, test.expMessage, status.Message)
+		})
+	}
+}
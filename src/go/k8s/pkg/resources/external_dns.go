@@ -0,0 +1,88 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+)
+
+const (
+	// ExternalDNSHostnameAnnotation is the annotation read by external-dns to
+	// create DNS records for a Service or an Ingress
+	ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	// ExternalDNSTTLAnnotation is the annotation read by external-dns to set
+	// the TTL of the DNS records
+	ExternalDNSTTLAnnotation = "external-dns.alpha.kubernetes.io/ttl"
+)
+
+// withExternalDNSAnnotations returns a copy of the annotations including the
+// external-dns annotations for the given configuration. The hostname defaults
+// to defaultHostname and is followed by the extra hostnames, if any.
+// Annotations that are already set are not overridden.
+func withExternalDNSAnnotations(
+	annotations map[string]string,
+	cfg *redpandav1alpha1.ExternalDNSConfig,
+	defaultHostname string,
+	extraHostnames ...string,
+) map[string]string {
+	if cfg == nil {
+		return annotations
+	}
+	result := make(map[string]string, len(annotations)+2)
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname = defaultHostname
+	}
+	var hostnames []string
+	if hostname != "" {
+		hostnames = append(hostnames, hostname)
+	}
+	hostnames = append(hostnames, extraHostnames...)
+	if len(hostnames) > 0 {
+		// external-dns creates a record for each of the comma separated
+		// hostnames
+		result[ExternalDNSHostnameAnnotation] = strings.Join(hostnames, ",")
+	}
+	if cfg.TTL > 0 {
+		result[ExternalDNSTTLAnnotation] = strconv.Itoa(cfg.TTL)
+	}
+	for k, v := range annotations {
+		result[k] = v
+	}
+	return result
+}
+
+// brokerEndpointHostnames returns the hostnames advertised by the brokers on
+// the external Kafka listener, i.e. <endpoint>.<subdomain> for each replica,
+// or nil if the brokers do not advertise a subdomain
+func brokerEndpointHostnames(
+	cluster *redpandav1alpha1.Cluster,
+) ([]string, error) {
+	ext := cluster.ExternalListener()
+	if ext == nil || ext.External.Subdomain == "" || cluster.Spec.Replicas == nil {
+		return nil, nil
+	}
+	hostnames := make([]string, 0, *cluster.Spec.Replicas)
+	for i := 0; i < int(*cluster.Spec.Replicas); i++ {
+		// The webhook rejects templates using the host IP together with
+		// external-dns, as the records are not bound to a host
+		ep, err := utils.ComputeEndpoint(ext.External.EndpointTemplate, utils.NewEndpointTemplateData(i, ""))
+		if err != nil {
+			return nil, err
+		}
+		hostnames = append(hostnames, fmt.Sprintf("%s.%s", ep, ext.External.Subdomain))
+	}
+	return hostnames, nil
+}
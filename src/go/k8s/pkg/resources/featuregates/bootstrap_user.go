@@ -0,0 +1,33 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package featuregates
+
+import "github.com/Masterminds/semver/v3"
+
+const (
+	bootstrapUserMajor = uint64(22)
+	bootstrapUserMinor = uint64(3)
+)
+
+// BootstrapUser returns whether the version creates the user of the
+// RP_BOOTSTRAP_USER environment variable when the cluster is formed, which
+// lets clients authenticate from its first start.
+func BootstrapUser(version string) bool {
+	if version == devVersion {
+		// development version contains this feature
+		return true
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	return v.Major() == bootstrapUserMajor && v.Minor() >= bootstrapUserMinor || v.Major() > bootstrapUserMajor
+}
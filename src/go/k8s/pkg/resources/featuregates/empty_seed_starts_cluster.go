@@ -0,0 +1,33 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package featuregates
+
+import "github.com/Masterminds/semver/v3"
+
+const (
+	emptySeedStartsClusterMajor = uint64(22)
+	emptySeedStartsClusterMinor = uint64(3)
+)
+
+// EmptySeedStartsCluster returns whether the version supports the
+// empty_seed_starts_cluster property, which lets a set of seed servers form a
+// cluster together, instead of the broker with no seed server.
+func EmptySeedStartsCluster(version string) bool {
+	if version == devVersion {
+		// development version contains this feature
+		return true
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	return v.Major() == emptySeedStartsClusterMajor && v.Minor() >= emptySeedStartsClusterMinor || v.Major() > emptySeedStartsClusterMajor
+}
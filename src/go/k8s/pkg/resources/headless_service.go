@@ -99,8 +99,8 @@ func (r *HeadlessServiceResource) obj() (k8sclient.Object, error) {
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   r.Key().Namespace,
 			Name:        r.Key().Name,
-			Labels:      objLabels,
-			Annotations: r.getAnnotation(),
+			Labels:      withCommonLabels(r.pandaCluster, objLabels),
+			Annotations: withCommonAnnotations(r.pandaCluster, r.getAnnotation()),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Service",
@@ -112,6 +112,8 @@ func (r *HeadlessServiceResource) obj() (k8sclient.Object, error) {
 			ClusterIP:                corev1.ClusterIPNone,
 			Ports:                    ports,
 			Selector:                 objLabels.AsAPISelector().MatchLabels,
+			IPFamilies:               r.pandaCluster.IPFamilies(),
+			IPFamilyPolicy:           r.pandaCluster.IPFamilyPolicy(),
 		},
 	}
 
@@ -15,11 +15,14 @@ import (
 
 	"github.com/go-logr/logr"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
 	netv1 "k8s.io/api/networking/v1"
+	netv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -27,6 +30,10 @@ import (
 const (
 	nginx = "nginx"
 
+	// ingressClassAnnotation selects the ingress controller on clusters that
+	// do not serve IngressClasses
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+
 	// SSLPassthroughAnnotation is the annotation for ingress nginx SSL passthrough
 	SSLPassthroughAnnotation = "nginx.ingress.kubernetes.io/ssl-passthrough" //nolint:gosec // This value does not contain credentials.
 
@@ -49,7 +56,9 @@ type IngressResource struct {
 	svcPortName string
 	annotations map[string]string
 	TLS         []netv1.IngressTLS
-	logger      logr.Logger
+	// capabilities of the Kubernetes cluster, nil if unknown
+	capabilities *capabilities.Capabilities
+	logger       logr.Logger
 }
 
 // NewIngress creates IngressResource
@@ -71,6 +80,7 @@ func NewIngress(
 		svcPortName,
 		nil,
 		nil,
+		nil,
 		logger.WithValues(
 			"Kind", ingressKind(),
 		),
@@ -105,6 +115,25 @@ func (r *IngressResource) WithTLS(clusterIssuer, secretName string) *IngressReso
 	return r
 }
 
+// WithExternalDNS sets the external-dns annotations of the IngressResource,
+// defaulting the hostname to the host of the Ingress
+func (r *IngressResource) WithExternalDNS(
+	cfg *redpandav1alpha1.ExternalDNSConfig,
+) *IngressResource {
+	r.annotations = withExternalDNSAnnotations(r.annotations, cfg, r.host)
+	return r
+}
+
+// WithCapabilities adapts the Ingress to the APIs served by the Kubernetes
+// cluster: the Ingress is created with the most recent API served, or not at
+// all if none is supported.
+func (r *IngressResource) WithCapabilities(
+	c *capabilities.Capabilities,
+) *IngressResource {
+	r.capabilities = c
+	return r
+}
+
 // GetAnnotations returns the annotations for the Ingress resource
 func (r *IngressResource) GetAnnotations() map[string]string {
 	return r.annotations
@@ -116,6 +145,10 @@ func (r *IngressResource) Ensure(ctx context.Context) error {
 		r.logger.V(debugLogLevel).Info("host not found, skip ensuring ingress")
 		return nil
 	}
+	if !r.capabilities.SupportsIngress() {
+		r.logger.Info("Kubernetes serves no supported Ingress API, skip ensuring ingress", "version", r.capabilities.ServerVersion)
+		return nil
+	}
 
 	obj, err := r.obj()
 	if err != nil {
@@ -125,12 +158,15 @@ func (r *IngressResource) Ensure(ctx context.Context) error {
 	if err != nil || created {
 		return err
 	}
-	var ingress netv1.Ingress
-	err = r.Get(ctx, r.Key(), &ingress)
+	var ingress k8sclient.Object = &netv1.Ingress{}
+	if r.capabilities.IngressVersion() == netv1beta1.SchemeGroupVersion.String() {
+		ingress = &netv1beta1.Ingress{}
+	}
+	err = r.Get(ctx, r.Key(), ingress)
 	if err != nil {
 		return fmt.Errorf("error while fetching Ingress resource: %w", err)
 	}
-	_, err = Update(ctx, &ingress, obj, r.Client, r.logger)
+	_, err = Update(ctx, ingress, obj, r.Client, r.logger)
 	return err
 }
 
@@ -143,6 +179,17 @@ func (r *IngressResource) obj() (k8sclient.Object, error) {
 		return nil, fmt.Errorf("cannot get object labels: %w", err)
 	}
 
+	annotations := r.annotations
+	className := &ingressClassName
+	if !r.capabilities.SupportsIngressClass() {
+		annotations = make(map[string]string, len(r.annotations)+1)
+		for k, v := range r.annotations {
+			annotations[k] = v
+		}
+		annotations[ingressClassAnnotation] = ingressClassName
+		className = nil
+	}
+
 	ingress := &netv1.Ingress{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Ingress",
@@ -151,11 +198,11 @@ func (r *IngressResource) obj() (k8sclient.Object, error) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        r.Key().Name,
 			Namespace:   r.Key().Namespace,
-			Labels:      objLabels,
-			Annotations: r.annotations,
+			Labels:      withCommonLabels(r.object, objLabels),
+			Annotations: withCommonAnnotations(r.object, annotations),
 		},
 		Spec: netv1.IngressSpec{
-			IngressClassName: &ingressClassName,
+			IngressClassName: className,
 			Rules: []netv1.IngressRule{
 				{
 					Host: r.host,
@@ -183,12 +230,62 @@ func (r *IngressResource) obj() (k8sclient.Object, error) {
 		},
 	}
 
-	err = controllerutil.SetControllerReference(r.object, ingress, r.scheme)
+	var obj k8sclient.Object = ingress
+	if r.capabilities.IngressVersion() == netv1beta1.SchemeGroupVersion.String() {
+		obj = ingressV1beta1(ingress)
+	}
+
+	err = controllerutil.SetControllerReference(r.object, obj, r.scheme)
 	if err != nil {
 		return nil, err
 	}
 
-	return ingress, nil
+	return obj, nil
+}
+
+// ingressV1beta1 converts the Ingress to the networking.k8s.io/v1beta1 API,
+// for Kubernetes clusters older than 1.19.
+func ingressV1beta1(ingress *netv1.Ingress) *netv1beta1.Ingress {
+	converted := &netv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: netv1beta1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: ingress.ObjectMeta,
+		Spec: netv1beta1.IngressSpec{
+			IngressClassName: ingress.Spec.IngressClassName,
+		},
+	}
+	for _, tls := range ingress.Spec.TLS {
+		converted.Spec.TLS = append(converted.Spec.TLS, netv1beta1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+	for _, rule := range ingress.Spec.Rules {
+		convertedRule := netv1beta1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			convertedRule.HTTP = &netv1beta1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				convertedPath := netv1beta1.HTTPIngressPath{Path: path.Path}
+				if path.PathType != nil {
+					pathType := netv1beta1.PathType(*path.PathType)
+					convertedPath.PathType = &pathType
+				}
+				if svc := path.Backend.Service; svc != nil {
+					convertedPath.Backend.ServiceName = svc.Name
+					if svc.Port.Name != "" {
+						convertedPath.Backend.ServicePort = intstr.FromString(svc.Port.Name)
+					} else {
+						convertedPath.Backend.ServicePort = intstr.FromInt(int(svc.Port.Number))
+					}
+				}
+				convertedRule.HTTP.Paths = append(convertedRule.HTTP.Paths, convertedPath)
+			}
+		}
+		converted.Spec.Rules = append(converted.Spec.Rules, convertedRule)
+	}
+	return converted
 }
 
 // Key returns namespace/name object that is used to identify object.
@@ -1,11 +1,20 @@
 package resources_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/capabilities"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
 	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	netv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestIngressWithTLS(t *testing.T) {
@@ -48,3 +57,83 @@ func TestIngressWithTLS(t *testing.T) {
 		require.True(t, found)
 	}
 }
+
+func TestIngressWithExternalDNS(t *testing.T) {
+	table := []struct {
+		name     string
+		cfg      *redpandav1alpha1.ExternalDNSConfig
+		expected map[string]string
+	}{
+		{
+			name:     "disabled",
+			expected: map[string]string{resources.SSLPassthroughAnnotation: "true"},
+		},
+		{
+			name: "default hostname",
+			cfg:  &redpandav1alpha1.ExternalDNSConfig{},
+			expected: map[string]string{
+				resources.SSLPassthroughAnnotation:      "true",
+				resources.ExternalDNSHostnameAnnotation: "test.example.local",
+			},
+		},
+		{
+			name: "hostname and ttl",
+			cfg:  &redpandav1alpha1.ExternalDNSConfig{Hostname: "proxy.example.local", TTL: 60},
+			expected: map[string]string{
+				resources.SSLPassthroughAnnotation:      "true",
+				resources.ExternalDNSHostnameAnnotation: "proxy.example.local",
+				resources.ExternalDNSTTLAnnotation:      "60",
+			},
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress := resources.NewIngress(nil, nil, nil, "test.example.local", "", "", logr.Discard()).
+				WithAnnotations(map[string]string{resources.SSLPassthroughAnnotation: "true"}).
+				WithExternalDNS(tt.cfg)
+			require.Equal(t, tt.expected, ingress.GetAnnotations())
+		})
+	}
+}
+
+func TestIngressCapabilities(t *testing.T) {
+	require.NoError(t, redpandav1alpha1.AddToScheme(scheme.Scheme))
+	// The UID is the merge key of the owner references in the update patch
+	cluster := &redpandav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default", UID: "cluster-uid"},
+	}
+	newIngress := func(c *capabilities.Capabilities) *resources.IngressResource {
+		return resources.NewIngress(fake.NewClientBuilder().Build(), cluster, scheme.Scheme, "test.example.local", "cluster", "proxy", logr.Discard()).
+			WithCapabilities(c)
+	}
+
+	t.Run("v1", func(t *testing.T) {
+		ingress := newIngress(nil)
+		require.NoError(t, ingress.Ensure(context.Background()))
+		var created netv1.Ingress
+		require.NoError(t, ingress.Get(context.Background(), ingress.Key(), &created))
+		require.Equal(t, "nginx", *created.Spec.IngressClassName)
+		require.Equal(t, "proxy", created.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name)
+	})
+
+	t.Run("v1beta1 without ingress class", func(t *testing.T) {
+		ingress := newIngress(&capabilities.Capabilities{ServerVersion: "v1.16.15", IngressAPIVersion: "networking.k8s.io/v1beta1"})
+		require.NoError(t, ingress.Ensure(context.Background()))
+		var created netv1beta1.Ingress
+		require.NoError(t, ingress.Get(context.Background(), ingress.Key(), &created))
+		require.Nil(t, created.Spec.IngressClassName)
+		require.Equal(t, "nginx", created.Annotations["kubernetes.io/ingress.class"])
+		backend := created.Spec.Rules[0].HTTP.Paths[0].Backend
+		require.Equal(t, "cluster", backend.ServiceName)
+		require.Equal(t, "proxy", backend.ServicePort.String())
+		// Ensuring again updates the v1beta1 Ingress
+		require.NoError(t, ingress.Ensure(context.Background()))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		ingress := newIngress(&capabilities.Capabilities{ServerVersion: "v1.13.12"})
+		require.NoError(t, ingress.Ensure(context.Background()))
+		err := ingress.Get(context.Background(), ingress.Key(), &netv1.Ingress{})
+		require.True(t, apierrors.IsNotFound(err))
+	})
+}
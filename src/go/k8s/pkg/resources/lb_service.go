@@ -96,13 +96,17 @@ func (r *LoadBalancerServiceResource) obj() (k8sclient.Object, error) {
 		})
 	}
 
+	annotations, err := r.getAnnotation()
+	if err != nil {
+		return nil, err
+	}
 	objLabels := labels.ForCluster(r.pandaCluster)
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   r.Key().Namespace,
 			Name:        r.Key().Name,
-			Labels:      objLabels,
-			Annotations: r.getAnnotation(),
+			Labels:      withCommonLabels(r.pandaCluster, objLabels),
+			Annotations: withCommonAnnotations(r.pandaCluster, annotations),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Service",
@@ -113,10 +117,12 @@ func (r *LoadBalancerServiceResource) obj() (k8sclient.Object, error) {
 			Type:                     corev1.ServiceTypeLoadBalancer,
 			Ports:                    ports,
 			Selector:                 objLabels.AsAPISelector().MatchLabels,
+			IPFamilies:               r.pandaCluster.IPFamilies(),
+			IPFamilyPolicy:           r.pandaCluster.IPFamilyPolicy(),
 		},
 	}
 
-	err := controllerutil.SetControllerReference(r.pandaCluster, svc, r.scheme)
+	err = controllerutil.SetControllerReference(r.pandaCluster, svc, r.scheme)
 	if err != nil {
 		return nil, err
 	}
@@ -136,10 +142,20 @@ func (r *LoadBalancerServiceResource) Key() types.NamespacedName {
 	return types.NamespacedName{Name: name, Namespace: r.pandaCluster.Namespace}
 }
 
-func (r *LoadBalancerServiceResource) getAnnotation() map[string]string {
+// getAnnotation returns the annotations of the bootstrap load balancer. With
+// external-dns, a record is requested for the bootstrap hostname and for the
+// endpoint of each broker.
+func (r *LoadBalancerServiceResource) getAnnotation() (map[string]string, error) {
 	ext := r.pandaCluster.ExternalListener()
 	if !r.isBootstrap || ext == nil || ext.External.Bootstrap == nil {
-		return nil
+		return nil, nil
+	}
+	if ext.External.ExternalDNS == nil {
+		return ext.External.Bootstrap.Annotations, nil
+	}
+	brokers, err := brokerEndpointHostnames(r.pandaCluster)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute the broker endpoints: %w", err)
 	}
-	return ext.External.Bootstrap.Annotations
+	return withExternalDNSAnnotations(ext.External.Bootstrap.Annotations, ext.External.ExternalDNS, ext.External.Subdomain, brokers...), nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources //nolint:testpackage // needed to test private method
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+)
+
+func TestLoadBalancerServiceExternalDNS(t *testing.T) {
+	cluster := func(external redpandav1alpha1.ExternalConnectivityConfig) *redpandav1alpha1.Cluster {
+		external.Enabled = true
+		external.Bootstrap = &redpandav1alpha1.LoadBalancerConfig{
+			Annotations: map[string]string{"cloud.example.com/internal": "true"},
+			Port:        9092,
+		}
+		return &redpandav1alpha1.Cluster{
+			Spec: redpandav1alpha1.ClusterSpec{
+				Replicas: pointer.Int32Ptr(3),
+				Configuration: redpandav1alpha1.RedpandaConfig{
+					KafkaAPI: []redpandav1alpha1.KafkaAPI{{Port: 9092}, {External: external}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		external redpandav1alpha1.ExternalConnectivityConfig
+		expected map[string]string
+	}{
+		{
+			name:     "disabled",
+			external: redpandav1alpha1.ExternalConnectivityConfig{Subdomain: "kafka.example.com"},
+			expected: map[string]string{"cloud.example.com/internal": "true"},
+		},
+		{
+			name: "bootstrap and broker records",
+			external: redpandav1alpha1.ExternalConnectivityConfig{
+				Subdomain:   "kafka.example.com",
+				ExternalDNS: &redpandav1alpha1.ExternalDNSConfig{TTL: 60},
+			},
+			expected: map[string]string{
+				"cloud.example.com/internal":  "true",
+				ExternalDNSHostnameAnnotation: "kafka.example.com,0.kafka.example.com,1.kafka.example.com,2.kafka.example.com",
+				ExternalDNSTTLAnnotation:      "60",
+			},
+		},
+		{
+			name: "endpoint template and hostname",
+			external: redpandav1alpha1.ExternalConnectivityConfig{
+				Subdomain:        "kafka.example.com",
+				EndpointTemplate: "broker-{{.Index}}",
+				ExternalDNS:      &redpandav1alpha1.ExternalDNSConfig{Hostname: "bootstrap.example.com"},
+			},
+			expected: map[string]string{
+				"cloud.example.com/internal":  "true",
+				ExternalDNSHostnameAnnotation: "bootstrap.example.com,broker-0.kafka.example.com,broker-1.kafka.example.com,broker-2.kafka.example.com",
+			},
+		},
+		{
+			name: "no subdomain",
+			external: redpandav1alpha1.ExternalConnectivityConfig{
+				ExternalDNS: &redpandav1alpha1.ExternalDNSConfig{Hostname: "bootstrap.example.com"},
+			},
+			expected: map[string]string{
+				"cloud.example.com/internal":  "true",
+				ExternalDNSHostnameAnnotation: "bootstrap.example.com",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := NewLoadBalancerService(nil, cluster(tt.external), nil, nil, true, logr.Discard())
+			annotations, err := lb.getAnnotation()
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, annotations)
+		})
+	}
+}
@@ -109,9 +109,10 @@ func (r *NodePortServiceResource) obj() (k8sclient.Object, error) {
 	objLabels := labels.ForCluster(r.pandaCluster)
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.Key().Namespace,
-			Name:      r.Key().Name,
-			Labels:    objLabels,
+			Namespace:   r.Key().Namespace,
+			Name:        r.Key().Name,
+			Labels:      withCommonLabels(r.pandaCluster, objLabels),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Service",
@@ -133,7 +134,9 @@ func (r *NodePortServiceResource) obj() (k8sclient.Object, error) {
 			Ports:                 ports,
 			// The selector is purposely set to nil. Our external connectivity doesn't use
 			// kubernetes service as kafka protocol need to have access to each broker individually.
-			Selector: nil,
+			Selector:       nil,
+			IPFamilies:     r.pandaCluster.IPFamilies(),
+			IPFamilyPolicy: r.pandaCluster.IPFamilyPolicy(),
 		},
 	}
 
@@ -81,8 +81,10 @@ func (r *PDBResource) obj() (k8sclient.Object, error) {
 	objLabels := labels.ForCluster(r.pandaCluster)
 	obj := &policyv1beta1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Key().Name,
-			Namespace: r.Key().Namespace,
+			Name:        r.Key().Name,
+			Namespace:   r.Key().Namespace,
+			Labels:      withCommonLabels(r.pandaCluster, objLabels),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PodDisruptionBudget",
@@ -0,0 +1,110 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	errNoInternalKafkaListener = errors.New("the cluster has no internal Kafka listener")
+	errKafkaTLSUnsupported     = errors.New("the internal Kafka listener requires TLS, which is not supported to list topics")
+	errNoSuperuserCredentials  = errors.New("no superuser Secret to authenticate to the Kafka API, it requires Pandaproxy or Schema Registry to be enabled")
+)
+
+// MaxReplicationFactorFunc returns the highest replication factor among the
+// topics of a cluster
+type MaxReplicationFactorFunc func(context.Context, k8sclient.Client, *redpandav1alpha1.Cluster) (int, error)
+
+// MaxReplicationFactor lists the topics of the cluster, internal ones
+// included, over its internal Kafka listener, and returns their highest
+// replication factor. With SASL, it authenticates as the Pandaproxy or the
+// Schema Registry superuser.
+func MaxReplicationFactor(
+	ctx context.Context, cl k8sclient.Client, cluster *redpandav1alpha1.Cluster,
+) (int, error) {
+	l := cluster.InternalListener()
+	if l == nil {
+		return 0, errNoInternalKafkaListener
+	}
+	if l.TLS.Enabled {
+		return 0, errKafkaTLSUnsupported
+	}
+	var brokers []string
+	for _, host := range cluster.Status.Nodes.Internal {
+		brokers = append(brokers, net.JoinHostPort(host, strconv.Itoa(l.Port)))
+	}
+	opts := []kgo.Opt{kgo.SeedBrokers(brokers...)}
+	if cluster.IsSASLOnInternalEnabled() {
+		mech, err := superuserScram(ctx, cl, cluster)
+		if err != nil {
+			return 0, err
+		}
+		opts = append(opts, kgo.SASL(mech.AsSha256Mechanism()))
+	}
+
+	kclient, err := kgo.NewClient(opts...)
+	if err != nil {
+		return 0, fmt.Errorf("creating kafka client: %w", err)
+	}
+	defer kclient.Close()
+	topics, err := kadm.NewClient(kclient).ListTopicsWithInternal(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing topics: %w", err)
+	}
+
+	var maxReplicationFactor int
+	for _, t := range topics {
+		if t.Err != nil {
+			return 0, fmt.Errorf("listing topic %s: %w", t.Topic, t.Err)
+		}
+		for _, p := range t.Partitions {
+			if len(p.Replicas) > maxReplicationFactor {
+				maxReplicationFactor = len(p.Replicas)
+			}
+		}
+	}
+	return maxReplicationFactor, nil
+}
+
+func superuserScram(
+	ctx context.Context, cl k8sclient.Client, cluster *redpandav1alpha1.Cluster,
+) (scram.Auth, error) {
+	var suffix string
+	switch {
+	case cluster.PandaproxyAPIInternal() != nil:
+		suffix = PandaProxySuffix
+	case cluster.Spec.Configuration.SchemaRegistry != nil:
+		suffix = SchemaRegistrySuffix
+	default:
+		return scram.Auth{}, errNoSuperuserCredentials
+	}
+	var secret corev1.Secret
+	key := types.NamespacedName{Name: resourceNameTrim(cluster.Name, suffix), Namespace: cluster.Namespace}
+	if err := cl.Get(ctx, key, &secret); err != nil {
+		return scram.Auth{}, fmt.Errorf("getting superuser Secret %s: %w", key.Name, err)
+	}
+	return scram.Auth{
+		User: string(secret.Data[corev1.BasicAuthUsernameKey]),
+		Pass: string(secret.Data[corev1.BasicAuthPasswordKey]),
+	}, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	v1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ Resource = &RoleResource{}
+
+// RoleResource is part of the reconciliation of redpanda.vectorized.io CRD
+// that grants the rules of spec.serviceAccount to the dedicated ServiceAccount
+// of the cluster, in the namespace of the cluster.
+type RoleResource struct {
+	k8sclient.Client
+	scheme       *runtime.Scheme
+	pandaCluster *redpandav1alpha1.Cluster
+	logger       logr.Logger
+}
+
+// NewRole creates RoleResource
+func NewRole(
+	client k8sclient.Client,
+	pandaCluster *redpandav1alpha1.Cluster,
+	scheme *runtime.Scheme,
+	logger logr.Logger,
+) *RoleResource {
+	return &RoleResource{
+		client,
+		scheme,
+		pandaCluster,
+		logger.WithValues("Kind", roleKind()),
+	}
+}
+
+// Ensure manages v1.Role that is assigned to the dedicated v1.ServiceAccount.
+// The Role is deleted if the cluster no longer grants any rule.
+func (r *RoleResource) Ensure(ctx context.Context) error {
+	if len(serviceAccountRules(r.pandaCluster)) == 0 {
+		return deleteIfExists(ctx, r.Client, &v1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: r.Key().Name, Namespace: r.Key().Namespace},
+		})
+	}
+
+	obj, err := r.obj()
+	if err != nil {
+		return fmt.Errorf("unable to construct Role object: %w", err)
+	}
+	created, err := CreateIfNotExists(ctx, r, obj, r.logger)
+	if err != nil || created {
+		return err
+	}
+	var role v1.Role
+	err = r.Get(ctx, r.Key(), &role)
+	if err != nil {
+		return fmt.Errorf("error while fetching Role resource: %w", err)
+	}
+
+	_, err = Update(ctx, &role, obj, r.Client, r.logger)
+	return err
+}
+
+// obj returns resource managed client.Object
+func (r *RoleResource) obj() (k8sclient.Object, error) {
+	role := &v1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.Key().Name,
+			Namespace:   r.Key().Namespace,
+			Labels:      withCommonLabels(r.pandaCluster, nil),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		Rules: serviceAccountRules(r.pandaCluster),
+	}
+
+	err := controllerutil.SetControllerReference(r.pandaCluster, role, r.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Key returns namespace/name object that is used to identify object.
+// For reference please visit types.NamespacedName docs in k8s.io/apimachinery
+func (r *RoleResource) Key() types.NamespacedName {
+	return types.NamespacedName{Name: r.pandaCluster.Name, Namespace: r.pandaCluster.Namespace}
+}
+
+// serviceAccountRules returns the rules granted to the dedicated
+// ServiceAccount of the cluster, if any.
+func serviceAccountRules(cluster *redpandav1alpha1.Cluster) []v1.PolicyRule {
+	cfg := cluster.Spec.ServiceAccount
+	if cfg == nil || cfg.Name != "" {
+		return nil
+	}
+	return cfg.Rules
+}
+
+// deleteIfExists deletes the object, ignoring objects that do not exist.
+func deleteIfExists(
+	ctx context.Context, c k8sclient.Client, obj k8sclient.Object,
+) error {
+	err := c.Delete(ctx, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+	}
+	return nil
+}
+
+func roleKind() string {
+	var r v1.Role
+	return r.Kind
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ Resource = &RoleBindingResource{}
+
+// RoleBindingResource is part of the reconciliation of redpanda.vectorized.io
+// CRD that binds the Role of the cluster to its dedicated ServiceAccount.
+type RoleBindingResource struct {
+	k8sclient.Client
+	scheme       *runtime.Scheme
+	pandaCluster *redpandav1alpha1.Cluster
+	logger       logr.Logger
+}
+
+// NewRoleBinding creates RoleBindingResource
+func NewRoleBinding(
+	client k8sclient.Client,
+	pandaCluster *redpandav1alpha1.Cluster,
+	scheme *runtime.Scheme,
+	logger logr.Logger,
+) *RoleBindingResource {
+	return &RoleBindingResource{
+		client,
+		scheme,
+		pandaCluster,
+		logger.WithValues("Kind", roleBindingKind()),
+	}
+}
+
+// Ensure manages v1.RoleBinding that is assigned to the dedicated
+// v1.ServiceAccount. The RoleBinding is deleted if the cluster no longer
+// grants any rule.
+func (r *RoleBindingResource) Ensure(ctx context.Context) error {
+	if len(serviceAccountRules(r.pandaCluster)) == 0 {
+		return deleteIfExists(ctx, r.Client, &v1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: r.Key().Name, Namespace: r.Key().Namespace},
+		})
+	}
+
+	obj, err := r.obj()
+	if err != nil {
+		return fmt.Errorf("unable to construct RoleBinding object: %w", err)
+	}
+	created, err := CreateIfNotExists(ctx, r, obj, r.logger)
+	if err != nil || created {
+		return err
+	}
+	var rb v1.RoleBinding
+	err = r.Get(ctx, r.Key(), &rb)
+	if err != nil {
+		return fmt.Errorf("error while fetching RoleBinding resource: %w", err)
+	}
+
+	_, err = Update(ctx, &rb, obj, r.Client, r.logger)
+	return err
+}
+
+// obj returns resource managed client.Object
+func (r *RoleBindingResource) obj() (k8sclient.Object, error) {
+	role := &RoleResource{pandaCluster: r.pandaCluster}
+	sa := &ServiceAccountResource{pandaCluster: r.pandaCluster}
+
+	rb := &v1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.Key().Name,
+			Namespace:   r.Key().Namespace,
+			Labels:      withCommonLabels(r.pandaCluster, nil),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		Subjects: []v1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      sa.Key().Name,
+				Namespace: sa.Key().Namespace,
+			},
+		},
+		RoleRef: v1.RoleRef{
+			APIGroup: v1.GroupName,
+			Kind:     "Role",
+			Name:     role.Key().Name,
+		},
+	}
+
+	err := controllerutil.SetControllerReference(r.pandaCluster, rb, r.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb, nil
+}
+
+// Key returns namespace/name object that is used to identify object.
+// For reference please visit types.NamespacedName docs in k8s.io/apimachinery
+func (r *RoleBindingResource) Key() types.NamespacedName {
+	return types.NamespacedName{Name: r.pandaCluster.Name, Namespace: r.pandaCluster.Namespace}
+}
+
+func roleBindingKind() string {
+	var r v1.RoleBinding
+	return r.Kind
+}
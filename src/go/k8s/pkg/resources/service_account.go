@@ -51,7 +51,7 @@ func NewServiceAccount(
 
 // Ensure manages ServiceAccount that is used in initContainer
 func (s *ServiceAccountResource) Ensure(ctx context.Context) error {
-	if s.pandaCluster.ExternalListener() == nil {
+	if !s.dedicated() {
 		return nil
 	}
 
@@ -60,16 +60,90 @@ func (s *ServiceAccountResource) Ensure(ctx context.Context) error {
 		return fmt.Errorf("unable to construct ServiceAccount object: %w", err)
 	}
 
-	_, err = CreateIfNotExists(ctx, s, obj, s.logger)
-	return err
+	created, err := CreateIfNotExists(ctx, s, obj, s.logger)
+	if err != nil || created {
+		return err
+	}
+
+	// Only the labels and annotations are reconciled, the rest of the
+	// ServiceAccount (e.g. its token secrets) is managed by Kubernetes.
+	var sa corev1.ServiceAccount
+	if err := s.Get(ctx, s.Key(), &sa); err != nil {
+		return fmt.Errorf("error while fetching ServiceAccount resource: %w", err)
+	}
+	annotationsUpdated := addMissing(&sa.Annotations, obj.GetAnnotations())
+	labelsUpdated := addMissing(&sa.Labels, obj.GetLabels())
+	if !annotationsUpdated && !labelsUpdated {
+		return nil
+	}
+	s.logger.Info(fmt.Sprintf("ServiceAccount %s metadata changed, updating", s.Key().Name))
+	if err := s.Update(ctx, &sa); err != nil {
+		return fmt.Errorf("unable to update ServiceAccount: %w", err)
+	}
+	return nil
+}
+
+// addMissing sets the entries of want in m, and returns whether m changed.
+func addMissing(m *map[string]string, want map[string]string) bool {
+	updated := false
+	for k, v := range want {
+		if current, ok := (*m)[k]; !ok || current != v {
+			if *m == nil {
+				*m = make(map[string]string)
+			}
+			(*m)[k] = v
+			updated = true
+		}
+	}
+	return updated
+}
+
+// dedicated returns whether the cluster uses a ServiceAccount managed by the
+// operator: either because the configurator needs to look up nodes for
+// external connectivity, because cloud storage authenticates with an IAM
+// role bound to it, or because it is configured in the cluster spec without
+// referencing an existing ServiceAccount.
+func (s *ServiceAccountResource) dedicated() bool {
+	return dedicatedServiceAccount(s.pandaCluster)
+}
+
+func dedicatedServiceAccount(pandaCluster *redpandav1alpha1.Cluster) bool {
+	cfg := pandaCluster.Spec.ServiceAccount
+	if cfg != nil && cfg.Name != "" {
+		return false
+	}
+	return cfg != nil ||
+		pandaCluster.ExternalListener() != nil ||
+		pandaCluster.Spec.CloudStorage.ServiceAccountAnnotations() != nil
+}
+
+// annotations returns the annotations of the ServiceAccount in the cluster
+// spec, and the ones binding the cloud storage IAM role to it.
+func (s *ServiceAccountResource) annotations() map[string]string {
+	var annotations map[string]string
+	if cfg := s.pandaCluster.Spec.ServiceAccount; cfg != nil && len(cfg.Annotations) > 0 {
+		annotations = make(map[string]string, len(cfg.Annotations))
+		for k, v := range cfg.Annotations {
+			annotations[k] = v
+		}
+	}
+	for k, v := range s.pandaCluster.Spec.CloudStorage.ServiceAccountAnnotations() {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[k] = v
+	}
+	return annotations
 }
 
 // obj returns resource managed client.Object
 func (s *ServiceAccountResource) obj() (k8sclient.Object, error) {
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.Key().Name,
-			Namespace: s.Key().Namespace,
+			Name:        s.Key().Name,
+			Namespace:   s.Key().Namespace,
+			Labels:      withCommonLabels(s.pandaCluster, nil),
+			Annotations: withCommonAnnotations(s.pandaCluster, s.annotations()),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ServiceAccount",
@@ -87,7 +161,11 @@ func (s *ServiceAccountResource) obj() (k8sclient.Object, error) {
 
 // Key returns namespace/name object that is used to identify object.
 // For reference please visit types.NamespacedName docs in k8s.io/apimachinery
+// If the cluster references an existing ServiceAccount, its name is returned.
 func (s *ServiceAccountResource) Key() types.NamespacedName {
+	if cfg := s.pandaCluster.Spec.ServiceAccount; cfg != nil && cfg.Name != "" {
+		return types.NamespacedName{Name: cfg.Name, Namespace: s.pandaCluster.Namespace}
+	}
 	return types.NamespacedName{Name: s.pandaCluster.Name, Namespace: s.pandaCluster.Namespace}
 }
 
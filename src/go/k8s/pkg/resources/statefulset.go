@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -54,6 +55,12 @@ const (
 	configSourceDir      = "/mnt/operator"
 	configFile           = "redpanda.yaml"
 
+	configuratorHooksDir = "/etc/configurator/hooks"
+	configuratorHookFile = "hook.sh"
+
+	adminAPIUsernameEnv = "ADMIN_API_USERNAME"
+	adminAPIPasswordEnv = "ADMIN_API_PASSWORD"
+
 	datadirName                  = "datadir"
 	archivalCacheIndexAnchorName = "shadow-index-cache"
 	defaultDatadirCapacity       = "100Gi"
@@ -64,6 +71,9 @@ var (
 	ConfigMapHashAnnotationKey = redpandav1alpha1.GroupVersion.Group + "/configmap-hash"
 	// CentralizedConfigurationHashAnnotationKey contains the hash of the centralized configuration properties that require a restart when changed
 	CentralizedConfigurationHashAnnotationKey = redpandav1alpha1.GroupVersion.Group + "/centralized-configuration-hash"
+	// RestartedAtAnnotationKey contains the time of the last rolling restart
+	// requested through the restart annotation of the cluster
+	RestartedAtAnnotationKey = redpandav1alpha1.GroupVersion.Group + "/restarted-at"
 
 	// terminationGracePeriodSeconds should account for additional delay introduced by hooks
 	terminationGracePeriodSeconds int64 = 120
@@ -97,7 +107,10 @@ type StatefulSetResource struct {
 	// being applied
 	nodeConfigMapHashGetter  func(context.Context) (string, error)
 	adminAPIClientFactory    adminutils.AdminAPIClientFactory
+	maxReplicationFactor     MaxReplicationFactorFunc
 	decommissionWaitInterval time.Duration
+	auditor                  *Auditor
+	podEvictor               PodEvictor
 	logger                   logr.Logger
 
 	LastObservedState *appsv1.StatefulSet
@@ -117,6 +130,7 @@ func NewStatefulSet(
 	configuratorSettings ConfiguratorSettings,
 	nodeConfigMapHashGetter func(context.Context) (string, error),
 	adminAPIClientFactory adminutils.AdminAPIClientFactory,
+	maxReplicationFactor MaxReplicationFactorFunc,
 	decommissionWaitInterval time.Duration,
 	logger logr.Logger,
 ) *StatefulSetResource {
@@ -134,12 +148,31 @@ func NewStatefulSet(
 		configuratorSettings,
 		nodeConfigMapHashGetter,
 		adminAPIClientFactory,
+		maxReplicationFactor,
 		decommissionWaitInterval,
+		nil,
+		nil,
 		logger.WithValues("Kind", statefulSetKind()),
 		nil,
 	}
 }
 
+// WithAuditor sets the Auditor recording the pod restarts and the
+// decommissions of brokers
+func (r *StatefulSetResource) WithAuditor(auditor *Auditor) *StatefulSetResource {
+	r.auditor = auditor
+	return r
+}
+
+// WithPodEvictor sets the PodEvictor restarting the pods of rolling updates,
+// which are deleted if it is not set
+func (r *StatefulSetResource) WithPodEvictor(
+	evictor PodEvictor,
+) *StatefulSetResource {
+	r.podEvictor = evictor
+	return r
+}
+
 // Ensure will manage kubernetes v1.StatefulSet for redpanda.vectorized.io custom resource
 func (r *StatefulSetResource) Ensure(ctx context.Context) error {
 	var sts appsv1.StatefulSet
@@ -276,6 +309,9 @@ func (r *StatefulSetResource) obj(
 		return nil, err
 	}
 	annotations[ConfigMapHashAnnotationKey] = configMapHash
+	if restartedAt := r.pandaCluster.Status.RestartedAt; restartedAt != nil {
+		annotations[RestartedAtAnnotationKey] = restartedAt.UTC().Format(time.RFC3339)
+	}
 	tolerations := r.pandaCluster.Spec.Tolerations
 	nodeSelector := r.pandaCluster.Spec.NodeSelector
 
@@ -307,9 +343,10 @@ func (r *StatefulSetResource) obj(
 
 	ss := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.Key().Namespace,
-			Name:      r.Key().Name,
-			Labels:    clusterLabels,
+			Namespace:   r.Key().Namespace,
+			Name:        r.Key().Name,
+			Labels:      withCommonLabels(r.pandaCluster, clusterLabels),
+			Annotations: withCommonAnnotations(r.pandaCluster, nil),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "StatefulSet",
@@ -327,11 +364,12 @@ func (r *StatefulSetResource) obj(
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        r.pandaCluster.Name,
 					Namespace:   r.pandaCluster.Namespace,
-					Labels:      clusterLabels.AsAPISelector().MatchLabels,
-					Annotations: annotations,
+					Labels:      withCommonLabels(r.pandaCluster, clusterLabels.AsAPISelector().MatchLabels),
+					Annotations: withCommonAnnotations(r.pandaCluster, annotations),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: r.getServiceAccountName(),
+					ImagePullSecrets:   r.pandaCluster.Spec.ImagePullSecrets,
 					SecurityContext: &corev1.PodSecurityContext{
 						FSGroup: pointer.Int64Ptr(fsGroup),
 					},
@@ -418,7 +456,7 @@ func (r *StatefulSetResource) obj(
 									Name:  "HOST_PORT",
 									Value: r.getNodePort(ExternalListenerName),
 								},
-							}, r.pandaproxyEnvVars()...),
+							}, append(r.pandaproxyEnvVars(), r.advertisedPortBaseEnvVars()...)...),
 							SecurityContext: &corev1.SecurityContext{
 								RunAsUser:  pointer.Int64Ptr(userID),
 								RunAsGroup: pointer.Int64Ptr(groupID),
@@ -442,7 +480,7 @@ func (r *StatefulSetResource) obj(
 					Containers: []corev1.Container{
 						{
 							Name:    redpandaContainerName,
-							Image:   r.pandaCluster.FullImageName(),
+							Image:   r.redpandaImage(),
 							Command: []string{"/usr/bin/rpk"},
 							Args: append([]string{
 								"redpanda",
@@ -450,7 +488,7 @@ func (r *StatefulSetResource) obj(
 								"--check=false",
 								r.portsConfiguration(),
 							}, prepareAdditionalArguments(
-								r.pandaCluster.Spec.Configuration.DeveloperMode,
+								r.pandaCluster,
 								r.pandaCluster.Spec.Resources)...),
 							Env: []corev1.EnvVar{
 								{
@@ -549,6 +587,10 @@ func (r *StatefulSetResource) obj(
 		}
 	}
 
+	if name := r.pandaCluster.AdminAPICredentialsSecretName(); name != "" {
+		ss.Spec.Template.Spec.Containers[0].Env = append(ss.Spec.Template.Spec.Containers[0].Env, adminAPICredentialsEnv(name)...)
+	}
+
 	if featuregates.CentralizedConfiguration(r.pandaCluster.Spec.Version) {
 		ss.Spec.Template.Spec.Containers[0].VolumeMounts = append(ss.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
 			Name:      "configmap-dir",
@@ -558,6 +600,7 @@ func (r *StatefulSetResource) obj(
 	}
 
 	setVolumes(ss, r.pandaCluster)
+	setConfiguratorHooks(ss, r.pandaCluster)
 
 	rpkStatusContainer := r.rpkStatusContainer(tlsVolumeMounts)
 	if rpkStatusContainer != nil {
@@ -572,6 +615,35 @@ func (r *StatefulSetResource) obj(
 	return ss, nil
 }
 
+// adminAPICredentialsEnv returns the environment variables of the credentials
+// of the operator: Redpanda creates the user of RP_BOOTSTRAP_USER when the
+// cluster is formed, which lets the operator authenticate from the start, and
+// the maintenance mode hooks authenticate with them.
+func adminAPICredentialsEnv(secretName string) []corev1.EnvVar {
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{
+			Name:      adminAPIUsernameEnv,
+			ValueFrom: secretKey(corev1.BasicAuthUsernameKey),
+		},
+		{
+			Name:      adminAPIPasswordEnv,
+			ValueFrom: secretKey(corev1.BasicAuthPasswordKey),
+		},
+		{
+			Name:  "RP_BOOTSTRAP_USER",
+			Value: fmt.Sprintf("$(%s):$(%s)", adminAPIUsernameEnv, adminAPIPasswordEnv),
+		},
+	}
+}
+
 // getPrestopHook creates a hook that drains the node before shutting down.
 func (r *StatefulSetResource) getPreStopHook() *corev1.Handler {
 	// TODO replace scripts with proper RPK calls
@@ -618,6 +690,9 @@ func (r *StatefulSetResource) composeCURLMaintenanceCommand(
 	adminAPI := r.pandaCluster.AdminAPIInternal()
 
 	cmd := fmt.Sprintf(`curl %s `, options)
+	if r.pandaCluster.AdminAPICredentialsSecretName() != "" {
+		cmd += fmt.Sprintf(`-u "${%s}:${%s}" `, adminAPIUsernameEnv, adminAPIPasswordEnv)
+	}
 
 	tlsConfig := adminAPI.GetTLS()
 	proto := "http"
@@ -640,10 +715,76 @@ func (r *StatefulSetResource) composeCURLMaintenanceCommand(
 	return cmd
 }
 
+// setConfiguratorHooks passes the hooks of the cluster spec to the
+// configurator: inline scripts are passed in an environment variable, while
+// scripts from a ConfigMap are mounted and their path is passed instead.
+func setConfiguratorHooks(
+	ss *appsv1.StatefulSet, cluster *redpandav1alpha1.Cluster,
+) {
+	if cluster.Spec.Configurator == nil {
+		return
+	}
+	hooks := []struct {
+		hook      *redpandav1alpha1.ConfiguratorHook
+		name      string
+		envPrefix string
+	}{
+		{cluster.Spec.Configurator.PreStartHook, "pre-start", "PRE_START_HOOK"},
+		{cluster.Spec.Configurator.PostStartHook, "post-start", "POST_START_HOOK"},
+	}
+	initContainers := ss.Spec.Template.Spec.InitContainers
+	for _, h := range hooks {
+		if h.hook == nil {
+			continue
+		}
+		for i := range initContainers {
+			if initContainers[i].Name != configuratorContainerName {
+				continue
+			}
+			if h.hook.ConfigMapRef == nil {
+				initContainers[i].Env = append(initContainers[i].Env, corev1.EnvVar{
+					Name:  h.envPrefix + "_SCRIPT",
+					Value: h.hook.Script,
+				})
+				continue
+			}
+			volumeName := "configurator-" + h.name + "-hook"
+			mountPath := path.Join(configuratorHooksDir, h.name)
+			initContainers[i].Env = append(initContainers[i].Env, corev1.EnvVar{
+				Name:  h.envPrefix + "_PATH",
+				Value: path.Join(mountPath, configuratorHookFile),
+			})
+			initContainers[i].VolumeMounts = append(initContainers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: mountPath,
+			})
+			ss.Spec.Template.Spec.Volumes = append(ss.Spec.Template.Spec.Volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: h.hook.ConfigMapRef.LocalObjectReference,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  h.hook.ConfigMapRef.Key,
+								Path: configuratorHookFile,
+							},
+						},
+						DefaultMode: pointer.Int32Ptr(0o555),
+						Optional:    h.hook.ConfigMapRef.Optional,
+					},
+				},
+			})
+		}
+	}
+}
+
 // setVolumes manipulates v1.StatefulSet object in order to add cloud storage and
 // Redpanda data volume
 func setVolumes(ss *appsv1.StatefulSet, cluster *redpandav1alpha1.Cluster) {
-	pvcDataDir := preparePVCResource(datadirName, cluster.Namespace, cluster.Spec.Storage, ss.Labels)
+	// The volume claim templates of a StatefulSet cannot be updated: they
+	// do not carry the common labels, which may change.
+	pvcLabels := labels.ForCluster(cluster)
+	pvcDataDir := preparePVCResource(datadirName, cluster.Namespace, cluster.Spec.Storage, pvcLabels)
 	ss.Spec.VolumeClaimTemplates = append(ss.Spec.VolumeClaimTemplates, pvcDataDir)
 	vol := corev1.Volume{
 		Name: datadirName,
@@ -667,7 +808,7 @@ func setVolumes(ss *appsv1.StatefulSet, cluster *redpandav1alpha1.Cluster) {
 	}
 
 	if cluster.Spec.CloudStorage.Enabled && featuregates.ShadowIndex(cluster.Spec.Version) && cluster.Spec.CloudStorage.CacheStorage != nil {
-		pvcArchivalDir := preparePVCResource(archivalCacheIndexAnchorName, cluster.Namespace, *cluster.Spec.CloudStorage.CacheStorage, ss.Labels)
+		pvcArchivalDir := preparePVCResource(archivalCacheIndexAnchorName, cluster.Namespace, *cluster.Spec.CloudStorage.CacheStorage, pvcLabels)
 		ss.Spec.VolumeClaimTemplates = append(ss.Spec.VolumeClaimTemplates, pvcArchivalDir)
 		archivalVol := corev1.Volume{
 			Name: archivalCacheIndexAnchorName,
@@ -699,7 +840,7 @@ func (r *StatefulSetResource) rpkStatusContainer(
 	}
 	return &corev1.Container{
 		Name:    rpkStatusContainerName,
-		Image:   r.pandaCluster.FullImageName(),
+		Image:   r.redpandaImage(),
 		Command: []string{"/usr/local/bin/rpk-status.sh"},
 		Env: []corev1.EnvVar{
 			{
@@ -718,7 +859,7 @@ func (r *StatefulSetResource) rpkStatusContainer(
 }
 
 func prepareAdditionalArguments(
-	developerMode bool,
+	pandaCluster *redpandav1alpha1.Cluster,
 	originalRequests redpandav1alpha1.RedpandaResourceRequirements,
 ) []string {
 	requests := originalRequests.DeepCopy()
@@ -727,14 +868,21 @@ func prepareAdditionalArguments(
 	requestedMemory := requests.RedpandaMemory().Value()
 
 	args := []string{}
-	if developerMode {
+	if pandaCluster.Spec.Configuration.DeveloperMode {
 		args = append(args,
 			"--overprovisioned",
 			"--kernel-page-cache=true",
-			"--default-log-level=debug",
 		)
-	} else {
-		args = append(args, "--default-log-level=info")
+	}
+	args = append(args, "--default-log-level="+pandaCluster.DefaultLogLevel())
+	if levels := pandaCluster.Spec.Configuration.LogLevels; len(levels) > 0 {
+		loggers := make([]string, 0, len(levels))
+		for logger, level := range levels {
+			loggers = append(loggers, logger+"="+level)
+		}
+		// Sorted so that the pods are not restarted by a different order
+		sort.Strings(loggers)
+		args = append(args, "--logger-log-level="+strings.Join(loggers, ":"))
 	}
 
 	// When cpu is not set, all cores are used
@@ -780,6 +928,20 @@ func (r *StatefulSetResource) pandaproxyEnvVars() []corev1.EnvVar {
 	return envs
 }
 
+// advertisedPortBaseEnvVars only sets the advertised port base when it is
+// configured, so that the pods of the other clusters are not restarted by an
+// operator upgrade
+func (r *StatefulSetResource) advertisedPortBaseEnvVars() []corev1.EnvVar {
+	listener := r.pandaCluster.ExternalListener()
+	if listener == nil || listener.External.AdvertisedPortBase == 0 {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  "EXTERNAL_CONNECTIVITY_ADVERTISED_PORT_BASE",
+		Value: strconv.Itoa(listener.External.AdvertisedPortBase),
+	}}
+}
+
 func (r *StatefulSetResource) getNodePort(name string) string {
 	for _, port := range r.nodePortSvc.Spec.Ports {
 		if port.Name == name {
@@ -790,7 +952,9 @@ func (r *StatefulSetResource) getNodePort(name string) string {
 }
 
 func (r *StatefulSetResource) getServiceAccountName() string {
-	if r.pandaCluster.ExternalListener() != nil {
+	if r.pandaCluster.ExternalListener() != nil ||
+		r.pandaCluster.Spec.ServiceAccount != nil ||
+		r.pandaCluster.Spec.CloudStorage.ServiceAccountAnnotations() != nil {
 		return r.serviceAccountName
 	}
 	return ""
@@ -861,8 +1025,21 @@ func statefulSetKind() string {
 	return statefulSet.Kind
 }
 
+// fullConfiguratorImage returns the configurator image set in the operator
+// settings, unless it is overridden in the cluster spec, pulled from the
+// registry mirror of the cluster if one is set.
 func (r *StatefulSetResource) fullConfiguratorImage() string {
-	return fmt.Sprintf("%s:%s", r.configuratorSettings.ConfiguratorBaseImage, r.configuratorSettings.ConfiguratorTag)
+	image := r.configuratorSettings.ConfiguratorBaseImage
+	tag := r.configuratorSettings.ConfiguratorTag
+	if c := r.pandaCluster.Spec.Configurator; c != nil {
+		if c.Image != "" {
+			image = c.Image
+		}
+		if c.Tag != "" {
+			tag = c.Tag
+		}
+	}
+	return fmt.Sprintf("%s:%s", r.pandaCluster.MirroredImage(image), tag)
 }
 
 // Version returns the cluster version specified in the image tag.
@@ -0,0 +1,226 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// redpandaImage returns the image of the brokers: the image of the cluster,
+// unless the canary upgrade to it failed, in which case the brokers keep the
+// image they had before the upgrade.
+func (r *StatefulSetResource) redpandaImage() string {
+	image := r.pandaCluster.FullImageName()
+	if s := r.pandaCluster.Status.Canary; s != nil &&
+		s.Phase == redpandav1alpha1.CanaryPhaseFailed && s.Image == image {
+		return s.BaselineImage
+	}
+	return image
+}
+
+// startCanary starts a canary upgrade if the image of the brokers changes
+// from current to modified, recording the health of the cluster as the
+// baseline of the canary. Rolling back a canary does not start another one.
+func (r *StatefulSetResource) startCanary(
+	ctx context.Context, current, modified *appsv1.StatefulSet,
+) error {
+	if !r.pandaCluster.CanaryUpgradeEnabled() || r.pandaCluster.GetCurrentReplicas() <= 1 {
+		return nil
+	}
+	from, to := statefulSetImage(current), statefulSetImage(modified)
+	if from == to || to != r.pandaCluster.FullImageName() {
+		return nil
+	}
+	if s := r.pandaCluster.Status.Canary; s != nil && s.Image == to && s.Phase == redpandav1alpha1.CanaryPhasePending {
+		return nil
+	}
+
+	adminAPI, err := r.getAdminAPIClient(ctx)
+	if err != nil {
+		return err
+	}
+	health, err := adminAPI.GetHealthOverview(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to record the cluster health before the canary upgrade: %w", err)
+	}
+	r.pandaCluster.Status.Canary = &redpandav1alpha1.CanaryStatus{
+		Image:         to,
+		BaselineImage: from,
+		Phase:         redpandav1alpha1.CanaryPhasePending,
+		Baseline:      canaryHealth(health),
+	}
+	r.logger.Info("Starting canary upgrade", "image", to, "baseline image", from, "baseline", r.pandaCluster.Status.Canary.Baseline)
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to record the canary upgrade in the cluster status: %w", err)
+	}
+	return nil
+}
+
+// isCanaryPending returns whether the next restarted pod is the canary of an
+// image upgrade, which is restarted alone.
+func (r *StatefulSetResource) isCanaryPending() bool {
+	s := r.pandaCluster.Status.Canary
+	return s != nil && s.Phase == redpandav1alpha1.CanaryPhasePending
+}
+
+// canaryRestarted starts the soak of the canary once its pod is deleted.
+func (r *StatefulSetResource) canaryRestarted(
+	ctx context.Context, pod *corev1.Pod,
+) error {
+	now := metav1.Now()
+	r.pandaCluster.Status.Canary.Phase = redpandav1alpha1.CanaryPhaseSoaking
+	r.pandaCluster.Status.Canary.Pod = pod.Name
+	r.pandaCluster.Status.Canary.StartedAt = &now
+	r.logger.Info("Soaking canary", "pod-name", pod.Name, "duration", r.pandaCluster.CanarySoakDuration())
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to record the canary pod in the cluster status: %w", err)
+	}
+	return nil
+}
+
+// soakCanary blocks the rolling update while the canary soaks. The canary
+// fails as soon as its pod restarts, or at the end of the soak if it is not
+// ready or the cluster is less healthy than the baseline. Otherwise the
+// canary succeeds and the rolling update proceeds with the other pods. A
+// failed canary is rolled back by the next reconciliation, see
+// redpandaImage.
+func (r *StatefulSetResource) soakCanary(ctx context.Context) error {
+	status := r.pandaCluster.Status.Canary
+	if status == nil || status.Phase != redpandav1alpha1.CanaryPhaseSoaking {
+		return nil
+	}
+	elapsed := time.Since(status.StartedAt.Time)
+	soak := r.pandaCluster.CanarySoakDuration()
+
+	var pod corev1.Pod
+	err := r.Get(ctx, types.NamespacedName{Name: status.Pod, Namespace: r.pandaCluster.Namespace}, &pod)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("unable to get the canary pod %s: %w", status.Pod, err)
+	case pod.DeletionTimestamp != nil:
+		// the pod running the previous image is terminating
+	default:
+		if restarts := redpandaRestarts(&pod); restarts > 0 {
+			return r.failCanary(ctx, fmt.Sprintf("the canary pod %s restarted %d times", pod.Name, restarts))
+		}
+		if elapsed >= soak && utils.IsPodReady(&pod) {
+			return r.checkCanaryHealth(ctx)
+		}
+	}
+	if elapsed >= soak {
+		return r.failCanary(ctx, fmt.Sprintf("the canary pod %s is not ready after %s", status.Pod, soak))
+	}
+
+	requeue := soak - elapsed
+	if requeue > RequeueDuration {
+		requeue = RequeueDuration
+	}
+	return &RequeueAfterError{
+		RequeueAfter: requeue,
+		Msg:          fmt.Sprintf("soak canary pod %s for %s", status.Pod, (soak - elapsed).Round(time.Second)),
+	}
+}
+
+// checkCanaryHealth compares the health of the cluster at the end of the soak
+// to the baseline of the canary.
+func (r *StatefulSetResource) checkCanaryHealth(ctx context.Context) error {
+	adminAPI, err := r.getAdminAPIClient(ctx)
+	if err != nil {
+		return err
+	}
+	health, err := adminAPI.GetHealthOverview(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to check the cluster health after the canary soak: %w", err)
+	}
+	status := r.pandaCluster.Status.Canary
+	if reason := canaryDegradation(status.Baseline, canaryHealth(health)); reason != "" {
+		return r.failCanary(ctx, reason)
+	}
+
+	status.Phase = redpandav1alpha1.CanaryPhaseSucceeded
+	r.logger.Info("Canary succeeded, upgrading the other pods", "pod-name", status.Pod, "image", status.Image)
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to record the canary success in the cluster status: %w", err)
+	}
+	return nil
+}
+
+// failCanary records the failure of the canary, which is rolled back by the
+// next reconciliation.
+func (r *StatefulSetResource) failCanary(ctx context.Context, reason string) error {
+	status := r.pandaCluster.Status.Canary
+	status.Phase = redpandav1alpha1.CanaryPhaseFailed
+	status.Message = reason
+	r.logger.Info("Canary failed, rolling back", "pod-name", status.Pod, "image", status.Image, "reason", reason)
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to record the canary failure in the cluster status: %w", err)
+	}
+	r.auditor.Record(r.pandaCluster, AuditReasonCanaryRolledBack, status.Pod,
+		"Canary pod %s of image %s failed and is rolled back to image %s: %s", status.Pod, status.Image, status.BaselineImage, reason)
+	return &RequeueError{Msg: "roll back canary"}
+}
+
+// canaryDegradation returns why the cluster is less healthy than the
+// baseline, or an empty string if it is not.
+func canaryDegradation(baseline, health redpandav1alpha1.CanaryHealth) string {
+	switch {
+	case health.NodesDown > baseline.NodesDown:
+		return fmt.Sprintf("%d nodes are down, %d before the upgrade", health.NodesDown, baseline.NodesDown)
+	case health.LeaderlessPartitions > baseline.LeaderlessPartitions:
+		return fmt.Sprintf("%d partitions are leaderless, %d before the upgrade", health.LeaderlessPartitions, baseline.LeaderlessPartitions)
+	case health.UnderReplicatedPartitions > baseline.UnderReplicatedPartitions:
+		return fmt.Sprintf("%d partitions are under-replicated, %d before the upgrade", health.UnderReplicatedPartitions, baseline.UnderReplicatedPartitions)
+	}
+	return ""
+}
+
+func canaryHealth(health admin.ClusterHealthOverview) redpandav1alpha1.CanaryHealth {
+	h := redpandav1alpha1.CanaryHealth{
+		NodesDown:            len(health.NodesDown),
+		LeaderlessPartitions: len(health.LeaderlessPartitions),
+	}
+	if health.UnderReplicatedCount != nil {
+		h.UnderReplicatedPartitions = *health.UnderReplicatedCount
+	}
+	return h
+}
+
+// statefulSetImage returns the image of the redpanda container of sts.
+func statefulSetImage(sts *appsv1.StatefulSet) string {
+	for i := range sts.Spec.Template.Spec.Containers {
+		if c := &sts.Spec.Template.Spec.Containers[i]; c.Name == redpandaContainerName {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+// redpandaRestarts returns how many times the redpanda container of pod
+// restarted.
+func redpandaRestarts(pod *corev1.Pod) int32 {
+	for i := range pod.Status.ContainerStatuses {
+		if s := &pod.Status.ContainerStatuses[i]; s.Name == redpandaContainerName {
+			return s.RestartCount
+		}
+	}
+	return 0
+}
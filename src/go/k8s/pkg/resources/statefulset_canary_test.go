@@ -0,0 +1,143 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources //nolint:testpackage // needed to test private methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCanaryDegradation(t *testing.T) {
+	baseline := redpandav1alpha1.CanaryHealth{LeaderlessPartitions: 1, UnderReplicatedPartitions: 3}
+	for _, test := range []struct {
+		name     string
+		health   redpandav1alpha1.CanaryHealth
+		degraded bool
+	}{
+		{"same", baseline, false},
+		{"healthier", redpandav1alpha1.CanaryHealth{}, false},
+		{"node down", redpandav1alpha1.CanaryHealth{NodesDown: 1, LeaderlessPartitions: 1, UnderReplicatedPartitions: 3}, true},
+		{"leaderless", redpandav1alpha1.CanaryHealth{LeaderlessPartitions: 2, UnderReplicatedPartitions: 3}, true},
+		{"under-replicated", redpandav1alpha1.CanaryHealth{LeaderlessPartitions: 1, UnderReplicatedPartitions: 4}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.degraded, canaryDegradation(baseline, test.health) != "")
+		})
+	}
+}
+
+func canaryCluster() *redpandav1alpha1.Cluster {
+	return &redpandav1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: redpandav1alpha1.ClusterSpec{
+			Image:   "vectorized/redpanda",
+			Version: "v22.2.2",
+			RestartConfig: &redpandav1alpha1.RestartConfig{
+				UpdateStrategy: &redpandav1alpha1.UpdateStrategy{
+					Canary: &redpandav1alpha1.CanaryUpgrade{
+						Enabled:      true,
+						SoakDuration: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRedpandaImage(t *testing.T) {
+	cluster := canaryCluster()
+	r := &StatefulSetResource{pandaCluster: cluster}
+	require.Equal(t, "vectorized/redpanda:v22.2.2", r.redpandaImage())
+
+	cluster.Status.Canary = &redpandav1alpha1.CanaryStatus{
+		Image:         "vectorized/redpanda:v22.2.2",
+		BaselineImage: "vectorized/redpanda:v22.1.7",
+		Phase:         redpandav1alpha1.CanaryPhaseSoaking,
+	}
+	require.Equal(t, "vectorized/redpanda:v22.2.2", r.redpandaImage())
+
+	// A failed canary is rolled back, until the image changes again
+	cluster.Status.Canary.Phase = redpandav1alpha1.CanaryPhaseFailed
+	require.Equal(t, "vectorized/redpanda:v22.1.7", r.redpandaImage())
+	cluster.Spec.Version = "v22.2.3"
+	require.Equal(t, "vectorized/redpanda:v22.2.3", r.redpandaImage())
+}
+
+func TestSoakCanary(t *testing.T) {
+	require.NoError(t, redpandav1alpha1.AddToScheme(scheme.Scheme))
+
+	canaryPod := func(ready bool, restarts int32) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-0", Namespace: "default"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: redpandaContainerName, RestartCount: restarts}},
+			},
+		}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return pod
+	}
+	for _, test := range []struct {
+		name    string
+		pod     *corev1.Pod
+		elapsed time.Duration
+		phase   string
+	}{
+		{"soaking", canaryPod(true, 0), 10 * time.Second, redpandav1alpha1.CanaryPhaseSoaking},
+		{"recreating", nil, 10 * time.Second, redpandav1alpha1.CanaryPhaseSoaking},
+		{"restarted", canaryPod(true, 1), 10 * time.Second, redpandav1alpha1.CanaryPhaseFailed},
+		{"not ready after soak", canaryPod(false, 0), 2 * time.Minute, redpandav1alpha1.CanaryPhaseFailed},
+		{"not recreated after soak", nil, 2 * time.Minute, redpandav1alpha1.CanaryPhaseFailed},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cluster := canaryCluster()
+			startedAt := metav1.NewTime(time.Now().Add(-test.elapsed))
+			cluster.Status.Canary = &redpandav1alpha1.CanaryStatus{
+				Image:         "vectorized/redpanda:v22.2.2",
+				BaselineImage: "vectorized/redpanda:v22.1.7",
+				Phase:         redpandav1alpha1.CanaryPhaseSoaking,
+				Pod:           "cluster-0",
+				StartedAt:     &startedAt,
+			}
+			builder := fake.NewClientBuilder().WithObjects(cluster)
+			if test.pod != nil {
+				builder = builder.WithObjects(test.pod)
+			}
+			r := &StatefulSetResource{
+				Client:       builder.Build(),
+				pandaCluster: cluster,
+				logger:       ctrl.Log.WithName("test"),
+			}
+
+			err := r.soakCanary(context.Background())
+			require.Error(t, err)
+			require.Equal(t, test.phase, cluster.Status.Canary.Phase)
+			if test.phase == redpandav1alpha1.CanaryPhaseSoaking {
+				var requeue *RequeueAfterError
+				require.True(t, errors.As(err, &requeue))
+				require.LessOrEqual(t, requeue.RequeueAfter, RequeueDuration)
+			} else {
+				require.NotEmpty(t, cluster.Status.Canary.Message)
+			}
+		})
+	}
+}
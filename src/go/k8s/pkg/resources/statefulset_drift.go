@@ -0,0 +1,288 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statefulSetFieldManager is the field manager of the server-side apply
+// requests of the operator. The API server tracks the fields each manager
+// set, so fields set by other controllers are left alone on apply.
+const statefulSetFieldManager = "redpanda-operator"
+
+// applyStatefulSet patches the StatefulSet with server-side apply, only
+// sending the fields the operator manages. Unlike an update, this does not
+// remove what admission webhooks or other controllers added to the live
+// object, such as injected sidecars, which would otherwise be re-added on
+// every update and trigger rolling restarts in a loop.
+//
+// When apply patches are not supported, the fields the operator owns are
+// patched with a three way merge against the last applied configuration,
+// like kubectl apply does on the client side.
+func (r *StatefulSetResource) applyStatefulSet(
+	ctx context.Context, current, modified *appsv1.StatefulSet,
+) error {
+	// Keep the last applied annotation in sync: it is the base of the
+	// three way diffs of shouldUpdate and patchOwnedFields.
+	if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(modified); err != nil {
+		return err
+	}
+	applied := modified.DeepCopy()
+	applied.TypeMeta = metav1.TypeMeta{
+		Kind:       "StatefulSet",
+		APIVersion: "apps/v1",
+	}
+	// Apply requests must not carry a resource version, which would turn
+	// them into a compare-and-swap, nor managed fields.
+	applied.ResourceVersion = ""
+	applied.ManagedFields = nil
+	applied.Status = appsv1.StatefulSetStatus{}
+	err := r.Patch(ctx, applied, k8sclient.Apply,
+		k8sclient.FieldOwner(statefulSetFieldManager), k8sclient.ForceOwnership)
+	if !isApplyUnsupported(err) {
+		return err
+	}
+	r.logger.Info("Server-side apply is not supported, patching the fields owned by the operator")
+	return r.patchOwnedFields(ctx, current, modified)
+}
+
+// isApplyUnsupported returns whether the error is the rejection of an apply
+// patch: API servers without server-side apply answer 415, and the object
+// tracker of client-go, which backs fake clients, does not know the type.
+func isApplyUnsupported(err error) bool {
+	return err != nil &&
+		(apierrors.IsUnsupportedMediaType(err) || strings.Contains(err.Error(), "PatchType is not supported"))
+}
+
+// patchOwnedFields sends a strategic merge patch computed from the last
+// applied configuration, the live object and the desired object: fields the
+// operator no longer sets are removed, changed fields are set, and fields
+// only in the live object are left alone. Without a last applied
+// configuration, there is no telling which fields are foreign, and the
+// object is replaced.
+func (r *StatefulSetResource) patchOwnedFields(
+	ctx context.Context, current, modified *appsv1.StatefulSet,
+) error {
+	original, err := patch.DefaultAnnotator.GetOriginalConfiguration(current)
+	if err != nil || original == nil {
+		_, err = Update(ctx, current, modified, r.Client, r.logger)
+		return err
+	}
+	patchResult, err := patch.DefaultPatchMaker.Calculate(current, modified,
+		patch.IgnoreStatusFields(),
+		patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
+		ignoreForeignTemplateFields(&modified.Spec.Template),
+	)
+	if err != nil {
+		return err
+	}
+	if patchResult.IsEmpty() {
+		return nil
+	}
+	return r.Patch(ctx, current, k8sclient.RawPatch(types.StrategicMergePatchType, patchResult.Patch))
+}
+
+// ignoreForeignTemplateFields removes from the current StatefulSet the pod
+// template fields that the operator does not manage, so that they do not
+// count as drift: containers, init containers and volumes with names the
+// operator does not use, the mounts of those volumes, and template labels
+// and annotations the operator does not set.
+//
+// The operator manages what it sets now, in desired, and what it set last,
+// in the last applied configuration: a field it no longer sets, such as a
+// sidecar that was disabled, is drift to remove. Without a last applied
+// configuration, there is no telling which fields are foreign, and none is
+// ignored.
+func ignoreForeignTemplateFields(
+	desired *corev1.PodTemplateSpec,
+) patch.CalculateOption {
+	return func(current, modified []byte) ([]byte, []byte, error) {
+		var sts appsv1.StatefulSet
+		if err := json.Unmarshal(current, &sts); err != nil {
+			return []byte{}, []byte{}, fmt.Errorf("could not unmarshal current statefulset: %w", err)
+		}
+		original, err := patch.DefaultAnnotator.GetOriginalConfiguration(&sts)
+		if err != nil || original == nil {
+			return current, modified, nil
+		}
+		var applied appsv1.StatefulSet
+		if err := json.Unmarshal(original, &applied); err != nil {
+			return []byte{}, []byte{}, fmt.Errorf("could not unmarshal last applied statefulset: %w", err)
+		}
+		claims := make([]string, 0, len(sts.Spec.VolumeClaimTemplates))
+		for i := range sts.Spec.VolumeClaimTemplates {
+			claims = append(claims, sts.Spec.VolumeClaimTemplates[i].Name)
+		}
+		owned := []*corev1.PodTemplateSpec{desired, &applied.Spec.Template}
+		removeForeignPodFields(&sts.Spec.Template.ObjectMeta, &sts.Spec.Template.Spec, owned, claims)
+		current, err = json.Marshal(sts)
+		if err != nil {
+			return []byte{}, []byte{}, fmt.Errorf("could not marshal current statefulset: %w", err)
+		}
+		return current, modified, nil
+	}
+}
+
+// ignoreForeignPodFields is ignoreForeignTemplateFields for the pods created
+// from the template, which is what webhooks injecting sidecars mutate. The
+// fields of a pod the operator manages are those of the desired template,
+// and those of the template revision the pod was created from, if known.
+func ignoreForeignPodFields(
+	desired, revision *corev1.PodTemplateSpec,
+) patch.CalculateOption {
+	return func(current, modified []byte) ([]byte, []byte, error) {
+		var pod corev1.Pod
+		if err := json.Unmarshal(current, &pod); err != nil {
+			return []byte{}, []byte{}, fmt.Errorf("could not unmarshal current pod: %w", err)
+		}
+		removeForeignPodFields(&pod.ObjectMeta, &pod.Spec, []*corev1.PodTemplateSpec{desired, revision}, nil)
+		current, err := json.Marshal(pod)
+		if err != nil {
+			return []byte{}, []byte{}, fmt.Errorf("could not marshal current pod: %w", err)
+		}
+		return current, modified, nil
+	}
+}
+
+// podRevisionTemplate returns the pod template of the StatefulSet revision
+// that the pod was created from, or nil if the revision is not known.
+func (r *StatefulSetResource) podRevisionTemplate(
+	ctx context.Context, pod *corev1.Pod,
+) (*corev1.PodTemplateSpec, error) {
+	name := pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+	if name == "" {
+		return nil, nil
+	}
+	var rev appsv1.ControllerRevision
+	err := r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, &rev)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get controller revision %s of pod %s: %w", name, pod.Name, err)
+	}
+	// The revisions of a StatefulSet hold its pod template as a patch of
+	// the StatefulSet.
+	var data struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(rev.Data.Raw, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode controller revision %s of pod %s: %w", name, pod.Name, err)
+	}
+	return &data.Spec.Template, nil
+}
+
+// removeForeignPodFields removes the fields of a pod, or pod template, that
+// are in none of the owned templates; nil templates are skipped. Mounts of
+// the volume claims are kept.
+func removeForeignPodFields(
+	meta *metav1.ObjectMeta,
+	spec *corev1.PodSpec,
+	owned []*corev1.PodTemplateSpec,
+	claims []string,
+) {
+	annotations := make(map[string]bool)
+	labels := make(map[string]bool)
+	volumes := make(map[string]bool)
+	var containers, initContainers []corev1.Container
+	for _, t := range owned {
+		if t == nil {
+			continue
+		}
+		for k := range t.Annotations {
+			annotations[k] = true
+		}
+		for k := range t.Labels {
+			labels[k] = true
+		}
+		for i := range t.Spec.Volumes {
+			volumes[t.Spec.Volumes[i].Name] = true
+		}
+		containers = append(containers, t.Spec.Containers...)
+		initContainers = append(initContainers, t.Spec.InitContainers...)
+	}
+
+	for k := range meta.Annotations {
+		if !annotations[k] {
+			delete(meta.Annotations, k)
+		}
+	}
+	// This includes the labels the StatefulSet controller adds to pods,
+	// which the operator does not manage either.
+	for k := range meta.Labels {
+		if !labels[k] {
+			delete(meta.Labels, k)
+		}
+	}
+
+	var ownedVolumes []corev1.Volume
+	for i := range spec.Volumes {
+		// Pods also mount the PVCs of the volume claim templates and the
+		// service account token; only volumes of the template are
+		// dropped, which are the only ones with a source other than a
+		// PVC or a projected token.
+		v := spec.Volumes[i]
+		if volumes[v.Name] || v.PersistentVolumeClaim != nil || v.Projected != nil {
+			ownedVolumes = append(ownedVolumes, v)
+		}
+	}
+	spec.Volumes = ownedVolumes
+
+	mountable := make(map[string]bool, len(spec.Volumes)+len(claims))
+	for i := range spec.Volumes {
+		mountable[spec.Volumes[i].Name] = true
+	}
+	for _, c := range claims {
+		mountable[c] = true
+	}
+	spec.InitContainers = ownedContainers(spec.InitContainers, initContainers, mountable)
+	spec.Containers = ownedContainers(spec.Containers, containers, mountable)
+}
+
+// ownedContainers returns the containers with a name in owned, without the
+// mounts of volumes that are not mountable.
+func ownedContainers(
+	containers, owned []corev1.Container, mountable map[string]bool,
+) []corev1.Container {
+	names := make(map[string]bool, len(owned))
+	for i := range owned {
+		names[owned[i].Name] = true
+	}
+	var res []corev1.Container
+	for i := range containers {
+		c := containers[i]
+		if !names[c.Name] {
+			continue
+		}
+		var mounts []corev1.VolumeMount
+		for _, m := range c.VolumeMounts {
+			if mountable[m.Name] {
+				mounts = append(mounts, m)
+			}
+		}
+		c.VolumeMounts = mounts
+		res = append(res, c)
+	}
+	return res
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources //nolint:testpackage // needed to test private method
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func driftTestStatefulSet() *appsv1.StatefulSet {
+	var replicas int32 = 1
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "test",
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "redpanda"},
+					Annotations: map[string]string{"test": "test"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "redpanda",
+						Image: "vectorized/redpanda:v22.1.1",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "datadir", MountPath: "/var/lib/redpanda/data"},
+							{Name: "config", MountPath: "/etc/redpanda"},
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         "config",
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "datadir"},
+			}},
+		},
+	}
+}
+
+// inject mimics a webhook injecting a sidecar into a pod spec.
+func inject(meta *metav1.ObjectMeta, spec *corev1.PodSpec) {
+	meta.Annotations["sidecar.example.com/status"] = "injected"
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:         "proxy",
+		Image:        "example/proxy",
+		VolumeMounts: []corev1.VolumeMount{{Name: "proxy-certs", MountPath: "/certs"}},
+	})
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{Name: "proxy-certs", MountPath: "/proxy"})
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         "proxy-certs",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+}
+
+// withRPKStatus adds the rpk status sidecar, which the operator can remove
+// from the template, to a pod spec.
+func withRPKStatus(meta *metav1.ObjectMeta, spec *corev1.PodSpec) {
+	meta.Annotations["rpk-status"] = "enabled"
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:         "rpk-status",
+		Image:        "vectorized/redpanda:v22.1.1",
+		VolumeMounts: []corev1.VolumeMount{{Name: "rpk-status-secret", MountPath: "/etc/secret"}},
+	})
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         "rpk-status-secret",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "rpk-status"}},
+	})
+}
+
+func TestShouldUpdate_IgnoresForeignFields(t *testing.T) {
+	modified := driftTestStatefulSet()
+	current := driftTestStatefulSet()
+	require.NoError(t, patch.DefaultAnnotator.SetLastAppliedAnnotation(current))
+	inject(&current.Spec.Template.ObjectMeta, &current.Spec.Template.Spec)
+
+	ssres := StatefulSetResource{}
+	update, err := ssres.shouldUpdate(false, current, modified)
+	require.NoError(t, err)
+	require.False(t, update)
+
+	// a change of an owned field is still detected
+	modified.Spec.Template.Spec.Containers[0].Image = "vectorized/redpanda:v22.1.2"
+	update, err = ssres.shouldUpdate(false, current, modified)
+	require.NoError(t, err)
+	require.True(t, update)
+}
+
+func TestShouldUpdate_RemovedFields(t *testing.T) {
+	ssres := StatefulSetResource{}
+
+	// The sidecar, its volume and annotation were applied by the operator,
+	// and are removed from the desired template
+	current := driftTestStatefulSet()
+	withRPKStatus(&current.Spec.Template.ObjectMeta, &current.Spec.Template.Spec)
+	require.NoError(t, patch.DefaultAnnotator.SetLastAppliedAnnotation(current))
+	inject(&current.Spec.Template.ObjectMeta, &current.Spec.Template.Spec)
+	update, err := ssres.shouldUpdate(false, current, driftTestStatefulSet())
+	require.NoError(t, err)
+	require.True(t, update)
+
+	// Without a last applied configuration, nothing is ignored
+	current = driftTestStatefulSet()
+	inject(&current.Spec.Template.ObjectMeta, &current.Spec.Template.Spec)
+	raw, err := json.Marshal(current)
+	require.NoError(t, err)
+	desired := driftTestStatefulSet()
+	ignored, _, err := ignoreForeignTemplateFields(&desired.Spec.Template)(raw, raw)
+	require.NoError(t, err)
+	require.Equal(t, raw, ignored)
+}
+
+func TestRemoveForeignPodFields(t *testing.T) {
+	owned := driftTestStatefulSet().Spec.Template
+	pod := corev1.Pod{
+		ObjectMeta: *owned.ObjectMeta.DeepCopy(),
+		Spec:       *owned.Spec.DeepCopy(),
+	}
+	pod.Labels["controller-revision-hash"] = "test-123"
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: "datadir",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: "datadir-test-0",
+		}},
+	})
+	expected := pod.DeepCopy()
+	delete(expected.Labels, "controller-revision-hash")
+	inject(&pod.ObjectMeta, &pod.Spec)
+
+	removeForeignPodFields(&pod.ObjectMeta, &pod.Spec, []*corev1.PodTemplateSpec{&owned, nil}, nil)
+	require.Equal(t, expected, &pod)
+
+	// Fields of the revision the pod was created from are kept, so that
+	// their removal from the template is drift
+	revision := owned.DeepCopy()
+	withRPKStatus(&revision.ObjectMeta, &revision.Spec)
+	withRPKStatus(&pod.ObjectMeta, &pod.Spec)
+	expected = pod.DeepCopy()
+	inject(&pod.ObjectMeta, &pod.Spec)
+	removeForeignPodFields(&pod.ObjectMeta, &pod.Spec, []*corev1.PodTemplateSpec{&owned, revision}, nil)
+	require.Equal(t, expected, &pod)
+}
+
+func TestPodRevisionTemplate(t *testing.T) {
+	sts := driftTestStatefulSet()
+	withRPKStatus(&sts.Spec.Template.ObjectMeta, &sts.Spec.Template.Spec)
+	data, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": sts.Spec.Template,
+		},
+	})
+	require.NoError(t, err)
+	rev := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-5d9f6c7b8"},
+		Data:       runtime.RawExtension{Raw: data},
+		Revision:   1,
+	}
+	r := &StatefulSetResource{
+		Client: fake.NewClientBuilder().WithObjects(rev).Build(),
+		logger: ctrl.Log.WithName("test"),
+	}
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "test-0",
+		Labels:    map[string]string{appsv1.ControllerRevisionHashLabelKey: "test-5d9f6c7b8"},
+	}}
+	template, err := r.podRevisionTemplate(context.Background(), pod)
+	require.NoError(t, err)
+	require.Equal(t, &sts.Spec.Template, template)
+
+	// Pods of unknown revisions have no revision template
+	pod.Labels[appsv1.ControllerRevisionHashLabelKey] = "test-unknown"
+	template, err = r.podRevisionTemplate(context.Background(), pod)
+	require.NoError(t, err)
+	require.Nil(t, template)
+}
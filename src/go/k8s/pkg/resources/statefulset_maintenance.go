@@ -0,0 +1,55 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// inMaintenanceWindow returns whether a maintenance window of the cluster is
+// open, so that the disruptive operation can run. Otherwise, the operation is
+// recorded as pending in the cluster status, and the cluster controller
+// requeues the cluster for when the next window opens. Once a window is open,
+// the operation is removed from the pending ones.
+func (r *StatefulSetResource) inMaintenanceWindow(
+	ctx context.Context, operation string,
+) (bool, error) {
+	open, next, err := r.pandaCluster.MaintenanceWindowAt(time.Now())
+	if err != nil {
+		return false, fmt.Errorf("unable to compute the maintenance windows: %w", err)
+	}
+	if open {
+		return true, r.clearPendingMaintenance(ctx, operation)
+	}
+	if r.pandaCluster.Status.SetPendingMaintenance(operation, next) {
+		r.logger.Info("Deferring disruptive operation until the next maintenance window",
+			"operation", operation, "next window", next)
+		if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+			return false, fmt.Errorf("unable to record the pending %s in the cluster status: %w", operation, err)
+		}
+	}
+	return false, nil
+}
+
+// clearPendingMaintenance removes the operation from the ones pending in the
+// cluster status, once it runs or is no longer needed.
+func (r *StatefulSetResource) clearPendingMaintenance(
+	ctx context.Context, operation string,
+) error {
+	if !r.pandaCluster.Status.ClearPendingMaintenance(operation) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to clear the pending %s from the cluster status: %w", operation, err)
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/go-logr/logr"
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
@@ -64,6 +65,10 @@ const (
 // The strategy implemented here (to initialize the cluster at 1 replica, then upscaling to the desired number, without hacks on the seed server list),
 // should fix this problem, since the list of seeds servers will be the same in all nodes once the cluster is created.
 //
+// When the seed servers are designated in `spec.bootstrap`, the cluster starts from all of them instead of 1 replica. They form the cluster together,
+// since `empty_seed_starts_cluster` is disabled, and they are the seed servers of every node, so the list does not change when the cluster is scaled.
+// This handler waits for every seed server to join the cluster and for the controller to have a leader, and only then upscales to `spec.replicas`.
+//
 //nolint:nestif // for clarity
 func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 	if r.pandaCluster.Status.DecommissioningNode != nil {
@@ -81,6 +86,13 @@ func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 		return r.Status().Update(ctx, r.pandaCluster)
 	}
 
+	if *r.pandaCluster.Spec.Replicas >= r.pandaCluster.Status.CurrentReplicas {
+		// A downscale waiting for a maintenance window may have been reverted
+		if err := r.clearPendingMaintenance(ctx, redpandav1alpha1.MaintenanceOperationDecommission); err != nil {
+			return err
+		}
+	}
+
 	if *r.pandaCluster.Spec.Replicas == r.pandaCluster.Status.CurrentReplicas {
 		// No changes to replicas, we do nothing here
 		return nil
@@ -89,9 +101,9 @@ func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 	if *r.pandaCluster.Spec.Replicas > r.pandaCluster.Status.CurrentReplicas {
 		r.logger.Info("Upscaling cluster", "replicas", *r.pandaCluster.Spec.Replicas)
 
-		// We care about upscaling only when the cluster is moving off 1 replica, which happen e.g. at cluster startup
-		if r.pandaCluster.Status.CurrentReplicas == 1 {
-			r.logger.Info("Waiting for first node to form a cluster before upscaling")
+		// We care about upscaling only when the cluster is moving off its seed servers, which happen e.g. at cluster startup
+		if r.pandaCluster.Status.CurrentReplicas == r.pandaCluster.BootstrapReplicas() {
+			r.logger.Info("Waiting for the seed servers to form a cluster before upscaling", "seed_servers", r.pandaCluster.Status.CurrentReplicas)
 			formed, err := r.isClusterFormed(ctx)
 			if err != nil {
 				return err
@@ -110,12 +122,54 @@ func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 	}
 
 	// User required replicas is lower than current replicas (currentReplicas): start the decommissioning process
+	if bootstrap := r.pandaCluster.Spec.Bootstrap; bootstrap != nil && *r.pandaCluster.Spec.Replicas < bootstrap.SeedReplicas {
+		return &RequeueAfterError{
+			RequeueAfter: wait.Jitter(r.decommissionWaitInterval, decommissionWaitJitterFactor),
+			Msg:          fmt.Sprintf("Refusing to downscale to %d replicas, which decommissions some of the %d seed servers", *r.pandaCluster.Spec.Replicas, bootstrap.SeedReplicas),
+		}
+	}
+	if err := r.verifyDownscaleReplication(ctx); err != nil {
+		return err
+	}
+	if inWindow, err := r.inMaintenanceWindow(ctx, redpandav1alpha1.MaintenanceOperationDecommission); err != nil || !inWindow {
+		return err
+	}
 	targetOrdinal := r.pandaCluster.Status.CurrentReplicas - 1 // Always decommission last node
 	r.logger.Info("Start decommission of last broker node", "ordinal", targetOrdinal)
 	r.pandaCluster.Status.DecommissioningNode = &targetOrdinal
 	return r.Status().Update(ctx, r.pandaCluster)
 }
 
+// verifyDownscaleReplication refuses to start a downscale of the cluster below
+// the replication factor of its topics, unless it is forced with the force
+// downscale annotation. The webhook refuses such changes already, but it can be
+// disabled.
+func (r *StatefulSetResource) verifyDownscaleReplication(ctx context.Context) error {
+	replicas := *r.pandaCluster.Spec.Replicas
+	if r.pandaCluster.IsDownscaleForced(replicas) {
+		r.logger.Info("Downscale forced through annotation, skipping the check of the replication factor of the topics", "replicas", replicas)
+		return nil
+	}
+
+	var maxReplicationFactor int
+	if r.maxReplicationFactor != nil {
+		rf, err := r.maxReplicationFactor(ctx, r, r.pandaCluster)
+		if err != nil {
+			return fmt.Errorf("unable to check the replication factor of the topics before downscaling, annotate the cluster with %s=%d to downscale anyway: %w",
+				redpandav1alpha1.ForceDownscaleAnnotation, replicas, err)
+		}
+		maxReplicationFactor = rf
+	}
+	if minimum := r.pandaCluster.MinimumReplicas(maxReplicationFactor); int(replicas) < minimum {
+		return &RequeueAfterError{
+			RequeueAfter: wait.Jitter(r.decommissionWaitInterval, decommissionWaitJitterFactor),
+			Msg: fmt.Sprintf("Refusing to downscale to %d replicas, which loses data of topics replicated on %d brokers: annotate the cluster with %s=%d to accept the loss of data",
+				replicas, minimum, redpandav1alpha1.ForceDownscaleAnnotation, replicas),
+		}
+	}
+	return nil
+}
+
 // handleDecommission manages the case of decommissioning of the last node of a cluster.
 //
 // When this handler is called, the `status.decommissioningNode` is populated with the pod ordinal (== nodeID) of the
@@ -149,6 +203,8 @@ func (r *StatefulSetResource) handleDecommission(ctx context.Context) error {
 				return fmt.Errorf("error while trying to decommission node %d in cluster %s: %w", broker.NodeID, r.pandaCluster.Name, err)
 			}
 			r.logger.Info("Node marked for decommissioning in cluster", "node_id", broker.NodeID)
+			r.auditor.Record(r.pandaCluster, AuditReasonBrokerDecommissioned, strconv.Itoa(broker.NodeID),
+				"Broker %d decommissioned to scale down to %d replicas", broker.NodeID, targetReplicas)
 		}
 
 		// The draining phase must always be completed with all nodes running, to let single-replica partitions be transferred.
@@ -235,6 +291,8 @@ func (r *StatefulSetResource) handleRecommission(ctx context.Context) error {
 			return fmt.Errorf("error while trying to recommission node %d in cluster %s: %w", *r.pandaCluster.Status.DecommissioningNode, r.pandaCluster.Name, err)
 		}
 		r.logger.Info("Node marked for being recommissioned in cluster", "node_id", *r.pandaCluster.Status.DecommissioningNode)
+		r.auditor.Record(r.pandaCluster, AuditReasonBrokerRecommissioned, strconv.Itoa(int(*r.pandaCluster.Status.DecommissioningNode)),
+			"Broker %d recommissioned", *r.pandaCluster.Status.DecommissioningNode)
 
 		return &RequeueAfterError{
 			RequeueAfter: wait.Jitter(r.decommissionWaitInterval, decommissionWaitJitterFactor),
@@ -253,6 +311,9 @@ func (r *StatefulSetResource) getAdminAPIClient(
 	return r.adminAPIClientFactory(ctx, r, r.pandaCluster, r.serviceFQDN, r.adminTLSConfigProvider, ordinals...)
 }
 
+// isClusterFormed returns whether the first node formed a cluster or, when the
+// seed servers are designated, whether every seed server joined the cluster and
+// its controller has a leader.
 func (r *StatefulSetResource) isClusterFormed(
 	ctx context.Context,
 ) (bool, error) {
@@ -265,7 +326,29 @@ func (r *StatefulSetResource) isClusterFormed(
 		// Eat the error and return that the cluster is not formed
 		return false, nil
 	}
-	return len(brokers) > 0, nil
+	if r.pandaCluster.Spec.Bootstrap == nil {
+		return len(brokers) > 0, nil
+	}
+
+	joined := make(map[int]bool, len(brokers))
+	for i := range brokers {
+		joined[brokers[i].NodeID] = true
+	}
+	for ordinal := 0; ordinal < int(r.pandaCluster.BootstrapReplicas()); ordinal++ {
+		if !joined[ordinal] {
+			r.logger.Info("Seed server has not joined the cluster yet", "node_id", ordinal)
+			return false, nil
+		}
+	}
+	health, err := rootNodeAdminAPI.GetHealthOverview(ctx)
+	if err != nil {
+		return false, nil
+	}
+	if health.ControllerID < 0 {
+		r.logger.Info("Controller of the cluster has no leader yet")
+		return false, nil
+	}
+	return true, nil
 }
 
 // disableMaintenanceModeOnDecommissionedNodes can be used to put a cluster in a consistent state, disabling maintenance mode on
@@ -315,6 +398,8 @@ func (r *StatefulSetResource) disableMaintenanceModeOnDecommissionedNodes(
 		return fmt.Errorf("could not disable maintenance mode on decommissioning node %d: %w", ordinal, err)
 	}
 	r.logger.Info("Maintenance mode disabled for the decommissioned node", "node_id", ordinal)
+	r.auditor.Record(r.pandaCluster, AuditReasonMaintenanceModeDisabled, strconv.Itoa(int(ordinal)),
+		"Maintenance mode disabled on decommissioned broker %d", ordinal)
 	return nil
 }
 
@@ -111,6 +111,7 @@ func TestEnsure(t *testing.T) {
 				},
 				func(ctx context.Context) (string, error) { return hash, nil },
 				adminutils.NewInternalAdminAPI,
+				nil,
 				time.Second,
 				ctrl.Log.WithName("test"))
 
@@ -18,15 +18,23 @@ import (
 	"net/url"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/featuregates"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -35,6 +43,9 @@ const (
 	// requeue resource reconciliation.
 	RequeueDuration = time.Second * 10
 	adminAPITimeout = time.Millisecond * 100
+	// drainBeforeRestartTimeout bounds the wait for a broker to drain within
+	// a reconciliation
+	drainBeforeRestartTimeout = time.Second * 5
 )
 
 var errRedpandaNotReady = errors.New("redpanda not ready")
@@ -51,10 +62,20 @@ var errRedpandaNotReady = errors.New("redpanda not ready")
 // differentiate from the current stored statefulset definition 2) if true,
 // set the Restarting status to true and remove statefulset with the orphan Pods
 // 3) perform rolling update like removing Pods accordingly to theirs ordinal
-// number 4) requeue until the pod is in ready state 5) prior to a pod update
+// number, possibly restarting multiple Pods of the same rack at once when the
+// update strategy allows it 4) requeue until the pod is in ready state 5) prior to a pod update
 // verify the previously updated pod and requeue as necessary. Currently, the
 // verification checks the pod has started listening in its http Admin API port and may be
-// extended.
+// extended. A pod restarted alone is only deleted once its broker is drained,
+// see drainBeforeRestart. Pods are evicted rather than deleted, so that their
+// PodDisruptionBudget is respected, see restartPod.
+//
+// If the cluster has maintenance windows, the update only runs while one is
+// open: outside of them, it is recorded as pending in the status and resumes
+// in the next window.
+//
+// With canary upgrades, an image change restarts a single pod first, which is
+// soaked before the other pods are restarted, see soakCanary.
 func (r *StatefulSetResource) runUpdate(
 	ctx context.Context, current, modified *appsv1.StatefulSet,
 ) error {
@@ -72,12 +93,19 @@ func (r *StatefulSetResource) runUpdate(
 	}
 
 	if !update {
-		return nil
+		return r.clearPendingMaintenance(ctx, redpandav1alpha1.MaintenanceOperationRollingUpdate)
+	}
+	inWindow, err := r.inMaintenanceWindow(ctx, redpandav1alpha1.MaintenanceOperationRollingUpdate)
+	if err != nil || !inWindow {
+		return err
 	}
 
 	if err = r.updateRestartingStatus(ctx, true); err != nil {
 		return fmt.Errorf("unable to turn on restarting status in cluster custom resource: %w", err)
 	}
+	if err = r.startCanary(ctx, current, modified); err != nil {
+		return err
+	}
 	if err = r.updateStatefulSet(ctx, current, modified); err != nil {
 		return err
 	}
@@ -110,6 +138,10 @@ func (r *StatefulSetResource) rollingUpdate(
 		return podList.Items[i].Name < podList.Items[j].Name
 	})
 
+	if err = r.soakCanary(ctx); err != nil {
+		return err
+	}
+
 	var artificialPod corev1.Pod
 	artificialPod.Annotations = template.Annotations
 	artificialPod.Spec = template.Spec
@@ -127,22 +159,26 @@ func (r *StatefulSetResource) rollingUpdate(
 		ignoreExistingVolumes(volumes),
 	}
 
+	var outdated []corev1.Pod
 	for i := range podList.Items {
 		pod := podList.Items[i]
 
-		patchResult, err := patch.DefaultPatchMaker.Calculate(&pod, &artificialPod, opts...)
+		revision, err := r.podRevisionTemplate(ctx, &pod)
+		if err != nil {
+			return err
+		}
+		podOpts := append([]patch.CalculateOption{ignoreForeignPodFields(template, revision)}, opts...)
+		patchResult, err := patch.DefaultPatchMaker.Calculate(&pod, &artificialPod, podOpts...)
 		if err != nil {
 			return err
 		}
 
 		if !patchResult.IsEmpty() {
-			r.logger.Info("Changes in Pod definition other than activeDeadlineSeconds, configurator and Redpanda container name. Deleting pod",
+			r.logger.Info("Changes in Pod definition other than activeDeadlineSeconds, configurator and Redpanda container name",
 				"pod-name", pod.Name,
 				"patch", patchResult.Patch)
-			if err = r.Delete(ctx, &pod); err != nil {
-				return fmt.Errorf("unable to remove Redpanda pod: %w", err)
-			}
-			return &RequeueAfterError{RequeueAfter: RequeueDuration, Msg: "wait for pod restart"}
+			outdated = append(outdated, pod)
+			continue
 		}
 
 		if !utils.IsPodReady(&pod) {
@@ -167,15 +203,212 @@ func (r *StatefulSetResource) rollingUpdate(
 		}
 	}
 
+	if len(outdated) == 0 {
+		return nil
+	}
+
+	batch := r.podsToRestart(ctx, outdated)
+	if len(batch) == 1 {
+		if err = r.drainBeforeRestart(ctx, &batch[0]); err != nil {
+			return err
+		}
+	}
+	for i := range batch {
+		pod := batch[i]
+		r.logger.Info("Restarting pod", "pod-name", pod.Name)
+		if err = r.restartPod(ctx, &pod); err != nil {
+			return err
+		}
+		r.auditor.Record(r.pandaCluster, AuditReasonPodRestarted, pod.Name,
+			"Pod %s deleted to be restarted with the updated spec", pod.Name)
+		if r.isCanaryPending() {
+			if err = r.canaryRestarted(ctx, &pod); err != nil {
+				return err
+			}
+		}
+	}
+	return &RequeueAfterError{RequeueAfter: RequeueDuration, Msg: "wait for pod restart"}
+}
+
+// PodEvictor evicts a pod through the Eviction API, which the API server
+// refuses with a TooManyRequests error while the eviction would violate the
+// PodDisruptionBudget of the pod
+type PodEvictor func(ctx context.Context, pod *corev1.Pod) error
+
+// NewPodEvictor returns a PodEvictor creating the evictions with clientset
+func NewPodEvictor(clientset kubernetes.Interface) PodEvictor {
+	return func(ctx context.Context, pod *corev1.Pod) error {
+		return clientset.CoreV1().Pods(pod.Namespace).Evict(ctx, &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		})
+	}
+}
+
+// restartPod evicts the pod so that the PodDisruptionBudget of the cluster is
+// respected, or deletes it if no PodEvictor is set. A blocked eviction is
+// retried in a later reconciliation.
+func (r *StatefulSetResource) restartPod(
+	ctx context.Context, pod *corev1.Pod,
+) error {
+	if r.podEvictor == nil {
+		if err := r.Delete(ctx, pod); err != nil {
+			return fmt.Errorf("unable to remove Redpanda pod: %w", err)
+		}
+		return nil
+	}
+	err := r.podEvictor(ctx, pod)
+	if apierrors.IsTooManyRequests(err) {
+		return &RequeueAfterError{
+			RequeueAfter: RequeueDuration,
+			Msg:          fmt.Sprintf("eviction of pod %s is blocked by its PodDisruptionBudget", pod.Name),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to evict Redpanda pod: %w", err)
+	}
 	return nil
 }
 
+// drainBeforeRestart puts the broker of a pod in maintenance mode and waits
+// for it to transfer the leadership of its partitions, so that its clients
+// move to other brokers before the pod is deleted. The preStop hook of the pod
+// drains it too, but only within the termination grace period of the pod.
+//
+// Draining is waited for drainBeforeRestartTimeout at most, then the
+// reconciliation is requeued until the broker is drained. Brokers that cannot
+// drain, e.g. because they are not ready or another broker is in maintenance
+// mode, are restarted right away. Parallel restarts rely on the preStop hook
+// only, as a single broker can be in maintenance mode at a time.
+func (r *StatefulSetResource) drainBeforeRestart(
+	ctx context.Context, pod *corev1.Pod,
+) error {
+	if !featuregates.MaintenanceMode(r.pandaCluster.Status.Version) ||
+		!r.pandaCluster.IsUsingMaintenanceModeHooks() ||
+		r.pandaCluster.GetCurrentReplicas() <= 1 ||
+		!utils.IsPodReady(pod) {
+		return nil
+	}
+	nodeID, err := strconv.Atoi(strings.TrimPrefix(pod.Name, r.pandaCluster.Name+"-"))
+	if err != nil {
+		return fmt.Errorf("unable to get the node id of pod %s: %w", pod.Name, err)
+	}
+
+	adminAPI, err := r.getAdminAPIClient(ctx)
+	if err != nil {
+		return err
+	}
+	if err = adminAPI.EnableMaintenanceMode(ctx, nodeID); err != nil {
+		r.logger.Error(err, "Unable to enable maintenance mode before restarting the pod, restarting it anyway", "pod-name", pod.Name, "node_id", nodeID)
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, drainBeforeRestartTimeout)
+	defer cancel()
+	status, err := adminAPI.WaitForDrain(waitCtx, nodeID)
+	if errors.Is(err, admin.ErrMaintenanceModeUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return &RequeueAfterError{
+			RequeueAfter: RequeueDuration,
+			Msg:          fmt.Sprintf("wait for broker %d to drain before restarting pod %s, %d partitions pending: %v", nodeID, pod.Name, status.PendingPartitions(), err),
+		}
+	}
+	if status.Errors {
+		r.logger.Info("Broker drained with errors, see its logs", "pod-name", pod.Name, "node_id", nodeID, "failed", status.Failed)
+	}
+	return nil
+}
+
+// podsToRestart returns the outdated pods that can be restarted together.
+//
+// A single pod is returned if it is the canary of an image upgrade, or unless
+// the cluster allows more than one unavailable pod during updates, which is
+// capped by the PodDisruptionBudget. In that case, the admin API must report
+// a stable cluster with no under-replicated partitions, and only pods
+// scheduled in the same rack as the first outdated pod are returned, up to
+// the maximum number of unavailable pods.
+func (r *StatefulSetResource) podsToRestart(
+	ctx context.Context, outdated []corev1.Pod,
+) []corev1.Pod {
+	maxUnavailable := int(r.pandaCluster.MaxUnavailableDuringUpdate())
+	if maxUnavailable <= 1 || len(outdated) == 1 || r.isCanaryPending() {
+		return outdated[:1]
+	}
+
+	stable, err := r.isStableForParallelUpdate(ctx)
+	if err != nil {
+		r.logger.Error(err, "Unable to verify cluster health, restarting a single pod")
+		return outdated[:1]
+	}
+	if !stable.Stable {
+		r.logger.Info("Cluster is not stable, restarting a single pod", "reasons", stable.Reasons)
+		return outdated[:1]
+	}
+
+	rackLabel := r.pandaCluster.UpdateRackLabel()
+	racks := make(map[string]string, len(outdated))
+	for i := range outdated {
+		if outdated[i].Spec.NodeName == "" {
+			continue
+		}
+		var node corev1.Node
+		if err := r.Get(ctx, types.NamespacedName{Name: outdated[i].Spec.NodeName}, &node); err != nil {
+			r.logger.Error(err, "Unable to retrieve node of pod", "pod-name", outdated[i].Name)
+			continue
+		}
+		racks[outdated[i].Name] = node.Labels[rackLabel]
+	}
+	return selectPodsInSameRack(outdated, racks, maxUnavailable)
+}
+
+// isStableForParallelUpdate checks that all nodes are up, all partitions have
+// a leader and are fully replicated, and that no node is in maintenance mode
+// or being decommissioned.
+func (r *StatefulSetResource) isStableForParallelUpdate(
+	ctx context.Context,
+) (admin.StabilityVerdict, error) {
+	adminAPI, err := r.getAdminAPIClient(ctx)
+	if err != nil {
+		return admin.StabilityVerdict{}, err
+	}
+	return adminAPI.CheckClusterStability(ctx, admin.StabilityOptions{
+		// Partition movements only slow down the restart of the pods
+		AllowReconfigurations: true,
+	})
+}
+
+// selectPodsInSameRack returns up to max pods sharing the rack of the first
+// pod. Pods without a known rack are never grouped with other pods.
+func selectPodsInSameRack(
+	pods []corev1.Pod, racks map[string]string, max int,
+) []corev1.Pod {
+	rack := racks[pods[0].Name]
+	if rack == "" {
+		return pods[:1]
+	}
+	var batch []corev1.Pod
+	for i := range pods {
+		if len(batch) == max {
+			break
+		}
+		if racks[pods[i].Name] == rack {
+			batch = append(batch, pods[i])
+		}
+	}
+	return batch
+}
+
 func (r *StatefulSetResource) updateStatefulSet(
 	ctx context.Context,
 	current *appsv1.StatefulSet,
 	modified *appsv1.StatefulSet,
 ) error {
-	_, err := Update(ctx, current, modified, r.Client, r.logger)
+	r.logger.Info(fmt.Sprintf("Resource %s (%s) changed, applying", modified.GetName(), statefulSetKind()))
+	err := r.applyStatefulSet(ctx, current, modified)
 	if err != nil && strings.Contains(err.Error(), "spec: Forbidden: updates to statefulset spec for fields other than") {
 		// REF: https://github.com/kubernetes/kubernetes/issues/69041#issuecomment-723757166
 		// https://www.giffgaff.io/tech/resizing-statefulset-persistent-volumes-with-zero-downtime
@@ -187,6 +420,8 @@ func (r *StatefulSetResource) updateStatefulSet(
 		if err != nil {
 			return fmt.Errorf("unable to delete statefulset using orphan propagation policy: %w", err)
 		}
+		r.auditor.Record(r.pandaCluster, AuditReasonStatefulSetRecreated, current.Name,
+			"StatefulSet %s deleted, orphaning its pods, to be recreated with updated immutable fields", current.Name)
 		return &RequeueAfterError{RequeueAfter: RequeueDuration, Msg: "wait for sts to be deleted"}
 	}
 	if err != nil {
@@ -205,6 +440,7 @@ func (r *StatefulSetResource) shouldUpdate(
 		patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
 		utils.IgnoreAnnotation(patch.LastAppliedConfig),
 		utils.IgnoreAnnotation(CentralizedConfigurationHashAnnotationKey),
+		ignoreForeignTemplateFields(&modified.Spec.Template),
 	}
 	patchResult, err := patch.DefaultPatchMaker.Calculate(current, modified, opts...)
 	if err != nil {
@@ -10,12 +10,23 @@
 package resources //nolint:testpackage // needed to test private method
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/go-logr/logr"
+	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestShouldUpdate_AnnotationChange(t *testing.T) {
@@ -65,3 +76,155 @@ func TestShouldUpdate_AnnotationChange(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, update)
 }
+
+func TestSelectPodsInSameRack(t *testing.T) {
+	pod := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	names := func(pods []corev1.Pod) []string {
+		var res []string
+		for i := range pods {
+			res = append(res, pods[i].Name)
+		}
+		return res
+	}
+	pods := []corev1.Pod{pod("rp-0"), pod("rp-1"), pod("rp-2"), pod("rp-3"), pod("rp-4")}
+
+	tests := []struct {
+		name     string
+		racks    map[string]string
+		max      int
+		expected []string
+	}{
+		{
+			name:     "no rack information",
+			racks:    map[string]string{},
+			max:      3,
+			expected: []string{"rp-0"},
+		},
+		{
+			name:     "first pod without rack",
+			racks:    map[string]string{"rp-1": "a", "rp-2": "a"},
+			max:      3,
+			expected: []string{"rp-0"},
+		},
+		{
+			name:     "pods of the same rack",
+			racks:    map[string]string{"rp-0": "a", "rp-1": "b", "rp-2": "a", "rp-3": "b", "rp-4": "a"},
+			max:      3,
+			expected: []string{"rp-0", "rp-2", "rp-4"},
+		},
+		{
+			name:     "limited by max unavailable",
+			racks:    map[string]string{"rp-0": "a", "rp-1": "a", "rp-2": "a", "rp-3": "a", "rp-4": "a"},
+			max:      2,
+			expected: []string{"rp-0", "rp-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, names(selectPodsInSameRack(pods, tt.racks, tt.max)))
+		})
+	}
+}
+
+func TestRestartPod(t *testing.T) {
+	pod := func() *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rp-0", Namespace: "default"}}
+	}
+	podExists := func(c k8sclient.Client) bool {
+		err := c.Get(context.Background(), k8sclient.ObjectKeyFromObject(pod()), &corev1.Pod{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	tests := []struct {
+		name       string
+		evictor    PodEvictor
+		expRequeue bool
+		expErr     bool
+		expDeleted bool
+	}{
+		{
+			name:       "deleted without evictor",
+			expDeleted: true,
+		},
+		{
+			name:    "evicted",
+			evictor: func(context.Context, *corev1.Pod) error { return nil },
+		},
+		{
+			name: "eviction blocked by the PodDisruptionBudget",
+			evictor: func(context.Context, *corev1.Pod) error {
+				return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+			},
+			expRequeue: true,
+		},
+		{
+			name:    "eviction failure",
+			evictor: func(context.Context, *corev1.Pod) error { return errors.New("boom") },
+			expErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(pod()).Build()
+			r := &StatefulSetResource{Client: c, logger: logr.Discard()}
+			r.WithPodEvictor(tt.evictor)
+
+			err := r.restartPod(context.Background(), pod())
+			var requeue *RequeueAfterError
+			switch {
+			case tt.expRequeue:
+				require.True(t, errors.As(err, &requeue), "expected a requeue, got %v", err)
+			case tt.expErr:
+				require.Error(t, err)
+				require.False(t, errors.As(err, &requeue))
+			default:
+				require.NoError(t, err)
+			}
+			require.Equal(t, !tt.expDeleted, podExists(c))
+		})
+	}
+}
+
+func TestNewPodEvictor(t *testing.T) {
+	clientset := k8sfake.NewSimpleClientset()
+	var evicted *policyv1beta1.Eviction
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		evicted = action.(k8stesting.CreateAction).GetObject().(*policyv1beta1.Eviction)
+		return true, nil, nil
+	})
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rp-0", Namespace: "redpanda"}}
+	require.NoError(t, NewPodEvictor(clientset)(context.Background(), pod))
+	require.NotNil(t, evicted)
+	require.Equal(t, "rp-0", evicted.Name)
+	require.Equal(t, "redpanda", evicted.Namespace)
+}
+
+func TestAdvertisedPortBaseEnvVars(t *testing.T) {
+	cluster := func(external redpandav1alpha1.ExternalConnectivityConfig) *redpandav1alpha1.Cluster {
+		c := &redpandav1alpha1.Cluster{}
+		c.Spec.Configuration.KafkaAPI = []redpandav1alpha1.KafkaAPI{
+			{Port: 9092},
+			{External: external},
+		}
+		return c
+	}
+
+	r := &StatefulSetResource{pandaCluster: cluster(redpandav1alpha1.ExternalConnectivityConfig{})}
+	require.Empty(t, r.advertisedPortBaseEnvVars())
+
+	r.pandaCluster = cluster(redpandav1alpha1.ExternalConnectivityConfig{Enabled: true})
+	require.Empty(t, r.advertisedPortBaseEnvVars(), "pod templates do not change without a port base")
+
+	r.pandaCluster = cluster(redpandav1alpha1.ExternalConnectivityConfig{Enabled: true, AdvertisedPortBase: 31000})
+	require.Equal(t, []corev1.EnvVar{{Name: "EXTERNAL_CONNECTIVITY_ADVERTISED_PORT_BASE", Value: "31000"}}, r.advertisedPortBaseEnvVars())
+}
@@ -101,8 +101,10 @@ func (r *SuperUsersResource) obj() (k8sclient.Object, error) {
 
 	obj := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Key().Name,
-			Namespace: r.Key().Namespace,
+			Name:        r.Key().Name,
+			Namespace:   r.Key().Namespace,
+			Labels:      withCommonLabels(r.object, nil),
+			Annotations: withCommonAnnotations(r.object, nil),
 		},
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Secret",
@@ -30,6 +30,12 @@ type AdminTLSConfigProvider interface {
 	GetTLSConfig(ctx context.Context, k8sClient client.Reader) (*tls.Config, error)
 }
 
+// KafkaAPICAProvider returns the CA certificate of the Kafka API, which is
+// nil if the Kafka API has no TLS or its certificate has no CA
+type KafkaAPICAProvider interface {
+	KafkaAPICA(ctx context.Context, k8sClient client.Reader) ([]byte, error)
+}
+
 // TLSMountPoint defines paths to be mounted
 // We need 2 secrets and 2 mount points for each API endpoint that supports TLS and mTLS:
 // 1. The Node certs used by the API endpoint to sign requests
@@ -0,0 +1,177 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronHorizon bounds the search of the next activation of a schedule, so that
+// schedules that never activate, e.g. on February 30th, do not loop forever.
+const cronHorizon = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a schedule in the standard five fields cron format:
+// "minute hour day-of-month month day-of-week". Each field is either "*", a
+// value, a range "a-b", optionally with a step "*/n" or "a-b/n", or a comma
+// separated list of these. Days of the week go from 0 (Sunday) to 6, 7 being
+// Sunday too. As in cron, when both the day of the month and the day of the
+// week are restricted, a day matching either of them activates the schedule.
+type CronSchedule struct {
+	minute, hour, dom, month, dow []bool
+	domRestricted, dowRestricted  bool
+}
+
+// ParseCronSchedule parses a schedule in the cron format.
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	var s CronSchedule
+	var err error
+	for _, f := range []struct {
+		into     *[]bool
+		name     string
+		min, max int
+	}{
+		{&s.minute, "minute", 0, 59},
+		{&s.hour, "hour", 0, 23},
+		{&s.dom, "day of month", 1, 31},
+		{&s.month, "month", 1, 12},
+		{&s.dow, "day of week", 0, 7},
+	} {
+		if *f.into, err = parseCronField(fields[0], f.min, f.max); err != nil {
+			return nil, fmt.Errorf("invalid cron schedule %q: invalid %s: %w", spec, f.name, err)
+		}
+		fields = fields[1:]
+	}
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+	s.domRestricted = !allSet(s.dom[1:])
+	s.dowRestricted = !allSet(s.dow[:7])
+	return &s, nil
+}
+
+func parseCronField(field string, min, max int) ([]bool, error) {
+	set := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			rng = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			v, err := strconv.Atoi(rng)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", rng)
+			}
+			lo, hi = v, v
+			if step > 1 {
+				// "a/n" is a shorthand for "a-max/n".
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q is out of the range %d-%d", rng, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func allSet(set []bool) bool {
+	for _, v := range set {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
+
+// Next returns the first activation of the schedule strictly after t, in the
+// location of t. It returns the zero time if the schedule does not activate
+// in the next five years. Activations in the hour skipped when the clocks go
+// forward for daylight saving time happen right after it.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	// Search the wall clock time, which has no daylight saving time
+	// transitions.
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	limit := wall.Add(cronHorizon)
+	for {
+		if wall = s.nextWall(wall, limit); wall.IsZero() {
+			return wall
+		}
+		next := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+		if next.After(t) {
+			return next
+		}
+	}
+}
+
+func (s *CronSchedule) nextWall(t, limit time.Time) time.Time {
+	t = t.Add(time.Minute)
+	for t.Before(limit) {
+		switch {
+		case !s.month[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = t.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		case !s.hour[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[t.Weekday()]
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// ErrCronNeverActivates is returned for schedules that never activate, e.g.
+// on February 30th.
+var ErrCronNeverActivates = errors.New("the schedule never activates")
+
+// Validate returns an error if the schedule never activates.
+func (s *CronSchedule) Validate() error {
+	if s.Next(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return ErrCronNeverActivates
+	}
+	return nil
+}
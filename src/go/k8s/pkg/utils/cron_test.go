@@ -0,0 +1,91 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Monday
+	from := time.Date(2022, time.August, 1, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2022, time.August, 1, 10, 31, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2022, time.August, 2, 2, 0, 0, 0, time.UTC)},
+		{"45 10 * * *", time.Date(2022, time.August, 1, 10, 45, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2022, time.August, 1, 10, 40, 0, 0, time.UTC)},
+		{"0 22-23 * * 1-5", time.Date(2022, time.August, 1, 22, 0, 0, 0, time.UTC)},
+		{"0 1 * * 6,7", time.Date(2022, time.August, 6, 1, 0, 0, 0, time.UTC)},
+		{"0 1 * * 0", time.Date(2022, time.August, 7, 1, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, time.September, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Either the day of the month or the day of the week
+		{"0 0 15 * 3", time.Date(2022, time.August, 3, 0, 0, 0, 0, time.UTC)},
+		{"30 3 1/10 * *", time.Date(2022, time.August, 11, 3, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			s, err := utils.ParseCronSchedule(tt.schedule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, s.Next(from))
+		})
+	}
+}
+
+func TestCronScheduleNextTimeZone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	s, err := utils.ParseCronSchedule("0 2 * * *")
+	require.NoError(t, err)
+	// 23:00 UTC on March 26th is 1:00 in Paris, before the switch to
+	// summer time at 2:00, which does not exist that day.
+	next := s.Next(time.Date(2022, time.March, 26, 23, 0, 0, 0, time.UTC).In(paris))
+	assert.Equal(t, time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+	} {
+		t.Run(schedule, func(t *testing.T) {
+			_, err := utils.ParseCronSchedule(schedule)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCronScheduleValidate(t *testing.T) {
+	s, err := utils.ParseCronSchedule("0 0 31 4 *")
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.Validate(), utils.ErrCronNeverActivates)
+
+	s, err = utils.ParseCronSchedule("0 0 31 * *")
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate())
+}
@@ -12,6 +12,8 @@ version=$1
 rev=${2:-$(git rev-parse --short HEAD)}
 # auth0 clientId for the vectorized cloud, optional
 clientId=$3
+# base64 ed25519 public key verifying self-update release manifests, optional
+releaseKey=$4
 img_tag=${version:-latest}
 
 out_dir="$(go env GOOS)-$(go env GOARCH)"
@@ -21,7 +23,8 @@ mkdir -p "${out_dir}"
 ver_pkg='github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/version'
 cont_pkg='github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/container/common'
 auth0_pkg='github.com/redpanda-data/redpanda/src/go/rpk/pkg/vcloud'
+update_pkg='github.com/redpanda-data/redpanda/src/go/rpk/pkg/selfupdate'
 
 go build \
-  -ldflags "-X ${ver_pkg}.version=${version} -X ${ver_pkg}.rev=${rev} -X ${cont_pkg}.tag=${img_tag} -X ${auth0_pkg}.clientId=${clientId}" \
+  -ldflags "-X ${ver_pkg}.version=${version} -X ${ver_pkg}.rev=${rev} -X ${cont_pkg}.tag=${img_tag} -X ${auth0_pkg}.clientId=${clientId} -X ${update_pkg}.publicKey=${releaseKey}" \
   -o "${out_dir}" ./...
@@ -30,6 +30,7 @@ import (
 	"github.com/sethgrid/pester"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"golang.org/x/sync/singleflight"
 )
 
 // ErrNoAdminAPILeader happen when there's no leader for the Admin API.
@@ -47,21 +48,35 @@ type BasicCredentials struct {
 }
 
 // GenericErrorBody is the JSON decodable body that is produced by generic error
-// handling in the admin server when a seastar http exception is thrown.
+// handling in the admin server when a seastar http exception is thrown. The
+// AdminError wrapped by HTTPResponseError exposes it too.
 type GenericErrorBody struct {
 	Message string `json:"message"`
 	Code    int    `json:"code"`
 }
 
 // AdminAPI is a client to interact with Redpanda's admin server.
+//
+// An AdminAPI is safe for concurrent use by multiple goroutines, once it is
+// configured: the Set methods of the client (SetRequestSigner,
+// SetBackoffPolicy, SetRetryHook) and Use must be called before it is used.
 type AdminAPI struct {
-	urls                []string
+	urls                []string // not modified after the client is created
 	brokerIDToUrlsMutex sync.Mutex
 	brokerIDToUrls      map[int]string
+	brokerMapping       singleflight.Group // dedups brokerIDToUrls refreshes
+	leaderEpochsMutex   sync.Mutex
+	leaderEpochs        map[partitionKey]int64 // highest leader epoch observed per partition
 	retryClient         *pester.Client
+	retryTransport      *retryTransport
 	oneshotClient       *http.Client
 	basicCredentials    BasicCredentials
 	tlsConfig           *tls.Config
+	hostTLS             map[string]*tls.Config // TLS overrides per host:port
+	knownURLs           map[string]bool        // URLs redirects are followed to
+	signer              RequestSigner
+	middlewares         []Middleware // see Use
+	apiVersions         *apiVersions // shared with the single host clients
 }
 
 func getBasicCredentials(cfg *config.Config) BasicCredentials {
@@ -81,7 +96,23 @@ func NewClient(fs afero.Fs, cfg *config.Config) (*AdminAPI, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to create admin api tls config: %v", err)
 	}
-	return NewAdminAPI(addrs, getBasicCredentials(cfg), tc)
+	signer, err := NewRequestSigner(a.Signing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create admin api request signer: %v", err)
+	}
+	hostTLS, err := hostTLSFromConfig(fs, a.HostTLS)
+	if err != nil {
+		return nil, err
+	}
+	cl, err := NewAdminAPI(addrs, getBasicCredentials(cfg), tc)
+	if err != nil {
+		return nil, err
+	}
+	cl.SetRequestSigner(signer)
+	if err := cl.SetHostTLS(hostTLS); err != nil {
+		return nil, err
+	}
+	return cl, nil
 }
 
 // NewHostClient returns an AdminAPI that talks to the given host, which is
@@ -111,7 +142,46 @@ func NewHostClient(
 		addrs = []string{host} // trust input is hostname (validate below)
 	}
 
-	return NewAdminAPI(addrs, getBasicCredentials(cfg), tc)
+	signer, err := NewRequestSigner(a.Signing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create admin api request signer: %v", err)
+	}
+	hostTLS, err := hostTLSFromConfig(fs, a.HostTLS)
+	if err != nil {
+		return nil, err
+	}
+	cl, err := NewAdminAPI(addrs, getBasicCredentials(cfg), tc)
+	if err != nil {
+		return nil, err
+	}
+	cl.SetRequestSigner(signer)
+	if err := cl.SetHostTLS(hostTLS); err != nil {
+		return nil, err
+	}
+	return cl, nil
+}
+
+// hostTLSFromConfig returns the TLS overrides of the rpk.admin_api.host_tls
+// section of the config, reading their CA files.
+func hostTLSFromConfig(
+	fs afero.Fs, hosts map[string]*config.HostTLS,
+) (map[string]HostTLS, error) {
+	overrides := make(map[string]HostTLS, len(hosts))
+	for host, h := range hosts {
+		if h == nil {
+			continue
+		}
+		o := HostTLS{ServerName: h.ServerName, InsecureSkipVerify: h.InsecureSkipVerify}
+		if h.CAFile != "" {
+			pem, err := afero.ReadFile(fs, h.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the CA file of admin host %q: %v", host, err)
+			}
+			o.CAPEM = pem
+		}
+		overrides[host] = o
+	}
+	return overrides, nil
 }
 
 func NewAdminAPI(
@@ -123,9 +193,6 @@ func NewAdminAPI(
 func newAdminAPI(
 	urls []string, creds BasicCredentials, tlsConfig *tls.Config,
 ) (*AdminAPI, error) {
-	// General purpose backoff, includes 503s and other errors
-	const retryBackoffMs = 1500
-
 	if len(urls) == 0 {
 		return nil, errors.New("at least one url is required for the admin api")
 	}
@@ -135,38 +202,35 @@ func newAdminAPI(
 	// Use a retrying HTTP client to handle that gracefully.
 	client := pester.New()
 
-	// Backoff is the default redpanda raft election timeout: this enables us
-	// to cleanly retry on 503s due to leadership changes in progress.
-	client.Backoff = func(retry int) time.Duration {
-		maxJitter := 100
-		delayMs := retryBackoffMs + rng(maxJitter)
-		return time.Duration(delayMs) * time.Millisecond
-	}
-
-	// This happens to be the same as the pester default, but make it explicit:
-	// a raft election on a 3 node group might take 3x longer if it has
-	// to repeat until the lowest-priority voter wins.
-	client.MaxRetries = 3
+	// The backoff is applied by retryTransport, which unlike pester stops
+	// waiting when the request context is canceled; see BackoffPolicy.
+	client.Backoff = func(int) time.Duration { return 0 }
+	client.MaxRetries = DefaultBackoffPolicy.MaxAttempts
 
-	client.LogHook = func(e pester.ErrEntry) {
-		// Only log from here when retrying: a final error propagates to caller
-		if e.Err != nil && e.Retry <= client.MaxRetries {
-			log.Infof("Retrying %s for error: %s", e.Verb, e.Err.Error())
-		}
-	}
+	// Retries are logged by the retry hook of the transport, which knows the
+	// backoff; a final error propagates to the caller.
+	client.LogHook = func(pester.ErrEntry) {}
 
 	client.Timeout = 10 * time.Second
+	transport := &retryTransport{
+		base:   http.DefaultTransport,
+		policy: DefaultBackoffPolicy,
+		hook:   logRetry,
+	}
+	client.Transport = transport
 
 	a := &AdminAPI{
 		urls:             make([]string, len(urls)),
 		retryClient:      client,
+		retryTransport:   transport,
 		oneshotClient:    &http.Client{Timeout: 10 * time.Second},
 		basicCredentials: creds,
 		tlsConfig:        tlsConfig,
 		brokerIDToUrls:   make(map[int]string),
+		apiVersions:      new(apiVersions),
 	}
 	if tlsConfig != nil {
-		a.retryClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+		transport.base = &http.Transport{TLSClientConfig: tlsConfig}
 		a.oneshotClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
 	}
 
@@ -187,12 +251,49 @@ func newAdminAPI(
 		}
 		a.urls[i] = fmt.Sprintf("%s://%s", scheme, host)
 	}
+	a.knownURLs = knownURLs(a.urls, nil)
+	client.CheckRedirect = a.checkRedirect
+	a.oneshotClient.CheckRedirect = a.checkRedirect
 
 	return a, nil
 }
 
+// SetRequestSigner sets the signer of every request sent by the client, or
+// disables request signing if the signer is nil. This must be called before
+// the client is used.
+func (a *AdminAPI) SetRequestSigner(signer RequestSigner) {
+	a.signer = signer
+}
+
+// SetBackoffPolicy sets the backoff between the retries of requests. Zero
+// fields of the policy use the DefaultBackoffPolicy. This must be called
+// before the client is used.
+func (a *AdminAPI) SetBackoffPolicy(p BackoffPolicy) {
+	p = p.withDefaults()
+	a.retryTransport.policy = p
+	a.retryClient.MaxRetries = p.MaxAttempts
+}
+
+// SetRetryHook sets the function called before each retry of a request, which
+// by default logs the retry. A nil hook disables logging retries. This must be
+// called before the client is used.
+func (a *AdminAPI) SetRetryHook(hook func(RetryAttempt)) {
+	a.retryTransport.hook = hook
+}
+
 func (a *AdminAPI) newAdminForSingleHost(host string) (*AdminAPI, error) {
-	return newAdminAPI([]string{host}, a.basicCredentials, a.tlsConfig)
+	aa, err := newAdminAPI([]string{host}, a.basicCredentials, a.tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	aa.signer = a.signer
+	aa.middlewares = a.middlewares
+	aa.apiVersions = a.apiVersions
+	aa.knownURLs = knownURLs(aa.urls, a.knownURLs)
+	aa.setHostTLSConfigs(a.hostTLS)
+	aa.SetBackoffPolicy(a.retryTransport.policy)
+	aa.SetRetryHook(a.retryTransport.hook)
+	return aa, nil
 }
 
 func (a *AdminAPI) urlsWithPath(path string) []string {
@@ -214,22 +315,37 @@ var rng = func() func(int) int {
 	}
 }()
 
+// mapBrokerIDsToURLs queries the node config of every broker to refresh the
+// broker ID to URL mapping. Concurrent calls share a single refresh, rather
+// than each querying every broker.
 func (a *AdminAPI) mapBrokerIDsToURLs(ctx context.Context) {
-	err := a.eachBroker(func(aa *AdminAPI) error {
-		nc, err := aa.GetNodeConfig(ctx)
-		if err != nil {
-			return err
-		}
-		a.brokerIDToUrlsMutex.Lock()
-		a.brokerIDToUrls[nc.NodeID] = aa.urls[0]
-		a.brokerIDToUrlsMutex.Unlock()
-		return nil
+	_, err, _ := a.brokerMapping.Do("", func() (interface{}, error) {
+		return nil, a.eachBroker(func(aa *AdminAPI) error {
+			nc, err := aa.GetNodeConfig(ctx)
+			if err != nil {
+				return err
+			}
+			a.setBrokerURL(nc.NodeID, aa.urls[0])
+			return nil
+		})
 	})
 	if err != nil {
 		log.Warn(fmt.Sprintf("failed to map brokerID to URL for 1 or more brokers: %v", err))
 	}
 }
 
+func (a *AdminAPI) setBrokerURL(brokerID int, url string) {
+	a.brokerIDToUrlsMutex.Lock()
+	defer a.brokerIDToUrlsMutex.Unlock()
+	a.brokerIDToUrls[brokerID] = url
+}
+
+func (a *AdminAPI) mappedBrokers() int {
+	a.brokerIDToUrlsMutex.Lock()
+	defer a.brokerIDToUrlsMutex.Unlock()
+	return len(a.brokerIDToUrls)
+}
+
 // GetLeaderID returns the broker ID of the leader of the Admin API.
 func (a *AdminAPI) GetLeaderID(ctx context.Context) (*int, error) {
 	pa, err := a.GetPartition(ctx, "redpanda", "controller", 0)
@@ -249,6 +365,11 @@ func (a *AdminAPI) GetLeaderID(ctx context.Context) (*int, error) {
 // one of them succeeds, or we run out of nodes.  In the latter case, we will return
 // the error from the last node we tried.
 func (a *AdminAPI) sendAny(ctx context.Context, method, path string, body, into interface{}) error {
+	body, err := replayableBody(body)
+	if err != nil {
+		return err
+	}
+
 	// Shuffle the list of URLs
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	shuffled := make([]string, len(a.urls))
@@ -258,7 +379,6 @@ func (a *AdminAPI) sendAny(ctx context.Context, method, path string, body, into
 	// After a 503 or 504, wait a little for an election
 	const unavailableBackoff = 1500 * time.Millisecond
 
-	var err error
 	for i := range shuffled {
 		url := shuffled[i] + path
 
@@ -270,9 +390,16 @@ func (a *AdminAPI) sendAny(ctx context.Context, method, path string, body, into
 				status := httpErr.Response.StatusCode
 
 				// The node was up but told us the cluster
-				// wasn't ready: wait before retry.
-				if status == 503 || status == 504 {
-					time.Sleep(unavailableBackoff)
+				// wasn't ready: wait before retry, at least as long as it
+				// asked us to.
+				if status == 503 || status == 504 || status == 429 {
+					backoff := httpErr.RetryAfter()
+					if status != 429 && backoff < unavailableBackoff {
+						backoff = unavailableBackoff
+					}
+					if ctxErr := sleep(ctx, backoff); ctxErr != nil {
+						return fmt.Errorf("%w (last error: %v)", ctxErr, err)
+					}
 				}
 			}
 		}
@@ -285,6 +412,7 @@ func (a *AdminAPI) sendAny(ctx context.Context, method, path string, body, into
 		res, err = a.sendAndReceive(ctx, method, url, body, retryable)
 		if err == nil {
 			// Success, return the result from this node.
+			captureResponseHeaders(ctx, res)
 			return maybeUnmarshalRespInto(method, url, res, into)
 		}
 	}
@@ -293,6 +421,57 @@ func (a *AdminAPI) sendAny(ctx context.Context, method, path string, body, into
 	return err
 }
 
+// Read-your-writes polling parameters. Writes are replicated through the
+// controller log, so a resource that was just created on the leader may
+// briefly be missing (or 404) on followers.
+const (
+	visibilityBackoff    = 100 * time.Millisecond
+	visibilityMaxBackoff = 2 * time.Second
+	visibilityTimeout    = 10 * time.Second
+)
+
+// IsNotFound returns whether the error is a 404 response from the admin API.
+func IsNotFound(err error) bool {
+	var he *HTTPResponseError
+	return errors.As(err, &he) && he.Response.StatusCode == http.StatusNotFound
+}
+
+// retryUntilVisible calls fn until it reports that the resource it is looking
+// for is visible. While fn returns false or a 404 error, it is retried with a
+// small, doubling backoff. Any other error is returned immediately.
+//
+// If the context has no deadline, a default of visibilityTimeout is used.
+func retryUntilVisible(
+	ctx context.Context, fn func(context.Context) (bool, error),
+) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, visibilityTimeout)
+		defer cancel()
+	}
+	backoff := visibilityBackoff
+	for {
+		visible, err := fn(ctx)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil && visible {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+			}
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > visibilityMaxBackoff {
+			backoff = visibilityMaxBackoff
+		}
+	}
+}
+
 // sendToLeader sends a single request to the leader of the Admin API for Redpanda >= 21.11.1
 // otherwise, it broadcasts the request.
 func (a *AdminAPI) sendToLeader(
@@ -306,6 +485,11 @@ func (a *AdminAPI) sendToLeader(
 		// an election to start *and* for the resulting election to complete
 		staleLeaderBackoff = 9000 * time.Millisecond
 	)
+	body, err := replayableBody(body)
+	if err != nil {
+		return err
+	}
+
 	// If there's only one broker, let's just send the request to it
 	if len(a.urls) == 1 {
 		return a.sendOne(ctx, method, path, body, into, true)
@@ -326,14 +510,26 @@ func (a *AdminAPI) sendToLeader(
 			if retries == 0 {
 				return err
 			}
-			time.Sleep(noLeaderBackoff)
+			if ctxErr := sleep(ctx, noLeaderBackoff); ctxErr != nil {
+				return fmt.Errorf("%w (last error: %v)", ctxErr, err)
+			}
+		} else if IsStaleLeadership(err) {
+			// The brokers we reached have not yet learned of the
+			// latest election; give them time to catch up.
+			retries--
+			if retries == 0 {
+				return err
+			}
+			if ctxErr := sleep(ctx, noLeaderBackoff); ctxErr != nil {
+				return fmt.Errorf("%w (last error: %v)", ctxErr, err)
+			}
 		} else if err != nil {
 			// Unexpected error, do not retry promptly.
 			return err
 		} else {
 			// Got a leader ID, check if it's resolvable
 			leaderURL, err = a.brokerIDToURL(ctx, *leaderID)
-			if err != nil && len(a.brokerIDToUrls) == 0 {
+			if err != nil && a.mappedBrokers() == 0 {
 				// Could not map any IDs: probably this is an old redpanda
 				// with no node_config endpoint.  Fall back to broadcast.
 				return a.sendAll(ctx, method, path, body, into)
@@ -348,7 +544,9 @@ func (a *AdminAPI) sendToLeader(
 				if retries == 0 {
 					return err
 				}
-				time.Sleep(staleLeaderBackoff)
+				if ctxErr := sleep(ctx, staleLeaderBackoff); ctxErr != nil {
+					return fmt.Errorf("%w (last error: %v)", ctxErr, err)
+				}
 			} else {
 				// Success: break out of retry loop
 				break
@@ -400,6 +598,7 @@ func (a *AdminAPI) sendOne(
 	if err != nil {
 		return err
 	}
+	captureResponseHeaders(ctx, res)
 	return maybeUnmarshalRespInto(method, url, res, into)
 }
 
@@ -417,6 +616,11 @@ func (a *AdminAPI) sendOne(
 // Redpanda is deployed, hence, we need to reintroduce the sendAll method and
 // broadcast on writes to the Admin API.
 func (a *AdminAPI) sendAll(rootCtx context.Context, method, path string, body, into interface{}) error {
+	body, err := replayableBody(body)
+	if err != nil {
+		return err
+	}
+
 	var (
 		once   sync.Once
 		resURL string
@@ -457,8 +661,9 @@ func (a *AdminAPI) sendAll(rootCtx context.Context, method, path string, body, i
 		})
 	}
 
-	err := grp.Wait()
+	err = grp.Wait()
 	if res != nil {
+		captureResponseHeaders(rootCtx, res)
 		return maybeUnmarshalRespInto(method, resURL, res, into)
 	}
 	return err
@@ -481,16 +686,25 @@ func (a *AdminAPI) eachBroker(fn func(aa *AdminAPI) error) error {
 	return grp.Wait().ErrorOrNil()
 }
 
-// Unmarshals a response body into `into`, if it is non-nil.
+// Unmarshals a response body into `into`, if it is non-nil, and closes it.
 //
 // * If into is a *[]byte, the raw response put directly into `into`.
 // * If into is a *string, the raw response put directly into `into` as a string.
-// * Otherwise, the response is json unmarshaled into `into`.
+// * If into is a streamInto, it reads the response body itself.
+// * Otherwise, a non-empty response is json unmarshaled into `into`.
 func maybeUnmarshalRespInto(
 	method, url string, resp *http.Response, into interface{},
 ) error {
-	if into == nil {
+	defer resp.Body.Close()
+	switch t := into.(type) {
+	case nil:
+		// Drain the body so that the connection can be reused.
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck // the response is not needed
 		return nil
+	case streamInto:
+		// The body is not drained: the stream may stop early, and the
+		// rest of a large body is better dropped with the connection.
+		return t(resp.Body)
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -502,6 +716,11 @@ func maybeUnmarshalRespInto(
 	case *string:
 		*t = string(body)
 	default:
+		// Successful responses without a body, such as 204 No Content,
+		// have nothing to decode.
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil
+		}
 		if err := json.Unmarshal(body, into); err != nil {
 			return fmt.Errorf("unable to decode %s %s response body: %w", method, url, err)
 		}
@@ -518,9 +737,19 @@ func (a *AdminAPI) sendAndReceive(
 ) (*http.Response, error) {
 	var r io.Reader
 	if body != nil {
-		// We might be passing io reader already as body, e.g: license file.
-		if v, ok := body.(io.Reader); ok {
+		// A raw body, e.g. a license file, is sent as is.
+		if v, ok := body.(rawBody); ok {
+			r = bytes.NewReader(v)
+		} else if v, ok := body.(io.Reader); ok {
 			r = v
+			// Signers need the whole body.
+			if a.signer != nil {
+				bs, err := io.ReadAll(v)
+				if err != nil {
+					return nil, fmt.Errorf("unable to read request body for %s %s: %w", method, url, err)
+				}
+				r = bytes.NewReader(bs)
+			}
 		} else {
 			bs, err := json.Marshal(body)
 			if err != nil {
@@ -530,37 +759,39 @@ func (a *AdminAPI) sendAndReceive(
 		}
 	}
 
+	if retryable {
+		ctx = withRetryState(ctx)
+	}
 	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
 		return nil, err
 	}
 
-	if a.basicCredentials.Username != "" {
-		req.SetBasicAuth(a.basicCredentials.Username, a.basicCredentials.Password)
-	}
-
 	const applicationJSON = "application/json"
-	req.Header.Set("Content-Type", applicationJSON)
+	if body != nil {
+		req.Header.Set("Content-Type", applicationJSON)
+	}
 	req.Header.Set("Accept", applicationJSON)
-
-	// Issue request to the appropriate client, depending on retry behaviour
-	var res *http.Response
-	if retryable {
-		res, err = a.retryClient.Do(req) //nolint:contextcheck // False positive in v1.0.9, will be fixed in next release.
-	} else {
-		res, err = a.oneshotClient.Do(req)
+	if h, ok := ctx.Value(requestHeadersKey{}).(http.Header); ok {
+		for k, vs := range h {
+			req.Header[k] = vs
+		}
 	}
 
+	// Issue request through the middlewares to the appropriate client,
+	// depending on retry behaviour
+	res, err := a.roundTripper(retryable).RoundTrip(req)
 	if err != nil {
 		// When the server expects a TLS connection, but the TLS config isn't
 		// set/ passed, The client returns an error like
 		// Get "http://localhost:9644/v1/security/users": EOF
 		// which doesn't make it obvious to the user what's going on.
 		if errors.Is(err, io.EOF) {
-			return nil, fmt.Errorf("%s to server %s expected a tls connection: %w", method, url, err)
+			err = fmt.Errorf("%s to server %s expected a tls connection: %w", method, url, err)
 		}
-		return nil, err
+		return nil, classifyTransportError(ctx, err)
 	}
+	decompressResponse(res)
 
 	if res.StatusCode/100 != 2 {
 		resBody, err := io.ReadAll(res.Body)
@@ -580,7 +811,21 @@ func (he HTTPResponseError) DecodeGenericErrorBody() (GenericErrorBody, error) {
 	return resp, err
 }
 
+// Error returns the request, and either the message of the server if the
+// body is the standard error body, or the quoted body.
 func (he HTTPResponseError) Error() string {
+	if ae := he.decodeAdminError(); ae != nil {
+		return fmt.Sprintf("request %s %s failed: %s", he.Method, he.URL, ae)
+	}
 	return fmt.Sprintf("request %s %s failed: %s, body: %q\n",
 		he.Method, he.URL, http.StatusText(he.Response.StatusCode), he.Body)
 }
+
+// Unwrap returns the *AdminError decoded from the body, if the body is the
+// standard error body of the admin server.
+func (he HTTPResponseError) Unwrap() error {
+	if ae := he.decodeAdminError(); ae != nil {
+		return ae
+	}
+	return nil
+}
@@ -16,7 +16,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -223,3 +225,99 @@ func callsForNodeID(calls []testCall, nodeID int) []testCall {
 	}
 	return filtered
 }
+
+func TestRetryUntilVisible(t *testing.T) {
+	var gets int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&gets, 1) < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`["Joss"]`))
+	}))
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	err = adminClient.WaitForUser(context.Background(), "Joss")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&gets))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err = adminClient.WaitForUser(ctx, "Lola")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestConcurrentUse is meant to be run with -race: it sends requests to the
+// leader from many goroutines at once, with and without node_config support.
+func TestConcurrentUse(t *testing.T) {
+	const (
+		nNodes     = 3
+		leaderID   = 1
+		goroutines = 20
+	)
+	for _, test := range []struct {
+		name       string
+		nodeConfig bool // whether brokers support node_config, if not requests are broadcast
+	}{
+		{"to the leader", true},
+		{"broadcast", false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var nodeConfigCalls [nNodes]int32
+			var urls []string
+			for i := 0; i < nNodes; i++ {
+				nodeID := i
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch {
+					case r.URL.Path == "/v1/node_config":
+						atomic.AddInt32(&nodeConfigCalls[nodeID], 1)
+						// Slow enough for the goroutines to
+						// need the mapping at the same time.
+						time.Sleep(200 * time.Millisecond)
+						if !test.nodeConfig {
+							w.WriteHeader(http.StatusNotFound)
+							return
+						}
+						fmt.Fprintf(w, `{"node_id": %d}`, nodeID)
+					case r.URL.Path == "/v1/partitions/redpanda/controller/0":
+						fmt.Fprintf(w, `{"leader_id": %d}`, leaderID)
+					case strings.HasPrefix(r.URL.Path, "/v1/security/users"):
+						if test.nodeConfig && nodeID != leaderID {
+							w.WriteHeader(http.StatusInternalServerError)
+						}
+					}
+				}))
+				defer ts.Close()
+				urls = append(urls, ts.URL)
+			}
+
+			cl, err := NewAdminAPI(urls, BasicCredentials{}, nil)
+			require.NoError(t, err)
+			cl.SetRetryHook(nil)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, goroutines)
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs <- cl.DeleteUser(context.Background(), fmt.Sprintf("user-%d", i))
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			// Without deduplication, each goroutine would query
+			// the node config of every broker.
+			for i := range nodeConfigCalls {
+				require.Less(t, atomic.LoadInt32(&nodeConfigCalls[i]), int32(goroutines), "node config calls of node %d", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,723 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package admintest provides a fake admin API client with programmable
+// responses, to unit test code that talks to the admin API without an HTTP
+// server.
+package admintest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+)
+
+// ErrNotImplemented is returned by the methods of Fake whose function is not
+// set.
+var ErrNotImplemented = errors.New("admintest: method not implemented")
+
+// Fake implements admin.AdminAPIClient. Each method calls the function of
+// the same name with a Fn suffix; if that function is nil, the method returns
+// zero values and an error wrapping ErrNotImplemented. Every call is recorded
+// and can be inspected with Calls.
+//
+// The functions must be set before the fake is used; it is then safe for
+// concurrent use.
+type Fake struct {
+	GetLeaderIDFn                  func(ctx context.Context) (*int, error)
+	BrokersFn                      func(ctx context.Context) ([]admin.Broker, error)
+	BrokerFn                       func(ctx context.Context, node int) (admin.Broker, error)
+	DecommissionBrokerFn           func(ctx context.Context, node int) error
+	RecommissionBrokerFn           func(ctx context.Context, node int) error
+	WaitForDecommissionFn          func(ctx context.Context, nodeID int) error
+	EnableMaintenanceModeFn        func(ctx context.Context, nodeID int) error
+	DisableMaintenanceModeFn       func(ctx context.Context, nodeID int) error
+	MaintenanceStatusFn            func(ctx context.Context, nodeID int) (admin.MaintenanceStatus, error)
+	WaitForDrainFn                 func(ctx context.Context, nodeID int) (admin.MaintenanceStatus, error)
+	CancelNodePartitionsMovementFn func(ctx context.Context, node int) ([]admin.PartitionsMovementResult, error)
+	GetHealthOverviewFn            func(ctx context.Context) (admin.ClusterHealthOverview, error)
+	GetClusterUUIDFn               func(ctx context.Context) (string, error)
+	GetBootstrapStatusFn           func(ctx context.Context) (admin.BootstrapStatus, error)
+	ReadyFn                        func(ctx context.Context) error
+	AliveFn                        func(ctx context.Context) error
+	GetPartitionStatusFn           func(ctx context.Context) (admin.PartitionBalancerStatus, error)
+	CancelAllPartitionsMovementFn  func(ctx context.Context) ([]admin.PartitionsMovementResult, error)
+	TriggerPartitionsRebalanceFn   func(ctx context.Context) error
+	ReconfigurationsFn             func(ctx context.Context) ([]admin.Reconfiguration, error)
+	WaitForReconfigurationDoneFn   func(ctx context.Context, filter func(admin.Reconfiguration) bool) error
+	CheckClusterStabilityFn        func(ctx context.Context, opts admin.StabilityOptions) (admin.StabilityVerdict, error)
+	SubscribeFn                    func(ctx context.Context, opts admin.SubscribeOptions) <-chan admin.ClusterEvent
+	ConfigFn                       func(ctx context.Context) (admin.Config, error)
+	GetLoggersFn                   func(ctx context.Context) ([]admin.Logger, error)
+	SetLogLevelFn                  func(ctx context.Context, name, level string, expirySeconds int) error
+	SetLoggerLevelFn               func(ctx context.Context, logger, level string, expiry time.Duration) error
+	SetLoggerLevelsFn              func(ctx context.Context, levels map[string]string, expiry time.Duration) map[string]error
+	ClusterConfigSchemaFn          func(ctx context.Context) (admin.ConfigSchema, error)
+	PatchClusterConfigFn           func(ctx context.Context, upsert map[string]interface{}, remove []string) (admin.ClusterConfigWriteResult, error)
+	PatchClusterConfigAtVersionFn  func(ctx context.Context, upsert map[string]interface{}, remove []string, version int) (admin.ClusterConfigWriteResult, error)
+	ClusterConfigStatusFn          func(ctx context.Context, sendToLeader bool) (admin.ConfigStatusResponse, error)
+	WaitForConfigVersionFn         func(ctx context.Context, version int) error
+	GetNodeConfigFn                func(ctx context.Context) (admin.NodeConfig, error)
+	GetAllNodeConfigsFn            func(ctx context.Context) (map[int]admin.NodeConfig, error)
+	PatchNodeConfigFn              func(ctx context.Context, upsert map[string]interface{}, remove []string) (admin.NodeConfigWriteResult, error)
+	PatchBrokerConfigFn            func(ctx context.Context, node int, upsert map[string]interface{}, remove []string) (admin.NodeConfigWriteResult, error)
+	GetFeaturesFn                  func(ctx context.Context) (admin.FeaturesResponse, error)
+	GetLicenseInfoFn               func(ctx context.Context) (admin.License, error)
+	SetLicenseFn                   func(ctx context.Context, license interface{}) error
+	GetPartitionFn                 func(ctx context.Context, namespace, topic string, partition int) (admin.Partition, error)
+	AllClusterPartitionsFn         func(ctx context.Context) ([]admin.ClusterPartition, error)
+	ScanPartitionsFn               func(ctx context.Context, fn func(admin.PartitionState) error) error
+	ScanTopicPartitionsFn          func(ctx context.Context, namespace string, topics []string, concurrency int, fn func(admin.PartitionState) error) error
+	GetPartitionManifestFn         func(ctx context.Context, namespace, topic string, partition int) (admin.PartitionManifest, error)
+	CloudStorageCacheStatsFn       func(ctx context.Context, node int) (admin.CacheStats, error)
+	TrimCloudStorageCacheFn        func(ctx context.Context, node int, target admin.CacheTrimTarget) (admin.CacheTrimResult, error)
+	GetPartitionProducersFn        func(ctx context.Context, namespace, topic string, partition int) (admin.PartitionProducers, error)
+	GetPartitionTransactionsFn     func(ctx context.Context, namespace, topic string, partition int) (admin.PartitionTransactions, error)
+	CoordinatorTransactionsFn      func(ctx context.Context, coordinatorPartition int) ([]admin.Transaction, error)
+	AllTransactionsFn              func(ctx context.Context) ([]admin.Transaction, error)
+	RaftGroupStateFn               func(ctx context.Context, group int) (admin.RaftGroupState, error)
+	RaftGroupStatesFn              func(ctx context.Context, group int) (map[int]admin.RaftGroupState, error)
+	RaftGroupFollowersFn           func(ctx context.Context, leader, group int) ([]admin.RaftFollower, error)
+	PrometheusMetricsFn            func(ctx context.Context) ([]byte, error)
+	CloudStorageStatsFn            func(ctx context.Context) (admin.CloudStorageStats, error)
+	UploadDebugBundleFn            func(ctx context.Context, name string, r io.ReaderAt, size int64, opts admin.DebugBundleUploadOptions) (admin.DebugBundleUpload, error)
+	CreateUserFn                   func(ctx context.Context, username, password, mechanism string) error
+	UpdateUserFn                   func(ctx context.Context, username, password, mechanism string) error
+	DeleteUserFn                   func(ctx context.Context, username string) error
+	ListUsersFn                    func(ctx context.Context) ([]string, error)
+	WaitForUserFn                  func(ctx context.Context, username string) error
+	BatchCreateUsersFn             func(ctx context.Context, users []admin.UserCredentials, concurrency int) error
+	BatchDeleteUsersFn             func(ctx context.Context, usernames []string, concurrency int) error
+	DoFn                           func(ctx context.Context, method, path string, body, into interface{}) error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+var _ admin.AdminAPIClient = &Fake{}
+
+// Calls returns the names of the methods called so far, in order.
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Reset forgets the calls recorded so far.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+func (f *Fake) record(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, method)
+}
+
+func notImplemented(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotImplemented)
+}
+
+// GetLeaderID implements admin.AdminAPIClient.
+func (f *Fake) GetLeaderID(ctx context.Context) (*int, error) {
+	f.record("GetLeaderID")
+	if f.GetLeaderIDFn != nil {
+		return f.GetLeaderIDFn(ctx)
+	}
+	return nil, notImplemented("GetLeaderID")
+}
+
+// Brokers implements admin.AdminAPIClient.
+func (f *Fake) Brokers(ctx context.Context) ([]admin.Broker, error) {
+	f.record("Brokers")
+	if f.BrokersFn != nil {
+		return f.BrokersFn(ctx)
+	}
+	return nil, notImplemented("Brokers")
+}
+
+// Broker implements admin.AdminAPIClient.
+func (f *Fake) Broker(ctx context.Context, node int) (admin.Broker, error) {
+	f.record("Broker")
+	if f.BrokerFn != nil {
+		return f.BrokerFn(ctx, node)
+	}
+	return admin.Broker{}, notImplemented("Broker")
+}
+
+// DecommissionBroker implements admin.AdminAPIClient.
+func (f *Fake) DecommissionBroker(ctx context.Context, node int) error {
+	f.record("DecommissionBroker")
+	if f.DecommissionBrokerFn != nil {
+		return f.DecommissionBrokerFn(ctx, node)
+	}
+	return notImplemented("DecommissionBroker")
+}
+
+// RecommissionBroker implements admin.AdminAPIClient.
+func (f *Fake) RecommissionBroker(ctx context.Context, node int) error {
+	f.record("RecommissionBroker")
+	if f.RecommissionBrokerFn != nil {
+		return f.RecommissionBrokerFn(ctx, node)
+	}
+	return notImplemented("RecommissionBroker")
+}
+
+// WaitForDecommission implements admin.AdminAPIClient.
+func (f *Fake) WaitForDecommission(ctx context.Context, nodeID int) error {
+	f.record("WaitForDecommission")
+	if f.WaitForDecommissionFn != nil {
+		return f.WaitForDecommissionFn(ctx, nodeID)
+	}
+	return notImplemented("WaitForDecommission")
+}
+
+// EnableMaintenanceMode implements admin.AdminAPIClient.
+func (f *Fake) EnableMaintenanceMode(ctx context.Context, nodeID int) error {
+	f.record("EnableMaintenanceMode")
+	if f.EnableMaintenanceModeFn != nil {
+		return f.EnableMaintenanceModeFn(ctx, nodeID)
+	}
+	return notImplemented("EnableMaintenanceMode")
+}
+
+// DisableMaintenanceMode implements admin.AdminAPIClient.
+func (f *Fake) DisableMaintenanceMode(ctx context.Context, nodeID int) error {
+	f.record("DisableMaintenanceMode")
+	if f.DisableMaintenanceModeFn != nil {
+		return f.DisableMaintenanceModeFn(ctx, nodeID)
+	}
+	return notImplemented("DisableMaintenanceMode")
+}
+
+// MaintenanceStatus implements admin.AdminAPIClient.
+func (f *Fake) MaintenanceStatus(ctx context.Context, nodeID int) (admin.MaintenanceStatus, error) {
+	f.record("MaintenanceStatus")
+	if f.MaintenanceStatusFn != nil {
+		return f.MaintenanceStatusFn(ctx, nodeID)
+	}
+	return admin.MaintenanceStatus{}, notImplemented("MaintenanceStatus")
+}
+
+// WaitForDrain implements admin.AdminAPIClient.
+func (f *Fake) WaitForDrain(ctx context.Context, nodeID int) (admin.MaintenanceStatus, error) {
+	f.record("WaitForDrain")
+	if f.WaitForDrainFn != nil {
+		return f.WaitForDrainFn(ctx, nodeID)
+	}
+	return admin.MaintenanceStatus{}, notImplemented("WaitForDrain")
+}
+
+// CancelNodePartitionsMovement implements admin.AdminAPIClient.
+func (f *Fake) CancelNodePartitionsMovement(ctx context.Context, node int) ([]admin.PartitionsMovementResult, error) {
+	f.record("CancelNodePartitionsMovement")
+	if f.CancelNodePartitionsMovementFn != nil {
+		return f.CancelNodePartitionsMovementFn(ctx, node)
+	}
+	return nil, notImplemented("CancelNodePartitionsMovement")
+}
+
+// GetHealthOverview implements admin.AdminAPIClient.
+func (f *Fake) GetHealthOverview(ctx context.Context) (admin.ClusterHealthOverview, error) {
+	f.record("GetHealthOverview")
+	if f.GetHealthOverviewFn != nil {
+		return f.GetHealthOverviewFn(ctx)
+	}
+	return admin.ClusterHealthOverview{}, notImplemented("GetHealthOverview")
+}
+
+// GetClusterUUID implements admin.AdminAPIClient.
+func (f *Fake) GetClusterUUID(ctx context.Context) (string, error) {
+	f.record("GetClusterUUID")
+	if f.GetClusterUUIDFn != nil {
+		return f.GetClusterUUIDFn(ctx)
+	}
+	return "", notImplemented("GetClusterUUID")
+}
+
+// GetBootstrapStatus implements admin.AdminAPIClient.
+func (f *Fake) GetBootstrapStatus(ctx context.Context) (admin.BootstrapStatus, error) {
+	f.record("GetBootstrapStatus")
+	if f.GetBootstrapStatusFn != nil {
+		return f.GetBootstrapStatusFn(ctx)
+	}
+	return admin.BootstrapStatus{}, notImplemented("GetBootstrapStatus")
+}
+
+// Ready implements admin.AdminAPIClient.
+func (f *Fake) Ready(ctx context.Context) error {
+	f.record("Ready")
+	if f.ReadyFn != nil {
+		return f.ReadyFn(ctx)
+	}
+	return notImplemented("Ready")
+}
+
+// Alive implements admin.AdminAPIClient.
+func (f *Fake) Alive(ctx context.Context) error {
+	f.record("Alive")
+	if f.AliveFn != nil {
+		return f.AliveFn(ctx)
+	}
+	return notImplemented("Alive")
+}
+
+// GetPartitionStatus implements admin.AdminAPIClient.
+func (f *Fake) GetPartitionStatus(ctx context.Context) (admin.PartitionBalancerStatus, error) {
+	f.record("GetPartitionStatus")
+	if f.GetPartitionStatusFn != nil {
+		return f.GetPartitionStatusFn(ctx)
+	}
+	return admin.PartitionBalancerStatus{}, notImplemented("GetPartitionStatus")
+}
+
+// CancelAllPartitionsMovement implements admin.AdminAPIClient.
+func (f *Fake) CancelAllPartitionsMovement(ctx context.Context) ([]admin.PartitionsMovementResult, error) {
+	f.record("CancelAllPartitionsMovement")
+	if f.CancelAllPartitionsMovementFn != nil {
+		return f.CancelAllPartitionsMovementFn(ctx)
+	}
+	return nil, notImplemented("CancelAllPartitionsMovement")
+}
+
+// TriggerPartitionsRebalance implements admin.AdminAPIClient.
+func (f *Fake) TriggerPartitionsRebalance(ctx context.Context) error {
+	f.record("TriggerPartitionsRebalance")
+	if f.TriggerPartitionsRebalanceFn != nil {
+		return f.TriggerPartitionsRebalanceFn(ctx)
+	}
+	return notImplemented("TriggerPartitionsRebalance")
+}
+
+// Reconfigurations implements admin.AdminAPIClient.
+func (f *Fake) Reconfigurations(ctx context.Context) ([]admin.Reconfiguration, error) {
+	f.record("Reconfigurations")
+	if f.ReconfigurationsFn != nil {
+		return f.ReconfigurationsFn(ctx)
+	}
+	return nil, notImplemented("Reconfigurations")
+}
+
+// WaitForReconfigurationDone implements admin.AdminAPIClient.
+func (f *Fake) WaitForReconfigurationDone(ctx context.Context, filter func(admin.Reconfiguration) bool) error {
+	f.record("WaitForReconfigurationDone")
+	if f.WaitForReconfigurationDoneFn != nil {
+		return f.WaitForReconfigurationDoneFn(ctx, filter)
+	}
+	return notImplemented("WaitForReconfigurationDone")
+}
+
+// CheckClusterStability implements admin.AdminAPIClient.
+func (f *Fake) CheckClusterStability(ctx context.Context, opts admin.StabilityOptions) (admin.StabilityVerdict, error) {
+	f.record("CheckClusterStability")
+	if f.CheckClusterStabilityFn != nil {
+		return f.CheckClusterStabilityFn(ctx, opts)
+	}
+	return admin.StabilityVerdict{}, notImplemented("CheckClusterStability")
+}
+
+// Subscribe implements admin.AdminAPIClient.
+func (f *Fake) Subscribe(ctx context.Context, opts admin.SubscribeOptions) <-chan admin.ClusterEvent {
+	f.record("Subscribe")
+	if f.SubscribeFn != nil {
+		return f.SubscribeFn(ctx, opts)
+	}
+	events := make(chan admin.ClusterEvent)
+	close(events)
+	return events
+}
+
+// Config implements admin.AdminAPIClient.
+func (f *Fake) Config(ctx context.Context) (admin.Config, error) {
+	f.record("Config")
+	if f.ConfigFn != nil {
+		return f.ConfigFn(ctx)
+	}
+	return admin.Config{}, notImplemented("Config")
+}
+
+// GetLoggers implements admin.AdminAPIClient.
+func (f *Fake) GetLoggers(ctx context.Context) ([]admin.Logger, error) {
+	f.record("GetLoggers")
+	if f.GetLoggersFn != nil {
+		return f.GetLoggersFn(ctx)
+	}
+	return nil, notImplemented("GetLoggers")
+}
+
+// SetLogLevel implements admin.AdminAPIClient.
+func (f *Fake) SetLogLevel(ctx context.Context, name, level string, expirySeconds int) error {
+	f.record("SetLogLevel")
+	if f.SetLogLevelFn != nil {
+		return f.SetLogLevelFn(ctx, name, level, expirySeconds)
+	}
+	return notImplemented("SetLogLevel")
+}
+
+// SetLoggerLevel implements admin.AdminAPIClient.
+func (f *Fake) SetLoggerLevel(ctx context.Context, logger, level string, expiry time.Duration) error {
+	f.record("SetLoggerLevel")
+	if f.SetLoggerLevelFn != nil {
+		return f.SetLoggerLevelFn(ctx, logger, level, expiry)
+	}
+	return notImplemented("SetLoggerLevel")
+}
+
+// SetLoggerLevels implements admin.AdminAPIClient.
+func (f *Fake) SetLoggerLevels(ctx context.Context, levels map[string]string, expiry time.Duration) map[string]error {
+	f.record("SetLoggerLevels")
+	if f.SetLoggerLevelsFn != nil {
+		return f.SetLoggerLevelsFn(ctx, levels, expiry)
+	}
+	errs := make(map[string]error, len(levels))
+	for logger := range levels {
+		errs[logger] = notImplemented("SetLoggerLevels")
+	}
+	return errs
+}
+
+// ClusterConfigSchema implements admin.AdminAPIClient.
+func (f *Fake) ClusterConfigSchema(ctx context.Context) (admin.ConfigSchema, error) {
+	f.record("ClusterConfigSchema")
+	if f.ClusterConfigSchemaFn != nil {
+		return f.ClusterConfigSchemaFn(ctx)
+	}
+	return admin.ConfigSchema{}, notImplemented("ClusterConfigSchema")
+}
+
+// PatchClusterConfig implements admin.AdminAPIClient.
+func (f *Fake) PatchClusterConfig(ctx context.Context, upsert map[string]interface{}, remove []string) (admin.ClusterConfigWriteResult, error) {
+	f.record("PatchClusterConfig")
+	if f.PatchClusterConfigFn != nil {
+		return f.PatchClusterConfigFn(ctx, upsert, remove)
+	}
+	return admin.ClusterConfigWriteResult{}, notImplemented("PatchClusterConfig")
+}
+
+// PatchClusterConfigAtVersion implements admin.AdminAPIClient.
+func (f *Fake) PatchClusterConfigAtVersion(ctx context.Context, upsert map[string]interface{}, remove []string, version int) (admin.ClusterConfigWriteResult, error) {
+	f.record("PatchClusterConfigAtVersion")
+	if f.PatchClusterConfigAtVersionFn != nil {
+		return f.PatchClusterConfigAtVersionFn(ctx, upsert, remove, version)
+	}
+	return admin.ClusterConfigWriteResult{}, notImplemented("PatchClusterConfigAtVersion")
+}
+
+// ClusterConfigStatus implements admin.AdminAPIClient.
+func (f *Fake) ClusterConfigStatus(ctx context.Context, sendToLeader bool) (admin.ConfigStatusResponse, error) {
+	f.record("ClusterConfigStatus")
+	if f.ClusterConfigStatusFn != nil {
+		return f.ClusterConfigStatusFn(ctx, sendToLeader)
+	}
+	return admin.ConfigStatusResponse{}, notImplemented("ClusterConfigStatus")
+}
+
+// WaitForConfigVersion implements admin.AdminAPIClient.
+func (f *Fake) WaitForConfigVersion(ctx context.Context, version int) error {
+	f.record("WaitForConfigVersion")
+	if f.WaitForConfigVersionFn != nil {
+		return f.WaitForConfigVersionFn(ctx, version)
+	}
+	return notImplemented("WaitForConfigVersion")
+}
+
+// GetNodeConfig implements admin.AdminAPIClient.
+func (f *Fake) GetNodeConfig(ctx context.Context) (admin.NodeConfig, error) {
+	f.record("GetNodeConfig")
+	if f.GetNodeConfigFn != nil {
+		return f.GetNodeConfigFn(ctx)
+	}
+	return admin.NodeConfig{}, notImplemented("GetNodeConfig")
+}
+
+// GetAllNodeConfigs implements admin.AdminAPIClient.
+func (f *Fake) GetAllNodeConfigs(ctx context.Context) (map[int]admin.NodeConfig, error) {
+	f.record("GetAllNodeConfigs")
+	if f.GetAllNodeConfigsFn != nil {
+		return f.GetAllNodeConfigsFn(ctx)
+	}
+	return nil, notImplemented("GetAllNodeConfigs")
+}
+
+// PatchNodeConfig implements admin.AdminAPIClient.
+func (f *Fake) PatchNodeConfig(ctx context.Context, upsert map[string]interface{}, remove []string) (admin.NodeConfigWriteResult, error) {
+	f.record("PatchNodeConfig")
+	if f.PatchNodeConfigFn != nil {
+		return f.PatchNodeConfigFn(ctx, upsert, remove)
+	}
+	return admin.NodeConfigWriteResult{}, notImplemented("PatchNodeConfig")
+}
+
+// PatchBrokerConfig implements admin.AdminAPIClient.
+func (f *Fake) PatchBrokerConfig(ctx context.Context, node int, upsert map[string]interface{}, remove []string) (admin.NodeConfigWriteResult, error) {
+	f.record("PatchBrokerConfig")
+	if f.PatchBrokerConfigFn != nil {
+		return f.PatchBrokerConfigFn(ctx, node, upsert, remove)
+	}
+	return admin.NodeConfigWriteResult{}, notImplemented("PatchBrokerConfig")
+}
+
+// GetFeatures implements admin.AdminAPIClient.
+func (f *Fake) GetFeatures(ctx context.Context) (admin.FeaturesResponse, error) {
+	f.record("GetFeatures")
+	if f.GetFeaturesFn != nil {
+		return f.GetFeaturesFn(ctx)
+	}
+	return admin.FeaturesResponse{}, notImplemented("GetFeatures")
+}
+
+// GetLicenseInfo implements admin.AdminAPIClient.
+func (f *Fake) GetLicenseInfo(ctx context.Context) (admin.License, error) {
+	f.record("GetLicenseInfo")
+	if f.GetLicenseInfoFn != nil {
+		return f.GetLicenseInfoFn(ctx)
+	}
+	return admin.License{}, notImplemented("GetLicenseInfo")
+}
+
+// SetLicense implements admin.AdminAPIClient.
+func (f *Fake) SetLicense(ctx context.Context, license interface{}) error {
+	f.record("SetLicense")
+	if f.SetLicenseFn != nil {
+		return f.SetLicenseFn(ctx, license)
+	}
+	return notImplemented("SetLicense")
+}
+
+// GetPartition implements admin.AdminAPIClient.
+func (f *Fake) GetPartition(ctx context.Context, namespace, topic string, partition int) (admin.Partition, error) {
+	f.record("GetPartition")
+	if f.GetPartitionFn != nil {
+		return f.GetPartitionFn(ctx, namespace, topic, partition)
+	}
+	return admin.Partition{}, notImplemented("GetPartition")
+}
+
+// AllClusterPartitions implements admin.AdminAPIClient.
+func (f *Fake) AllClusterPartitions(ctx context.Context) ([]admin.ClusterPartition, error) {
+	f.record("AllClusterPartitions")
+	if f.AllClusterPartitionsFn != nil {
+		return f.AllClusterPartitionsFn(ctx)
+	}
+	return nil, notImplemented("AllClusterPartitions")
+}
+
+// ScanPartitions implements admin.AdminAPIClient.
+func (f *Fake) ScanPartitions(ctx context.Context, fn func(admin.PartitionState) error) error {
+	f.record("ScanPartitions")
+	if f.ScanPartitionsFn != nil {
+		return f.ScanPartitionsFn(ctx, fn)
+	}
+	return notImplemented("ScanPartitions")
+}
+
+// ScanTopicPartitions implements admin.AdminAPIClient.
+func (f *Fake) ScanTopicPartitions(ctx context.Context, namespace string, topics []string, concurrency int, fn func(admin.PartitionState) error) error {
+	f.record("ScanTopicPartitions")
+	if f.ScanTopicPartitionsFn != nil {
+		return f.ScanTopicPartitionsFn(ctx, namespace, topics, concurrency, fn)
+	}
+	return notImplemented("ScanTopicPartitions")
+}
+
+// GetPartitionManifest implements admin.AdminAPIClient.
+func (f *Fake) GetPartitionManifest(ctx context.Context, namespace, topic string, partition int) (admin.PartitionManifest, error) {
+	f.record("GetPartitionManifest")
+	if f.GetPartitionManifestFn != nil {
+		return f.GetPartitionManifestFn(ctx, namespace, topic, partition)
+	}
+	return admin.PartitionManifest{}, notImplemented("GetPartitionManifest")
+}
+
+// CloudStorageCacheStats implements admin.AdminAPIClient.
+func (f *Fake) CloudStorageCacheStats(ctx context.Context, node int) (admin.CacheStats, error) {
+	f.record("CloudStorageCacheStats")
+	if f.CloudStorageCacheStatsFn != nil {
+		return f.CloudStorageCacheStatsFn(ctx, node)
+	}
+	return admin.CacheStats{}, notImplemented("CloudStorageCacheStats")
+}
+
+// TrimCloudStorageCache implements admin.AdminAPIClient.
+func (f *Fake) TrimCloudStorageCache(ctx context.Context, node int, target admin.CacheTrimTarget) (admin.CacheTrimResult, error) {
+	f.record("TrimCloudStorageCache")
+	if f.TrimCloudStorageCacheFn != nil {
+		return f.TrimCloudStorageCacheFn(ctx, node, target)
+	}
+	return admin.CacheTrimResult{}, notImplemented("TrimCloudStorageCache")
+}
+
+// GetPartitionProducers implements admin.AdminAPIClient.
+func (f *Fake) GetPartitionProducers(ctx context.Context, namespace, topic string, partition int) (admin.PartitionProducers, error) {
+	f.record("GetPartitionProducers")
+	if f.GetPartitionProducersFn != nil {
+		return f.GetPartitionProducersFn(ctx, namespace, topic, partition)
+	}
+	return admin.PartitionProducers{}, notImplemented("GetPartitionProducers")
+}
+
+// GetPartitionTransactions implements admin.AdminAPIClient.
+func (f *Fake) GetPartitionTransactions(ctx context.Context, namespace, topic string, partition int) (admin.PartitionTransactions, error) {
+	f.record("GetPartitionTransactions")
+	if f.GetPartitionTransactionsFn != nil {
+		return f.GetPartitionTransactionsFn(ctx, namespace, topic, partition)
+	}
+	return admin.PartitionTransactions{}, notImplemented("GetPartitionTransactions")
+}
+
+// CoordinatorTransactions implements admin.AdminAPIClient.
+func (f *Fake) CoordinatorTransactions(ctx context.Context, coordinatorPartition int) ([]admin.Transaction, error) {
+	f.record("CoordinatorTransactions")
+	if f.CoordinatorTransactionsFn != nil {
+		return f.CoordinatorTransactionsFn(ctx, coordinatorPartition)
+	}
+	return nil, notImplemented("CoordinatorTransactions")
+}
+
+// AllTransactions implements admin.AdminAPIClient.
+func (f *Fake) AllTransactions(ctx context.Context) ([]admin.Transaction, error) {
+	f.record("AllTransactions")
+	if f.AllTransactionsFn != nil {
+		return f.AllTransactionsFn(ctx)
+	}
+	return nil, notImplemented("AllTransactions")
+}
+
+// RaftGroupState implements admin.AdminAPIClient.
+func (f *Fake) RaftGroupState(ctx context.Context, group int) (admin.RaftGroupState, error) {
+	f.record("RaftGroupState")
+	if f.RaftGroupStateFn != nil {
+		return f.RaftGroupStateFn(ctx, group)
+	}
+	return admin.RaftGroupState{}, notImplemented("RaftGroupState")
+}
+
+// RaftGroupStates implements admin.AdminAPIClient.
+func (f *Fake) RaftGroupStates(ctx context.Context, group int) (map[int]admin.RaftGroupState, error) {
+	f.record("RaftGroupStates")
+	if f.RaftGroupStatesFn != nil {
+		return f.RaftGroupStatesFn(ctx, group)
+	}
+	return nil, notImplemented("RaftGroupStates")
+}
+
+// RaftGroupFollowers implements admin.AdminAPIClient.
+func (f *Fake) RaftGroupFollowers(ctx context.Context, leader, group int) ([]admin.RaftFollower, error) {
+	f.record("RaftGroupFollowers")
+	if f.RaftGroupFollowersFn != nil {
+		return f.RaftGroupFollowersFn(ctx, leader, group)
+	}
+	return nil, notImplemented("RaftGroupFollowers")
+}
+
+// PrometheusMetrics implements admin.AdminAPIClient.
+func (f *Fake) PrometheusMetrics(ctx context.Context) ([]byte, error) {
+	f.record("PrometheusMetrics")
+	if f.PrometheusMetricsFn != nil {
+		return f.PrometheusMetricsFn(ctx)
+	}
+	return nil, notImplemented("PrometheusMetrics")
+}
+
+// CloudStorageStats implements admin.AdminAPIClient.
+func (f *Fake) CloudStorageStats(ctx context.Context) (admin.CloudStorageStats, error) {
+	f.record("CloudStorageStats")
+	if f.CloudStorageStatsFn != nil {
+		return f.CloudStorageStatsFn(ctx)
+	}
+	return admin.CloudStorageStats{}, notImplemented("CloudStorageStats")
+}
+
+// UploadDebugBundle implements admin.AdminAPIClient.
+func (f *Fake) UploadDebugBundle(ctx context.Context, name string, r io.ReaderAt, size int64, opts admin.DebugBundleUploadOptions) (admin.DebugBundleUpload, error) {
+	f.record("UploadDebugBundle")
+	if f.UploadDebugBundleFn != nil {
+		return f.UploadDebugBundleFn(ctx, name, r, size, opts)
+	}
+	return admin.DebugBundleUpload{}, notImplemented("UploadDebugBundle")
+}
+
+// CreateUser implements admin.AdminAPIClient.
+func (f *Fake) CreateUser(ctx context.Context, username, password, mechanism string) error {
+	f.record("CreateUser")
+	if f.CreateUserFn != nil {
+		return f.CreateUserFn(ctx, username, password, mechanism)
+	}
+	return notImplemented("CreateUser")
+}
+
+// UpdateUser implements admin.AdminAPIClient.
+func (f *Fake) UpdateUser(ctx context.Context, username, password, mechanism string) error {
+	f.record("UpdateUser")
+	if f.UpdateUserFn != nil {
+		return f.UpdateUserFn(ctx, username, password, mechanism)
+	}
+	return notImplemented("UpdateUser")
+}
+
+// DeleteUser implements admin.AdminAPIClient.
+func (f *Fake) DeleteUser(ctx context.Context, username string) error {
+	f.record("DeleteUser")
+	if f.DeleteUserFn != nil {
+		return f.DeleteUserFn(ctx, username)
+	}
+	return notImplemented("DeleteUser")
+}
+
+// ListUsers implements admin.AdminAPIClient.
+func (f *Fake) ListUsers(ctx context.Context) ([]string, error) {
+	f.record("ListUsers")
+	if f.ListUsersFn != nil {
+		return f.ListUsersFn(ctx)
+	}
+	return nil, notImplemented("ListUsers")
+}
+
+// WaitForUser implements admin.AdminAPIClient.
+func (f *Fake) WaitForUser(ctx context.Context, username string) error {
+	f.record("WaitForUser")
+	if f.WaitForUserFn != nil {
+		return f.WaitForUserFn(ctx, username)
+	}
+	return notImplemented("WaitForUser")
+}
+
+// BatchCreateUsers implements admin.AdminAPIClient.
+func (f *Fake) BatchCreateUsers(ctx context.Context, users []admin.UserCredentials, concurrency int) error {
+	f.record("BatchCreateUsers")
+	if f.BatchCreateUsersFn != nil {
+		return f.BatchCreateUsersFn(ctx, users, concurrency)
+	}
+	return notImplemented("BatchCreateUsers")
+}
+
+// BatchDeleteUsers implements admin.AdminAPIClient.
+func (f *Fake) BatchDeleteUsers(ctx context.Context, usernames []string, concurrency int) error {
+	f.record("BatchDeleteUsers")
+	if f.BatchDeleteUsersFn != nil {
+		return f.BatchDeleteUsersFn(ctx, usernames, concurrency)
+	}
+	return notImplemented("BatchDeleteUsers")
+}
+
+// Do implements admin.AdminAPIClient.
+func (f *Fake) Do(ctx context.Context, method, path string, body, into interface{}) error {
+	f.record("Do")
+	if f.DoFn != nil {
+		return f.DoFn(ctx, method, path, body, into)
+	}
+	return notImplemented("Do")
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admintest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	f := &Fake{
+		ListUsersFn: func(context.Context) ([]string, error) {
+			return []string{"alice", "bob"}, nil
+		},
+	}
+	var cl admin.AdminAPIClient = f
+
+	users, err := cl.ListUsers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, users)
+
+	_, err = cl.Brokers(ctx)
+	require.True(t, errors.Is(err, ErrNotImplemented))
+
+	errs := cl.SetLoggerLevels(ctx, map[string]string{"raft": "debug"}, 0)
+	require.True(t, errors.Is(errs["raft"], ErrNotImplemented))
+
+	_, open := <-cl.Subscribe(ctx, admin.SubscribeOptions{})
+	require.False(t, open)
+
+	require.Equal(t, []string{"ListUsers", "Brokers", "SetLoggerLevels", "Subscribe"}, f.Calls())
+	f.Reset()
+	require.Empty(t, f.Calls())
+}
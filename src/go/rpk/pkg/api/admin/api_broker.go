@@ -11,24 +11,58 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"sort"
+	"time"
 )
 
 const (
-	brokersEndpoint = "/v1/brokers"
-	brokerEndpoint  = "/v1/brokers/%d"
+	brokersEndpoint = "/brokers"
+	brokerEndpoint  = "/brokers/%d"
 )
 
+// ErrMaintenanceModeUnsupported is returned when a broker does not report its
+// maintenance status, e.g. a v21 broker during an upgrade to v22.
+var ErrMaintenanceModeUnsupported = errors.New("maintenance mode is not supported by the broker, upgrade in progress?")
+
+// drainPollInterval is how often WaitForDrain queries the maintenance status.
+var drainPollInterval = 2 * time.Second
+
+// MaintenanceStatus is the maintenance mode status of a broker. A broker in
+// maintenance mode drains itself by transferring the leadership of its
+// partitions to other brokers.
 type MaintenanceStatus struct {
-	Draining     bool `json:"draining"`
-	Finished     bool `json:"finished"`
-	Errors       bool `json:"errors"`
-	Partitions   int  `json:"partitions"`
-	Eligible     int  `json:"eligible"`
-	Transferring int  `json:"transferring"`
-	Failed       int  `json:"failed"`
+	// Draining is true if the broker is in maintenance mode.
+	Draining bool `json:"draining"`
+	// Finished is true once the broker transferred every leadership it could.
+	Finished bool `json:"finished"`
+	// Errors is true if draining encountered errors, which the broker logs.
+	Errors bool `json:"errors"`
+	// Partitions is the number of partitions of the broker.
+	Partitions int `json:"partitions"`
+	// Eligible is the number of partitions the broker still leads and whose
+	// leadership can be transferred, i.e. partitions with other replicas.
+	Eligible int `json:"eligible"`
+	// Transferring is the number of leadership transfers in progress.
+	Transferring int `json:"transferring"`
+	// Failed is the number of failed leadership transfers.
+	Failed int `json:"failed"`
+}
+
+// Drained returns whether the broker is in maintenance mode and finished
+// draining. Errors may have prevented some leaderships from being transferred.
+func (m MaintenanceStatus) Drained() bool {
+	return m.Draining && m.Finished
+}
+
+// PendingPartitions returns the number of partitions whose leadership is yet
+// to be transferred before the broker is drained.
+func (m MaintenanceStatus) PendingPartitions() int {
+	if m.Drained() {
+		return 0
+	}
+	return m.Eligible
 }
 
 // MembershipStatus enumerates possible membership states for brokers.
@@ -57,64 +91,89 @@ func (a *AdminAPI) Brokers(ctx context.Context) ([]Broker, error) {
 	defer func() {
 		sort.Slice(bs, func(i, j int) bool { return bs[i].NodeID < bs[j].NodeID }) //nolint:revive // return inside this deferred function is for the sort's less function
 	}()
-	return bs, a.sendAny(ctx, http.MethodGet, brokersEndpoint, nil, &bs)
+	return bs, a.sendVersioned(ctx, brokersEndpoint, func(ctx context.Context, v APIVersion) error {
+		return a.GetAny(ctx, v.Path(brokersEndpoint), nil, &bs)
+	})
 }
 
 // Broker queries one of the client's hosts and returns broker information.
 func (a *AdminAPI) Broker(ctx context.Context, node int) (Broker, error) {
 	var b Broker
-	err := a.sendAny(
-		ctx,
-		http.MethodGet,
-		fmt.Sprintf(brokerEndpoint, node), nil, &b)
+	err := a.sendVersioned(ctx, brokersEndpoint, func(ctx context.Context, v APIVersion) error {
+		return a.GetAny(ctx, v.Path(fmt.Sprintf(brokerEndpoint, node)), nil, &b)
+	})
 	return b, err
 }
 
 // DecommissionBroker issues a decommission request for the given broker.
 func (a *AdminAPI) DecommissionBroker(ctx context.Context, node int) error {
-	return a.sendToLeader(
-		ctx,
-		http.MethodPut,
-		fmt.Sprintf("%s/%d/decommission", brokersEndpoint, node),
-		nil,
-		nil,
-	)
+	return a.PutLeader(ctx, APIv1.Path(fmt.Sprintf("%s/%d/decommission", brokersEndpoint, node)), nil, nil, nil)
 }
 
 // RecommissionBroker issues a recommission request for the given broker.
 func (a *AdminAPI) RecommissionBroker(ctx context.Context, node int) error {
-	return a.sendToLeader(
-		ctx,
-		http.MethodPut,
-		fmt.Sprintf("%s/%d/recommission", brokersEndpoint, node),
-		nil,
-		nil,
-	)
+	return a.PutLeader(ctx, APIv1.Path(fmt.Sprintf("%s/%d/recommission", brokersEndpoint, node)), nil, nil, nil)
 }
 
 // EnableMaintenanceMode enables maintenance mode for a node.
 func (a *AdminAPI) EnableMaintenanceMode(ctx context.Context, nodeID int) error {
-	return a.sendAny(
-		ctx,
-		http.MethodPut,
-		fmt.Sprintf("%s/%d/maintenance", brokersEndpoint, nodeID),
-		nil,
-		nil,
-	)
+	return a.PutAny(ctx, APIv1.Path(fmt.Sprintf("%s/%d/maintenance", brokersEndpoint, nodeID)), nil, nil, nil)
 }
 
 // DisableMaintenanceMode disables maintenance mode for a node.
 func (a *AdminAPI) DisableMaintenanceMode(ctx context.Context, nodeID int) error {
-	return a.sendAny(
-		ctx,
-		http.MethodDelete,
-		fmt.Sprintf("%s/%d/maintenance", brokersEndpoint, nodeID),
-		nil,
-		nil,
+	return a.DeleteAny(ctx, APIv1.Path(fmt.Sprintf("%s/%d/maintenance", brokersEndpoint, nodeID)), nil, nil, nil)
+}
+
+// MaintenanceStatus returns the maintenance status of a node.
+func (a *AdminAPI) MaintenanceStatus(ctx context.Context, nodeID int) (MaintenanceStatus, error) {
+	b, err := a.Broker(ctx, nodeID)
+	if err != nil {
+		return MaintenanceStatus{}, err
+	}
+	if b.Maintenance == nil {
+		return MaintenanceStatus{}, ErrMaintenanceModeUnsupported
+	}
+	return *b.Maintenance, nil
+}
+
+// WaitForDrain polls the maintenance status of a node in maintenance mode
+// until it is drained, and returns its last status, which reports whether
+// draining encountered errors.
+//
+// A node that is not in maintenance mode yet, e.g. right after enabling it,
+// and failures to query the status, are waited on until the context is done.
+// The last failure is then returned, or else the error of the context, along
+// with the last status to report the progress of draining.
+func (a *AdminAPI) WaitForDrain(ctx context.Context, nodeID int) (MaintenanceStatus, error) {
+	var (
+		status  MaintenanceStatus
+		lastErr error
 	)
+	for {
+		s, err := a.MaintenanceStatus(ctx, nodeID)
+		switch {
+		case err == nil && s.Drained():
+			return s, nil
+		case err == nil:
+			status, lastErr = s, nil
+		case ctx.Err() == nil:
+			// A request interrupted by the context says nothing new.
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return status, fmt.Errorf("node %d is not drained: %w", nodeID, lastErr)
+			}
+			return status, fmt.Errorf("node %d is not drained, %d partitions pending: %w", nodeID, status.PendingPartitions(), ctx.Err())
+		case <-time.After(drainPollInterval):
+		}
+	}
 }
 
 func (a *AdminAPI) CancelNodePartitionsMovement(ctx context.Context, node int) ([]PartitionsMovementResult, error) {
 	var response []PartitionsMovementResult
-	return response, a.sendAny(ctx, http.MethodPost, fmt.Sprintf("%s/%d/cancel_partition_moves", brokersEndpoint, node), nil, &response)
+	return response, a.PostAny(ctx, APIv1.Path(fmt.Sprintf("%s/%d/cancel_partition_moves", brokersEndpoint, node)), nil, nil, &response)
 }
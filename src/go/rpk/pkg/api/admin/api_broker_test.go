@@ -0,0 +1,92 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForDrain(t *testing.T) {
+	defer func(interval time.Duration) { drainPollInterval = interval }(drainPollInterval)
+	drainPollInterval = time.Millisecond
+
+	// broker serves the given maintenance statuses of broker 1 in turn,
+	// repeating the last one.
+	broker := func(statuses ...string) *httptest.Server {
+		var calls int32
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/brokers/1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			i := int(atomic.AddInt32(&calls, 1)) - 1
+			if i >= len(statuses) {
+				i = len(statuses) - 1
+			}
+			if statuses[i] == "" {
+				w.Write([]byte(`{"node_id":1}`))
+				return
+			}
+			fmt.Fprintf(w, `{"node_id":1,"maintenance_status":%s}`, statuses[i])
+		}))
+	}
+
+	t.Run("drained", func(t *testing.T) {
+		b := broker(
+			`{"draining":false}`,
+			`{"draining":true,"partitions":10,"eligible":4,"transferring":2}`,
+			`{"draining":true,"finished":true,"errors":true,"partitions":10,"failed":1}`,
+		)
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		status, err := cl.WaitForDrain(context.Background(), 1)
+		require.NoError(t, err)
+		require.True(t, status.Drained())
+		require.True(t, status.Errors)
+		require.Equal(t, 1, status.Failed)
+		require.Equal(t, 0, status.PendingPartitions())
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		b := broker(`{"draining":true,"partitions":10,"eligible":4}`)
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		status, err := cl.WaitForDrain(ctx, 1)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Equal(t, 4, status.PendingPartitions())
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		b := broker("")
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = cl.WaitForDrain(ctx, 1)
+		require.True(t, errors.Is(err, ErrMaintenanceModeUnsupported))
+	})
+}
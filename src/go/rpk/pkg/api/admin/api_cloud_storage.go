@@ -0,0 +1,216 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// PartitionManifest is the archival manifest of a partition, describing the
+// segments of the partition that have been uploaded to cloud storage.
+type PartitionManifest struct {
+	Version     int    `json:"version"`
+	Namespace   string `json:"namespace"`
+	Topic       string `json:"topic"`
+	Partition   int    `json:"partition"`
+	Revision    int64  `json:"revision"`
+	LastOffset  int64  `json:"last_offset"`
+	StartOffset int64  `json:"start_offset"`
+	// Segments is keyed by the name of the segment in cloud storage.
+	Segments map[string]ManifestSegment `json:"segments"`
+}
+
+// ManifestSegment is a segment uploaded to cloud storage.
+//
+// The base and committed offsets are raft offsets. DeltaOffset is the number
+// of non-data batches (e.g. configuration batches) before the segment, i.e.
+// the kafka offset of a record is its raft offset minus the delta.
+type ManifestSegment struct {
+	IsCompacted     bool  `json:"is_compacted"`
+	SizeBytes       int64 `json:"size_bytes"`
+	BaseOffset      int64 `json:"base_offset"`
+	CommittedOffset int64 `json:"committed_offset"`
+	BaseTimestamp   int64 `json:"base_timestamp"`
+	MaxTimestamp    int64 `json:"max_timestamp"`
+	DeltaOffset     int64 `json:"delta_offset"`
+	DeltaOffsetEnd  int64 `json:"delta_offset_end"`
+	ArchiverTerm    int64 `json:"archiver_term"`
+	SegmentTerm     int64 `json:"segment_term"`
+	NTPRevision     int64 `json:"ntp_revision"`
+}
+
+// KafkaBaseOffset returns the kafka offset of the first record in the segment.
+func (s ManifestSegment) KafkaBaseOffset() int64 {
+	return s.BaseOffset - s.DeltaOffset
+}
+
+// KafkaLastOffset returns the kafka offset of the last record in the segment.
+// Manifests written by older versions do not track the delta at the end of
+// the segment, in which case the delta at the start of the segment is used.
+func (s ManifestSegment) KafkaLastOffset() int64 {
+	delta := s.DeltaOffsetEnd
+	if delta == 0 {
+		delta = s.DeltaOffset
+	}
+	return s.CommittedOffset - delta
+}
+
+// NamedManifestSegment is a ManifestSegment along with its name.
+type NamedManifestSegment struct {
+	Name string
+	ManifestSegment
+}
+
+// SortedSegments returns the segments of the manifest sorted by base offset.
+func (m PartitionManifest) SortedSegments() []NamedManifestSegment {
+	segments := make([]NamedManifestSegment, 0, len(m.Segments))
+	for name, s := range m.Segments {
+		segments = append(segments, NamedManifestSegment{name, s})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].BaseOffset < segments[j].BaseOffset
+	})
+	return segments
+}
+
+// ManifestGap is a range of raft offsets that are not covered by any segment
+// in a manifest.
+type ManifestGap struct {
+	From int64 // first missing offset
+	To   int64 // last missing offset
+}
+
+// Gaps returns the ranges of offsets that are missing between the segments of
+// the manifest, sorted by offset. Overlapping segments are not gaps.
+func (m PartitionManifest) Gaps() []ManifestGap {
+	var (
+		gaps []ManifestGap
+		next int64 = -1
+	)
+	for _, s := range m.SortedSegments() {
+		if next >= 0 && s.BaseOffset > next {
+			gaps = append(gaps, ManifestGap{next, s.BaseOffset - 1})
+		}
+		if s.CommittedOffset+1 > next {
+			next = s.CommittedOffset + 1
+		}
+	}
+	return gaps
+}
+
+// GetPartitionManifest returns the archival manifest of a partition. The
+// manifest is maintained by the leader of the partition, so the request is
+// sent to the leader if it can be found.
+func (a *AdminAPI) GetPartitionManifest(
+	ctx context.Context, namespace, topic string, partition int,
+) (PartitionManifest, error) {
+	var m PartitionManifest
+	path := APIv1.Path(fmt.Sprintf("/cloud_storage/manifest/%s/%s/%d", namespace, topic, partition))
+	if len(a.urls) == 1 {
+		return m, a.sendOne(ctx, http.MethodGet, path, nil, &m, true)
+	}
+
+	pa, err := a.GetPartition(ctx, namespace, topic, partition)
+	if err != nil {
+		return m, err
+	}
+	if pa.LeaderID < 0 {
+		return m, fmt.Errorf("partition %s/%s/%d has no leader", namespace, topic, partition)
+	}
+	leaderURL, err := a.brokerIDToURL(ctx, pa.LeaderID)
+	if err != nil {
+		// We cannot reach the leader directly; any broker may proxy the
+		// request to it.
+		return m, a.GetAny(ctx, path, nil, &m)
+	}
+	aLeader, err := a.newAdminForSingleHost(leaderURL)
+	if err != nil {
+		return m, err
+	}
+	return m, aLeader.sendOne(ctx, http.MethodGet, path, nil, &m, true)
+}
+
+// CacheStats is the usage of the cloud storage cache of a broker, which holds
+// the segments downloaded from cloud storage on the local disk.
+type CacheStats struct {
+	SizeBytes    int64 `json:"size_bytes"`
+	Objects      int64 `json:"objects"`
+	MaxSizeBytes int64 `json:"max_size_bytes"`
+	// MaxObjects is zero if the number of objects is not limited.
+	MaxObjects int64 `json:"max_objects"`
+	// LastTrim is the unix time in milliseconds of the last trim of the
+	// cache, or zero if the cache was never trimmed.
+	LastTrim int64 `json:"last_trim"`
+}
+
+// CacheTrimTarget is the usage a cache trim shrinks the cache to. Zero values
+// leave the corresponding limit to the broker, which trims down to its
+// configured limits.
+type CacheTrimTarget struct {
+	SizeBytes int64
+	Objects   int64
+}
+
+// CacheTrimResult is what a cache trim reclaimed.
+type CacheTrimResult struct {
+	BytesTrimmed   int64 `json:"bytes_trimmed"`
+	ObjectsTrimmed int64 `json:"objects_trimmed"`
+}
+
+// CloudStorageCacheStats returns the usage of the cloud storage cache of the
+// broker with the given node ID, which must be reachable through one of the
+// URLs of the client.
+//
+// Brokers without cloud storage enabled return a 404, which can be checked
+// with IsNotFound.
+func (a *AdminAPI) CloudStorageCacheStats(ctx context.Context, node int) (CacheStats, error) {
+	var stats CacheStats
+	aa, err := a.forBroker(ctx, node)
+	if err != nil {
+		return stats, err
+	}
+	return stats, aa.GetOne(ctx, APIv1.Path("/cloud_storage/cache/stats"), nil, &stats)
+}
+
+// TrimCloudStorageCache evicts segments from the cloud storage cache of the
+// broker with the given node ID until the cache fits in the target. Evicted
+// segments are downloaded again if they are read.
+func (a *AdminAPI) TrimCloudStorageCache(
+	ctx context.Context, node int, target CacheTrimTarget,
+) (CacheTrimResult, error) {
+	var result CacheTrimResult
+	aa, err := a.forBroker(ctx, node)
+	if err != nil {
+		return result, err
+	}
+	query := url.Values{}
+	if target.SizeBytes > 0 {
+		query.Set("bytes", strconv.FormatInt(target.SizeBytes, 10))
+	}
+	if target.Objects > 0 {
+		query.Set("objects", strconv.FormatInt(target.Objects, 10))
+	}
+	return result, aa.PostOne(ctx, APIv1.Path("/cloud_storage/cache/trim"), query, nil, &result)
+}
+
+// forBroker returns a single host client for the broker with the given node
+// ID, for node local endpoints.
+func (a *AdminAPI) forBroker(ctx context.Context, node int) (*AdminAPI, error) {
+	brokerURL, err := a.brokerIDToURL(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return a.newAdminForSingleHost(brokerURL)
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPartitionManifest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/cloud_storage/manifest/kafka/foo/0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{
+  "version": 1,
+  "namespace": "kafka",
+  "topic": "foo",
+  "partition": 0,
+  "revision": 12,
+  "last_offset": 399,
+  "segments": {
+    "200-1-v1.log": {"base_offset": 200, "committed_offset": 299, "delta_offset": 4, "size_bytes": 2048},
+    "0-1-v1.log": {"base_offset": 0, "committed_offset": 99, "delta_offset": 0, "delta_offset_end": 2, "size_bytes": 1024},
+    "300-1-v1.log": {"base_offset": 300, "committed_offset": 399, "delta_offset": 6, "size_bytes": 4096}
+  }
+}`))
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	m, err := cl.GetPartitionManifest(context.Background(), "kafka", "foo", 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(399), m.LastOffset)
+
+	segments := m.SortedSegments()
+	require.Len(t, segments, 3)
+	require.Equal(t, []string{"0-1-v1.log", "200-1-v1.log", "300-1-v1.log"},
+		[]string{segments[0].Name, segments[1].Name, segments[2].Name})
+	require.Equal(t, int64(97), segments[0].KafkaLastOffset())
+	require.Equal(t, int64(196), segments[1].KafkaBaseOffset())
+	require.Equal(t, int64(295), segments[1].KafkaLastOffset())
+
+	require.Equal(t, []ManifestGap{{100, 199}}, m.Gaps())
+
+	_, err = cl.GetPartitionManifest(context.Background(), "kafka", "bar", 0)
+	require.True(t, IsNotFound(err))
+}
+
+func TestCloudStorageCache(t *testing.T) {
+	broker := func(id int, trims *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/v1/node_config":
+				json.NewEncoder(w).Encode(map[string]int{"node_id": id})
+			case r.URL.Path == "/v1/cloud_storage/cache/stats" && r.Method == http.MethodGet:
+				w.Write([]byte(`{"size_bytes":4096,"objects":3,"max_size_bytes":8192,"last_trim":1650000000000}`))
+			case r.URL.Path == "/v1/cloud_storage/cache/trim" && r.Method == http.MethodPost:
+				*trims = append(*trims, r.URL.RawQuery)
+				w.Write([]byte(`{"bytes_trimmed":3072,"objects_trimmed":2}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	var trims0, trims1 []string
+	b0 := broker(0, &trims0)
+	defer b0.Close()
+	b1 := broker(1, &trims1)
+	defer b1.Close()
+
+	cl, err := NewAdminAPI([]string{b0.URL, b1.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	stats, err := cl.CloudStorageCacheStats(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, CacheStats{
+		SizeBytes:    4096,
+		Objects:      3,
+		MaxSizeBytes: 8192,
+		LastTrim:     1650000000000,
+	}, stats)
+
+	result, err := cl.TrimCloudStorageCache(context.Background(), 1, CacheTrimTarget{SizeBytes: 1024})
+	require.NoError(t, err)
+	require.Equal(t, CacheTrimResult{BytesTrimmed: 3072, ObjectsTrimmed: 2}, result)
+	_, err = cl.TrimCloudStorageCache(context.Background(), 1, CacheTrimTarget{})
+	require.NoError(t, err)
+	require.Empty(t, trims0)
+	require.Equal(t, []string{"bytes=1024", ""}, trims1)
+
+	_, err = cl.CloudStorageCacheStats(context.Background(), 2)
+	require.Error(t, err)
+}
@@ -11,9 +11,15 @@ package admin
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 )
 
+const clusterHealthOverviewPath = "/cluster/health_overview"
+
 // Health overview data structure.
 type ClusterHealthOverview struct {
 	IsHealthy            bool     `json:"is_healthy"`
@@ -21,6 +27,8 @@ type ClusterHealthOverview struct {
 	AllNodes             []int    `json:"all_nodes"`
 	NodesDown            []int    `json:"nodes_down"`
 	LeaderlessPartitions []string `json:"leaderless_partitions"`
+	// UnderReplicatedCount is only reported by newer Redpanda versions.
+	UnderReplicatedCount *int `json:"under_replicated_count,omitempty"`
 }
 
 // PartitionBalancerStatus is the status of the partition auto balancer.
@@ -65,17 +73,225 @@ type PartitionsMovementResult struct {
 	Result    string `json:"result,omitempty"`
 }
 
+// Reconfiguration is a partition movement that is in progress.
+type Reconfiguration struct {
+	Namespace        string    `json:"ns"`
+	Topic            string    `json:"topic"`
+	PartitionID      int       `json:"partition"`
+	PreviousReplicas []Replica `json:"previous_replicas"`
+	NewReplicas      []Replica `json:"current_replicas"`
+	BytesLeftToMove  int       `json:"bytes_left_to_move"`
+	BytesMoved       int       `json:"bytes_moved"`
+}
+
+// StabilityOptions configures CheckClusterStability.
+type StabilityOptions struct {
+	// IgnoreNodes are the nodes that are expected to be down or in
+	// maintenance mode, e.g. the node that is being restarted.
+	IgnoreNodes []int
+	// AllowUnderReplicated does not consider under-replicated partitions
+	// a reason for instability.
+	AllowUnderReplicated bool
+	// AllowReconfigurations does not consider partition movements in
+	// progress a reason for instability.
+	AllowReconfigurations bool
+}
+
+// StabilityVerdict is the result of CheckClusterStability.
+type StabilityVerdict struct {
+	// Stable is true if no reasons for instability were found.
+	Stable bool `json:"stable"`
+	// Reasons describe why the cluster is not stable.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
 func (a *AdminAPI) GetHealthOverview(ctx context.Context) (ClusterHealthOverview, error) {
 	var response ClusterHealthOverview
-	return response, a.sendAny(ctx, http.MethodGet, "/v1/cluster/health_overview", nil, &response)
+	return response, a.sendVersioned(ctx, clusterHealthOverviewPath, func(ctx context.Context, v APIVersion) error {
+		return a.GetAny(ctx, v.Path(clusterHealthOverviewPath), nil, &response)
+	})
 }
 
 func (a *AdminAPI) GetPartitionStatus(ctx context.Context) (PartitionBalancerStatus, error) {
 	var response PartitionBalancerStatus
-	return response, a.sendAny(ctx, http.MethodGet, "/v1/cluster/partition_balancer/status", nil, &response)
+	return response, a.GetAny(ctx, APIv1.Path("/cluster/partition_balancer/status"), nil, &response)
 }
 
 func (a *AdminAPI) CancelAllPartitionsMovement(ctx context.Context) ([]PartitionsMovementResult, error) {
 	var response []PartitionsMovementResult
-	return response, a.sendAny(ctx, http.MethodPost, "/v1/cluster/cancel_reconfigurations", nil, &response)
+	return response, a.PostAny(ctx, APIv1.Path("/cluster/cancel_reconfigurations"), nil, nil, &response)
+}
+
+// TriggerPartitionsRebalance asks the partition balancer to rebalance the
+// partitions of the cluster now, rather than on its next tick.
+func (a *AdminAPI) TriggerPartitionsRebalance(ctx context.Context) error {
+	return a.PostAny(ctx, APIv1.Path("/partitions/rebalance"), nil, nil, nil)
+}
+
+// ErrMixedClusters is returned by GetBootstrapStatus when the brokers of the
+// client belong to different clusters.
+var ErrMixedClusters = errors.New("the brokers report different cluster UUIDs")
+
+// BootstrapStatus is the bootstrap state of the brokers of the client.
+type BootstrapStatus struct {
+	// Bootstrapped is true if every broker joined a bootstrapped cluster.
+	Bootstrapped bool `json:"bootstrapped"`
+	// ClusterUUID is the UUID of the cluster, empty if no broker finished
+	// bootstrapping.
+	ClusterUUID string `json:"cluster_uuid,omitempty"`
+	// Brokers is the cluster UUID reported by each admin API address, empty
+	// for the brokers that did not finish bootstrapping.
+	Brokers map[string]string `json:"brokers"`
+}
+
+// GetClusterUUID returns the UUID that the cluster generated when it was
+// bootstrapped, which identifies it for its whole life, unlike its broker
+// addresses. The UUID is empty if the cluster did not finish bootstrapping.
+// Redpanda versions without a cluster UUID return a 404, which can be checked
+// with IsNotFound.
+func (a *AdminAPI) GetClusterUUID(ctx context.Context) (string, error) {
+	var response struct {
+		ClusterUUID string `json:"cluster_uuid"`
+	}
+	return response.ClusterUUID, a.GetAny(ctx, APIv1.Path("/cluster/uuid"), nil, &response)
+}
+
+// GetBootstrapStatus asks every broker of the client for its cluster UUID.
+// If the brokers report different UUIDs, which happens when addresses of
+// different clusters are mixed up in the configuration, ErrMixedClusters is
+// returned.
+func (a *AdminAPI) GetBootstrapStatus(ctx context.Context) (BootstrapStatus, error) {
+	var mu sync.Mutex
+	status := BootstrapStatus{Brokers: make(map[string]string, len(a.urls))}
+	err := a.eachBroker(func(aa *AdminAPI) error {
+		uuid, err := aa.GetClusterUUID(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		status.Brokers[aa.urls[0]] = uuid
+		return nil
+	})
+	if err != nil {
+		return BootstrapStatus{}, err
+	}
+
+	status.Bootstrapped = true
+	var mixed bool
+	for _, uuid := range status.Brokers {
+		if uuid == "" {
+			status.Bootstrapped = false
+			continue
+		}
+		if status.ClusterUUID == "" {
+			status.ClusterUUID = uuid
+		} else if uuid != status.ClusterUUID {
+			mixed = true
+		}
+	}
+	if mixed {
+		var brokers []string
+		for url, uuid := range status.Brokers {
+			brokers = append(brokers, fmt.Sprintf("%s: %q", url, uuid))
+		}
+		sort.Strings(brokers)
+		return BootstrapStatus{}, fmt.Errorf("%w: %s", ErrMixedClusters, strings.Join(brokers, ", "))
+	}
+	return status, nil
+}
+
+// Reconfigurations returns the partition movements that are in progress.
+func (a *AdminAPI) Reconfigurations(ctx context.Context) ([]Reconfiguration, error) {
+	var response []Reconfiguration
+	return response, a.GetAny(ctx, APIv1.Path("/partitions/reconfigurations"), nil, &response)
+}
+
+// CheckClusterStability combines the cluster health overview, the maintenance
+// status of the brokers and the partition movements in progress into a single
+// verdict, telling whether it is safe to disrupt the cluster further, e.g. by
+// restarting the next broker during a rolling restart.
+//
+// An error is returned if the cluster state cannot be retrieved. Partition
+// movements are not checked in clusters that do not report them.
+func (a *AdminAPI) CheckClusterStability(
+	ctx context.Context, opts StabilityOptions,
+) (StabilityVerdict, error) {
+	health, err := a.GetHealthOverview(ctx)
+	if err != nil {
+		return StabilityVerdict{}, fmt.Errorf("unable to request cluster health: %w", err)
+	}
+	brokers, err := a.Brokers(ctx)
+	if err != nil {
+		return StabilityVerdict{}, fmt.Errorf("unable to request brokers: %w", err)
+	}
+	var reconfigurations []Reconfiguration
+	if !opts.AllowReconfigurations {
+		reconfigurations, err = a.Reconfigurations(ctx)
+		if err != nil && !IsNotFound(err) {
+			return StabilityVerdict{}, fmt.Errorf("unable to request partition movements: %w", err)
+		}
+	}
+	return EvaluateClusterStability(health, brokers, reconfigurations, opts), nil
+}
+
+// EvaluateClusterStability returns the stability verdict for the given cluster
+// state. See CheckClusterStability.
+func EvaluateClusterStability(
+	health ClusterHealthOverview,
+	brokers []Broker,
+	reconfigurations []Reconfiguration,
+	opts StabilityOptions,
+) StabilityVerdict {
+	ignored := make(map[int]bool, len(opts.IgnoreNodes))
+	for _, id := range opts.IgnoreNodes {
+		ignored[id] = true
+	}
+
+	var reasons []string
+	var down []int
+	for _, id := range health.NodesDown {
+		if !ignored[id] {
+			down = append(down, id)
+		}
+	}
+	sort.Ints(down)
+	if len(down) > 0 {
+		reasons = append(reasons, fmt.Sprintf("nodes down: %v", down))
+	}
+	if health.ControllerID < 0 {
+		reasons = append(reasons, "there is no controller leader")
+	}
+	if n := len(health.LeaderlessPartitions); n > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d partitions without a leader", n))
+	}
+	if !opts.AllowUnderReplicated && health.UnderReplicatedCount != nil && *health.UnderReplicatedCount > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d under-replicated partitions", *health.UnderReplicatedCount))
+	}
+
+	for _, b := range brokers {
+		if ignored[b.NodeID] {
+			continue
+		}
+		if b.MembershipStatus == MembershipStatusDraining {
+			reasons = append(reasons, fmt.Sprintf("node %d is being decommissioned", b.NodeID))
+		}
+		if b.Maintenance != nil && b.Maintenance.Draining {
+			reasons = append(reasons, fmt.Sprintf("node %d is in maintenance mode", b.NodeID))
+		}
+	}
+
+	if !opts.AllowReconfigurations && len(reconfigurations) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d partition movements in progress", len(reconfigurations)))
+	}
+
+	// The health overview also reports the cluster as unhealthy when the
+	// ignored nodes are down, in which case the reasons above are enough.
+	if !health.IsHealthy && len(reasons) == 0 && len(down) == len(health.NodesDown) {
+		reasons = append(reasons, "the cluster reports itself as unhealthy")
+	}
+	return StabilityVerdict{
+		Stable:  len(reasons) == 0,
+		Reasons: reasons,
+	}
 }
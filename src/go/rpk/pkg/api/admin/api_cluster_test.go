@@ -0,0 +1,160 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateClusterStability(t *testing.T) {
+	two := 2
+	healthy := ClusterHealthOverview{
+		IsHealthy:    true,
+		ControllerID: 0,
+		AllNodes:     []int{0, 1, 2},
+	}
+	brokers := []Broker{
+		{NodeID: 0, MembershipStatus: MembershipStatusActive},
+		{NodeID: 1, MembershipStatus: MembershipStatusActive},
+		{NodeID: 2, MembershipStatus: MembershipStatusActive},
+	}
+	for _, test := range []struct {
+		name             string
+		health           ClusterHealthOverview
+		brokers          []Broker
+		reconfigurations []Reconfiguration
+		opts             StabilityOptions
+		exp              StabilityVerdict
+	}{
+		{
+			name:    "stable",
+			health:  healthy,
+			brokers: brokers,
+			exp:     StabilityVerdict{Stable: true},
+		},
+		{
+			name: "unstable",
+			health: ClusterHealthOverview{
+				ControllerID:         -1,
+				AllNodes:             []int{0, 1, 2},
+				NodesDown:            []int{2, 1},
+				LeaderlessPartitions: []string{"kafka/foo/0"},
+				UnderReplicatedCount: &two,
+			},
+			brokers: []Broker{
+				{NodeID: 0, MembershipStatus: MembershipStatusDraining},
+				{NodeID: 1, Maintenance: &MaintenanceStatus{Draining: true}},
+			},
+			reconfigurations: []Reconfiguration{{Topic: "foo"}},
+			exp: StabilityVerdict{Reasons: []string{
+				"nodes down: [1 2]",
+				"there is no controller leader",
+				"1 partitions without a leader",
+				"2 under-replicated partitions",
+				"node 0 is being decommissioned",
+				"node 1 is in maintenance mode",
+				"1 partition movements in progress",
+			}},
+		},
+		{
+			name: "ignored node down and in maintenance",
+			health: ClusterHealthOverview{
+				ControllerID:         0,
+				AllNodes:             []int{0, 1, 2},
+				NodesDown:            []int{2},
+				UnderReplicatedCount: &two,
+			},
+			brokers: []Broker{
+				{NodeID: 0},
+				{NodeID: 1},
+				{NodeID: 2, Maintenance: &MaintenanceStatus{Draining: true}},
+			},
+			reconfigurations: []Reconfiguration{{Topic: "foo"}},
+			opts: StabilityOptions{
+				IgnoreNodes:           []int{2},
+				AllowUnderReplicated:  true,
+				AllowReconfigurations: true,
+			},
+			exp: StabilityVerdict{Stable: true},
+		},
+		{
+			name:    "reported unhealthy without details",
+			health:  ClusterHealthOverview{AllNodes: []int{0, 1, 2}},
+			brokers: brokers,
+			exp:     StabilityVerdict{Reasons: []string{"the cluster reports itself as unhealthy"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := EvaluateClusterStability(test.health, test.brokers, test.reconfigurations, test.opts)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+func TestGetBootstrapStatus(t *testing.T) {
+	broker := func(uuid string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/cluster/uuid", r.URL.Path)
+			fmt.Fprintf(w, `{"cluster_uuid":%q}`, uuid)
+		}))
+	}
+	a, b, empty, other := broker("a"), broker("a"), broker(""), broker("b")
+	for _, s := range []*httptest.Server{a, b, empty, other} {
+		defer s.Close()
+	}
+
+	for _, test := range []struct {
+		name     string
+		urls     []string
+		exp      BootstrapStatus
+		expMixed bool
+	}{
+		{
+			name: "bootstrapped",
+			urls: []string{a.URL, b.URL},
+			exp: BootstrapStatus{
+				Bootstrapped: true,
+				ClusterUUID:  "a",
+				Brokers:      map[string]string{a.URL: "a", b.URL: "a"},
+			},
+		},
+		{
+			name: "bootstrapping",
+			urls: []string{a.URL, empty.URL},
+			exp: BootstrapStatus{
+				ClusterUUID: "a",
+				Brokers:     map[string]string{a.URL: "a", empty.URL: ""},
+			},
+		},
+		{
+			name:     "mixed clusters",
+			urls:     []string{a.URL, other.URL},
+			expMixed: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cl, err := NewAdminAPI(test.urls, BasicCredentials{}, nil)
+			require.NoError(t, err)
+			status, err := cl.GetBootstrapStatus(context.Background())
+			if test.expMixed {
+				require.ErrorIs(t, err, ErrMixedClusters)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, status)
+		})
+	}
+}
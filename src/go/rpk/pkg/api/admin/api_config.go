@@ -12,10 +12,14 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config represents a Redpanda configuration. There are many keys returned, so
@@ -26,7 +30,7 @@ type Config map[string]interface{}
 // multiple URLs are configured.
 func (a *AdminAPI) Config(ctx context.Context) (Config, error) {
 	var rawResp []byte
-	err := a.sendAny(ctx, http.MethodGet, "/v1/config", nil, &rawResp)
+	err := a.GetAny(ctx, APIv1.Path("/config"), nil, &rawResp)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +41,28 @@ func (a *AdminAPI) Config(ctx context.Context) (Config, error) {
 	return unmarshaled, nil
 }
 
+// Logger is a broker logger and its current level.
+type Logger struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// GetLoggers returns the loggers of the single admin host in this client and
+// their current level. This function will return an error if the client has
+// multiple URLs configured.
+//
+// Older versions of Redpanda do not support listing loggers and return a 404,
+// which can be checked with IsNotFound.
+func (a *AdminAPI) GetLoggers(ctx context.Context) ([]Logger, error) {
+	var loggers []Logger
+	err := a.GetOne(ctx, APIv1.Path("/loggers"), nil, &loggers)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(loggers, func(i, j int) bool { return loggers[i].Name < loggers[j].Name })
+	return loggers, nil
+}
+
 // SetLogLevel sets the logger level for the logger `name` to the given level
 // for the single admin host in this client. This function will return an error
 // if the client has multiple URLs configured.
@@ -44,8 +70,14 @@ func (a *AdminAPI) Config(ctx context.Context) (Config, error) {
 // Expiry configures how long the log level override will persist. If zero,
 // Redpanda will persist the override until it shuts down.
 func (a *AdminAPI) SetLogLevel(ctx context.Context, name, level string, expirySeconds int) error {
-	if expirySeconds < 0 {
-		return fmt.Errorf("invalid negative expiry of %ds", expirySeconds)
+	return a.SetLoggerLevel(ctx, name, level, time.Duration(expirySeconds)*time.Second)
+}
+
+// SetLoggerLevel is SetLogLevel with the expiry as a duration, which is
+// rounded up to the second.
+func (a *AdminAPI) SetLoggerLevel(ctx context.Context, logger, level string, expiry time.Duration) error {
+	if expiry < 0 {
+		return fmt.Errorf("invalid negative expiry of %v", expiry)
 	}
 	switch level = strings.ToLower(level); level {
 	case "error",
@@ -57,8 +89,31 @@ func (a *AdminAPI) SetLogLevel(ctx context.Context, name, level string, expirySe
 		return fmt.Errorf("unknown logger level %q", level)
 	}
 
-	path := fmt.Sprintf("/v1/config/log_level/%s?level=%s&expires=%d", url.PathEscape(name), level, expirySeconds)
-	return a.sendOne(ctx, http.MethodPut, path, nil, nil, false)
+	expirySeconds := int64((expiry + time.Second - 1) / time.Second)
+	query := url.Values{
+		"level":   []string{level},
+		"expires": []string{strconv.FormatInt(expirySeconds, 10)},
+	}
+	return a.PutOne(ctx, APIv1.Path("/config/log_level/"+url.PathEscape(logger)), query, nil, nil)
+}
+
+// SetLoggerLevels sets the level of many loggers at once, mapping logger
+// names to their level, with the same semantics as SetLoggerLevel. Every
+// logger is attempted; the returned map contains the loggers that could not
+// be set and is nil if all were set.
+func (a *AdminAPI) SetLoggerLevels(
+	ctx context.Context, levels map[string]string, expiry time.Duration,
+) map[string]error {
+	var failures map[string]error
+	for logger, level := range levels {
+		if err := a.SetLoggerLevel(ctx, logger, level, expiry); err != nil {
+			if failures == nil {
+				failures = make(map[string]error)
+			}
+			failures[logger] = err
+		}
+	}
+	return failures
 }
 
 type ConfigPropertyItems struct {
@@ -85,7 +140,7 @@ type ConfigSchemaResponse struct {
 
 func (a *AdminAPI) ClusterConfigSchema(ctx context.Context) (ConfigSchema, error) {
 	var response ConfigSchemaResponse
-	err := a.sendAny(ctx, http.MethodGet, "/v1/cluster_config/schema", nil, &response)
+	err := a.GetAny(ctx, APIv1.Path("/cluster_config/schema"), nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +161,7 @@ func (a *AdminAPI) PatchClusterConfig(
 	}
 
 	var result ClusterConfigWriteResult
-	err := a.sendToLeader(ctx, http.MethodPut, "/v1/cluster_config", body, &result)
+	err := a.PutLeader(ctx, APIv1.Path("/cluster_config"), nil, body, &result)
 	if err != nil {
 		return result, err
 	}
@@ -114,6 +169,31 @@ func (a *AdminAPI) PatchClusterConfig(
 	return result, nil
 }
 
+// PatchClusterConfigAtVersion is PatchClusterConfig, but only applies the
+// patch if the cluster configuration is still at the given version, which is
+// usually the version of the configuration the patch was computed from. The
+// version is sent as an If-Match precondition, so that a concurrent write
+// fails the patch rather than being silently overwritten by it; such
+// failures are reported by IsConfigVersionConflict.
+func (a *AdminAPI) PatchClusterConfigAtVersion(
+	ctx context.Context, upsert map[string]interface{}, remove []string, version int,
+) (ClusterConfigWriteResult, error) {
+	ctx = withRequestHeader(ctx, "If-Match", strconv.Quote(strconv.Itoa(version)))
+	return a.PatchClusterConfig(ctx, upsert, remove)
+}
+
+// IsConfigVersionConflict returns whether the error is a failed
+// PatchClusterConfigAtVersion because the cluster configuration was changed
+// since the version the patch was made for.
+func IsConfigVersionConflict(err error) bool {
+	var he *HTTPResponseError
+	if !errors.As(err, &he) || he.Response == nil {
+		return false
+	}
+	code := he.Response.StatusCode
+	return code == http.StatusPreconditionFailed || code == http.StatusConflict
+}
+
 type ConfigStatus struct {
 	NodeID        int64    `json:"node_id"`
 	Restart       bool     `json:"restart"`
@@ -124,14 +204,26 @@ type ConfigStatus struct {
 
 type ConfigStatusResponse []ConfigStatus
 
+// Version returns the highest cluster configuration version of the nodes,
+// which is the version to make conditional writes against.
+func (s ConfigStatusResponse) Version() int {
+	var v int64
+	for _, n := range s {
+		if n.ConfigVersion > v {
+			v = n.ConfigVersion
+		}
+	}
+	return int(v)
+}
+
 func (a *AdminAPI) ClusterConfigStatus(ctx context.Context, sendToLeader bool) (ConfigStatusResponse, error) {
 	var result ConfigStatusResponse
 	var err error
-	path := "/v1/cluster_config/status"
+	path := APIv1.Path("/cluster_config/status")
 	if sendToLeader {
-		err = a.sendToLeader(ctx, http.MethodGet, path, nil, &result)
+		err = a.GetLeader(ctx, path, nil, &result)
 	} else {
-		err = a.sendAny(ctx, http.MethodGet, path, nil, &result)
+		err = a.GetAny(ctx, path, nil, &result)
 	}
 	if err != nil {
 		return nil, err
@@ -139,3 +231,29 @@ func (a *AdminAPI) ClusterConfigStatus(ctx context.Context, sendToLeader bool) (
 
 	return result, nil
 }
+
+// WaitForConfigVersion waits until every node in the cluster reports a cluster
+// config version at least as new as the given version, such as the version
+// returned from PatchClusterConfig.
+//
+// If the context has no deadline, this gives up after a short default timeout.
+func (a *AdminAPI) WaitForConfigVersion(ctx context.Context, version int) error {
+	var lagging []int64
+	err := retryUntilVisible(ctx, func(ctx context.Context) (bool, error) {
+		status, err := a.ClusterConfigStatus(ctx, true)
+		if err != nil {
+			return false, err
+		}
+		lagging = lagging[:0]
+		for _, s := range status {
+			if s.ConfigVersion < int64(version) {
+				lagging = append(lagging, s.NodeID)
+			}
+		}
+		return len(status) > 0 && len(lagging) == 0, nil
+	})
+	if err != nil && len(lagging) > 0 {
+		return fmt.Errorf("config version %d not yet applied on nodes %v: %w", version, lagging, err)
+	}
+	return err
+}
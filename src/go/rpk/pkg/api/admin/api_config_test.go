@@ -0,0 +1,130 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggers(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		set = make(map[string]string)
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/loggers":
+			w.Write([]byte(`[{"name":"raft","level":"info"},{"name":"kafka","level":"debug"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/config/log_level/raft":
+			mu.Lock()
+			set["raft"] = r.URL.RawQuery
+			mu.Unlock()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	loggers, err := cl.GetLoggers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Logger{{"kafka", "debug"}, {"raft", "info"}}, loggers)
+
+	require.NoError(t, cl.SetLoggerLevel(ctx, "raft", "TRACE", 1500*time.Millisecond))
+	require.Equal(t, "expires=2&level=trace", set["raft"])
+	require.Error(t, cl.SetLoggerLevel(ctx, "raft", "verbose", 0))
+	require.Error(t, cl.SetLoggerLevel(ctx, "raft", "info", -time.Second))
+
+	failures := cl.SetLoggerLevels(ctx, map[string]string{"raft": "warn", "unknown": "warn"}, 0)
+	require.Equal(t, "expires=0&level=warn", set["raft"])
+	require.Len(t, failures, 1)
+	require.True(t, IsNotFound(failures["unknown"]))
+}
+
+func TestPatchClusterConfigAtVersion(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		version = 4
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/cluster_config/status":
+			fmt.Fprintf(w, `[{"node_id":0,"config_version":%d},{"node_id":1,"config_version":%d}]`, version-1, version)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/cluster_config":
+			if m := r.Header.Get("If-Match"); m != "" && m != strconv.Quote(strconv.Itoa(version)) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				fmt.Fprintf(w, `{"message":"config version is %d","code":412}`, version)
+				return
+			}
+			version++
+			fmt.Fprintf(w, `{"config_version":%d}`, version)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	status, err := cl.ClusterConfigStatus(ctx, true)
+	require.NoError(t, err)
+	require.Equal(t, 4, status.Version())
+
+	res, err := cl.PatchClusterConfigAtVersion(ctx, map[string]interface{}{"a": 1}, nil, status.Version())
+	require.NoError(t, err)
+	require.Equal(t, 5, res.ConfigVersion)
+
+	// The configuration changed since the status was read.
+	_, err = cl.PatchClusterConfigAtVersion(ctx, map[string]interface{}{"a": 2}, nil, status.Version())
+	require.True(t, IsConfigVersionConflict(err))
+
+	// Unconditional writes still go through.
+	res, err = cl.PatchClusterConfig(ctx, map[string]interface{}{"a": 2}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 6, res.ConfigVersion)
+}
+
+func TestWaitForConfigVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/cluster_config/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[{"node_id":0,"config_version":5},{"node_id":1,"config_version":4},{"node_id":2,"config_version":5}]`)
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, cl.WaitForConfigVersion(context.Background(), 4))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err = cl.WaitForConfigVersion(ctx, 5)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, err.Error(), "config version 5 not yet applied on nodes [1]")
+}
@@ -0,0 +1,253 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	bundleUploadsPath = "/debug/bundle/uploads"
+
+	defaultBundleChunkSize = 4 << 20
+	// bundleChunkAttempts is how many times a chunk is sent before the
+	// upload fails. The upload can be resumed later, as long as the server
+	// keeps the partial upload.
+	bundleChunkAttempts = 3
+	bundleRetryBackoff  = time.Second
+)
+
+// DebugBundleUploadOptions configures UploadDebugBundle.
+type DebugBundleUploadOptions struct {
+	// URL is the base URL of the bundle intake endpoint, e.g. a support URL.
+	// If empty, the bundle is uploaded to the admin API of a broker of the
+	// client.
+	URL string
+	// Token, if set, is sent as a bearer token to URL. The credentials of
+	// the admin API are never sent to URL.
+	Token string
+	// ChunkSize is the size of the chunks of the archive that are sent in
+	// each request, before compression. It defaults to 4MiB.
+	ChunkSize int64
+	// Progress, if set, is called after every chunk with the number of bytes
+	// of the archive the server has, and the size of the archive.
+	Progress func(uploaded, size int64)
+}
+
+// DebugBundleUpload is the state of an upload on the server.
+type DebugBundleUpload struct {
+	ID   string `json:"upload_id"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// Offset is the number of bytes of the archive the server has; the
+	// upload is complete when it reaches Size.
+	Offset int64 `json:"offset"`
+}
+
+// UploadDebugBundle streams the archive in r, of the given size, to the
+// bundle intake endpoint, in gzip compressed chunks.
+//
+// The server identifies uploads by the SHA-256 of the archive, and replies to
+// a new upload of an archive it partially has with the offset to continue
+// from: uploading a bundle again after a failure only sends what is missing.
+// Failed chunks are retried after asking the server what it received.
+func (a *AdminAPI) UploadDebugBundle(
+	ctx context.Context,
+	name string,
+	r io.ReaderAt,
+	size int64,
+	opts DebugBundleUploadOptions,
+) (DebugBundleUpload, error) {
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultBundleChunkSize
+	}
+	sum := sha256.New()
+	if _, err := io.Copy(sum, io.NewSectionReader(r, 0, size)); err != nil {
+		return DebugBundleUpload{}, fmt.Errorf("unable to read %s: %w", name, err)
+	}
+
+	up, err := a.newBundleUploader(ctx, opts, map[string]interface{}{
+		"name":   name,
+		"size":   size,
+		"sha256": hex.EncodeToString(sum.Sum(nil)),
+	})
+	if err != nil {
+		return DebugBundleUpload{}, fmt.Errorf("unable to start the upload of %s: %w", name, err)
+	}
+
+	state := up.state
+	if opts.Progress != nil {
+		opts.Progress(state.Offset, size)
+	}
+	buf := make([]byte, chunkSize)
+	for state.Offset < size {
+		n, err := r.ReadAt(buf, state.Offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return state, fmt.Errorf("unable to read %s: %w", name, err)
+		}
+		prev := state.Offset
+		state, err = up.sendChunk(ctx, prev, buf[:n])
+		if err != nil {
+			return state, fmt.Errorf("unable to upload %s: %w", name, err)
+		}
+		if state.Offset == prev {
+			return state, fmt.Errorf("unable to upload %s: the server did not accept the chunk at offset %d", name, prev)
+		}
+		if opts.Progress != nil {
+			opts.Progress(state.Offset, size)
+		}
+	}
+	return state, nil
+}
+
+// bundleUploader sends the requests of a single upload. Partial uploads live
+// on the server that received them, so every request of an upload goes to
+// the same server.
+type bundleUploader struct {
+	cl    *http.Client
+	base  string
+	auth  func(req *http.Request, body []byte) error
+	state DebugBundleUpload
+}
+
+func (a *AdminAPI) newBundleUploader(
+	ctx context.Context, opts DebugBundleUploadOptions, create interface{},
+) (*bundleUploader, error) {
+	body, err := json.Marshal(create)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.URL != "" {
+		up := &bundleUploader{
+			cl:   &http.Client{Timeout: time.Minute},
+			base: strings.TrimSuffix(opts.URL, "/"),
+			auth: func(req *http.Request, _ []byte) error {
+				if opts.Token != "" {
+					req.Header.Set("Authorization", "Bearer "+opts.Token)
+				}
+				return nil
+			},
+		}
+		return up, up.do(ctx, http.MethodPost, up.base+APIv1.Path(bundleUploadsPath), body, false, &up.state)
+	}
+
+	// Uploads are started on the first broker that accepts them.
+	for _, u := range a.urls {
+		up := &bundleUploader{
+			cl:   a.oneshotClient,
+			base: u,
+			auth: func(req *http.Request, body []byte) error {
+				if a.basicCredentials.Username != "" {
+					req.SetBasicAuth(a.basicCredentials.Username, a.basicCredentials.Password)
+				}
+				if a.signer != nil {
+					return a.signer.Sign(req, body)
+				}
+				return nil
+			},
+		}
+		if err = up.do(ctx, http.MethodPost, up.base+APIv1.Path(bundleUploadsPath), body, false, &up.state); err == nil {
+			return up, nil
+		}
+	}
+	return nil, err
+}
+
+// sendChunk sends the chunk of the archive starting at offset, retrying after
+// failures from where the server is.
+func (up *bundleUploader) sendChunk(
+	ctx context.Context, offset int64, chunk []byte,
+) (DebugBundleUpload, error) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(chunk); err != nil {
+		return up.state, err
+	}
+	if err := zw.Close(); err != nil {
+		return up.state, err
+	}
+
+	path := up.base + APIv1.Path(bundleUploadsPath) + "/" + url.PathEscape(up.state.ID)
+	var err error
+	for attempt := 1; ; attempt++ {
+		var next DebugBundleUpload
+		query := url.Values{"offset": []string{strconv.FormatInt(offset, 10)}}
+		err = up.do(ctx, http.MethodPut, pathWithQuery(path, query), compressed.Bytes(), true, &next)
+		if err == nil {
+			up.state = next
+			return next, nil
+		}
+		if attempt == bundleChunkAttempts {
+			return up.state, err
+		}
+		select {
+		case <-ctx.Done():
+			return up.state, ctx.Err()
+		case <-time.After(time.Duration(attempt) * bundleRetryBackoff):
+		}
+		// The chunk may have been received even though the request
+		// failed: continue from wherever the server is.
+		var current DebugBundleUpload
+		if err := up.do(ctx, http.MethodGet, path, nil, false, &current); err == nil && current.Offset != offset {
+			up.state = current
+			return current, nil
+		}
+	}
+}
+
+func (up *bundleUploader) do(
+	ctx context.Context, method, u string, body []byte, chunk bool, into interface{},
+) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	switch {
+	case chunk:
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-Encoding", "gzip")
+	case body != nil:
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := up.auth(req, body); err != nil {
+		return fmt.Errorf("unable to sign request %s %s: %w", method, u, err)
+	}
+
+	res, err := up.cl.Do(req)
+	if err != nil {
+		return classifyTransportError(ctx, err)
+	}
+	if res.StatusCode/100 != 2 {
+		defer res.Body.Close()
+		resBody, _ := io.ReadAll(res.Body)
+		return &HTTPResponseError{Response: res, Body: resBody, Method: method, URL: u}
+	}
+	return maybeUnmarshalRespInto(method, u, res, into)
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// bundleIntake is a bundle intake endpoint that keeps partial uploads.
+type bundleIntake struct {
+	t        *testing.T
+	mu       sync.Mutex
+	received []byte
+	size     int64
+	// failAt makes the chunk at this offset fail once, after it has been
+	// stored, like a connection dropped before the response.
+	failAt int64
+	failed bool
+	auth   string
+}
+
+func (b *bundleIntake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.auth = r.Header.Get("Authorization")
+	reply := func() {
+		json.NewEncoder(w).Encode(DebugBundleUpload{
+			ID: "up-1", Name: "bundle.zip", Size: b.size, Offset: int64(len(b.received)),
+		})
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/debug/bundle/uploads":
+		var req struct {
+			Size   int64  `json:"size"`
+			SHA256 string `json:"sha256"`
+		}
+		require.NoError(b.t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(b.t, req.SHA256, 64)
+		b.size = req.Size
+		reply()
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/debug/bundle/uploads/up-1":
+		reply()
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/debug/bundle/uploads/up-1":
+		require.Equal(b.t, "gzip", r.Header.Get("Content-Encoding"))
+		offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+		require.NoError(b.t, err)
+		require.Equal(b.t, int64(len(b.received)), offset)
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(b.t, err)
+		chunk, err := io.ReadAll(zr)
+		require.NoError(b.t, err)
+		b.received = append(b.received, chunk...)
+		if offset == b.failAt && !b.failed {
+			b.failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		reply()
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUploadDebugBundle(t *testing.T) {
+	archive := bytes.Repeat([]byte("redpanda debug bundle "), 100)
+	size := int64(len(archive))
+
+	for _, test := range []struct {
+		name     string
+		resumeAt int64
+		failAt   int64
+		viaURL   bool
+	}{
+		{name: "new upload", failAt: -1},
+		{name: "resumed upload", resumeAt: 1000, failAt: -1},
+		{name: "failed chunk", failAt: 512},
+		{name: "support URL", failAt: -1, viaURL: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			intake := &bundleIntake{t: t, failAt: test.failAt, received: append([]byte(nil), archive[:test.resumeAt]...)}
+			ts := httptest.NewServer(intake)
+			defer ts.Close()
+
+			opts := DebugBundleUploadOptions{ChunkSize: 512}
+			var progress []int64
+			opts.Progress = func(uploaded, total int64) {
+				require.Equal(t, size, total)
+				progress = append(progress, uploaded)
+			}
+			urls := []string{ts.URL}
+			if test.viaURL {
+				urls = []string{"http://127.0.0.1:1"}
+				opts.URL, opts.Token = ts.URL, "secret"
+			}
+			cl, err := NewAdminAPI(urls, BasicCredentials{Username: "admin", Password: "pw"}, nil)
+			require.NoError(t, err)
+
+			state, err := cl.UploadDebugBundle(context.Background(), "bundle.zip", bytes.NewReader(archive), size, opts)
+			require.NoError(t, err)
+			require.Equal(t, size, state.Offset)
+			require.Equal(t, archive, intake.received)
+			require.Equal(t, test.resumeAt, progress[0])
+			require.Equal(t, size, progress[len(progress)-1])
+			if test.viaURL {
+				require.Equal(t, "Bearer secret", intake.auth)
+			} else {
+				require.Contains(t, intake.auth, "Basic ")
+			}
+		})
+	}
+}
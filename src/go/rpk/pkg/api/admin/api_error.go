@@ -0,0 +1,157 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// The categories of the errors of requests, which can be checked with
+// errors.Is to tell an unreachable cluster from an invalid request:
+//
+//	if errors.Is(err, admin.ErrConnection) {
+//		...
+//	}
+//
+// An error is in at most one category. Errors that are in none, such as a
+// canceled context or an undecodable response, are neither the fault of the
+// connection nor of the request.
+var (
+	// ErrConnection is a request that did not get a response because the
+	// broker could not be reached: the address did not resolve, the
+	// connection was refused or closed, or the TLS handshake failed.
+	ErrConnection = errors.New("admin API connection error")
+	// ErrTimeout is a request that did not get a response in time, because
+	// of the deadline of its context or a timeout of the connection.
+	ErrTimeout = errors.New("admin API timeout")
+	// ErrServer is a request that was answered with a 5xx status: the
+	// broker failed or is not ready, and the request may succeed later.
+	ErrServer = errors.New("admin API server error")
+	// ErrClient is a request that was answered with a 4xx status, e.g. a
+	// 404 for something that does not exist or a 400 for an invalid
+	// request, which fails again if it is sent again as is.
+	ErrClient = errors.New("admin API client error")
+)
+
+// transportError is the error of a request that got no response, with the
+// category of the error.
+type transportError struct {
+	err      error
+	category error
+}
+
+func (te *transportError) Error() string        { return te.err.Error() }
+func (te *transportError) Unwrap() error        { return te.err }
+func (te *transportError) Is(target error) bool { return target == te.category }
+
+// classifyTransportError returns err, the error of a request sent with ctx
+// that got no response, in the ErrTimeout or ErrConnection category. The
+// context is checked as well as the error, since the retry client does not
+// always wrap the error of the context.
+func classifyTransportError(ctx context.Context, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
+	var ne net.Error
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil || errors.As(err, &ne) && ne.Timeout() {
+		return &transportError{err, ErrTimeout}
+	}
+	return &transportError{err, ErrConnection}
+}
+
+// Is returns whether target is ErrServer for 5xx responses or ErrClient for
+// 4xx responses.
+func (he HTTPResponseError) Is(target error) bool {
+	if he.Response == nil {
+		return false
+	}
+	switch target {
+	case ErrServer:
+		return he.Response.StatusCode >= 500
+	case ErrClient:
+		return he.Response.StatusCode >= 400 && he.Response.StatusCode < 500
+	}
+	return false
+}
+
+// AdminError is a request that failed with the standard JSON error body of
+// the admin server, e.g.
+//
+//	{"message": "Unexpected node id 5", "code": 404}
+//
+// It is wrapped by the HTTPResponseError of the request, and can be
+// extracted with errors.As:
+//
+//	var ae *admin.AdminError
+//	if errors.As(err, &ae) && ae.Code == http.StatusNotFound {
+//		...
+//	}
+type AdminError struct {
+	Method string
+	URL    string
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Message is the reason of the failure reported by the server.
+	Message string
+	// Code is the error code reported by the server, which is the HTTP
+	// status code for most errors.
+	Code int
+}
+
+// Error returns the one line message of the server and the error code, e.g.
+// "Unexpected node id 5 (404 Not Found)", which is suitable to show to users.
+// The request is in Method and URL.
+func (ae *AdminError) Error() string {
+	code := ae.Code
+	if code == 0 {
+		code = ae.StatusCode
+	}
+	if text := http.StatusText(code); text != "" {
+		return fmt.Sprintf("%s (%d %s)", ae.Message, code, text)
+	}
+	return fmt.Sprintf("%s (%d)", ae.Message, code)
+}
+
+// decodeAdminError decodes the body of the response into an AdminError, and
+// returns nil if it is not the standard error body, e.g. for the property
+// errors of cluster configuration writes.
+func (he HTTPResponseError) decodeAdminError() *AdminError {
+	var body struct {
+		Message *string `json:"message"`
+		Code    int     `json:"code"`
+	}
+	if err := json.Unmarshal(he.Body, &body); err != nil || body.Message == nil {
+		return nil
+	}
+	var status int
+	if he.Response != nil {
+		status = he.Response.StatusCode
+	}
+	// Messages span a single line, some are wrapped in quotes by the
+	// server.
+	msg := strings.TrimSpace(*body.Message)
+	if len(msg) >= 2 && msg[0] == '"' && msg[len(msg)-1] == '"' {
+		msg = msg[1 : len(msg)-1]
+	}
+	msg = strings.Join(strings.Fields(msg), " ")
+	return &AdminError{
+		Method:     he.Method,
+		URL:        he.URL,
+		StatusCode: status,
+		Message:    msg,
+		Code:       body.Code,
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminError(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		status int
+		body   string
+		exp    *AdminError
+		expErr string
+	}{
+		{
+			name:   "standard error body",
+			status: http.StatusNotFound,
+			body:   `{"message": "Unexpected node id 5", "code": 404}`,
+			exp:    &AdminError{StatusCode: 404, Message: "Unexpected node id 5", Code: 404},
+			expErr: "Unexpected node id 5 (404 Not Found)",
+		},
+		{
+			name:   "quoted multi line message",
+			status: http.StatusBadRequest,
+			body:   `{"message": "\"Node 2 is already\n in maintenance mode\"", "code": 400}`,
+			exp:    &AdminError{StatusCode: 400, Message: "Node 2 is already in maintenance mode", Code: 400},
+			expErr: "Node 2 is already in maintenance mode (400 Bad Request)",
+		},
+		{
+			name:   "code differs from status",
+			status: http.StatusServiceUnavailable,
+			body:   `{"message": "Not leader", "code": 599}`,
+			exp:    &AdminError{StatusCode: 503, Message: "Not leader", Code: 599},
+			expErr: "Not leader (599)",
+		},
+		{
+			name:   "property errors",
+			status: http.StatusBadRequest,
+			body:   `{"log_retention_ms": "expected an integer"}`,
+		},
+		{
+			name:   "not json",
+			status: http.StatusInternalServerError,
+			body:   `oops`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer ts.Close()
+
+			cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+			require.NoError(t, err)
+			err = cl.sendAny(context.Background(), http.MethodPut, "/v1/test", nil, nil)
+
+			var he *HTTPResponseError
+			require.True(t, errors.As(err, &he), "got %v", err)
+			require.Equal(t, test.status, he.Response.StatusCode)
+
+			var ae *AdminError
+			if test.exp == nil {
+				require.False(t, errors.As(err, &ae), "got %v", ae)
+				require.Equal(t, test.body, string(he.Body))
+				return
+			}
+			require.True(t, errors.As(err, &ae), "got %v", err)
+			test.exp.Method = http.MethodPut
+			test.exp.URL = ts.URL + "/v1/test"
+			require.Equal(t, test.exp, ae)
+			require.Equal(t, test.expErr, ae.Error())
+			require.Equal(t, "request PUT "+ts.URL+"/v1/test failed: "+test.expErr, err.Error())
+			require.False(t, strings.Contains(err.Error(), "\n"))
+		})
+	}
+}
+
+func TestErrorCategories(t *testing.T) {
+	categories := []error{ErrConnection, ErrTimeout, ErrServer, ErrClient}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/v1/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/v1/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}))
+	defer ts.Close()
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	for _, test := range []struct {
+		name     string
+		url      string
+		path     string
+		timeout  time.Duration
+		cancel   bool
+		expected error
+	}{
+		{name: "client error", url: ts.URL, path: "/v1/missing", expected: ErrClient},
+		{name: "server error", url: ts.URL, path: "/v1/unavailable", expected: ErrServer},
+		{name: "timeout", url: ts.URL, path: "/v1/slow", timeout: 50 * time.Millisecond, expected: ErrTimeout},
+		{name: "connection refused", url: closed.URL, path: "/v1/missing", expected: ErrConnection},
+		{name: "untrusted certificate", url: "https://" + tlsServer.Listener.Addr().String(), path: "/v1/missing", expected: ErrConnection},
+		{name: "canceled", url: ts.URL, path: "/v1/slow", cancel: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cl, err := NewAdminAPI([]string{test.url}, BasicCredentials{}, nil)
+			require.NoError(t, err)
+			cl.SetBackoffPolicy(BackoffPolicy{Base: time.Millisecond, MaxAttempts: 1})
+
+			ctx := context.Background()
+			if test.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.timeout)
+				defer cancel()
+			}
+			if test.cancel {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+			err = cl.sendAny(ctx, http.MethodGet, test.path, nil, nil)
+			require.Error(t, err)
+			for _, category := range categories {
+				require.Equal(t, category == test.expected, errors.Is(err, category), "errors.Is(%v, %v)", err, category)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// ClusterEventType is the kind of a ClusterEvent.
+type ClusterEventType string
+
+const (
+	// ClusterEventLeaderChanged is sent when the controller leader changes.
+	// NodeID is the new leader, or -1 if there is no leader.
+	ClusterEventLeaderChanged ClusterEventType = "leader_changed"
+	// ClusterEventNodeUp is sent when a node joins the cluster or comes
+	// back up.
+	ClusterEventNodeUp ClusterEventType = "node_up"
+	// ClusterEventNodeDown is sent when a node of the cluster goes down.
+	ClusterEventNodeDown ClusterEventType = "node_down"
+	// ClusterEventNodeRemoved is sent when a node is no longer part of the
+	// cluster, e.g. after it was decommissioned.
+	ClusterEventNodeRemoved ClusterEventType = "node_removed"
+	// ClusterEventConfigVersion is sent when a node applies a new version
+	// of the cluster configuration.
+	ClusterEventConfigVersion ClusterEventType = "config_version_changed"
+	// ClusterEventError is sent when the cluster state could not be
+	// retrieved. The subscription keeps retrying.
+	ClusterEventError ClusterEventType = "error"
+	// ClusterEventObserved is sent after the events of each observation of
+	// the cluster state, if SubscribeOptions.Observed is set. Health is the
+	// health overview the events were derived from.
+	ClusterEventObserved ClusterEventType = "observed"
+)
+
+// ClusterEvent is a change in the state of the cluster.
+type ClusterEvent struct {
+	Type ClusterEventType `json:"type"`
+	Time time.Time        `json:"time"`
+	// NodeID is the node the event is about.
+	NodeID int `json:"node_id"`
+	// ConfigVersion is the new cluster config version of the node, for
+	// ClusterEventConfigVersion events.
+	ConfigVersion int64 `json:"config_version,omitempty"`
+	// Err is the error that occurred, for ClusterEventError events.
+	Err error `json:"-"`
+	// Health is the observed health overview, for ClusterEventObserved
+	// events.
+	Health *ClusterHealthOverview `json:"-"`
+}
+
+// SubscribeOptions configures a subscription to cluster events.
+type SubscribeOptions struct {
+	// Interval is how often the cluster state is observed. Defaults to 2s.
+	Interval time.Duration
+	// MaxBackoff caps the delay between retries while the admin API cannot
+	// be reached. Defaults to 30s.
+	MaxBackoff time.Duration
+	// Observed sends a ClusterEventObserved event after each observation,
+	// so that subscribers can display the cluster state without polling
+	// the admin API themselves.
+	Observed bool
+}
+
+// clusterState is the part of the cluster state that events are derived
+// from.
+type clusterState struct {
+	leader         int
+	nodes          map[int]bool // node ID => up
+	configVersions map[int]int64
+
+	health ClusterHealthOverview // not diffed
+}
+
+// Subscribe returns a stream of cluster events: leadership changes, nodes
+// going up or down and cluster config version changes. The first events
+// describe the current state of the cluster.
+//
+// The admin API does not expose a streaming endpoint for cluster events, so
+// events are derived by observing the cluster health overview and the cluster
+// config status every Interval. Failures to reach the admin API are sent as
+// ClusterEventError events and retried with exponential backoff; once the
+// admin API is reachable again, events resume from the last observed state.
+//
+// The returned channel is closed when the context is canceled.
+func (a *AdminAPI) Subscribe(
+	ctx context.Context, opts SubscribeOptions,
+) <-chan ClusterEvent {
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+
+	events := make(chan ClusterEvent, 16)
+	go func() {
+		defer close(events)
+
+		send := func(e ClusterEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var (
+			prev          *clusterState
+			backoff       = opts.Interval
+			configMissing bool // the cluster does not support central config
+		)
+		for {
+			wait := opts.Interval
+			cur, err := a.observeClusterState(ctx, &configMissing)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if !send(ClusterEvent{Type: ClusterEventError, Time: time.Now(), NodeID: -1, Err: err}) {
+					return
+				}
+				wait = backoff
+				if backoff *= 2; backoff > opts.MaxBackoff {
+					backoff = opts.MaxBackoff
+				}
+			default:
+				backoff = opts.Interval
+				now := time.Now()
+				for _, e := range diffClusterState(prev, cur, now) {
+					if !send(e) {
+						return
+					}
+				}
+				if opts.Observed {
+					health := cur.health
+					if !send(ClusterEvent{Type: ClusterEventObserved, Time: now, NodeID: -1, Health: &health}) {
+						return
+					}
+				}
+				prev = &cur
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+	return events
+}
+
+func (a *AdminAPI) observeClusterState(
+	ctx context.Context, configMissing *bool,
+) (clusterState, error) {
+	health, err := a.GetHealthOverview(ctx)
+	if err != nil {
+		return clusterState{}, err
+	}
+	s := clusterState{
+		leader:         health.ControllerID,
+		nodes:          make(map[int]bool, len(health.AllNodes)),
+		configVersions: make(map[int]int64),
+		health:         health,
+	}
+	for _, id := range health.AllNodes {
+		s.nodes[id] = true
+	}
+	for _, id := range health.NodesDown {
+		s.nodes[id] = false
+	}
+
+	if *configMissing {
+		return s, nil
+	}
+	status, err := a.ClusterConfigStatus(ctx, false)
+	if err != nil {
+		if IsNotFound(err) {
+			*configMissing = true
+			return s, nil
+		}
+		return clusterState{}, err
+	}
+	for _, st := range status {
+		s.configVersions[int(st.NodeID)] = st.ConfigVersion
+	}
+	return s, nil
+}
+
+// diffClusterState returns the events that lead from prev to cur, in node
+// order. If prev is nil, the events describe cur from scratch.
+func diffClusterState(prev *clusterState, cur clusterState, now time.Time) []ClusterEvent {
+	if prev == nil {
+		prev = &clusterState{leader: -1}
+	}
+	var events []ClusterEvent
+	if cur.leader != prev.leader {
+		events = append(events, ClusterEvent{Type: ClusterEventLeaderChanged, Time: now, NodeID: cur.leader})
+	}
+
+	ids := make([]int, 0, len(cur.nodes))
+	for id := range cur.nodes {
+		ids = append(ids, id)
+	}
+	for id := range prev.nodes {
+		if _, ok := cur.nodes[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		wasUp, existed := prev.nodes[id]
+		isUp, exists := cur.nodes[id]
+		switch {
+		case !exists:
+			events = append(events, ClusterEvent{Type: ClusterEventNodeRemoved, Time: now, NodeID: id})
+		case isUp && (!existed || !wasUp):
+			events = append(events, ClusterEvent{Type: ClusterEventNodeUp, Time: now, NodeID: id})
+		case !isUp && (!existed || wasUp):
+			events = append(events, ClusterEvent{Type: ClusterEventNodeDown, Time: now, NodeID: id})
+		}
+	}
+
+	ids = ids[:0]
+	for id := range cur.configVersions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		v := cur.configVersions[id]
+		if old, ok := prev.configVersions[id]; !ok || old != v {
+			events = append(events, ClusterEvent{Type: ClusterEventConfigVersion, Time: now, NodeID: id, ConfigVersion: v})
+		}
+	}
+	return events
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffClusterState(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		name string
+		prev *clusterState
+		cur  clusterState
+		exp  []ClusterEvent
+	}{
+		{
+			name: "initial state",
+			cur: clusterState{
+				leader:         1,
+				nodes:          map[int]bool{0: true, 1: true, 2: false},
+				configVersions: map[int]int64{0: 3, 1: 3},
+			},
+			exp: []ClusterEvent{
+				{Type: ClusterEventLeaderChanged, Time: now, NodeID: 1},
+				{Type: ClusterEventNodeUp, Time: now, NodeID: 0},
+				{Type: ClusterEventNodeUp, Time: now, NodeID: 1},
+				{Type: ClusterEventNodeDown, Time: now, NodeID: 2},
+				{Type: ClusterEventConfigVersion, Time: now, NodeID: 0, ConfigVersion: 3},
+				{Type: ClusterEventConfigVersion, Time: now, NodeID: 1, ConfigVersion: 3},
+			},
+		},
+		{
+			name: "no changes",
+			prev: &clusterState{
+				leader:         1,
+				nodes:          map[int]bool{0: true, 1: true},
+				configVersions: map[int]int64{0: 3, 1: 3},
+			},
+			cur: clusterState{
+				leader:         1,
+				nodes:          map[int]bool{0: true, 1: true},
+				configVersions: map[int]int64{0: 3, 1: 3},
+			},
+		},
+		{
+			name: "leader goes down, node removed and config updated",
+			prev: &clusterState{
+				leader:         1,
+				nodes:          map[int]bool{0: true, 1: true, 2: true},
+				configVersions: map[int]int64{0: 3, 1: 3, 2: 3},
+			},
+			cur: clusterState{
+				leader:         0,
+				nodes:          map[int]bool{0: true, 1: false},
+				configVersions: map[int]int64{0: 4, 1: 3},
+			},
+			exp: []ClusterEvent{
+				{Type: ClusterEventLeaderChanged, Time: now, NodeID: 0},
+				{Type: ClusterEventNodeDown, Time: now, NodeID: 1},
+				{Type: ClusterEventNodeRemoved, Time: now, NodeID: 2},
+				{Type: ClusterEventConfigVersion, Time: now, NodeID: 0, ConfigVersion: 4},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := diffClusterState(test.prev, test.cur, now)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+func TestSubscribeObserved(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/cluster/health_overview":
+			fmt.Fprint(w, `{"is_healthy":true,"controller_id":1,"all_nodes":[0,1],"nodes_down":[]}`)
+		case "/v1/cluster_config/status":
+			fmt.Fprint(w, `[{"node_id":0,"config_version":3},{"node_id":1,"config_version":3}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var types []ClusterEventType
+	for e := range cl.Subscribe(ctx, SubscribeOptions{Interval: time.Hour, Observed: true}) {
+		types = append(types, e.Type)
+		if e.Type == ClusterEventObserved {
+			require.Equal(t, ClusterHealthOverview{IsHealthy: true, ControllerID: 1, AllNodes: []int{0, 1}, NodesDown: []int{}}, *e.Health)
+			break
+		}
+	}
+	// The observed event follows the events of the observation.
+	require.Equal(t, []ClusterEventType{
+		ClusterEventLeaderChanged,
+		ClusterEventNodeUp,
+		ClusterEventNodeUp,
+		ClusterEventConfigVersion,
+		ClusterEventConfigVersion,
+		ClusterEventObserved,
+	}, types)
+}
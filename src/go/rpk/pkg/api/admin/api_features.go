@@ -11,7 +11,6 @@ package admin
 
 import (
 	"context"
-	"net/http"
 )
 
 // FeatureState enumerates the possible states of a feature.
@@ -48,24 +47,22 @@ type LicenseProperties struct {
 	Organization string `json:"org"`
 	Type         string `json:"type"`
 	Expires      int64  `json:"expires"`
+	// Checksum is the hex encoded sha256 of the loaded license. It is only
+	// reported by newer Redpanda versions.
+	Checksum string `json:"sha256,omitempty"`
 }
 
 // GetFeatures returns information about the available features.
 func (a *AdminAPI) GetFeatures(ctx context.Context) (FeaturesResponse, error) {
 	var features FeaturesResponse
-	return features, a.sendAny(
-		ctx,
-		http.MethodGet,
-		"/v1/features",
-		nil,
-		&features)
+	return features, a.GetAny(ctx, APIv1.Path("/features"), nil, &features)
 }
 
 func (a *AdminAPI) GetLicenseInfo(ctx context.Context) (License, error) {
 	var license License
-	return license, a.sendAny(ctx, http.MethodGet, "/v1/features/license", nil, &license)
+	return license, a.GetAny(ctx, APIv1.Path("/features/license"), nil, &license)
 }
 
 func (a *AdminAPI) SetLicense(ctx context.Context, license interface{}) error {
-	return a.sendToLeader(ctx, http.MethodPut, "/v1/features/license", license, nil)
+	return a.PutLeader(ctx, APIv1.Path("/features/license"), nil, license, nil)
 }
@@ -10,12 +10,102 @@
 package admin
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 func (a *AdminAPI) PrometheusMetrics(ctx context.Context) ([]byte, error) {
 	var res []byte
-	err := a.sendOne(ctx, http.MethodGet, "/metrics", nil, &res, false)
+	err := a.GetOne(ctx, "/metrics", nil, &res)
 	return res, err
 }
+
+// CloudStorageStats are the cloud storage transfer counters of the brokers,
+// summed over all brokers, since they started.
+type CloudStorageStats struct {
+	SuccessfulUploads   int64
+	FailedUploads       int64
+	SuccessfulDownloads int64
+	FailedDownloads     int64
+}
+
+// cloudStorageMetrics maps the metrics of the archival and remote read
+// services to the counter of CloudStorageStats they add to.
+var cloudStorageMetrics = map[string]func(*CloudStorageStats) *int64{
+	"vectorized_cloud_storage_successful_uploads":          func(s *CloudStorageStats) *int64 { return &s.SuccessfulUploads },
+	"vectorized_cloud_storage_successful_manifest_uploads": func(s *CloudStorageStats) *int64 { return &s.SuccessfulUploads },
+	"vectorized_cloud_storage_failed_uploads":              func(s *CloudStorageStats) *int64 { return &s.FailedUploads },
+	"vectorized_cloud_storage_failed_manifest_uploads":     func(s *CloudStorageStats) *int64 { return &s.FailedUploads },
+	"vectorized_cloud_storage_successful_downloads":        func(s *CloudStorageStats) *int64 { return &s.SuccessfulDownloads },
+	"vectorized_cloud_storage_failed_downloads":            func(s *CloudStorageStats) *int64 { return &s.FailedDownloads },
+}
+
+// CloudStorageStats scrapes the metrics of every broker of the client and
+// sums their cloud storage transfer counters. Brokers that cannot be scraped
+// are skipped; an error is only returned if no broker could be scraped.
+func (a *AdminAPI) CloudStorageStats(ctx context.Context) (CloudStorageStats, error) {
+	var (
+		mu      sync.Mutex
+		stats   CloudStorageStats
+		scraped bool
+	)
+	err := a.eachBroker(func(aa *AdminAPI) error {
+		metrics, err := aa.PrometheusMetrics(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		addCloudStorageStats(&stats, metrics)
+		scraped = true
+		return nil
+	})
+	if !scraped {
+		return CloudStorageStats{}, err
+	}
+	return stats, nil
+}
+
+// addCloudStorageStats adds the cloud storage counters of metrics, in the
+// Prometheus text format, to stats.
+func addCloudStorageStats(stats *CloudStorageStats, metrics []byte) {
+	s := bufio.NewScanner(bytes.NewReader(metrics))
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		// A sample is the metric name, optional labels in braces, and
+		// the value: name{label="value",...} 42
+		nameEnd := strings.IndexAny(line, "{ ")
+		if nameEnd < 0 {
+			continue
+		}
+		counter, ok := cloudStorageMetrics[line[:nameEnd]]
+		if !ok {
+			continue
+		}
+		rest := line[nameEnd:]
+		if strings.HasPrefix(rest, "{") {
+			end := strings.LastIndexByte(rest, '}')
+			if end < 0 {
+				continue
+			}
+			rest = rest[end+1:]
+		}
+		// The value may be followed by a timestamp.
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		*counter(stats) += int64(v)
+	}
+}
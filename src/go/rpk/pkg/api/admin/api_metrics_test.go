@@ -0,0 +1,55 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudStorageStats(t *testing.T) {
+	metrics := `# HELP vectorized_cloud_storage_successful_uploads Successful uploads
+# TYPE vectorized_cloud_storage_successful_uploads counter
+vectorized_cloud_storage_successful_uploads{shard="0"} 3
+vectorized_cloud_storage_successful_uploads{shard="1"} 2.000000
+vectorized_cloud_storage_failed_manifest_uploads{shard="0",namespace="kafka"} 1 1660000000000
+vectorized_cloud_storage_successful_downloads 4
+vectorized_cloud_storage_failed_uploads_total{shard="0"} 100
+vectorized_storage_log_written_bytes{shard="0"} 100
+`
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/metrics", r.URL.Path)
+		w.Write([]byte(metrics))
+	}))
+	defer good.Close()
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	cl, err := NewAdminAPI([]string{good.URL, good.URL, bad.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	stats, err := cl.CloudStorageStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, CloudStorageStats{
+		SuccessfulUploads:   10,
+		FailedUploads:       2,
+		SuccessfulDownloads: 8,
+	}, stats)
+
+	cl, err = NewAdminAPI([]string{bad.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	_, err = cl.CloudStorageStats(context.Background())
+	require.Error(t, err)
+}
@@ -11,12 +11,51 @@ package admin
 
 import (
 	"context"
-	"net/http"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 )
 
+// NodeConfig is the node configuration of a broker, as returned by the
+// /v1/node_config endpoint.
 type NodeConfig struct {
-	NodeID int `json:"node_id"`
-	// TODO: add the rest of the fields
+	NodeID                 int     `json:"node_id"`
+	Rack                   *string `json:"rack,omitempty"`
+	DataDirectory          string  `json:"data_directory"`
+	EmptySeedStartsCluster *bool   `json:"empty_seed_starts_cluster,omitempty"`
+
+	RPCServer          config.SocketAddress             `json:"rpc_server"`
+	AdvertisedRPCAPI   *config.SocketAddress            `json:"advertised_rpc_api,omitempty"`
+	KafkaAPI           []config.NamedAuthNSocketAddress `json:"kafka_api"`
+	AdvertisedKafkaAPI []config.NamedSocketAddress      `json:"advertised_kafka_api,omitempty"`
+	AdminAPI           []config.NamedSocketAddress      `json:"admin"`
+	SeedServers        []config.SeedServer              `json:"seed_servers"`
+
+	// MembershipStatus is not part of the node configuration; it is only
+	// filled in by GetAllNodeConfigs from the brokers endpoint.
+	MembershipStatus MembershipStatus `json:"-"`
+}
+
+// NodeConfigsError is returned by GetAllNodeConfigs when some brokers could
+// not be queried. Errors is keyed by the admin URL of each failing broker.
+type NodeConfigsError struct {
+	Errors map[string]error
+}
+
+func (e *NodeConfigsError) Error() string {
+	urls := make([]string, 0, len(e.Errors))
+	for url := range e.Errors {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	msgs := make([]string, 0, len(urls))
+	for _, url := range urls {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", url, e.Errors[url]))
+	}
+	return fmt.Sprintf("unable to get the node config of %d broker(s): %s", len(urls), strings.Join(msgs, "; "))
 }
 
 // NodeConfig returns a single node configuration.
@@ -25,5 +64,92 @@ type NodeConfig struct {
 func (a *AdminAPI) GetNodeConfig(ctx context.Context) (NodeConfig, error) {
 	var nodeconfig NodeConfig
 
-	return nodeconfig, a.sendOne(ctx, http.MethodGet, "/v1/node_config", nil, &nodeconfig, false)
+	return nodeconfig, a.GetOne(ctx, APIv1.Path("/node_config"), nil, &nodeconfig)
+}
+
+// GetAllNodeConfigs queries the node configuration of every broker of the
+// client and returns them keyed by node ID, with their membership status.
+//
+// If some brokers fail, the configs of the brokers that answered are still
+// returned, along with a *NodeConfigsError describing the failures. The
+// broker ID to URL mapping of the client is refreshed from the answers.
+func (a *AdminAPI) GetAllNodeConfigs(ctx context.Context) (map[int]NodeConfig, error) {
+	var (
+		mu      sync.Mutex
+		configs = make(map[int]NodeConfig, len(a.urls))
+		errs    = make(map[string]error)
+	)
+	// Failures are collected per broker in errs.
+	_ = a.eachBroker(func(aa *AdminAPI) error {
+		nc, err := aa.GetNodeConfig(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[aa.urls[0]] = err
+			return err
+		}
+		configs[nc.NodeID] = nc
+		a.setBrokerURL(nc.NodeID, aa.urls[0])
+		return nil
+	})
+
+	// The membership status is best effort: the configs are still useful
+	// without it.
+	if len(configs) > 0 {
+		if brokers, err := a.Brokers(ctx); err == nil {
+			for _, b := range brokers {
+				if nc, ok := configs[b.NodeID]; ok {
+					nc.MembershipStatus = b.MembershipStatus
+					configs[b.NodeID] = nc
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return configs, &NodeConfigsError{Errors: errs}
+	}
+	return configs, nil
+}
+
+// NodeConfigWriteResult is the result of a write of node config overrides.
+type NodeConfigWriteResult struct {
+	// Restart is whether some of the written properties only take effect
+	// once the broker restarts.
+	Restart bool `json:"restart"`
+}
+
+// PatchNodeConfig sets and clears node config overrides of the single broker
+// of this client: properties in upsert are overridden with the given value,
+// and the overrides of the properties in remove are cleared, reverting them
+// to the cluster wide value. It's expected to be called from an AdminAPI with
+// a single broker URL, otherwise the method will return an error.
+//
+// Brokers that do not support node config overrides return a 404, which can
+// be checked with IsNotFound.
+func (a *AdminAPI) PatchNodeConfig(
+	ctx context.Context, upsert map[string]interface{}, remove []string,
+) (NodeConfigWriteResult, error) {
+	body := map[string]interface{}{
+		"upsert": upsert,
+		"remove": remove,
+	}
+	var result NodeConfigWriteResult
+	return result, a.PutOne(ctx, APIv1.Path("/node_config"), nil, body, &result)
+}
+
+// PatchBrokerConfig is PatchNodeConfig for the broker with the given node ID,
+// which must be reachable through one of the URLs of the client.
+func (a *AdminAPI) PatchBrokerConfig(
+	ctx context.Context, node int, upsert map[string]interface{}, remove []string,
+) (NodeConfigWriteResult, error) {
+	url, err := a.brokerIDToURL(ctx, node)
+	if err != nil {
+		return NodeConfigWriteResult{}, err
+	}
+	aa, err := a.newAdminForSingleHost(url)
+	if err != nil {
+		return NodeConfigWriteResult{}, err
+	}
+	return aa.PatchNodeConfig(ctx, upsert, remove)
 }
@@ -0,0 +1,108 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllNodeConfigs(t *testing.T) {
+	brokers := `[{"node_id":1,"membership_status":"active"},{"node_id":2,"membership_status":"draining"}]`
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/node_config":
+			w.Write([]byte(`{
+				"node_id": 1,
+				"rack": "a",
+				"data_directory": "/var/lib/redpanda/data",
+				"rpc_server": {"address": "0.0.0.0", "port": 33145},
+				"kafka_api": [{"name": "internal", "address": "0.0.0.0", "port": 9092}],
+				"admin": [{"address": "0.0.0.0", "port": 9644}],
+				"seed_servers": [{"host": {"address": "seed-0", "port": 33145}}]
+			}`))
+		case "/v1/brokers":
+			w.Write([]byte(brokers))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer good.Close()
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/brokers":
+			w.Write([]byte(brokers))
+		case "/v1/node_config":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer bad.Close()
+
+	cl, err := NewAdminAPI([]string{good.URL, bad.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	configs, err := cl.GetAllNodeConfigs(context.Background())
+	var ncErr *NodeConfigsError
+	require.True(t, errors.As(err, &ncErr))
+	require.Len(t, ncErr.Errors, 1)
+	require.Contains(t, ncErr.Errors, bad.URL)
+
+	require.Len(t, configs, 1)
+	nc := configs[1]
+	require.Equal(t, "a", *nc.Rack)
+	require.Equal(t, 33145, nc.RPCServer.Port)
+	require.Equal(t, "internal", nc.KafkaAPI[0].Name)
+	require.Equal(t, "seed-0", nc.SeedServers[0].Host.Address)
+	require.Equal(t, MembershipStatusActive, nc.MembershipStatus)
+}
+
+func TestPatchBrokerConfig(t *testing.T) {
+	broker := func(id int, patched *map[string]interface{}) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/v1/node_config" && r.Method == http.MethodGet:
+				json.NewEncoder(w).Encode(map[string]int{"node_id": id})
+			case r.URL.Path == "/v1/node_config" && r.Method == http.MethodPut:
+				require.NoError(t, json.NewDecoder(r.Body).Decode(patched))
+				w.Write([]byte(`{"restart":true}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	var patched0, patched1 map[string]interface{}
+	b0 := broker(0, &patched0)
+	defer b0.Close()
+	b1 := broker(1, &patched1)
+	defer b1.Close()
+
+	cl, err := NewAdminAPI([]string{b0.URL, b1.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	result, err := cl.PatchBrokerConfig(context.Background(), 1, map[string]interface{}{"storage_read_buffer_size": "262144"}, []string{"fetch_max_bytes"})
+	require.NoError(t, err)
+	require.True(t, result.Restart)
+	require.Nil(t, patched0)
+	require.Equal(t, map[string]interface{}{
+		"upsert": map[string]interface{}{"storage_read_buffer_size": "262144"},
+		"remove": []interface{}{"fetch_max_bytes"},
+	}, patched1)
+
+	_, err = cl.PatchBrokerConfig(context.Background(), 2, nil, []string{"fetch_max_bytes"})
+	require.Error(t, err)
+}
@@ -11,8 +11,8 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
 )
 
 // Replica contains the information of a partition replica.
@@ -28,19 +28,144 @@ type Partition struct {
 	PartitionID int       `json:"partition_id"`
 	Status      string    `json:"status"`
 	LeaderID    int       `json:"leader_id"`
+	LeaderEpoch int64     `json:"leader_epoch,omitempty"` // 0 if the broker does not report it
 	RaftGroupID int       `json:"raft_group_id"`
 	Replicas    []Replica `json:"replicas"`
 }
 
+// StaleLeadershipError is returned when a broker reports a leader epoch for a
+// partition that is older than one the client already observed: the broker
+// has not yet learned that leadership moved.
+type StaleLeadershipError struct {
+	Namespace string
+	Topic     string
+	Partition int
+	Epoch     int64 // the epoch reported by the broker
+	Observed  int64 // the highest epoch observed by the client
+}
+
+func (e *StaleLeadershipError) Error() string {
+	return fmt.Sprintf("stale leadership for partition %s/%s/%d: got leader epoch %d, already observed %d",
+		e.Namespace, e.Topic, e.Partition, e.Epoch, e.Observed)
+}
+
+// IsStaleLeadership returns whether the error is a StaleLeadershipError.
+func IsStaleLeadership(err error) bool {
+	var se *StaleLeadershipError
+	return errors.As(err, &se)
+}
+
+type partitionKey struct {
+	namespace string
+	topic     string
+	partition int
+}
+
+// observeLeaderEpoch records the leader epoch of a partition, and returns a
+// StaleLeadershipError if it is older than the highest one observed so far.
+// Epochs of 0 are unknown and always accepted.
+func (a *AdminAPI) observeLeaderEpoch(namespace, topic string, partition int, epoch int64) error {
+	if epoch <= 0 {
+		return nil
+	}
+	k := partitionKey{namespace, topic, partition}
+	a.leaderEpochsMutex.Lock()
+	defer a.leaderEpochsMutex.Unlock()
+	if observed := a.leaderEpochs[k]; epoch < observed {
+		return &StaleLeadershipError{namespace, topic, partition, epoch, observed}
+	}
+	if a.leaderEpochs == nil {
+		a.leaderEpochs = make(map[partitionKey]int64)
+	}
+	a.leaderEpochs[k] = epoch
+	return nil
+}
+
 // GetPartition returns detailed partition information.
+//
+// If the broker that answers reports an older leader epoch than one this
+// client already observed for the partition, the broker ID to URL mapping is
+// refreshed and the partition is queried again, in the hope of reaching a
+// broker with up to date leadership. If every attempt is stale, a
+// StaleLeadershipError is returned along with the stale partition.
 func (a *AdminAPI) GetPartition(
 	ctx context.Context, namespace, topic string, partition int,
 ) (Partition, error) {
-	var pa Partition
-	return pa, a.sendAny(
-		ctx,
-		http.MethodGet,
-		fmt.Sprintf("/v1/partitions/%s/%s/%d", namespace, topic, partition),
-		nil,
-		&pa)
+	path := APIv1.Path(fmt.Sprintf("/partitions/%s/%s/%d", namespace, topic, partition))
+	var (
+		pa  Partition
+		err error
+	)
+	for attempt := 0; attempt < a.staleLeadershipAttempts(); attempt++ {
+		if attempt > 0 {
+			a.mapBrokerIDsToURLs(ctx)
+		}
+		pa = Partition{}
+		if err = a.GetAny(ctx, path, nil, &pa); err != nil {
+			return pa, err
+		}
+		if err = a.observeLeaderEpoch(namespace, topic, partition, pa.LeaderEpoch); err == nil {
+			return pa, nil
+		}
+	}
+	return pa, err
+}
+
+// staleLeadershipAttempts is how many times partitions are queried before
+// giving up on brokers reporting stale leadership. With a single broker,
+// querying it again would return the same answer.
+func (a *AdminAPI) staleLeadershipAttempts() int {
+	if len(a.urls) == 1 {
+		return 1
+	}
+	return 3
+}
+
+// ClusterPartition is a partition of the cluster as known by the controller.
+type ClusterPartition struct {
+	Namespace   string    `json:"ns"`
+	Topic       string    `json:"topic"`
+	PartitionID int       `json:"partition_id"`
+	LeaderID    *int      `json:"leader_id,omitempty"`    // nil if the partition has no leader
+	LeaderEpoch int64     `json:"leader_epoch,omitempty"` // 0 if the broker does not report it
+	Replicas    []Replica `json:"replicas"`
+	Disabled    bool      `json:"disabled,omitempty"`
+}
+
+// AllClusterPartitions returns every partition of the cluster with its
+// replicas and leader, in a single request.
+//
+// As with GetPartition, responses with stale leadership for any partition
+// are retried after refreshing the broker ID to URL mapping.
+func (a *AdminAPI) AllClusterPartitions(ctx context.Context) ([]ClusterPartition, error) {
+	var (
+		partitions []ClusterPartition
+		err        error
+	)
+	for attempt := 0; attempt < a.staleLeadershipAttempts(); attempt++ {
+		if attempt > 0 {
+			a.mapBrokerIDsToURLs(ctx)
+		}
+		partitions = nil
+		if err = a.GetAny(ctx, APIv1.Path("/cluster/partitions"), nil, &partitions); err != nil {
+			return partitions, err
+		}
+		if err = a.observeClusterLeaderEpochs(partitions); err == nil {
+			return partitions, nil
+		}
+	}
+	return partitions, err
+}
+
+// observeClusterLeaderEpochs records the leader epochs of the partitions,
+// and returns the first StaleLeadershipError.
+func (a *AdminAPI) observeClusterLeaderEpochs(partitions []ClusterPartition) error {
+	var stale error
+	for _, p := range partitions {
+		err := a.observeLeaderEpoch(p.Namespace, p.Topic, p.PartitionID, p.LeaderEpoch)
+		if err != nil && stale == nil {
+			stale = err
+		}
+	}
+	return stale
 }
@@ -0,0 +1,122 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// PartitionState is the state of a partition as streamed by ScanPartitions
+// and ScanTopicPartitions.
+type PartitionState = ClusterPartition
+
+// DefaultScanConcurrency is the number of topics ScanTopicPartitions scans
+// concurrently if no concurrency is given.
+const DefaultScanConcurrency = 8
+
+// streamInto is an into argument of the send functions that reads the body
+// of the response itself, rather than having it read in memory and decoded.
+type streamInto func(r io.Reader) error
+
+// ScanPartitions calls fn for every partition of the cluster, as they are
+// decoded from a single response of the admin API, so that the partitions are
+// never all held in memory. Scanning stops at the first error returned by
+// fn, which is returned.
+//
+// Unlike AllClusterPartitions, the leader epochs of the partitions are not
+// tracked, and responses with stale leadership are not retried.
+func (a *AdminAPI) ScanPartitions(ctx context.Context, fn func(PartitionState) error) error {
+	return a.GetAny(ctx, APIv1.Path("/cluster/partitions"), nil, streamPartitions(fn))
+}
+
+// ScanTopicPartitions calls fn for every partition of the given topics of the
+// namespace, requesting the partitions of up to concurrency topics at once.
+// The calls to fn are serialized, the partitions of a topic are passed in
+// order, but the topics are interleaved. Scanning stops at the first error,
+// either returned by fn or from a request, which is returned.
+func (a *AdminAPI) ScanTopicPartitions(
+	ctx context.Context,
+	namespace string,
+	topics []string,
+	concurrency int,
+	fn func(PartitionState) error,
+) error {
+	if concurrency <= 0 {
+		concurrency = DefaultScanConcurrency
+	}
+	grp, grpCtx := errgroup.WithContext(ctx)
+	var mu sync.Mutex
+	serialized := func(p PartitionState) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := grpCtx.Err(); err != nil {
+			return err // another topic failed
+		}
+		return fn(p)
+	}
+
+	topicsCh := make(chan string)
+	for i := 0; i < concurrency && i < len(topics); i++ {
+		grp.Go(func() error {
+			for topic := range topicsCh {
+				path := APIv1.Path(fmt.Sprintf("/cluster/partitions/%s/%s", url.PathEscape(namespace), url.PathEscape(topic)))
+				if err := a.GetAny(grpCtx, path, nil, streamPartitions(serialized)); err != nil {
+					return fmt.Errorf("unable to scan the partitions of topic %q: %w", topic, err)
+				}
+			}
+			return nil
+		})
+	}
+	grp.Go(func() error {
+		defer close(topicsCh)
+		for _, topic := range topics {
+			select {
+			case topicsCh <- topic:
+			case <-grpCtx.Done():
+				return nil // the error of the failed scan is returned
+			}
+		}
+		return nil
+	})
+	return grp.Wait()
+}
+
+// streamPartitions decodes a JSON array of partitions from the response body,
+// calling fn for each of them.
+func streamPartitions(fn func(PartitionState) error) streamInto {
+	return func(r io.Reader) error {
+		dec := json.NewDecoder(r)
+		if tok, err := dec.Token(); err != nil {
+			return fmt.Errorf("unable to decode partitions: %w", err)
+		} else if tok != json.Delim('[') {
+			return fmt.Errorf("unable to decode partitions: expected an array, got %v", tok)
+		}
+		for dec.More() {
+			var p PartitionState
+			if err := dec.Decode(&p); err != nil {
+				return fmt.Errorf("unable to decode partitions: %w", err)
+			}
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("unable to decode partitions: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanPartitions(t *testing.T) {
+	partitionsJSON := func(topic string, n int) string {
+		var ps []string
+		for i := 0; i < n; i++ {
+			ps = append(ps, fmt.Sprintf(`{"ns": "kafka", "topic": %q, "partition_id": %d, "leader_id": 1, "replicas": [{"node_id": 1}]}`, topic, i))
+		}
+		return "[" + strings.Join(ps, ",") + "]"
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/cluster/partitions":
+			fmt.Fprint(w, partitionsJSON("foo", 5))
+		case "/v1/cluster/partitions/kafka/foo":
+			fmt.Fprint(w, partitionsJSON("foo", 3))
+		case "/v1/cluster/partitions/kafka/bar":
+			fmt.Fprint(w, partitionsJSON("bar", 2))
+		case "/v1/cluster/partitions/kafka/baz":
+			fmt.Fprint(w, partitionsJSON("baz", 4))
+		case "/v1/cluster/partitions/kafka/empty":
+			fmt.Fprint(w, "[]")
+		case "/v1/cluster/partitions/kafka/broken":
+			fmt.Fprint(w, `[{"ns": "kafka", "topic": "broken", "partition_id": 0}, {"ns": `)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var ids []int
+	err = cl.ScanPartitions(ctx, func(p PartitionState) error {
+		require.Equal(t, 1, *p.LeaderID)
+		ids = append(ids, p.PartitionID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, ids)
+
+	t.Run("callback error stops the scan", func(t *testing.T) {
+		stop := errors.New("stop")
+		var n int
+		err := cl.ScanPartitions(ctx, func(PartitionState) error {
+			n++
+			if n == 2 {
+				return stop
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("topics", func(t *testing.T) {
+		var got []string
+		err := cl.ScanTopicPartitions(ctx, "kafka", []string{"foo", "bar", "baz", "empty"}, 2, func(p PartitionState) error {
+			got = append(got, fmt.Sprintf("%s/%d", p.Topic, p.PartitionID))
+			return nil
+		})
+		require.NoError(t, err)
+		sort.Strings(got)
+		require.Equal(t, []string{"bar/0", "bar/1", "baz/0", "baz/1", "baz/2", "baz/3", "foo/0", "foo/1", "foo/2"}, got)
+	})
+
+	t.Run("failed topics", func(t *testing.T) {
+		for _, topic := range []string{"missing", "broken"} {
+			err := cl.ScanTopicPartitions(ctx, "kafka", []string{"foo", topic, "bar"}, 0, func(PartitionState) error { return nil })
+			require.Error(t, err)
+			require.Contains(t, err.Error(), topic)
+		}
+	})
+}
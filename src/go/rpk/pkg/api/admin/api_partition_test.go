@@ -0,0 +1,73 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPartitionStaleLeadership(t *testing.T) {
+	const nNodes = 3
+	for _, test := range []struct {
+		name   string
+		epochs []int64 // leader epochs returned by successive requests, the last one repeats
+		exp    []int64 // epochs returned by successive GetPartition calls, -1 for a stale error
+		remaps bool    // whether the broker mapping is refreshed
+	}{
+		{"epochs moving forward", []int64{3, 4, 5}, []int64{3, 4, 5}, false},
+		{"unknown epochs", []int64{0, 0}, []int64{0, 0}, false},
+		{"stale epoch is retried", []int64{5, 3, 5}, []int64{5, 5}, true},
+		{"always stale", []int64{5, 3}, []int64{5, -1}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var partitionCalls, nodeConfigCalls int32
+			var urls []string
+			for i := 0; i < nNodes; i++ {
+				nodeID := i
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/v1/node_config":
+						atomic.AddInt32(&nodeConfigCalls, 1)
+						fmt.Fprintf(w, `{"node_id": %d}`, nodeID)
+					case "/v1/partitions/kafka/foo/0":
+						n := int(atomic.AddInt32(&partitionCalls, 1)) - 1
+						if n >= len(test.epochs) {
+							n = len(test.epochs) - 1
+						}
+						fmt.Fprintf(w, `{"leader_id": 1, "leader_epoch": %d}`, test.epochs[n])
+					}
+				}))
+				defer ts.Close()
+				urls = append(urls, ts.URL)
+			}
+
+			cl, err := NewAdminAPI(urls, BasicCredentials{}, nil)
+			require.NoError(t, err)
+
+			for _, exp := range test.exp {
+				pa, err := cl.GetPartition(context.Background(), "kafka", "foo", 0)
+				if exp == -1 {
+					require.True(t, IsStaleLeadership(err), "expected a stale leadership error, got %v", err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, exp, pa.LeaderEpoch)
+			}
+			require.Equal(t, test.remaps, atomic.LoadInt32(&nodeConfigCalls) > 0)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// RaftGroupState is the state of a raft group as seen by one of its
+// replicas. Offsets are raft offsets.
+type RaftGroupState struct {
+	GroupID  int   `json:"group_id"`
+	NodeID   int   `json:"node_id"`
+	Term     int64 `json:"term"`
+	LeaderID int   `json:"leader_id"` // -1 if the replica knows no leader
+	IsLeader bool  `json:"is_leader"`
+	// CommitIndex is the last offset replicated to a majority of the
+	// replicas.
+	CommitIndex int64 `json:"commit_index"`
+	// LastAppliedOffset is the last offset applied to the state machine of
+	// the replica, which lags behind the commit index while the replica
+	// catches up.
+	LastAppliedOffset int64 `json:"last_applied_offset"`
+	// DirtyOffset is the last offset written to the log of the replica,
+	// whether it is committed or not.
+	DirtyOffset   int64 `json:"dirty_offset"`
+	FlushedOffset int64 `json:"flushed_offset"`
+}
+
+// RaftFollower is the replication state of a follower of a raft group, as
+// tracked by the leader of the group.
+type RaftFollower struct {
+	NodeID int `json:"id"`
+	// MatchIndex is the last offset the leader knows to be replicated to
+	// the follower.
+	MatchIndex int64 `json:"match_index"`
+	// NextIndex is the next offset the leader sends to the follower.
+	NextIndex int64 `json:"next_index"`
+	IsLearner bool  `json:"is_learner"`
+	// MsSinceLastHeartbeat is the time since the follower last answered a
+	// heartbeat of the leader.
+	MsSinceLastHeartbeat int64 `json:"ms_since_last_heartbeat"`
+}
+
+// RaftGroupState returns the state of the raft group on the single broker of
+// this client. It's expected to be called from an AdminAPI with a single
+// broker URL, otherwise the method will return an error.
+//
+// Brokers that do not host a replica of the group return a 404, which can be
+// checked with IsNotFound.
+func (a *AdminAPI) RaftGroupState(ctx context.Context, group int) (RaftGroupState, error) {
+	var state RaftGroupState
+	return state, a.GetOne(ctx, APIv1.Path(fmt.Sprintf("/raft/%d/state", group)), nil, &state)
+}
+
+// RaftGroupStates queries the state of the raft group on every broker of the
+// client and returns them keyed by node ID. Brokers that do not host a
+// replica of the group are skipped.
+//
+// If some brokers fail, the states of the brokers that answered are still
+// returned, along with the errors.
+func (a *AdminAPI) RaftGroupStates(ctx context.Context, group int) (map[int]RaftGroupState, error) {
+	var (
+		mu     sync.Mutex
+		states = make(map[int]RaftGroupState)
+	)
+	err := a.eachBroker(func(aa *AdminAPI) error {
+		state, err := aa.RaftGroupState(ctx, group)
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		states[state.NodeID] = state
+		a.setBrokerURL(state.NodeID, aa.urls[0])
+		return nil
+	})
+	return states, err
+}
+
+// RaftGroupFollowers returns the followers of the raft group as tracked by
+// its leader, which must be reachable through one of the URLs of the client.
+// Only the leader tracks the followers: other brokers return an error.
+func (a *AdminAPI) RaftGroupFollowers(ctx context.Context, leader, group int) ([]RaftFollower, error) {
+	aa, err := a.forBroker(ctx, leader)
+	if err != nil {
+		return nil, err
+	}
+	var followers []RaftFollower
+	return followers, aa.GetOne(ctx, APIv1.Path(fmt.Sprintf("/raft/%d/followers", group)), nil, &followers)
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaftGroup(t *testing.T) {
+	// Brokers 0 and 1 host a replica of group 7, broker 0 leading it;
+	// broker 2 does not.
+	broker := func(id int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/v1/node_config":
+				fmt.Fprintf(w, `{"node_id":%d}`, id)
+			case r.URL.Path == "/v1/raft/7/state" && id < 2:
+				fmt.Fprintf(w, `{"group_id":7,"node_id":%d,"term":3,"leader_id":0,"is_leader":%t,"commit_index":120,"last_applied_offset":%d,"dirty_offset":130}`,
+					id, id == 0, 120-10*id)
+			case r.URL.Path == "/v1/raft/7/followers" && id == 0:
+				w.Write([]byte(`[{"id":1,"match_index":110,"next_index":111,"ms_since_last_heartbeat":50}]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	var urls []string
+	for i := 0; i < 3; i++ {
+		b := broker(i)
+		defer b.Close()
+		urls = append(urls, b.URL)
+	}
+
+	cl, err := NewAdminAPI(urls, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	states, err := cl.RaftGroupStates(context.Background(), 7)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	require.True(t, states[0].IsLeader)
+	require.Equal(t, int64(120), states[0].LastAppliedOffset)
+	require.Equal(t, int64(110), states[1].LastAppliedOffset)
+	require.Equal(t, int64(130), states[1].DirtyOffset)
+
+	followers, err := cl.RaftGroupFollowers(context.Background(), 0, 7)
+	require.NoError(t, err)
+	require.Equal(t, []RaftFollower{{NodeID: 1, MatchIndex: 110, NextIndex: 111, MsSinceLastHeartbeat: 50}}, followers)
+
+	_, err = cl.RaftGroupFollowers(context.Background(), 1, 7)
+	require.True(t, IsNotFound(err))
+
+	states, err = cl.RaftGroupStates(context.Background(), 8)
+	require.NoError(t, err)
+	require.Empty(t, states)
+}
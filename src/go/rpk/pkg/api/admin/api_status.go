@@ -0,0 +1,89 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	statusReadyPath = "/status/ready"
+
+	// StatusReady is the status of a broker ready to serve requests.
+	StatusReady = "ready"
+
+	// ProbeTimeout is the longest a probe waits for a broker to respond.
+	ProbeTimeout = 2 * time.Second
+)
+
+// NotReadyError is returned by Ready when a broker responded, but is not ready
+// to serve requests yet, e.g. while it is booting.
+type NotReadyError struct {
+	URL    string
+	Status string
+}
+
+func (e *NotReadyError) Error() string {
+	return fmt.Sprintf("%s is not ready, status: %q", e.URL, e.Status)
+}
+
+// Ready returns nil if every broker of the client is ready to serve requests.
+//
+// Ready and Alive are meant for probes and health checks: each broker is
+// sent a single request, with no retries and a timeout of at most
+// ProbeTimeout, so that the result reflects the state of the brokers rather
+// than being masked by retries.
+func (a *AdminAPI) Ready(ctx context.Context) error {
+	return a.eachBroker(func(aa *AdminAPI) error {
+		var status struct {
+			Status string `json:"status"`
+		}
+		err := aa.probe(ctx, &status)
+		var he *HTTPResponseError
+		if errors.As(err, &he) && he.Response.StatusCode == http.StatusServiceUnavailable {
+			// Brokers that are booting respond with their status.
+			if json.Unmarshal(he.Body, &status) == nil && status.Status != "" {
+				return &NotReadyError{URL: aa.urls[0], Status: status.Status}
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if status.Status != StatusReady {
+			return &NotReadyError{URL: aa.urls[0], Status: status.Status}
+		}
+		return nil
+	})
+}
+
+// Alive returns nil if the admin server of every broker of the client
+// responds, whether the broker is ready or not. See Ready.
+func (a *AdminAPI) Alive(ctx context.Context) error {
+	return a.eachBroker(func(aa *AdminAPI) error {
+		err := aa.probe(ctx, nil)
+		var he *HTTPResponseError
+		if errors.As(err, &he) {
+			// Any response means the server is up.
+			return nil
+		}
+		return err
+	})
+}
+
+func (a *AdminAPI) probe(ctx context.Context, into interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+	return a.sendOne(ctx, http.MethodGet, APIv1.Path(statusReadyPath), nil, into, false)
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbes(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		status   int
+		body     string
+		hang     bool
+		expReady func(*testing.T, error)
+		expAlive bool
+	}{
+		{
+			name:   "ready",
+			status: http.StatusOK,
+			body:   `{"status":"ready"}`,
+			expReady: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			expAlive: true,
+		},
+		{
+			name:   "booting",
+			status: http.StatusServiceUnavailable,
+			body:   `{"status":"booting"}`,
+			expReady: func(t *testing.T, err error) {
+				var nre *NotReadyError
+				require.True(t, errors.As(err, &nre), "got %v", err)
+				require.Equal(t, "booting", nre.Status)
+			},
+			expAlive: true,
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			expReady: func(t *testing.T, err error) {
+				var he *HTTPResponseError
+				require.True(t, errors.As(err, &he), "got %v", err)
+			},
+			expAlive: true,
+		},
+		{
+			name: "hung",
+			hang: true,
+			expReady: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int32
+			done := make(chan struct{})
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, APIv1.Path(statusReadyPath), r.URL.Path)
+				atomic.AddInt32(&requests, 1)
+				if test.hang {
+					<-done
+					return
+				}
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer ts.Close()
+			defer close(done)
+
+			cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			test.expReady(t, cl.Ready(ctx))
+			require.Equal(t, int32(1), atomic.LoadInt32(&requests), "probes must not be retried")
+
+			err = cl.Alive(ctx)
+			if test.expAlive {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// The transaction coordinator state is stored in the partitions of this
+// internal topic, each coordinating a subset of the transactional IDs.
+const (
+	txCoordinatorNamespace = "kafka_internal"
+	txCoordinatorTopic     = "tx"
+)
+
+// ProducerID identifies a producer session: the epoch is bumped each time a
+// transactional producer with the same transactional ID is initialized.
+type ProducerID struct {
+	ID    int64 `json:"id"`
+	Epoch int16 `json:"epoch"`
+}
+
+// PartitionProducer is the idempotency state a partition keeps for a
+// producer. Partitions keep this state until the producer is expired, which
+// is what zombie producers, that stopped producing without closing, hold on
+// to.
+type PartitionProducer struct {
+	ID    int64 `json:"id"`
+	Epoch int16 `json:"epoch"`
+	// LastSequenceNumbers are the sequence numbers of the last batches
+	// of the producer, used to deduplicate retried batches.
+	LastSequenceNumbers []int32 `json:"last_sequence_numbers"`
+	// LastUpdateTimestamp is when the producer last produced, in unix
+	// milliseconds.
+	LastUpdateTimestamp int64 `json:"last_update_timestamp"`
+	// The fields below are only set if the producer has a transaction
+	// open on the partition.
+	TransactionBeginOffset         *int64 `json:"transaction_begin_offset,omitempty"`
+	TransactionLastUpdateTimestamp *int64 `json:"transaction_last_update_timestamp,omitempty"`
+	TransactionTimeoutMs           *int64 `json:"transaction_timeout_ms,omitempty"`
+}
+
+// PartitionProducers are the producers known by a partition.
+type PartitionProducers struct {
+	Namespace   string              `json:"ns"`
+	Topic       string              `json:"topic"`
+	PartitionID int                 `json:"partition_id"`
+	Producers   []PartitionProducer `json:"producers"`
+}
+
+// PartitionTransaction is a transaction as seen by a partition it writes to.
+type PartitionTransaction struct {
+	ProducerID ProducerID `json:"producer_id"`
+	Status     string     `json:"status"`
+	// LSOBound is the offset of the first batch of the transaction, which
+	// holds back the last stable offset of the partition until the
+	// transaction ends.
+	LSOBound    int64 `json:"lso_bound"`
+	StalenessMs int64 `json:"staleness_ms"`
+	TimeoutMs   int64 `json:"timeout_ms"`
+}
+
+// PartitionTransactions are the transactions of a partition.
+type PartitionTransactions struct {
+	ActiveTransactions  []PartitionTransaction `json:"active_transactions"`
+	ExpiredTransactions []PartitionTransaction `json:"expired_transactions"`
+}
+
+// TransactionPartition is a partition a transaction writes to.
+type TransactionPartition struct {
+	Namespace   string `json:"ns"`
+	Topic       string `json:"topic"`
+	PartitionID int    `json:"partition_id"`
+	Etag        int64  `json:"etag"`
+}
+
+// TransactionGroup is a group a transaction commits offsets to.
+type TransactionGroup struct {
+	GroupID string `json:"group_id"`
+	Etag    int64  `json:"etag"`
+}
+
+// Transaction is the state of a transactional ID in its coordinator.
+type Transaction struct {
+	TransactionalID string                 `json:"transactional_id"`
+	ProducerID      ProducerID             `json:"pid"`
+	TxSeq           int64                  `json:"tx_seq"`
+	Status          string                 `json:"status"`
+	TimeoutMs       int64                  `json:"timeout_ms"`
+	StalenessMs     int64                  `json:"staleness_ms"`
+	Partitions      []TransactionPartition `json:"partitions"`
+	Groups          []TransactionGroup     `json:"groups"`
+	// CoordinatorPartition is the partition of the coordinator topic
+	// that owns the transactional ID. It is set by the client.
+	CoordinatorPartition int `json:"-"`
+}
+
+// GetPartitionProducers returns the producers known by a partition. The
+// state is local to the leader of the partition, which is queried directly.
+func (a *AdminAPI) GetPartitionProducers(
+	ctx context.Context, namespace, topic string, partition int,
+) (PartitionProducers, error) {
+	var producers PartitionProducers
+	path := APIv1.Path(fmt.Sprintf("/debug/producers/%s/%s/%d", namespace, topic, partition))
+	return producers, a.getPartitionLeader(ctx, namespace, topic, partition, path, &producers)
+}
+
+// GetPartitionTransactions returns the active and expired transactions of a
+// partition, from the leader of the partition.
+func (a *AdminAPI) GetPartitionTransactions(
+	ctx context.Context, namespace, topic string, partition int,
+) (PartitionTransactions, error) {
+	var txs PartitionTransactions
+	path := APIv1.Path(fmt.Sprintf("/partitions/%s/%s/%d/transactions", namespace, topic, partition))
+	return txs, a.getPartitionLeader(ctx, namespace, topic, partition, path, &txs)
+}
+
+// CoordinatorTransactions returns the transactions coordinated by the given
+// partition of the transaction coordinator, from the leader of the
+// partition.
+func (a *AdminAPI) CoordinatorTransactions(
+	ctx context.Context, coordinatorPartition int,
+) ([]Transaction, error) {
+	var txs []Transaction
+	query := url.Values{"coordinator_partition_id": []string{strconv.Itoa(coordinatorPartition)}}
+	path := pathWithQuery(APIv1.Path("/transactions"), query)
+	if err := a.getPartitionLeader(ctx, txCoordinatorNamespace, txCoordinatorTopic, coordinatorPartition, path, &txs); err != nil {
+		return nil, err
+	}
+	for i := range txs {
+		txs[i].CoordinatorPartition = coordinatorPartition
+	}
+	return txs, nil
+}
+
+// AllTransactions returns the transactions of every partition of the
+// transaction coordinator, sorted by transactional ID. If transactions were
+// never used in the cluster, the coordinator has no partitions and this
+// returns no transactions.
+func (a *AdminAPI) AllTransactions(ctx context.Context) ([]Transaction, error) {
+	partitions, err := a.AllClusterPartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the transaction coordinator partitions: %w", err)
+	}
+	var all []Transaction
+	for _, p := range partitions {
+		if p.Namespace != txCoordinatorNamespace || p.Topic != txCoordinatorTopic {
+			continue
+		}
+		txs, err := a.CoordinatorTransactions(ctx, p.PartitionID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the transactions of coordinator partition %d: %w", p.PartitionID, err)
+		}
+		all = append(all, txs...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].TransactionalID < all[j].TransactionalID })
+	return all, nil
+}
+
+// getPartitionLeader sends a GET request to the current leader of the
+// partition, for endpoints serving state local to the leader.
+func (a *AdminAPI) getPartitionLeader(
+	ctx context.Context, namespace, topic string, partition int, path string, into interface{},
+) error {
+	pa, err := a.GetPartition(ctx, namespace, topic, partition)
+	if err != nil {
+		return err
+	}
+	if pa.LeaderID < 0 {
+		return fmt.Errorf("partition %s/%s/%d has no leader", namespace, topic, partition)
+	}
+	leaderURL, err := a.brokerIDToURL(ctx, pa.LeaderID)
+	if err != nil {
+		return err
+	}
+	aLeader, err := a.newAdminForSingleHost(leaderURL)
+	if err != nil {
+		return err
+	}
+	return aLeader.sendOne(ctx, http.MethodGet, path, nil, into, true)
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionsFromPartitionLeaders(t *testing.T) {
+	// Node 1 leads kafka/foo/0 and tx/1, node 0 leads tx/0; state local to
+	// a leader is only served by that leader.
+	const nNodes = 2
+	var urls []string
+	for i := 0; i < nNodes; i++ {
+		nodeID := i
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v1/node_config":
+				fmt.Fprintf(w, `{"node_id": %d}`, nodeID)
+			case "/v1/partitions/kafka/foo/0", "/v1/partitions/kafka_internal/tx/1":
+				fmt.Fprint(w, `{"leader_id": 1}`)
+			case "/v1/partitions/kafka_internal/tx/0":
+				fmt.Fprint(w, `{"leader_id": 0}`)
+			case "/v1/cluster/partitions":
+				fmt.Fprint(w, `[
+					{"ns": "kafka", "topic": "foo", "partition_id": 0},
+					{"ns": "kafka_internal", "topic": "tx", "partition_id": 0},
+					{"ns": "kafka_internal", "topic": "tx", "partition_id": 1}
+				]`)
+			case "/v1/debug/producers/kafka/foo/0":
+				if nodeID != 1 {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"ns": "kafka", "topic": "foo", "partition_id": 0, "producers": [
+					{"id": 7, "epoch": 1, "last_sequence_numbers": [41], "last_update_timestamp": 1000},
+					{"id": 8, "epoch": 0, "last_update_timestamp": 2000, "transaction_begin_offset": 12}
+				]}`)
+			case "/v1/transactions":
+				p := r.URL.Query().Get("coordinator_partition_id")
+				if p != fmt.Sprint(nodeID) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprintf(w, `[{"transactional_id": "tx-%d", "pid": {"id": %d, "epoch": 2}, "status": "ongoing"}]`, 1-nodeID, nodeID)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer ts.Close()
+		urls = append(urls, ts.URL)
+	}
+
+	cl, err := NewAdminAPI(urls, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	producers, err := cl.GetPartitionProducers(ctx, "kafka", "foo", 0)
+	require.NoError(t, err)
+	require.Len(t, producers.Producers, 2)
+	require.Equal(t, []int32{41}, producers.Producers[0].LastSequenceNumbers)
+	require.Nil(t, producers.Producers[0].TransactionBeginOffset)
+	require.Equal(t, int64(12), *producers.Producers[1].TransactionBeginOffset)
+
+	txs, err := cl.AllTransactions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Transaction{
+		{TransactionalID: "tx-0", ProducerID: ProducerID{ID: 1, Epoch: 2}, Status: "ongoing", CoordinatorPartition: 1},
+		{TransactionalID: "tx-1", ProducerID: ProducerID{ID: 0, Epoch: 2}, Status: "ongoing", CoordinatorPartition: 0},
+	}, txs)
+}
@@ -12,11 +12,11 @@ package admin
 import (
 	"context"
 	"errors"
-	"net/http"
+	"fmt"
 	"net/url"
 )
 
-const usersEndpoint = "/v1/security/users"
+const usersEndpoint = "/security/users"
 
 type newUser struct {
 	User      string `json:"username"`
@@ -48,7 +48,24 @@ func (a *AdminAPI) CreateUser(ctx context.Context, username, password, mechanism
 		Password:  password,
 		Algorithm: mechanism,
 	}
-	return a.sendToLeader(ctx, http.MethodPost, usersEndpoint, u, nil)
+	return a.PostLeader(ctx, APIv1.Path(usersEndpoint), nil, u, nil)
+}
+
+// UpdateUser updates the password and mechanism of the given user.
+func (a *AdminAPI) UpdateUser(ctx context.Context, username, password, mechanism string) error {
+	if username == "" {
+		return errors.New("invalid empty username")
+	}
+	if password == "" {
+		return errors.New("invalid empty password")
+	}
+	u := newUser{
+		User:      username,
+		Password:  password,
+		Algorithm: mechanism,
+	}
+	path := APIv1.Path(usersEndpoint + "/" + url.PathEscape(username))
+	return a.PutLeader(ctx, path, nil, u, nil)
 }
 
 // DeleteUser deletes the given username, if it exists.
@@ -56,12 +73,37 @@ func (a *AdminAPI) DeleteUser(ctx context.Context, username string) error {
 	if username == "" {
 		return errors.New("invalid empty username")
 	}
-	path := usersEndpoint + "/" + url.PathEscape(username)
-	return a.sendToLeader(ctx, http.MethodDelete, path, nil, nil)
+	path := APIv1.Path(usersEndpoint + "/" + url.PathEscape(username))
+	return a.DeleteLeader(ctx, path, nil, nil, nil)
 }
 
 // ListUsers returns the current users.
 func (a *AdminAPI) ListUsers(ctx context.Context) ([]string, error) {
 	var users []string
-	return users, a.sendAny(ctx, http.MethodGet, usersEndpoint, nil, &users)
+	return users, a.GetAny(ctx, APIv1.Path(usersEndpoint), nil, &users)
+}
+
+// WaitForUser waits until the given user is listed by every broker in the
+// client, which is useful to read back a user immediately after creating it.
+//
+// If the context has no deadline, this gives up after a short default timeout.
+func (a *AdminAPI) WaitForUser(ctx context.Context, username string) error {
+	return a.eachBroker(func(aa *AdminAPI) error {
+		err := retryUntilVisible(ctx, func(ctx context.Context) (bool, error) {
+			users, err := aa.ListUsers(ctx)
+			if err != nil {
+				return false, err
+			}
+			for _, u := range users {
+				if u == username {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return fmt.Errorf("user %q is not visible on %s: %w", username, aa.urls[0], err)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,180 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultUserBatchConcurrency is the number of concurrent requests issued by
+// BatchCreateUsers and BatchDeleteUsers when no concurrency is specified.
+const DefaultUserBatchConcurrency = 8
+
+// UserCredentials is a user to create with BatchCreateUsers.
+type UserCredentials struct {
+	Username  string
+	Password  string
+	Mechanism string // SCRAM-SHA-256 or SCRAM-SHA-512
+}
+
+// BatchUsersError is returned from BatchCreateUsers and BatchDeleteUsers if
+// some users could not be created or deleted. Every user that is not in
+// Failures succeeded.
+type BatchUsersError struct {
+	Op       string           // "create" or "delete"
+	Failures map[string]error // username => error
+}
+
+func (e *BatchUsersError) Error() string {
+	users := make([]string, 0, len(e.Failures))
+	for u := range e.Failures {
+		users = append(users, u)
+	}
+	sort.Strings(users)
+
+	const maxListed = 3
+	var listed []string
+	for _, u := range users {
+		if len(listed) == maxListed {
+			listed = append(listed, fmt.Sprintf("and %d more", len(users)-maxListed))
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s: %v", u, e.Failures[u]))
+	}
+	return fmt.Sprintf("unable to %s %d users: %s", e.Op, len(users), strings.Join(listed, "; "))
+}
+
+// BatchCreateUsers creates all given users, issuing up to concurrency requests
+// at once (DefaultUserBatchConcurrency if concurrency is not positive).
+//
+// The admin API has no bulk endpoint, so users are created one request at a
+// time. The leader is looked up once for the whole batch rather than once per
+// user. If any user cannot be created, this returns a *BatchUsersError
+// describing every failure; the other users are still created.
+func (a *AdminAPI) BatchCreateUsers(
+	ctx context.Context, users []UserCredentials, concurrency int,
+) error {
+	fns := make(map[string]func(*AdminAPI) error, len(users))
+	for _, u := range users {
+		u := u
+		fns[u.Username] = func(cl *AdminAPI) error {
+			return cl.CreateUser(ctx, u.Username, u.Password, u.Mechanism)
+		}
+	}
+	return a.batchUsers(ctx, "create", fns, concurrency)
+}
+
+// BatchDeleteUsers deletes all given users, issuing up to concurrency requests
+// at once (DefaultUserBatchConcurrency if concurrency is not positive).
+//
+// As with BatchCreateUsers, this returns a *BatchUsersError describing every
+// user that could not be deleted.
+func (a *AdminAPI) BatchDeleteUsers(
+	ctx context.Context, usernames []string, concurrency int,
+) error {
+	fns := make(map[string]func(*AdminAPI) error, len(usernames))
+	for _, u := range usernames {
+		u := u
+		fns[u] = func(cl *AdminAPI) error {
+			return cl.DeleteUser(ctx, u)
+		}
+	}
+	return a.batchUsers(ctx, "delete", fns, concurrency)
+}
+
+func (a *AdminAPI) batchUsers(
+	ctx context.Context,
+	op string,
+	fns map[string]func(*AdminAPI) error,
+	concurrency int,
+) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultUserBatchConcurrency
+	}
+
+	leader := a.batchLeader(ctx)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures = make(map[string]error)
+		sem      = make(chan struct{}, concurrency)
+	)
+	for user, fn := range fns {
+		user, fn := user, fn
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			failures[user] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			err := fn(leader)
+			if err != nil && leader != a && ctx.Err() == nil && !isClientError(err) {
+				// Leadership may have moved during the batch: retry
+				// with a fresh leader lookup.
+				err = fn(a)
+			}
+			if err != nil {
+				mu.Lock()
+				failures[user] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &BatchUsersError{Op: op, Failures: failures}
+	}
+	return nil
+}
+
+// batchLeader returns a client for the current admin API leader only, falling
+// back to this client (which looks up the leader on every request) if the
+// leader cannot be determined.
+func (a *AdminAPI) batchLeader(ctx context.Context) *AdminAPI {
+	if len(a.urls) == 1 {
+		return a
+	}
+	leaderID, err := a.GetLeaderID(ctx)
+	if err != nil {
+		return a
+	}
+	leaderURL, err := a.brokerIDToURL(ctx, *leaderID)
+	if err != nil {
+		return a
+	}
+	leader, err := a.newAdminForSingleHost(leaderURL)
+	if err != nil {
+		return a
+	}
+	return leader
+}
+
+// isClientError returns whether err is a 4xx response, which would fail again
+// if retried.
+func isClientError(err error) bool {
+	var he *HTTPResponseError
+	return errors.As(err, &he) && he.Response != nil &&
+		he.Response.StatusCode >= 400 && he.Response.StatusCode < 500
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchUsers(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		users    = make(map[string]bool)
+		inflight int32
+		peak     int32
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPost:
+			var u newUser
+			if err := json.NewDecoder(r.Body).Decode(&u); err != nil || strings.HasPrefix(u.User, "bad") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			users[u.User] = true
+		case http.MethodDelete:
+			name := strings.TrimPrefix(r.URL.Path, APIv1.Path(usersEndpoint)+"/")
+			if !users[name] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(users, name)
+		}
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var create []UserCredentials
+	for i := 0; i < 20; i++ {
+		create = append(create, UserCredentials{fmt.Sprintf("user-%d", i), "pass", ScramSha256})
+	}
+	create = append(create, UserCredentials{"bad-user", "pass", ScramSha256}, UserCredentials{"no-password", "", ScramSha256})
+
+	err = cl.BatchCreateUsers(ctx, create, 4)
+	var be *BatchUsersError
+	require.True(t, errors.As(err, &be))
+	require.Equal(t, "create", be.Op)
+	require.Len(t, be.Failures, 2)
+	require.Contains(t, be.Failures, "bad-user")
+	require.Contains(t, be.Failures, "no-password")
+	require.Len(t, users, 20)
+	require.LessOrEqual(t, atomic.LoadInt32(&peak), int32(4))
+
+	err = cl.BatchDeleteUsers(ctx, []string{"user-0", "user-1", "missing"}, 0)
+	require.True(t, errors.As(err, &be))
+	require.Len(t, be.Failures, 1)
+	require.True(t, IsNotFound(be.Failures["missing"]))
+	require.Len(t, users, 18)
+
+	require.NoError(t, cl.BatchDeleteUsers(ctx, nil, 0))
+}
+
+func TestBatchUsersError(t *testing.T) {
+	err := &BatchUsersError{Op: "create", Failures: map[string]error{
+		"d": errors.New("boom"),
+		"a": errors.New("boom"),
+		"c": errors.New("boom"),
+		"b": errors.New("boom"),
+	}}
+	require.Equal(t, "unable to create 4 users: a: boom; b: boom; c: boom; and 1 more", err.Error())
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// APIVersion is the version of an admin API endpoint, which prefixes its
+// path: /v1/brokers is version 1 of the /brokers endpoint.
+type APIVersion int
+
+const (
+	APIv1 APIVersion = 1
+	APIv2 APIVersion = 2
+)
+
+func (v APIVersion) String() string { return fmt.Sprintf("v%d", int(v)) }
+
+// Path returns the path of version v of the endpoint, which starts with a
+// slash: APIv1.Path("/brokers") is /v1/brokers.
+func (v APIVersion) Path(endpoint string) string {
+	return "/" + v.String() + endpoint
+}
+
+// apiVersions remembers the version of each endpoint that the brokers
+// answered, so that versions are only negotiated once per endpoint. It is
+// shared by the single host clients derived from a client.
+type apiVersions struct {
+	mu         sync.Mutex
+	negotiated map[string]APIVersion
+}
+
+func (vs *apiVersions) get(endpoint string) (APIVersion, bool) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	v, ok := vs.negotiated[endpoint]
+	return v, ok
+}
+
+func (vs *apiVersions) set(endpoint string, v APIVersion) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.negotiated == nil {
+		vs.negotiated = make(map[string]APIVersion)
+	}
+	vs.negotiated[endpoint] = v
+}
+
+// NegotiatedAPIVersion returns the version of the endpoint the client uses,
+// if it was negotiated already.
+func (a *AdminAPI) NegotiatedAPIVersion(endpoint string) (APIVersion, bool) {
+	return a.apiVersions.get(endpoint)
+}
+
+// endpointVersions are the versions of the endpoints that brokers serve in
+// more than one version with the same semantics, in order of preference,
+// the newest first. Endpoints that are not listed only have a v1.
+var endpointVersions = map[string][]APIVersion{
+	brokersEndpoint:           {APIv2, APIv1},
+	clusterHealthOverviewPath: {APIv2, APIv1},
+}
+
+// sendVersioned sends a request to a version of the endpoint with send,
+// which builds the path of the request from the version: it may be the path
+// of a resource under the endpoint, e.g. /brokers/1 under /brokers.
+//
+// The first request to the endpoint tries each version until one exists on
+// the broker, which is the case unless the broker answers a 404 for the
+// route itself, as brokers that predate a version do. The client then keeps
+// using the version that was answered, unless its route is missing again,
+// e.g. on an older broker during a rolling upgrade, in which case the next
+// versions are tried again. A 404 for a missing resource of an existing
+// route is returned as is.
+func (a *AdminAPI) sendVersioned(
+	ctx context.Context,
+	endpoint string,
+	send func(ctx context.Context, v APIVersion) error,
+) error {
+	versions, ok := endpointVersions[endpoint]
+	if !ok {
+		versions = []APIVersion{APIv1}
+	}
+	if v, ok := a.apiVersions.get(endpoint); ok {
+		versions = preferVersion(versions, v)
+	}
+	var err error
+	for _, v := range versions {
+		err = send(ctx, v)
+		if isUnknownRoute(err) {
+			continue
+		}
+		if err == nil || IsNotFound(err) {
+			a.apiVersions.set(endpoint, v)
+		}
+		return err
+	}
+	return err
+}
+
+// isUnknownRoute returns whether the error is the 404 of a route the broker
+// does not serve: the admin server answers those with the generic "Not
+// found" message, while the handlers of existing routes explain which
+// resource is missing. An empty body, e.g. from a proxy, is also taken as a
+// missing route.
+func isUnknownRoute(err error) bool {
+	var he *HTTPResponseError
+	if !errors.As(err, &he) || he.Response.StatusCode != http.StatusNotFound {
+		return false
+	}
+	if len(bytes.TrimSpace(he.Body)) == 0 {
+		return true
+	}
+	ae := he.decodeAdminError()
+	return ae != nil && strings.EqualFold(ae.Message, "not found")
+}
+
+// preferVersion returns versions with v first, and the versions after v
+// in their order. The versions preferred to v are not tried again: the
+// brokers answered v before, so they do not have the newer versions.
+func preferVersion(versions []APIVersion, v APIVersion) []APIVersion {
+	for i, candidate := range versions {
+		if candidate == v {
+			return versions[i:]
+		}
+	}
+	return versions
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVersionPath(t *testing.T) {
+	require.Equal(t, "/v1/brokers", APIv1.Path("/brokers"))
+	require.Equal(t, "/v2/brokers/1", APIv2.Path("/brokers/1"))
+}
+
+func TestSendVersioned(t *testing.T) {
+	// broker serves /brokers in the given versions, and counts the
+	// requests of each path. Broker 3 does not exist.
+	broker := func(versions ...string) (*httptest.Server, map[string]*int32) {
+		calls := map[string]*int32{
+			"/v1/brokers": new(int32), "/v2/brokers": new(int32),
+			"/v1/brokers/3": new(int32), "/v2/brokers/3": new(int32),
+		}
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if n, ok := calls[r.URL.Path]; ok {
+				atomic.AddInt32(n, 1)
+			}
+			for _, v := range versions {
+				switch r.URL.Path {
+				case "/" + v + "/brokers":
+					w.Write([]byte(`[]`))
+					return
+				case "/" + v + "/brokers/3":
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"message": "broker 3 not found", "code": 404}`))
+					return
+				}
+			}
+			// The answer of the admin server to unknown routes.
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not found", "code": 404}`))
+		})), calls
+	}
+
+	t.Run("newer broker", func(t *testing.T) {
+		b, calls := broker("v1", "v2")
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		_, err = cl.Brokers(context.Background())
+		require.NoError(t, err)
+		_, err = cl.Brokers(context.Background())
+		require.NoError(t, err)
+		v, ok := cl.NegotiatedAPIVersion(brokersEndpoint)
+		require.True(t, ok)
+		require.Equal(t, APIv2, v)
+		require.Equal(t, int32(2), atomic.LoadInt32(calls["/v2/brokers"]))
+		require.Equal(t, int32(0), atomic.LoadInt32(calls["/v1/brokers"]))
+	})
+
+	t.Run("older broker", func(t *testing.T) {
+		b, calls := broker("v1")
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		_, ok := cl.NegotiatedAPIVersion(brokersEndpoint)
+		require.False(t, ok)
+		_, err = cl.Brokers(context.Background())
+		require.NoError(t, err)
+		_, err = cl.Brokers(context.Background())
+		require.NoError(t, err)
+		v, ok := cl.NegotiatedAPIVersion(brokersEndpoint)
+		require.True(t, ok)
+		require.Equal(t, APIv1, v)
+		// v2 is only tried by the first request.
+		require.Equal(t, int32(1), atomic.LoadInt32(calls["/v2/brokers"]))
+		require.Equal(t, int32(2), atomic.LoadInt32(calls["/v1/brokers"]))
+	})
+
+	t.Run("missing resource", func(t *testing.T) {
+		b, calls := broker("v1", "v2")
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		// The 404 of a missing broker is not a missing version.
+		_, err = cl.Broker(context.Background(), 3)
+		require.True(t, IsNotFound(err))
+		require.Equal(t, int32(1), atomic.LoadInt32(calls["/v2/brokers/3"]))
+		require.Equal(t, int32(0), atomic.LoadInt32(calls["/v1/brokers/3"]))
+		v, ok := cl.NegotiatedAPIVersion(brokersEndpoint)
+		require.True(t, ok)
+		require.Equal(t, APIv2, v)
+	})
+
+	t.Run("no version", func(t *testing.T) {
+		b, _ := broker()
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		_, err = cl.Brokers(context.Background())
+		require.True(t, IsNotFound(err))
+		_, ok := cl.NegotiatedAPIVersion(brokersEndpoint)
+		require.False(t, ok)
+	})
+
+	t.Run("shared with host clients", func(t *testing.T) {
+		b, _ := broker("v1")
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+		host, err := cl.newAdminForSingleHost(b.URL)
+		require.NoError(t, err)
+
+		_, err = host.Brokers(context.Background())
+		require.NoError(t, err)
+		v, ok := cl.NegotiatedAPIVersion(brokersEndpoint)
+		require.True(t, ok)
+		require.Equal(t, APIv1, v)
+	})
+}
+
+func TestPreferVersion(t *testing.T) {
+	versions := []APIVersion{APIv2, APIv1}
+	require.Equal(t, []APIVersion{APIv2, APIv1}, preferVersion(versions, APIv2))
+	require.Equal(t, []APIVersion{APIv1}, preferVersion(versions, APIv1))
+	require.Equal(t, versions, preferVersion(versions, APIVersion(3)))
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// AdminAPIClient contains the endpoint methods of AdminAPI. Code that only
+// needs to talk to the admin API can depend on this interface, and use the
+// fake of the admintest package in unit tests instead of an HTTP server.
+//
+// New endpoint methods of AdminAPI must be added here and to the fake. The
+// per-method send helpers, such as GetAny or PostLeader, are not: code that
+// depends on this interface sends requests to unwrapped endpoints with Do.
+type AdminAPIClient interface {
+	// Brokers
+	GetLeaderID(ctx context.Context) (*int, error)
+	Brokers(ctx context.Context) ([]Broker, error)
+	Broker(ctx context.Context, node int) (Broker, error)
+	DecommissionBroker(ctx context.Context, node int) error
+	RecommissionBroker(ctx context.Context, node int) error
+	WaitForDecommission(ctx context.Context, nodeID int) error
+	EnableMaintenanceMode(ctx context.Context, nodeID int) error
+	DisableMaintenanceMode(ctx context.Context, nodeID int) error
+	MaintenanceStatus(ctx context.Context, nodeID int) (MaintenanceStatus, error)
+	WaitForDrain(ctx context.Context, nodeID int) (MaintenanceStatus, error)
+	CancelNodePartitionsMovement(ctx context.Context, node int) ([]PartitionsMovementResult, error)
+
+	// Cluster
+	GetHealthOverview(ctx context.Context) (ClusterHealthOverview, error)
+	GetClusterUUID(ctx context.Context) (string, error)
+	GetBootstrapStatus(ctx context.Context) (BootstrapStatus, error)
+	Ready(ctx context.Context) error
+	Alive(ctx context.Context) error
+	GetPartitionStatus(ctx context.Context) (PartitionBalancerStatus, error)
+	CancelAllPartitionsMovement(ctx context.Context) ([]PartitionsMovementResult, error)
+	TriggerPartitionsRebalance(ctx context.Context) error
+	Reconfigurations(ctx context.Context) ([]Reconfiguration, error)
+	WaitForReconfigurationDone(ctx context.Context, filter func(Reconfiguration) bool) error
+	CheckClusterStability(ctx context.Context, opts StabilityOptions) (StabilityVerdict, error)
+	Subscribe(ctx context.Context, opts SubscribeOptions) <-chan ClusterEvent
+
+	// Configuration
+	Config(ctx context.Context) (Config, error)
+	GetLoggers(ctx context.Context) ([]Logger, error)
+	SetLogLevel(ctx context.Context, name, level string, expirySeconds int) error
+	SetLoggerLevel(ctx context.Context, logger, level string, expiry time.Duration) error
+	SetLoggerLevels(ctx context.Context, levels map[string]string, expiry time.Duration) map[string]error
+	ClusterConfigSchema(ctx context.Context) (ConfigSchema, error)
+	PatchClusterConfig(ctx context.Context, upsert map[string]interface{}, remove []string) (ClusterConfigWriteResult, error)
+	PatchClusterConfigAtVersion(ctx context.Context, upsert map[string]interface{}, remove []string, version int) (ClusterConfigWriteResult, error)
+	ClusterConfigStatus(ctx context.Context, sendToLeader bool) (ConfigStatusResponse, error)
+	WaitForConfigVersion(ctx context.Context, version int) error
+	GetNodeConfig(ctx context.Context) (NodeConfig, error)
+	GetAllNodeConfigs(ctx context.Context) (map[int]NodeConfig, error)
+	PatchNodeConfig(ctx context.Context, upsert map[string]interface{}, remove []string) (NodeConfigWriteResult, error)
+	PatchBrokerConfig(ctx context.Context, node int, upsert map[string]interface{}, remove []string) (NodeConfigWriteResult, error)
+
+	// Features and license
+	GetFeatures(ctx context.Context) (FeaturesResponse, error)
+	GetLicenseInfo(ctx context.Context) (License, error)
+	SetLicense(ctx context.Context, license interface{}) error
+
+	// Partitions and cloud storage
+	GetPartition(ctx context.Context, namespace, topic string, partition int) (Partition, error)
+	AllClusterPartitions(ctx context.Context) ([]ClusterPartition, error)
+	ScanPartitions(ctx context.Context, fn func(PartitionState) error) error
+	ScanTopicPartitions(ctx context.Context, namespace string, topics []string, concurrency int, fn func(PartitionState) error) error
+	GetPartitionManifest(ctx context.Context, namespace, topic string, partition int) (PartitionManifest, error)
+	CloudStorageCacheStats(ctx context.Context, node int) (CacheStats, error)
+	TrimCloudStorageCache(ctx context.Context, node int, target CacheTrimTarget) (CacheTrimResult, error)
+
+	// Transactions
+	GetPartitionProducers(ctx context.Context, namespace, topic string, partition int) (PartitionProducers, error)
+	GetPartitionTransactions(ctx context.Context, namespace, topic string, partition int) (PartitionTransactions, error)
+	CoordinatorTransactions(ctx context.Context, coordinatorPartition int) ([]Transaction, error)
+	AllTransactions(ctx context.Context) ([]Transaction, error)
+
+	// Raft
+	RaftGroupState(ctx context.Context, group int) (RaftGroupState, error)
+	RaftGroupStates(ctx context.Context, group int) (map[int]RaftGroupState, error)
+	RaftGroupFollowers(ctx context.Context, leader, group int) ([]RaftFollower, error)
+
+	// Metrics
+	PrometheusMetrics(ctx context.Context) ([]byte, error)
+	CloudStorageStats(ctx context.Context) (CloudStorageStats, error)
+
+	// Debug
+	UploadDebugBundle(ctx context.Context, name string, r io.ReaderAt, size int64, opts DebugBundleUploadOptions) (DebugBundleUpload, error)
+
+	// Users
+	CreateUser(ctx context.Context, username, password, mechanism string) error
+	UpdateUser(ctx context.Context, username, password, mechanism string) error
+	DeleteUser(ctx context.Context, username string) error
+	ListUsers(ctx context.Context) ([]string, error)
+	WaitForUser(ctx context.Context, username string) error
+	BatchCreateUsers(ctx context.Context, users []UserCredentials, concurrency int) error
+	BatchDeleteUsers(ctx context.Context, usernames []string, concurrency int) error
+
+	// Endpoints that the client does not wrap
+	Do(ctx context.Context, method, path string, body, into interface{}) error
+}
+
+var _ AdminAPIClient = &AdminAPI{}
@@ -0,0 +1,136 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	stdnet "net"
+	"net/http"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/net"
+)
+
+// HostTLS overrides the TLS configuration of the client for a single host.
+type HostTLS struct {
+	// ServerName is the name the certificate of the host is verified
+	// against, rather than the hostname of the host.
+	ServerName string
+	// InsecureSkipVerify disables the verification of the certificate of
+	// the host.
+	InsecureSkipVerify bool
+	// CAPEM holds PEM encoded CAs trusted for the host, in addition to
+	// the CAs of the client, or to the system CAs if the client has none.
+	CAPEM []byte
+}
+
+// SetHostTLS overrides the TLS configuration of the client for some hosts,
+// keyed by host as given to the client, e.g. "broker-0:9644". This must be
+// called before the client is used.
+func (a *AdminAPI) SetHostTLS(overrides map[string]HostTLS) error {
+	configs := make(map[string]*tls.Config, len(overrides))
+	for host, o := range overrides {
+		_, hostport, err := net.ParseHostMaybeScheme(host)
+		if err != nil {
+			return fmt.Errorf("invalid host %q: %w", host, err)
+		}
+		cfg, err := a.hostTLSConfig(hostport, o)
+		if err != nil {
+			return fmt.Errorf("invalid TLS override of host %q: %w", host, err)
+		}
+		configs[hostport] = cfg
+	}
+	a.setHostTLSConfigs(configs)
+	return nil
+}
+
+func (a *AdminAPI) hostTLSConfig(hostport string, o HostTLS) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if a.tlsConfig != nil {
+		cfg = a.tlsConfig.Clone()
+	}
+	cfg.ServerName = o.ServerName
+	cfg.InsecureSkipVerify = o.InsecureSkipVerify //nolint:gosec // explicitly requested for the host
+	if len(o.CAPEM) == 0 || o.InsecureSkipVerify {
+		return cfg, nil
+	}
+
+	var err error
+	extra := x509.NewCertPool()
+	if !extra.AppendCertsFromPEM(o.CAPEM) {
+		return nil, errors.New("no valid PEM certificate in the CA")
+	}
+	// Certificate pools cannot be merged, so the chain is verified here
+	// against either pool, with the roots of the client first (nil roots
+	// are the system roots).
+	roots := cfg.RootCAs
+	name := o.ServerName
+	if name == "" {
+		if name, _, err = stdnet.SplitHostPort(hostport); err != nil {
+			name = hostport
+		}
+	}
+	cfg.InsecureSkipVerify = true //nolint:gosec // verified in VerifyConnection
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no certificate presented by the host")
+		}
+		opts := x509.VerifyOptions{
+			DNSName:       name,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, c := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(c)
+		}
+		var err error
+		for _, pool := range []*x509.CertPool{roots, extra} {
+			opts.Roots = pool
+			if _, err = cs.PeerCertificates[0].Verify(opts); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+	return cfg, nil
+}
+
+// setHostTLSConfigs routes the requests to the hosts with a TLS override
+// through a transport of their own.
+func (a *AdminAPI) setHostTLSConfigs(configs map[string]*tls.Config) {
+	if len(configs) == 0 {
+		return
+	}
+	a.hostTLS = configs
+	transports := make(map[string]http.RoundTripper, len(configs))
+	for host, cfg := range configs {
+		transports[host] = &http.Transport{TLSClientConfig: cfg}
+	}
+	a.retryTransport.base = &hostTLSTransport{a.retryTransport.base, transports}
+	a.oneshotClient.Transport = &hostTLSTransport{a.oneshotClient.Transport, transports}
+}
+
+// hostTLSTransport sends the requests to the hosts in hosts with their own
+// transport, and the other requests with base.
+type hostTLSTransport struct {
+	base  http.RoundTripper
+	hosts map[string]http.RoundTripper
+}
+
+func (t *hostTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt, ok := t.hosts[req.URL.Host]; ok {
+		return rt.RoundTrip(req)
+	}
+	if t.base == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.base.RoundTrip(req)
+}
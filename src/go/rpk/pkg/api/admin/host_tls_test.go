@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"node_id": 1}`))
+	}))
+	defer ts.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	// The certificate of the test server is valid for example.com and
+	// 127.0.0.1, but not for localhost: this is a mismatching certificate.
+	host := strings.Replace(strings.TrimPrefix(ts.URL, "https://"), "127.0.0.1", "localhost", 1)
+
+	for _, test := range []struct {
+		name     string
+		override *HostTLS
+		expErr   bool
+	}{
+		{"no override", nil, true},
+		{"CA without server name", &HostTLS{CAPEM: ca}, true},
+		{"server name without CA", &HostTLS{ServerName: "example.com"}, true},
+		{"server name and CA", &HostTLS{ServerName: "example.com", CAPEM: ca}, false},
+		{"insecure", &HostTLS{InsecureSkipVerify: true}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cl, err := NewAdminAPI([]string{host}, BasicCredentials{}, &tls.Config{MinVersion: tls.VersionTLS12})
+			require.NoError(t, err)
+			cl.SetRetryHook(nil)
+			cl.SetBackoffPolicy(BackoffPolicy{MaxAttempts: 1})
+			if test.override != nil {
+				err = cl.SetHostTLS(map[string]HostTLS{host: *test.override})
+				require.NoError(t, err)
+			}
+			_, err = cl.GetNodeConfig(context.Background())
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	cl, err := NewAdminAPI([]string{host}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	err = cl.SetHostTLS(map[string]HostTLS{host: {CAPEM: []byte("not a certificate")}})
+	require.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// Middleware wraps the round trip of every request of the client, to
+// inspect or modify the request before it is sent and its response, e.g. to
+// add headers, trace requests or mirror them to another endpoint.
+//
+// A middleware sees each request once, as built by the client: the retries
+// of the request and the redirects it follows happen in next. A middleware
+// must not modify the request it is given, but send a modified clone of it
+// (see http.Request.Clone), as required of any http.RoundTripper.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is a function that implements http.RoundTripper, which
+// helps writing middlewares.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Use appends middlewares to the client. Requests go through the middlewares
+// in the order they were added, then through the built-in authentication
+// middlewares of the client (BasicAuthMiddleware and SigningMiddleware), so
+// that headers added by middlewares are signed. This must be called before
+// the client is used.
+func (a *AdminAPI) Use(middlewares ...Middleware) {
+	a.middlewares = append(a.middlewares, middlewares...)
+}
+
+// roundTripper returns the chain of middlewares that requests are sent
+// through, which ends with the retrying client if retryable is true, or the
+// one-shot client otherwise.
+func (a *AdminAPI) roundTripper(retryable bool) http.RoundTripper {
+	var rt http.RoundTripper = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if retryable {
+			return a.retryClient.Do(req) //nolint:contextcheck // False positive in v1.0.9, will be fixed in next release.
+		}
+		return a.oneshotClient.Do(req)
+	})
+	rt = SigningMiddleware(a.signer)(rt)
+	rt = BasicAuthMiddleware(a.basicCredentials)(rt)
+	for i := len(a.middlewares) - 1; i >= 0; i-- {
+		rt = a.middlewares[i](rt)
+	}
+	return rt
+}
+
+// BasicAuthMiddleware sets the basic auth credentials of requests, unless
+// the username is empty. Clients authenticate with their credentials through
+// this middleware.
+func BasicAuthMiddleware(creds BasicCredentials) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if creds.Username == "" {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.SetBasicAuth(creds.Username, creds.Password)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// SigningMiddleware signs requests with the signer, if it is non-nil.
+// Clients sign requests with their signer through this middleware.
+func SigningMiddleware(signer RequestSigner) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if signer == nil {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := requestBody(req)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read request body for %s %s: %w", req.Method, req.URL, err)
+			}
+			req = req.Clone(req.Context())
+			if err := signer.Sign(req, body); err != nil {
+				return nil, fmt.Errorf("unable to sign request %s %s: %w", req.Method, req.URL, err)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// requestBody returns a copy of the body of the request, which the client
+// always makes replayable.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("body of type %T cannot be replayed", req.Body)
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// LoggingMiddleware logs every request, with its status code or error and
+// its duration, with logf, or at the debug level if logf is nil.
+func LoggingMiddleware(logf func(format string, args ...interface{})) Middleware {
+	if logf == nil {
+		logf = log.Debugf
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next.RoundTrip(req)
+			took := time.Since(start).Round(time.Millisecond)
+			if err != nil {
+				logf("%s %s failed after %v: %v", req.Method, req.URL, took, err)
+			} else {
+				logf("%s %s: %s in %v", req.Method, req.URL, res.Status, took)
+			}
+			return res, err
+		})
+	}
+}
+
+// RateLimitMiddleware limits the rate of requests of the client to limit
+// requests per second, with bursts of up to burst requests. Requests wait
+// for their turn until their context is done.
+func RateLimitMiddleware(limit rate.Limit, burst int) Middleware {
+	limiter := rate.NewLimiter(limit, burst)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, fmt.Errorf("rate limited request %s %s: %w", req.Method, req.URL, err)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
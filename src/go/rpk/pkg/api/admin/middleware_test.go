@@ -0,0 +1,129 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// headerSigner signs requests with a header holding the tenant header and
+// the body, to check that signing sees both.
+type headerSigner struct{}
+
+func (headerSigner) Sign(req *http.Request, body []byte) error {
+	req.Header.Set("X-Signature", req.Header.Get("X-Tenant")+":"+string(body))
+	return nil
+}
+
+func TestMiddlewares(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []*http.Request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r)
+		mu.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	last := func() *http.Request {
+		mu.Lock()
+		defer mu.Unlock()
+		return seen[len(seen)-1]
+	}
+
+	cl, err := NewAdminAPI([]string{server.URL}, BasicCredentials{Username: "admin", Password: "secret"}, nil)
+	require.NoError(t, err)
+	cl.SetRequestSigner(headerSigner{})
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	tenant := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Tenant", "tenant-1")
+			return next.RoundTrip(req)
+		})
+	}
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	cl.Use(trace("first"), trace("second"), tenant, LoggingMiddleware(logf))
+
+	ctx := context.Background()
+	require.NoError(t, cl.sendOne(ctx, http.MethodPut, "/v1/config", map[string]int{"k": 1}, nil, false))
+
+	r := last()
+	require.Equal(t, []string{"first", "second"}, order)
+	user, pass, ok := r.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "admin", user)
+	require.Equal(t, "secret", pass)
+	require.Equal(t, "tenant-1", r.Header.Get("X-Tenant"))
+	require.Equal(t, `tenant-1:{"k":1}`, r.Header.Get("X-Signature"))
+	require.Len(t, logged, 1)
+	require.True(t, strings.HasPrefix(logged[0], "PUT "+server.URL+"/v1/config: 200 OK in "), logged[0])
+
+	// Clients derived for a single host keep the middlewares.
+	single, err := cl.newAdminForSingleHost(server.URL)
+	require.NoError(t, err)
+	order = nil
+	require.Error(t, single.sendOne(ctx, http.MethodGet, "/fail", nil, nil, false))
+	require.Equal(t, []string{"first", "second"}, order)
+	require.Equal(t, "tenant-1", last().Header.Get("X-Tenant"))
+	require.True(t, strings.HasPrefix(logged[1], "GET "+server.URL+"/fail: 400 Bad Request in "), logged[1])
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	var calls int
+	rt := RateLimitMiddleware(rate.Every(time.Hour), 1)(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+	send := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/v1/brokers", nil)
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		if err == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+
+	require.NoError(t, send(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, send(ctx), "the second request should wait past the deadline")
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxRedirects caps the redirects followed by a request. A redirect to the
+// leader takes a single hop, more only happen while leadership moves.
+const maxRedirects = 3
+
+// ErrTooManyRedirects is returned when a request is redirected more than
+// maxRedirects times.
+var ErrTooManyRedirects = fmt.Errorf("stopped after %d redirects", maxRedirects)
+
+// UnknownRedirectError is returned when the admin API redirects a request to
+// a URL that is not one of the URLs of the client. Such redirects are not
+// followed, since the credentials of the client would be sent to a host it
+// was not configured to trust.
+type UnknownRedirectError struct {
+	Method string
+	From   string // the URL that redirected the request
+	To     string // the URL the request was redirected to
+	Known  []string
+}
+
+func (e *UnknownRedirectError) Error() string {
+	return fmt.Sprintf("%s %s redirected to %s, which is not one of the admin API URLs %s",
+		e.Method, e.From, e.To, strings.Join(e.Known, ", "))
+}
+
+// knownURLs returns the scheme://host of the URLs redirects may be followed
+// to: the URLs of the client, and those of the client it was derived from.
+func knownURLs(urls []string, parent map[string]bool) map[string]bool {
+	known := make(map[string]bool, len(urls)+len(parent))
+	for u := range parent {
+		known[u] = true
+	}
+	for _, u := range urls {
+		known[u] = true
+	}
+	return known
+}
+
+// checkRedirect follows the redirects of the admin API, such as the redirect
+// of a write to the leader, to the known URLs only. The credentials of the
+// client are applied again, since the http package drops the Authorization
+// header on redirects across hosts, and signed requests are signed again for
+// their new URL. Hosts with a TLS override keep it, as the override is
+// applied per host by the transport.
+func (a *AdminAPI) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > maxRedirects {
+		return ErrTooManyRedirects
+	}
+	prev := via[len(via)-1]
+	target := req.URL.Scheme + "://" + req.URL.Host
+	if !a.knownURLs[target] {
+		known := make([]string, 0, len(a.knownURLs))
+		for u := range a.knownURLs {
+			known = append(known, u)
+		}
+		sort.Strings(known)
+		return &UnknownRedirectError{
+			Method: prev.Method,
+			From:   prev.URL.String(),
+			To:     req.URL.String(),
+			Known:  known,
+		}
+	}
+
+	if a.basicCredentials.Username != "" {
+		req.SetBasicAuth(a.basicCredentials.Username, a.basicCredentials.Password)
+	}
+	if a.signer == nil {
+		return nil
+	}
+	var body []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("unable to replay the body of the redirected request %s %s: %w", req.Method, req.URL, err)
+		}
+		body, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("unable to replay the body of the redirected request %s %s: %w", req.Method, req.URL, err)
+		}
+	}
+	if err := a.signer.Sign(req, body); err != nil {
+		return fmt.Errorf("unable to sign redirected request %s %s: %w", req.Method, req.URL, err)
+	}
+	return nil
+}
+
+// IsUnknownRedirect returns whether the error is an UnknownRedirectError.
+func IsUnknownRedirect(err error) bool {
+	var re *UnknownRedirectError
+	return errors.As(err, &re)
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirects(t *testing.T) {
+	// The leader checks the credentials and the body of the requests that
+	// the follower redirects to it.
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPut && string(body) != `{"k":"v"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"node_id": 1}`))
+	}))
+	defer leader.Close()
+	unknown := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"node_id": 2}`))
+	}))
+	defer unknown.Close()
+
+	var redirectTo string
+	follower := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := redirectTo
+		if target == "" {
+			target = "http://" + r.Host // loop
+		}
+		http.Redirect(w, r, target+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer follower.Close()
+
+	// Redirects are not retries: they are neither delayed by the backoff,
+	// which is an hour here, nor reported to the retry hook.
+	newClient := func(urls ...string) *AdminAPI {
+		cl, err := NewAdminAPI(urls, BasicCredentials{Username: "admin", Password: "secret"}, nil)
+		require.NoError(t, err)
+		cl.SetRetryHook(func(a RetryAttempt) {
+			t.Errorf("redirect reported as retry %d of %s %s", a.Attempt, a.Method, a.URL)
+		})
+		cl.SetBackoffPolicy(BackoffPolicy{Base: time.Hour, Max: time.Hour})
+		return cl
+	}
+	// A backoff would fail the requests with the deadline of the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("followed to a known URL with the credentials", func(t *testing.T) {
+		redirectTo = leader.URL
+		cl := newClient(follower.URL, leader.URL)
+		single, err := cl.newAdminForSingleHost(follower.URL)
+		require.NoError(t, err)
+
+		var nc NodeConfig
+		require.NoError(t, single.sendOne(ctx, http.MethodGet, "/v1/node_config", nil, &nc, false))
+		require.Equal(t, 1, nc.NodeID)
+		require.NoError(t, single.sendOne(ctx, http.MethodPut, "/v1/config", map[string]string{"k": "v"}, nil, true))
+	})
+
+	t.Run("not followed to an unknown URL", func(t *testing.T) {
+		redirectTo = unknown.URL
+		cl := newClient(follower.URL, leader.URL)
+		single, err := cl.newAdminForSingleHost(follower.URL)
+		require.NoError(t, err)
+
+		err = single.sendOne(ctx, http.MethodGet, "/v1/node_config", nil, nil, false)
+		require.Error(t, err)
+		var re *UnknownRedirectError
+		require.True(t, errors.As(err, &re), "got %v", err)
+		require.Equal(t, follower.URL+"/v1/node_config", re.From)
+		require.Equal(t, unknown.URL+"/v1/node_config", re.To)
+		known := []string{follower.URL, leader.URL}
+		sort.Strings(known)
+		require.Equal(t, known, re.Known)
+	})
+
+	t.Run("too many redirects", func(t *testing.T) {
+		redirectTo = ""
+		cl := newClient(follower.URL)
+
+		err := cl.sendOne(ctx, http.MethodGet, "/v1/node_config", nil, nil, false)
+		require.True(t, errors.Is(err, ErrTooManyRedirects), "got %v", err)
+	})
+}
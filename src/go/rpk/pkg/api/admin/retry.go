@@ -0,0 +1,194 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BackoffPolicy is the exponential backoff between the attempts of a request
+// that failed with a connection error, a 5xx or a 429. Before the nth retry,
+// the client waits a random duration between zero and Base*2^(n-1), capped at
+// Max ("full jitter"), so that clients retrying against an unavailable
+// controller spread out instead of retrying in lockstep.
+type BackoffPolicy struct {
+	Base        time.Duration // backoff ceiling of the first retry
+	Max         time.Duration // maximum backoff ceiling
+	MaxAttempts int           // attempts of a request, including the first
+}
+
+// DefaultBackoffPolicy is the backoff policy of new clients. The ceilings
+// grow past the default redpanda raft election timeout of 1.5s, so that the
+// client outlasts leadership changes, even if they repeat.
+var DefaultBackoffPolicy = BackoffPolicy{
+	Base:        time.Second,
+	Max:         10 * time.Second,
+	MaxAttempts: 5,
+}
+
+// withDefaults returns the policy with the zero fields set to the defaults.
+func (p BackoffPolicy) withDefaults() BackoffPolicy {
+	if p.Base <= 0 {
+		p.Base = DefaultBackoffPolicy.Base
+	}
+	if p.Max <= 0 {
+		p.Max = DefaultBackoffPolicy.Max
+	}
+	if p.Max < p.Base {
+		p.Max = p.Base
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultBackoffPolicy.MaxAttempts
+	}
+	return p
+}
+
+// ceiling returns the maximum backoff before the given retry, starting at 1.
+func (p BackoffPolicy) ceiling(retry int) time.Duration {
+	d := p.Base
+	for i := 1; i < retry && d < p.Max; i++ {
+		d *= 2
+	}
+	if d > p.Max {
+		d = p.Max
+	}
+	return d
+}
+
+// Backoff returns the backoff before the given retry, starting at 1.
+func (p BackoffPolicy) Backoff(retry int) time.Duration {
+	return time.Duration(rng(int(p.ceiling(retry)) + 1))
+}
+
+// RetryAttempt describes a retry of a request, passed to the retry hook of the
+// client before waiting for the backoff.
+type RetryAttempt struct {
+	Method      string
+	URL         string
+	Attempt     int           // the attempt about to be made, starting at 2
+	MaxAttempts int           // attempts of the request, including the first
+	Backoff     time.Duration // the wait before the attempt
+	Err         error         // the error of the previous attempt
+}
+
+// logRetry is the default retry hook.
+func logRetry(a RetryAttempt) {
+	log.Infof("Retrying %s %s (attempt %d of %d) in %v for error: %v",
+		a.Method, a.URL, a.Attempt, a.MaxAttempts, a.Backoff.Round(time.Millisecond), a.Err)
+}
+
+type retryStateKey struct{}
+
+// retryState is shared by the attempts of a single request through the retry
+// client, which are sequential.
+type retryState struct {
+	attempts int
+	until    time.Time // set from the Retry-After of the last response
+	lastErr  error     // the error or failed status of the last attempt
+}
+
+// withRetryState returns a context that lets retryTransport delay the retries
+// of a request sent with it.
+func withRetryState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryStateKey{}, new(retryState))
+}
+
+// retryTransport waits between the attempts of the pester retry client.
+//
+// Pester sleeps between retries without looking at the request context, so
+// cancelling a request would only take effect once the backoff is over. The
+// client therefore has no backoff of its own and this transport waits instead,
+// aborting as soon as the context is done. The wait is the longer of the
+// backoff and whatever is left of the Retry-After of a previous 429 or 503.
+//
+// The hook, if non-nil, is called before waiting for each retry. Redirects
+// of a request, e.g. to the leader, are sent right away: they are hops of
+// the same attempt, not retries.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy BackoffPolicy
+	hook   func(RetryAttempt)
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	s, _ := ctx.Value(retryStateKey{}).(*retryState)
+	// The http package sets the response that caused a redirect on the
+	// redirected request.
+	redirect := req.Response != nil
+	if s != nil && !redirect {
+		wait := time.Until(s.until)
+		if s.attempts > 0 {
+			policy := t.policy.withDefaults()
+			if b := policy.Backoff(s.attempts); b > wait {
+				wait = b
+			}
+			if t.hook != nil {
+				t.hook(RetryAttempt{
+					Method:      req.Method,
+					URL:         req.URL.String(),
+					Attempt:     s.attempts + 1,
+					MaxAttempts: policy.MaxAttempts,
+					Backoff:     wait,
+					Err:         s.lastErr,
+				})
+			}
+		}
+		s.attempts++
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	res, err := base.RoundTrip(req)
+	if s != nil {
+		s.lastErr = err
+		if err == nil {
+			switch res.StatusCode {
+			case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+				if d := parseRetryAfter(res.Header, time.Now()); d > 0 {
+					s.until = time.Now().Add(d)
+				}
+			}
+			if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+				s.lastErr = fmt.Errorf("unexpected status %s", res.Status)
+			}
+		}
+	}
+	return res, err
+}
+
+// sleep waits for d, returning early with the context error if ctx is done
+// first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
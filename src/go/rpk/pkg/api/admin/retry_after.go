@@ -0,0 +1,63 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryAfter caps how long a Retry-After header can delay a request, so
+// that a misbehaving server cannot stall the client.
+const maxRetryAfter = 30 * time.Second
+
+// RetryAfter returns how long the server asked to wait before retrying the
+// request, or zero if the response is not a 429 or 503 with a valid
+// Retry-After header.
+func (he HTTPResponseError) RetryAfter() time.Duration {
+	if he.Response == nil {
+		return 0
+	}
+	switch he.Response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return parseRetryAfter(he.Response.Header, time.Now())
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header in either the delay-seconds or
+// the HTTP-date format, returning zero if it is missing or invalid.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		if secs > int64(maxRetryAfter/time.Second) {
+			return maxRetryAfter
+		}
+		d = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(v); err == nil {
+		d = at.Sub(now)
+	}
+	if d <= 0 {
+		return 0
+	}
+	if d > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return d
+}
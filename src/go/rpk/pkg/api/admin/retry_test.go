@@ -0,0 +1,103 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleep(t *testing.T) {
+	require.NoError(t, sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.True(t, errors.Is(sleep(ctx, time.Hour), context.Canceled))
+}
+
+func TestCancelInterruptsRetryBackoff(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = cl.sendAny(ctx, http.MethodGet, "/v1/status/ready", nil, nil)
+	require.Error(t, err)
+	// Without cancellation, the retries would take several backoffs of up
+	// to 10s.
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestBackoffPolicy(t *testing.T) {
+	p := BackoffPolicy{Base: 100 * time.Millisecond, Max: time.Second}.withDefaults()
+	require.Equal(t, DefaultBackoffPolicy.MaxAttempts, p.MaxAttempts)
+	for _, test := range []struct {
+		retry int
+		exp   time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	} {
+		require.Equal(t, test.exp, p.ceiling(test.retry), "retry %d", test.retry)
+		for i := 0; i < 100; i++ {
+			b := p.Backoff(test.retry)
+			require.True(t, b >= 0 && b <= test.exp, "retry %d: backoff %v", test.retry, b)
+		}
+	}
+
+	p = BackoffPolicy{Base: time.Minute, Max: time.Second}.withDefaults()
+	require.Equal(t, time.Minute, p.ceiling(3))
+}
+
+func TestRetryHook(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	cl.SetBackoffPolicy(BackoffPolicy{Base: time.Millisecond, Max: 2 * time.Millisecond, MaxAttempts: 4})
+	var attempts []RetryAttempt
+	cl.SetRetryHook(func(a RetryAttempt) { attempts = append(attempts, a) })
+
+	err = cl.sendAny(context.Background(), http.MethodGet, "/v1/status/ready", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	for i, a := range attempts {
+		require.Equal(t, i+2, a.Attempt)
+		require.Equal(t, 4, a.MaxAttempts)
+		require.Equal(t, http.MethodGet, a.Method)
+		require.Equal(t, ts.URL+"/v1/status/ready", a.URL)
+		require.True(t, a.Backoff <= 2*time.Millisecond)
+		require.Contains(t, a.Err.Error(), "503")
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The helpers below pair an HTTP method with a routing strategy, and are
+// what endpoint wrappers should use rather than the send functions directly:
+//
+//   - *Any sends to any broker, trying the others on failure,
+//   - *Leader sends to the leader of the admin API,
+//   - *All sends to every broker at once, keeping the first success, which
+//     is what writes need where brokers cannot redirect to the leader,
+//   - *One sends to the single broker of a single host client, without
+//     retries, which is what node local endpoints need.
+//
+// Every helper takes optional query parameters, which are encoded and
+// appended to the path, and every helper but the GETs an optional body,
+// including the DELETEs: a few endpoints take one, which Go sends like any
+// other body. A body is JSON encoded unless it is an io.Reader, which is
+// sent as is. Responses without a body, such as 204 No Content, leave into
+// untouched.
+
+// GetAny sends a GET request to any broker.
+func (a *AdminAPI) GetAny(ctx context.Context, path string, query url.Values, into interface{}) error {
+	return a.sendAny(ctx, http.MethodGet, pathWithQuery(path, query), nil, into)
+}
+
+// GetLeader sends a GET request to the leader of the admin API.
+func (a *AdminAPI) GetLeader(ctx context.Context, path string, query url.Values, into interface{}) error {
+	return a.sendToLeader(ctx, http.MethodGet, pathWithQuery(path, query), nil, into)
+}
+
+// GetOne sends a GET request to the broker of a single host client.
+func (a *AdminAPI) GetOne(ctx context.Context, path string, query url.Values, into interface{}) error {
+	return a.sendOne(ctx, http.MethodGet, pathWithQuery(path, query), nil, into, false)
+}
+
+// PostAny sends a POST request to any broker.
+func (a *AdminAPI) PostAny(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAny(ctx, http.MethodPost, pathWithQuery(path, query), body, into)
+}
+
+// PostLeader sends a POST request to the leader of the admin API.
+func (a *AdminAPI) PostLeader(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendToLeader(ctx, http.MethodPost, pathWithQuery(path, query), body, into)
+}
+
+// PostAll sends a POST request to every broker.
+func (a *AdminAPI) PostAll(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAll(ctx, http.MethodPost, pathWithQuery(path, query), body, into)
+}
+
+// PostOne sends a POST request to the broker of a single host client.
+func (a *AdminAPI) PostOne(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendOne(ctx, http.MethodPost, pathWithQuery(path, query), body, into, false)
+}
+
+// PutAny sends a PUT request to any broker.
+func (a *AdminAPI) PutAny(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAny(ctx, http.MethodPut, pathWithQuery(path, query), body, into)
+}
+
+// PutLeader sends a PUT request to the leader of the admin API.
+func (a *AdminAPI) PutLeader(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendToLeader(ctx, http.MethodPut, pathWithQuery(path, query), body, into)
+}
+
+// PutAll sends a PUT request to every broker.
+func (a *AdminAPI) PutAll(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAll(ctx, http.MethodPut, pathWithQuery(path, query), body, into)
+}
+
+// PutOne sends a PUT request to the broker of a single host client.
+func (a *AdminAPI) PutOne(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendOne(ctx, http.MethodPut, pathWithQuery(path, query), body, into, false)
+}
+
+// PatchAny sends a PATCH request to any broker.
+func (a *AdminAPI) PatchAny(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAny(ctx, http.MethodPatch, pathWithQuery(path, query), body, into)
+}
+
+// PatchLeader sends a PATCH request to the leader of the admin API.
+func (a *AdminAPI) PatchLeader(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendToLeader(ctx, http.MethodPatch, pathWithQuery(path, query), body, into)
+}
+
+// PatchAll sends a PATCH request to every broker.
+func (a *AdminAPI) PatchAll(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAll(ctx, http.MethodPatch, pathWithQuery(path, query), body, into)
+}
+
+// PatchOne sends a PATCH request to the broker of a single host client.
+func (a *AdminAPI) PatchOne(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendOne(ctx, http.MethodPatch, pathWithQuery(path, query), body, into, false)
+}
+
+// DeleteAny sends a DELETE request to any broker.
+func (a *AdminAPI) DeleteAny(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAny(ctx, http.MethodDelete, pathWithQuery(path, query), body, into)
+}
+
+// DeleteLeader sends a DELETE request to the leader of the admin API.
+func (a *AdminAPI) DeleteLeader(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendToLeader(ctx, http.MethodDelete, pathWithQuery(path, query), body, into)
+}
+
+// DeleteAll sends a DELETE request to every broker.
+func (a *AdminAPI) DeleteAll(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendAll(ctx, http.MethodDelete, pathWithQuery(path, query), body, into)
+}
+
+// DeleteOne sends a DELETE request to the broker of a single host client.
+func (a *AdminAPI) DeleteOne(ctx context.Context, path string, query url.Values, body, into interface{}) error {
+	return a.sendOne(ctx, http.MethodDelete, pathWithQuery(path, query), body, into, false)
+}
+
+// Do sends a request to an endpoint that the client does not wrap, with the
+// routing, retries, authentication, and error handling of the endpoints that
+// it does. GET and HEAD requests are sent to any broker, trying the others on
+// failure; other requests are sent to the leader of the admin API.
+//
+// The path includes the version prefix and any query, e.g.
+// "/v1/cluster/partition_balancer/status". The body is JSON encoded unless it
+// is an io.Reader, which is sent as is. The response is JSON decoded into
+// into, unless into is a *[]byte or a *string, which receive the raw body.
+// Failed requests return an *HTTPResponseError.
+func (a *AdminAPI) Do(ctx context.Context, method, path string, body, into interface{}) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("invalid admin API path %q: the path must start with a /", path)
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return a.sendAny(ctx, method, path, body, into)
+	default:
+		return a.sendToLeader(ctx, method, path, body, into)
+	}
+}
+
+// pathWithQuery appends the encoded query to path, after any query the path
+// already has.
+func pathWithQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + query.Encode()
+}
+
+type requestHeadersKey struct{}
+
+// withRequestHeader returns a context whose requests are sent with the
+// header, e.g. a precondition of a conditional write. Headers added to a
+// context that already has some are merged with them.
+func withRequestHeader(ctx context.Context, key, value string) context.Context {
+	h := make(http.Header)
+	if prior, ok := ctx.Value(requestHeadersKey{}).(http.Header); ok {
+		h = prior.Clone()
+	}
+	h.Set(key, value)
+	return context.WithValue(ctx, requestHeadersKey{}, h)
+}
+
+type responseHeadersKey struct{}
+
+// WithResponseHeaders returns a context whose requests copy the given headers
+// of their response into the returned header, e.g. the ETag of a resource to
+// send back as an If-Match precondition. Without keys, every header of the
+// response is copied.
+//
+// Only the headers of the response that the call used are copied: responses
+// of failed attempts on other brokers, and error responses, are not. Since
+// the header is filled in place, a context should be used for a single call
+// at a time.
+func WithResponseHeaders(ctx context.Context, keys ...string) (context.Context, http.Header) {
+	h := make(http.Header)
+	return context.WithValue(ctx, responseHeadersKey{}, responseHeaders{keys, h}), h
+}
+
+type responseHeaders struct {
+	keys []string
+	into http.Header
+}
+
+// captureResponseHeaders copies the headers of a successful response into
+// the header of the context, if it has one from WithResponseHeaders.
+func captureResponseHeaders(ctx context.Context, res *http.Response) {
+	rh, ok := ctx.Value(responseHeadersKey{}).(responseHeaders)
+	if !ok {
+		return
+	}
+	if len(rh.keys) == 0 {
+		for k, vs := range res.Header {
+			rh.into[k] = append([]string(nil), vs...)
+		}
+		return
+	}
+	for _, k := range rh.keys {
+		if vs := res.Header.Values(k); len(vs) > 0 {
+			rh.into[http.CanonicalHeaderKey(k)] = append([]string(nil), vs...)
+		}
+	}
+}
+
+// rawBody is a request body that is sent as is. Unlike an io.Reader, it can
+// be sent more than once.
+type rawBody []byte
+
+// replayableBody reads a body that is an io.Reader into memory, so that it
+// can be sent again when a request is retried on another broker; a reader
+// can only be sent once. Other bodies are returned as is.
+func replayableBody(body interface{}) (interface{}, error) {
+	r, ok := body.(io.Reader)
+	if !ok {
+		return body, nil
+	}
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read request body: %w", err)
+	}
+	return rawBody(bs), nil
+}
+
+// decompressResponse decompresses a gzipped response that the transport left
+// as is. The transport only decompresses the responses to the requests that
+// it asked a gzipped response for, but the server, or a proxy in front of it,
+// may compress regardless: the raw body returned to a caller, and the headers
+// copied with WithResponseHeaders, would then not match. Like the transport,
+// the headers of the compressed body are removed.
+func decompressResponse(res *http.Response) {
+	if res.Uncompressed || !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+	res.Body = &gzipBody{body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+}
+
+// gzipBody decompresses a body on its first read, so that an empty body, e.g.
+// of a HEAD request, is not an error.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.zr == nil && g.err == nil {
+		g.zr, g.err = gzip.NewReader(g.body)
+	}
+	if g.err != nil {
+		return 0, g.err
+	}
+	return g.zr.Read(p)
+}
+
+func (g *gzipBody) Close() error {
+	return g.body.Close()
+}
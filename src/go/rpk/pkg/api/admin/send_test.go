@@ -0,0 +1,200 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathWithQuery(t *testing.T) {
+	require.Equal(t, "/v1/foo", pathWithQuery("/v1/foo", nil))
+	require.Equal(t, "/v1/foo?a=1&b=x+y", pathWithQuery("/v1/foo", url.Values{"b": []string{"x y"}, "a": []string{"1"}}))
+	require.Equal(t, "/v1/foo?a=1&b=2", pathWithQuery("/v1/foo?a=1", url.Values{"b": []string{"2"}}))
+}
+
+func TestSendHelpers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no content", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Empty(t, r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		into := struct{ Foo string }{"unchanged"}
+		require.NoError(t, cl.PostAny(ctx, "/v1/foo", nil, nil, &into))
+		require.Equal(t, "unchanged", into.Foo)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPut, r.Method)
+			require.Equal(t, "/v1/config/log_level/a b", r.URL.Path)
+			require.Equal(t, "debug", r.URL.Query().Get("level"))
+			require.Equal(t, "2", r.URL.Query().Get("expires"))
+		}))
+		defer ts.Close()
+		cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, cl.SetLogLevel(ctx, "a b", "debug", 2))
+	})
+
+	t.Run("delete with body", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, `{"force":true}`, string(body))
+		}))
+		defer ts.Close()
+		cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, cl.DeleteLeader(ctx, "/v1/foo", nil, map[string]bool{"force": true}, nil))
+	})
+
+	t.Run("patch to all brokers", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPatch, r.Method)
+			require.Equal(t, "dry_run=true", r.URL.RawQuery)
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, `{"Foo":"bar"}`, string(body))
+			w.Write([]byte(`{"Foo":"baz"}`))
+		}))
+		defer ts.Close()
+		cl, err := NewAdminAPI([]string{failing.URL, ts.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		var into struct{ Foo string }
+		require.NoError(t, cl.PatchAll(ctx, "/v1/foo", url.Values{"dry_run": []string{"true"}}, struct{ Foo string }{"bar"}, &into))
+		require.Equal(t, "baz", into.Foo)
+	})
+
+	t.Run("reader body is replayed", func(t *testing.T) {
+		var calls int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, "license", string(body))
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		ts1 := httptest.NewServer(handler)
+		defer ts1.Close()
+		ts2 := httptest.NewServer(handler)
+		defer ts2.Close()
+		cl, err := NewAdminAPI([]string{ts1.URL, ts2.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, cl.PutAny(ctx, "/v1/foo", nil, strings.NewReader("license"), nil))
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestWithResponseHeaders(t *testing.T) {
+	var calls int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("ETag", `"failed"`)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("ETag", `"5"`)
+		w.Header().Add("X-Page", "a")
+		w.Header().Add("X-Page", "b")
+		w.Write([]byte(`{"Foo":"bar"}`))
+	})
+	ts1 := httptest.NewServer(handler)
+	defer ts1.Close()
+	ts2 := httptest.NewServer(handler)
+	defer ts2.Close()
+	cl, err := NewAdminAPI([]string{ts1.URL, ts2.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	// Only the headers of the response that was used are copied.
+	ctx, h := WithResponseHeaders(context.Background(), "etag", "x-page", "x-missing")
+	var into struct{ Foo string }
+	require.NoError(t, cl.GetAny(ctx, "/v1/foo", nil, &into))
+	require.Equal(t, "bar", into.Foo)
+	require.Equal(t, http.Header{"Etag": {`"5"`}, "X-Page": {"a", "b"}}, h)
+
+	ctx, h = WithResponseHeaders(context.Background())
+	require.NoError(t, cl.GetAny(ctx, "/v1/foo", nil, nil))
+	require.Equal(t, `"5"`, h.Get("ETag"))
+	require.Equal(t, []string{"a", "b"}, h.Values("X-Page"))
+}
+
+func TestDo(t *testing.T) {
+	gzipped := func(s string) []byte {
+		var b bytes.Buffer
+		zw := gzip.NewWriter(&b)
+		zw.Write([]byte(s))
+		zw.Close()
+		return b.Bytes()
+	}
+	// The server gzips its responses although the requests do not ask for
+	// it, which the transport does not decompress.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/foo":
+			require.Equal(t, "1", r.URL.Query().Get("a"))
+			w.Write(gzipped(`{"Foo":"bar"}`))
+		case r.Method == http.MethodHead:
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, `{"Foo":"baz"}`, string(body))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(gzipped(`{"message":"bad foo","code":400}`))
+		}
+	}))
+	defer ts.Close()
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	ctx, h := WithResponseHeaders(context.Background())
+	var raw []byte
+	require.NoError(t, cl.Do(ctx, http.MethodGet, "/v1/foo?a=1", nil, &raw))
+	require.Equal(t, `{"Foo":"bar"}`, string(raw))
+	require.Empty(t, h.Get("Content-Encoding"))
+
+	var into struct{ Foo string }
+	require.NoError(t, cl.Do(context.Background(), http.MethodGet, "/v1/foo?a=1", nil, &into))
+	require.Equal(t, "bar", into.Foo)
+
+	require.NoError(t, cl.Do(context.Background(), http.MethodHead, "/v1/foo", nil, nil))
+
+	err = cl.Do(context.Background(), http.MethodPost, "/v1/foo", struct{ Foo string }{"baz"}, nil)
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+	require.Equal(t, `{"message":"bad foo","code":400}`, string(he.Body))
+
+	require.Error(t, cl.Do(context.Background(), http.MethodGet, "v1/foo", nil, nil))
+}
@@ -0,0 +1,254 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+)
+
+// RequestSigner signs admin API requests before they are sent, for admin
+// APIs fronted by gateways that only accept signed requests.
+type RequestSigner interface {
+	// Sign adds the signature of the request to its headers. The body is
+	// the full request body, which may be empty.
+	Sign(req *http.Request, body []byte) error
+}
+
+// The request signing schemes supported in the rpk configuration.
+const (
+	SigningHMACSHA256 = "hmac-sha256"
+	SigningAWSSigV4   = "aws-sigv4"
+)
+
+// NewRequestSigner returns the signer configured in rpk.admin_api.signing, or
+// nil if requests are not signed.
+func NewRequestSigner(cfg *config.RequestSigning) (RequestSigner, error) {
+	if cfg == nil || cfg.Type == "" {
+		return nil, nil
+	}
+	if cfg.KeyID == "" || cfg.Secret == "" {
+		return nil, errors.New("request signing requires a key_id and a secret")
+	}
+	switch strings.ToLower(cfg.Type) {
+	case SigningHMACSHA256:
+		return &HMACSigner{KeyID: cfg.KeyID, Secret: []byte(cfg.Secret)}, nil
+	case SigningAWSSigV4:
+		if cfg.Region == "" || cfg.Service == "" {
+			return nil, errors.New("aws-sigv4 request signing requires a region and a service")
+		}
+		return &SigV4Signer{
+			AccessKeyID:     cfg.KeyID,
+			SecretAccessKey: cfg.Secret,
+			SessionToken:    cfg.SessionToken,
+			Region:          cfg.Region,
+			Service:         cfg.Service,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported request signing type %q, supported types: %s, %s", cfg.Type, SigningHMACSHA256, SigningAWSSigV4)
+	}
+}
+
+// HMACSigner signs requests with a shared secret following the HTTP
+// Signatures draft (draft-cavage-http-signatures) using hmac-sha256. The
+// request target, host, date and body digest are signed.
+type HMACSigner struct {
+	KeyID  string
+	Secret []byte
+
+	now func() time.Time // for testing
+}
+
+// Sign implements RequestSigner.
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	sum := sha256.Sum256(body)
+	req.Header.Set("Date", now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]))
+
+	headers := []string{"(request-target)", "host", "date", "digest"}
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		var v string
+		switch h {
+		case "(request-target)":
+			v = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			v = requestHost(req)
+		default:
+			v = req.Header.Get(h)
+		}
+		lines = append(lines, h+": "+v)
+	}
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	req.Header.Set("Signature", fmt.Sprintf(`keyId=%q,algorithm="hmac-sha256",headers=%q,signature=%q`,
+		s.KeyID,
+		strings.Join(headers, " "),
+		base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	))
+	return nil
+}
+
+// SigV4Signer signs requests with AWS Signature Version 4, for admin APIs
+// fronted by AWS gateways. The signature is sent in the Authorization header,
+// which replaces any basic authentication credentials.
+type SigV4Signer struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Service         string
+
+	now func() time.Time // for testing
+}
+
+// Sign implements RequestSigner.
+func (s *SigV4Signer) Sign(req *http.Request, body []byte) error {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	signed := map[string]string{
+		"host":       requestHost(req),
+		"x-amz-date": amzDate,
+	}
+	if s.SessionToken != "" {
+		signed["x-amz-security-token"] = s.SessionToken
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(signed[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalURI(req.URL.EscapedPath()),
+		sigV4CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+// sigV4CanonicalURI encodes every segment of the already escaped path once
+// more, as required for services other than S3.
+func sigV4CanonicalURI(escapedPath string) string {
+	if escapedPath == "" {
+		return "/"
+	}
+	segments := strings.Split(escapedPath, "/")
+	for i, seg := range segments {
+		segments[i] = sigV4Escape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// sigV4CanonicalQuery sorts the encoded parameters by name, then by value.
+func sigV4CanonicalQuery(query map[string][]string) string {
+	type param struct{ k, v string }
+	var params []param
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, param{sigV4Escape(k), sigV4Escape(v)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].k != params[j].k {
+			return params[i].k < params[j].k
+		}
+		return params[i].v < params[j].v
+	})
+	pairs := make([]string, 0, len(params))
+	for _, p := range params {
+		pairs = append(pairs, p.k+"="+p.v)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape percent-encodes every byte but the unreserved characters of
+// RFC 3986.
+func sigV4Escape(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0xf])
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigV4Signer(t *testing.T) {
+	// Test vectors from the AWS Signature Version 4 test suite.
+	s := &SigV4Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "service",
+		now:             func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+	for _, test := range []struct {
+		name string
+		url  string
+		exp  string
+	}{
+		{
+			name: "get-vanilla",
+			url:  "https://example.amazonaws.com/",
+			exp:  "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name: "get-vanilla-query-order-key-case",
+			url:  "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			exp:  "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			require.NoError(t, err)
+			require.NoError(t, s.Sign(req, nil))
+			require.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+			require.Equal(t, test.exp, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestNewRequestSigner(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		cfg    *config.RequestSigning
+		exp    RequestSigner
+		expErr bool
+	}{
+		{name: "disabled"},
+		{name: "no type", cfg: &config.RequestSigning{KeyID: "id", Secret: "s"}},
+		{
+			name: "hmac",
+			cfg:  &config.RequestSigning{Type: "HMAC-SHA256", KeyID: "id", Secret: "s"},
+			exp:  &HMACSigner{KeyID: "id", Secret: []byte("s")},
+		},
+		{
+			name: "sigv4",
+			cfg:  &config.RequestSigning{Type: "aws-sigv4", KeyID: "id", Secret: "s", Region: "r", Service: "svc"},
+			exp:  &SigV4Signer{AccessKeyID: "id", SecretAccessKey: "s", Region: "r", Service: "svc"},
+		},
+		{name: "missing secret", cfg: &config.RequestSigning{Type: "hmac-sha256", KeyID: "id"}, expErr: true},
+		{name: "sigv4 missing region", cfg: &config.RequestSigning{Type: "aws-sigv4", KeyID: "id", Secret: "s"}, expErr: true},
+		{name: "unknown type", cfg: &config.RequestSigning{Type: "rsa", KeyID: "id", Secret: "s"}, expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := NewRequestSigner(test.cfg)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, s)
+		})
+	}
+}
+
+func TestSignedRequests(t *testing.T) {
+	secret := []byte("secret")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		sum := sha256.Sum256(body)
+		require.Equal(t, "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]), r.Header.Get("Digest"))
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("(request-target): post /v1/security/users\n" +
+			"host: " + r.Host + "\n" +
+			"date: " + r.Header.Get("Date") + "\n" +
+			"digest: " + r.Header.Get("Digest")))
+		exp := `keyId="rpk",algorithm="hmac-sha256",headers="(request-target) host date digest",signature="` +
+			base64.StdEncoding.EncodeToString(mac.Sum(nil)) + `"`
+		if r.Header.Get("Signature") != exp {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	cl, err := NewAdminAPI([]string{ts.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+	cl.SetRequestSigner(&HMACSigner{KeyID: "rpk", Secret: secret})
+	require.NoError(t, cl.CreateUser(context.Background(), "user", "pass", ScramSha256))
+
+	cl.SetRequestSigner(&HMACSigner{KeyID: "rpk", Secret: []byte("wrong")})
+	require.Error(t, cl.CreateUser(context.Background(), "user", "pass", ScramSha256))
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// waitPollInterval is how often the WaitFor helpers poll the cluster.
+var waitPollInterval = 2 * time.Second
+
+// WaitForCondition calls cond every poll interval until it reports that it is
+// met, or until the context is done.
+//
+// Failures of cond in the ErrConnection, ErrTimeout or ErrServer category,
+// e.g. while a broker restarts, are retried. Any other failure is returned
+// immediately. Once the context is done, its error is returned along with the
+// last failure of cond, if any.
+//
+// The wait is only bounded by the context: callers should give it a deadline
+// unless they mean to wait as long as the operation takes.
+func WaitForCondition(
+	ctx context.Context, poll time.Duration, cond func(context.Context) (bool, error),
+) error {
+	var lastErr error
+	for {
+		met, err := cond(ctx)
+		switch {
+		case err == nil && met:
+			return nil
+		case err == nil:
+			lastErr = nil
+		case ctx.Err() != nil:
+			// A request interrupted by the context says nothing new.
+		case errors.Is(err, ErrConnection), errors.Is(err, ErrTimeout), errors.Is(err, ErrServer):
+			lastErr = err
+		default:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// WaitForDecommission waits until the node is decommissioned, i.e. until it is
+// no longer listed in the brokers of the cluster. The returned error reports
+// the last membership status of the node if the context is done first.
+func (a *AdminAPI) WaitForDecommission(ctx context.Context, nodeID int) error {
+	var status MembershipStatus
+	err := WaitForCondition(ctx, waitPollInterval, func(ctx context.Context) (bool, error) {
+		brokers, err := a.Brokers(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, b := range brokers {
+			if b.NodeID == nodeID {
+				status = b.MembershipStatus
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil && status != "" {
+		return fmt.Errorf("node %d is not decommissioned, its membership status is %s: %w", nodeID, status, err)
+	}
+	return err
+}
+
+// WaitForReconfigurationDone waits until no partition movement matching the
+// filter is in progress. A nil filter matches every movement. The returned
+// error reports the number of movements still in progress if the context is
+// done first.
+func (a *AdminAPI) WaitForReconfigurationDone(
+	ctx context.Context, filter func(Reconfiguration) bool,
+) error {
+	var pending int
+	err := WaitForCondition(ctx, waitPollInterval, func(ctx context.Context) (bool, error) {
+		reconfigurations, err := a.Reconfigurations(ctx)
+		if err != nil {
+			return false, err
+		}
+		pending = 0
+		for _, r := range reconfigurations {
+			if filter == nil || filter(r) {
+				pending++
+			}
+		}
+		return pending == 0, nil
+	})
+	if err != nil && pending > 0 {
+		return fmt.Errorf("%d partition movements are still in progress: %w", pending, err)
+	}
+	return err
+}
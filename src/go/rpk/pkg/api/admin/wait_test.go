@@ -0,0 +1,138 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForCondition(t *testing.T) {
+	errConn := &transportError{errors.New("connection refused"), ErrConnection}
+
+	t.Run("met", func(t *testing.T) {
+		var calls int
+		err := WaitForCondition(context.Background(), time.Millisecond, func(context.Context) (bool, error) {
+			calls++
+			switch calls {
+			case 1:
+				return false, nil
+			case 2:
+				return false, errConn
+			}
+			return true, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		errInvalid := errors.New("invalid")
+		err := WaitForCondition(context.Background(), time.Millisecond, func(context.Context) (bool, error) {
+			return false, errInvalid
+		})
+		require.Equal(t, errInvalid, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := WaitForCondition(ctx, time.Millisecond, func(context.Context) (bool, error) {
+			return false, errConn
+		})
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Contains(t, err.Error(), "connection refused")
+	})
+}
+
+func TestWaitForDecommission(t *testing.T) {
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+
+	// broker serves the given brokers in turn, repeating the last ones.
+	broker := func(responses ...string) *httptest.Server {
+		var calls int32
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/brokers" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			i := int(atomic.AddInt32(&calls, 1)) - 1
+			if i >= len(responses) {
+				i = len(responses) - 1
+			}
+			w.Write([]byte(responses[i]))
+		}))
+	}
+
+	t.Run("decommissioned", func(t *testing.T) {
+		b := broker(
+			`[{"node_id":0,"membership_status":"active"},{"node_id":1,"membership_status":"draining"}]`,
+			`[{"node_id":0,"membership_status":"active"}]`,
+		)
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, cl.WaitForDecommission(context.Background(), 1))
+	})
+
+	t.Run("still draining", func(t *testing.T) {
+		b := broker(`[{"node_id":0,"membership_status":"active"},{"node_id":1,"membership_status":"draining"}]`)
+		defer b.Close()
+		cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err = cl.WaitForDecommission(ctx, 1)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Contains(t, err.Error(), "draining")
+	})
+}
+
+func TestWaitForReconfigurationDone(t *testing.T) {
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+
+	var calls int32
+	b := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/partitions/reconfigurations" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Write([]byte(`[{"ns":"kafka","topic":"foo","partition":0},{"ns":"kafka","topic":"bar","partition":1}]`))
+			return
+		}
+		w.Write([]byte(`[{"ns":"kafka","topic":"bar","partition":1}]`))
+	}))
+	defer b.Close()
+	cl, err := NewAdminAPI([]string{b.URL}, BasicCredentials{}, nil)
+	require.NoError(t, err)
+
+	err = cl.WaitForReconfigurationDone(context.Background(), func(r Reconfiguration) bool {
+		return r.Topic == "foo"
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = cl.WaitForReconfigurationDone(ctx, nil)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Contains(t, err.Error(), "1 partition movements")
+}
@@ -0,0 +1,388 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// avroSchema is a parsed Avro schema. Named types are shared through the
+// names of their parser, so recursive types are supported.
+type avroSchema struct {
+	typ     string // primitive type, "record", "enum", "array", "map", "fixed" or "union"
+	name    string // full name of named types
+	fields  []avroField
+	symbols []string
+	items   *avroSchema // array items and map values
+	union   []*avroSchema
+	size    int
+}
+
+type avroField struct {
+	name   string
+	schema *avroSchema
+}
+
+// avroParser parses Avro schemas, keeping the named types it has seen so
+// that later schemas can refer to them.
+type avroParser struct {
+	names map[string]*avroSchema
+}
+
+func newAvroParser() *avroParser {
+	return &avroParser{names: make(map[string]*avroSchema)}
+}
+
+func (p *avroParser) parse(schema string) (*avroSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(schema), &raw); err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %v", err)
+	}
+	return p.parseValue(raw, "")
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+func (p *avroParser) parseValue(raw interface{}, namespace string) (*avroSchema, error) {
+	switch v := raw.(type) {
+	case string:
+		if avroPrimitives[v] {
+			return &avroSchema{typ: v}, nil
+		}
+		if s, ok := p.names[fullName(v, namespace)]; ok {
+			return s, nil
+		}
+		if s, ok := p.names[v]; ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("unknown avro type %q", v)
+
+	case []interface{}:
+		u := &avroSchema{typ: "union"}
+		for _, r := range v {
+			s, err := p.parseValue(r, namespace)
+			if err != nil {
+				return nil, err
+			}
+			u.union = append(u.union, s)
+		}
+		return u, nil
+
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	}
+	return nil, fmt.Errorf("invalid avro schema element %v", raw)
+}
+
+func (p *avroParser) parseComplex(v map[string]interface{}, namespace string) (*avroSchema, error) {
+	typ, ok := v["type"].(string)
+	if !ok {
+		// e.g. {"type": {"type": "array", ...}}
+		if inner, exists := v["type"]; exists {
+			return p.parseValue(inner, namespace)
+		}
+		return nil, errors.New("avro schema element without type")
+	}
+
+	named := func() (*avroSchema, string, error) {
+		name, _ := v["name"].(string)
+		if name == "" {
+			return nil, "", fmt.Errorf("avro %s without name", typ)
+		}
+		if ns, ok := v["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		full := fullName(name, namespace)
+		if i := strings.LastIndexByte(full, '.'); i >= 0 {
+			namespace = full[:i]
+		} else {
+			namespace = ""
+		}
+		s := &avroSchema{typ: typ, name: full}
+		p.names[full] = s
+		return s, namespace, nil
+	}
+
+	switch typ {
+	case "record", "error":
+		s, ns, err := named()
+		if err != nil {
+			return nil, err
+		}
+		s.typ = "record"
+		fields, _ := v["fields"].([]interface{})
+		for _, f := range fields {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid field in avro record %s", s.name)
+			}
+			name, _ := fm["name"].(string)
+			fs, err := p.parseValue(fm["type"], ns)
+			if err != nil {
+				return nil, fmt.Errorf("field %s of avro record %s: %v", name, s.name, err)
+			}
+			s.fields = append(s.fields, avroField{name: name, schema: fs})
+		}
+		return s, nil
+
+	case "enum":
+		s, _, err := named()
+		if err != nil {
+			return nil, err
+		}
+		symbols, _ := v["symbols"].([]interface{})
+		for _, sym := range symbols {
+			str, _ := sym.(string)
+			s.symbols = append(s.symbols, str)
+		}
+		return s, nil
+
+	case "fixed":
+		s, _, err := named()
+		if err != nil {
+			return nil, err
+		}
+		size, _ := v["size"].(float64)
+		s.size = int(size)
+		return s, nil
+
+	case "array", "map":
+		key := "items"
+		if typ == "map" {
+			key = "values"
+		}
+		items, err := p.parseValue(v[key], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroSchema{typ: typ, items: items}, nil
+	}
+
+	// A primitive with attributes, e.g. a logical type.
+	return p.parseValue(typ, namespace)
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// orderedObject is a JSON object that keeps the order of its keys, so that
+// decoded records print their fields in schema order.
+type orderedObject []objectField
+
+type objectField struct {
+	key   string
+	value interface{}
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, f := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		k, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, k...)
+		buf = append(buf, ':')
+		buf = append(buf, v...)
+	}
+	return append(buf, '}'), nil
+}
+
+var errShortBuffer = errors.New("unexpected end of data")
+
+// avroReader reads the Avro binary encoding.
+type avroReader struct {
+	b []byte
+}
+
+func (r *avroReader) long() (int64, error) {
+	u, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, errShortBuffer
+	}
+	r.b = r.b[n:]
+	return int64(u>>1) ^ -int64(u&1), nil
+}
+
+func (r *avroReader) bytes() ([]byte, error) {
+	l, err := r.long()
+	if err != nil {
+		return nil, err
+	}
+	if l < 0 || int64(len(r.b)) < l {
+		return nil, errShortBuffer
+	}
+	b := r.b[:l]
+	r.b = r.b[l:]
+	return b, nil
+}
+
+func (r *avroReader) fixed(n int) ([]byte, error) {
+	if len(r.b) < n {
+		return nil, errShortBuffer
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+// decodeAvro decodes a value of the schema, returning a value that can be
+// marshaled to JSON along with the rest of the data.
+func decodeAvro(s *avroSchema, data []byte) (interface{}, error) {
+	r := &avroReader{b: data}
+	v, err := r.decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.b) > 0 {
+		return nil, fmt.Errorf("%d trailing bytes after the avro value", len(r.b))
+	}
+	return v, nil
+}
+
+func (r *avroReader) decode(s *avroSchema) (interface{}, error) {
+	switch s.typ {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.fixed(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		return r.long()
+	case "float":
+		b, err := r.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return jsonFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))), nil
+	case "double":
+		b, err := r.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return jsonFloat(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case "bytes", "string":
+		b, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "fixed":
+		b, err := r.fixed(s.size)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "enum":
+		i, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.symbols) {
+			return nil, fmt.Errorf("enum %s index %d out of range", s.name, i)
+		}
+		return s.symbols[i], nil
+	case "union":
+		i, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.union) {
+			return nil, fmt.Errorf("union index %d out of range", i)
+		}
+		return r.decode(s.union[i])
+	case "record":
+		o := make(orderedObject, 0, len(s.fields))
+		for _, f := range s.fields {
+			v, err := r.decode(f.schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", s.name, f.name, err)
+			}
+			o = append(o, objectField{f.name, v})
+		}
+		return o, nil
+	case "array", "map":
+		var (
+			arr = []interface{}{}
+			obj = orderedObject{}
+		)
+		for {
+			n, err := r.long()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				break
+			}
+			if n < 0 { // followed by the size of the block in bytes
+				n = -n
+				if _, err := r.long(); err != nil {
+					return nil, err
+				}
+			}
+			for ; n > 0; n-- {
+				var key []byte
+				if s.typ == "map" {
+					if key, err = r.bytes(); err != nil {
+						return nil, err
+					}
+				}
+				v, err := r.decode(s.items)
+				if err != nil {
+					return nil, err
+				}
+				if s.typ == "map" {
+					obj = append(obj, objectField{string(key), v})
+				} else {
+					arr = append(arr, v)
+				}
+			}
+		}
+		if s.typ == "map" {
+			return obj, nil
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("unsupported avro type %q", s.typ)
+}
+
+// jsonFloat marshals NaN and infinities, which JSON cannot represent, as
+// strings.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return json.Marshal(fmt.Sprint(v))
+	}
+	return json.Marshal(v)
+}
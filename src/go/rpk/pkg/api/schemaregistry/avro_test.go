@@ -0,0 +1,103 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func appendZigzag(b []byte, v int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutVarint(buf, v)]...)
+}
+
+func appendAvroString(b []byte, s string) []byte {
+	return append(appendZigzag(b, int64(len(s))), s...)
+}
+
+const userSchema = `{
+  "type": "record",
+  "name": "User",
+  "namespace": "com.example",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "int"},
+    {"name": "email", "type": ["null", "string"]},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["USER", "ADMIN"]}},
+    {"name": "props", "type": {"type": "map", "values": "long"}},
+    {"name": "score", "type": "double"},
+    {"name": "friend", "type": ["null", "User"]}
+  ]
+}`
+
+func TestDecodeAvro(t *testing.T) {
+	s, err := newAvroParser().parse(userSchema)
+	require.NoError(t, err)
+
+	var b []byte
+	b = appendAvroString(b, "alice")
+	b = appendZigzag(b, 30)
+	b = appendZigzag(b, 1) // email: string branch
+	b = appendAvroString(b, "a@example.com")
+	b = appendZigzag(b, 2) // tags
+	b = appendAvroString(b, "x")
+	b = appendAvroString(b, "y")
+	b = appendZigzag(b, 0)
+	b = appendZigzag(b, 1)  // kind: ADMIN
+	b = appendZigzag(b, -1) // props: a block with its size in bytes
+	b = appendZigzag(b, 2)
+	b = appendAvroString(b, "k")
+	b = appendZigzag(b, -5)
+	b = appendZigzag(b, 0)
+	score := make([]byte, 8)
+	binary.LittleEndian.PutUint64(score, math.Float64bits(1.5))
+	b = append(b, score...)
+	b = appendZigzag(b, 1) // friend: a recursive User
+	b = appendAvroString(b, "bob")
+	b = appendZigzag(b, 40)
+	b = appendZigzag(b, 0) // no email
+	b = appendZigzag(b, 0) // no tags
+	b = appendZigzag(b, 0) // USER
+	b = appendZigzag(b, 0) // no props
+	b = append(b, make([]byte, 8)...)
+	b = appendZigzag(b, 0) // no friend
+
+	v, err := decodeAvro(s, b)
+	require.NoError(t, err)
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"name":"alice","age":30,"email":"a@example.com","tags":["x","y"],"kind":"ADMIN","props":{"k":-5},"score":1.5,`+
+			`"friend":{"name":"bob","age":40,"email":null,"tags":[],"kind":"USER","props":{},"score":0,"friend":null}}`,
+		string(out))
+
+	_, err = decodeAvro(s, b[:len(b)-3])
+	require.Error(t, err)
+	_, err = decodeAvro(s, append(b, 0))
+	require.Error(t, err)
+}
+
+func TestParseAvroInvalid(t *testing.T) {
+	for _, schema := range []string{
+		`{`,
+		`"Unknown"`,
+		`{"type": "record", "fields": []}`,
+		`{"type": "record", "name": "R", "fields": [{"name": "a", "type": "Missing"}]}`,
+	} {
+		_, err := newAvroParser().parse(schema)
+		require.Error(t, err, schema)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package schemaregistry provides a client to fetch schemas from Redpanda's
+// schema registry, and decoders for records serialized with them.
+package schemaregistry
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// These are the schema types of the schema registry. An empty type is Avro.
+const (
+	TypeAvro     = "AVRO"
+	TypeProtobuf = "PROTOBUF"
+	TypeJSON     = "JSON"
+)
+
+// Reference is a reference of a schema to another schema, e.g. an imported
+// protobuf file.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema is a schema of the schema registry.
+type Schema struct {
+	Schema     string      `json:"schema"`
+	Type       string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// SchemaType returns the type of the schema, defaulting to Avro.
+func (s Schema) SchemaType() string {
+	if s.Type == "" {
+		return TypeAvro
+	}
+	return strings.ToUpper(s.Type)
+}
+
+// Client fetches schemas from the schema registry. Schemas are immutable, so
+// they are cached for the lifetime of the client.
+type Client struct {
+	urls     []string
+	cl       *http.Client
+	username string
+	password string
+
+	mu       sync.Mutex
+	byID     map[int]Schema
+	byRef    map[Reference]Schema
+	decoders map[int]*decoder
+}
+
+// NewClient returns a client for the schema registry at the given URLs,
+// which are tried in order. URLs without a scheme default to http.
+func NewClient(urls []string, tlsConfig *tls.Config, username, password string) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("at least one schema registry url is required")
+	}
+	cl := &Client{
+		cl:       &http.Client{Timeout: 10 * time.Second},
+		username: username,
+		password: password,
+		byID:     make(map[int]Schema),
+		byRef:    make(map[Reference]Schema),
+		decoders: make(map[int]*decoder),
+	}
+	if tlsConfig != nil {
+		cl.cl.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	for _, u := range urls {
+		u = strings.TrimSuffix(strings.TrimSpace(u), "/")
+		if !strings.Contains(u, "://") {
+			scheme := "http"
+			if tlsConfig != nil {
+				scheme = "https"
+			}
+			u = scheme + "://" + u
+		}
+		if _, err := url.Parse(u); err != nil {
+			return nil, fmt.Errorf("invalid schema registry url %q: %v", u, err)
+		}
+		cl.urls = append(cl.urls, u)
+	}
+	return cl, nil
+}
+
+// SchemaByID returns the schema with the given ID.
+func (c *Client) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	c.mu.Lock()
+	s, ok := c.byID[id]
+	c.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), &s); err != nil {
+		return s, err
+	}
+	c.mu.Lock()
+	c.byID[id] = s
+	c.mu.Unlock()
+	return s, nil
+}
+
+// SchemaByReference returns the schema of a reference.
+func (c *Client) SchemaByReference(ctx context.Context, ref Reference) (Schema, error) {
+	key := Reference{Subject: ref.Subject, Version: ref.Version}
+	c.mu.Lock()
+	s, ok := c.byRef[key]
+	c.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+	path := fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(ref.Subject), ref.Version)
+	if err := c.get(ctx, path, &s); err != nil {
+		return s, err
+	}
+	c.mu.Lock()
+	c.byRef[key] = s
+	c.mu.Unlock()
+	return s, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, into interface{}) error {
+	var err error
+	for _, u := range c.urls {
+		if err = c.getOne(ctx, u+path, into); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		var re *ResponseError
+		if errors.As(err, &re) && re.StatusCode < http.StatusInternalServerError {
+			return err // the other URLs would answer the same
+		}
+	}
+	return err
+}
+
+func (c *Client) getOne(ctx context.Context, u string, into interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json, application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	res, err := c.cl.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to request %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read the response of %s: %w", u, err)
+	}
+	if res.StatusCode/100 != 2 {
+		re := &ResponseError{URL: u, StatusCode: res.StatusCode}
+		var e struct {
+			Code    int    `json:"error_code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &e) == nil {
+			re.Message = e.Message
+		}
+		return re
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("unable to decode the response of %s: %w", u, err)
+	}
+	return nil
+}
+
+// ResponseError is a non 2xx response of the schema registry.
+type ResponseError struct {
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("request %s failed: %s", e.URL, msg)
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrNoSchemaID is returned when decoding data that does not start with the
+// header of the schema registry wire format.
+var ErrNoSchemaID = errors.New("data is not prefixed with a schema ID")
+
+// decoder decodes the data serialized with one schema.
+type decoder struct {
+	typ   string
+	avro  *avroSchema
+	proto *protoTypes
+	file  *protoFile
+}
+
+// Decode decodes data serialized with the schema registry wire format: a zero
+// byte, the big endian schema ID and, for protobuf, the indexes of the message
+// in its file. The schema must be of the given type. The returned value
+// marshals to JSON.
+func (c *Client) Decode(ctx context.Context, data []byte, typ string) (interface{}, error) {
+	if len(data) < 5 || data[0] != 0 {
+		return nil, ErrNoSchemaID
+	}
+	id := int(binary.BigEndian.Uint32(data[1:5]))
+	data = data[5:]
+
+	d, err := c.decoder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.typ != typ {
+		return nil, fmt.Errorf("schema %d is %s, not %s", id, d.typ, typ)
+	}
+
+	switch d.typ {
+	case TypeAvro:
+		return decodeAvro(d.avro, data)
+	case TypeProtobuf:
+		r := &protoReader{b: data}
+		indexes, err := r.messageIndexes()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the message indexes: %w", err)
+		}
+		m, err := messageByIndexes(d.file, indexes)
+		if err != nil {
+			return nil, fmt.Errorf("schema %d: %w", id, err)
+		}
+		return d.proto.decodeProto(m, r.b)
+	}
+	return nil, fmt.Errorf("unsupported schema type %s", d.typ)
+}
+
+// messageIndexes reads the zigzag encoded count and indexes of the message
+// of the wire format. A zero count is the first message of the file.
+func (r *protoReader) messageIndexes() ([]int, error) {
+	zigzag := func() (int, error) {
+		u, err := r.varint()
+		return int(int64(u>>1) ^ -int64(u&1)), err
+	}
+	n, err := zigzag()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > len(r.b) {
+		return nil, fmt.Errorf("invalid message index count %d", n)
+	}
+	indexes := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		idx, err := zigzag()
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid message index %d", idx)
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+func (c *Client) decoder(ctx context.Context, id int) (*decoder, error) {
+	c.mu.Lock()
+	d, ok := c.decoders[id]
+	c.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+
+	s, err := c.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get schema %d: %w", id, err)
+	}
+	d = &decoder{typ: s.SchemaType()}
+	switch d.typ {
+	case TypeAvro:
+		p := newAvroParser()
+		err = c.eachReference(ctx, s, make(map[Reference]bool), func(ref Schema) error {
+			_, err := p.parse(ref.Schema)
+			return err
+		})
+		if err == nil {
+			d.avro, err = p.parse(s.Schema)
+		}
+	case TypeProtobuf:
+		d.proto = newProtoTypes()
+		err = c.eachReference(ctx, s, make(map[Reference]bool), func(ref Schema) error {
+			_, err := d.proto.parse(ref.Schema)
+			return err
+		})
+		if err == nil {
+			d.file, err = d.proto.parse(s.Schema)
+		}
+	default:
+		err = fmt.Errorf("unsupported schema type %s", d.typ)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schema %d: %w", id, err)
+	}
+
+	c.mu.Lock()
+	c.decoders[id] = d
+	c.mu.Unlock()
+	return d, nil
+}
+
+// eachReference calls fn on the references of a schema, recursively and
+// depth first, so that a schema is seen after the schemas it refers to.
+func (c *Client) eachReference(
+	ctx context.Context, s Schema, seen map[Reference]bool, fn func(Schema) error,
+) error {
+	for _, ref := range s.References {
+		key := Reference{Subject: ref.Subject, Version: ref.Version}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rs, err := c.SchemaByReference(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("unable to get reference %s: %w", ref.Name, err)
+		}
+		if err := c.eachReference(ctx, rs, seen, fn); err != nil {
+			return err
+		}
+		if err := fn(rs); err != nil {
+			return fmt.Errorf("reference %s: %w", ref.Name, err)
+		}
+	}
+	return nil
+}
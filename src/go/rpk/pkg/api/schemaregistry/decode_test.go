@@ -0,0 +1,85 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+	)
+	responses := map[string]interface{}{
+		"/schemas/ids/1": Schema{Schema: `{"type": "record", "name": "R", "fields": [{"name": "money", "type": "Money"}]}`,
+			References: []Reference{{Name: "Money", Subject: "money", Version: 1}}},
+		"/subjects/money/versions/1": Schema{Schema: `{"type": "record", "name": "Money", "fields": [{"name": "cents", "type": "long"}]}`},
+		"/schemas/ids/2": Schema{Schema: `syntax = "proto3"; import "money.proto"; message A {} message B { common.Money money = 1; }`,
+			Type: TypeProtobuf, References: []Reference{{Name: "money.proto", Subject: "money-proto", Version: 3}}},
+		"/subjects/money-proto/versions/3": Schema{Schema: `syntax = "proto3"; package common; message Money { int64 cents = 1; }`,
+			Type: TypeProtobuf},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	cl, err := NewClient([]string{ts.URL}, nil, "", "")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	avro := []byte{0, 0, 0, 0, 1, 0x54} // cents: 42
+	for i := 0; i < 2; i++ {
+		v, err := cl.Decode(ctx, avro, TypeAvro)
+		require.NoError(t, err)
+		out, _ := json.Marshal(v)
+		require.Equal(t, `{"money":{"cents":42}}`, string(out))
+	}
+
+	// Message B is the second message of the file: one index, 1.
+	proto := []byte{0, 0, 0, 0, 2, 2, 2, 0x0a, 2, 0x08, 42}
+	v, err := cl.Decode(ctx, proto, TypeProtobuf)
+	require.NoError(t, err)
+	out, _ := json.Marshal(v)
+	require.Equal(t, `{"money":{"cents":42}}`, string(out))
+
+	for path, n := range requests {
+		require.Equal(t, 1, n, path)
+	}
+
+	_, err = cl.Decode(ctx, proto, TypeAvro)
+	require.Error(t, err)
+	_, err = cl.Decode(ctx, []byte("plain"), TypeAvro)
+	require.True(t, errors.Is(err, ErrNoSchemaID))
+
+	_, err = cl.Decode(ctx, []byte{0, 0, 0, 0, 9, 0}, TypeAvro)
+	var re *ResponseError
+	require.True(t, errors.As(err, &re))
+	require.Equal(t, http.StatusNotFound, re.StatusCode)
+	require.Contains(t, err.Error(), "Schema not found")
+}
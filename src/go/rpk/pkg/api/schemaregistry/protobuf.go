@@ -0,0 +1,676 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file contains a small parser of .proto files, which only keeps what is
+// needed to decode messages: messages, their fields and enums. Options,
+// services, extensions and reserved ranges are skipped.
+
+type protoFile struct {
+	pkg      string
+	messages []*protoMessage // top level messages, in declaration order
+}
+
+type protoMessage struct {
+	name   string // full name, without a leading dot
+	fields map[int32]*protoField
+	order  []int32         // field numbers in declaration order
+	nested []*protoMessage // nested messages in declaration order
+}
+
+type protoField struct {
+	name     string
+	number   int32
+	typ      string // scalar type or, once resolved, the full name of the message or enum
+	repeated bool
+	scope    string // full name of the enclosing message, to resolve typ
+
+	mapKey, mapValue *protoField // if the field is a map
+}
+
+type protoEnum struct {
+	name   string
+	values map[int32]string
+}
+
+// protoTypes are the messages and enums of parsed files, keyed by full name.
+type protoTypes struct {
+	messages map[string]*protoMessage
+	enums    map[string]*protoEnum
+}
+
+func newProtoTypes() *protoTypes {
+	return &protoTypes{
+		messages: make(map[string]*protoMessage),
+		enums:    make(map[string]*protoEnum),
+	}
+}
+
+type protoTokenizer struct {
+	s   string
+	tok string
+}
+
+func (t *protoTokenizer) next() string {
+	s := t.s
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(s, "//"):
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				s = s[i:]
+			} else {
+				s = ""
+			}
+			continue
+		case strings.HasPrefix(s, "/*"):
+			if i := strings.Index(s, "*/"); i >= 0 {
+				s = s[i+2:]
+			} else {
+				s = ""
+			}
+			continue
+		}
+		break
+	}
+	if s == "" {
+		t.s, t.tok = "", ""
+		return ""
+	}
+	n := 1
+	switch c := s[0]; {
+	case c == '"' || c == '\'':
+		for n < len(s) && s[n] != c {
+			if s[n] == '\\' {
+				n++
+			}
+			n++
+		}
+		n++
+	case c == '_' || c == '.' || c == '-' || c == '+' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+		for n < len(s) && (s[n] == '_' || s[n] == '.' || unicode.IsLetter(rune(s[n])) || unicode.IsDigit(rune(s[n]))) {
+			n++
+		}
+	}
+	if n > len(s) {
+		n = len(s)
+	}
+	t.tok, t.s = s[:n], s[n:]
+	return t.tok
+}
+
+func (t *protoTokenizer) expect(want string) error {
+	if got := t.next(); got != want {
+		return fmt.Errorf("expected %q, got %q", want, got)
+	}
+	return nil
+}
+
+// skipStatement skips tokens up to and including the end of the current
+// statement, which is either a ";" or a balanced "{...}" block.
+func (t *protoTokenizer) skipStatement() {
+	depth := 0
+	for tok := t.next(); tok != ""; tok = t.next() {
+		switch tok {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				return
+			}
+		case ";":
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// skipOptions skips a "[...]" list of field options, if any, and the ";"
+// ending the field.
+func (t *protoTokenizer) endField() error {
+	tok := t.next()
+	if tok == "[" {
+		for tok != "]" && tok != "" {
+			tok = t.next()
+		}
+		tok = t.next()
+	}
+	if tok != ";" {
+		return fmt.Errorf("expected \";\", got %q", tok)
+	}
+	return nil
+}
+
+// parse adds the messages and enums of a .proto file.
+func (pt *protoTypes) parse(src string) (*protoFile, error) {
+	t := &protoTokenizer{s: src}
+	f := &protoFile{}
+	for tok := t.next(); tok != ""; tok = t.next() {
+		switch tok {
+		case "package":
+			f.pkg = t.next()
+			if err := t.expect(";"); err != nil {
+				return nil, err
+			}
+		case "message":
+			m, err := pt.parseMessage(t, f.pkg)
+			if err != nil {
+				return nil, err
+			}
+			f.messages = append(f.messages, m)
+		case "enum":
+			if err := pt.parseEnum(t, f.pkg); err != nil {
+				return nil, err
+			}
+		case ";":
+		default: // syntax, import, option, service, extend
+			t.skipStatement()
+		}
+	}
+	return f, nil
+}
+
+func joinName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func (pt *protoTypes) parseMessage(t *protoTokenizer, scope string) (*protoMessage, error) {
+	m := &protoMessage{
+		name:   joinName(scope, t.next()),
+		fields: make(map[int32]*protoField),
+	}
+	pt.messages[m.name] = m
+	if err := t.expect("{"); err != nil {
+		return nil, fmt.Errorf("message %s: %v", m.name, err)
+	}
+	if err := pt.parseBody(t, m); err != nil {
+		return nil, fmt.Errorf("message %s: %v", m.name, err)
+	}
+	return m, nil
+}
+
+// parseBody parses the body of a message or of a oneof, up to its closing
+// brace.
+func (pt *protoTypes) parseBody(t *protoTokenizer, m *protoMessage) error {
+	for {
+		tok := t.next()
+		switch tok {
+		case "":
+			return errors.New("unexpected end of file")
+		case "}":
+			return nil
+		case ";":
+		case "message":
+			n, err := pt.parseMessage(t, m.name)
+			if err != nil {
+				return err
+			}
+			m.nested = append(m.nested, n)
+		case "enum":
+			if err := pt.parseEnum(t, m.name); err != nil {
+				return err
+			}
+		case "oneof":
+			t.next() // name
+			if err := t.expect("{"); err != nil {
+				return err
+			}
+			if err := pt.parseBody(t, m); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions", "extend", "group":
+			t.skipStatement()
+		default:
+			f := &protoField{scope: m.name}
+			switch tok {
+			case "repeated":
+				f.repeated = true
+				tok = t.next()
+			case "optional", "required":
+				tok = t.next()
+			}
+			if tok == "map" {
+				if err := t.expect("<"); err != nil {
+					return err
+				}
+				f.mapKey = &protoField{name: "key", number: 1, typ: t.next(), scope: m.name}
+				if err := t.expect(","); err != nil {
+					return err
+				}
+				f.mapValue = &protoField{name: "value", number: 2, typ: t.next(), scope: m.name}
+				if err := t.expect(">"); err != nil {
+					return err
+				}
+				f.repeated = true
+			} else {
+				f.typ = tok
+			}
+			f.name = t.next()
+			if err := t.expect("="); err != nil {
+				return fmt.Errorf("field %s: %v", f.name, err)
+			}
+			n, err := strconv.ParseInt(t.next(), 0, 32)
+			if err != nil {
+				return fmt.Errorf("field %s: invalid number: %v", f.name, err)
+			}
+			f.number = int32(n)
+			if err := t.endField(); err != nil {
+				return fmt.Errorf("field %s: %v", f.name, err)
+			}
+			m.fields[f.number] = f
+			m.order = append(m.order, f.number)
+		}
+	}
+}
+
+func (pt *protoTypes) parseEnum(t *protoTokenizer, scope string) error {
+	e := &protoEnum{name: joinName(scope, t.next()), values: make(map[int32]string)}
+	pt.enums[e.name] = e
+	if err := t.expect("{"); err != nil {
+		return fmt.Errorf("enum %s: %v", e.name, err)
+	}
+	for {
+		tok := t.next()
+		switch tok {
+		case "":
+			return fmt.Errorf("enum %s: unexpected end of file", e.name)
+		case "}":
+			return nil
+		case ";":
+		case "option", "reserved":
+			t.skipStatement()
+		default:
+			if err := t.expect("="); err != nil {
+				return fmt.Errorf("enum %s value %s: %v", e.name, tok, err)
+			}
+			n, err := strconv.ParseInt(t.next(), 0, 32)
+			if err != nil {
+				return fmt.Errorf("enum %s value %s: invalid number: %v", e.name, tok, err)
+			}
+			if _, exists := e.values[int32(n)]; !exists { // allow_alias keeps the first name
+				e.values[int32(n)] = tok
+			}
+			if err := t.endField(); err != nil {
+				return fmt.Errorf("enum %s value %s: %v", e.name, tok, err)
+			}
+		}
+	}
+}
+
+// resolve returns the full name of a message or enum type referenced from a
+// scope, following the protobuf scoping rules: the innermost scope wins.
+func (pt *protoTypes) resolve(name, scope string) (string, bool) {
+	exists := func(n string) bool {
+		_, m := pt.messages[n]
+		_, e := pt.enums[n]
+		return m || e
+	}
+	if strings.HasPrefix(name, ".") {
+		return name[1:], exists(name[1:])
+	}
+	for {
+		if n := joinName(scope, name); exists(n) {
+			return n, true
+		}
+		if scope == "" {
+			return name, false
+		}
+		if i := strings.LastIndexByte(scope, '.'); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+// messageByIndexes returns the message of a file at the given path of
+// indexes, as encoded in the schema registry wire format: the first index is
+// a top level message, the next ones are nested messages.
+func messageByIndexes(f *protoFile, indexes []int) (*protoMessage, error) {
+	if len(indexes) == 0 {
+		indexes = []int{0}
+	}
+	if indexes[0] >= len(f.messages) {
+		return nil, fmt.Errorf("message index %d out of range", indexes[0])
+	}
+	m := f.messages[indexes[0]]
+	for _, i := range indexes[1:] {
+		if i >= len(m.nested) {
+			return nil, fmt.Errorf("nested message index %d of %s out of range", i, m.name)
+		}
+		m = m.nested[i]
+	}
+	return m, nil
+}
+
+// protoReader reads the protobuf binary encoding.
+type protoReader struct {
+	b []byte
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	u, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, errShortBuffer
+	}
+	r.b = r.b[n:]
+	return u, nil
+}
+
+func (r *protoReader) fixed(n int) ([]byte, error) {
+	if len(r.b) < n {
+		return nil, errShortBuffer
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	l, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.b)) < l {
+		return nil, errShortBuffer
+	}
+	return r.fixed(int(l))
+}
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// decodeProto decodes a message, returning a value that can be marshaled to
+// JSON. Fields are keyed by name in declaration order; unknown fields are
+// keyed by number.
+func (pt *protoTypes) decodeProto(m *protoMessage, data []byte) (interface{}, error) {
+	r := &protoReader{b: data}
+	values := make(map[int32]interface{})
+	var unknown []int32
+	for len(r.b) > 0 {
+		tag, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		num, wire := int32(tag>>3), int(tag&7)
+		var f *protoField
+		if m != nil {
+			f = m.fields[num]
+		}
+		if f == nil {
+			v, err := r.raw(wire)
+			if err != nil {
+				return nil, err
+			}
+			if _, seen := values[num]; !seen {
+				unknown = append(unknown, num)
+			}
+			values[num] = v
+			continue
+		}
+
+		if f.repeated && f.mapKey == nil && wire == wireBytes && pt.isPackable(f) {
+			b, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			packed := &protoReader{b: b}
+			for len(packed.b) > 0 {
+				v, err := pt.value(packed, f, scalarWire(f.typ))
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", m.name, f.name, err)
+				}
+				values[num] = append(asSlice(values[num]), v)
+			}
+			continue
+		}
+
+		v, err := pt.value(r, f, wire)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", m.name, f.name, err)
+		}
+		switch {
+		case f.mapKey != nil:
+			entry, _ := v.(orderedObject)
+			var key, val interface{}
+			for _, e := range entry {
+				switch e.key {
+				case "key":
+					key = e.value
+				case "value":
+					val = e.value
+				}
+			}
+			obj, _ := values[num].(orderedObject)
+			values[num] = append(obj, objectField{fmt.Sprint(key), val})
+		case f.repeated:
+			values[num] = append(asSlice(values[num]), v)
+		default:
+			values[num] = v
+		}
+	}
+
+	var o orderedObject
+	if m != nil {
+		for _, num := range m.order {
+			if v, ok := values[num]; ok {
+				o = append(o, objectField{m.fields[num].name, v})
+			}
+		}
+	}
+	for _, num := range unknown {
+		o = append(o, objectField{strconv.Itoa(int(num)), values[num]})
+	}
+	if o == nil {
+		o = orderedObject{}
+	}
+	return o, nil
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// value decodes a non packed value of a field.
+func (pt *protoTypes) value(r *protoReader, f *protoField, wire int) (interface{}, error) {
+	if f.mapKey != nil {
+		b, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		entry := &protoMessage{
+			name:   f.name,
+			fields: map[int32]*protoField{1: f.mapKey, 2: f.mapValue},
+			order:  []int32{1, 2},
+		}
+		return pt.decodeProto(entry, b)
+	}
+	if isScalar(f.typ) {
+		if wire != scalarWire(f.typ) {
+			return nil, fmt.Errorf("wire type %d does not match the type %s", wire, f.typ)
+		}
+		return pt.scalar(r, f, wire)
+	}
+	full, ok := pt.resolve(f.typ, f.scope)
+	if e, isEnum := pt.enums[full]; ok && isEnum {
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if name, known := e.values[int32(n)]; known {
+			return name, nil
+		}
+		return int32(n), nil
+	}
+	if wire != wireBytes {
+		return r.raw(wire)
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	// Messages of unknown types, e.g. well known types whose file is not in
+	// the registry, are decoded without their schema.
+	return pt.decodeProto(pt.messages[full], b)
+}
+
+// raw decodes a value without knowing its type.
+func (r *protoReader) raw(wire int) (interface{}, error) {
+	switch wire {
+	case wireVarint:
+		return r.varint()
+	case wireFixed64:
+		b, err := r.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.Uint64(b), nil
+	case wireFixed32:
+		b, err := r.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.Uint32(b), nil
+	case wireBytes:
+		b, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		// Without a schema, bytes can be a string, a message or binary.
+		if sub, err := (&protoTypes{}).decodeProto(nil, b); err == nil && len(b) > 0 && !isPrintable(b) {
+			return sub, nil
+		}
+		if isPrintable(b) {
+			return string(b), nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+	return nil, fmt.Errorf("unsupported wire type %d", wire)
+}
+
+func isPrintable(b []byte) bool {
+	for _, r := range string(b) {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isScalar(typ string) bool {
+	switch typ {
+	case "double", "float", "int32", "int64", "uint32", "uint64", "sint32", "sint64",
+		"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string", "bytes":
+		return true
+	}
+	return false
+}
+
+// isPackable returns whether a repeated field can be packed: scalars that
+// are not length delimited, and enums.
+func (pt *protoTypes) isPackable(f *protoField) bool {
+	if isScalar(f.typ) {
+		return f.typ != "string" && f.typ != "bytes"
+	}
+	full, ok := pt.resolve(f.typ, f.scope)
+	_, isEnum := pt.enums[full]
+	return ok && isEnum
+}
+
+func scalarWire(typ string) int {
+	switch typ {
+	case "double", "fixed64", "sfixed64":
+		return wireFixed64
+	case "float", "fixed32", "sfixed32":
+		return wireFixed32
+	case "string", "bytes":
+		return wireBytes
+	}
+	return wireVarint
+}
+
+// scalar decodes a scalar value of a field.
+func (pt *protoTypes) scalar(r *protoReader, f *protoField, wire int) (interface{}, error) {
+	switch wire {
+	case wireFixed64:
+		b, err := r.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		u := binary.LittleEndian.Uint64(b)
+		switch f.typ {
+		case "double":
+			return jsonFloat(math.Float64frombits(u)), nil
+		case "sfixed64":
+			return int64(u), nil
+		}
+		return u, nil
+	case wireFixed32:
+		b, err := r.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		u := binary.LittleEndian.Uint32(b)
+		switch f.typ {
+		case "float":
+			return jsonFloat(math.Float32frombits(u)), nil
+		case "sfixed32":
+			return int32(u), nil
+		}
+		return u, nil
+	case wireBytes:
+		b, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		if f.typ == "bytes" {
+			return base64.StdEncoding.EncodeToString(b), nil
+		}
+		return string(b), nil
+	}
+	u, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	switch f.typ {
+	case "bool":
+		return u != 0, nil
+	case "int32":
+		return int32(u), nil
+	case "int64":
+		return int64(u), nil
+	case "uint32":
+		return uint32(u), nil
+	case "sint32", "sint64":
+		return int64(u>>1) ^ -int64(u&1), nil
+	}
+	return u, nil
+}
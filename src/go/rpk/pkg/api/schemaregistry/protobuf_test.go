@@ -0,0 +1,131 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const personProto = `
+syntax = "proto3";
+package test.v1;
+
+option go_package = "example.com/test";
+
+/* A person. */
+message Person {
+  string name = 1;
+  int32 id = 2 [deprecated = true];
+  repeated int32 scores = 3;
+  Phone phone = 4;
+  map<string, int64> counts = 5;
+  Kind kind = 6;
+  oneof contact {
+    string email = 7;
+  }
+  sint64 delta = 8;
+
+  message Phone {
+    string number = 1;
+  }
+  enum Kind {
+    UNKNOWN = 0;
+    ADMIN = 1;
+  }
+}
+
+message Other {
+  bool ok = 1;
+}
+`
+
+func appendUvarint(b []byte, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutUvarint(buf, v)]...)
+}
+
+func appendTag(b []byte, num, wire int) []byte {
+	return appendUvarint(b, uint64(num<<3|wire))
+}
+
+func appendBytes(b []byte, v []byte) []byte {
+	return append(appendUvarint(b, uint64(len(v))), v...)
+}
+
+func TestDecodeProto(t *testing.T) {
+	pt := newProtoTypes()
+	f, err := pt.parse(personProto)
+	require.NoError(t, err)
+
+	m, err := messageByIndexes(f, []int{0})
+	require.NoError(t, err)
+	require.Equal(t, "test.v1.Person", m.name)
+	m, err = messageByIndexes(f, []int{0, 0})
+	require.NoError(t, err)
+	require.Equal(t, "test.v1.Person.Phone", m.name)
+	_, err = messageByIndexes(f, []int{2})
+	require.Error(t, err)
+
+	var phone []byte
+	phone = appendTag(phone, 1, wireBytes)
+	phone = appendBytes(phone, []byte("555"))
+
+	var entry []byte
+	entry = appendTag(entry, 1, wireBytes)
+	entry = appendBytes(entry, []byte("k"))
+	entry = appendTag(entry, 2, wireVarint)
+	entry = appendUvarint(entry, 7)
+
+	var b []byte
+	b = appendTag(b, 1, wireBytes)
+	b = appendBytes(b, []byte("alice"))
+	b = appendTag(b, 2, wireVarint)
+	b = appendUvarint(b, 42)
+	b = appendTag(b, 3, wireBytes) // packed
+	b = appendBytes(b, []byte{1, 2, 3})
+	b = appendTag(b, 3, wireVarint) // and not packed
+	b = appendUvarint(b, 4)
+	b = appendTag(b, 4, wireBytes)
+	b = appendBytes(b, phone)
+	b = appendTag(b, 5, wireBytes)
+	b = appendBytes(b, entry)
+	b = appendTag(b, 6, wireVarint)
+	b = appendUvarint(b, 1)
+	b = appendTag(b, 8, wireVarint)
+	b = appendUvarint(b, 3) // zigzag -2
+	b = appendTag(b, 99, wireVarint)
+	b = appendUvarint(b, 5)
+
+	v, err := pt.decodeProto(f.messages[0], b)
+	require.NoError(t, err)
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"name":"alice","id":42,"scores":[1,2,3,4],"phone":{"number":"555"},"counts":{"k":7},"kind":"ADMIN","delta":-2,"99":5}`,
+		string(out))
+
+	_, err = pt.decodeProto(f.messages[0], b[:len(b)-1])
+	require.Error(t, err)
+}
+
+func TestParseProtoInvalid(t *testing.T) {
+	for _, src := range []string{
+		`message Foo {`,
+		`message Foo { string name 1; }`,
+		`message Foo { string name = x; }`,
+	} {
+		_, err := newProtoTypes().parse(src)
+		require.Error(t, err, src)
+	}
+}
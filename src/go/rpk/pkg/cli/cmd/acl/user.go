@@ -11,6 +11,7 @@ package acl
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
@@ -45,6 +46,7 @@ redpanda section of your redpanda.yaml.
 	cmd.AddCommand(newCreateUserCommand(fs))
 	cmd.AddCommand(newDeleteUserCommand(fs))
 	cmd.AddCommand(newListUsersCommand(fs))
+	cmd.AddCommand(newImportUsersCommand(fs))
 	return cmd
 }
 
@@ -95,26 +97,27 @@ acl help text for more info.
 			} else if userOld != "" { // backcompat
 				user = userOld
 			} else {
-				out.Die("missing required username argument")
+				out.DieUsage("missing required username argument")
 			}
 			if pass == "" {
 				if passOld == "" { // backcompat
-					out.Die("missing required --password")
+					out.DieUsage("missing required --password")
 				}
 				pass = passOld
 			}
 
-			switch strings.ToLower(mechanism) {
-			case "scram-sha-256":
-				mechanism = admin.ScramSha256
-			case "scram-sha-512":
-				mechanism = admin.ScramSha512
-			default:
-				out.Die("unsupported mechanism %q", mechanism)
-			}
+			mechanism, err = parseMechanism(mechanism)
+			out.MaybeDieErr(err)
 
 			err = cl.CreateUser(cmd.Context(), user, pass, mechanism)
 			out.MaybeDie(err, "unable to create user %q: %v", user, err)
+
+			// Users are replicated asynchronously; wait until all
+			// brokers know the user so that scripts can use it
+			// immediately.
+			if err := cl.WaitForUser(cmd.Context(), user); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
 			fmt.Printf("Created user %q.\n", user)
 		},
 	}
@@ -136,6 +139,19 @@ acl help text for more info.
 	return cmd
 }
 
+// parseMechanism returns the admin API name of a case insensitive SASL
+// mechanism.
+func parseMechanism(mechanism string) (string, error) {
+	switch strings.ToLower(mechanism) {
+	case "scram-sha-256":
+		return admin.ScramSha256, nil
+	case "scram-sha-512":
+		return admin.ScramSha512, nil
+	default:
+		return "", fmt.Errorf("unsupported mechanism %q", mechanism)
+	}
+}
+
 func newDeleteUserCommand(fs afero.Fs) *cobra.Command {
 	var oldUser string
 	cmd := &cobra.Command{
@@ -164,7 +180,7 @@ delete any ACLs that may exist for this user.
 			} else if len(oldUser) > 0 {
 				user = oldUser
 			} else {
-				out.Die("missing required username argument")
+				out.DieUsage("missing required username argument")
 			}
 
 			err = cl.DeleteUser(cmd.Context(), user)
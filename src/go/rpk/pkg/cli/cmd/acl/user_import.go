@@ -0,0 +1,326 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package acl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"gopkg.in/yaml.v3"
+)
+
+// importUser is a user in the file passed to 'rpk acl user import'.
+type importUser struct {
+	Name      string      `yaml:"name"`
+	Password  string      `yaml:"password"`
+	Mechanism string      `yaml:"mechanism"`
+	ACLs      []importACL `yaml:"acls"`
+}
+
+// importACL is a set of ACLs granted to (or denied from) an imported user,
+// following the same multiplying effect as the 'rpk acl create' flags.
+type importACL struct {
+	Operations          []string `yaml:"operations"`
+	Topics              []string `yaml:"topics"`
+	Groups              []string `yaml:"groups"`
+	Cluster             bool     `yaml:"cluster"`
+	TransactionalIDs    []string `yaml:"transactional_ids"`
+	ResourcePatternType string   `yaml:"resource_pattern_type"`
+	Hosts               []string `yaml:"hosts"`
+	Deny                bool     `yaml:"deny"`
+}
+
+type importUsersFile struct {
+	Users []importUser `yaml:"users"`
+}
+
+func newImportUsersCommand(fs afero.Fs) *cobra.Command {
+	var dry bool
+	cmd := &cobra.Command{
+		Use:   "import [FILE]",
+		Short: "Create or update SASL users and their ACLs from a file",
+		Long: `Create or update SASL users and their ACLs from a file.
+
+This command creates every user listed in the file, or updates the password
+and mechanism of users that already exist, and then creates the ACLs listed
+for each user. The status of every user is reported, and the command exits
+with a non-zero status if any user could not be imported.
+
+The file is either YAML or, if its name ends in .csv, CSV. YAML files have the
+following format; the mechanism defaults to scram-sha-256, and ACLs follow the
+same rules as 'rpk acl create' for the user:
+
+    users:
+      - name: app1
+        password: secret1
+        mechanism: scram-sha-512
+        acls:
+          - operations: [read, describe]
+            topics: [orders]
+            groups: [app1]
+          - operations: [write]
+            topics: [app1-]
+            resource_pattern_type: prefixed
+            hosts: [10.0.0.1]
+      - name: app2
+        password: secret2
+
+CSV files contain one user per line, without ACLs:
+
+    name,password,mechanism
+    app1,secret1,scram-sha-512
+    app2,secret2,
+
+The header line and the mechanism column are optional.
+
+Use --dry-run to validate the file and print what would be done without
+changing anything.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			raw, err := afero.ReadFile(fs, args[0])
+			out.MaybeDie(err, "unable to read %q: %v", args[0], err)
+			users, err := parseImportUsers(args[0], raw)
+			out.MaybeDie(err, "unable to parse %q: %v", args[0], err)
+			builders, err := validateImportUsers(users)
+			out.MaybeDieErr(err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+			existing, err := cl.ListUsers(cmd.Context())
+			out.MaybeDie(err, "unable to list users: %v", err)
+			exists := make(map[string]bool, len(existing))
+			for _, u := range existing {
+				exists[u] = true
+			}
+
+			var adm *kadm.Client
+			for _, b := range builders {
+				if len(b) > 0 && !dry {
+					adm, err = kafka.NewAdmin(fs, p, cfg)
+					out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+					defer adm.Close()
+					break
+				}
+			}
+
+			var exit1 bool
+			defer func() {
+				if exit1 {
+					os.Exit(1)
+				}
+			}()
+
+			tw := out.NewTable("user", "action", "acls", "status")
+			defer tw.Flush()
+			for i, u := range users {
+				action := "create"
+				if exists[u.Name] {
+					action = "update"
+				}
+				status := "OK"
+				if dry {
+					status = "dry run"
+				}
+				nacls := countACLs(u.ACLs)
+				if dry {
+					tw.Print(u.Name, action, nacls, status)
+					continue
+				}
+
+				if err := importUserWithACLs(cmd, cl, adm, u, builders[i], exists[u.Name]); err != nil {
+					status = err.Error()
+					exit1 = true
+				}
+				tw.Print(u.Name, action, nacls, status)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&dry, "dry-run", false, "Validate the file and print what would be done, without changing anything")
+	return cmd
+}
+
+// importUserWithACLs creates or updates the user and creates its ACLs.
+func importUserWithACLs(
+	cmd *cobra.Command,
+	cl *admin.AdminAPI,
+	adm *kadm.Client,
+	u importUser,
+	builders []*kadm.ACLBuilder,
+	exists bool,
+) error {
+	ctx := cmd.Context()
+	if exists {
+		if err := cl.UpdateUser(ctx, u.Name, u.Password, u.Mechanism); err != nil {
+			return fmt.Errorf("unable to update user: %v", err)
+		}
+	} else {
+		if err := cl.CreateUser(ctx, u.Name, u.Password, u.Mechanism); err != nil {
+			return fmt.Errorf("unable to create user: %v", err)
+		}
+		if err := cl.WaitForUser(ctx, u.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	var total, failed int
+	var firstErr error
+	for _, b := range builders {
+		results, err := adm.CreateACLs(ctx, b)
+		if err != nil {
+			return fmt.Errorf("unable to create ACLs: %v", err)
+		}
+		for _, r := range results {
+			total++
+			if r.Err != nil {
+				if failed++; firstErr == nil {
+					firstErr = r.Err
+				}
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to create %d of %d ACLs: %v", failed, total, kafka.ErrMessage(firstErr))
+	}
+	return nil
+}
+
+// parseImportUsers parses the users of a YAML or, if the filename ends in
+// .csv, CSV file.
+func parseImportUsers(filename string, raw []byte) ([]importUser, error) {
+	if strings.EqualFold(filepath.Ext(filename), ".csv") {
+		return parseImportUsersCSV(raw)
+	}
+	var f importUsersFile
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return f.Users, nil
+}
+
+func parseImportUsersCSV(raw []byte) ([]importUser, error) {
+	r := csv.NewReader(bytes.NewReader(raw))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+	var users []importUser
+	for first := true; ; first = false {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && strings.EqualFold(rec[0], "name") {
+			continue // header
+		}
+		if len(rec) < 2 || len(rec) > 3 {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("line %d: expected name,password[,mechanism], got %d fields", line, len(rec))
+		}
+		u := importUser{Name: rec[0], Password: rec[1]}
+		if len(rec) == 3 {
+			u.Mechanism = rec[2]
+		}
+		users = append(users, u)
+	}
+}
+
+// validateImportUsers checks every user before anything is imported, so that
+// a typo late in the file does not leave the cluster half imported. It fills
+// in the default mechanism and returns the ACL builders of each user.
+func validateImportUsers(users []importUser) ([][]*kadm.ACLBuilder, error) {
+	if len(users) == 0 {
+		return nil, errors.New("no users to import")
+	}
+	seen := make(map[string]bool, len(users))
+	builders := make([][]*kadm.ACLBuilder, len(users))
+	for i := range users {
+		u := &users[i]
+		switch {
+		case u.Name == "":
+			return nil, fmt.Errorf("user %d: invalid empty name", i+1)
+		case seen[u.Name]:
+			return nil, fmt.Errorf("user %q: duplicate user", u.Name)
+		case u.Password == "":
+			return nil, fmt.Errorf("user %q: invalid empty password", u.Name)
+		}
+		seen[u.Name] = true
+
+		if u.Mechanism == "" {
+			u.Mechanism = admin.ScramSha256
+		}
+		mechanism, err := parseMechanism(u.Mechanism)
+		if err != nil {
+			return nil, fmt.Errorf("user %q: %v", u.Name, err)
+		}
+		u.Mechanism = mechanism
+
+		for j, ia := range u.ACLs {
+			a := acls{
+				topics:              ia.Topics,
+				groups:              ia.Groups,
+				cluster:             ia.Cluster,
+				txnIDs:              ia.TransactionalIDs,
+				resourcePatternType: ia.ResourcePatternType,
+				operations:          ia.Operations,
+			}
+			principal := "User:" + u.Name
+			if ia.Deny {
+				a.denyPrincipals, a.denyHosts = []string{principal}, ia.Hosts
+			} else {
+				a.allowPrincipals, a.allowHosts = []string{principal}, ia.Hosts
+			}
+			b, err := a.createCreations()
+			if err != nil {
+				return nil, fmt.Errorf("user %q: acl %d: %v", u.Name, j+1, err)
+			}
+			builders[i] = append(builders[i], b)
+		}
+	}
+	return builders, nil
+}
+
+// countACLs returns the number of ACLs created for a user: one for every
+// combination of host, resource and operation.
+func countACLs(importACLs []importACL) int {
+	var n int
+	for _, a := range importACLs {
+		resources := len(a.Topics) + len(a.Groups) + len(a.TransactionalIDs)
+		if a.Cluster {
+			resources++
+		}
+		hosts := len(a.Hosts)
+		if hosts == 0 {
+			hosts = 1 // all hosts
+		}
+		n += hosts * resources * len(a.Operations)
+	}
+	return n
+}
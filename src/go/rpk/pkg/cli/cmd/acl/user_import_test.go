@@ -0,0 +1,126 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package acl
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportUsers(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		filename string
+		raw      string
+		exp      []importUser
+		expErr   bool
+	}{
+		{
+			name:     "yaml",
+			filename: "users.yaml",
+			raw: `users:
+  - name: app1
+    password: secret1
+    mechanism: scram-sha-512
+    acls:
+      - operations: [read, describe]
+        topics: [orders]
+        hosts: [10.0.0.1]
+  - name: app2
+    password: secret2
+`,
+			exp: []importUser{
+				{
+					Name:      "app1",
+					Password:  "secret1",
+					Mechanism: "scram-sha-512",
+					ACLs: []importACL{{
+						Operations: []string{"read", "describe"},
+						Topics:     []string{"orders"},
+						Hosts:      []string{"10.0.0.1"},
+					}},
+				},
+				{Name: "app2", Password: "secret2"},
+			},
+		},
+		{
+			name:     "yaml with unknown field",
+			filename: "users.yml",
+			raw:      "users:\n  - name: app1\n    pasword: secret1\n",
+			expErr:   true,
+		},
+		{
+			name:     "csv with header",
+			filename: "users.CSV",
+			raw:      "name,password,mechanism\napp1,secret1,scram-sha-512\n# comment\napp2, secret2\n",
+			exp: []importUser{
+				{Name: "app1", Password: "secret1", Mechanism: "scram-sha-512"},
+				{Name: "app2", Password: "secret2"},
+			},
+		},
+		{
+			name:     "csv with missing password",
+			filename: "users.csv",
+			raw:      "app1,secret1\napp2\n",
+			expErr:   true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			users, err := parseImportUsers(test.filename, []byte(test.raw))
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, users)
+		})
+	}
+}
+
+func TestValidateImportUsers(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		users := []importUser{
+			{Name: "app1", Password: "secret1", ACLs: []importACL{
+				{Operations: []string{"read", "describe"}, Topics: []string{"a", "b"}},
+				{Operations: []string{"write"}, Cluster: true, Hosts: []string{"h1", "h2"}, Deny: true},
+			}},
+			{Name: "app2", Password: "secret2", Mechanism: "SCRAM-sha-512"},
+		}
+		builders, err := validateImportUsers(users)
+		require.NoError(t, err)
+		require.Len(t, builders, 2)
+		require.Len(t, builders[0], 2)
+		require.Len(t, builders[1], 0)
+		require.Equal(t, admin.ScramSha256, users[0].Mechanism)
+		require.Equal(t, admin.ScramSha512, users[1].Mechanism)
+		require.Equal(t, 6, countACLs(users[0].ACLs))
+	})
+
+	for _, test := range []struct {
+		name  string
+		users []importUser
+	}{
+		{"no users", nil},
+		{"empty name", []importUser{{Password: "p"}}},
+		{"empty password", []importUser{{Name: "u"}}},
+		{"duplicate user", []importUser{{Name: "u", Password: "p"}, {Name: "u", Password: "p"}}},
+		{"unknown mechanism", []importUser{{Name: "u", Password: "p", Mechanism: "plain"}}},
+		{"invalid operation", []importUser{{Name: "u", Password: "p", ACLs: []importACL{
+			{Operations: []string{"fly"}, Topics: []string{"t"}},
+		}}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := validateImportUsers(test.users)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package cloud contains the commands to log in to Redpanda Cloud and to
+// use its managed clusters.
+package cloud
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cloud",
+		Short: "Interact with Redpanda Cloud",
+	}
+	cmd.AddCommand(
+		newLoginCommand(fs),
+		newLogoutCommand(fs),
+		newClusterCommand(fs),
+	)
+	return cmd
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud/auth"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud/cloudapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newClusterCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "List and use Redpanda Cloud managed clusters",
+	}
+	cmd.AddCommand(
+		newClusterListCommand(fs),
+		newClusterUseCommand(fs),
+	)
+	return cmd
+}
+
+// newAPIClient returns a cloud API client using the stored session.
+func newAPIClient(ctx context.Context, fs afero.Fs) (*cloudapi.Client, error) {
+	store, err := auth.NewStore(fs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize the session store: %w", err)
+	}
+	session, err := auth.LoadSession(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudapi.Client{URL: session.APIURL, Token: session.Token.AccessToken}, nil
+}
+
+func newClusterListCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the managed clusters you have access to",
+		Args:    cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cl, err := newAPIClient(cmd.Context(), fs)
+			out.MaybeDieErr(err)
+			clusters, err := cl.Clusters(cmd.Context())
+			out.MaybeDieErr(err)
+
+			tw := out.NewTable("NAME", "ID", "STATE")
+			defer tw.Flush()
+			for _, c := range clusters {
+				tw.Print(c.Name, c.ID, c.State)
+			}
+		},
+	}
+}
+
+func newClusterUseCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [NAME]",
+		Short: "Point rpk at a managed cluster",
+		Long: `Point rpk at a managed cluster.
+
+This command looks up the cluster by name or ID, and writes its Kafka and
+admin API addresses to the rpk section of the configuration file, with TLS
+enabled. Subsequent commands then talk to the cluster without needing
+--brokers or --api-urls. SASL credentials, if the cluster requires them, are
+not part of the session and still have to be configured.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+			cfg = cfg.FileOrDefaults() // we set fields in the raw file without writing env / flag overrides
+
+			cl, err := newAPIClient(cmd.Context(), fs)
+			out.MaybeDieErr(err)
+			c, err := cl.ClusterByName(cmd.Context(), args[0])
+			out.MaybeDieErr(err)
+
+			err = applyCluster(cfg, c)
+			out.MaybeDieErr(err)
+			err = cfg.Write(fs)
+			out.MaybeDie(err, "unable to write config: %v", err)
+			fmt.Printf("rpk now uses cluster %q (%s).\n", c.Name, c.ID)
+		},
+	}
+}
+
+// applyCluster sets the rpk Kafka and admin API addresses to those of the
+// managed cluster. TLS is always enabled, keeping any TLS files already
+// configured, since managed clusters only listen with TLS.
+func applyCluster(cfg *config.Config, c cloudapi.Cluster) error {
+	if len(c.KafkaAPI.SeedBrokers) == 0 {
+		return fmt.Errorf("cluster %q does not have any Kafka API address yet, its state is %q", c.Name, c.State)
+	}
+	cfg.Rpk.KafkaAPI.Brokers = c.KafkaAPI.SeedBrokers
+	if cfg.Rpk.KafkaAPI.TLS == nil {
+		cfg.Rpk.KafkaAPI.TLS = new(config.TLS)
+	}
+
+	var admins []string
+	for _, u := range c.AdminAPI.URLs {
+		host, err := hostPort(u)
+		if err != nil {
+			return err
+		}
+		admins = append(admins, host)
+	}
+	cfg.Rpk.AdminAPI.Addresses = admins
+	if len(admins) > 0 && cfg.Rpk.AdminAPI.TLS == nil {
+		cfg.Rpk.AdminAPI.TLS = new(config.TLS)
+	}
+	return nil
+}
+
+// hostPort strips the scheme of an admin API URL: rpk admin addresses are
+// host:port, and the scheme is derived from whether TLS is enabled.
+func hostPort(u string) (string, error) {
+	if !strings.Contains(u, "://") {
+		return u, nil
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse admin API URL %q: %w", u, err)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("admin API URL %q has no host", u)
+	}
+	return parsed.Host, nil
+}
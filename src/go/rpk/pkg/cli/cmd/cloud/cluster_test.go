@@ -0,0 +1,37 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cloud
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud/cloudapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCluster(t *testing.T) {
+	var c cloudapi.Cluster
+	c.Name = "prod"
+	c.KafkaAPI.SeedBrokers = []string{"seed-0.prod.example.com:9092"}
+	c.AdminAPI.URLs = []string{"https://admin-0.prod.example.com:9644", "admin-1.prod.example.com:9644"}
+
+	cfg := config.Default()
+	cfg.Rpk.KafkaAPI.TLS = &config.TLS{TruststoreFile: "ca.pem"}
+	require.NoError(t, applyCluster(cfg, c))
+
+	require.Equal(t, []string{"seed-0.prod.example.com:9092"}, cfg.Rpk.KafkaAPI.Brokers)
+	require.Equal(t, &config.TLS{TruststoreFile: "ca.pem"}, cfg.Rpk.KafkaAPI.TLS)
+	require.Equal(t, []string{"admin-0.prod.example.com:9644", "admin-1.prod.example.com:9644"}, cfg.Rpk.AdminAPI.Addresses)
+	require.NotNil(t, cfg.Rpk.AdminAPI.TLS)
+
+	c.KafkaAPI.SeedBrokers = nil
+	require.Error(t, applyCluster(config.Default(), c))
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cloud
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud/auth"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultAuthURL  = "https://auth.prd.cloud.redpanda.com"
+	defaultAudience = "cloudv2-production.redpanda.cloud"
+	defaultAPIURL   = "https://cloud-api.prd.cloud.redpanda.com"
+)
+
+// envOr returns the value of the environment variable, or def if it is unset.
+func envOr(env, def string) string {
+	if v, ok := os.LookupEnv(env); ok {
+		return v
+	}
+	return def
+}
+
+func newLoginCommand(fs afero.Fs) *cobra.Command {
+	var (
+		endpoints auth.Endpoints
+		apiURL    string
+	)
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to Redpanda Cloud",
+		Long: `Log in to Redpanda Cloud.
+
+This command starts an OAuth device login: it prints a URL and a code, which
+you open and enter in a browser, on this machine or any other. Once the login
+is approved, the session is stored in the OS keychain if one is available
+(the macOS keychain, or the Secret Service keyring on Linux desktops), and
+otherwise in a file that only your user can read.
+
+The stored session is used by the other 'rpk cloud' commands, and is renewed
+automatically when it expires. Use 'rpk cloud cluster use' to point rpk at a
+managed cluster by name.
+
+The authorization server and cloud API can be changed with flags, or with the
+RPK_CLOUD_AUTH_URL, RPK_CLOUD_CLIENT_ID, RPK_CLOUD_AUDIENCE and
+RPK_CLOUD_API_URL environment variables.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			if endpoints.ClientID == "" {
+				out.DieUsage("--client-id (or RPK_CLOUD_CLIENT_ID) is required")
+			}
+			store, err := auth.NewStore(fs)
+			out.MaybeDie(err, "unable to initialize the session store: %v", err)
+
+			cl := &auth.Client{Endpoints: endpoints}
+			dc, err := cl.RequestDeviceCode(cmd.Context())
+			out.MaybeDieErr(err)
+
+			if dc.VerificationURIComplete != "" {
+				fmt.Printf("Open %s in a browser to log in,\nand confirm that it shows the code %s.\n", dc.VerificationURIComplete, dc.UserCode)
+			} else {
+				fmt.Printf("Open %s in a browser to log in,\nand enter the code %s.\n", dc.VerificationURI, dc.UserCode)
+			}
+			fmt.Println("Waiting for the login to complete...")
+
+			token, err := cl.PollToken(cmd.Context(), dc)
+			out.MaybeDie(err, "unable to log in: %v", err)
+
+			err = store.Save(auth.Session{
+				Endpoints: endpoints,
+				APIURL:    apiURL,
+				Token:     token,
+			})
+			out.MaybeDieErr(err)
+			fmt.Printf("Logged in; the session is stored in %s.\n", store)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVar(&endpoints.AuthURL, "auth-url", envOr("RPK_CLOUD_AUTH_URL", defaultAuthURL), "Base URL of the authorization server")
+	f.StringVar(&endpoints.ClientID, "client-id", envOr("RPK_CLOUD_CLIENT_ID", ""), "OAuth client ID to log in with")
+	f.StringVar(&endpoints.Audience, "audience", envOr("RPK_CLOUD_AUDIENCE", defaultAudience), "Audience to request tokens for")
+	f.StringVar(&apiURL, "api-url", envOr("RPK_CLOUD_API_URL", defaultAPIURL), "Base URL of the cloud API")
+	return cmd
+}
+
+func newLogoutCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Delete the stored Redpanda Cloud session",
+		Args:  cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			store, err := auth.NewStore(fs)
+			out.MaybeDie(err, "unable to initialize the session store: %v", err)
+			err = store.Delete()
+			out.MaybeDieErr(err)
+			fmt.Println("Logged out.")
+		},
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/license"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/maintenance"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/partitions"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/producers"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/storage"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/group"
 	"github.com/spf13/afero"
@@ -58,11 +60,15 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 		newHealthOverviewCommand(fs),
 		newLogdirsCommand(fs),
 		newMetadataCommand(fs),
+		newReportCommand(fs),
+		newWatchCommand(fs),
 
 		config.NewConfigCommand(fs),
 		license.NewLicenseCommand(fs),
 		maintenance.NewMaintenanceCommand(fs),
 		partitions.NewPartitionsCommand(fs),
+		producers.NewProducersCommand(fs),
+		storage.NewStorageCommand(fs),
 		offsets,
 	)
 
@@ -10,6 +10,12 @@
 package config
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/spf13/afero"
@@ -80,7 +86,35 @@ different redpanda version that does not recognize certain properties.`,
 		newLintCommand(fs),
 		newSetCommand(fs),
 		newGetCommand(fs),
+		newWatchCommand(fs),
 	)
 
 	return command
 }
+
+// addWaitTimeoutFlag adds the --wait-timeout flag of the commands that write
+// the cluster configuration.
+func addWaitTimeoutFlag(cmd *cobra.Command, waitTimeout *time.Duration) {
+	cmd.Flags().DurationVar(
+		waitTimeout,
+		"wait-timeout",
+		10*time.Second,
+		"How long to wait for every broker to apply the new configuration version, 0 to not wait",
+	)
+}
+
+// waitForConfigVersion waits up to timeout for every broker to apply the
+// config version, warning about the brokers that did not. A timeout of 0
+// skips the wait.
+func waitForConfigVersion(
+	ctx context.Context, client *admin.AdminAPI, version int, timeout time.Duration,
+) {
+	if timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := client.WaitForConfigVersion(ctx, version); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; check 'rpk cluster config status'\n", err)
+	}
+}
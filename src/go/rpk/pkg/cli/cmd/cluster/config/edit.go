@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -23,6 +24,7 @@ import (
 )
 
 func newEditCommand(fs afero.Fs, all *bool) *cobra.Command {
+	var waitTimeout time.Duration
 	cmd := &cobra.Command{
 		Use:   "edit",
 		Short: "Edit cluster configuration properties",
@@ -40,6 +42,9 @@ values.
 
 By default, low level tunables are excluded: use the '--all' flag
 to edit all properties including these tunables.
+
+If the cluster configuration is changed by someone else while you edit it,
+your changes are not written, so that they do not revert the other changes.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, _ []string) {
@@ -54,14 +59,21 @@ to edit all properties including these tunables.
 			schema, err := client.ClusterConfigSchema(cmd.Context())
 			out.MaybeDie(err, "unable to query config schema: %v", err)
 
+			// GET the config version before the config, so that a
+			// concurrent write in between fails our write rather than
+			// being reverted by it.
+			status, err := client.ClusterConfigStatus(cmd.Context(), true)
+			out.MaybeDie(err, "unable to get current config version: %v", err)
+
 			// GET current config
 			currentConfig, err := client.Config(cmd.Context())
 			out.MaybeDie(err, "unable to get current config: %v", err)
 
-			err = executeEdit(cmd.Context(), client, schema, currentConfig, all)
+			err = executeEdit(cmd.Context(), client, schema, currentConfig, status.Version(), all, waitTimeout)
 			out.MaybeDie(err, "unable to edit: %v", err)
 		},
 	}
+	addWaitTimeoutFlag(cmd, &waitTimeout)
 	return cmd
 }
 
@@ -70,7 +82,9 @@ func executeEdit(
 	client *admin.AdminAPI,
 	schema admin.ConfigSchema,
 	currentConfig admin.Config,
+	version int,
 	all *bool,
+	waitTimeout time.Duration,
 ) error {
 	// Generate a yaml template for editing
 	file, err := os.CreateTemp("/tmp", "config_*.yaml")
@@ -116,7 +130,7 @@ func executeEdit(
 	}
 
 	// Read back template & parse
-	err = importConfig(ctx, client, filename, currentConfig, schema, *all)
+	err = importConfig(ctx, client, filename, currentConfig, version, schema, *all, waitTimeout)
 	if err != nil {
 		return fmt.Errorf("error updating config: %v", err)
 	}
@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -40,8 +41,10 @@ func importConfig(
 	client *admin.AdminAPI,
 	filename string,
 	oldConfig admin.Config,
+	version int,
 	schema admin.ConfigSchema,
 	all bool,
+	waitTimeout time.Duration,
 ) (err error) {
 	readbackBytes, err := os.ReadFile(filename)
 	if err != nil {
@@ -160,8 +163,12 @@ func importConfig(
 	// Newline between table and result of write
 	fmt.Printf("\n")
 
-	// PUT to admin API
-	result, err := client.PatchClusterConfig(ctx, upsert, remove)
+	// PUT to admin API, only if nobody changed the configuration since we
+	// read it: the diff above would silently revert their changes.
+	result, err := client.PatchClusterConfigAtVersion(ctx, upsert, remove, version)
+	if admin.IsConfigVersionConflict(err) {
+		return &formattedError{fmt.Sprintf("The cluster configuration was changed by another client since version %d was read.\nRe-run the command to apply your changes to the current configuration.\n", version)}
+	}
 	if he := (*admin.HTTPResponseError)(nil); errors.As(err, &he) {
 		// Special case 400 (validation) errors with friendly output
 		// about which configuration properties were invalid.
@@ -178,6 +185,7 @@ func importConfig(
 	if err != nil {
 		return fmt.Errorf("error setting config: %v", err)
 	}
+	waitForConfigVersion(ctx, client, result.ConfigVersion, waitTimeout)
 
 	fmt.Printf("Successfully updated configuration. New configuration version is %d.\n", result.ConfigVersion)
 
@@ -213,7 +221,10 @@ func formatValidationError(
 }
 
 func newImportCommand(fs afero.Fs, all *bool) *cobra.Command {
-	var filename string
+	var (
+		filename    string
+		waitTimeout time.Duration
+	)
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import cluster configuration from a file",
@@ -223,7 +234,15 @@ Import configuration from a YAML file, usually generated with
 corresponding 'export' command.  This downloads the current cluster
 configuration, calculates the difference with the YAML file, and
 updates any properties that were changed.  If a property is removed
-from the YAML file, it is reset to its default value.  `,
+from the YAML file, it is reset to its default value.
+
+If the cluster configuration is changed by someone else while this command
+runs, no changes are made: re-run the command to import the file over the
+current configuration.
+
+Once the configuration is imported, this command waits up to --wait-timeout
+for every broker to apply the new configuration version, and warns about the
+brokers that did not.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
 			cfg, err := p.Load(fs)
@@ -236,12 +255,18 @@ from the YAML file, it is reset to its default value.  `,
 			schema, err := client.ClusterConfigSchema(cmd.Context())
 			out.MaybeDie(err, "unable to query config schema: %v", err)
 
+			// GET the config version before the config, so that a
+			// concurrent write in between fails our write rather than
+			// being reverted by it.
+			status, err := client.ClusterConfigStatus(cmd.Context(), true)
+			out.MaybeDie(err, "unable to query config version: %v", err)
+
 			// GET current config
 			currentConfig, err := client.Config(cmd.Context())
 			out.MaybeDie(err, "unable to query config values: %v", err)
 
 			// Read back template & parse
-			err = importConfig(cmd.Context(), client, filename, currentConfig, schema, *all)
+			err = importConfig(cmd.Context(), client, filename, currentConfig, status.Version(), schema, *all, waitTimeout)
 			if fe := (*formattedError)(nil); errors.As(err, &fe) {
 				fmt.Fprint(os.Stderr, err)
 				out.Die("No changes were made")
@@ -257,6 +282,7 @@ from the YAML file, it is reset to its default value.  `,
 		"",
 		"full path to file to import, e.g. '/tmp/config.yml'",
 	)
+	addWaitTimeoutFlag(cmd, &waitTimeout)
 	return cmd
 }
 
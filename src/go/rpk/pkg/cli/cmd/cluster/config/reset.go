@@ -67,7 +67,7 @@ the setting as if it was set to the default.
 
 			// Encode output
 			outBytes, err := yaml.Marshal(content)
-			out.MaybeDie(err, "Serialization error: %v", configCacheFile, err)
+			out.MaybeDie(err, "Serialization error: %v", err)
 
 			// Write back output
 			err = afero.WriteFile(fs, configCacheFile, outBytes, 0o755)
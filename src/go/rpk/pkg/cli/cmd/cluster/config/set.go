@@ -12,6 +12,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -22,6 +23,7 @@ import (
 )
 
 func newSetCommand(fs afero.Fs) *cobra.Command {
+	var waitTimeout time.Duration
 	cmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a single cluster configuration property",
@@ -30,7 +32,11 @@ func newSetCommand(fs afero.Fs) *cobra.Command {
 This command is provided for use in scripts.  For interactive editing, or bulk
 changes, use the 'edit' and 'import' commands respectively.
 
-If an empty string is given as the value, the property is reset to its default.`,
+If an empty string is given as the value, the property is reset to its default.
+
+Once the property is set, this command waits up to --wait-timeout for every
+broker to apply the new configuration version, and warns about the brokers that
+did not.`,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			key := args[0]
@@ -48,7 +54,7 @@ If an empty string is given as the value, the property is reset to its default.`
 
 			meta, ok := schema[key]
 			if !ok {
-				out.Die("Unknown property %q", key)
+				out.DieUsage("Unknown property %q", key)
 			}
 
 			upsert := make(map[string]interface{})
@@ -90,9 +96,11 @@ If an empty string is given as the value, the property is reset to its default.`
 			}
 
 			out.MaybeDie(err, "error setting property: %v", err)
+			waitForConfigVersion(cmd.Context(), client, result.ConfigVersion, waitTimeout)
 			fmt.Printf("Successfully updated configuration. New configuration version is %d.\n", result.ConfigVersion)
 		},
 	}
 
+	addWaitTimeoutFlag(cmd, &waitTimeout)
 	return cmd
 }
@@ -0,0 +1,203 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newWatchCommand(fs afero.Fs) *cobra.Command {
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print cluster configuration changes as they happen",
+		Long: `Print cluster configuration changes as they happen.
+
+This command polls the cluster configuration status every --interval. Whenever
+the cluster configuration version changes, the new configuration is fetched and
+the properties that were changed, set, or reset since the previous version are
+printed, along with when the change was seen and how many nodes have applied
+it so far. The first output is the configuration version when the command
+starts.
+
+The admin API does not record who changed a property; use the Redpanda logs of
+the controller leader to find which request made a change.
+
+This is useful to catch configuration drift while investigating an incident.
+Press Ctrl+C to stop watching.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			client, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			if interval <= 0 {
+				out.DieUsage("invalid non-positive --interval %v", interval)
+			}
+
+			ctx := cmd.Context()
+			var (
+				version = -1
+				current admin.Config
+			)
+			for {
+				status, err := client.ClusterConfigStatus(ctx, true)
+				if err == nil && status.Version() != version {
+					var next admin.Config
+					next, err = client.Config(ctx)
+					if err == nil {
+						if version < 0 {
+							fmt.Printf("Watching cluster configuration version %d, %s.\n", status.Version(), appliedBy(status))
+						} else {
+							printConfigChange(os.Stdout, time.Now(), status, diffConfig(current, next))
+						}
+						version, current = status.Version(), next
+					}
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					fmt.Fprintf(os.Stderr, "%s: unable to query cluster configuration: %v\n", time.Now().Format(time.RFC3339), err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+	cmd.Flags().DurationVarP(&interval, "interval", "i", 2*time.Second, "How often to poll the cluster configuration status (e.g. 500ms, 5s)")
+	return cmd
+}
+
+// configChange is a property whose value differs between two versions of
+// the cluster configuration.
+type configChange struct {
+	name       string
+	prev, next interface{}
+	set        bool // the property did not exist before
+	reset      bool // the property no longer exists
+}
+
+// diffConfig returns the properties that differ between prev and next,
+// sorted by name.
+func diffConfig(prev, next admin.Config) []configChange {
+	var changes []configChange
+	for name, v := range next {
+		ov, exists := prev[name]
+		switch {
+		case !exists:
+			changes = append(changes, configChange{name: name, next: v, set: true})
+		case !reflect.DeepEqual(ov, v):
+			changes = append(changes, configChange{name: name, prev: ov, next: v})
+		}
+	}
+	for name, v := range prev {
+		if _, exists := next[name]; !exists {
+			changes = append(changes, configChange{name: name, prev: v, reset: true})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].name < changes[j].name })
+	return changes
+}
+
+func printConfigChange(
+	w io.Writer, at time.Time, status admin.ConfigStatusResponse, changes []configChange,
+) {
+	fmt.Fprintf(w, "%s: cluster configuration version %d, %s\n", at.Format(time.RFC3339), status.Version(), appliedBy(status))
+	if len(changes) == 0 {
+		// The version also changes when a property is set to the value it
+		// already had.
+		fmt.Fprintln(w, "  no property changed")
+	}
+	for _, c := range changes {
+		switch {
+		case c.set:
+			fmt.Fprintf(w, "+ %s: %s\n", c.name, configValue(c.next))
+		case c.reset:
+			fmt.Fprintf(w, "- %s: %s\n", c.name, configValue(c.prev))
+		default:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", c.name, configValue(c.prev), configValue(c.next))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// appliedBy describes how many nodes have applied the latest configuration
+// version, and which need a restart for it to take effect.
+func appliedBy(status admin.ConfigStatusResponse) string {
+	var applied int
+	var restart []string
+	for _, s := range status {
+		if s.ConfigVersion == int64(status.Version()) {
+			applied++
+		}
+		if s.Restart {
+			restart = append(restart, fmt.Sprint(s.NodeID))
+		}
+	}
+	s := fmt.Sprintf("applied by %d/%d nodes", applied, len(status))
+	if len(restart) > 0 {
+		s += fmt.Sprintf(" (restart needed on nodes %s)", strings.Join(restart, ", "))
+	}
+	return s
+}
+
+// configValue formats a property value on a single line, as YAML flow.
+func configValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	if err := enc.Encode(flowValue(v)); err != nil {
+		return fmt.Sprint(v)
+	}
+	enc.Close()
+	return strings.TrimSpace(sb.String())
+}
+
+// flowValue wraps arrays and objects in a yaml.Node with the flow style, so
+// that they are encoded on a single line.
+func flowValue(v interface{}) interface{} {
+	switch v.(type) {
+	case []interface{}, map[string]interface{}:
+		var n yaml.Node
+		if err := n.Encode(v); err != nil {
+			return v
+		}
+		n.Style = yaml.FlowStyle
+		return &n
+	}
+	return v
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfig(t *testing.T) {
+	prev := admin.Config{
+		"log_retention_ms":     604800000.0,
+		"superusers":           []interface{}{"admin"},
+		"auto_create_topics":   false,
+		"kafka_qdc_enable":     true,
+		"cloud_storage_bucket": "bucket",
+	}
+	next := admin.Config{
+		"log_retention_ms":   86400000.0,
+		"superusers":         []interface{}{"admin", "ops"},
+		"auto_create_topics": false,
+		"kafka_qdc_enable":   true,
+		"enable_sasl":        true,
+	}
+
+	changes := diffConfig(prev, next)
+	var buf bytes.Buffer
+	status := admin.ConfigStatusResponse{
+		{NodeID: 0, ConfigVersion: 7},
+		{NodeID: 1, ConfigVersion: 7, Restart: true},
+		{NodeID: 2, ConfigVersion: 6},
+	}
+	printConfigChange(&buf, time.Date(2022, 5, 4, 12, 0, 0, 0, time.UTC), status, changes)
+	require.Equal(t, `2022-05-04T12:00:00Z: cluster configuration version 7, applied by 2/3 nodes (restart needed on nodes 1)
+- cloud_storage_bucket: bucket
++ enable_sasl: true
+~ log_retention_ms: 604800000 -> 86400000
+~ superusers: [admin] -> [admin, ops]
+
+`, buf.String())
+
+	require.Empty(t, diffConfig(next, next))
+}
+
+func TestConfigValue(t *testing.T) {
+	for _, test := range []struct {
+		v   interface{}
+		exp string
+	}{
+		{nil, "null"},
+		{"", `""`},
+		{"a b", "a b"},
+		{1.5, "1.5"},
+		{[]interface{}{}, "[]"},
+		{[]interface{}{map[string]interface{}{"name": "internal", "port": 9092.0}}, "[{name: internal, port: 9092}]"},
+	} {
+		require.Equal(t, test.exp, configValue(test.v))
+	}
+}
@@ -49,12 +49,16 @@ func newInfoCommand(fs afero.Fs) *cobra.Command {
 				expired := info.Properties.Expires < 0
 				if format == "json" {
 					tm := time.Unix(info.Properties.Expires, 0).Format("Jan 2 2006")
+					// The UUID is only informational, older versions
+					// do not have one.
+					uuid, _ := cl.GetClusterUUID(cmd.Context())
 					props, err := json.MarshalIndent(struct {
 						Organization string
 						Type         string
 						Expires      string
-						Expired      bool `json:"license_expired,omitempty"`
-					}{info.Properties.Organization, info.Properties.Type, tm, expired}, "", "  ")
+						Expired      bool   `json:"license_expired,omitempty"`
+						ClusterUUID  string `json:"cluster_uuid,omitempty"`
+					}{info.Properties.Organization, info.Properties.Type, tm, expired, uuid}, "", "  ")
 					out.MaybeDie(err, "unable to print license information as json: %v", err)
 					fmt.Printf("%s\n", props)
 				} else {
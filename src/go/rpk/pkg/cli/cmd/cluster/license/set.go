@@ -34,7 +34,7 @@ default location '/etc/redpanda/redpanda.license'.
 
 		Run: func(cmd *cobra.Command, args []string) {
 			if licPath != "" && len(args) > 0 {
-				out.Die("inline license cannot be passed if flag '--path' is set")
+				out.DieUsage("inline license cannot be passed if flag '--path' is set")
 			}
 			if licPath == "" && len(args) == 0 {
 				fmt.Println("Neither license file nor inline license was provided, checking '/etc/redpanda/redpanda.license'.")
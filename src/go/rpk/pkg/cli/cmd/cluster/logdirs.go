@@ -179,7 +179,7 @@ where revision is a Redpanda internal concept.
 			var rowfn func(*out.TabWriter, row)
 			switch strings.ToLower(aggregateInto) {
 			default:
-				out.Die("unrecognized --aggregate-into %q", aggregateInto)
+				out.DieUsage("unrecognized --aggregate-into %q", aggregateInto)
 
 			case "broker":
 				headers = []string{"broker", "size", "error"}
@@ -12,6 +12,7 @@ package maintenance
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
@@ -34,7 +35,7 @@ func newDisableCommand(fs afero.Fs) *cobra.Command {
 			}
 
 			if nodeID < 0 {
-				out.Die("invalid node id: %d", nodeID)
+				out.DieUsage("invalid node id: %d", nodeID)
 			}
 
 			p := config.ParamsFromCommand(cmd)
@@ -45,13 +46,8 @@ func newDisableCommand(fs afero.Fs) *cobra.Command {
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
 			err = client.DisableMaintenanceMode(cmd.Context(), nodeID)
-			if he := (*admin.HTTPResponseError)(nil); errors.As(err, &he) {
-				if he.Response.StatusCode == 404 {
-					body, bodyErr := he.DecodeGenericErrorBody()
-					if bodyErr == nil {
-						out.Die("Not found: %s", body.Message)
-					}
-				}
+			if ae := (*admin.AdminError)(nil); errors.As(err, &ae) && ae.StatusCode == http.StatusNotFound {
+				out.Die("Not found: %s", ae.Message)
 			}
 
 			out.MaybeDie(err, "error disabling maintenance mode: %v", err)
@@ -10,8 +10,10 @@
 package maintenance
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -23,7 +25,10 @@ import (
 )
 
 func newEnableCommand(fs afero.Fs) *cobra.Command {
-	var wait bool
+	var (
+		wait    bool
+		timeout time.Duration
+	)
 	cmd := &cobra.Command{
 		Use:   "enable <node-id>",
 		Short: "Enable maintenance mode for a node",
@@ -40,7 +45,7 @@ node exists that is already in maintenance mode then an error will be returned.
 			}
 
 			if nodeID < 0 {
-				out.Die("invalid node id: %d", nodeID)
+				out.DieUsage("invalid node id: %d", nodeID)
 			}
 
 			p := config.ParamsFromCommand(cmd)
@@ -51,18 +56,13 @@ node exists that is already in maintenance mode then an error will be returned.
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
 			err = client.EnableMaintenanceMode(cmd.Context(), nodeID)
-			var he *admin.HTTPResponseError
-			if errors.As(err, &he) {
-				if he.Response.StatusCode == 404 {
-					body, bodyErr := he.DecodeGenericErrorBody()
-					if bodyErr == nil {
-						out.Die("Not found: %s", body.Message)
-					}
-				} else if he.Response.StatusCode == 400 {
-					body, bodyErr := he.DecodeGenericErrorBody()
-					if bodyErr == nil {
-						out.Die("Cannot enable maintenance mode: %s", body.Message)
-					}
+			var ae *admin.AdminError
+			if errors.As(err, &ae) {
+				switch ae.StatusCode {
+				case http.StatusNotFound:
+					out.Die("Not found: %s", ae.Message)
+				case http.StatusBadRequest:
+					out.Die("Cannot enable maintenance mode: %s", ae.Message)
 				}
 			}
 
@@ -75,41 +75,25 @@ node exists that is already in maintenance mode then an error will be returned.
 
 			fmt.Println("Waiting for node to drain...")
 
-			var table *out.TabWriter
-			retries := 3
-			for {
-				b, err := client.Broker(cmd.Context(), nodeID)
-				if err == nil && b.Maintenance == nil {
-					err = fmt.Errorf("maintenance mode not supported. upgrade in progress?")
-					// since admin api client uses `sendAny` it is possible that
-					// we enabled maintenance mode and then started querying a
-					// broker that hadn't been upgraded yet.  with the retry,
-					// this is unlikely. and won't occur at all once all nodes
-					// are upgraded to v22.1.x.
-					//
-					// [[fallthrough]].
-				}
-				if err != nil {
-					if retries <= 0 {
-						out.Die("Error retrieving broker status: %v", err)
-					}
-					retries--
-					time.Sleep(time.Second * 2)
-					continue
-				}
-				retries = 3
-				if table == nil {
-					table = newMaintenanceReportTable()
-				}
-				addBrokerMaintenanceReport(table, b)
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			status, err := client.WaitForDrain(ctx, nodeID)
+			if status.Draining {
+				table := newMaintenanceReportTable()
+				addBrokerMaintenanceReport(table, admin.Broker{NodeID: nodeID, Maintenance: &status})
 				table.Flush()
-				if b.Maintenance.Draining && b.Maintenance.Finished {
-					return
-				}
-				time.Sleep(time.Second * 2)
+			}
+			out.MaybeDie(err, "error waiting for node %d to drain: %v", nodeID, err)
+			if status.Errors {
+				fmt.Printf("Node %d drained with errors, %d leadership transfers failed: see the logs of the node\n", nodeID, status.Failed)
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait until node is drained")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "With --wait, how long to wait for the node to drain, e.g. 10m (no limit by default)")
 	return cmd
 }
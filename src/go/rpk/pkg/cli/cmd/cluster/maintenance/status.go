@@ -56,8 +56,8 @@ Field descriptions:
        DRAINING: true if the node is actively draining leadership
        FINISHED: leadership draining has completed
          ERRORS: errors have been encountered while draining
-     PARTITIONS: number of partitions whose leadership has moved
-       ELIGIBLE: number of partitions with leadership eligible to move
+     PARTITIONS: number of partitions of the node
+       ELIGIBLE: number of partitions whose leadership is yet to move
    TRANSFERRING: current active number of leadership transfers
          FAILED: number of failed leadership transfers
 
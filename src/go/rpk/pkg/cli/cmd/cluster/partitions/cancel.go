@@ -1,7 +1,9 @@
 package partitions
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -14,6 +16,8 @@ func newMovementCancelCommand(fs afero.Fs) *cobra.Command {
 	var (
 		node      int
 		noConfirm bool
+		wait      bool
+		timeout   time.Duration
 	)
 	cmd := &cobra.Command{
 		Use:   "movement-cancel",
@@ -31,6 +35,9 @@ If "--node" is set, this command will only stop the partition movements
 occurring in the specified node:
 
     rpk cluster partitions movement-cancel --node 1
+
+Canceled movements are reverted in the background. With "--wait", this command
+waits until the canceled movements are no longer in progress.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -69,13 +76,41 @@ occurring in the specified node:
 				return
 			}
 			printMovementsResult(movements)
+
+			if !wait {
+				return
+			}
+			fmt.Println("Waiting for the canceled movements to complete...")
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			canceled := make(map[movedPartition]bool, len(movements))
+			for _, m := range movements {
+				canceled[movedPartition{m.Namespace, m.Topic, m.Partition}] = true
+			}
+			err = cl.WaitForReconfigurationDone(ctx, func(r admin.Reconfiguration) bool {
+				return canceled[movedPartition{r.Namespace, r.Topic, r.PartitionID}]
+			})
+			out.MaybeDie(err, "error waiting for the canceled movements to complete: %v", err)
+			fmt.Println("The canceled movements are complete")
 		},
 	}
 	cmd.Flags().IntVar(&node, "node", -1, "ID of a specific node on which to cancel ongoing partition movements")
 	cmd.Flags().BoolVar(&noConfirm, "no-confirm", false, "Disable confirmation prompt")
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait until the canceled movements are no longer in progress")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "With --wait, how long to wait for the movements, e.g. 10m (no limit by default)")
 	return cmd
 }
 
+type movedPartition struct {
+	namespace string
+	topic     string
+	partition int
+}
+
 func printMovementsResult(movements []admin.PartitionsMovementResult) {
 	headers := []string{
 		"NAMESPACE",
@@ -0,0 +1,228 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package partitions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// partitionFilters select the partitions to list. A partition is listed if
+// it matches any of the enabled filters, or if no filter is enabled.
+type partitionFilters struct {
+	leaderless      bool
+	underReplicated bool
+	moving          bool
+}
+
+func (f partitionFilters) any() bool {
+	return f.leaderless || f.underReplicated || f.moving
+}
+
+type partitionKey struct {
+	ns, topic string
+	partition int
+}
+
+// partitionRow is a listed partition with its problems.
+type partitionRow struct {
+	ns, topic string
+	partition int
+	leader    string
+	replicas  []int
+	moveTo    []int // the new replicas if the partition is moving
+	status    []string
+}
+
+// listPartitions returns the rows of the partitions of the given topics (all
+// topics if empty) that match the filters, sorted by namespace, topic and
+// partition. A partition is under-replicated if any of its replicas is on a
+// node that is down.
+func listPartitions(
+	partitions []admin.ClusterPartition,
+	nodesDown []int,
+	reconfigurations []admin.Reconfiguration,
+	topics []string,
+	f partitionFilters,
+) []partitionRow {
+	down := make(map[int]bool)
+	for _, n := range nodesDown {
+		down[n] = true
+	}
+	moving := make(map[partitionKey][]int)
+	for _, r := range reconfigurations {
+		moving[partitionKey{r.Namespace, r.Topic, r.PartitionID}] = replicaNodes(r.NewReplicas)
+	}
+	wantTopic := make(map[string]bool)
+	for _, t := range topics {
+		wantTopic[t] = true
+	}
+
+	var rows []partitionRow
+	for _, p := range partitions {
+		if len(wantTopic) > 0 && !wantTopic[p.Topic] {
+			continue
+		}
+		row := partitionRow{
+			ns:        p.Namespace,
+			topic:     p.Topic,
+			partition: p.PartitionID,
+			leader:    "-",
+			replicas:  replicaNodes(p.Replicas),
+		}
+		var isLeaderless, isUnderReplicated, isMoving bool
+		if p.LeaderID == nil || *p.LeaderID < 0 {
+			isLeaderless = true
+			row.status = append(row.status, "leaderless")
+		} else {
+			row.leader = fmt.Sprint(*p.LeaderID)
+		}
+		for _, n := range row.replicas {
+			if down[n] {
+				isUnderReplicated = true
+				row.status = append(row.status, "under-replicated")
+				break
+			}
+		}
+		if to, ok := moving[partitionKey{p.Namespace, p.Topic, p.PartitionID}]; ok {
+			isMoving = true
+			row.moveTo = to
+			row.status = append(row.status, "moving")
+		}
+		if p.Disabled {
+			row.status = append(row.status, "disabled")
+		}
+		if f.any() && !(f.leaderless && isLeaderless || f.underReplicated && isUnderReplicated || f.moving && isMoving) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		l, r := rows[i], rows[j]
+		if l.ns != r.ns {
+			return l.ns < r.ns
+		}
+		if l.topic != r.topic {
+			return l.topic < r.topic
+		}
+		return l.partition < r.partition
+	})
+	return rows
+}
+
+func replicaNodes(replicas []admin.Replica) []int {
+	nodes := make([]int, 0, len(replicas))
+	for _, r := range replicas {
+		nodes = append(nodes, r.NodeID)
+	}
+	return nodes
+}
+
+func newListCommand(fs afero.Fs) *cobra.Command {
+	var (
+		f     partitionFilters
+		allNs bool
+	)
+	cmd := &cobra.Command{
+		Use:     "list [TOPICS...]",
+		Aliases: []string{"ls"},
+		Short:   "List the partitions of the cluster and their replicas",
+		Long: `List the partitions of the cluster and their replicas.
+
+This command lists the partitions of all topics, or of the given topics, with
+their leader and replica set, from a single request to the admin API. The
+filters narrow the list down to the partitions that need attention; if several
+filters are given, partitions matching any of them are listed:
+
+    --leaderless          partitions without a leader
+    --under-replicated    partitions with a replica on a node that is down
+    --moving              partitions being moved to new replicas
+
+The STATUS column lists the problems of each partition, and MOVING TO is the
+new replica set of partitions being moved. By default, only the partitions of
+the kafka namespace are listed; use --all-namespaces to include internal
+partitions, such as the controller partition.
+`,
+		Run: func(cmd *cobra.Command, topics []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			partitions, err := cl.AllClusterPartitions(cmd.Context())
+			out.MaybeDie(err, "unable to list partitions: %v", err)
+
+			health, err := cl.GetHealthOverview(cmd.Context())
+			out.MaybeDie(err, "unable to request cluster health: %v", err)
+
+			// Older versions do not report partition movements; they
+			// are only needed to filter or show moving partitions.
+			reconfigurations, err := cl.Reconfigurations(cmd.Context())
+			movesUnknown := err != nil
+			if movesUnknown && f.moving {
+				out.Die("unable to list partition movements: %v", err)
+			}
+
+			if !allNs {
+				kafka := partitions[:0]
+				for _, p := range partitions {
+					if p.Namespace == "kafka" {
+						kafka = append(kafka, p)
+					}
+				}
+				partitions = kafka
+			}
+
+			rows := listPartitions(partitions, health.NodesDown, reconfigurations, topics, f)
+			if movesUnknown {
+				fmt.Println("Partition movements are not reported by this cluster, moving partitions are not shown.")
+			}
+			printPartitions(rows, allNs)
+		},
+	}
+	cmd.Flags().BoolVar(&f.leaderless, "leaderless", false, "Only list partitions without a leader")
+	cmd.Flags().BoolVar(&f.underReplicated, "under-replicated", false, "Only list partitions with a replica on a node that is down")
+	cmd.Flags().BoolVar(&f.moving, "moving", false, "Only list partitions that are being moved")
+	cmd.Flags().BoolVarP(&allNs, "all-namespaces", "a", false, "Include the partitions of internal namespaces")
+	return cmd
+}
+
+func printPartitions(rows []partitionRow, withNs bool) {
+	headers := []string{"TOPIC", "PARTITION", "LEADER", "REPLICAS", "MOVING TO", "STATUS"}
+	if withNs {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+	tw := out.NewTable(headers...)
+	defer tw.Flush()
+	for _, r := range rows {
+		moveTo := "-"
+		if r.moveTo != nil {
+			moveTo = fmt.Sprint(r.moveTo)
+		}
+		status := "-"
+		if len(r.status) > 0 {
+			status = strings.Join(r.status, ", ")
+		}
+		fields := []interface{}{r.topic, r.partition, r.leader, fmt.Sprint(r.replicas), moveTo, status}
+		if withNs {
+			fields = append([]interface{}{r.ns}, fields...)
+		}
+		tw.Print(fields...)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package partitions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPartitions(t *testing.T) {
+	leader := func(id int) *int { return &id }
+	replicas := func(ids ...int) []admin.Replica {
+		var rs []admin.Replica
+		for _, id := range ids {
+			rs = append(rs, admin.Replica{NodeID: id})
+		}
+		return rs
+	}
+	partitions := []admin.ClusterPartition{
+		{Namespace: "kafka", Topic: "foo", PartitionID: 1, LeaderID: leader(1), Replicas: replicas(1, 2, 3)},
+		{Namespace: "kafka", Topic: "foo", PartitionID: 0, Replicas: replicas(1, 2, 3)},
+		{Namespace: "kafka", Topic: "bar", PartitionID: 0, LeaderID: leader(4), Replicas: replicas(4)},
+		{Namespace: "kafka", Topic: "baz", PartitionID: 0, LeaderID: leader(2), Replicas: replicas(2, 3)},
+	}
+	nodesDown := []int{3}
+	reconfigurations := []admin.Reconfiguration{
+		{Namespace: "kafka", Topic: "bar", PartitionID: 0, PreviousReplicas: replicas(4), NewReplicas: replicas(1)},
+	}
+
+	all := listPartitions(partitions, nodesDown, reconfigurations, nil, partitionFilters{})
+	require.Equal(t, []partitionRow{
+		{ns: "kafka", topic: "bar", partition: 0, leader: "4", replicas: []int{4}, moveTo: []int{1}, status: []string{"moving"}},
+		{ns: "kafka", topic: "baz", partition: 0, leader: "2", replicas: []int{2, 3}, status: []string{"under-replicated"}},
+		{ns: "kafka", topic: "foo", partition: 0, leader: "-", replicas: []int{1, 2, 3}, status: []string{"leaderless", "under-replicated"}},
+		{ns: "kafka", topic: "foo", partition: 1, leader: "1", replicas: []int{1, 2, 3}, status: []string{"under-replicated"}},
+	}, all)
+
+	for _, test := range []struct {
+		name   string
+		topics []string
+		f      partitionFilters
+		exp    []string
+	}{
+		{"leaderless", nil, partitionFilters{leaderless: true}, []string{"foo/0"}},
+		{"moving", nil, partitionFilters{moving: true}, []string{"bar/0"}},
+		{"leaderless or moving", nil, partitionFilters{leaderless: true, moving: true}, []string{"bar/0", "foo/0"}},
+		{"under-replicated of topic", []string{"baz"}, partitionFilters{underReplicated: true}, []string{"baz/0"}},
+		{"topic", []string{"foo"}, partitionFilters{}, []string{"foo/0", "foo/1"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, r := range listPartitions(partitions, nodesDown, reconfigurations, test.topics, test.f) {
+				got = append(got, fmt.Sprintf("%s/%d", r.topic, r.partition))
+			}
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
@@ -31,6 +31,7 @@ func NewPartitionsCommand(fs afero.Fs) *cobra.Command {
 
 	cmd.AddCommand(
 		newBalancerStatusCommand(fs),
+		newListCommand(fs),
 		newMovementCancelCommand(fs),
 	)
 
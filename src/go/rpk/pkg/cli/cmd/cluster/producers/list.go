@@ -0,0 +1,167 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package producers
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// producerRow is a producer known by a partition.
+type producerRow struct {
+	partition  int
+	id         int64
+	epoch      int16
+	lastSeq    string
+	lastUpdate time.Time
+	idle       time.Duration
+	txBegin    string // the begin offset of the open transaction, or "-"
+}
+
+// producerRows returns the producers of the partitions that have been idle
+// for at least minIdle, sorted by partition and producer ID.
+func producerRows(
+	now time.Time, partitions []admin.PartitionProducers, minIdle time.Duration,
+) []producerRow {
+	var rows []producerRow
+	for _, p := range partitions {
+		for _, pr := range p.Producers {
+			lastUpdate := time.UnixMilli(pr.LastUpdateTimestamp)
+			idle := now.Sub(lastUpdate)
+			if idle < 0 {
+				idle = 0
+			}
+			if idle < minIdle {
+				continue
+			}
+			row := producerRow{
+				partition:  p.PartitionID,
+				id:         pr.ID,
+				epoch:      pr.Epoch,
+				lastSeq:    "-",
+				lastUpdate: lastUpdate,
+				idle:       idle.Truncate(time.Second),
+				txBegin:    "-",
+			}
+			if n := len(pr.LastSequenceNumbers); n > 0 {
+				row.lastSeq = fmt.Sprint(pr.LastSequenceNumbers[n-1])
+			}
+			if pr.TransactionBeginOffset != nil {
+				row.txBegin = fmt.Sprint(*pr.TransactionBeginOffset)
+			}
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].partition != rows[j].partition {
+			return rows[i].partition < rows[j].partition
+		}
+		return rows[i].id < rows[j].id
+	})
+	return rows
+}
+
+func newListCommand(fs afero.Fs) *cobra.Command {
+	var (
+		topic      string
+		partitions []int
+		minIdle    time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the producers known by the partitions of a topic",
+		Long: `List the producers known by the partitions of a topic.
+
+Partitions keep the state of each idempotent or transactional producer that
+wrote to them, to deduplicate retried batches, until the producer expires.
+Producers that stopped without closing, or zombie producers, keep holding this
+state, and the open transactions of such producers hold back the last stable
+offset of the partition, which stalls read_committed consumers.
+
+This command asks the leader of each partition of the topic for its producers:
+
+    PRODUCER-ID      the ID of the producer
+    EPOCH            the epoch of the producer, bumped on each initialization
+    LAST-SEQUENCE    the sequence number of the last batch of the producer
+    LAST-UPDATE      when the producer last produced
+    IDLE             for how long the producer has not produced
+    TX-BEGIN-OFFSET  the offset of the open transaction of the producer, if any
+
+Use --idle to only list the producers that have not produced for a while,
+which are the likely zombies:
+
+    rpk cluster producers list --topic foo --idle 1h
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			if len(partitions) == 0 {
+				all, err := cl.AllClusterPartitions(cmd.Context())
+				out.MaybeDie(err, "unable to list partitions: %v", err)
+				for _, p := range all {
+					if p.Namespace == "kafka" && p.Topic == topic {
+						partitions = append(partitions, p.PartitionID)
+					}
+				}
+				if len(partitions) == 0 {
+					out.Die("topic %q not found", topic)
+				}
+			}
+
+			var (
+				producers []admin.PartitionProducers
+				failed    bool
+			)
+			for _, partition := range partitions {
+				pp, err := cl.GetPartitionProducers(cmd.Context(), "kafka", topic, partition)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "unable to list the producers of partition %d: %v\n", partition, err)
+					failed = true
+					continue
+				}
+				pp.PartitionID = partition
+				producers = append(producers, pp)
+			}
+
+			printProducers(producerRows(time.Now(), producers, minIdle))
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&topic, "topic", "t", "", "Topic to list the producers of")
+	cmd.Flags().IntSliceVarP(&partitions, "partitions", "p", nil, "Partitions to list the producers of (default all)")
+	cmd.Flags().DurationVar(&minIdle, "idle", 0, "Only list the producers that have not produced for at least this long")
+	cobra.MarkFlagRequired(cmd.Flags(), "topic")
+	return cmd
+}
+
+func printProducers(rows []producerRow) {
+	tw := out.NewTable("PARTITION", "PRODUCER-ID", "EPOCH", "LAST-SEQUENCE", "LAST-UPDATE", "IDLE", "TX-BEGIN-OFFSET")
+	defer tw.Flush()
+	for _, r := range rows {
+		tw.Print(r.partition, r.id, r.epoch, r.lastSeq, r.lastUpdate.UTC().Format(time.RFC3339), r.idle, r.txBegin)
+	}
+}
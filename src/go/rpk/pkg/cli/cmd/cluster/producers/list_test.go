@@ -0,0 +1,46 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package producers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProducerRows(t *testing.T) {
+	now := time.Unix(10000, 0)
+	ms := func(ago time.Duration) int64 { return now.Add(-ago).UnixMilli() }
+	offset := int64(12)
+	partitions := []admin.PartitionProducers{
+		{PartitionID: 1, Producers: []admin.PartitionProducer{
+			{ID: 9, Epoch: 0, LastSequenceNumbers: []int32{3, 4}, LastUpdateTimestamp: ms(2 * time.Hour), TransactionBeginOffset: &offset},
+			{ID: 7, Epoch: 2, LastUpdateTimestamp: ms(time.Minute)},
+		}},
+		{PartitionID: 0, Producers: []admin.PartitionProducer{
+			{ID: 9, Epoch: 0, LastSequenceNumbers: []int32{8}, LastUpdateTimestamp: ms(90*time.Minute + 500*time.Millisecond)},
+		}},
+	}
+
+	rows := producerRows(now, partitions, 0)
+	require.Equal(t, []producerRow{
+		{partition: 0, id: 9, lastSeq: "8", lastUpdate: time.UnixMilli(ms(90*time.Minute + 500*time.Millisecond)), idle: 90 * time.Minute, txBegin: "-"},
+		{partition: 1, id: 7, epoch: 2, lastSeq: "-", lastUpdate: time.UnixMilli(ms(time.Minute)), idle: time.Minute, txBegin: "-"},
+		{partition: 1, id: 9, lastSeq: "4", lastUpdate: time.UnixMilli(ms(2 * time.Hour)), idle: 2 * time.Hour, txBegin: "12"},
+	}, rows)
+
+	idle := producerRows(now, partitions, time.Hour)
+	require.Len(t, idle, 2)
+	require.Equal(t, 0, idle[0].partition)
+	require.Equal(t, 1, idle[1].partition)
+	require.Equal(t, "12", idle[1].txBegin)
+}
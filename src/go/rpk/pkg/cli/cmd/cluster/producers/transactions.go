@@ -0,0 +1,92 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package producers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newTransactionsCommand(fs afero.Fs) *cobra.Command {
+	var minStaleness time.Duration
+	cmd := &cobra.Command{
+		Use:     "transactions",
+		Aliases: []string{"txs"},
+		Short:   "List the transactions of the transaction coordinator",
+		Long: `List the transactions of the transaction coordinator.
+
+This command lists the state of every transactional ID known by the
+transaction coordinator, along with the producer ID and epoch it is bound to,
+the status of its current transaction, and the partitions the transaction
+writes to. STALENESS is the time since the transaction was last updated.
+
+Use --stale to only list the transactions that have not been updated for a
+while, whose producers likely died:
+
+    rpk cluster producers transactions --stale 10m
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			txs, err := cl.AllTransactions(cmd.Context())
+			out.MaybeDie(err, "unable to list transactions: %v", err)
+
+			tw := out.NewTable("TRANSACTIONAL-ID", "PRODUCER-ID", "EPOCH", "STATUS", "TIMEOUT", "STALENESS", "PARTITIONS")
+			defer tw.Flush()
+			for _, tx := range txs {
+				staleness := time.Duration(tx.StalenessMs) * time.Millisecond
+				if staleness < minStaleness {
+					continue
+				}
+				tw.Print(
+					tx.TransactionalID,
+					tx.ProducerID.ID,
+					tx.ProducerID.Epoch,
+					tx.Status,
+					time.Duration(tx.TimeoutMs)*time.Millisecond,
+					staleness,
+					formatTransactionPartitions(tx.Partitions),
+				)
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&minStaleness, "stale", 0, "Only list the transactions not updated for at least this long")
+	return cmd
+}
+
+// formatTransactionPartitions formats the partitions of a transaction as
+// topic/partition, prefixed with the namespace outside of kafka.
+func formatTransactionPartitions(partitions []admin.TransactionPartition) string {
+	if len(partitions) == 0 {
+		return "-"
+	}
+	s := make([]string, 0, len(partitions))
+	for _, p := range partitions {
+		tp := fmt.Sprintf("%s/%d", p.Topic, p.PartitionID)
+		if p.Namespace != "" && p.Namespace != "kafka" {
+			tp = p.Namespace + "/" + tp
+		}
+		s = append(s, tp)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,486 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+const (
+	producedBytesMetric = "vectorized_cluster_partition_bytes_produced_total"
+	fetchedBytesMetric  = "vectorized_cluster_partition_bytes_fetched_total"
+)
+
+func newReportCommand(fs afero.Fs) *cobra.Command {
+	var (
+		format   string
+		internal bool
+		sample   time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Export a capacity planning report of the cluster",
+		Long: `Export a capacity planning report of the cluster.
+
+This command collects, into a single report:
+
+  * the number of brokers, topics, partitions, and partition replicas,
+  * the disk usage of each broker, as the size of the partitions it hosts,
+  * the partitions, replication factor, retention, and size (across all
+    replicas) of each topic,
+  * the produce and fetch throughput of the cluster and of each topic.
+
+The throughput is calculated by scraping the metrics of every broker in
+rpk.admin_api twice, --sample-interval apart. Only the brokers listed in
+rpk.admin_api are scraped: list every broker there for a complete report. A
+--sample-interval of 0 skips collecting the throughput.
+
+The report is printed as JSON by default. With --format csv, one row is printed
+per topic, followed by a "(total)" row with the totals of the cluster.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			if format != "json" && format != "csv" {
+				out.DieUsage("invalid --format %q, must be json or csv", format)
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			ctx := cmd.Context()
+			r, err := collectReport(ctx, adm, internal)
+			out.MaybeDie(err, "unable to collect the cluster report: %v", err)
+			r.ClusterUUID = clusterUUID(ctx, fs, cfg)
+
+			if sample > 0 {
+				var scrapers []metricsScraper
+				for _, addr := range cfg.Rpk.AdminAPI.Addresses {
+					cl, err := admin.NewHostClient(fs, cfg, addr)
+					out.MaybeDie(err, "unable to initialize admin client for %s: %v", addr, err)
+					scrapers = append(scrapers, cl)
+				}
+				err = addThroughput(ctx, &r, scrapers, sample)
+				out.MaybeDie(err, "unable to collect the cluster throughput: %v", err)
+			}
+
+			if format == "csv" {
+				err = writeReportCSV(os.Stdout, r)
+				out.MaybeDie(err, "unable to write the report: %v", err)
+				return
+			}
+			b, err := json.MarshalIndent(r, "", "  ")
+			out.MaybeDie(err, "unable to encode the report: %v", err)
+			fmt.Printf("%s\n", b)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "json", "Output format (json, csv)")
+	cmd.Flags().BoolVarP(&internal, "include-internal-topics", "i", false, "Include internal topics in the report")
+	cmd.Flags().DurationVar(&sample, "sample-interval", 10*time.Second, "Time between the two metrics scrapes used to calculate the throughput; 0 disables it")
+	return cmd
+}
+
+// clusterUUID returns the UUID of the cluster, or an empty string if the
+// admin API cannot be reached or the cluster has no UUID: unlike the Kafka
+// cluster ID, it is only used to correlate reports with clusters, which is
+// not worth failing the report over.
+func clusterUUID(ctx context.Context, fs afero.Fs, cfg *config.Config) string {
+	cl, err := admin.NewClient(fs, cfg)
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	uuid, _ := cl.GetClusterUUID(ctx)
+	return uuid
+}
+
+type clusterReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	ClusterID   string    `json:"cluster_id,omitempty"`
+	ClusterUUID string    `json:"cluster_uuid,omitempty"`
+
+	Brokers        int   `json:"brokers"`
+	Topics         int   `json:"topics"`
+	Partitions     int   `json:"partitions"`
+	Replicas       int   `json:"replicas"`
+	DiskUsageBytes int64 `json:"disk_usage_bytes"`
+
+	// The throughput is nil if it was not sampled.
+	ProduceBytesPerSec *float64 `json:"produce_bytes_per_sec,omitempty"`
+	FetchBytesPerSec   *float64 `json:"fetch_bytes_per_sec,omitempty"`
+
+	BrokerDetails []brokerReport `json:"broker_details"`
+	TopicDetails  []topicReport  `json:"topic_details"`
+}
+
+type brokerReport struct {
+	NodeID         int32  `json:"node_id"`
+	Host           string `json:"host"`
+	Rack           string `json:"rack,omitempty"`
+	Replicas       int    `json:"replicas"`
+	DiskUsageBytes int64  `json:"disk_usage_bytes"`
+}
+
+type topicReport struct {
+	Name              string `json:"name"`
+	Internal          bool   `json:"internal,omitempty"`
+	Partitions        int    `json:"partitions"`
+	ReplicationFactor int    `json:"replication_factor"`
+	CleanupPolicy     string `json:"cleanup_policy,omitempty"`
+	// Retentions are nil if the topic has no retention limit.
+	RetentionMs    *int64 `json:"retention_ms"`
+	RetentionBytes *int64 `json:"retention_bytes"`
+	SizeBytes      int64  `json:"size_bytes"`
+
+	ProduceBytesPerSec *float64 `json:"produce_bytes_per_sec,omitempty"`
+	FetchBytesPerSec   *float64 `json:"fetch_bytes_per_sec,omitempty"`
+}
+
+func collectReport(
+	ctx context.Context, adm *kadm.Client, internal bool,
+) (clusterReport, error) {
+	r := clusterReport{GeneratedAt: time.Now().UTC()}
+
+	m, err := adm.Metadata(ctx)
+	if err != nil {
+		return r, fmt.Errorf("unable to request metadata: %v", err)
+	}
+	r.ClusterID = m.Cluster
+	r.Brokers = len(m.Brokers)
+
+	brokers := make(map[int32]*brokerReport, len(m.Brokers))
+	for _, b := range m.Brokers {
+		br := brokerReport{NodeID: b.NodeID, Host: b.Host}
+		if b.Rack != nil {
+			br.Rack = *b.Rack
+		}
+		r.BrokerDetails = append(r.BrokerDetails, br)
+	}
+	sort.Slice(r.BrokerDetails, func(i, j int) bool {
+		return r.BrokerDetails[i].NodeID < r.BrokerDetails[j].NodeID
+	})
+	for i := range r.BrokerDetails {
+		brokers[r.BrokerDetails[i].NodeID] = &r.BrokerDetails[i]
+	}
+
+	topics := make(map[string]*topicReport)
+	var names []string
+	for _, t := range m.Topics.Sorted() {
+		if t.Err != nil || (t.IsInternal && !internal) {
+			continue
+		}
+		tr := topicReport{
+			Name:       t.Topic,
+			Internal:   t.IsInternal,
+			Partitions: len(t.Partitions),
+		}
+		if len(t.Partitions) > 0 {
+			tr.ReplicationFactor = t.Partitions.NumReplicas()
+		}
+		for _, p := range t.Partitions {
+			r.Replicas += len(p.Replicas)
+			for _, id := range p.Replicas {
+				if b, ok := brokers[id]; ok {
+					b.Replicas++
+				}
+			}
+		}
+		r.Partitions += tr.Partitions
+		r.TopicDetails = append(r.TopicDetails, tr)
+		names = append(names, t.Topic)
+	}
+	for i := range r.TopicDetails {
+		topics[r.TopicDetails[i].Name] = &r.TopicDetails[i]
+	}
+	r.Topics = len(r.TopicDetails)
+	if len(names) == 0 {
+		return r, nil
+	}
+
+	configs, err := adm.DescribeTopicConfigs(ctx, names...)
+	if err != nil {
+		return r, fmt.Errorf("unable to describe topic configs: %v", err)
+	}
+	for _, rc := range configs {
+		t, ok := topics[rc.Name]
+		if !ok || rc.Err != nil {
+			continue
+		}
+		for _, c := range rc.Configs {
+			if c.Value == nil {
+				continue
+			}
+			switch c.Key {
+			case "cleanup.policy":
+				t.CleanupPolicy = *c.Value
+			case "retention.ms":
+				t.RetentionMs = parseRetention(*c.Value)
+			case "retention.bytes":
+				t.RetentionBytes = parseRetention(*c.Value)
+			}
+		}
+	}
+
+	dirs, err := adm.DescribeAllLogDirs(ctx, nil)
+	if err != nil {
+		var se *kadm.ShardErrors
+		if !errors.As(err, &se) || se.AllFailed {
+			return r, fmt.Errorf("unable to describe log dirs: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "warning: unable to describe the log dirs of some brokers, their disk usage is missing: %v\n", err)
+	}
+	dirs.Each(func(d kadm.DescribedLogDir) {
+		if d.Err != nil {
+			return
+		}
+		d.Topics.Each(func(p kadm.DescribedLogDirPartition) {
+			r.DiskUsageBytes += p.Size
+			if b, ok := brokers[d.Broker]; ok {
+				b.DiskUsageBytes += p.Size
+			}
+			if t, ok := topics[p.Topic]; ok {
+				t.SizeBytes += p.Size
+			}
+		})
+	})
+	return r, nil
+}
+
+// parseRetention parses a retention config, returning nil for unlimited (-1)
+// or unparsable retentions.
+func parseRetention(v string) *int64 {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return nil
+	}
+	return &n
+}
+
+type metricsScraper interface {
+	PrometheusMetrics(ctx context.Context) ([]byte, error)
+}
+
+// addThroughput scrapes the metrics of every broker twice, interval apart,
+// and adds the produce and fetch throughput to the report.
+func addThroughput(
+	ctx context.Context,
+	r *clusterReport,
+	scrapers []metricsScraper,
+	interval time.Duration,
+) error {
+	if len(scrapers) == 0 {
+		return nil
+	}
+	type sums struct{ produced, fetched map[string]float64 }
+	scrape := func() ([]sums, error) {
+		var all []sums
+		for _, s := range scrapers {
+			raw, err := s.PrometheusMetrics(ctx)
+			if err != nil {
+				return nil, err
+			}
+			ss := sums{make(map[string]float64), make(map[string]float64)}
+			addTopicSums(ss.produced, raw, producedBytesMetric)
+			addTopicSums(ss.fetched, raw, fetchedBytesMetric)
+			all = append(all, ss)
+		}
+		return all, nil
+	}
+
+	before, err := scrape()
+	if err != nil {
+		return err
+	}
+	select {
+	case <-time.After(interval):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	after, err := scrape()
+	if err != nil {
+		return err
+	}
+
+	// Rates are calculated per broker, so that a broker restarting in
+	// between the scrapes only loses its own throughput.
+	var totalProduced, totalFetched float64
+	for i := range r.TopicDetails {
+		t := &r.TopicDetails[i]
+		var p, f float64
+		for b := range before {
+			p += ratePerSec(before[b].produced[t.Name], after[b].produced[t.Name], interval)
+			f += ratePerSec(before[b].fetched[t.Name], after[b].fetched[t.Name], interval)
+		}
+		t.ProduceBytesPerSec, t.FetchBytesPerSec = &p, &f
+		totalProduced += p
+		totalFetched += f
+	}
+	r.ProduceBytesPerSec, r.FetchBytesPerSec = &totalProduced, &totalFetched
+	return nil
+}
+
+// ratePerSec returns the rate of a counter, which may have been reset by a
+// broker restart in between the scrapes.
+func ratePerSec(before, after float64, interval time.Duration) float64 {
+	if after < before {
+		return 0
+	}
+	return (after - before) / interval.Seconds()
+}
+
+// addTopicSums adds the samples of the given metric in the Prometheus text
+// format to sums, by kafka topic.
+func addTopicSums(sums map[string]float64, raw []byte, metric string) {
+	s := bufio.NewScanner(bytes.NewReader(raw))
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, metric+"{") {
+			continue
+		}
+		end := strings.LastIndexByte(line, '}')
+		if end < 0 {
+			continue
+		}
+		labels := parseLabels(line[len(metric)+1 : end])
+		if ns, ok := labels["namespace"]; ok && ns != "kafka" {
+			continue
+		}
+		topic, ok := labels["topic"]
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(line[end+1:])
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		sums[topic] += v
+	}
+}
+
+// parseLabels parses the labels of a Prometheus sample, e.g.
+// `topic="foo",partition="0"`.
+func parseLabels(s string) map[string]string {
+	labels := make(map[string]string)
+	for len(s) > 0 {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 || eq+1 >= len(s) || s[eq+1] != '"' {
+			return labels
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+
+		var value strings.Builder
+		i := 0
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		labels[name] = value.String()
+		if i >= len(s) {
+			return labels
+		}
+		s = strings.TrimPrefix(s[i+1:], ",")
+	}
+	return labels
+}
+
+func writeReportCSV(w io.Writer, r clusterReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"topic",
+		"partitions",
+		"replication_factor",
+		"cleanup_policy",
+		"retention_ms",
+		"retention_bytes",
+		"size_bytes",
+		"produce_bytes_per_sec",
+		"fetch_bytes_per_sec",
+	})
+	optInt := func(n *int64) string {
+		if n == nil {
+			return ""
+		}
+		return strconv.FormatInt(*n, 10)
+	}
+	optFloat := func(f *float64) string {
+		if f == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*f, 'f', 2, 64)
+	}
+	topics := append([]topicReport(nil), r.TopicDetails...)
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	for _, t := range topics {
+		cw.Write([]string{
+			t.Name,
+			strconv.Itoa(t.Partitions),
+			strconv.Itoa(t.ReplicationFactor),
+			t.CleanupPolicy,
+			optInt(t.RetentionMs),
+			optInt(t.RetentionBytes),
+			strconv.FormatInt(t.SizeBytes, 10),
+			optFloat(t.ProduceBytesPerSec),
+			optFloat(t.FetchBytesPerSec),
+		})
+	}
+	cw.Write([]string{
+		"(total)",
+		strconv.Itoa(r.Partitions),
+		"",
+		"",
+		"",
+		"",
+		strconv.FormatInt(r.DiskUsageBytes, 10),
+		optFloat(r.ProduceBytesPerSec),
+		optFloat(r.FetchBytesPerSec),
+	})
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeScraper struct {
+	scrapes [][]byte
+}
+
+func (f *fakeScraper) PrometheusMetrics(context.Context) ([]byte, error) {
+	raw := f.scrapes[0]
+	f.scrapes = f.scrapes[1:]
+	return raw, nil
+}
+
+func TestAddTopicSums(t *testing.T) {
+	raw := []byte(`# HELP vectorized_cluster_partition_bytes_produced_total Total number of bytes produced
+# TYPE vectorized_cluster_partition_bytes_produced_total counter
+vectorized_cluster_partition_bytes_produced_total{namespace="kafka",partition="0",shard="0",topic="foo"} 100
+vectorized_cluster_partition_bytes_produced_total{namespace="kafka",partition="1",shard="1",topic="foo"} 50.5
+vectorized_cluster_partition_bytes_produced_total{namespace="kafka",partition="0",shard="0",topic="we\"ird"} 7
+vectorized_cluster_partition_bytes_produced_total{namespace="redpanda",partition="0",shard="0",topic="controller"} 1000
+vectorized_cluster_partition_bytes_fetched_total{namespace="kafka",partition="0",shard="0",topic="foo"} 9
+`)
+	sums := make(map[string]float64)
+	addTopicSums(sums, raw, producedBytesMetric)
+	require.Equal(t, map[string]float64{"foo": 150.5, `we"ird`: 7}, sums)
+}
+
+func TestAddThroughput(t *testing.T) {
+	scrape := func(produced, fetched string) []byte {
+		return []byte(producedBytesMetric + `{namespace="kafka",partition="0",topic="foo"} ` + produced + "\n" +
+			fetchedBytesMetric + `{namespace="kafka",partition="0",topic="foo"} ` + fetched + "\n")
+	}
+	broker1 := &fakeScraper{[][]byte{scrape("100", "0"), scrape("300", "400")}}
+	// broker 2 restarted in between the scrapes, resetting its counters.
+	broker2 := &fakeScraper{[][]byte{scrape("500", "500"), scrape("10", "10")}}
+
+	r := clusterReport{TopicDetails: []topicReport{{Name: "foo"}, {Name: "bar"}}}
+	err := addThroughput(context.Background(), &r, []metricsScraper{broker1, broker2}, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// The restart of broker 2 only loses its own throughput.
+	require.Equal(t, 2000.0, *r.TopicDetails[0].ProduceBytesPerSec)
+	require.Equal(t, 4000.0, *r.TopicDetails[0].FetchBytesPerSec)
+	require.Equal(t, 0.0, *r.TopicDetails[1].ProduceBytesPerSec)
+	require.Equal(t, 2000.0, *r.ProduceBytesPerSec)
+	require.Equal(t, 4000.0, *r.FetchBytesPerSec)
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	retention := int64(3600000)
+	produced, fetched := 1.5, 3.0
+	r := clusterReport{
+		Partitions:         4,
+		DiskUsageBytes:     2048,
+		ProduceBytesPerSec: &produced,
+		FetchBytesPerSec:   &fetched,
+		TopicDetails: []topicReport{
+			{Name: "foo", Partitions: 3, ReplicationFactor: 3, CleanupPolicy: "delete", RetentionMs: &retention, SizeBytes: 2000, ProduceBytesPerSec: &produced, FetchBytesPerSec: &fetched},
+			{Name: "bar", Partitions: 1, ReplicationFactor: 1, CleanupPolicy: "compact", SizeBytes: 48},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, writeReportCSV(&buf, r))
+	require.Equal(t, `topic,partitions,replication_factor,cleanup_policy,retention_ms,retention_bytes,size_bytes,produce_bytes_per_sec,fetch_bytes_per_sec
+bar,1,1,compact,,,48,,
+foo,3,3,delete,3600000,,2000,1.50,3.00
+(total),4,,,,,2048,1.50,3.00
+`, buf.String())
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Args:  cobra.ExactArgs(0),
+		Short: "Inspect and trim the cloud storage cache of brokers",
+		Long: `Inspect and trim the cloud storage cache of brokers.
+
+Brokers keep the segments they download from cloud storage in a local cache,
+which is bounded by the cloud_storage_cache_size property. These commands
+report the usage of the cache of a broker, and evict segments from it to
+reclaim disk space without restarting the broker.
+`,
+	}
+	cmd.AddCommand(
+		newCacheStatusCommand(fs),
+		newCacheTrimCommand(fs),
+	)
+	return cmd
+}
+
+func newCacheStatusCommand(fs afero.Fs) *cobra.Command {
+	var broker int
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the usage of the cloud storage cache of a broker",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cl := newAdminClient(fs, cmd)
+
+			stats, err := cl.CloudStorageCacheStats(cmd.Context(), broker)
+			if admin.IsNotFound(err) {
+				out.Die("broker %d does not have a cloud storage cache", broker)
+			}
+			out.MaybeDie(err, "unable to get the cache status: %v", err)
+
+			maxObjects := "-"
+			if stats.MaxObjects > 0 {
+				maxObjects = fmt.Sprint(stats.MaxObjects)
+			}
+			tw := out.NewTable("BROKER", "SIZE", "MAX-SIZE", "OBJECTS", "MAX-OBJECTS", "LAST-TRIM")
+			defer tw.Flush()
+			tw.Print(
+				broker,
+				units.BytesSize(float64(stats.SizeBytes)),
+				units.BytesSize(float64(stats.MaxSizeBytes)),
+				stats.Objects,
+				maxObjects,
+				formatTimestamp(stats.LastTrim),
+			)
+		},
+	}
+	cmd.Flags().IntVar(&broker, "broker", -1, "Node ID of the broker")
+	cobra.MarkFlagRequired(cmd.Flags(), "broker")
+	return cmd
+}
+
+func newCacheTrimCommand(fs afero.Fs) *cobra.Command {
+	var (
+		broker     int
+		targetSize string
+		objects    int64
+	)
+	cmd := &cobra.Command{
+		Use:   "trim",
+		Short: "Evict segments from the cloud storage cache of a broker",
+		Long: `Evict segments from the cloud storage cache of a broker.
+
+By default, the broker trims its cache down to its configured limits. The
+--target-size and --target-objects flags trim further, down to the given
+size or number of objects; the least recently used segments are evicted
+first. Evicted segments are downloaded again when they are read.
+
+    rpk cluster storage cache trim --broker 1
+    rpk cluster storage cache trim --broker 1 --target-size 10GiB
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			var target admin.CacheTrimTarget
+			if targetSize != "" {
+				size, err := units.RAMInBytes(targetSize)
+				out.MaybeDie(err, "invalid --target-size %q: %v", targetSize, err)
+				if size <= 0 {
+					out.DieUsage("invalid --target-size %q, must be positive", targetSize)
+				}
+				target.SizeBytes = size
+			}
+			if objects < 0 {
+				out.DieUsage("invalid --target-objects %d, must be positive", objects)
+			}
+			target.Objects = objects
+
+			cl := newAdminClient(fs, cmd)
+
+			result, err := cl.TrimCloudStorageCache(cmd.Context(), broker, target)
+			if admin.IsNotFound(err) {
+				out.Die("broker %d does not have a cloud storage cache", broker)
+			}
+			out.MaybeDie(err, "unable to trim the cache: %v", err)
+
+			fmt.Printf("Trimmed %s (%d objects) from the cache of broker %d.\n",
+				units.BytesSize(float64(result.BytesTrimmed)), result.ObjectsTrimmed, broker)
+		},
+	}
+	cmd.Flags().IntVar(&broker, "broker", -1, "Node ID of the broker")
+	cobra.MarkFlagRequired(cmd.Flags(), "broker")
+	cmd.Flags().StringVar(&targetSize, "target-size", "", "Size to trim the cache down to (e.g. 10GiB)")
+	cmd.Flags().Int64Var(&objects, "target-objects", 0, "Number of objects to trim the cache down to")
+	return cmd
+}
+
+func newAdminClient(fs afero.Fs, cmd *cobra.Command) *admin.AdminAPI {
+	p := config.ParamsFromCommand(cmd)
+	cfg, err := p.Load(fs)
+	out.MaybeDie(err, "unable to load config: %v", err)
+
+	cl, err := admin.NewClient(fs, cfg)
+	out.MaybeDie(err, "unable to initialize admin client: %v", err)
+	return cl
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newManifestCommand(fs afero.Fs) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "manifest [NAMESPACE/]TOPIC/PARTITION",
+		Short: "Print the cloud storage manifest of a partition",
+		Long: `Print the cloud storage manifest of a partition.
+
+The manifest of a partition lists the segments of the partition that have
+been uploaded to cloud storage. For each segment, this command prints the
+range of raft offsets it contains, along with the corresponding kafka offsets
+(which exclude non-data batches), its size, and its timestamps. The namespace
+defaults to "kafka".
+
+Offsets that are not covered by any segment are printed as gaps after the
+table: consumers reading from cloud storage cannot read records in a gap.
+
+With "--format json", the manifest is printed as returned by the broker.
+
+    rpk cluster storage manifest foo/0
+    rpk cluster storage manifest kafka/foo/0 --format json
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ns, topic, partition, err := parseNTP(args[0])
+			out.MaybeDieErr(err)
+			if format != "text" && format != "json" {
+				out.DieUsage("invalid --format %q, must be text or json", format)
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			m, err := cl.GetPartitionManifest(cmd.Context(), ns, topic, partition)
+			if admin.IsNotFound(err) {
+				out.Die("no cloud storage manifest found for %s/%s/%d", ns, topic, partition)
+			}
+			out.MaybeDie(err, "unable to get the manifest: %v", err)
+
+			if format == "json" {
+				b, err := json.MarshalIndent(m, "", "  ")
+				out.MaybeDie(err, "unable to print the manifest as json: %v", err)
+				fmt.Printf("%s\n", b)
+				return
+			}
+			printManifest(m)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	return cmd
+}
+
+// parseNTP parses [namespace/]topic/partition, defaulting to the kafka
+// namespace.
+func parseNTP(s string) (ns, topic string, partition int, err error) {
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 2:
+		ns, topic = "kafka", parts[0]
+	case 3:
+		ns, topic = parts[0], parts[1]
+	default:
+		return "", "", 0, fmt.Errorf("invalid partition %q, must be [NAMESPACE/]TOPIC/PARTITION", s)
+	}
+	partition, err = strconv.Atoi(parts[len(parts)-1])
+	if err != nil || partition < 0 || ns == "" || topic == "" {
+		return "", "", 0, fmt.Errorf("invalid partition %q, must be [NAMESPACE/]TOPIC/PARTITION", s)
+	}
+	return ns, topic, partition, nil
+}
+
+func printManifest(m admin.PartitionManifest) {
+	segments := m.SortedSegments()
+	var size int64
+	for _, s := range segments {
+		size += s.SizeBytes
+	}
+
+	out.Section("MANIFEST")
+	fmt.Printf(`Partition:      %s/%s/%d
+Revision:       %d
+Last offset:    %d
+Segments:       %d
+Size:           %s
+`, m.Namespace, m.Topic, m.Partition, m.Revision, m.LastOffset, len(segments), units.BytesSize(float64(size)))
+	fmt.Println()
+
+	out.Section("SEGMENTS")
+	tw := out.NewTable(
+		"NAME",
+		"BASE-OFFSET",
+		"LAST-OFFSET",
+		"KAFKA-BASE-OFFSET",
+		"KAFKA-LAST-OFFSET",
+		"SIZE",
+		"COMPACTED",
+		"TERM",
+		"BASE-TIMESTAMP",
+		"MAX-TIMESTAMP",
+	)
+	for _, s := range segments {
+		tw.Print(
+			s.Name,
+			s.BaseOffset,
+			s.CommittedOffset,
+			s.KafkaBaseOffset(),
+			s.KafkaLastOffset(),
+			units.BytesSize(float64(s.SizeBytes)),
+			s.IsCompacted,
+			s.SegmentTerm,
+			formatTimestamp(s.BaseTimestamp),
+			formatTimestamp(s.MaxTimestamp),
+		)
+	}
+	tw.Flush()
+
+	if gaps := m.Gaps(); len(gaps) > 0 {
+		fmt.Println()
+		out.Section("GAPS")
+		tw := out.NewTable("FROM-OFFSET", "TO-OFFSET")
+		for _, g := range gaps {
+			tw.Print(g.From, g.To)
+		}
+		tw.Flush()
+	}
+}
+
+func formatTimestamp(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNTP(t *testing.T) {
+	for _, test := range []struct {
+		in        string
+		ns, topic string
+		partition int
+		expErr    bool
+	}{
+		{in: "foo/3", ns: "kafka", topic: "foo", partition: 3},
+		{in: "redpanda/controller/0", ns: "redpanda", topic: "controller", partition: 0},
+		{in: "foo", expErr: true},
+		{in: "foo/bar", expErr: true},
+		{in: "foo/-1", expErr: true},
+		{in: "/foo/0", expErr: true},
+		{in: "a/b/c/0", expErr: true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			ns, topic, partition, err := parseNTP(test.in)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.ns, ns)
+			require.Equal(t, test.topic, topic)
+			require.Equal(t, test.partition, partition)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// The number of cluster events kept in the watch view.
+const watchRecentEvents = 10
+
+func newWatchCommand(fs afero.Fs) *cobra.Command {
+	var (
+		interval time.Duration
+		once     bool
+
+		adminURL       string
+		adminEnableTLS bool
+		adminCertFile  string
+		adminKeyFile   string
+		adminCAFile    string
+	)
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Continuously display the status of the cluster",
+		Long: `Continuously display the status of the cluster.
+
+This command refreshes a view of the cluster every --interval, similar to
+'top'. While the admin API cannot be reached, the view is refreshed less often,
+as the retries back off. The view contains:
+
+* the cluster health: controller, nodes down, leaderless and under-replicated
+  partitions (under-replicated partitions are only reported by newer versions
+  of Redpanda)
+* the partition movements currently in flight, as reported by the partition
+  balancer
+* the status of every broker: liveness, membership and maintenance mode
+* the most recent cluster events: leadership changes, nodes going up or down
+  and cluster configuration changes
+
+If the output is not a terminal, each refresh is printed after the previous
+one instead of redrawing the screen. Use --once to print a single view and
+exit.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			if interval <= 0 {
+				out.DieUsage("invalid non-positive --interval %v", interval)
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			redraw := term.IsTerminal(int(os.Stdout.Fd()))
+			var recent []admin.ClusterEvent
+			draw := func(s clusterSnapshot) {
+				var buf bytes.Buffer
+				if redraw {
+					buf.WriteString("\033[H\033[2J") // move to the top left and clear the screen
+				}
+				printClusterSnapshot(&buf, s, recent)
+				os.Stdout.Write(buf.Bytes())
+			}
+
+			if once {
+				draw(takeClusterSnapshot(ctx, cl, nil))
+				return
+			}
+
+			// The view is refreshed on every observation of the
+			// subscription, from the health overview it observed.
+			for e := range cl.Subscribe(ctx, admin.SubscribeOptions{Interval: interval, Observed: true}) {
+				e := e
+				if e.Type == admin.ClusterEventObserved {
+					draw(takeClusterSnapshot(ctx, cl, &e))
+					continue
+				}
+				if recent = append(recent, e); len(recent) > watchRecentEvents {
+					recent = recent[len(recent)-watchRecentEvents:]
+				}
+				if e.Type == admin.ClusterEventError {
+					draw(takeClusterSnapshot(ctx, cl, &e))
+				}
+			}
+		},
+	}
+
+	cmd.Flags().DurationVarP(&interval, "interval", "i", 2*time.Second, "How often to refresh the view (e.g. 500ms, 5s)")
+	cmd.Flags().BoolVar(&once, "once", false, "Print the view once and exit")
+
+	cmd.PersistentFlags().StringVar(
+		&adminURL,
+		config.FlagAdminHosts2,
+		"",
+		"Comma-separated list of admin API addresses (<IP>:<port>")
+
+	common.AddAdminAPITLSFlags(cmd,
+		&adminEnableTLS,
+		&adminCertFile,
+		&adminKeyFile,
+		&adminCAFile,
+	)
+	return cmd
+}
+
+// clusterSnapshot is the state of the cluster displayed by a single refresh
+// of the watch view. Each part of the state is fetched independently so that
+// a single failing endpoint does not hide the rest of the view.
+type clusterSnapshot struct {
+	at time.Time
+
+	health    admin.ClusterHealthOverview
+	healthErr error
+
+	balancer    admin.PartitionBalancerStatus
+	balancerErr error
+
+	brokers    []admin.Broker
+	brokersErr error
+}
+
+// takeClusterSnapshot fetches the parts of the view that the cluster events
+// do not carry. The health overview is the one of the observed or error event
+// e, or is fetched too if e is nil.
+func takeClusterSnapshot(
+	ctx context.Context, cl *admin.AdminAPI, e *admin.ClusterEvent,
+) clusterSnapshot {
+	s := clusterSnapshot{at: time.Now()}
+	switch {
+	case e == nil:
+		s.health, s.healthErr = cl.GetHealthOverview(ctx)
+	case e.Type == admin.ClusterEventError:
+		s.at, s.healthErr = e.Time, e.Err
+	default:
+		s.at, s.health = e.Time, *e.Health
+	}
+	s.balancer, s.balancerErr = cl.GetPartitionStatus(ctx)
+	s.brokers, s.brokersErr = cl.Brokers(ctx)
+	return s
+}
+
+func printClusterSnapshot(
+	w io.Writer, s clusterSnapshot, recent []admin.ClusterEvent,
+) {
+	section := func(header string) {
+		fmt.Fprintln(w, strings.ToUpper(header))
+		fmt.Fprintln(w, strings.Repeat("=", len(header)))
+	}
+
+	section(fmt.Sprintf("cluster (updated %s)", s.at.Format("15:04:05")))
+	if s.healthErr != nil {
+		fmt.Fprintf(w, "unable to request cluster health: %v\n", s.healthErr)
+	} else {
+		tw := out.NewTabWriterTo(w)
+		tw.Print("Healthy:", s.health.IsHealthy)
+		tw.Print("Controller ID:", s.health.ControllerID)
+		tw.Print("Nodes down:", s.health.NodesDown)
+		tw.Print("Leaderless partitions:", len(s.health.LeaderlessPartitions))
+		if s.health.UnderReplicatedCount != nil {
+			tw.Print("Under-replicated partitions:", *s.health.UnderReplicatedCount)
+		} else {
+			tw.Print("Under-replicated partitions:", "-")
+		}
+		if s.balancerErr != nil {
+			tw.Print("Partition movements:", "-")
+		} else {
+			tw.Print("Partition movements:", fmt.Sprintf("%d (balancer %s)", s.balancer.CurrentReassignmentsCount, s.balancer.Status))
+		}
+		tw.Flush()
+	}
+	fmt.Fprintln(w)
+
+	section("brokers")
+	if s.brokersErr != nil {
+		fmt.Fprintf(w, "unable to request brokers: %v\n", s.brokersErr)
+	} else {
+		tw := out.NewTableTo(w, "ID", "Alive", "Membership", "Maintenance", "Version", "Cores")
+		for _, b := range s.brokers {
+			alive := "-"
+			if b.IsAlive != nil {
+				alive = fmt.Sprint(*b.IsAlive)
+			}
+			maintenance := "-"
+			if m := b.Maintenance; m != nil && m.Draining {
+				maintenance = "draining"
+				if m.Finished {
+					maintenance = "drained"
+				}
+			}
+			tw.Print(b.NodeID, alive, b.MembershipStatus, maintenance, b.Version, b.NumCores)
+		}
+		tw.Flush()
+	}
+
+	if len(recent) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	section("recent events")
+	tw := out.NewTabWriterTo(w)
+	for _, e := range recent {
+		switch e.Type {
+		case admin.ClusterEventError:
+			tw.Print(e.Time.Format("15:04:05"), e.Type, e.Err)
+		case admin.ClusterEventConfigVersion:
+			tw.Print(e.Time.Format("15:04:05"), e.Type, fmt.Sprintf("node %d is at config version %d", e.NodeID, e.ConfigVersion))
+		default:
+			tw.Print(e.Time.Format("15:04:05"), e.Type, fmt.Sprintf("node %d", e.NodeID))
+		}
+	}
+	tw.Flush()
+}
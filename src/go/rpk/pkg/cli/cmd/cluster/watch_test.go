@@ -0,0 +1,130 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintClusterSnapshot(t *testing.T) {
+	at := time.Date(2022, 6, 1, 12, 30, 15, 0, time.UTC)
+	alive, dead := true, false
+	underReplicated := 3
+	health := admin.ClusterHealthOverview{
+		IsHealthy:            false,
+		ControllerID:         1,
+		AllNodes:             []int{0, 1, 2},
+		NodesDown:            []int{2},
+		LeaderlessPartitions: []string{"kafka/foo/0"},
+		UnderReplicatedCount: &underReplicated,
+	}
+	brokers := []admin.Broker{
+		{NodeID: 0, NumCores: 2, MembershipStatus: admin.MembershipStatusActive, IsAlive: &alive, Version: "v22.2.1"},
+		{NodeID: 1, NumCores: 2, MembershipStatus: admin.MembershipStatusActive, IsAlive: &alive, Version: "v22.2.1", Maintenance: &admin.MaintenanceStatus{Draining: true}},
+		{NodeID: 2, NumCores: 2, MembershipStatus: admin.MembershipStatusDraining, IsAlive: &dead, Version: "v22.2.1", Maintenance: &admin.MaintenanceStatus{Draining: true, Finished: true}},
+		{NodeID: 3, NumCores: 1, MembershipStatus: admin.MembershipStatusActive},
+	}
+
+	for _, test := range []struct {
+		name   string
+		s      clusterSnapshot
+		recent []admin.ClusterEvent
+		exp    string
+	}{
+		{
+			name: "full snapshot",
+			s: clusterSnapshot{
+				at:       at,
+				health:   health,
+				balancer: admin.PartitionBalancerStatus{Status: "in_progress", CurrentReassignmentsCount: 4},
+				brokers:  brokers,
+			},
+			exp: `CLUSTER (UPDATED 12:30:15)
+==========================
+Healthy:                      false
+Controller ID:                1
+Nodes down:                   [2]
+Leaderless partitions:        1
+Under-replicated partitions:  3
+Partition movements:          4 (balancer in_progress)
+
+BROKERS
+=======
+ID    ALIVE  MEMBERSHIP  MAINTENANCE  VERSION  CORES
+0     true   active      -            v22.2.1  2
+1     true   active      draining     v22.2.1  2
+2     false  draining    drained      v22.2.1  2
+3     -      active      -                     1
+`,
+		},
+		{
+			name: "older brokers and unavailable balancer",
+			s: clusterSnapshot{
+				at:          at,
+				health:      admin.ClusterHealthOverview{IsHealthy: true, ControllerID: 0},
+				balancerErr: errors.New("not found"),
+				brokers:     brokers[:1],
+			},
+			exp: `CLUSTER (UPDATED 12:30:15)
+==========================
+Healthy:                      true
+Controller ID:                0
+Nodes down:                   []
+Leaderless partitions:        0
+Under-replicated partitions:  -
+Partition movements:          -
+
+BROKERS
+=======
+ID    ALIVE  MEMBERSHIP  MAINTENANCE  VERSION  CORES
+0     true   active      -            v22.2.1  2
+`,
+		},
+		{
+			name: "unreachable cluster with recent events",
+			s: clusterSnapshot{
+				at:         at,
+				healthErr:  errors.New("connection refused"),
+				brokersErr: errors.New("connection refused"),
+			},
+			recent: []admin.ClusterEvent{
+				{Type: admin.ClusterEventLeaderChanged, Time: at.Add(-time.Minute), NodeID: 1},
+				{Type: admin.ClusterEventConfigVersion, Time: at.Add(-time.Minute), NodeID: 0, ConfigVersion: 7},
+				{Type: admin.ClusterEventError, Time: at, NodeID: -1, Err: errors.New("connection refused")},
+			},
+			exp: `CLUSTER (UPDATED 12:30:15)
+==========================
+unable to request cluster health: connection refused
+
+BROKERS
+=======
+unable to request brokers: connection refused
+
+RECENT EVENTS
+=============
+12:29:15  leader_changed          node 1
+12:29:15  config_version_changed  node 0 is at config version 7
+12:30:15  error                   connection refused
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printClusterSnapshot(&buf, test.s, test.recent)
+			require.Equal(t, test.exp, buf.String())
+		})
+	}
+}
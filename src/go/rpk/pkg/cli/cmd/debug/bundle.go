@@ -11,6 +11,7 @@ package debug
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/docker/go-units"
@@ -21,10 +22,33 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kgo"
 )
 
+type bundleParams struct {
+	fs             afero.Fs
+	cfg            *config.Config
+	cl             *kgo.Client
+	admin          *admin.AdminAPI
+	logsSince      string
+	logsUntil      string
+	logsLimitBytes int
+	noLogs         bool
+	timeout        time.Duration
+
+	// maxBytes, if positive, limits the size of the files of the bundle.
+	maxBytes int
+	// redact, if true, redacts credentials and hostnames from the bundle.
+	redact bool
+
+	// k8s, if true, collects the Kubernetes resources and pod logs in
+	// namespace through the API server instead of reading journald logs.
+	k8s       bool
+	namespace string
+}
+
 // Use the same date specs as journalctl (see `man journalctl`).
-const timeHelpText = `(journalctl date format, e.g. YYYY-MM-DD)`
+const timeHelpText = `(journalctl date format: YYYY-MM-DD [HH:MM[:SS]], now, today, yesterday, tomorrow, or relative to now such as -1h or "2 days ago")`
 
 func newBundleCommand(fs afero.Fs) *cobra.Command {
 	var (
@@ -48,8 +72,19 @@ func newBundleCommand(fs afero.Fs) *cobra.Command {
 		logsSince     string
 		logsUntil     string
 		logsSizeLimit string
+		noLogs        bool
+
+		maxSize  string
+		noRedact bool
 
 		timeout time.Duration
+
+		k8s       bool
+		namespace string
+
+		upload      bool
+		uploadURL   string
+		uploadToken string
 	)
 	command := &cobra.Command{
 		Use:   "bundle",
@@ -60,7 +95,7 @@ func newBundleCommand(fs afero.Fs) *cobra.Command {
 			cfg, err := p.Load(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 
-			admin, err := admin.NewClient(fs, cfg)
+			adm, err := admin.NewClient(fs, cfg)
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
 			cl, err := kafka.NewFranzClient(fs, p, cfg)
@@ -70,8 +105,43 @@ func newBundleCommand(fs afero.Fs) *cobra.Command {
 			logsLimit, err := units.FromHumanSize(logsSizeLimit)
 			out.MaybeDie(err, "unable to parse --logs-size-limit: %v", err)
 
-			err = executeBundle(cmd.Context(), fs, cfg, cl, admin, logsSince, logsUntil, int(logsLimit), timeout)
+			var maxBytes int64
+			if maxSize != "" {
+				maxBytes, err = units.FromHumanSize(maxSize)
+				out.MaybeDie(err, "unable to parse --max-size: %v", err)
+				if maxBytes <= 0 {
+					out.DieUsage("invalid --max-size %q, must be positive", maxSize)
+				}
+			}
+
+			if !cmd.Flags().Changed("k8s") {
+				k8s = isRunningInK8s(fs)
+			}
+
+			filename, err := executeBundle(cmd.Context(), bundleParams{
+				fs:             fs,
+				cfg:            cfg,
+				cl:             cl,
+				admin:          adm,
+				logsSince:      logsSince,
+				logsUntil:      logsUntil,
+				logsLimitBytes: int(logsLimit),
+				noLogs:         noLogs,
+				timeout:        timeout,
+				maxBytes:       int(maxBytes),
+				redact:         !noRedact,
+				k8s:            k8s,
+				namespace:      namespace,
+			})
 			out.MaybeDie(err, "unable to create bundle: %v", err)
+
+			if upload {
+				err = uploadBundle(cmd.Context(), fs, adm, filename, admin.DebugBundleUploadOptions{
+					URL:   uploadURL,
+					Token: uploadToken,
+				})
+				out.MaybeDie(err, "unable to upload bundle: %v", err)
+			}
 		},
 	}
 	command.Flags().StringVar(
@@ -104,6 +174,55 @@ func newBundleCommand(fs afero.Fs) *cobra.Command {
 		"100MiB",
 		"Read the logs until the given size is reached. Multipliers are also supported, e.g. 3MB, 1GiB",
 	)
+	command.Flags().BoolVar(
+		&noLogs,
+		"no-logs",
+		false,
+		"Do not include the redpanda logs (or the pod logs in --k8s mode)",
+	)
+	command.Flags().StringVar(
+		&maxSize,
+		"max-size",
+		"",
+		"Limit the total size of the files in the bundle, before compression, e.g. 50MiB. Files past the limit are truncated or skipped and listed in errors.txt",
+	)
+	command.Flags().BoolVar(
+		&noRedact,
+		"no-redact",
+		false,
+		"Do not redact credentials and hostnames from the bundle",
+	)
+	command.Flags().BoolVar(
+		&k8s,
+		"k8s",
+		false,
+		"Collect Kubernetes resources, events and pod logs through the API server. Defaults to true when running inside a Kubernetes pod",
+	)
+	command.Flags().StringVar(
+		&namespace,
+		"namespace",
+		"",
+		"The Kubernetes namespace to collect resources from in --k8s mode. Defaults to the namespace of the pod rpk runs in",
+	)
+
+	command.Flags().BoolVar(
+		&upload,
+		"upload",
+		false,
+		"Upload the bundle to the admin API of the brokers, or to --upload-url. An interrupted upload is resumed by uploading the same file again",
+	)
+	command.Flags().StringVar(
+		&uploadURL,
+		"upload-url",
+		"",
+		"The base URL of the bundle intake endpoint to upload to instead of the admin API, e.g. the one given by the Redpanda Data support team",
+	)
+	command.Flags().StringVar(
+		&uploadToken,
+		"upload-token",
+		os.Getenv("RPK_BUNDLE_UPLOAD_TOKEN"),
+		"The token to authenticate to --upload-url with (default $RPK_BUNDLE_UPLOAD_TOKEN)",
+	)
 
 	common.AddKafkaFlags(
 		command,
@@ -177,4 +296,38 @@ The following are the data sources that are bundled in the compressed file:
 
  - dmidecode: The DMI table contents. Only included if this command is run
    as root.
+
+If --k8s is passed, or rpk detects that it is running inside a Kubernetes pod,
+the following are also collected through the Kubernetes API server, using the
+pod's service account, from the namespace given with --namespace (by default,
+the pod's own namespace). In this mode, pod logs replace the journald logs:
+
+ - Kubernetes resources: Pods, events, services, StatefulSets, persistent
+   volume claims and Redpanda Cluster custom resources. Secrets and
+   ConfigMaps are never collected.
+
+ - Pod logs: The logs of each container in each pod, and of the previous
+   container instance if it restarted. --logs-since, --logs-until and
+   --logs-size-limit are honored; a date that cannot be parsed is ignored with
+   a warning.
+
+ - Admin API data: The brokers, cluster health overview and cluster config
+   status, as reported by the admin API.
+
+Credentials, such as passwords, secrets and tokens, are redacted from every
+file of the bundle. So are the hostnames of the configuration, of the brokers
+and of this host, and the DNS names of the configured TLS certificates: they
+are replaced by placeholders, e.g. host-1 or broker-0.domain-1 for a wildcard
+certificate name. The placeholders are mapped back to the hostnames in a
+redaction map file saved next to the bundle, which is not part of it. Share
+the bundle without the map to keep the hostnames private. IP addresses are
+not redacted. Pass --no-redact to disable redaction.
+
+--no-logs leaves the logs out of the bundle, and --max-size limits its size,
+which is otherwise dominated by the logs (see --logs-size-limit).
+
+With --upload, the bundle is then uploaded in compressed chunks to the bundle
+intake endpoint of the admin API, or to --upload-url when the Redpanda Data
+support team gave you one. If the upload is interrupted, uploading the same
+file again continues where it stopped.
 `
@@ -14,23 +14,14 @@ package debug
 import (
 	"context"
 	"errors"
-	"time"
 
-	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
-	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/spf13/afero"
-	"github.com/twmb/franz-go/pkg/kgo"
 )
 
-func executeBundle(
-	context.Context,
-	afero.Fs,
-	*config.Config,
-	*kgo.Client,
-	*admin.AdminAPI,
-	string, string,
-	int,
-	time.Duration,
-) error {
-	return errors.New("rpk debug bundle is unsupported on your operating system")
+func executeBundle(context.Context, bundleParams) (string, error) {
+	return "", errors.New("rpk debug bundle is unsupported on your operating system")
+}
+
+func isRunningInK8s(afero.Fs) bool {
+	return false
 }
@@ -0,0 +1,435 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// The service account credentials that Kubernetes mounts into every pod.
+const k8sServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// The namespaced resources that are collected in Kubernetes mode, by the name
+// of the file they are saved to. Secrets and ConfigMaps are deliberately left
+// out, since they may contain credentials.
+var k8sResources = []struct {
+	name string
+	path string
+}{
+	{"pods", "/api/v1/namespaces/%s/pods"},
+	{"events", "/api/v1/namespaces/%s/events"},
+	{"services", "/api/v1/namespaces/%s/services"},
+	{"persistentvolumeclaims", "/api/v1/namespaces/%s/persistentvolumeclaims"},
+	{"statefulsets", "/apis/apps/v1/namespaces/%s/statefulsets"},
+	{"clusters", "/apis/redpanda.vectorized.io/v1alpha1/namespaces/%s/clusters"},
+}
+
+// isRunningInK8s returns whether rpk is running inside a Kubernetes pod with
+// a mounted service account.
+func isRunningInK8s(fs afero.Fs) bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return false
+	}
+	exists, _ := afero.Exists(fs, filepath.Join(k8sServiceAccountDir, "token"))
+	return exists
+}
+
+// k8sAPI is a minimal read only client for the Kubernetes API server, using
+// the in-cluster service account credentials.
+type k8sAPI struct {
+	host  string
+	token string
+	cl    *http.Client
+}
+
+func newInClusterK8sAPI(fs afero.Fs) (*k8sAPI, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("unable to find the Kubernetes API server: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set")
+	}
+	token, err := afero.ReadFile(fs, filepath.Join(k8sServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the service account token: %w", err)
+	}
+	ca, err := afero.ReadFile(fs, filepath.Join(k8sServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("unable to parse the service account CA")
+	}
+	return &k8sAPI{
+		host:  "https://" + net.JoinHostPort(host, port),
+		token: strings.TrimSpace(string(token)),
+		cl: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:    pool,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		},
+	}, nil
+}
+
+// get issues a GET request for the given API path, reading at most limitBytes
+// of the response if limitBytes is positive.
+func (k *k8sAPI) get(
+	ctx context.Context, path string, query url.Values, limitBytes int,
+) ([]byte, error) {
+	u := k.host + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+k.token)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := k.cl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var r io.Reader = res.Body
+	if limitBytes > 0 {
+		r = io.LimitReader(r, int64(limitBytes))
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read GET %s response body: %w", path, err)
+	}
+	if res.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("GET %s failed: %s, body: %q", path, http.StatusText(res.StatusCode), body)
+	}
+	return body, nil
+}
+
+// k8sPodList is the subset of a v1.PodList needed to find container logs.
+type k8sPodList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			InitContainers []struct {
+				Name string `json:"name"`
+			} `json:"initContainers"`
+			Containers []struct {
+				Name string `json:"name"`
+			} `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			ContainerStatuses []struct {
+				Name         string `json:"name"`
+				RestartCount int    `json:"restartCount"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// saveK8SData returns the steps that collect Kubernetes resources, pod logs
+// and admin API data. If the API server cannot be reached, a single failing
+// step is returned so that the error ends up in the bundle's errors.txt.
+func saveK8SData(ctx context.Context, ps *stepParams, bp bundleParams) []step {
+	adminStep := saveAdminAPIData(ctx, ps, bp.admin)
+
+	k8s, err := newInClusterK8sAPI(bp.fs)
+	if err != nil {
+		return []step{adminStep, func() error {
+			return fmt.Errorf("unable to collect Kubernetes data: %w", err)
+		}}
+	}
+	namespace := bp.namespace
+	if namespace == "" {
+		ns, err := afero.ReadFile(bp.fs, filepath.Join(k8sServiceAccountDir, "namespace"))
+		if err != nil {
+			return []step{adminStep, func() error {
+				return fmt.Errorf("unable to determine the pod namespace, please pass --namespace: %w", err)
+			}}
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+
+	steps := []step{
+		adminStep,
+		saveK8SResources(ctx, ps, k8s, namespace),
+	}
+	if !bp.noLogs {
+		steps = append(steps, saveK8SPodLogs(ctx, ps, k8s, namespace, bp.logsSince, bp.logsUntil, bp.logsLimitBytes))
+	}
+	return steps
+}
+
+// Saves the namespaced resources in k8sResources as JSON lists.
+func saveK8SResources(
+	ctx context.Context, ps *stepParams, k8s *k8sAPI, namespace string,
+) step {
+	return func() error {
+		log.Debugf("Reading Kubernetes resources in namespace %q", namespace)
+		var grp multierror.Group
+		for _, r := range k8sResources {
+			r := r
+			grp.Go(func() error {
+				ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+				defer cancel()
+				body, err := k8s.get(ctx, fmt.Sprintf(r.path, url.PathEscape(namespace)), nil, -1)
+				if err != nil {
+					return fmt.Errorf("unable to list %s: %w", r.name, err)
+				}
+				return writeFileToZip(ps, filepath.Join("k8s", r.name+".json"), body)
+			})
+		}
+		return grp.Wait().ErrorOrNil()
+	}
+}
+
+// Saves the logs of every container in every pod of the namespace, including
+// the logs of the previous instance of containers that restarted.
+func saveK8SPodLogs(
+	ctx context.Context,
+	ps *stepParams,
+	k8s *k8sAPI,
+	namespace, since, until string,
+	logsLimitBytes int,
+) step {
+	return func() error {
+		listCtx, cancel := context.WithTimeout(ctx, ps.timeout)
+		defer cancel()
+		raw, err := k8s.get(listCtx, fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(namespace)), nil, -1)
+		if err != nil {
+			return fmt.Errorf("unable to list pods: %w", err)
+		}
+		var pods k8sPodList
+		if err := json.Unmarshal(raw, &pods); err != nil {
+			return fmt.Errorf("unable to decode pod list: %w", err)
+		}
+
+		query := url.Values{}
+		if logsLimitBytes > 0 {
+			query.Set("limitBytes", strconv.Itoa(logsLimitBytes))
+		}
+		// The time filters are skipped rather than failing the step, so that
+		// the logs are still collected with a date that we cannot parse.
+		now := time.Now()
+		if since != "" {
+			if sinceTime, err := parseJournalTime(since, now); err != nil {
+				log.Warnf("Collecting the pod logs without --logs-since: %v", err)
+			} else {
+				query.Set("sinceTime", sinceTime.Format(time.RFC3339))
+			}
+		}
+		// The API server has no end time filter: we request timestamped
+		// lines and drop the ones written after --logs-until ourselves.
+		var untilTime time.Time
+		if until != "" {
+			if untilTime, err = parseJournalTime(until, now); err != nil {
+				log.Warnf("Collecting the pod logs without --logs-until: %v", err)
+			} else {
+				query.Set("timestamps", "true")
+			}
+		}
+
+		var grp multierror.Group
+		save := func(pod, container string, previous bool) {
+			q := url.Values{}
+			for k, v := range query {
+				q[k] = v
+			}
+			q.Set("container", container)
+			filename := fmt.Sprintf("%s-%s.log", pod, container)
+			if previous {
+				q.Set("previous", "true")
+				filename = fmt.Sprintf("%s-%s-previous.log", pod, container)
+			}
+			grp.Go(func() error {
+				ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+				defer cancel()
+				path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log", url.PathEscape(namespace), url.PathEscape(pod))
+				body, err := k8s.get(ctx, path, q, logsLimitBytes)
+				if err != nil {
+					return fmt.Errorf("unable to get logs for container %q in pod %q: %w", container, pod, err)
+				}
+				if !untilTime.IsZero() {
+					body = filterLogsUntil(body, untilTime)
+				}
+				return writeFileToZip(ps, filepath.Join("k8s", "logs", filename), body)
+			})
+		}
+		for _, p := range pods.Items {
+			pod := p.Metadata.Name
+			for _, c := range p.Spec.InitContainers {
+				save(pod, c.Name, false)
+			}
+			for _, c := range p.Spec.Containers {
+				save(pod, c.Name, false)
+			}
+			for _, cs := range p.Status.ContainerStatuses {
+				if cs.RestartCount > 0 {
+					save(pod, cs.Name, true)
+				}
+			}
+		}
+		return grp.Wait().ErrorOrNil()
+	}
+}
+
+// filterLogsUntil keeps the lines of logs requested with timestamps=true up to
+// the first one written after until, and strips their timestamp. Lines without
+// a timestamp, such as the remainder of a line cut by limitBytes, are kept.
+func filterLogsUntil(logs []byte, until time.Time) []byte {
+	var filtered []byte
+	for _, line := range bytes.SplitAfter(logs, []byte("\n")) {
+		sp := bytes.IndexByte(line, ' ')
+		if sp < 0 {
+			filtered = append(filtered, line...)
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339Nano, string(line[:sp]))
+		if err != nil {
+			filtered = append(filtered, line...)
+			continue
+		}
+		if ts.After(until) {
+			break // container logs are in chronological order
+		}
+		filtered = append(filtered, line[sp+1:]...)
+	}
+	return filtered
+}
+
+// parseJournalTime parses the subset of the journalctl date formats accepted
+// by --logs-since and --logs-until: absolute dates, RFC3339, the now, today,
+// yesterday and tomorrow keywords, and times relative to now such as -1h,
+// +30min or "2 days ago".
+func parseJournalTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	}
+	for _, layout := range []string{
+		time.RFC3339,
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if d, ok := parseJournalSpan(s); ok {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported date %q, expected YYYY-MM-DD [HH:MM[:SS]], now, today, yesterday, tomorrow or a relative time such as -1h", s)
+}
+
+var journalSpanUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// parseJournalSpan parses a single journalctl time span relative to now,
+// written as -N<unit>, +N<unit> or "N <unit> ago".
+func parseJournalSpan(s string) (time.Duration, bool) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasSuffix(s, " ago"):
+		s, sign = strings.TrimSuffix(s, " ago"), -1
+	case strings.HasPrefix(s, "-"):
+		s, sign = s[1:], -1
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	default:
+		return 0, false
+	}
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if i <= 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:i])
+	unit, ok := journalSpanUnits[strings.TrimSpace(s[i:])]
+	if err != nil || !ok {
+		return 0, false
+	}
+	return sign * time.Duration(n) * unit, true
+}
+
+// Saves cluster wide information from the admin API.
+func saveAdminAPIData(
+	ctx context.Context, ps *stepParams, cl *admin.AdminAPI,
+) step {
+	return func() error {
+		ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+		defer cancel()
+
+		var grp multierror.Group
+		save := func(filename string, fn func() (interface{}, error)) {
+			grp.Go(func() error {
+				resp, err := fn()
+				if err != nil {
+					return fmt.Errorf("unable to save %s: %w", filename, err)
+				}
+				raw, err := json.MarshalIndent(resp, "", "  ")
+				if err != nil {
+					return fmt.Errorf("unable to encode %s: %w", filename, err)
+				}
+				return writeFileToZip(ps, filepath.Join("admin", filename), raw)
+			})
+		}
+		save("brokers.json", func() (interface{}, error) {
+			return cl.Brokers(ctx)
+		})
+		save("health_overview.json", func() (interface{}, error) {
+			return cl.GetHealthOverview(ctx)
+		})
+		save("cluster_config_status.json", func() (interface{}, error) {
+			return cl.ClusterConfigStatus(ctx, true)
+		})
+		return grp.Wait().ErrorOrNil()
+	}
+}
@@ -46,26 +46,18 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-func executeBundle(
-	ctx context.Context,
-	fs afero.Fs,
-	conf *config.Config,
-	cl *kgo.Client,
-	admin *admin.AdminAPI,
-	logsSince, logsUntil string,
-	logsLimitBytes int,
-	timeout time.Duration,
-) error {
+// executeBundle creates the bundle and returns the name of its file.
+func executeBundle(ctx context.Context, bp bundleParams) (string, error) {
 	mode := os.FileMode(0o755)
 	timestamp := time.Now().Unix()
 	filename := fmt.Sprintf("%d-bundle.zip", timestamp)
-	f, err := fs.OpenFile(
+	f, err := bp.fs.OpenFile(
 		filename,
 		os.O_CREATE|os.O_WRONLY,
 		mode,
 	)
 	if err != nil {
-		return fmt.Errorf("couldn't create bundle file: %w", err)
+		return "", fmt.Errorf("couldn't create bundle file: %w", err)
 	}
 	defer f.Close()
 
@@ -75,24 +67,27 @@ func executeBundle(
 	defer w.Close()
 
 	ps := &stepParams{
-		fs:      fs,
-		w:       w,
-		timeout: timeout,
+		fs:       bp.fs,
+		w:        w,
+		timeout:  bp.timeout,
+		maxBytes: bp.maxBytes,
+	}
+	if bp.redact {
+		ps.redactor = newBundleRedactor(ctx, bp)
 	}
 
 	steps := []step{
-		saveKafkaMetadata(ctx, ps, cl),
-		saveDataDirStructure(ps, conf),
-		saveConfig(ps, conf),
+		saveKafkaMetadata(ctx, ps, bp.cl),
+		saveDataDirStructure(ps, bp.cfg),
+		saveConfig(ps, bp.cfg),
 		saveCPUInfo(ps),
 		saveInterrupts(ps),
-		saveResourceUsageData(ps, conf),
+		saveResourceUsageData(ps, bp.cfg),
 		saveNTPDrift(ps),
 		saveSyslog(ps),
-		savePrometheusMetrics(ctx, ps, admin),
+		savePrometheusMetrics(ctx, ps, bp.admin),
 		saveDNSData(ctx, ps),
-		saveDiskUsage(ctx, ps, conf),
-		saveLogs(ctx, ps, logsSince, logsUntil, logsLimitBytes),
+		saveDiskUsage(ctx, ps, bp.cfg),
 		saveSocketData(ctx, ps),
 		saveTopOutput(ctx, ps),
 		saveVmstat(ctx, ps),
@@ -100,6 +95,11 @@ func executeBundle(
 		saveLspci(ctx, ps),
 		saveDmidecode(ctx, ps),
 	}
+	if bp.k8s {
+		steps = append(steps, saveK8SData(ctx, ps, bp)...)
+	} else if !bp.noLogs {
+		steps = append(steps, saveLogs(ctx, ps, bp.logsSince, bp.logsUntil, bp.logsLimitBytes))
+	}
 
 	for _, s := range steps {
 		grp.Go(s)
@@ -107,6 +107,8 @@ func executeBundle(
 
 	errs := grp.Wait()
 	if errs != nil {
+		// The errors are always saved, even past --max-size.
+		ps.maxBytes = 0
 		err := writeFileToZip(ps, "errors.txt", []byte(errs.Error()))
 		if err != nil {
 			errs = multierror.Append(errs, err)
@@ -115,6 +117,51 @@ func executeBundle(
 	}
 
 	log.Infof("Debug bundle saved to '%s'", filename)
+	if ps.redactor != nil {
+		if err := saveRedactionMap(bp.fs, ps.redactor, filename); err != nil {
+			return "", err
+		}
+	}
+	return filename, nil
+}
+
+// newBundleRedactor returns a redactor of the hostnames of the config, of the
+// SANs of its certificates, of the brokers of the cluster and of this host.
+func newBundleRedactor(ctx context.Context, bp bundleParams) *redactor {
+	r := newRedactor()
+	r.addConfigHosts(bp.fs, bp.cfg)
+	if hostname, err := os.Hostname(); err == nil {
+		r.addHost(hostname)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	meta, err := kadm.NewClient(bp.cl).BrokerMetadata(ctx)
+	if err != nil {
+		log.Debugf("Unable to list the brokers to redact their hostnames: %v", err)
+	}
+	for _, b := range meta.Brokers {
+		r.addHost(b.Host)
+	}
+	return r
+}
+
+// saveRedactionMap saves the hostnames behind the placeholders of the bundle
+// next to it, e.g. 1650000000-bundle-redaction-map.json. It is never part of
+// the bundle, so that the bundle can be shared without it.
+func saveRedactionMap(fs afero.Fs, r *redactor, bundleFilename string) error {
+	m := r.mapping()
+	if len(m) == 0 {
+		return nil
+	}
+	bs, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode the redaction map: %w", err)
+	}
+	filename := strings.TrimSuffix(bundleFilename, ".zip") + "-redaction-map.json"
+	if err := afero.WriteFile(fs, filename, bs, 0o600); err != nil {
+		return fmt.Errorf("unable to save the redaction map: %w", err)
+	}
+	log.Infof("Redaction map saved to '%s', keep it out of shared bundles", filename)
 	return nil
 }
 
@@ -125,6 +172,26 @@ type stepParams struct {
 	m       sync.Mutex
 	w       *zip.Writer
 	timeout time.Duration
+
+	// redactor, if non-nil, redacts the files written to the bundle.
+	redactor *redactor
+
+	// maxBytes, if positive, limits the bytes of all the files of the
+	// bundle, of which written are already written; both are guarded by m.
+	maxBytes int
+	written  int
+}
+
+// leftBytes returns how many bytes of the bundle are left for a file, or -1
+// if the bundle is not limited. It must be called with ps.m held.
+func (ps *stepParams) leftBytes() int {
+	if ps.maxBytes <= 0 {
+		return -1
+	}
+	if left := ps.maxBytes - ps.written; left > 0 {
+		return left
+	}
+	return 0
 }
 
 type fileInfo struct {
@@ -168,14 +235,30 @@ func writeFileToZip(ps *stepParams, filename string, contents []byte) error {
 	ps.m.Lock()
 	defer ps.m.Unlock()
 
+	left := ps.leftBytes()
+	if left == 0 {
+		return fmt.Errorf("skipped '%s': the bundle reached --max-size", filename)
+	}
+	if ps.redactor != nil {
+		contents = ps.redactor.redact(contents)
+	}
+	truncated := left > 0 && len(contents) > left
+	if truncated {
+		contents = contents[:left]
+	}
+
 	wr, err := ps.w.Create(filename)
 	if err != nil {
 		return err
 	}
 	_, err = wr.Write(contents)
+	ps.written += len(contents)
 	if err != nil {
 		return fmt.Errorf("couldn't save '%s': %w", filename, err)
 	}
+	if truncated {
+		return fmt.Errorf("truncated '%s': the bundle reached --max-size", filename)
+	}
 	return nil
 }
 
@@ -191,6 +274,15 @@ func writeCommandOutputToZipLimit(
 	ps.m.Lock()
 	defer ps.m.Unlock()
 
+	left := ps.leftBytes()
+	if left == 0 {
+		return fmt.Errorf("skipped '%s': the bundle reached --max-size", filename)
+	}
+	limitedByBundle := left > 0 && (outputLimitBytes <= 0 || left < outputLimitBytes)
+	if limitedByBundle {
+		outputLimitBytes = left
+	}
+
 	ctx, cancel := context.WithTimeout(rootCtx, ps.timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, command, args...)
@@ -203,11 +295,18 @@ func writeCommandOutputToZipLimit(
 		return err
 	}
 
+	var lw *limitedWriter
 	if outputLimitBytes > 0 {
-		wr = &limitedWriter{
+		lw = &limitedWriter{
 			w:          wr,
 			limitBytes: outputLimitBytes,
 		}
+		wr = lw
+	}
+	var rw *redactingWriter
+	if ps.redactor != nil {
+		rw = &redactingWriter{w: wr, r: ps.redactor}
+		wr = rw
 	}
 
 	cmd.Stdout = wr
@@ -219,6 +318,16 @@ func writeCommandOutputToZipLimit(
 	}
 
 	err = cmd.Wait()
+	if rw != nil {
+		// Past the limit, the flush only fails to write the truncated end.
+		_ = rw.Flush()
+	}
+	if lw != nil {
+		ps.written += lw.accumBytes
+		if limitedByBundle && lw.accumBytes >= lw.limitBytes {
+			return fmt.Errorf("truncated '%s': the bundle reached --max-size", filename)
+		}
+	}
 	if err != nil {
 		if !strings.Contains(err.Error(), "broken pipe") {
 			return fmt.Errorf("couldn't save '%s': %w", filename, err)
@@ -577,7 +686,6 @@ func saveDiskUsage(ctx context.Context, ps *stepParams, conf *config.Config) ste
 	}
 }
 
-// TODO: What if running inside a container/ k8s?
 // Writes the journald redpanda logs, if available, to the bundle.
 func saveLogs(ctx context.Context, ps *stepParams, since, until string, logsLimitBytes int) step {
 	return func() error {
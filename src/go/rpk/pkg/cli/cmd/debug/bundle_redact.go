@@ -0,0 +1,237 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+// +build linux
+
+package debug
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/spf13/afero"
+)
+
+// credentialRe matches the value of credential looking keys, in YAML, JSON,
+// flags and key=value pairs: password: x, "token":"x", --secret-key=x.
+var credentialRe = regexp.MustCompile(`(?i)((?:password|passwd|secret[_-]?key|secret|token|api[_-]?key|access[_-]?key)["']?\s*[:=]\s*["']?)([^\s"',}]+)`)
+
+// redactor replaces credentials and customer hostnames in the files of the
+// bundle. Hostnames are replaced by stable placeholders, e.g. host-1, so that
+// the bundle stays readable; wildcard SANs redact their whole domain, e.g.
+// broker-0.domain-1. The placeholders can be mapped back to the hostnames
+// with the map returned by mapping, which is kept out of the bundle.
+type redactor struct {
+	placeholders map[string]string // lowercase hostname or domain => placeholder
+	nhosts       int
+	ndomains     int
+	re           *regexp.Regexp
+}
+
+func newRedactor() *redactor {
+	return &redactor{placeholders: make(map[string]string)}
+}
+
+// addHost adds a hostname, host:port address or SAN to redact. IP addresses
+// are left as is, as they are needed to debug networking issues, and so are
+// unqualified names, e.g. localhost or redpanda-0: they identify nothing and
+// would redact ordinary words of the logs.
+func (r *redactor) addHost(h string) {
+	if host, _, err := net.SplitHostPort(h); err == nil {
+		h = host
+	}
+	h = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(h), "."))
+	domain := strings.HasPrefix(h, "*.")
+	h = strings.TrimPrefix(h, "*.")
+	if !strings.Contains(h, ".") || net.ParseIP(h) != nil {
+		return
+	}
+	if _, ok := r.placeholders[h]; ok {
+		return
+	}
+	if domain {
+		r.ndomains++
+		r.placeholders[h] = fmt.Sprintf("domain-%d", r.ndomains)
+	} else {
+		r.nhosts++
+		r.placeholders[h] = fmt.Sprintf("host-%d", r.nhosts)
+	}
+	r.re = nil
+}
+
+// addConfigHosts adds the addresses of the config, and the SANs of its
+// certificates.
+func (r *redactor) addConfigHosts(fs afero.Fs, cfg *config.Config) {
+	rp := cfg.Redpanda
+	for _, s := range rp.SeedServers {
+		r.addHost(s.Host.Address)
+	}
+	r.addHost(rp.RPCServer.Address)
+	if rp.AdvertisedRPCAPI != nil {
+		r.addHost(rp.AdvertisedRPCAPI.Address)
+	}
+	for _, a := range rp.KafkaAPI {
+		r.addHost(a.Address)
+	}
+	for _, a := range rp.AdvertisedKafkaAPI {
+		r.addHost(a.Address)
+	}
+	for _, a := range rp.AdminAPI {
+		r.addHost(a.Address)
+	}
+	if pp := cfg.Pandaproxy; pp != nil {
+		for _, a := range pp.PandaproxyAPI {
+			r.addHost(a.Address)
+		}
+		for _, a := range pp.AdvertisedPandaproxyAPI {
+			r.addHost(a.Address)
+		}
+	}
+	if sr := cfg.SchemaRegistry; sr != nil {
+		for _, a := range sr.SchemaRegistryAPI {
+			r.addHost(a.Address)
+		}
+	}
+	for _, b := range cfg.Rpk.KafkaAPI.Brokers {
+		r.addHost(b)
+	}
+	for _, a := range cfg.Rpk.AdminAPI.Addresses {
+		r.addHost(a)
+	}
+
+	var certFiles []string
+	for _, tlss := range [][]config.ServerTLS{rp.RPCServerTLS, rp.KafkaAPITLS, rp.AdminAPITLS} {
+		for _, t := range tlss {
+			certFiles = append(certFiles, t.CertFile)
+		}
+	}
+	if pp := cfg.Pandaproxy; pp != nil {
+		for _, t := range pp.PandaproxyAPITLS {
+			certFiles = append(certFiles, t.CertFile)
+		}
+	}
+	if sr := cfg.SchemaRegistry; sr != nil {
+		for _, t := range sr.SchemaRegistryAPITLS {
+			certFiles = append(certFiles, t.CertFile)
+		}
+	}
+	for _, t := range []*config.TLS{cfg.Rpk.KafkaAPI.TLS, cfg.Rpk.AdminAPI.TLS} {
+		if t != nil {
+			certFiles = append(certFiles, t.CertFile)
+		}
+	}
+	for _, f := range certFiles {
+		r.addCertificateSANs(fs, f)
+	}
+}
+
+// addCertificateSANs adds the DNS SANs of the certificates of a PEM file.
+// Unreadable files are skipped: the bundle is collected regardless.
+func (r *redactor) addCertificateSANs(fs afero.Fs, file string) {
+	if file == "" {
+		return
+	}
+	rest, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return
+	}
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		for _, name := range cert.DNSNames {
+			r.addHost(name)
+		}
+	}
+}
+
+// redact returns b with credentials and hostnames redacted.
+func (r *redactor) redact(b []byte) []byte {
+	b = credentialRe.ReplaceAll(b, []byte("${1}(REDACTED)"))
+	if len(r.placeholders) == 0 {
+		return b
+	}
+	if r.re == nil {
+		// Longest first, so that a hostname is replaced before a domain
+		// it is in.
+		names := make([]string, 0, len(r.placeholders))
+		for name := range r.placeholders {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+		sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+		r.re = regexp.MustCompile(`(?i)\b(` + strings.Join(names, "|") + `)\b`)
+	}
+	return r.re.ReplaceAllFunc(b, func(name []byte) []byte {
+		return []byte(r.placeholders[strings.ToLower(string(name))])
+	})
+}
+
+// mapping returns the hostname or domain behind every placeholder.
+func (r *redactor) mapping() map[string]string {
+	m := make(map[string]string, len(r.placeholders))
+	for name, placeholder := range r.placeholders {
+		m[placeholder] = name
+	}
+	return m
+}
+
+// redactingWriter redacts what is written through it line by line, which
+// lets command outputs be redacted as they are streamed to the bundle.
+type redactingWriter struct {
+	w   io.Writer
+	r   *redactor
+	buf []byte
+}
+
+// maxRedactedLine bounds the buffered bytes of a line without a newline.
+const maxRedactedLine = 64 << 10
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	rw.buf = append(rw.buf, p...)
+	end := bytes.LastIndexByte(rw.buf, '\n') + 1
+	if end == 0 && len(rw.buf) < maxRedactedLine {
+		return len(p), nil
+	}
+	if end == 0 {
+		end = len(rw.buf)
+	}
+	if _, err := rw.w.Write(rw.r.redact(rw.buf[:end])); err != nil {
+		return 0, err
+	}
+	rw.buf = append(rw.buf[:0], rw.buf[end:]...)
+	return len(p), nil
+}
+
+// Flush writes what is left of an unterminated last line.
+func (rw *redactingWriter) Flush() error {
+	if len(rw.buf) == 0 {
+		return nil
+	}
+	_, err := rw.w.Write(rw.r.redact(rw.buf))
+	rw.buf = rw.buf[:0]
+	return err
+}
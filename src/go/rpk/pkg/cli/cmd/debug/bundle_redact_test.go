@@ -0,0 +1,89 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+// +build linux
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor()
+	for _, h := range []string{
+		"broker-0.prod.acme.com:9092",
+		"BROKER-0.prod.acme.com", // duplicate, different case
+		"*.internal.acme.com",
+		"localhost:9092",
+		"redpanda-0",
+		"10.0.0.1:33145",
+	} {
+		r.addHost(h)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		exp  string
+	}{{
+		name: "hostnames are replaced by stable placeholders",
+		in:   "connected to broker-0.prod.acme.com:9092, then Broker-0.Prod.Acme.Com",
+		exp:  "connected to host-1:9092, then host-1",
+	}, {
+		name: "wildcard SANs redact their domain",
+		in:   "dialing rp-2.internal.acme.com",
+		exp:  "dialing rp-2.domain-1",
+	}, {
+		name: "unqualified names and IPs are kept",
+		in:   "localhost redpanda-0 10.0.0.1:33145",
+		exp:  "localhost redpanda-0 10.0.0.1:33145",
+	}, {
+		name: "credentials are redacted",
+		in:   "password: hunter2\n{\"token\":\"abc\"} --secret-key=xyz",
+		exp:  "password: (REDACTED)\n{\"token\":\"(REDACTED)\"} --secret-key=(REDACTED)",
+	}, {
+		name: "hostnames are only replaced whole",
+		in:   "notbroker-0.prod.acme.community",
+		exp:  "notbroker-0.prod.acme.community",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.exp, string(r.redact([]byte(tt.in))))
+		})
+	}
+
+	require.Equal(t, map[string]string{
+		"host-1":   "broker-0.prod.acme.com",
+		"domain-1": "internal.acme.com",
+	}, r.mapping())
+}
+
+func TestRedactingWriter(t *testing.T) {
+	r := newRedactor()
+	r.addHost("broker-0.acme.com")
+
+	var out bytes.Buffer
+	rw := &redactingWriter{w: &out, r: r}
+
+	// A hostname split across writes is still redacted.
+	for _, s := range []string{"first broker-0.ac", "me.com\nsecond bro", "ker-0.acme.com"} {
+		n, err := rw.Write([]byte(s))
+		require.NoError(t, err)
+		require.Equal(t, len(s), n)
+	}
+	require.Equal(t, "first host-1\n", out.String())
+
+	require.NoError(t, rw.Flush())
+	require.Equal(t, "first host-1\nsecond host-1", out.String())
+}
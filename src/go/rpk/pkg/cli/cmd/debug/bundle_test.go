@@ -13,8 +13,15 @@
 package debug
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -80,3 +87,94 @@ func TestWalkDirMissingRoot(t *testing.T) {
 	// was added to the files map, and that it has an associated error related to the filename.
 	require.Contains(t, files[root].Error, "/etc/its_highly_unlikely_that_a_dir_named_like_this_exists_anywhere")
 }
+
+func TestParseJournalTime(t *testing.T) {
+	now := time.Date(2022, 3, 4, 10, 11, 12, 0, time.Local)
+	for _, tt := range []struct {
+		in     string
+		exp    time.Time
+		expErr bool
+	}{
+		{in: "2022-03-04", exp: time.Date(2022, 3, 4, 0, 0, 0, 0, time.Local)},
+		{in: "2022-03-04 10:11", exp: time.Date(2022, 3, 4, 10, 11, 0, 0, time.Local)},
+		{in: "2022-03-04 10:11:12", exp: time.Date(2022, 3, 4, 10, 11, 12, 0, time.Local)},
+		{in: "2022-03-04T10:11:12Z", exp: time.Date(2022, 3, 4, 10, 11, 12, 0, time.UTC)},
+		{in: "now", exp: now},
+		{in: "today", exp: time.Date(2022, 3, 4, 0, 0, 0, 0, time.Local)},
+		{in: "yesterday", exp: time.Date(2022, 3, 3, 0, 0, 0, 0, time.Local)},
+		{in: "tomorrow", exp: time.Date(2022, 3, 5, 0, 0, 0, 0, time.Local)},
+		{in: "-1h", exp: now.Add(-time.Hour)},
+		{in: "+30min", exp: now.Add(30 * time.Minute)},
+		{in: "2 days ago", exp: now.Add(-48 * time.Hour)},
+		{in: "-1fortnight", expErr: true},
+		{in: "someday", expErr: true},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseJournalTime(tt.in, now)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.exp.Equal(got), "expected %v, got %v", tt.exp, got)
+		})
+	}
+}
+
+func TestFilterLogsUntil(t *testing.T) {
+	until := time.Date(2022, 3, 4, 10, 11, 12, 0, time.UTC)
+	logs := "2022-03-04T10:11:11.5Z first\n" +
+		"continuation\n" +
+		"2022-03-04T10:11:12Z second\n" +
+		"2022-03-04T10:11:12.000000001Z third\n" +
+		"2022-03-04T10:11:11Z fourth\n"
+	require.Equal(t, "first\ncontinuation\nsecond\n", string(filterLogsUntil([]byte(logs), until)))
+	require.Empty(t, filterLogsUntil(nil, until))
+}
+
+func TestSaveK8SPodLogs(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/ns/pods":
+			io.WriteString(w, `{"items":[{"metadata":{"name":"rp-0"},"spec":{"containers":[{"name":"redpanda"}]}}]}`)
+		case "/api/v1/namespaces/ns/pods/rp-0/log":
+			queries = append(queries, r.URL.RawQuery)
+			io.WriteString(w, "2022-03-04T10:00:00Z kept\n2022-03-04T12:00:00Z dropped\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	ps := &stepParams{w: zip.NewWriter(&buf), timeout: time.Second}
+	k8s := &k8sAPI{host: srv.URL, cl: srv.Client()}
+	err := saveK8SPodLogs(context.Background(), ps, k8s, "ns", "2022-03-04", "2022-03-04T11:00:00Z", 0)()
+	require.NoError(t, err)
+	require.NoError(t, ps.w.Close())
+
+	since, err := parseJournalTime("2022-03-04", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"container=redpanda&sinceTime=" + url.QueryEscape(since.Format(time.RFC3339)) + "&timestamps=true",
+	}, queries)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 1)
+	require.Equal(t, "k8s/logs/rp-0-redpanda.log", zr.File[0].Name)
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	got, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "kept\n", string(got))
+
+	// Dates that cannot be parsed only skip the time filter
+	queries = nil
+	ps = &stepParams{w: zip.NewWriter(&bytes.Buffer{}), timeout: time.Second}
+	err = saveK8SPodLogs(context.Background(), ps, k8s, "ns", "someday", "someday", 0)()
+	require.NoError(t, err)
+	require.Equal(t, []string{"container=redpanda"}, queries)
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/spf13/afero"
+)
+
+// uploadBundle uploads the bundle file, printing the progress to stderr.
+func uploadBundle(
+	ctx context.Context,
+	fs afero.Fs,
+	cl admin.AdminAPIClient,
+	filename string,
+	opts admin.DebugBundleUploadOptions,
+) error {
+	f, err := fs.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", filename, err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat %s: %w", filename, err)
+	}
+
+	opts.Progress = func(uploaded, size int64) {
+		pct := 100.0
+		if size > 0 {
+			pct = float64(uploaded) * 100 / float64(size)
+		}
+		fmt.Fprintf(os.Stderr, "\rUploaded %s of %s (%.0f%%)", units.BytesSize(float64(uploaded)), units.BytesSize(float64(size)), pct)
+	}
+	up, err := cl.UploadDebugBundle(ctx, filepath.Base(filename), f, stat.Size(), opts)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Debug bundle uploaded, upload ID: %s\n", up.ID)
+	return nil
+}
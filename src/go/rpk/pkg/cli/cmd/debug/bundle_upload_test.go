@@ -0,0 +1,45 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin/admintest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadBundle(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/tmp/1660000000-bundle.zip", []byte("zip contents"), 0o644))
+
+	fake := &admintest.Fake{
+		UploadDebugBundleFn: func(_ context.Context, name string, r io.ReaderAt, size int64, opts admin.DebugBundleUploadOptions) (admin.DebugBundleUpload, error) {
+			require.Equal(t, "1660000000-bundle.zip", name)
+			require.Equal(t, int64(12), size)
+			buf := make([]byte, size)
+			_, err := r.ReadAt(buf, 0)
+			require.NoError(t, err)
+			require.Equal(t, "zip contents", string(buf))
+			require.Equal(t, "https://support.example.com", opts.URL)
+			require.NotNil(t, opts.Progress)
+			return admin.DebugBundleUpload{ID: "up-1", Size: size, Offset: size}, nil
+		},
+	}
+	err := uploadBundle(context.Background(), fs, fake, "/tmp/1660000000-bundle.zip", admin.DebugBundleUploadOptions{
+		URL: "https://support.example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"UploadDebugBundle"}, fake.Calls())
+}
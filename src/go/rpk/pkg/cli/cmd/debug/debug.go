@@ -22,7 +22,11 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 
 	cmd.AddCommand(
 		newBundleCommand(fs),
+		newGenLoadCommand(fs),
 		NewInfoCommand(),
+		newProbeCommand(fs),
+		newProfileDiffCommand(fs),
+		newRaftCommand(fs),
 	)
 
 	return cmd
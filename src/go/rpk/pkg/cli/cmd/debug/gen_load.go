@@ -0,0 +1,404 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"golang.org/x/time/rate"
+)
+
+// hotKey is the key of the records of --hot-key-ratio, which all land on the
+// same partition of their topic.
+const hotKey = "hot"
+
+func newGenLoadCommand(fs afero.Fs) *cobra.Command {
+	var (
+		topicPrefix string
+		topics      int
+		partitions  int32
+		replicas    int16
+
+		recordRate       float64
+		duration         time.Duration
+		keys             int
+		hotKeyRatio      float64
+		recordSize       string
+		largeRecordSize  string
+		largeRecordRatio float64
+		burstRecords     int
+		burstInterval    time.Duration
+
+		deleteTopics bool
+	)
+	cmd := &cobra.Command{
+		Use:     "gen-load",
+		Aliases: []string{"simulate-partition-pressure"},
+		Short:   "Produce skewed test traffic to reproduce cluster behavior",
+		Long: `Produce skewed test traffic to reproduce cluster behavior.
+
+This command creates test topics, or reuses them if they exist, and produces
+generated records to them at the given rate, for the given duration or until
+interrupted. The traffic can be skewed to put pressure on some partitions or
+brokers, which is useful to reproduce and demonstrate the behavior of a
+cluster in a test environment:
+
+  * hot keys: --hot-key-ratio of the records have the same key, and so land
+    on the same partition of their topic;
+  * large records: --large-record-ratio of the records are of
+    --large-record-size rather than --record-size; records of more than
+    1MB are rejected unless the limits of the client and topics are raised;
+  * bursty producers: every --burst-interval, --burst-records records are
+    produced at once, on top of --rate.
+
+The report ends with the records produced to each partition, which shows the
+skew of the traffic.
+
+This command is meant for test clusters; do not run it against a production
+cluster.
+
+    rpk debug gen-load --topics 3 --partitions 12 --hot-key-ratio 0.5
+    rpk debug gen-load --rate 200 --large-record-size 1MiB --large-record-ratio 0.01
+    rpk debug gen-load --burst-records 50000 --burst-interval 10s --delete
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			shape, err := newLoadShape(keys, hotKeyRatio, recordSize, largeRecordSize, largeRecordRatio)
+			out.MaybeDieErr(err)
+			switch {
+			case topics <= 0:
+				out.DieUsage("invalid --topics %d, must be positive", topics)
+			case partitions <= 0:
+				out.DieUsage("invalid --partitions %d, must be positive", partitions)
+			case recordRate <= 0:
+				out.DieUsage("invalid --rate %v, must be positive", recordRate)
+			case duration < 0:
+				out.DieUsage("invalid negative --duration")
+			case burstRecords < 0 || burstRecords > 0 && burstInterval <= 0:
+				out.DieUsage("--burst-records must not be negative, and requires a positive --burst-interval")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg, kgo.ProducerBatchCompression(kgo.NoCompression()))
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+			adm := kadm.NewClient(cl)
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+
+			names := make([]string, topics)
+			for i := range names {
+				names[i] = fmt.Sprintf("%s-%d", topicPrefix, i)
+			}
+			err = createLoadTopics(ctx, adm, names, partitions, replicas)
+			out.MaybeDieErr(err)
+			if deleteTopics {
+				defer func() {
+					_, err := adm.DeleteTopics(context.Background(), names...)
+					out.MaybeDie(err, "unable to delete the test topics: %v", err)
+					fmt.Printf("Deleted %d test topics.\n", len(names))
+				}()
+			}
+
+			runCtx := ctx
+			if duration > 0 {
+				var cancelRun context.CancelFunc
+				runCtx, cancelRun = context.WithTimeout(ctx, duration)
+				defer cancelRun()
+			}
+			fmt.Printf("Producing %v records per second to %d topics of %d partitions...\n", recordRate, topics, partitions)
+			gen := newLoadGenerator(shape, names, time.Now().UnixNano())
+			start := time.Now()
+			stats := produceLoad(runCtx, cl, gen, recordRate, burstRecords, burstInterval)
+			printLoadStats(stats, time.Since(start))
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&topicPrefix, "topic-prefix", "rpk-gen-load", "Prefix of the test topics, which are named <prefix>-<n>")
+	f.IntVar(&topics, "topics", 1, "Number of test topics")
+	f.Int32VarP(&partitions, "partitions", "p", 12, "Number of partitions of the test topics that are created")
+	f.Int16VarP(&replicas, "replicas", "r", -1, "Replication factor of the test topics that are created; the cluster default if -1")
+	f.Float64Var(&recordRate, "rate", 1000, "Records produced per second, across all the test topics")
+	f.DurationVar(&duration, "duration", time.Minute, "How long to produce for; until interrupted if 0")
+	f.IntVar(&keys, "keys", 1000, "Number of distinct keys of the records that do not have the hot key; unkeyed records if 0")
+	f.Float64Var(&hotKeyRatio, "hot-key-ratio", 0, "Ratio of the records, from 0 to 1, that have the same hot key")
+	f.StringVar(&recordSize, "record-size", "1KiB", "Size of the record values")
+	f.StringVar(&largeRecordSize, "large-record-size", "512KiB", "Size of the values of large records")
+	f.Float64Var(&largeRecordRatio, "large-record-ratio", 0, "Ratio of the records, from 0 to 1, that are large records")
+	f.IntVar(&burstRecords, "burst-records", 0, "Records produced at once every --burst-interval, on top of --rate")
+	f.DurationVar(&burstInterval, "burst-interval", 0, "Interval between two bursts of --burst-records")
+	f.BoolVar(&deleteTopics, "delete", false, "Delete the test topics once done")
+
+	return cmd
+}
+
+// loadShape is the shape of the generated records.
+type loadShape struct {
+	keys             int
+	hotKeyRatio      float64
+	recordSize       int
+	largeRecordSize  int
+	largeRecordRatio float64
+}
+
+func newLoadShape(
+	keys int, hotKeyRatio float64, recordSize, largeRecordSize string, largeRecordRatio float64,
+) (loadShape, error) {
+	s := loadShape{keys: keys, hotKeyRatio: hotKeyRatio, largeRecordRatio: largeRecordRatio}
+	if keys < 0 {
+		return s, fmt.Errorf("invalid --keys %d, must not be negative", keys)
+	}
+	if hotKeyRatio < 0 || hotKeyRatio > 1 {
+		return s, fmt.Errorf("invalid --hot-key-ratio %v, must be between 0 and 1", hotKeyRatio)
+	}
+	if largeRecordRatio < 0 || largeRecordRatio > 1 {
+		return s, fmt.Errorf("invalid --large-record-ratio %v, must be between 0 and 1", largeRecordRatio)
+	}
+	for _, size := range []struct {
+		flag, value string
+		into        *int
+	}{
+		{"record-size", recordSize, &s.recordSize},
+		{"large-record-size", largeRecordSize, &s.largeRecordSize},
+	} {
+		n, err := units.RAMInBytes(size.value)
+		if err != nil || n < 0 || n > 1<<30 {
+			return s, fmt.Errorf("invalid --%s %q, must be a size of at most 1GiB, e.g. 1KiB", size.flag, size.value)
+		}
+		*size.into = int(n)
+	}
+	return s, nil
+}
+
+// loadGenerator generates the records of a loadShape, spread evenly across
+// topics.
+type loadGenerator struct {
+	shape  loadShape
+	topics []string
+	rng    *rand.Rand
+	// value is shared by the values of all the records, which are never
+	// modified. It is random so that it does not compress.
+	value []byte
+	next  int
+}
+
+func newLoadGenerator(shape loadShape, topics []string, seed int64) *loadGenerator {
+	g := &loadGenerator{shape: shape, topics: topics, rng: rand.New(rand.NewSource(seed))}
+	size := shape.recordSize
+	if shape.largeRecordRatio > 0 && shape.largeRecordSize > size {
+		size = shape.largeRecordSize
+	}
+	g.value = make([]byte, size)
+	g.rng.Read(g.value)
+	return g
+}
+
+func (g *loadGenerator) record() *kgo.Record {
+	r := &kgo.Record{Topic: g.topics[g.next]}
+	g.next = (g.next + 1) % len(g.topics)
+
+	switch {
+	case g.shape.hotKeyRatio > 0 && g.rng.Float64() < g.shape.hotKeyRatio:
+		r.Key = []byte(hotKey)
+	case g.shape.keys > 0:
+		r.Key = []byte("key-" + strconv.Itoa(g.rng.Intn(g.shape.keys)))
+	}
+	size := g.shape.recordSize
+	if g.shape.largeRecordRatio > 0 && g.rng.Float64() < g.shape.largeRecordRatio {
+		size = g.shape.largeRecordSize
+	}
+	r.Value = g.value[:size]
+	return r
+}
+
+// createLoadTopics creates the test topics, reusing those that exist.
+func createLoadTopics(
+	ctx context.Context, adm *kadm.Client, topics []string, partitions int32, replicas int16,
+) error {
+	created, err := adm.CreateTopics(ctx, partitions, replicas, nil, topics...)
+	if err != nil {
+		return fmt.Errorf("unable to create the test topics: %w", err)
+	}
+	var reused int
+	for topic, t := range created {
+		switch {
+		case errors.Is(t.Err, kerr.TopicAlreadyExists):
+			reused++
+		case t.Err != nil:
+			return fmt.Errorf("unable to create the test topic %q: %w", topic, t.Err)
+		}
+	}
+	if reused > 0 {
+		fmt.Printf("Reusing %d existing test topics.\n", reused)
+	}
+	return nil
+}
+
+// partitionLoad is what was produced to a partition.
+type partitionLoad struct {
+	topic     string
+	partition int32
+	records   int64
+	bytes     int64
+}
+
+// loadStats accumulates the outcome of the produced records.
+type loadStats struct {
+	mu         sync.Mutex
+	partitions map[string]map[int32]*partitionLoad
+	errors     map[string]int64
+}
+
+func (s *loadStats) add(r *kgo.Record, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if s.errors == nil {
+			s.errors = make(map[string]int64)
+		}
+		s.errors[err.Error()]++
+		return
+	}
+	if s.partitions == nil {
+		s.partitions = make(map[string]map[int32]*partitionLoad)
+	}
+	ps, ok := s.partitions[r.Topic]
+	if !ok {
+		ps = make(map[int32]*partitionLoad)
+		s.partitions[r.Topic] = ps
+	}
+	p, ok := ps[r.Partition]
+	if !ok {
+		p = &partitionLoad{topic: r.Topic, partition: r.Partition}
+		ps[r.Partition] = p
+	}
+	p.records++
+	p.bytes += int64(len(r.Key) + len(r.Value))
+}
+
+// sorted returns the load of every partition, sorted by topic and partition.
+func (s *loadStats) sorted() []partitionLoad {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var loads []partitionLoad
+	for _, ps := range s.partitions {
+		for _, p := range ps {
+			loads = append(loads, *p)
+		}
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].topic != loads[j].topic {
+			return loads[i].topic < loads[j].topic
+		}
+		return loads[i].partition < loads[j].partition
+	})
+	return loads
+}
+
+// produceLoad produces the generated records at the rate, plus the bursts,
+// until the context is done. Produce errors are counted rather than fatal:
+// a cluster under pressure is expected to reject some records.
+func produceLoad(
+	ctx context.Context,
+	cl *kgo.Client,
+	gen *loadGenerator,
+	recordRate float64,
+	burstRecords int,
+	burstInterval time.Duration,
+) *loadStats {
+	stats := new(loadStats)
+	produce := func(r *kgo.Record) {
+		// The context of the records is not ctx, so that the records
+		// that are buffered when ctx is done are still produced.
+		cl.Produce(context.Background(), r, stats.add)
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(recordRate), 1)
+	var bursts <-chan time.Time
+	if burstRecords > 0 {
+		ticker := time.NewTicker(burstInterval)
+		defer ticker.Stop()
+		bursts = ticker.C
+	}
+	for {
+		select {
+		case <-bursts:
+			for i := 0; i < burstRecords && ctx.Err() == nil; i++ {
+				produce(gen.record())
+			}
+			continue
+		default:
+		}
+		if limiter.Wait(ctx) != nil {
+			break
+		}
+		produce(gen.record())
+	}
+	fmt.Println("Waiting for the buffered records to be produced...")
+	cl.Flush(context.Background())
+	return stats
+}
+
+func printLoadStats(stats *loadStats, elapsed time.Duration) {
+	loads := stats.sorted()
+	var records, bytes int64
+	for _, l := range loads {
+		records += l.records
+		bytes += l.bytes
+	}
+
+	out.Section("produced")
+	seconds := elapsed.Seconds()
+	fmt.Printf("%d records, %s in %v (%.0f records/s, %s/s)\n",
+		records, units.BytesSize(float64(bytes)), elapsed.Round(time.Millisecond),
+		float64(records)/seconds, units.BytesSize(float64(bytes)/seconds))
+
+	if len(stats.errors) > 0 {
+		out.Section("errors")
+		tw := out.NewTable("ERROR", "RECORDS")
+		for err, n := range stats.errors {
+			tw.Print(err, n)
+		}
+		tw.Flush()
+	}
+
+	if len(loads) > 0 {
+		out.Section("partitions")
+		tw := out.NewTable("TOPIC", "PARTITION", "RECORDS", "BYTES", "SHARE")
+		for _, l := range loads {
+			tw.Print(l.topic, l.partition, l.records, units.BytesSize(float64(l.bytes)),
+				fmt.Sprintf("%.1f%%", 100*float64(l.records)/float64(records)))
+		}
+		tw.Flush()
+	}
+}
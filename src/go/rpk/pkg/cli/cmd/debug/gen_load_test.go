@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLoadShape(t *testing.T) {
+	s, err := newLoadShape(10, 0.5, "1KiB", "1MiB", 0.1)
+	require.NoError(t, err)
+	require.Equal(t, loadShape{keys: 10, hotKeyRatio: 0.5, recordSize: 1024, largeRecordSize: 1 << 20, largeRecordRatio: 0.1}, s)
+
+	for _, bad := range []func() (loadShape, error){
+		func() (loadShape, error) { return newLoadShape(-1, 0, "1KiB", "1MiB", 0) },
+		func() (loadShape, error) { return newLoadShape(10, 1.5, "1KiB", "1MiB", 0) },
+		func() (loadShape, error) { return newLoadShape(10, 0, "1KiB", "1MiB", -0.1) },
+		func() (loadShape, error) { return newLoadShape(10, 0, "big", "1MiB", 0) },
+		func() (loadShape, error) { return newLoadShape(10, 0, "1KiB", "2GiB", 0) },
+	} {
+		_, err := bad()
+		require.Error(t, err)
+	}
+}
+
+func TestLoadGenerator(t *testing.T) {
+	topics := []string{"a", "b"}
+	shape := loadShape{keys: 100, hotKeyRatio: 0.5, recordSize: 10, largeRecordSize: 1000, largeRecordRatio: 0.1}
+	g := newLoadGenerator(shape, topics, 1)
+
+	const n = 10000
+	var hot, large int
+	perTopic := make(map[string]int)
+	for i := 0; i < n; i++ {
+		r := g.record()
+		perTopic[r.Topic]++
+		require.NotEmpty(t, r.Key)
+		if string(r.Key) == hotKey {
+			hot++
+		}
+		switch len(r.Value) {
+		case 1000:
+			large++
+		case 10:
+		default:
+			t.Fatalf("unexpected value size %d", len(r.Value))
+		}
+	}
+	require.Equal(t, map[string]int{"a": n / 2, "b": n / 2}, perTopic)
+	require.InDelta(t, n/2, hot, n/20)
+	require.InDelta(t, n/10, large, n/50)
+
+	// Unkeyed records, without large records.
+	g = newLoadGenerator(loadShape{recordSize: 10, largeRecordSize: 1000}, topics, 1)
+	require.Len(t, g.value, 10)
+	r := g.record()
+	require.Nil(t, r.Key)
+	require.Len(t, r.Value, 10)
+}
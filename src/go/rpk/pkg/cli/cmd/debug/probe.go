@@ -0,0 +1,406 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newProbeCommand(fs afero.Fs) *cobra.Command {
+	var (
+		configFile string
+
+		brokers   []string
+		user      string
+		password  string
+		mechanism string
+		enableTLS bool
+		certFile  string
+		keyFile   string
+		caFile    string
+
+		adminHosts     []string
+		adminEnableTLS bool
+		adminCertFile  string
+		adminKeyFile   string
+		adminCAFile    string
+
+		duration time.Duration
+		interval time.Duration
+		timeout  time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "probe",
+		Short: "Measure the latency of every broker to find slow ones",
+		Long: `Measure the latency of every broker to find slow ones.
+
+This command repeatedly sends lightweight requests to the Kafka API (an
+ApiVersions request) and the admin API (a node config request) of every
+broker, for the given duration or until interrupted. It then prints, per
+broker and API, the number of requests and errors, the longest streak of
+consecutive errors, and latency percentiles of the successful requests.
+
+The report ends with the outlier broker, if any: the broker with the highest
+error rate if some requests failed, otherwise the broker whose p99 latency is
+at least twice the median p99 latency of the other brokers.
+
+The requests are cheap, but they are sent to every broker at every interval;
+avoid very short intervals on busy clusters.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			if interval <= 0 || duration < interval {
+				out.DieUsage("--interval must be positive and no longer than --duration")
+			}
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+
+			targets, err := probeTargets(ctx, fs, cfg, cl)
+			out.MaybeDieErr(err)
+
+			fmt.Printf("Probing %d endpoints every %v for %v...\n", len(targets), interval, duration)
+			results := summarize(runProbes(ctx, targets, duration, interval, timeout))
+
+			tw := out.NewTable("BROKER", "API", "REQUESTS", "ERRORS", "MAX-ERROR-STREAK", "P50", "P90", "P99", "MAX")
+			for _, r := range results {
+				tw.Print(r.broker, r.api, r.requests, r.errors, r.maxStreak, r.p50, r.p90, r.p99, r.max)
+			}
+			tw.Flush()
+			fmt.Println()
+
+			if broker, reason := findOutlier(results); broker != "" {
+				fmt.Printf("Outlier: broker %s, %s.\n", broker, reason)
+			} else {
+				fmt.Println("No outlier broker found.")
+			}
+		},
+	}
+
+	f := cmd.Flags()
+	f.DurationVar(&duration, "duration", 30*time.Second, "How long to probe the brokers for")
+	f.DurationVar(&interval, "interval", time.Second, "Interval between two requests to the same endpoint")
+	f.DurationVar(&timeout, "timeout", 5*time.Second, "Timeout of each request, after which it counts as an error")
+	f.StringSliceVar(&adminHosts, config.FlagAdminHosts2, nil, "Comma-separated list of admin API addresses (<IP>:<port>), one for each broker")
+
+	common.AddKafkaFlags(
+		cmd,
+		&configFile,
+		&user,
+		&password,
+		&mechanism,
+		&enableTLS,
+		&certFile,
+		&keyFile,
+		&caFile,
+		&brokers,
+	)
+	common.AddAdminAPITLSFlags(cmd,
+		&adminEnableTLS,
+		&adminCertFile,
+		&adminKeyFile,
+		&adminCAFile,
+	)
+
+	return cmd
+}
+
+// probeTarget is an endpoint of a broker, and the request to time against it.
+type probeTarget struct {
+	broker string // the node ID, or the address if it is unknown
+	api    string
+	probe  func(ctx context.Context) error
+}
+
+// probeTargets returns the Kafka API of every broker in the cluster metadata,
+// and the admin API of every configured admin address.
+func probeTargets(
+	ctx context.Context, fs afero.Fs, cfg *config.Config, cl *kgo.Client,
+) ([]probeTarget, error) {
+	brokers, err := kadm.NewClient(cl).ListBrokers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list brokers: %v", err)
+	}
+	var targets []probeTarget
+	for _, b := range brokers {
+		broker := cl.Broker(int(b.NodeID))
+		targets = append(targets, probeTarget{
+			broker: strconv.Itoa(int(b.NodeID)),
+			api:    "kafka",
+			probe: func(ctx context.Context) error {
+				_, err := broker.Request(ctx, kmsg.NewPtrApiVersionsRequest())
+				return err
+			},
+		})
+	}
+
+	for _, addr := range cfg.Rpk.AdminAPI.Addresses {
+		hostCl, err := admin.NewHostClient(fs, cfg, addr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize admin client for %s: %v", addr, err)
+		}
+		broker := addr
+		if nc, err := hostCl.GetNodeConfig(ctx); err == nil {
+			broker = strconv.Itoa(nc.NodeID)
+		}
+		targets = append(targets, probeTarget{
+			broker: broker,
+			api:    "admin",
+			probe: func(ctx context.Context) error {
+				_, err := hostCl.GetNodeConfig(ctx)
+				return err
+			},
+		})
+	}
+	return targets, nil
+}
+
+type probeKey struct {
+	broker string
+	api    string
+}
+
+// probeStats are the outcomes of the requests to one endpoint.
+type probeStats struct {
+	latencies []time.Duration // of the successful requests
+	errors    int
+	streak    int
+	maxStreak int
+}
+
+func (s *probeStats) record(latency time.Duration, err error) {
+	if err != nil {
+		s.errors++
+		s.streak++
+		if s.streak > s.maxStreak {
+			s.maxStreak = s.streak
+		}
+		return
+	}
+	s.streak = 0
+	s.latencies = append(s.latencies, latency)
+}
+
+// runProbes probes every target at every interval until the duration
+// elapses or the context is canceled. A target is not probed again while its
+// previous request is in flight, so that a slow broker is not piled on.
+func runProbes(
+	ctx context.Context,
+	targets []probeTarget,
+	duration, interval, timeout time.Duration,
+) map[probeKey]*probeStats {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		stats = make(map[probeKey]*probeStats, len(targets))
+	)
+	for _, t := range targets {
+		stats[probeKey{t.broker, t.api}] = new(probeStats)
+	}
+	for _, t := range targets {
+		t := t
+		s := stats[probeKey{t.broker, t.api}]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				reqCtx, reqCancel := context.WithTimeout(context.Background(), timeout)
+				start := time.Now()
+				err := t.probe(reqCtx)
+				latency := time.Since(start)
+				reqCancel()
+
+				// Requests cut short by the end of the probe are not
+				// counted.
+				if ctx.Err() != nil {
+					return
+				}
+				mu.Lock()
+				s.record(latency, err)
+				mu.Unlock()
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return stats
+}
+
+type probeResult struct {
+	broker    string
+	api       string
+	requests  int
+	errors    int
+	maxStreak int
+
+	p50, p90, p99, max time.Duration
+}
+
+// summarize returns the results of each endpoint, sorted by broker and API.
+func summarize(stats map[probeKey]*probeStats) []probeResult {
+	results := make([]probeResult, 0, len(stats))
+	for k, s := range stats {
+		sorted := append([]time.Duration(nil), s.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		r := probeResult{
+			broker:    k.broker,
+			api:       k.api,
+			requests:  len(s.latencies) + s.errors,
+			errors:    s.errors,
+			maxStreak: s.maxStreak,
+			p50:       percentile(sorted, 50),
+			p90:       percentile(sorted, 90),
+			p99:       percentile(sorted, 99),
+		}
+		if len(sorted) > 0 {
+			r.max = sorted[len(sorted)-1]
+		}
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		l, r := results[i], results[j]
+		if l.broker != r.broker {
+			return brokerLess(l.broker, r.broker)
+		}
+		return l.api < r.api
+	})
+	return results
+}
+
+// brokerLess sorts node IDs numerically, before addresses.
+func brokerLess(l, r string) bool {
+	li, lerr := strconv.Atoi(l)
+	ri, rerr := strconv.Atoi(r)
+	switch {
+	case lerr == nil && rerr == nil:
+		return li < ri
+	case lerr == nil || rerr == nil:
+		return lerr == nil
+	default:
+		return l < r
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies, or 0
+// if there are none.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// findOutlier returns the broker that stands out from the others, and why,
+// or an empty broker if none does. Errors trump latency: if any request
+// failed, the outlier is the broker with the highest error rate. Otherwise,
+// it is the broker with the highest p99 latency, if it is at least twice the
+// median p99 latency of the other brokers.
+func findOutlier(results []probeResult) (string, string) {
+	type brokerStats struct {
+		requests, errors int
+		p99              time.Duration
+	}
+	var order []string
+	brokers := make(map[string]*brokerStats)
+	for _, r := range results {
+		b, ok := brokers[r.broker]
+		if !ok {
+			b = new(brokerStats)
+			brokers[r.broker] = b
+			order = append(order, r.broker)
+		}
+		b.requests += r.requests
+		b.errors += r.errors
+		if r.p99 > b.p99 {
+			b.p99 = r.p99
+		}
+	}
+
+	var worst string
+	var worstRate float64
+	for _, name := range order {
+		b := brokers[name]
+		if b.errors == 0 {
+			continue
+		}
+		if rate := float64(b.errors) / float64(b.requests); worst == "" || rate > worstRate {
+			worst, worstRate = name, rate
+		}
+	}
+	if worst != "" {
+		b := brokers[worst]
+		return worst, fmt.Sprintf("%d of %d requests failed (%.0f%%)", b.errors, b.requests, worstRate*100)
+	}
+
+	if len(order) < 2 {
+		return "", ""
+	}
+	for _, name := range order {
+		if worst == "" || brokers[name].p99 > brokers[worst].p99 {
+			worst = name
+		}
+	}
+	var others []time.Duration
+	for _, name := range order {
+		if name != worst {
+			others = append(others, brokers[name].p99)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	median := others[len(others)/2]
+	if len(others)%2 == 0 {
+		median = (others[len(others)/2-1] + others[len(others)/2]) / 2
+	}
+	p99 := brokers[worst].p99
+	if median <= 0 || p99 < 2*median {
+		return "", ""
+	}
+	return worst, fmt.Sprintf("its p99 latency of %v is %.1fx the median p99 latency of the other brokers (%v)",
+		p99, float64(p99)/float64(median), median)
+}
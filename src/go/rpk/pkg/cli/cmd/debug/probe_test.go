@@ -0,0 +1,118 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	require.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	require.Equal(t, time.Millisecond, percentile(sorted[:1], 99))
+	require.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+func TestProbeStatsRecord(t *testing.T) {
+	var s probeStats
+	fail := errors.New("fail")
+	s.record(time.Millisecond, nil)
+	s.record(0, fail)
+	s.record(0, fail)
+	s.record(2*time.Millisecond, nil)
+	s.record(0, fail)
+	require.Equal(t, 3, s.errors)
+	require.Equal(t, 2, s.maxStreak)
+	require.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, s.latencies)
+}
+
+func TestFindOutlier(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		results []probeResult
+		exp     string
+	}{
+		{
+			name: "similar brokers",
+			results: []probeResult{
+				{broker: "0", api: "kafka", requests: 10, p99: 10 * ms},
+				{broker: "1", api: "kafka", requests: 10, p99: 12 * ms},
+				{broker: "2", api: "kafka", requests: 10, p99: 15 * ms},
+			},
+		},
+		{
+			name: "slow broker",
+			results: []probeResult{
+				{broker: "0", api: "admin", requests: 10, p99: 2 * ms},
+				{broker: "0", api: "kafka", requests: 10, p99: 10 * ms},
+				{broker: "1", api: "admin", requests: 10, p99: 50 * ms},
+				{broker: "1", api: "kafka", requests: 10, p99: 11 * ms},
+				{broker: "2", api: "kafka", requests: 10, p99: 12 * ms},
+			},
+			exp: "1",
+		},
+		{
+			name: "errors trump latency",
+			results: []probeResult{
+				{broker: "0", api: "kafka", requests: 10, errors: 1, p99: 10 * ms},
+				{broker: "1", api: "kafka", requests: 10, p99: 100 * ms},
+				{broker: "2", api: "kafka", requests: 10, errors: 5, p99: 10 * ms},
+			},
+			exp: "2",
+		},
+		{
+			name: "single broker",
+			results: []probeResult{
+				{broker: "0", api: "kafka", requests: 10, p99: 10 * ms},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			broker, reason := findOutlier(test.results)
+			require.Equal(t, test.exp, broker)
+			require.Equal(t, test.exp == "", reason == "")
+		})
+	}
+}
+
+func TestRunProbes(t *testing.T) {
+	calls := 0
+	targets := []probeTarget{{
+		broker: "0",
+		api:    "kafka",
+		probe: func(context.Context) error {
+			calls++
+			if calls%2 == 0 {
+				return errors.New("fail")
+			}
+			return nil
+		},
+	}}
+	stats := runProbes(context.Background(), targets, 50*time.Millisecond, time.Millisecond, time.Second)
+	s := stats[probeKey{"0", "kafka"}]
+	require.NotNil(t, s)
+	require.True(t, s.errors > 0)
+	require.True(t, len(s.latencies) > 0)
+
+	results := summarize(stats)
+	require.Len(t, results, 1)
+	require.Equal(t, len(s.latencies)+s.errors, results[0].requests)
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/pprof"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newProfileDiffCommand(fs afero.Fs) *cobra.Command {
+	var (
+		sampleIndex string
+		top         int
+	)
+	cmd := &cobra.Command{
+		Use:   "profile-diff [BEFORE] [AFTER]",
+		Short: "Show the sites that grew the most between two profiles",
+		Long: `Show the sites that grew the most between two profiles.
+
+This command compares two profiles in the pprof format, e.g. two memory
+profiles of a broker captured some time apart, and prints the sites whose
+value grew the most from the first profile to the second. The samples are
+attributed to their leaf function, i.e. to the allocation site of memory
+profiles or to the running function of CPU profiles.
+
+In a leak investigation, the sites that keep growing between consecutive
+memory profiles are where the leaked memory is allocated.
+
+The sample type to compare is selected with --sample-index, as in pprof; it
+defaults to the default sample type of the profiles, e.g. inuse_space for
+memory profiles.
+
+    rpk debug profile-diff heap-1.prof heap-2.prof
+    rpk debug profile-diff --sample-index alloc_space --top 20 heap-1.prof heap-2.prof
+`,
+		Args: cobra.ExactArgs(2),
+		Run: func(_ *cobra.Command, args []string) {
+			before, err := readProfile(fs, args[0])
+			out.MaybeDieErr(err)
+			after, err := readProfile(fs, args[1])
+			out.MaybeDieErr(err)
+
+			diff, err := pprof.Compare(before, after, sampleIndex)
+			out.MaybeDie(err, "unable to compare the profiles: %v", err)
+			printProfileDiff(diff, top)
+		},
+	}
+	cmd.Flags().StringVar(&sampleIndex, "sample-index", "", "Sample type to compare, e.g. inuse_space or alloc_objects; the default sample type of the profiles if empty")
+	cmd.Flags().IntVar(&top, "top", 10, "Number of growing sites to print; all of them if 0")
+	return cmd
+}
+
+func readProfile(fs afero.Fs, path string) (*pprof.Profile, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open profile %q: %w", path, err)
+	}
+	defer f.Close()
+	p, err := pprof.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse profile %q: %w", path, err)
+	}
+	return p, nil
+}
+
+func printProfileDiff(diff *pprof.Diff, top int) {
+	unit := diff.SampleType.Unit
+	out.Section(fmt.Sprintf("%s (%s)", diff.SampleType.Type, unit))
+	fmt.Printf("Total: %s -> %s (%s)\n\n",
+		formatSampleValue(diff.TotalBefore, unit, false),
+		formatSampleValue(diff.TotalAfter, unit, false),
+		formatSampleValue(diff.TotalAfter-diff.TotalBefore, unit, true),
+	)
+
+	growing := diff.Growing(top)
+	if len(growing) == 0 {
+		fmt.Println("No site grew.")
+		return
+	}
+	tw := out.NewTable("SITE", "BEFORE", "AFTER", "DELTA")
+	defer tw.Flush()
+	for _, s := range growing {
+		tw.Print(
+			s.Site,
+			formatSampleValue(s.Before, unit, false),
+			formatSampleValue(s.After, unit, false),
+			formatSampleValue(s.Delta, unit, true),
+		)
+	}
+}
+
+// formatSampleValue formats bytes and nanoseconds for humans, and other units
+// as plain numbers.
+func formatSampleValue(v int64, unit string, signed bool) string {
+	var sign string
+	if v < 0 {
+		sign, v = "-", -v
+	} else if signed {
+		sign = "+"
+	}
+	switch unit {
+	case "bytes":
+		return sign + units.BytesSize(float64(v))
+	case "nanoseconds":
+		return sign + time.Duration(v).String()
+	default:
+		return sign + strconv.FormatInt(v, 10)
+	}
+}
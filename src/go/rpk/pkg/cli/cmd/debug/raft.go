@@ -0,0 +1,152 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newRaftCommand(fs afero.Fs) *cobra.Command {
+	var (
+		adminURL       string
+		adminEnableTLS bool
+		adminCertFile  string
+		adminKeyFile   string
+		adminCAFile    string
+	)
+	cmd := &cobra.Command{
+		Use:   "raft",
+		Args:  cobra.ExactArgs(0),
+		Short: "Inspect the raft groups of the cluster",
+	}
+	common.AddAdminAPITLSFlags(cmd,
+		&adminEnableTLS,
+		&adminCertFile,
+		&adminKeyFile,
+		&adminCAFile,
+	)
+	cmd.PersistentFlags().StringVar(
+		&adminURL,
+		config.FlagAdminHosts2,
+		"",
+		"Comma-separated list of admin API addresses (<IP>:<port>)")
+
+	cmd.AddCommand(newRaftDescribeCommand(fs))
+	return cmd
+}
+
+func newRaftDescribeCommand(fs afero.Fs) *cobra.Command {
+	var group int
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Describe the replicas of a raft group",
+		Long: `Describe the replicas of a raft group.
+
+This command queries every broker for its view of the raft group, and the
+leader of the group for the replication state of its followers. It helps
+diagnosing stuck replication, which Kafka level metrics do not show:
+
+  - a replica whose last applied offset lags behind the commit index is
+    slow to apply committed batches,
+  - a follower whose match index lags behind the leader's dirty offset is
+    slow to replicate, or cut off from the leader if it has not answered a
+    heartbeat for a while,
+  - replicas that disagree on the term or on the leader are in the middle of
+    an election, or partitioned from each other.
+
+Offsets are raft offsets, which include non-data batches.
+
+    rpk debug raft describe --group 12
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			states, err := cl.RaftGroupStates(cmd.Context(), group)
+			if err != nil {
+				if len(states) == 0 {
+					out.Die("unable to describe raft group %d: %v", group, err)
+				}
+				fmt.Printf("Some brokers could not be queried: %v\n\n", err)
+			}
+			if len(states) == 0 {
+				out.Die("no broker hosts a replica of raft group %d", group)
+			}
+
+			leader := printRaftReplicas(states)
+			if leader < 0 {
+				fmt.Println("\nNo replica is the leader of the group, the followers are unknown.")
+				return
+			}
+			followers, err := cl.RaftGroupFollowers(cmd.Context(), leader, group)
+			out.MaybeDie(err, "unable to get the followers from leader %d: %v", leader, err)
+			fmt.Println()
+			printRaftFollowers(states[leader], followers)
+		},
+	}
+	cmd.Flags().IntVar(&group, "group", -1, "ID of the raft group to describe")
+	cobra.MarkFlagRequired(cmd.Flags(), "group")
+	return cmd
+}
+
+// printRaftReplicas prints the state of every replica of the group, and
+// returns the node ID of the replica that is the leader, or -1.
+func printRaftReplicas(states map[int]admin.RaftGroupState) int {
+	nodes := make([]int, 0, len(states))
+	for node := range states {
+		nodes = append(nodes, node)
+	}
+	sort.Ints(nodes)
+
+	leader := -1
+	out.Section("REPLICAS")
+	tw := out.NewTable("NODE", "TERM", "LEADER", "COMMIT-INDEX", "LAST-APPLIED", "APPLY-LAG", "DIRTY-OFFSET", "FLUSHED-OFFSET")
+	defer tw.Flush()
+	for _, node := range nodes {
+		s := states[node]
+		if s.IsLeader {
+			leader = node
+		}
+		tw.Print(node, s.Term, s.LeaderID, s.CommitIndex, s.LastAppliedOffset, s.CommitIndex-s.LastAppliedOffset, s.DirtyOffset, s.FlushedOffset)
+	}
+	return leader
+}
+
+func printRaftFollowers(leader admin.RaftGroupState, followers []admin.RaftFollower) {
+	sort.Slice(followers, func(i, j int) bool { return followers[i].NodeID < followers[j].NodeID })
+
+	out.Section("FOLLOWERS")
+	tw := out.NewTable("NODE", "MATCH-INDEX", "NEXT-INDEX", "LAG", "LEARNER", "LAST-HEARTBEAT")
+	defer tw.Flush()
+	for _, f := range followers {
+		tw.Print(
+			f.NodeID,
+			f.MatchIndex,
+			f.NextIndex,
+			leader.DirtyOffset-f.MatchIndex,
+			f.IsLearner,
+			time.Duration(f.MsSinceLastHeartbeat)*time.Millisecond,
+		)
+	}
+}
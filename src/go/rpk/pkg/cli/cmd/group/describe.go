@@ -24,7 +24,12 @@ import (
 )
 
 func NewDescribeCommand(fs afero.Fs) *cobra.Command {
-	var summary bool
+	var (
+		summary    bool
+		lagHistory bool
+		watch      time.Duration
+		samples    int
+	)
 
 	cmd := &cobra.Command{
 		Use:   "describe [GROUPS...]",
@@ -33,9 +38,26 @@ func NewDescribeCommand(fs afero.Fs) *cobra.Command {
 
 This command describes group members, calculates their lag, and prints detailed
 information about the members.
+
+With --lag-history, this command instead samples the lag of the groups
+repeatedly, every --watch interval for --samples samples, and prints how the
+lag of each partition evolved over the window: the lag at the start and the
+end, the rates at which records were consumed and produced, whether the group
+is catching up or falling behind, and, if it is catching up, an estimate of
+when it will be caught up. For example, to sample the lag every 5 seconds for
+a minute:
+
+    rpk group describe my-group --lag-history --watch 5s --samples 13
 `,
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, groups []string) {
+			if lagHistory && samples < 2 {
+				out.DieUsage("--samples must be at least 2 to calculate a trend")
+			}
+			if lagHistory && watch <= 0 {
+				out.DieUsage("--watch must be a positive duration")
+			}
+
 			p := config.ParamsFromCommand(cmd)
 			cfg, err := p.Load(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
@@ -44,6 +66,16 @@ information about the members.
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
 			defer adm.Close()
 
+			if lagHistory {
+				collected, err := sampleGroupLag(cmd.Context(), adm, groups, watch, samples)
+				out.MaybeDie(err, "unable to sample group lag: %v", err)
+				if len(collected) < 2 {
+					out.Die("unable to calculate the lag trend: only %d sample(s) succeeded", len(collected))
+				}
+				printLagTrends(lagTrends(collected))
+				return
+			}
+
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
 
@@ -80,6 +112,9 @@ information about the members.
 		},
 	}
 	cmd.Flags().BoolVarP(&summary, "print-summary", "s", false, "Print only the group summary section")
+	cmd.Flags().BoolVar(&lagHistory, "lag-history", false, "Sample the lag repeatedly and print its trend per partition")
+	cmd.Flags().DurationVar(&watch, "watch", 5*time.Second, "Interval between lag samples, with --lag-history")
+	cmd.Flags().IntVar(&samples, "samples", 12, "Number of lag samples to take, with --lag-history")
 	return cmd
 }
 
@@ -12,6 +12,8 @@ package group
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -19,6 +21,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
 )
 
 func NewCommand(fs afero.Fs) *cobra.Command {
@@ -95,8 +98,12 @@ members and their lag), and manage offsets.
 	)
 
 	cmd.AddCommand(
+		newCommitCommand(fs),
+		newCopyOffsetsCommand(fs),
 		newDeleteCommand(fs),
 		NewDescribeCommand(fs),
+		newKickCommand(fs),
+		newLagCommand(fs),
 		newListCommand(fs),
 		newSeekCommand(fs),
 	)
@@ -142,7 +149,8 @@ groups, or to list groups that need to be cleaned up.
 }
 
 func newDeleteCommand(fs afero.Fs) *cobra.Command {
-	return &cobra.Command{
+	var force bool
+	cmd := &cobra.Command{
 		Use:   "delete [GROUPS...]",
 		Short: "Delete groups from brokers",
 		Long: `Delete groups from brokers.
@@ -163,6 +171,10 @@ they are explicitly deleted.
 You may want to delete groups to clean up offsets sooner than when they
 automatically are cleaned up, such as when you create temporary groups for
 quick investigation or testing. This command helps you do that.
+
+Groups with active members are not deleted. With --force, their members are
+removed first, as with 'rpk group kick'; this only helps for members that are
+no longer running, since running members rejoin the group.
 `,
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -170,28 +182,60 @@ quick investigation or testing. This command helps you do that.
 			cfg, err := p.Load(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 
-			adm, err := kafka.NewAdmin(fs, p, cfg)
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
-			defer adm.Close()
+			defer cl.Close()
+			adm := kadm.NewClient(cl)
+			adm.SetTimeoutMillis(5000)
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+
+			described, err := adm.DescribeGroups(ctx, args...)
+			out.HandleShardError("DescribeGroups", err)
+
+			statuses := make(map[string]string)
+			var deletable []string
+			for _, g := range described.Sorted() {
+				switch {
+				case g.Err != nil || len(g.Members) == 0:
+					// Describe errors are reported by the deletion.
+					deletable = append(deletable, g.Group)
+				case !force:
+					statuses[g.Group] = fmt.Sprintf("NOT DELETED: %d active members, use --force to remove them", len(g.Members))
+				default:
+					if _, err := removeMembers(ctx, cl, g.Group, allMembers(g)); err != nil {
+						statuses[g.Group] = fmt.Sprintf("unable to remove the %d members: %v", len(g.Members), err)
+						continue
+					}
+					deletable = append(deletable, g.Group)
+				}
+			}
 
-			deleted, err := adm.DeleteGroups(context.Background(), args...)
-			out.HandleShardError("DeleteGroups", err)
+			if len(deletable) > 0 {
+				deleted, err := adm.DeleteGroups(ctx, deletable...)
+				out.HandleShardError("DeleteGroups", err)
+				for _, g := range deleted.Sorted() {
+					statuses[g.Group] = "OK"
+					if g.Err != nil {
+						statuses[g.Group] = g.Err.Error()
+					}
+				}
+			}
 
 			tw := out.NewTable("GROUP", "STATUS")
 			defer tw.Flush()
-			for _, g := range deleted.Sorted() {
-				status := "OK"
-				if g.Err != nil {
-					status = g.Err.Error()
-				}
+			for _, g := range described.Sorted() {
 				tw.PrintStructFields(struct {
 					Group  string
 					Status string
 				}{
 					g.Group,
-					status,
+					statuses[g.Group],
 				})
 			}
 		},
 	}
+	cmd.Flags().BoolVar(&force, "force", false, "Remove the members of the groups that have active members, then delete them")
+	return cmd
 }
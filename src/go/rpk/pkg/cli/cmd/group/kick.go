@@ -0,0 +1,171 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newKickCommand(fs afero.Fs) *cobra.Command {
+	var (
+		group     string
+		members   []string
+		instances []string
+	)
+	cmd := &cobra.Command{
+		Use:   "kick",
+		Short: "Remove members from a group",
+		Long: `Remove members from a group.
+
+A consumer that stops without leaving its group stays a member until its
+session times out. A static member, which has an instance ID, stays a member
+until it rejoins. Until then, the partitions assigned to these zombie members
+are not consumed, and their assignment cannot change.
+
+This command removes such members from the group, which triggers a rebalance
+of the remaining members. Members are selected by member ID with --member, or
+by instance ID with --instance, as listed by 'rpk group describe'. A member
+that is still running rejoins the group as a new member.
+
+    rpk group kick --group my-group --member consumer-1-1f6f0f8a
+    rpk group kick --group my-group --instance my-static-consumer
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			if len(members) == 0 && len(instances) == 0 {
+				out.DieUsage("at least one --member or --instance is required")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+
+			described, err := kadm.NewClient(cl).DescribeGroups(ctx, group)
+			out.HandleShardError("DescribeGroups", err)
+			g, ok := described[group]
+			if !ok {
+				out.Die("group %q was not described", group)
+			}
+			out.MaybeDie(g.Err, "unable to describe group %q: %v", group, g.Err)
+
+			kick, missing := selectMembers(g, members, instances)
+			if len(missing) > 0 {
+				out.Die("%v are not members of group %q", missing, group)
+			}
+
+			kicked, err := removeMembers(ctx, cl, group, kick)
+			out.MaybeDie(err, "unable to remove members from group %q: %v", group, err)
+			printKicked(kicked)
+		},
+	}
+	cmd.Flags().StringVar(&group, "group", "", "Group to remove the members from")
+	cmd.Flags().StringSliceVar(&members, "member", nil, "Member ID to remove from the group (repeatable)")
+	cmd.Flags().StringSliceVar(&instances, "instance", nil, "Instance ID of a static member to remove from the group (repeatable)")
+	cmd.MarkFlagRequired("group")
+	return cmd
+}
+
+// selectMembers returns the members of the group with the given member or
+// instance IDs, and the IDs that match no member.
+func selectMembers(
+	g kadm.DescribedGroup, memberIDs, instanceIDs []string,
+) (selected []kmsg.LeaveGroupRequestMember, missing []string) {
+	find := func(id string, match func(kadm.DescribedGroupMember) bool) {
+		for _, m := range g.Members {
+			if match(m) {
+				selected = append(selected, leavingMember(m))
+				return
+			}
+		}
+		missing = append(missing, id)
+	}
+	for _, id := range memberIDs {
+		find(id, func(m kadm.DescribedGroupMember) bool { return m.MemberID == id })
+	}
+	for _, id := range instanceIDs {
+		find(id, func(m kadm.DescribedGroupMember) bool { return m.InstanceID != nil && *m.InstanceID == id })
+	}
+	return selected, missing
+}
+
+// allMembers returns every member of the group, to remove them all.
+func allMembers(g kadm.DescribedGroup) []kmsg.LeaveGroupRequestMember {
+	members := make([]kmsg.LeaveGroupRequestMember, 0, len(g.Members))
+	for _, m := range g.Members {
+		members = append(members, leavingMember(m))
+	}
+	return members
+}
+
+func leavingMember(m kadm.DescribedGroupMember) kmsg.LeaveGroupRequestMember {
+	member := kmsg.NewLeaveGroupRequestMember()
+	member.MemberID = m.MemberID
+	member.InstanceID = m.InstanceID
+	return member
+}
+
+// removeMembers removes the members from the group with a LeaveGroup request
+// on their behalf, and returns the result of each member.
+func removeMembers(
+	ctx context.Context,
+	cl *kgo.Client,
+	group string,
+	members []kmsg.LeaveGroupRequestMember,
+) ([]kmsg.LeaveGroupResponseMember, error) {
+	req := kmsg.NewPtrLeaveGroupRequest()
+	req.Group = group
+	req.Members = members
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
+	return resp.Members, nil
+}
+
+func printKicked(kicked []kmsg.LeaveGroupResponseMember) {
+	tw := out.NewTable("MEMBER-ID", "INSTANCE-ID", "STATUS")
+	defer tw.Flush()
+	for _, m := range kicked {
+		status := "OK"
+		if err := kerr.ErrorForCode(m.ErrorCode); err != nil {
+			status = err.Error()
+		}
+		var instance string
+		if m.InstanceID != nil {
+			instance = *m.InstanceID
+		}
+		tw.PrintStructFields(struct {
+			Member   string
+			Instance string
+			Status   string
+		}{m.MemberID, instance, status})
+	}
+}
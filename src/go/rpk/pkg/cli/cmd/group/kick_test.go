@@ -0,0 +1,41 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestSelectMembers(t *testing.T) {
+	g := kadm.DescribedGroup{
+		Group: "g",
+		Members: []kadm.DescribedGroupMember{
+			{MemberID: "consumer-1"},
+			{MemberID: "consumer-2", InstanceID: kmsg.StringPtr("static-2")},
+			{MemberID: "consumer-3"},
+		},
+	}
+
+	selected, missing := selectMembers(g, []string{"consumer-3", "consumer-4"}, []string{"static-2", "static-5"})
+	require.Equal(t, []string{"consumer-4", "static-5"}, missing)
+	require.Len(t, selected, 2)
+	require.Equal(t, "consumer-3", selected[0].MemberID)
+	require.Nil(t, selected[0].InstanceID)
+	require.Equal(t, "consumer-2", selected[1].MemberID)
+	require.Equal(t, kmsg.StringPtr("static-2"), selected[1].InstanceID)
+
+	all := allMembers(g)
+	require.Len(t, all, 3)
+	require.Equal(t, "consumer-1", all[0].MemberID)
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func newLagCommand(fs afero.Fs) *cobra.Command {
+	var (
+		exporter string
+		timeout  time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "lag [GROUPS...]",
+		Short: "Print or export the lag of consumer groups",
+		Long: `Print or export the lag of consumer groups.
+
+This command calculates the lag of every partition consumed by the given
+groups, or by all groups if none are given, and prints the total and the
+maximum partition lag of each group.
+
+With --exporter, this command instead runs an HTTP server on the given address
+that exports the lag in the Prometheus exposition format on /metrics. The lag
+is calculated from the Kafka API on every scrape, and the list of groups is
+refreshed on every scrape if no groups are given. The following metrics are
+exported:
+
+    rpk_group_lag{group,topic,partition}              lag of a partition
+    rpk_group_committed_offset{group,topic,partition} last committed offset
+    rpk_group_log_end_offset{group,topic,partition}   end offset of a partition
+    rpk_group_lag_total{group}                        sum of the group lag
+    rpk_group_members{group}                          members in the group
+    rpk_group_lag_scrape_success                      1 if the last scrape succeeded
+
+For example, to export the lag of all groups on port 9102:
+
+    rpk group lag --exporter :9102
+`,
+		Run: func(cmd *cobra.Command, groups []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			if exporter == "" {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				defer cancel()
+				lags, err := collectGroupLag(ctx, adm, groups)
+				out.MaybeDie(err, "unable to calculate group lag: %v", err)
+				printGroupLagSummary(lags)
+				return
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+				ctx, cancel := context.WithTimeout(r.Context(), timeout)
+				defer cancel()
+				lags, err := collectGroupLag(ctx, adm, groups)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "unable to calculate group lag: %v\n", err)
+				}
+				var buf bytes.Buffer
+				writeGroupLagMetrics(&buf, lags, err == nil)
+				w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+				w.Write(buf.Bytes())
+			})
+			fmt.Printf("Exporting group lag on http://%s/metrics\n", exporter)
+			srv := &http.Server{Addr: exporter, Handler: mux}
+			err = srv.ListenAndServe()
+			out.MaybeDie(err, "unable to serve group lag metrics: %v", err)
+		},
+	}
+	cmd.Flags().StringVar(&exporter, "exporter", "", "Address to export the lag on in the Prometheus format (e.g. :9102), rather than printing it")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "Timeout to calculate the lag (or for each scrape, with --exporter)")
+	return cmd
+}
+
+// groupLag is the lag of a single group, flattened from kadm.GroupLag.
+type groupLag struct {
+	group      string
+	members    int
+	partitions []partitionLag
+}
+
+type partitionLag struct {
+	topic     string
+	partition int32
+	committed int64 // -1 if nothing is committed
+	end       int64
+	lag       int64
+}
+
+func (g groupLag) total() (sum, max int64) {
+	for _, p := range g.partitions {
+		sum += p.lag
+		if p.lag > max {
+			max = p.lag
+		}
+	}
+	return sum, max
+}
+
+// collectGroupLag calculates the lag of the given groups, or of all groups if
+// none are given. Partial shard failures are not fatal: the lag of the groups
+// and partitions that could be queried is still returned.
+func collectGroupLag(
+	ctx context.Context, adm *kadm.Client, groups []string,
+) ([]groupLag, error) {
+	if len(groups) == 0 {
+		listed, err := adm.ListGroups(ctx)
+		if err = shardErr(err); err != nil {
+			return nil, fmt.Errorf("unable to list groups: %w", err)
+		}
+		for _, g := range listed.Sorted() {
+			groups = append(groups, g.Group)
+		}
+		if len(groups) == 0 {
+			return nil, nil
+		}
+	}
+
+	described, err := adm.DescribeGroups(ctx, groups...)
+	if err = shardErr(err); err != nil {
+		return nil, fmt.Errorf("unable to describe groups: %w", err)
+	}
+	fetched := adm.FetchManyOffsets(ctx, groups...)
+	fetched.EachError(func(r kadm.FetchOffsetsResponse) {
+		delete(fetched, r.Group)
+	})
+
+	var listed kadm.ListedOffsets
+	listPartitions := described.AssignedPartitions()
+	listPartitions.Merge(fetched.CommittedPartitions())
+	if topics := listPartitions.Topics(); len(topics) > 0 {
+		listed, err = adm.ListEndOffsets(ctx, topics...)
+		if err = shardErr(err); err != nil {
+			return nil, fmt.Errorf("unable to list end offsets: %w", err)
+		}
+	}
+
+	var lags []groupLag
+	for _, group := range described.Sorted() {
+		r, ok := fetched[group.Group]
+		if group.Err != nil || !ok {
+			continue
+		}
+		g := groupLag{
+			group:   group.Group,
+			members: len(group.Members),
+		}
+		for _, l := range kadm.CalculateGroupLag(group, r.Fetched, listed).Sorted() {
+			if l.Err != nil {
+				continue
+			}
+			g.partitions = append(g.partitions, partitionLag{
+				topic:     l.End.Topic,
+				partition: l.End.Partition,
+				committed: l.Commit.At,
+				end:       l.End.Offset,
+				lag:       l.Lag,
+			})
+		}
+		lags = append(lags, g)
+	}
+	return lags, nil
+}
+
+// shardErr returns nil if only some shards of a request failed.
+func shardErr(err error) error {
+	var se *kadm.ShardErrors
+	if errors.As(err, &se) && !se.AllFailed {
+		return nil
+	}
+	return err
+}
+
+func printGroupLagSummary(lags []groupLag) {
+	tw := out.NewTable("GROUP", "MEMBERS", "PARTITIONS", "TOTAL-LAG", "MAX-LAG")
+	defer tw.Flush()
+	for _, g := range lags {
+		sum, max := g.total()
+		tw.Print(g.group, g.members, len(g.partitions), sum, max)
+	}
+}
+
+// writeGroupLagMetrics writes the lags in the Prometheus text exposition
+// format.
+func writeGroupLagMetrics(w io.Writer, lags []groupLag, success bool) {
+	sort.Slice(lags, func(i, j int) bool { return lags[i].group < lags[j].group })
+
+	partitionGauge := func(name, help string, value func(partitionLag) int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, g := range lags {
+			for _, p := range g.partitions {
+				fmt.Fprintf(w, "%s{group=\"%s\",topic=\"%s\",partition=\"%d\"} %d\n",
+					name, escapeLabel(g.group), escapeLabel(p.topic), p.partition, value(p))
+			}
+		}
+	}
+	groupGauge := func(name, help string, value func(groupLag) int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, g := range lags {
+			fmt.Fprintf(w, "%s{group=\"%s\"} %d\n", name, escapeLabel(g.group), value(g))
+		}
+	}
+
+	partitionGauge("rpk_group_lag", "Lag of a partition consumed by a group.",
+		func(p partitionLag) int64 { return p.lag })
+	partitionGauge("rpk_group_committed_offset", "Last offset committed by a group for a partition, -1 if none.",
+		func(p partitionLag) int64 { return p.committed })
+	partitionGauge("rpk_group_log_end_offset", "End offset of a partition consumed by a group.",
+		func(p partitionLag) int64 { return p.end })
+	groupGauge("rpk_group_lag_total", "Sum of the lag of all partitions consumed by a group.",
+		func(g groupLag) int64 { sum, _ := g.total(); return sum })
+	groupGauge("rpk_group_members", "Number of members in a group.",
+		func(g groupLag) int64 { return int64(g.members) })
+
+	var ok int
+	if success {
+		ok = 1
+	}
+	fmt.Fprintf(w, "# HELP rpk_group_lag_scrape_success Whether the group lag could be calculated.\n")
+	fmt.Fprintf(w, "# TYPE rpk_group_lag_scrape_success gauge\n")
+	fmt.Fprintf(w, "rpk_group_lag_scrape_success %d\n", ok)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// lagSample is the lag of groups at a point in time.
+type lagSample struct {
+	at   time.Time
+	lags []groupLag
+}
+
+// sampleGroupLag collects the lag of the groups every interval, samples
+// times. Samples that fail are skipped, with a warning.
+func sampleGroupLag(
+	ctx context.Context, adm *kadm.Client, groups []string, interval time.Duration, samples int,
+) ([]lagSample, error) {
+	var collected []lagSample
+	for i := 0; i < samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return collected, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		sampleCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		lags, err := collectGroupLag(sampleCtx, adm, groups)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sample %d/%d failed: %v\n", i+1, samples, err)
+			continue
+		}
+		collected = append(collected, lagSample{at: time.Now(), lags: lags})
+		fmt.Fprintf(os.Stderr, "sample %d/%d collected\n", i+1, samples)
+	}
+	return collected, nil
+}
+
+// partitionTrend is how the lag of a partition evolved between the first and
+// the last sample it was seen in.
+type partitionTrend struct {
+	topic     string
+	partition int32
+	first     partitionLag
+	last      partitionLag
+	elapsed   time.Duration
+}
+
+type groupTrend struct {
+	group      string
+	partitions []partitionTrend
+}
+
+// lagTrends returns the trend of the lag of every partition across the
+// samples, sorted by group, topic and partition.
+func lagTrends(samples []lagSample) []groupTrend {
+	type key struct {
+		topic     string
+		partition int32
+	}
+	type seen struct {
+		first, last     partitionLag
+		firstAt, lastAt time.Time
+	}
+	byGroup := make(map[string]map[key]*seen)
+	for _, s := range samples {
+		for _, g := range s.lags {
+			ps, ok := byGroup[g.group]
+			if !ok {
+				ps = make(map[key]*seen)
+				byGroup[g.group] = ps
+			}
+			for _, p := range g.partitions {
+				k := key{p.topic, p.partition}
+				if sn, ok := ps[k]; ok {
+					sn.last, sn.lastAt = p, s.at
+					continue
+				}
+				ps[k] = &seen{p, p, s.at, s.at}
+			}
+		}
+	}
+
+	var trends []groupTrend
+	for group, ps := range byGroup {
+		g := groupTrend{group: group}
+		for k, sn := range ps {
+			g.partitions = append(g.partitions, partitionTrend{
+				topic:     k.topic,
+				partition: k.partition,
+				first:     sn.first,
+				last:      sn.last,
+				elapsed:   sn.lastAt.Sub(sn.firstAt),
+			})
+		}
+		sort.Slice(g.partitions, func(i, j int) bool {
+			l, r := g.partitions[i], g.partitions[j]
+			return l.topic < r.topic || l.topic == r.topic && l.partition < r.partition
+		})
+		trends = append(trends, g)
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].group < trends[j].group })
+	return trends
+}
+
+// consumeRate returns the rate at which the group commits offsets, in
+// records per second, and false if it cannot be calculated.
+func (p partitionTrend) consumeRate() (float64, bool) {
+	if p.elapsed <= 0 || p.first.committed < 0 || p.last.committed < 0 {
+		return 0, false
+	}
+	return float64(p.last.committed-p.first.committed) / p.elapsed.Seconds(), true
+}
+
+// produceRate returns the rate at which records are produced to the
+// partition, in records per second, and false if it cannot be calculated.
+func (p partitionTrend) produceRate() (float64, bool) {
+	if p.elapsed <= 0 {
+		return 0, false
+	}
+	return float64(p.last.end-p.first.end) / p.elapsed.Seconds(), true
+}
+
+// status returns whether the group is catching up on the partition, and, if
+// it is, the estimated time until it is caught up at the current pace.
+func (p partitionTrend) status() (trend string, eta time.Duration) {
+	delta := p.last.lag - p.first.lag
+	switch {
+	case p.last.lag == 0:
+		return "caught-up", 0
+	case delta < 0 && p.elapsed > 0:
+		perSecond := float64(-delta) / p.elapsed.Seconds()
+		eta = time.Duration(float64(p.last.lag) / perSecond * float64(time.Second))
+		return "catching-up", eta.Round(time.Second)
+	case delta > 0:
+		return "falling-behind", 0
+	default:
+		return "steady", 0
+	}
+}
+
+func printLagTrends(trends []groupTrend) {
+	for _, g := range trends {
+		fmt.Printf("GROUP %s\n", g.group)
+		tw := out.NewTable(
+			"TOPIC",
+			"PARTITION",
+			"LAG-START",
+			"LAG-END",
+			"LAG-DELTA",
+			"CONSUME-RATE",
+			"PRODUCE-RATE",
+			"TREND",
+			"ETA",
+		)
+		for _, p := range g.partitions {
+			consume, produce := "-", "-"
+			if r, ok := p.consumeRate(); ok {
+				consume = fmt.Sprintf("%.1f/s", r)
+			}
+			if r, ok := p.produceRate(); ok {
+				produce = fmt.Sprintf("%.1f/s", r)
+			}
+			trend, eta := p.status()
+			etaStr := "-"
+			if trend == "catching-up" {
+				etaStr = eta.String()
+			}
+			tw.Print(
+				p.topic,
+				p.partition,
+				p.first.lag,
+				p.last.lag,
+				fmt.Sprintf("%+d", p.last.lag-p.first.lag),
+				consume,
+				produce,
+				trend,
+				etaStr,
+			)
+		}
+		tw.Flush()
+		fmt.Println()
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLagTrends(t *testing.T) {
+	start := time.Unix(1000, 0)
+	samples := []lagSample{
+		{at: start, lags: []groupLag{
+			{group: "b", partitions: []partitionLag{
+				{topic: "foo", partition: 1, committed: 100, end: 200, lag: 100},
+				{topic: "foo", partition: 0, committed: 10, end: 20, lag: 10},
+			}},
+			{group: "a", partitions: []partitionLag{
+				{topic: "bar", partition: 0, committed: -1, end: 5, lag: 5},
+			}},
+		}},
+		{at: start.Add(5 * time.Second), lags: []groupLag{
+			{group: "b", partitions: []partitionLag{
+				{topic: "foo", partition: 0, committed: 20, end: 40, lag: 20},
+				{topic: "foo", partition: 1, committed: 150, end: 200, lag: 50},
+			}},
+		}},
+		{at: start.Add(10 * time.Second), lags: []groupLag{
+			{group: "a", partitions: []partitionLag{
+				{topic: "bar", partition: 0, committed: 5, end: 5, lag: 0},
+			}},
+			{group: "b", partitions: []partitionLag{
+				{topic: "foo", partition: 2, committed: 0, end: 0, lag: 0},
+			}},
+		}},
+	}
+
+	trends := lagTrends(samples)
+	require.Len(t, trends, 2)
+	require.Equal(t, "a", trends[0].group)
+	require.Equal(t, "b", trends[1].group)
+
+	bar := trends[0].partitions[0]
+	require.Equal(t, 10*time.Second, bar.elapsed)
+	_, ok := bar.consumeRate()
+	require.False(t, ok, "nothing was committed at the start")
+	trend, _ := bar.status()
+	require.Equal(t, "caught-up", trend)
+
+	foo := trends[1].partitions
+	require.Len(t, foo, 3)
+	for i, p := range foo {
+		require.Equal(t, int32(i), p.partition)
+	}
+
+	// Partition 0 falls behind: the lag doubled in 5s.
+	rate, ok := foo[0].consumeRate()
+	require.True(t, ok)
+	require.Equal(t, 2.0, rate)
+	rate, ok = foo[0].produceRate()
+	require.True(t, ok)
+	require.Equal(t, 4.0, rate)
+	trend, _ = foo[0].status()
+	require.Equal(t, "falling-behind", trend)
+
+	// Partition 1 catches up: the lag decreases by 10/s.
+	trend, eta := foo[1].status()
+	require.Equal(t, "catching-up", trend)
+	require.Equal(t, 5*time.Second, eta)
+
+	// Partition 2 was seen once: no rate can be calculated.
+	_, ok = foo[2].produceRate()
+	require.False(t, ok)
+}
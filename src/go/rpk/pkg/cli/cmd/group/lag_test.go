@@ -0,0 +1,61 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGroupLagMetrics(t *testing.T) {
+	lags := []groupLag{
+		{
+			group:   `we"ird`,
+			members: 0,
+		},
+		{
+			group:   "app",
+			members: 2,
+			partitions: []partitionLag{
+				{topic: "foo", partition: 0, committed: 10, end: 15, lag: 5},
+				{topic: "foo", partition: 1, committed: -1, end: 3, lag: 3},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	writeGroupLagMetrics(&buf, lags, true)
+	exp := `# HELP rpk_group_lag Lag of a partition consumed by a group.
+# TYPE rpk_group_lag gauge
+rpk_group_lag{group="app",topic="foo",partition="0"} 5
+rpk_group_lag{group="app",topic="foo",partition="1"} 3
+# HELP rpk_group_committed_offset Last offset committed by a group for a partition, -1 if none.
+# TYPE rpk_group_committed_offset gauge
+rpk_group_committed_offset{group="app",topic="foo",partition="0"} 10
+rpk_group_committed_offset{group="app",topic="foo",partition="1"} -1
+# HELP rpk_group_log_end_offset End offset of a partition consumed by a group.
+# TYPE rpk_group_log_end_offset gauge
+rpk_group_log_end_offset{group="app",topic="foo",partition="0"} 15
+rpk_group_log_end_offset{group="app",topic="foo",partition="1"} 3
+# HELP rpk_group_lag_total Sum of the lag of all partitions consumed by a group.
+# TYPE rpk_group_lag_total gauge
+rpk_group_lag_total{group="app"} 8
+rpk_group_lag_total{group="we\"ird"} 0
+# HELP rpk_group_members Number of members in a group.
+# TYPE rpk_group_members gauge
+rpk_group_members{group="app"} 2
+rpk_group_members{group="we\"ird"} 0
+# HELP rpk_group_lag_scrape_success Whether the group lag could be calculated.
+# TYPE rpk_group_lag_scrape_success gauge
+rpk_group_lag_scrape_success 1
+`
+	require.Equal(t, exp, buf.String())
+}
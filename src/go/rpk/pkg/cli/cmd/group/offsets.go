@@ -0,0 +1,194 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func newCopyOffsetsCommand(fs afero.Fs) *cobra.Command {
+	var (
+		from   string
+		to     string
+		topics []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "copy-offsets --from GROUP --to GROUP [--topic TOPIC]",
+		Short: "Copy the committed offsets of a group to another group",
+		Long: `Copy the committed offsets of a group to another group.
+
+This command commits the offsets of the --from group to the --to group, so that
+a new application starts consuming where another one stopped, e.g. when
+migrating consumers to a new group name.
+
+Only the partitions committed by the --from group are copied; other commits of
+the --to group are left untouched. Use --topic to copy the commits of only some
+topics.
+
+Like "rpk group seek", this requires the --to group to be empty.
+
+EXAMPLES
+
+Start group new-app where group old-app stopped:
+    rpk group copy-offsets --from old-app --to new-app
+Copy only the commits of topic foo:
+    rpk group copy-offsets --from old-app --to new-app --topic foo
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if from == to {
+				out.DieUsage("--from and --to must be different groups.")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			tset := make(map[string]bool)
+			for _, topic := range topics {
+				tset[topic] = true
+			}
+
+			commitTo := seekFetch(adm, from, tset)
+			if len(commitTo) == 0 {
+				out.Die("Group %q has no commits to copy.", from)
+			}
+			current := seekFetch(adm, to, tset)
+
+			committed, err := adm.CommitOffsets(context.Background(), to, commitTo)
+			out.MaybeDie(err, "unable to commit offsets: %v", err)
+			printCommitted(current, commitTo, committed)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Group to copy the commits from")
+	cmd.Flags().StringVar(&to, "to", "", "Group to commit to")
+	cmd.Flags().StringSliceVar(&topics, "topic", nil, "Only copy the commits of these topics (repeatable)")
+	cobra.MarkFlagRequired(cmd.Flags(), "from")
+	cobra.MarkFlagRequired(cmd.Flags(), "to")
+
+	return cmd
+}
+
+func newCommitCommand(fs afero.Fs) *cobra.Command {
+	var (
+		topic      string
+		partitions []int32
+		offset     int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "commit [GROUP] --topic TOPIC --partition PARTITION --offset OFFSET",
+		Short: "Commit an offset for partitions of a group",
+		Long: `Commit an offset for partitions of a group.
+
+This command commits an offset directly, e.g. to replay data from a known offset
+for a new application. Unlike "rpk group seek", it commits the same offset to
+each partition given with --partition, or to every partition of the topic if
+--partition is not used.
+
+The offset must not be past the end of any of the partitions. As with "rpk group
+seek", this requires the group to be empty.
+
+EXAMPLES
+
+Commit offset 100 for partition 0 of topic foo:
+    rpk group commit g --topic foo --partition 0 --offset 100
+Make group g consume topic foo from the start:
+    rpk group commit g --topic foo --offset 0
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if offset < 0 {
+				out.DieUsage("--offset cannot be negative.")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			group := args[0]
+
+			ends, err := adm.ListEndOffsets(context.Background(), topic)
+			if err == nil {
+				err = ends.Error()
+			}
+			out.MaybeDie(err, "unable to list the end offsets of %q: %v", topic, err)
+
+			commitTo, err := offsetsToCommit(topic, partitions, offset, ends)
+			out.MaybeDieErr(err)
+			current := seekFetch(adm, group, map[string]bool{topic: true})
+
+			committed, err := adm.CommitOffsets(context.Background(), group, commitTo)
+			out.MaybeDie(err, "unable to commit offsets: %v", err)
+			printCommitted(current, commitTo, committed)
+		},
+	}
+
+	cmd.Flags().StringVar(&topic, "topic", "", "Topic to commit to")
+	cmd.Flags().Int32SliceVar(&partitions, "partition", nil, "Partitions to commit to (repeatable), all partitions of the topic by default")
+	cmd.Flags().Int64Var(&offset, "offset", 0, "Offset to commit")
+	cobra.MarkFlagRequired(cmd.Flags(), "topic")
+	cobra.MarkFlagRequired(cmd.Flags(), "offset")
+
+	return cmd
+}
+
+// offsetsToCommit returns the offsets committing offset to the partitions of
+// topic, or to all of its partitions if none is given. The end offsets of the
+// topic are used to validate the partitions and the offset.
+func offsetsToCommit(
+	topic string, partitions []int32, offset int64, ends kadm.ListedOffsets,
+) (kadm.Offsets, error) {
+	tends, ok := ends[topic]
+	if !ok || len(tends) == 0 {
+		return nil, fmt.Errorf("topic %q does not exist", topic)
+	}
+	if len(partitions) == 0 {
+		for p := range tends {
+			partitions = append(partitions, p)
+		}
+	}
+
+	o := make(kadm.Offsets)
+	for _, p := range partitions {
+		end, ok := tends[p]
+		if !ok {
+			return nil, fmt.Errorf("partition %d of topic %q does not exist", p, topic)
+		}
+		if offset > end.Offset {
+			return nil, fmt.Errorf("offset %d is past the end offset %d of partition %d of topic %q", offset, end.Offset, p, topic)
+		}
+		o.Add(kadm.Offset{
+			Topic:       topic,
+			Partition:   p,
+			At:          offset,
+			LeaderEpoch: -1,
+		})
+	}
+	return o, nil
+}
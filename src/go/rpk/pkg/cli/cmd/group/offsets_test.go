@@ -0,0 +1,80 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestOffsetsToCommit(t *testing.T) {
+	ends := kadm.ListedOffsets{
+		"foo": map[int32]kadm.ListedOffset{
+			0: {Topic: "foo", Partition: 0, Offset: 100},
+			1: {Topic: "foo", Partition: 1, Offset: 50},
+		},
+	}
+	offset := func(p int32, at int64) kadm.Offset {
+		return kadm.Offset{Topic: "foo", Partition: p, At: at, LeaderEpoch: -1}
+	}
+
+	for _, test := range []struct {
+		name       string
+		topic      string
+		partitions []int32
+		offset     int64
+
+		exp    kadm.Offsets
+		expErr bool
+	}{
+		{
+			name:       "one partition",
+			topic:      "foo",
+			partitions: []int32{0},
+			offset:     75,
+			exp:        kadm.Offsets{"foo": map[int32]kadm.Offset{0: offset(0, 75)}},
+		},
+		{
+			name:   "all partitions",
+			topic:  "foo",
+			offset: 10,
+			exp:    kadm.Offsets{"foo": map[int32]kadm.Offset{0: offset(0, 10), 1: offset(1, 10)}},
+		},
+		{
+			name:   "past the end of a partition",
+			topic:  "foo",
+			offset: 75,
+			expErr: true,
+		},
+		{
+			name:       "unknown partition",
+			topic:      "foo",
+			partitions: []int32{2},
+			expErr:     true,
+		},
+		{
+			name:   "unknown topic",
+			topic:  "bar",
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := offsetsToCommit(test.topic, test.partitions, test.offset, ends)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
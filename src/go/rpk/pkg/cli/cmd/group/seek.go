@@ -102,10 +102,10 @@ Seek group G to the beginning of a topic it was not previously consuming:
 			}
 			switch {
 			case n == 0:
-				out.Die("Must specify one --to flag.")
+				out.DieUsage("Must specify one --to flag.")
 			case n == 1:
 			default:
-				out.Die("Cannot specify multiple --to flags.")
+				out.DieUsage("Cannot specify multiple --to flags.")
 			}
 
 			tset := make(map[string]bool)
@@ -217,7 +217,7 @@ func seek(
 		tps := current.TopicsSet()
 		for topic := range topics {
 			if _, exists := tps[topic]; !exists && !allowNewTopics {
-				out.Die("Cannot commit new topic %q without --allow-new-topics.", topic)
+				out.DieUsage("Cannot commit new topic %q without --allow-new-topics.", topic)
 			}
 			tps[topic] = map[int32]struct{}{} // ensure exists
 		}
@@ -241,7 +241,7 @@ func seek(
 				case 19: // e.g. "1622505600000000000"; nano to milli
 					milli /= 1e6
 				default:
-					out.Die("--to timestamp %q is not a second, nor a millisecond, nor a nanosecond", to)
+					out.DieUsage("--to timestamp %q is not a second, nor a millisecond, nor a nanosecond", to)
 				}
 				listed, err = adm.ListOffsetsAfterMilli(context.Background(), milli, topics...)
 			}
@@ -256,7 +256,12 @@ func seek(
 	// Finally, we commit.
 	committed, err := adm.CommitOffsets(context.Background(), group, commitTo)
 	out.MaybeDie(err, "unable to commit offsets: %v", err)
+	printCommitted(current, commitTo, committed)
+}
 
+// printCommitted prints the offsets committed to a group next to the offsets
+// the group had before.
+func printCommitted(current, commitTo kadm.Offsets, committed kadm.OffsetResponses) {
 	useErr := committed.Error() != nil
 	headers := []string{"topic", "partition", "prior-offset", "current-offset"}
 	if useErr {
@@ -14,11 +14,16 @@ package iotune
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
@@ -35,6 +40,16 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 	command := &cobra.Command{
 		Use:   "iotune",
 		Short: "Measure filesystem performance and create IO configuration file",
+		Long: `Measure filesystem performance and create IO configuration file.
+
+The devices backing each evaluation directory are resolved, down to the members
+of md-RAID arrays, and iotune runs once per set of devices: directories sharing
+their devices are evaluated once. The results are printed per device, along
+with the class of the devices (nvme, non-nvme, or nvme-raid, non-nvme-raid and
+mixed-raid for directories spanning several devices), and the IO configuration
+file gets an entry for the mountpoint of each of them, so that hosts mixing
+storage get accurate IO properties for every device.
+`,
 		Run: func(cmd *cobra.Command, args []string) {
 			timeout += duration
 			p := config.ParamsFromCommand(cmd)
@@ -57,8 +72,17 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 					out.Exit("iotune canceled.")
 				}
 			}
-			tuner := tuners.NewIoTuneTuner(
+			irqProcFile := irq.NewProcFile(fs)
+			blockDevices := disk.NewBlockDevices(
 				fs,
+				irq.NewDeviceInfo(fs, irqProcFile),
+				irqProcFile,
+				os.NewProc(),
+				timeout,
+			)
+			tuner := tuners.NewDeviceIoTuneTuner(
+				fs,
+				blockDevices,
 				evalDirectories,
 				outputFile,
 				duration,
@@ -68,6 +92,7 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 			result := tuner.Tune()
 			out.MaybeDie(result.Error(), "error during iotune execution: %v", result.Error())
 
+			printDeviceResults(tuner.Results())
 			fmt.Printf("IO configuration file stored as %q\n", outputFile)
 		},
 	}
@@ -107,3 +132,35 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 	command.Flags().BoolVar(&noConfirm, "no-confirm", false, "Disable confirmation prompt if the iotune file already exists")
 	return command
 }
+
+func printDeviceResults(results []tuners.IoTuneDeviceResult) {
+	tw := out.NewTable(
+		"directories",
+		"devices",
+		"class",
+		"mountpoint",
+		"read-iops",
+		"read-bandwidth",
+		"write-iops",
+		"write-bandwidth",
+	)
+	defer tw.Flush()
+	for _, r := range results {
+		devices := strings.Join(r.Devices, ",")
+		if devices == "" {
+			devices = "-"
+		}
+		for _, d := range r.Disks {
+			tw.Print(
+				strings.Join(r.Directories, ","),
+				devices,
+				r.Class,
+				d.MountPoint,
+				d.ReadIops,
+				units.HumanSize(float64(d.ReadBandwidth))+"/s",
+				d.WriteIops,
+				units.HumanSize(float64(d.WriteBandwidth))+"/s",
+			)
+		}
+	}
+}
@@ -12,8 +12,10 @@
 package brokers
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -80,7 +82,11 @@ func newListCommand(fs afero.Fs) *cobra.Command {
 }
 
 func newDecommissionBroker(fs afero.Fs) *cobra.Command {
-	return &cobra.Command{
+	var (
+		wait    bool
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
 		Use:   "decommission [BROKER ID]",
 		Short: "Decommission the given broker",
 		Long: `Decommission the given broker.
@@ -89,13 +95,17 @@ Decommissioning a broker removes it from the cluster.
 
 A decommission request is sent to every broker in the cluster, only the cluster
 leader handles the request.
+
+Decommissioning moves the partitions of the broker to the other brokers, which
+can take a while. With --wait, this command waits until the broker is removed
+from the cluster.
 `,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			broker, err := strconv.Atoi(args[0])
 			out.MaybeDie(err, "invalid broker %s: %v", args[0], err)
 			if broker < 0 {
-				out.Die("invalid negative broker id %v", broker)
+				out.DieUsage("invalid negative broker id %v", broker)
 			}
 
 			p := config.ParamsFromCommand(cmd)
@@ -108,9 +118,26 @@ leader handles the request.
 			err = cl.DecommissionBroker(cmd.Context(), broker)
 			out.MaybeDie(err, "unable to decommission broker: %v", err)
 
+			if !wait {
+				fmt.Printf("Success, broker %d has been decommissioned!\n", broker)
+				return
+			}
+
+			fmt.Printf("Waiting for broker %d to be decommissioned...\n", broker)
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			err = cl.WaitForDecommission(ctx, broker)
+			out.MaybeDie(err, "error waiting for broker %d to be decommissioned: %v", broker, err)
 			fmt.Printf("Success, broker %d has been decommissioned!\n", broker)
 		},
 	}
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait until the broker is removed from the cluster")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "With --wait, how long to wait for the broker to be decommissioned, e.g. 30m (no limit by default)")
+	return cmd
 }
 
 func newRecommissionBroker(fs afero.Fs) *cobra.Command {
@@ -133,7 +160,7 @@ the cluster leader handles the request.
 			broker, err := strconv.Atoi(args[0])
 			out.MaybeDie(err, "invalid broker %s: %v", args[0], err)
 			if broker < 0 {
-				out.Die("invalid negative broker id %v", broker)
+				out.DieUsage("invalid negative broker id %v", broker)
 			}
 
 			p := config.ParamsFromCommand(cmd)
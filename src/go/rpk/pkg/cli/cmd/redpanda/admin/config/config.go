@@ -6,8 +6,10 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -26,6 +28,7 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 	cmd.AddCommand(
 		newPrintCommand(fs),
 		newLogLevelCommand(fs),
+		newSetCommand(fs),
 	)
 	return cmd
 }
@@ -95,7 +98,10 @@ from the set of available loggers.
 The special logger "all" enables all loggers. Alternatively, you can specify
 many loggers at once. To see all possible loggers, run the following command:
 
-  redpanda --help-loggers
+  rpk redpanda admin loggers list --host <host>
+
+Loggers are discovered from the broker if it supports listing them, otherwise
+a built in list of loggers is used.
 
 This command accepts loggers that it does not know of to ensure you can
 independently update your redpanda installations from rpk. The success or
@@ -112,21 +118,28 @@ failure of enabling each logger is individually printed.
 
 			switch len(loggers) {
 			case 0:
-				choices := append([]string{"all"}, possibleLoggers...)
+				available := availableLoggers(cmd.Context(), cl)
+				choices := append([]string{"all"}, available...)
 				pick, err := out.Pick(choices, "Which logger would you like to set (all selects everything)?")
 				out.MaybeDie(err, "unable to pick logger: %v", err)
 				if pick == "all" {
-					loggers = possibleLoggers
+					loggers = available
 				} else {
 					loggers = []string{pick}
 				}
 
 			case 1:
 				if loggers[0] == "all" {
-					loggers = possibleLoggers
+					loggers = availableLoggers(cmd.Context(), cl)
 				}
 			}
 
+			levels := make(map[string]string, len(loggers))
+			for _, logger := range loggers {
+				levels[logger] = level
+			}
+			errs := cl.SetLoggerLevels(cmd.Context(), levels, time.Duration(expirySeconds)*time.Second)
+
 			type failure struct {
 				logger string
 				err    error
@@ -135,8 +148,7 @@ failure of enabling each logger is individually printed.
 			var successes []string
 
 			for _, logger := range loggers {
-				err := cl.SetLogLevel(cmd.Context(), logger, level, expirySeconds)
-				if err != nil {
+				if err, failed := errs[logger]; failed {
 					failures = append(failures, failure{logger, err})
 				} else {
 					successes = append(successes, logger)
@@ -169,7 +181,22 @@ failure of enabling each logger is individually printed.
 	return cmd
 }
 
-// List of possible loggers to set; more can be added in the future.
+// availableLoggers returns the loggers of the broker, or the built in list of
+// loggers if the broker does not support listing them.
+func availableLoggers(ctx context.Context, cl *admin.AdminAPI) []string {
+	listed, err := cl.GetLoggers(ctx)
+	if err != nil || len(listed) == 0 {
+		return possibleLoggers
+	}
+	loggers := make([]string, 0, len(listed))
+	for _, l := range listed {
+		loggers = append(loggers, l.Name)
+	}
+	return loggers
+}
+
+// List of possible loggers to set, used if the broker does not support
+// listing its loggers; more can be added in the future.
 // To generate this list, run 'redpanda --help-loggers'.
 var possibleLoggers = []string{
 	"admin_api_server",
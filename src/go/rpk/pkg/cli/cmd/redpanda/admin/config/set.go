@@ -0,0 +1,90 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newSetCommand(fs afero.Fs) *cobra.Command {
+	var broker int
+	cmd := &cobra.Command{
+		Use:   "set [KEY=VALUE...]",
+		Short: "Override configuration properties of a single broker",
+		Long: `Override configuration properties of a single broker.
+
+This command sets properties on one broker only, overriding the cluster wide
+value, which is useful to tune brokers running on different hardware. An empty
+value clears the override, reverting the property to the cluster wide value:
+
+  rpk redpanda admin config set --broker 2 storage_read_buffer_size=262144
+  rpk redpanda admin config set --broker 2 storage_read_buffer_size=
+
+Values are passed through to the broker, which validates them. The broker is
+selected by node ID and must be reachable through one of the admin API
+addresses of rpk. Brokers that do not support node overrides return an error.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			upsert, remove, err := parseOverrides(args)
+			out.MaybeDieErr(err)
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			result, err := cl.PatchBrokerConfig(cmd.Context(), broker, upsert, remove)
+			if admin.IsNotFound(err) {
+				out.Die("broker %d does not support node configuration overrides", broker)
+			}
+			out.MaybeDie(err, "unable to set node configuration: %v", err)
+
+			fmt.Printf("Successfully updated the configuration of broker %d.\n", broker)
+			if result.Restart {
+				fmt.Println("The broker must be restarted for some changes to take effect.")
+			}
+		},
+	}
+
+	cmd.Flags().IntVar(&broker, "broker", -1, "Node ID of the broker to configure")
+	cobra.MarkFlagRequired(cmd.Flags(), "broker")
+
+	return cmd
+}
+
+// parseOverrides splits KEY=VALUE arguments into the properties to override
+// and, for empty values, the properties to clear.
+func parseOverrides(args []string) (map[string]interface{}, []string, error) {
+	upsert := make(map[string]interface{})
+	var remove []string
+	for _, arg := range args {
+		eq := strings.IndexByte(arg, '=')
+		if eq <= 0 {
+			return nil, nil, fmt.Errorf("invalid argument %q, expected KEY=VALUE", arg)
+		}
+		key, value := arg[:eq], arg[eq+1:]
+		if value == "" {
+			remove = append(remove, key)
+		} else {
+			upsert[key] = value
+		}
+	}
+	return upsert, remove, nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOverrides(t *testing.T) {
+	upsert, remove, err := parseOverrides([]string{"a=1", "b=", "c=x=y"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": "1", "c": "x=y"}, upsert)
+	require.Equal(t, []string{"b"}, remove)
+
+	_, _, err = parseOverrides([]string{"a"})
+	require.Error(t, err)
+	_, _, err = parseOverrides([]string{"=1"})
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package loggers contains commands to talk to the Redpanda's admin loggers
+// endpoints.
+package loggers
+
+import (
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the loggers admin command.
+func NewCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "loggers",
+		Short: "View the loggers of a broker through the admin listener",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newListCommand(fs),
+	)
+	return cmd
+}
+
+func newListCommand(fs afero.Fs) *cobra.Command {
+	var host string
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the loggers of a broker and their current level",
+		Long: `List the loggers of a broker and their current level.
+
+The names listed by this command are the names accepted by
+'rpk redpanda admin config log-level set'.
+
+Older versions of Redpanda do not support listing loggers; for these versions,
+run the following command on the broker instead:
+
+  redpanda --help-loggers
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewHostClient(fs, cfg, host)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			loggers, err := cl.GetLoggers(cmd.Context())
+			if admin.IsNotFound(err) {
+				out.Die("this version of Redpanda does not support listing loggers, run 'redpanda --help-loggers' on the broker instead")
+			}
+			out.MaybeDie(err, "unable to request loggers: %v", err)
+
+			tw := out.NewTable("LOGGER", "LEVEL")
+			defer tw.Flush()
+			for _, l := range loggers {
+				tw.Print(l.Name, l.Level)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "", "either a hostname or an index into rpk.admin_api.addresses config section to select the hosts to issue the request to")
+	cobra.MarkFlagRequired(cmd.Flags(), "host")
+
+	return cmd
+}
@@ -47,7 +47,7 @@ func newListCommand(fs afero.Fs) *cobra.Command {
 			brokerID, err := strconv.Atoi(args[0])
 			out.MaybeDie(err, "invalid broker %s: %v", args[0], err)
 			if brokerID < 0 {
-				out.Die("invalid negative broker id %v", brokerID)
+				out.DieUsage("invalid negative broker id %v", brokerID)
 			}
 
 			p := config.ParamsFromCommand(cmd)
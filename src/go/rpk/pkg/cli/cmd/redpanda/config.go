@@ -38,6 +38,7 @@ func NewConfigCommand(fs afero.Fs) *cobra.Command {
 	root.AddCommand(set(fs))
 	root.AddCommand(bootstrap(fs))
 	root.AddCommand(initNode(fs))
+	root.AddCommand(lint(fs))
 
 	return root
 }
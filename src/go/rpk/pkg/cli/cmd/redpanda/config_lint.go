@@ -0,0 +1,81 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+// +build linux
+
+package redpanda
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func lint(fs afero.Fs) *cobra.Command {
+	var strict bool
+	c := &cobra.Command{
+		Use:     "lint [FILE]",
+		Aliases: []string{"validate"},
+		Short:   "Validate a redpanda.yaml without starting redpanda",
+		Long: `Validate a redpanda.yaml without starting redpanda.
+
+This command checks a configuration file against the schema known by rpk,
+and prints each issue with its line and column:
+
+  * values of the wrong type, such as a string where a port is expected;
+  * unknown keys, which are errors in sections whose keys are all known to
+    rpk, such as the rpk section, and warnings elsewhere;
+  * deprecated keys, and cluster properties that are only read from the file
+    when the cluster is first started;
+  * contradicting settings, such as TLS configured for a listener that does
+    not exist, mtls_identity authentication on a plaintext listener, or two
+    listeners bound to the same address.
+
+The file to check defaults to the redpanda.yaml rpk would use. The command
+exits with status 1 if the file has errors, or with --strict, any warnings,
+which makes it suitable to validate repositories of configuration in CI:
+
+  rpk redpanda config lint --strict config/redpanda.yaml
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var path string
+			if len(args) == 1 {
+				path = args[0]
+			} else {
+				p := config.ParamsFromCommand(cmd)
+				var err error
+				path, err = p.LocateConfig(fs)
+				out.MaybeDieErr(err)
+			}
+
+			raw, err := afero.ReadFile(fs, path)
+			out.MaybeDie(err, "unable to read config file %q: %v", path, err)
+
+			issues, err := config.Lint(raw)
+			out.MaybeDie(err, "%s: %v", path, err)
+
+			var failed bool
+			for _, i := range issues {
+				fmt.Printf("%s:%s\n", path, i)
+				failed = failed || !i.Warning || strict
+			}
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	c.Flags().BoolVar(&strict, "strict", false, "Fail on warnings too")
+	return c
+}
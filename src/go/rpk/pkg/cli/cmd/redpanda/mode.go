@@ -13,7 +13,10 @@
 package redpanda
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -22,21 +25,65 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type modeOptions struct {
+	profile string
+	dryRun  bool
+	format  string
+}
+
 func NewModeCommand(fs afero.Fs) *cobra.Command {
-	var configFile string
+	command := newModeCommand(fs, "mode [MODE]")
+	command.AddCommand(newModeCommand(fs, "set [MODE]"))
+	return command
+}
+
+func newModeCommand(fs afero.Fs, use string) *cobra.Command {
+	var (
+		configFile string
+		opts       modeOptions
+	)
 	command := &cobra.Command{
-		Use:   "mode <mode>",
+		Use:   use,
 		Short: "Enable a default configuration mode",
-		Long:  "",
+		Long: `Enable a default configuration mode.
+
+The development mode (dev) disables the tuners and runs Redpanda in developer
+mode, the production mode (prod) enables the tuners recommended for
+production.
+
+Before writing, the changes to the configuration and to the tuners are printed.
+Use --dry-run to only print them, and --format json to print them as JSON.
+
+Organizations with their own hardening baseline can define a mode profile in a
+YAML file and pass it with --profile instead of a mode:
+
+    name: hardened
+    base: prod
+    set:
+      rpk.tune_fstrim: true
+      rpk.coredump_dir: /mnt/coredump
+
+The profile applies its base mode, if any, and then sets every key under set
+as 'rpk redpanda config set' would.
+`,
 		Args: func(_ *cobra.Command, args []string) error {
-			if len(args) < 1 {
-				return fmt.Errorf("requires a mode [%s]", strings.Join(config.AvailableModes(), ", "))
+			if len(args) > 1 {
+				return fmt.Errorf("accepts a single mode [%s]", strings.Join(config.AvailableModes(), ", "))
+			}
+			if len(args) < 1 && opts.profile == "" {
+				return fmt.Errorf("requires a mode [%s] or --profile", strings.Join(config.AvailableModes(), ", "))
+			}
+			if len(args) == 1 && opts.profile != "" {
+				return fmt.Errorf("a mode cannot be used with --profile")
 			}
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			// Safe to access args[0] because it was validated in Args
-			err := executeMode(fs, cmd, args[0])
+			var mode string
+			if len(args) == 1 {
+				mode = args[0]
+			}
+			err := executeMode(fs, cmd, mode, opts)
 			out.MaybeDieErr(err)
 		},
 	}
@@ -47,25 +94,105 @@ func NewModeCommand(fs afero.Fs) *cobra.Command {
 		"Redpanda config file, if not set the file will be searched for"+
 			" in the default locations.",
 	)
+	command.Flags().StringVar(&opts.profile, "profile", "", "YAML file defining a custom mode profile (see --help)")
+	command.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the changes without writing them")
+	command.Flags().StringVar(&opts.format, "format", "text", "Output format of the changes (text, json)")
 	return command
 }
 
-func executeMode(fs afero.Fs, cmd *cobra.Command, mode string) error {
+func executeMode(fs afero.Fs, cmd *cobra.Command, mode string, opts modeOptions) error {
+	if opts.format != "" && opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("unsupported format %q, must be text or json", opts.format)
+	}
 	p := config.ParamsFromCommand(cmd)
 	cfg, err := p.Load(fs)
 	if err != nil {
 		return fmt.Errorf("unable to load config: %v", err)
 	}
 	cfg = cfg.FileOrDefaults() // we modify fields in the raw file without writing env / flag overrides
-	cfg, err = config.SetMode(mode, cfg)
+
+	before, err := config.SnapshotMode(cfg)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to read the current configuration: %v", err)
+	}
+	if opts.profile != "" {
+		profile, err := config.LoadModeProfile(fs, opts.profile)
+		if err != nil {
+			return err
+		}
+		mode = profile.Name
+		cfg, err = profile.Apply(cfg)
+		if err != nil {
+			return fmt.Errorf("unable to apply mode profile %q: %v", profile.Name, err)
+		}
+	} else {
+		cfg, err = config.SetMode(mode, cfg)
+		if err != nil {
+			return err
+		}
 	}
+	after, err := config.SnapshotMode(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to read the new configuration: %v", err)
+	}
+	changes := config.DiffMode(before, after)
 
-	fmt.Printf("Writing %q mode defaults to %q\n", mode, cfg.FileLocation())
+	if err := printModeChanges(os.Stdout, mode, cfg.FileLocation(), changes, opts); err != nil {
+		return err
+	}
+	if opts.dryRun {
+		return nil
+	}
+	if opts.format != "json" {
+		fmt.Printf("Writing %q mode defaults to %q\n", mode, cfg.FileLocation())
+	}
 	err = cfg.Write(fs)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func printModeChanges(
+	w io.Writer, mode, file string, changes []config.ModeChange, opts modeOptions,
+) error {
+	if opts.format == "json" {
+		if changes == nil {
+			changes = []config.ModeChange{}
+		}
+		return json.NewEncoder(w).Encode(struct {
+			Mode    string              `json:"mode"`
+			File    string              `json:"file"`
+			DryRun  bool                `json:"dry_run"`
+			Changes []config.ModeChange `json:"changes"`
+		}{mode, file, opts.dryRun, changes})
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "%q is already in %q mode, nothing to change.\n", file, mode)
+		return nil
+	}
+	tw := out.NewTableTo(w, "key", "current", "new")
+	for _, c := range changes {
+		key := c.Key
+		if c.Tuner {
+			key += " (tuner)"
+		}
+		tw.Print(key, modeValue(c.Old), modeValue(c.New))
+	}
+	return tw.Flush()
+}
+
+func modeValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
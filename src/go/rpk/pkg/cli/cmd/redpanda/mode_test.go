@@ -140,7 +140,7 @@ func TestModeCommand(t *testing.T) {
 			require.NoError(t, err)
 			cmd := NewModeCommand(fs)
 			cmd.SetArgs(tt.args)
-			err = executeMode(fs, cmd, tt.args[0])
+			err = executeMode(fs, cmd, tt.args[0], modeOptions{})
 			if tt.expErr && err != nil {
 				return
 			}
@@ -153,3 +153,44 @@ func TestModeCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestModeDryRun(t *testing.T) {
+	configPath := "/etc/redpanda/redpanda.yaml"
+	fs := afero.NewMemMapFs()
+	bs, err := yaml.Marshal(fillRpkConfig(configPath, config.ModeDev))
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, configPath, bs, 0o644))
+
+	cmd := NewModeCommand(fs)
+	err = executeMode(fs, cmd, "prod", modeOptions{dryRun: true})
+	require.NoError(t, err)
+
+	conf, err := new(config.Params).Load(fs)
+	require.NoError(t, err)
+	require.Exactly(t, fillRpkConfig(configPath, config.ModeDev), conf.File())
+}
+
+func TestModeProfile(t *testing.T) {
+	configPath := "/etc/redpanda/redpanda.yaml"
+	profilePath := "/etc/redpanda/hardened.yaml"
+	fs := afero.NewMemMapFs()
+	bs, err := yaml.Marshal(fillRpkConfig(configPath, config.ModeDev))
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, configPath, bs, 0o644))
+	profile := `name: hardened
+base: prod
+set:
+  rpk.tune_fstrim: true
+`
+	require.NoError(t, afero.WriteFile(fs, profilePath, []byte(profile), 0o644))
+
+	cmd := NewModeCommand(fs)
+	err = executeMode(fs, cmd, "", modeOptions{profile: profilePath})
+	require.NoError(t, err)
+
+	conf, err := new(config.Params).Load(fs)
+	require.NoError(t, err)
+	exp := fillRpkConfig(configPath, config.ModeProd)
+	exp.Rpk.TuneFstrim = true
+	require.Exactly(t, exp, conf.File())
+}
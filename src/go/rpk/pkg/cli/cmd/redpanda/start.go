@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -78,6 +79,7 @@ const (
 	unsafeBypassFsyncFlag = "unsafe-bypass-fsync"
 	nodeIDFlag            = "node-id"
 	setConfigFlag         = "set"
+	superviseFlag         = "supervise"
 	modeFlag              = "mode"
 	checkFlag             = "check"
 )
@@ -129,6 +131,13 @@ func NewStartCommand(fs afero.Fs, launcher rp.Launcher) *cobra.Command {
 		timeout         time.Duration
 		wellKnownIo     string
 		mode            string
+
+		supervise           bool
+		superviseLogDir     string
+		superviseLogRotate  string
+		superviseLogKeep    int
+		superviseMaxBackoff time.Duration
+		superviseStatusAddr string
 	)
 	sFlags := seastarFlags{}
 
@@ -363,6 +372,18 @@ func NewStartCommand(fs afero.Fs, launcher rp.Launcher) *cobra.Command {
 			}
 			rpArgs.ExtraArgs = args
 			fmt.Println(common.FeedbackMsg)
+			if supervise {
+				sv, ok := launcher.(rp.Supervisor)
+				if !ok {
+					return errors.New("--supervise is not supported on this platform")
+				}
+				svCfg, err := superviseConfig(cfg, superviseLogDir, superviseLogRotate, superviseLogKeep, superviseMaxBackoff, superviseStatusAddr)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Starting redpanda under supervision, logging to %s...\n", filepath.Join(svCfg.LogDir, "redpanda.log"))
+				return sv.Supervise(installDirectory, rpArgs, svCfg)
+			}
 			fmt.Println("Starting redpanda...")
 			return launcher.Start(installDirectory, rpArgs)
 		},
@@ -494,12 +515,62 @@ func NewStartCommand(fs afero.Fs, launcher rp.Launcher) *cobra.Command {
 			"fraction and a unit suffix, such as '300ms', '1.5s' or '2h45m'. "+
 			"Valid time units are 'ns', 'us' (or 'µs'), 'ms', 's', 'm', 'h'",
 	)
+	command.Flags().BoolVar(&supervise, superviseFlag, false,
+		"Run redpanda as a child process, capturing its output to rotating log files and restarting it if it crashes")
+	command.Flags().StringVar(&superviseLogDir, "supervise-log-dir", "",
+		"Directory of the redpanda.log file when supervising (default: the \"logs\" directory next to the data directory)")
+	command.Flags().StringVar(&superviseLogRotate, "supervise-log-rotate-size", "100MiB",
+		"Size at which redpanda.log is rotated when supervising; 0 disables rotation")
+	command.Flags().IntVar(&superviseLogKeep, "supervise-log-keep", 5,
+		"Number of rotated log files to keep when supervising")
+	command.Flags().DurationVar(&superviseMaxBackoff, "supervise-max-backoff", time.Minute,
+		"Maximum time to wait before restarting redpanda after consecutive crashes")
+	command.Flags().StringVar(&superviseStatusAddr, "supervise-status-addr", "127.0.0.1:9650",
+		"Address of the local supervisor status endpoint (GET /status); empty disables it")
 	for flag := range flagsMap(sFlags) {
 		command.Flag(flag).Hidden = true
 	}
 	return command
 }
 
+// superviseConfig returns the supervisor configuration from the --supervise
+// flags.
+func superviseConfig(
+	cfg *config.Config,
+	logDir, logRotateSize string,
+	logKeep int,
+	maxBackoff time.Duration,
+	statusAddr string,
+) (rp.SuperviseConfig, error) {
+	if logDir == "" {
+		logDir = filepath.Join(filepath.Dir(filepath.Clean(cfg.Redpanda.Directory)), "logs")
+	}
+	var rotateSize int64
+	if logRotateSize != "0" {
+		var err error
+		rotateSize, err = units.RAMInBytes(logRotateSize)
+		if err != nil || rotateSize < 0 {
+			return rp.SuperviseConfig{}, fmt.Errorf("invalid --supervise-log-rotate-size %q", logRotateSize)
+		}
+	}
+	if logKeep < 0 {
+		return rp.SuperviseConfig{}, fmt.Errorf("invalid --supervise-log-keep %d, must be positive", logKeep)
+	}
+	const minBackoff = time.Second
+	if maxBackoff < minBackoff {
+		return rp.SuperviseConfig{}, fmt.Errorf("invalid --supervise-max-backoff %s, must be at least %s", maxBackoff, minBackoff)
+	}
+	return rp.SuperviseConfig{
+		LogDir:        logDir,
+		LogRotateSize: rotateSize,
+		LogRotateKeep: logKeep,
+		MinBackoff:    minBackoff,
+		MaxBackoff:    maxBackoff,
+		StableAfter:   5 * time.Minute,
+		StatusAddr:    statusAddr,
+	}, nil
+}
+
 func flagsMap(sFlags seastarFlags) map[string]interface{} {
 	return map[string]interface{}{
 		memoryFlag:            sFlags.memory,
@@ -80,7 +80,7 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if !tunerParamsEmpty(&tunerParams) && configFile != "" {
-				out.Die("use either tuner params or redpanda config file")
+				out.DieUsage("use either tuner params or redpanda config file")
 			}
 			var tuners []string
 			p := config.ParamsFromCommand(cmd)
@@ -10,8 +10,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,10 +22,13 @@ import (
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/acl"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cloud"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/container"
@@ -30,14 +36,21 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/generate"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/group"
 	plugincmd "github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/plugin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/security"
+	selfupdatecmd "github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/selfupdate"
+	telemetrycmd "github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/telemetry"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/topic"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/version"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/wasm"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/plugin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/selfupdate"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/telemetry"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/term"
 )
 
@@ -72,15 +85,36 @@ func Execute() {
 	}
 	root.PersistentFlags().BoolVarP(&verbose, config.FlagVerbose,
 		"v", false, "Enable verbose logging (default: false)")
+	root.PersistentFlags().Var(&errorFormatFlag{root}, "error-format",
+		`Format of the errors printed to stderr, "text" or "json"; $RPK_ERROR_FORMAT sets the default`)
+	if format, ok := os.LookupEnv("RPK_ERROR_FORMAT"); ok {
+		if err := root.PersistentFlags().Set("error-format", format); err != nil {
+			out.DieUsage("invalid $RPK_ERROR_FORMAT: %v", err)
+		}
+	}
+	out.RegisterExitCoder(adminExitCode)
+	// Errors are printed below, in the error format, and flag and argument
+	// errors exit with ExitUsage.
+	root.SilenceErrors = true
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return out.UsageError(err)
+	})
+	root.PersistentPreRun = func(cmd *cobra.Command, _ []string) {
+		recordUsage(fs, cmd)
+	}
 
 	root.AddCommand(
 		acl.NewCommand(fs),
+		cloud.NewCommand(fs),
 		cluster.NewCommand(fs),
 		container.NewCommand(),
 		debug.NewCommand(fs),
 		generate.NewCommand(fs),
 		group.NewCommand(fs),
 		plugincmd.NewCommand(fs),
+		security.NewCommand(fs),
+		selfupdatecmd.NewCommand(fs),
+		telemetrycmd.NewCommand(fs),
 		topic.NewCommand(fs),
 		version.NewCommand(),
 		wasm.NewCommand(fs),
@@ -131,7 +165,11 @@ func Execute() {
 		c.Flags().BoolP("help", "h", false, "Help for "+c.Name())
 	})
 
-	err := root.Execute()
+	cmd, err := root.ExecuteC()
+	if err == nil {
+		sendUsage(fs)
+		checkForUpdate(fs, cmd)
+	}
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "check":
@@ -141,7 +179,129 @@ func Execute() {
 		}
 	}
 	if err != nil {
-		os.Exit(1)
+		// Commands with Run cannot fail, they exit by themselves: the
+		// error is then from the command line, e.g. an invalid argument.
+		code := out.ExitCode(err)
+		if cmd == nil || cmd.RunE == nil {
+			code = out.ExitUsage
+		}
+		out.DieCode(code, "Error: %v", err)
+	}
+}
+
+// errorFormatFlag is the --error-format flag. The format is set as soon as
+// the flag is parsed so that it applies to the errors of the flags that
+// follow.
+type errorFormatFlag struct{ root *cobra.Command }
+
+func (*errorFormatFlag) String() string { return out.ErrorFormat() }
+func (*errorFormatFlag) Type() string   { return "string" }
+
+func (f *errorFormatFlag) Set(format string) error {
+	if err := out.SetErrorFormat(format); err != nil {
+		return err
+	}
+	// The usage is not json.
+	f.root.SilenceUsage = format == out.ErrorFormatJSON
+	return nil
+}
+
+// adminExitCode returns the exit code of errors of the admin API client.
+func adminExitCode(err error) (int, bool) {
+	switch {
+	case admin.IsNotFound(err):
+		return out.ExitNotFound, true
+	case errors.Is(err, admin.ErrClient):
+		return out.ExitUsage, true
+	case errors.Is(err, admin.ErrServer):
+		return out.ExitServer, true
+	case errors.Is(err, admin.ErrTimeout):
+		return out.ExitTimeout, true
+	case errors.Is(err, admin.ErrConnection):
+		return out.ExitConnection, true
+	}
+	return 0, false
+}
+
+// recordUsage spools the usage event of cmd if the user opted in to
+// telemetry. Failures are only logged: telemetry must never get in the way.
+func recordUsage(fs afero.Fs, cmd *cobra.Command) {
+	path := cmd.CommandPath()
+	if strings.HasPrefix(path, "rpk telemetry") {
+		return
+	}
+	t, err := telemetry.New(fs)
+	if err != nil {
+		log.Debugf("unable to record usage: %v", err)
+		return
+	}
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) { flags = append(flags, f.Name) })
+	err = t.Record(telemetry.Event{
+		Time:    time.Now().UTC(),
+		Command: path,
+		Flags:   flags,
+		Version: version.Pretty(),
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+	})
+	if err != nil {
+		log.Debugf("unable to record usage: %v", err)
+	}
+}
+
+// sendUsage sends the spooled usage events if they are due, giving up quickly
+// if the telemetry endpoint is unreachable.
+func sendUsage(fs afero.Fs) {
+	const timeout = 2 * time.Second
+	t, err := telemetry.New(fs)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := t.MaybeSend(ctx, &http.Client{Timeout: timeout}); err != nil {
+		log.Debugf("unable to send usage: %v", err)
+	}
+}
+
+// checkForUpdate prints a notice if a new release of rpk is available, if the
+// user enabled the startup check and the last check is old enough. Failures
+// are only logged: the check must never get in the way.
+func checkForUpdate(fs afero.Fs, cmd *cobra.Command) {
+	const timeout = 2 * time.Second
+	current := version.Version()
+	if current == "" || strings.HasPrefix(cmd.CommandPath(), "rpk self-update") {
+		return
+	}
+	path, err := selfupdate.SettingsPath()
+	if err != nil {
+		return
+	}
+	s, err := selfupdate.LoadSettings(fs, path)
+	if err != nil || !s.CheckDue(time.Now()) {
+		return
+	}
+	u, err := selfupdate.New()
+	if err != nil {
+		log.Debugf("unable to check for a new release: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	m, err := u.FetchManifest(ctx, s.Channel)
+	if err != nil {
+		log.Debugf("unable to check for a new release: %v", err)
+		return
+	}
+	s.LastCheck, s.Latest = time.Now(), m.Release
+	if err := selfupdate.SaveSettings(fs, path, s); err != nil {
+		log.Debugf("unable to save the last release check: %v", err)
+	}
+	if cmp, err := selfupdate.CompareVersions(m.Release, current); err != nil {
+		log.Debugf("unable to compare release %s to the current version: %v", m.Release, err)
+	} else if cmp > 0 {
+		fmt.Fprintf(os.Stderr, "\nA new %s release of rpk is available: %s (current: %s). Run 'rpk self-update' to update.\n", s.Channel, m.Release, current)
 	}
 }
 
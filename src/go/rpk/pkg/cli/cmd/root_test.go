@@ -3,10 +3,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +23,29 @@ func TestLogOutput(t *testing.T) {
 	require.Exactly(t, os.Stdout, logrus.StandardLogger().Out)
 }
 
+func TestAdminExitCode(t *testing.T) {
+	status := func(code int) error {
+		return &admin.HTTPResponseError{Response: &http.Response{StatusCode: code}}
+	}
+	for _, test := range []struct {
+		name string
+		err  error
+		exp  int
+		ok   bool
+	}{
+		{"404", status(http.StatusNotFound), out.ExitNotFound, true},
+		{"400", status(http.StatusBadRequest), out.ExitUsage, true},
+		{"503", status(http.StatusServiceUnavailable), out.ExitServer, true},
+		{"other", errors.New("boom"), 0, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			code, ok := adminExitCode(fmt.Errorf("request failed: %w", test.err))
+			require.Equal(t, test.exp, code)
+			require.Equal(t, test.ok, ok)
+		})
+	}
+}
+
 func TestTryExecPlugin(t *testing.T) {
 	fs := func() testPluginHandler {
 		return testPluginHandler{
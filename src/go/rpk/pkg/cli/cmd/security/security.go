@@ -0,0 +1,34 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package security contains commands to help securing Redpanda clusters.
+package security
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "security",
+		Short: "Helpers to secure Redpanda clusters",
+	}
+	cmd.AddCommand(newTLSCommand(fs))
+	return cmd
+}
+
+func newTLSCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tls",
+		Short: "Manage TLS certificates",
+	}
+	cmd.AddCommand(newTLSGenerateCommand(fs))
+	return cmd
+}
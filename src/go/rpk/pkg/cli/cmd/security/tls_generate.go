@@ -0,0 +1,360 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	certFileMode = 0o644
+	keyFileMode  = 0o600
+)
+
+func newTLSGenerateCommand(fs afero.Fs) *cobra.Command {
+	var (
+		hosts  []string
+		dir    string
+		client string
+		days   int
+		force  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a local CA, broker and client certificates",
+		Long: `Generate a local CA, broker and client certificates.
+
+This command creates a self signed certificate authority, one certificate per
+host in --hosts signed by it, and a client certificate for rpk and other
+clients. The certificates are written to --dir along with their private keys,
+which are only readable by the current user:
+
+    ca.crt, ca.key          the certificate authority
+    <host>.crt, <host>.key  the certificate of each broker
+    <client>.crt, <client>.key  the client certificate
+
+Each host is added as a subject alternative name of its certificate, as an IP
+address if it is one and as a DNS name otherwise. Broker certificates can be
+used both as server and client certificates, so that brokers can use mTLS
+between themselves.
+
+Once generated, the redpanda.yaml TLS sections to use them are printed for
+each broker. If a listener has a name, add it to the matching TLS section.
+
+The CA is meant for development and test clusters: its key is stored next to
+the certificates, unencrypted.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			if days <= 0 {
+				out.DieUsage("--days must be positive")
+			}
+			abs, err := filepath.Abs(dir)
+			out.MaybeDie(err, "unable to resolve %q: %v", dir, err)
+
+			gen, err := generateTLS(fs, tlsRequest{
+				dir:      abs,
+				hosts:    hosts,
+				client:   client,
+				validity: time.Duration(days) * 24 * time.Hour,
+				force:    force,
+			})
+			out.MaybeDieErr(err)
+
+			stanzas, err := tlsStanzas(gen)
+			out.MaybeDie(err, "unable to render the configuration: %v", err)
+			fmt.Printf("Wrote the CA, %d broker certificate(s) and the client certificate to %s.\n\n", len(gen.brokers), abs)
+			fmt.Print(stanzas)
+		},
+	}
+	cmd.Flags().StringSliceVar(&hosts, "hosts", nil, "Comma separated list of broker hostnames or IP addresses to generate certificates for")
+	cmd.Flags().StringVarP(&dir, "dir", "d", "certs", "Directory to write the certificates and keys to")
+	cmd.Flags().StringVar(&client, "client-name", "client", "Common name and file name of the client certificate")
+	cmd.Flags().IntVar(&days, "days", 365, "Number of days the certificates are valid for")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing certificates and keys")
+	cobra.MarkFlagRequired(cmd.Flags(), "hosts")
+	return cmd
+}
+
+type tlsRequest struct {
+	dir      string
+	hosts    []string
+	client   string
+	validity time.Duration
+	force    bool
+	now      time.Time // for tests; time.Now() if zero
+}
+
+// certPaths are the paths of a certificate and its key.
+type certPaths struct {
+	cert, key string
+}
+
+type generatedTLS struct {
+	ca      certPaths
+	brokers map[string]certPaths // keyed by host, in the order of hosts
+	hosts   []string
+	client  certPaths
+}
+
+// generateTLS creates a CA and the broker and client certificates signed by
+// it. Nothing is written if any of the files already exists, unless force is
+// set.
+func generateTLS(fs afero.Fs, req tlsRequest) (*generatedTLS, error) {
+	if len(req.hosts) == 0 {
+		return nil, errors.New("at least one host is required")
+	}
+	if req.client == "" {
+		return nil, errors.New("the client name cannot be empty")
+	}
+	now := req.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	paths := func(name string) certPaths {
+		return certPaths{
+			cert: filepath.Join(req.dir, name+".crt"),
+			key:  filepath.Join(req.dir, name+".key"),
+		}
+	}
+	gen := &generatedTLS{
+		ca:      paths("ca"),
+		brokers: make(map[string]certPaths, len(req.hosts)),
+		client:  paths(fileName(req.client)),
+	}
+	files := []certPaths{gen.ca, gen.client}
+	for _, h := range req.hosts {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			return nil, errors.New("hosts cannot be empty")
+		}
+		if _, ok := gen.brokers[h]; ok {
+			return nil, fmt.Errorf("host %q is repeated", h)
+		}
+		p := paths(fileName(h))
+		if p == gen.ca || p == gen.client {
+			return nil, fmt.Errorf("host %q conflicts with the CA or client certificate file names", h)
+		}
+		gen.brokers[h] = p
+		gen.hosts = append(gen.hosts, h)
+		files = append(files, p)
+	}
+	if !req.force {
+		for _, p := range files {
+			for _, f := range []string{p.cert, p.key} {
+				exists, err := afero.Exists(fs, f)
+				if err != nil {
+					return nil, fmt.Errorf("unable to check %q: %v", f, err)
+				}
+				if exists {
+					return nil, fmt.Errorf("%q already exists, use --force to overwrite it", f)
+				}
+			}
+		}
+	}
+	if err := fs.MkdirAll(req.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create %q: %v", req.dir, err)
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the CA key: %v", err)
+	}
+	caTmpl, err := certTemplate("Redpanda CA", now, req.validity)
+	if err != nil {
+		return nil, err
+	}
+	caTmpl.IsCA = true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	caTmpl.MaxPathLenZero = true
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the CA certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the CA certificate: %v", err)
+	}
+	if err := writeCert(fs, gen.ca, caDER, caKey); err != nil {
+		return nil, err
+	}
+
+	sign := func(p certPaths, cn string, tweak func(*x509.Certificate)) error {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return fmt.Errorf("unable to generate the key of %q: %v", cn, err)
+		}
+		tmpl, err := certTemplate(cn, now, req.validity)
+		if err != nil {
+			return err
+		}
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+		tweak(tmpl)
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			return fmt.Errorf("unable to create the certificate of %q: %v", cn, err)
+		}
+		return writeCert(fs, p, der, key)
+	}
+
+	for _, h := range gen.hosts {
+		host := h
+		err := sign(gen.brokers[host], host, func(c *x509.Certificate) {
+			c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+			if ip := net.ParseIP(host); ip != nil {
+				c.IPAddresses = []net.IP{ip}
+			} else {
+				c.DNSNames = []string{host}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = sign(gen.client, req.client, func(c *x509.Certificate) {
+		c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+func certTemplate(cn string, now time.Time, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate a serial number: %v", err)
+	}
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn, Organization: []string{"Redpanda"}},
+		NotBefore:             now.Add(-time.Hour), // tolerate clock skew
+		NotAfter:              now.Add(validity),
+		BasicConstraintsValid: true,
+	}, nil
+}
+
+func writeCert(fs afero.Fs, p certPaths, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("unable to encode the key %q: %v", p.key, err)
+	}
+	for _, f := range []struct {
+		path  string
+		typ   string
+		bytes []byte
+		mode  os.FileMode
+	}{
+		{p.key, "PRIVATE KEY", keyDER, keyFileMode},
+		{p.cert, "CERTIFICATE", der, certFileMode},
+	} {
+		raw := pem.EncodeToMemory(&pem.Block{Type: f.typ, Bytes: f.bytes})
+		if err := afero.WriteFile(fs, f.path, raw, f.mode); err != nil {
+			return fmt.Errorf("unable to write %q: %v", f.path, err)
+		}
+		// WriteFile does not change the mode of existing files.
+		if err := fs.Chmod(f.path, f.mode); err != nil {
+			return fmt.Errorf("unable to set the permissions of %q: %v", f.path, err)
+		}
+	}
+	return nil
+}
+
+// fileName turns a host into a file name, replacing the characters that
+// cannot be used in one, e.g. the colons of IPv6 addresses.
+func fileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '%':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// tlsStanzas renders the redpanda.yaml sections to use the generated
+// certificates on each broker.
+func tlsStanzas(gen *generatedTLS) (string, error) {
+	type redpandaTLS struct {
+		KafkaAPITLS  []config.ServerTLS `yaml:"kafka_api_tls"`
+		AdminAPITLS  []config.ServerTLS `yaml:"admin_api_tls"`
+		RPCServerTLS config.ServerTLS   `yaml:"rpc_server_tls"`
+	}
+	type rpkTLS struct {
+		KafkaAPI struct {
+			TLS config.TLS `yaml:"tls"`
+		} `yaml:"kafka_api"`
+		AdminAPI struct {
+			TLS config.TLS `yaml:"tls"`
+		} `yaml:"admin_api"`
+	}
+	type stanza struct {
+		Redpanda redpandaTLS `yaml:"redpanda"`
+		Rpk      rpkTLS      `yaml:"rpk"`
+	}
+
+	var sb strings.Builder
+	for i, h := range gen.hosts {
+		p := gen.brokers[h]
+		server := config.ServerTLS{
+			Enabled:           true,
+			CertFile:          p.cert,
+			KeyFile:           p.key,
+			TruststoreFile:    gen.ca.cert,
+			RequireClientAuth: true,
+		}
+		var s stanza
+		s.Redpanda = redpandaTLS{
+			KafkaAPITLS:  []config.ServerTLS{server},
+			AdminAPITLS:  []config.ServerTLS{server},
+			RPCServerTLS: server,
+		}
+		client := config.TLS{
+			CertFile:       gen.client.cert,
+			KeyFile:        gen.client.key,
+			TruststoreFile: gen.ca.cert,
+		}
+		s.Rpk.KafkaAPI.TLS = client
+		s.Rpk.AdminAPI.TLS = client
+
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "# redpanda.yaml of %s\n", h)
+		enc := yaml.NewEncoder(&sb)
+		enc.SetIndent(2)
+		if err := enc.Encode(s); err != nil {
+			return "", err
+		}
+		if err := enc.Close(); err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package security
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func readCert(t *testing.T, fs afero.Fs, path string) *x509.Certificate {
+	raw, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestGenerateTLS(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	req := tlsRequest{
+		dir:      "/certs",
+		hosts:    []string{"broker-0.local", "10.0.0.1", "::1"},
+		client:   "admin",
+		validity: 24 * time.Hour,
+	}
+	gen, err := generateTLS(fs, req)
+	require.NoError(t, err)
+
+	ca := readCert(t, fs, "/certs/ca.crt")
+	require.True(t, ca.IsCA)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	for host, file := range map[string]string{
+		"broker-0.local": "/certs/broker-0.local.crt",
+		"10.0.0.1":       "/certs/10.0.0.1.crt",
+		"::1":            "/certs/__1.crt",
+	} {
+		require.Equal(t, file, gen.brokers[host].cert)
+		cert := readCert(t, fs, file)
+		_, err := cert.Verify(x509.VerifyOptions{
+			DNSName: host,
+			Roots:   roots,
+		})
+		require.NoError(t, err, host)
+	}
+
+	client := readCert(t, fs, "/certs/admin.crt")
+	_, err = client.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+
+	for _, f := range []string{"/certs/ca.key", "/certs/admin.key", "/certs/10.0.0.1.key"} {
+		info, err := fs.Stat(f)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(keyFileMode), info.Mode().Perm(), f)
+	}
+
+	// Existing files are not overwritten without force.
+	_, err = generateTLS(fs, req)
+	require.Error(t, err)
+	req.force = true
+	_, err = generateTLS(fs, req)
+	require.NoError(t, err)
+
+	stanzas, err := tlsStanzas(gen)
+	require.NoError(t, err)
+	require.Contains(t, stanzas, "# redpanda.yaml of 10.0.0.1\n")
+	require.Contains(t, stanzas, "cert_file: /certs/10.0.0.1.crt")
+	require.Contains(t, stanzas, "truststore_file: /certs/ca.crt")
+	require.Contains(t, stanzas, "cert_file: /certs/admin.crt")
+}
+
+func TestGenerateTLSInvalid(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, req := range []tlsRequest{
+		{dir: "/certs", client: "client"},
+		{dir: "/certs", hosts: []string{"a", "a"}, client: "client"},
+		{dir: "/certs", hosts: []string{"ca"}, client: "client"},
+		{dir: "/certs", hosts: []string{"a"}},
+	} {
+		_, err := generateTLS(fs, req)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package selfupdate contains the self-update command.
+package selfupdate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/version"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/selfupdate"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	var (
+		channel       string
+		check         bool
+		force         bool
+		skipSignature bool
+		enableCheck   bool
+		disableCheck  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update rpk to the latest release",
+		Long: `Update rpk to the latest release.
+
+This command is for rpk binaries installed standalone. If you installed rpk
+with a package manager (apt, yum, brew...), update it with your package manager
+instead.
+
+The latest release of the --channel (stable or nightly) is looked up in a
+release manifest, which must be signed with the release key rpk was built with.
+The rpk binary for your os / arch is then downloaded, its sha256 sum is checked
+against the manifest, and it atomically replaces the running rpk binary.
+
+Use --check to only print whether a new release is available.
+
+rpk is only replaced by a newer release: if the latest release of the channel
+is the current version or older, such as when switching from nightly to stable,
+nothing is installed unless you use --force.
+
+rpk can also check for a new release when it starts, at most once a day, and
+print a notice if there is one. This check is disabled unless you enable it
+with --enable-startup-check; the --channel used when enabling the check is the
+channel that is checked.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			settingsPath, err := selfupdate.SettingsPath()
+			out.MaybeDieErr(err)
+			settings, err := selfupdate.LoadSettings(fs, settingsPath)
+			out.MaybeDieErr(err)
+			if !cmd.Flags().Changed("channel") {
+				channel = settings.Channel
+			}
+
+			if enableCheck || disableCheck {
+				if enableCheck && disableCheck {
+					out.DieUsage("--enable-startup-check and --disable-startup-check cannot be used together")
+				}
+				settings.CheckEnabled = enableCheck
+				settings.Channel = channel
+				err = selfupdate.SaveSettings(fs, settingsPath, settings)
+				out.MaybeDie(err, "unable to save self-update settings: %v", err)
+				if enableCheck {
+					fmt.Printf("rpk will check for new %s releases when it starts, at most once a day.\n", channel)
+				} else {
+					fmt.Println("rpk will no longer check for new releases when it starts.")
+				}
+				return
+			}
+
+			u, err := selfupdate.New()
+			out.MaybeDieErr(err)
+			u.SkipSignature = skipSignature
+
+			m, err := u.FetchManifest(cmd.Context(), channel)
+			if errors.Is(err, selfupdate.ErrNoPublicKey) {
+				out.Die("%v; update rpk with your package manager, or use --skip-signature if you trust %s", err, u.BaseURL)
+			}
+			out.MaybeDie(err, "unable to fetch the latest %s release: %v", channel, err)
+
+			current := version.Version()
+			newer := true // development builds can always be updated
+			if current != "" {
+				cmp, err := selfupdate.CompareVersions(m.Release, current)
+				switch {
+				case err != nil && !force:
+					out.Die("unable to compare the latest %s release %s to rpk %s: %v; use --force to install it anyway", channel, m.Release, current, err)
+				case cmp == 0 && !force:
+					out.Exit("rpk %s is already the latest %s release.", current, channel)
+				case cmp < 0 && !force:
+					out.Exit("The latest %s release %s is older than rpk %s; use --force to downgrade.", channel, m.Release, current)
+				}
+				newer = err == nil && cmp > 0
+			}
+			if check {
+				if newer {
+					fmt.Printf("A new %s release of rpk is available: %s (current: %s).\n", channel, m.Release, describe(current))
+					fmt.Println("Run 'rpk self-update' to update.")
+				} else {
+					fmt.Printf("The latest %s release of rpk is %s (current: %s).\n", channel, m.Release, describe(current))
+				}
+				return
+			}
+
+			exe, err := os.Executable()
+			out.MaybeDie(err, "unable to find the rpk binary: %v", err)
+			exe, err = filepath.EvalSymlinks(exe)
+			out.MaybeDie(err, "unable to resolve the rpk binary path: %v", err)
+
+			fmt.Printf("Downloading and validating rpk %s...\n", m.Release)
+			body, err := u.DownloadForUser(cmd.Context(), m)
+			out.MaybeDieErr(err)
+
+			err = selfupdate.Replace(fs, exe, body)
+			out.MaybeDieErr(err)
+			fmt.Printf("Updated %s from %s to %s.\n", exe, describe(current), m.Release)
+		},
+	}
+
+	cmd.Flags().StringVar(&channel, "channel", selfupdate.ChannelStable, fmt.Sprintf("Release channel to update from (%s)", strings.Join(selfupdate.Channels(), ", ")))
+	cmd.Flags().BoolVar(&check, "check", false, "Only print whether a new release is available")
+	cmd.Flags().BoolVar(&force, "force", false, "Install the latest release even if it is the current version or older")
+	cmd.Flags().BoolVar(&skipSignature, "skip-signature", false, "Do not verify the signature of the release manifest (insecure)")
+	cmd.Flags().BoolVar(&enableCheck, "enable-startup-check", false, "Check for a new release of --channel when rpk starts, at most once a day")
+	cmd.Flags().BoolVar(&disableCheck, "disable-startup-check", false, "Disable the startup check for new releases")
+	return cmd
+}
+
+// describe returns the version, or a placeholder for development builds.
+func describe(version string) string {
+	if version == "" {
+		return "development build"
+	}
+	return version
+}
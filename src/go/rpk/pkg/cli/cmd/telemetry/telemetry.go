@@ -0,0 +1,141 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package telemetry contains the commands to opt in to and out of usage
+// telemetry, and to see what is sent.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/telemetry"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage the usage telemetry of rpk",
+		Long: `Manage the usage telemetry of rpk.
+
+Telemetry is disabled unless you enable it. Once enabled, rpk records which
+commands you run and the names of the flags you set; never arguments, flag
+values, hostnames, nor any data of your clusters. Events are spooled in a
+local file and sent at most once a day.
+
+Use 'rpk telemetry preview' to see exactly what would be sent.
+`,
+	}
+	cmd.AddCommand(
+		newStatusCommand(fs),
+		newEnableCommand(fs),
+		newDisableCommand(fs),
+		newPreviewCommand(fs),
+	)
+	return cmd
+}
+
+func newStatusCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Print whether telemetry is enabled and what is spooled",
+		Args:  cobra.NoArgs,
+		Run: func(*cobra.Command, []string) {
+			t, err := telemetry.New(fs)
+			out.MaybeDieErr(err)
+			s, err := t.Settings()
+			out.MaybeDieErr(err)
+			events, err := t.Spooled()
+			out.MaybeDieErr(err)
+
+			tw := out.NewTabWriter()
+			defer tw.Flush()
+			tw.PrintColumn("ENABLED", s.Enabled)
+			if s.ID != "" {
+				tw.PrintColumn("ID", s.ID)
+			}
+			tw.PrintColumn("SPOOL", t.SpoolPath())
+			tw.PrintColumn("SPOOLED-EVENTS", len(events))
+			if !s.LastSent.IsZero() {
+				tw.PrintColumn("LAST-SENT", s.LastSent.Format("2006-01-02 15:04:05 MST"))
+			}
+			tw.PrintColumn("ENDPOINT", t.Endpoint)
+		},
+	}
+}
+
+func newEnableCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable",
+		Short: "Opt in to usage telemetry",
+		Args:  cobra.NoArgs,
+		Run: func(*cobra.Command, []string) {
+			t, err := telemetry.New(fs)
+			out.MaybeDieErr(err)
+			s, err := t.Enable()
+			out.MaybeDie(err, "unable to enable telemetry: %v", err)
+			fmt.Printf("Telemetry enabled with ID %s, thank you!\n", s.ID)
+		},
+	}
+}
+
+func newDisableCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Opt out of usage telemetry and delete the spooled events",
+		Args:  cobra.NoArgs,
+		Run: func(*cobra.Command, []string) {
+			t, err := telemetry.New(fs)
+			out.MaybeDieErr(err)
+			err = t.Disable()
+			out.MaybeDie(err, "unable to disable telemetry: %v", err)
+			fmt.Println("Telemetry disabled.")
+		},
+	}
+}
+
+func newPreviewCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "preview",
+		Short: "Print the payload that would be sent, as JSON",
+		Long: `Print the payload that would be sent, as JSON.
+
+The payload has the following schema:
+
+    {
+      "schema_version": 1,
+      "id": "random ID generated by 'rpk telemetry enable'",
+      "events": [
+        {
+          "time": "2022-08-01T10:00:00Z",
+          "command": "rpk topic create",
+          "flags": ["partitions"],
+          "version": "v22.2.1 (rev 1234abcd)",
+          "os": "linux",
+          "arch": "amd64"
+        }
+      ]
+    }
+`,
+		Args: cobra.NoArgs,
+		Run: func(*cobra.Command, []string) {
+			t, err := telemetry.New(fs)
+			out.MaybeDieErr(err)
+			p, err := t.Preview()
+			out.MaybeDieErr(err)
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			out.MaybeDieErr(enc.Encode(p))
+		},
+	}
+}
@@ -40,7 +40,7 @@ func newAddPartitionsCommand(fs afero.Fs) *cobra.Command {
 			defer adm.Close()
 
 			if num <= 0 {
-				out.Die("No additional partitions requested, exiting!")
+				out.DieUsage("No additional partitions requested, exiting!")
 			}
 
 			resps, err := adm.CreatePartitions(context.Background(), num, topics...)
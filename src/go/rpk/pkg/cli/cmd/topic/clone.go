@@ -0,0 +1,432 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newCloneCommand(fs afero.Fs) *cobra.Command {
+	var (
+		from     string
+		to       string
+		withData bool
+		idle     time.Duration
+		groups   []string
+	)
+	cmd := &cobra.Command{
+		Use:   "clone --from [TOPIC] --to [TOPIC]",
+		Short: "Clone a topic, optionally with its data and group offsets",
+		Long: `Clone a topic, optionally with its data and group offsets.
+
+Kafka has no way to rename a topic. This command creates a new topic with the
+partitions, replication factor and configs set on an existing one, which makes
+renames scriptable: clone the topic, move its producers and consumers to the
+clone, and delete the original with "rpk topic delete".
+
+With --with-data, the records of the topic are copied to the same partitions
+of the clone, with their keys, values, headers and timestamps. The records
+that are copied are those up to the last stable offset of each partition when
+the command starts, so producers should be stopped first. Only committed
+records are copied: aborted transactions, transaction markers, and records of
+transactions that are still open are not. The copy fails if no record is
+received for --idle-timeout.
+
+Offsets are preserved on a best effort basis. A record keeps its offset if all
+the records before it in the partition are copied, which is not the case if
+the start of the partition was deleted by retention, if the topic is
+compacted, or if it has transactions. Use --groups to carry the offsets of
+consumer groups over: each group's offset is translated to the offset of the
+same record in the clone, the translated offsets are committed in one request
+per group, and the group's offsets of the original topic are deleted once all
+of them are committed. The groups must not have members while cloning. Without
+--with-data, the groups start from the beginning of the empty clone.
+
+EXAMPLES
+
+Rename the topic foo to bar, moving the offsets of the group g:
+    rpk topic clone --from foo --to bar --with-data --groups g
+    rpk topic delete foo
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if from == "" || to == "" {
+				out.DieUsage("Both --from and --to must be specified.")
+			}
+			if from == to {
+				out.DieUsage("Cannot clone topic %q to itself.", from)
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			ctx := cmd.Context()
+
+			partitions, replicas, configs, err := describeForClone(ctx, adm, from)
+			out.MaybeDieErr(err)
+
+			// The offsets of the groups are fetched before copying, so
+			// that the copy knows which records to track.
+			groupOffsets := make(map[string]kadm.Offsets)
+			for _, group := range groups {
+				fetched, err := adm.FetchOffsets(ctx, group)
+				if err == nil {
+					err = fetched.Error()
+				}
+				out.MaybeDie(err, "unable to fetch offsets for group %q: %v", group, err)
+				offsets := fetched.Offsets()
+				offsets.KeepFunc(func(o kadm.Offset) bool { return o.Topic == from })
+				groupOffsets[group] = offsets
+			}
+
+			created, err := adm.CreateTopics(ctx, partitions, replicas, configs, to)
+			if err == nil {
+				err = created[to].Err
+			}
+			out.MaybeDie(err, "unable to create topic %q: %v", to, err)
+			fmt.Printf("Created topic %q with %d partitions, %d replicas and %d configs.\n", to, partitions, replicas, len(configs))
+
+			tr := newOffsetTranslator(groupOffsets)
+			if withData {
+				if idle <= 0 {
+					out.DieUsage("invalid --idle-timeout %v: must be positive", idle)
+				}
+				copied, err := cloneData(ctx, fs, p, cfg, adm, from, to, idle, tr)
+				out.MaybeDie(err, "unable to copy the records of %q to %q: %v", from, to, err)
+				fmt.Printf("Copied %d records.\n", copied)
+			}
+
+			if len(groups) > 0 && swapGroupOffsets(ctx, adm, from, to, groupOffsets, tr) {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "Topic to clone")
+	cmd.Flags().StringVar(&to, "to", "", "Name of the clone, which must not exist")
+	cmd.Flags().BoolVar(&withData, "with-data", false, "Copy the records of the topic to the clone")
+	cmd.Flags().DurationVar(&idle, "idle-timeout", 30*time.Second, "With --with-data, fail if no record is received for this long")
+	cmd.Flags().StringSliceVar(&groups, "groups", nil, "Groups whose offsets to move from the topic to the clone (comma-separated)")
+	return cmd
+}
+
+// describeForClone returns the number of partitions, the replication factor
+// and the configs set on topic.
+func describeForClone(
+	ctx context.Context, adm *kadm.Client, topic string,
+) (int32, int16, map[string]*string, error) {
+	details, err := adm.ListTopics(ctx, topic)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("unable to describe topic %q: %w", topic, err)
+	}
+	detail, ok := details[topic]
+	if !ok {
+		return 0, 0, nil, fmt.Errorf("topic %q was not described", topic)
+	}
+	if detail.Err != nil {
+		return 0, 0, nil, fmt.Errorf("unable to describe topic %q: %w", topic, detail.Err)
+	}
+	var replicas int16
+	for _, p := range detail.Partitions {
+		replicas = int16(len(p.Replicas))
+		break
+	}
+
+	rcs, err := adm.DescribeTopicConfigs(ctx, topic)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("unable to describe the configs of topic %q: %w", topic, err)
+	}
+	if len(rcs) != 1 {
+		return 0, 0, nil, fmt.Errorf("config response returned %d resources when we asked for 1", len(rcs))
+	}
+	if err := rcs[0].Err; err != nil {
+		return 0, 0, nil, fmt.Errorf("unable to describe the configs of topic %q: %w", topic, err)
+	}
+	return int32(len(detail.Partitions)), replicas, topicOverrides(rcs[0].Configs), nil
+}
+
+// topicOverrides returns the configs set on a topic itself, which are the
+// ones to set on its clone. The others come from the cluster and apply to the
+// clone as well.
+func topicOverrides(configs []kadm.Config) map[string]*string {
+	overrides := make(map[string]*string)
+	for _, c := range configs {
+		if c.Source != kmsg.ConfigSourceDynamicTopicConfig || c.Value == nil {
+			continue
+		}
+		overrides[c.Key] = c.Value
+	}
+	return overrides
+}
+
+// cloneData copies the committed records of the partitions of from, up to
+// their last stable offset when the copy starts, to the same partitions of
+// to. It fails if no record is received for idle, and returns the number of
+// records copied.
+func cloneData(
+	ctx context.Context,
+	fs afero.Fs,
+	p *config.Params,
+	cfg *config.Config,
+	adm *kadm.Client,
+	from, to string,
+	idle time.Duration,
+	tr *offsetTranslator,
+) (int64, error) {
+	starts, err := adm.ListStartOffsets(ctx, from)
+	if err == nil {
+		err = starts.Error()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to list start offsets: %w", err)
+	}
+	// We consume with read committed, which cannot read past the last
+	// stable offset: records of open transactions would never arrive.
+	ends, err := adm.ListCommittedOffsets(ctx, from)
+	if err == nil {
+		err = ends.Error()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to list last stable offsets: %w", err)
+	}
+
+	consumeAt := make(map[int32]kgo.Offset)
+	remaining := make(map[int32]int64)
+	starts.Each(func(s kadm.ListedOffset) {
+		end, ok := ends[from][s.Partition]
+		if !ok || s.Offset >= end.Offset {
+			return
+		}
+		consumeAt[s.Partition] = kgo.NewOffset().At(s.Offset)
+		remaining[s.Partition] = end.Offset
+	})
+	if len(remaining) == 0 {
+		return 0, nil
+	}
+
+	cl, err := kafka.NewFranzClient(fs, p, cfg,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{from: consumeAt}),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		// Transaction markers are not copied, but they may be the
+		// last record of a partition, which we need to see to know
+		// that the partition is copied.
+		kgo.KeepControlRecords(),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cl.Close()
+
+	var copied int64
+	for len(remaining) > 0 {
+		pollCtx, cancel := context.WithTimeout(ctx, idle)
+		fetches := cl.PollFetches(pollCtx)
+		cancel()
+		if err := ctx.Err(); err != nil {
+			return copied, err
+		}
+		if fetches.RecordIter().Done() && pollCtx.Err() != nil {
+			return copied, fmt.Errorf("no record received for %v with %d partitions left to copy", idle, len(remaining))
+		}
+		if errs := fetches.Errors(); len(errs) > 0 {
+			return copied, errs[0].Err
+		}
+		var batch []*kgo.Record
+		fetches.EachRecord(func(r *kgo.Record) {
+			end, ok := remaining[r.Partition]
+			if !ok || r.Offset >= end {
+				return
+			}
+			tr.observe(r.Partition, r.Offset)
+			if !r.Attrs.IsControl() {
+				batch = append(batch, &kgo.Record{
+					Topic:     to,
+					Partition: r.Partition,
+					Key:       r.Key,
+					Value:     r.Value,
+					Headers:   r.Headers,
+					Timestamp: r.Timestamp,
+				})
+				tr.copied(r.Partition)
+			}
+			if r.Offset+1 >= end {
+				delete(remaining, r.Partition)
+			}
+		})
+		if err := cl.ProduceSync(ctx, batch...).FirstErr(); err != nil {
+			return copied, err
+		}
+		copied += int64(len(batch))
+	}
+	return copied, nil
+}
+
+// swapGroupOffsets commits the translated offsets of each group to the
+// clone, and deletes the group's offsets of the original topic if they were
+// all committed. It prints the results and returns whether any failed.
+func swapGroupOffsets(
+	ctx context.Context,
+	adm *kadm.Client,
+	from, to string,
+	groupOffsets map[string]kadm.Offsets,
+	tr *offsetTranslator,
+) (failed bool) {
+	groups := make([]string, 0, len(groupOffsets))
+	for group := range groupOffsets {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	tw := out.NewTable("group", "partition", "from-offset", "to-offset", "status")
+	defer tw.Flush()
+	for _, group := range groups {
+		offsets := groupOffsets[group]
+		if len(offsets) == 0 {
+			tw.Print(group, "-", "-", "-", fmt.Sprintf("SKIPPED: no offsets committed for %q", from))
+			continue
+		}
+		commitTo := make(kadm.Offsets)
+		offsets.Each(func(o kadm.Offset) {
+			commitTo.Add(kadm.Offset{
+				Topic:       to,
+				Partition:   o.Partition,
+				At:          tr.translate(o.Partition, o.At),
+				LeaderEpoch: -1,
+				Metadata:    o.Metadata,
+			})
+		})
+
+		committed, err := adm.CommitOffsets(ctx, group, commitTo)
+		if err == nil {
+			err = committed.Error()
+		}
+		status := "OK"
+		if err != nil {
+			failed = true
+			// As with seeking, a group with members cannot be
+			// committed to.
+			if errors.Is(err, kerr.UnknownMemberID) {
+				status = "INVALID_OPERATION: the group must be empty to move its offsets"
+			} else {
+				status = err.Error()
+			}
+		} else {
+			deleted, err := adm.DeleteOffsets(ctx, group, kadm.TopicsSet{from: offsets.TopicsSet()[from]})
+			if err == nil {
+				deleted.EachError(func(_ string, _ int32, deleteErr error) {
+					if err == nil {
+						err = deleteErr
+					}
+				})
+			}
+			if err != nil {
+				failed = true
+				status = fmt.Sprintf("COMMITTED: unable to delete the offsets of %q: %v", from, err)
+			}
+		}
+
+		for _, o := range sortedOffsets(offsets) {
+			c, _ := commitTo.Lookup(to, o.Partition)
+			tw.Print(group, o.Partition, o.At, c.At, status)
+		}
+	}
+	return failed
+}
+
+func sortedOffsets(offsets kadm.Offsets) []kadm.Offset {
+	var sorted []kadm.Offset
+	offsets.Each(func(o kadm.Offset) { sorted = append(sorted, o) })
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Topic != sorted[j].Topic {
+			return sorted[i].Topic < sorted[j].Topic
+		}
+		return sorted[i].Partition < sorted[j].Partition
+	})
+	return sorted
+}
+
+// offsetTranslator translates committed offsets of a topic to the offsets of
+// the same records in its clone, which is empty before the copy: the records
+// of a partition of the clone are numbered from 0 in the order they are
+// copied. A committed offset is the offset of the next record to consume, so
+// it translates to the number of records copied before the first record at
+// or after it.
+type offsetTranslator struct {
+	// pending are the offsets to translate of each partition, sorted.
+	pending map[int32][]int64
+	// translated are the translations of the offsets seen so far.
+	translated map[int32]map[int64]int64
+	// n is the number of records copied of each partition.
+	n map[int32]int64
+}
+
+func newOffsetTranslator(groupOffsets map[string]kadm.Offsets) *offsetTranslator {
+	tr := &offsetTranslator{
+		pending:    make(map[int32][]int64),
+		translated: make(map[int32]map[int64]int64),
+		n:          make(map[int32]int64),
+	}
+	for _, offsets := range groupOffsets {
+		offsets.Each(func(o kadm.Offset) {
+			tr.pending[o.Partition] = append(tr.pending[o.Partition], o.At)
+		})
+	}
+	for p, pending := range tr.pending {
+		sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+		tr.translated[p] = make(map[int64]int64)
+	}
+	return tr
+}
+
+// observe is called with the offset of each record of the original topic in
+// order, before the record is copied, if it is.
+func (tr *offsetTranslator) observe(partition int32, offset int64) {
+	pending := tr.pending[partition]
+	for len(pending) > 0 && pending[0] <= offset {
+		tr.translated[partition][pending[0]] = tr.n[partition]
+		pending = pending[1:]
+	}
+	tr.pending[partition] = pending
+}
+
+// copied is called after a record of the partition is copied.
+func (tr *offsetTranslator) copied(partition int32) {
+	tr.n[partition]++
+}
+
+// translate returns the offset in the clone of a committed offset of the
+// partition. Offsets past the copied records translate to the end of the
+// partition of the clone.
+func (tr *offsetTranslator) translate(partition int32, offset int64) int64 {
+	if t, ok := tr.translated[partition][offset]; ok {
+		return t
+	}
+	return tr.n[partition]
+}
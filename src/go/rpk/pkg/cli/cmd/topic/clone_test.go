@@ -0,0 +1,66 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTopicOverrides(t *testing.T) {
+	configs := []kadm.Config{
+		{Key: "cleanup.policy", Value: kmsg.StringPtr("compact"), Source: kmsg.ConfigSourceDynamicTopicConfig},
+		{Key: "retention.ms", Value: kmsg.StringPtr("604800000"), Source: kmsg.ConfigSourceDefaultConfig},
+		{Key: "segment.bytes", Value: kmsg.StringPtr("1073741824"), Source: kmsg.ConfigSourceStaticBrokerConfig},
+		{Key: "sensitive", Source: kmsg.ConfigSourceDynamicTopicConfig},
+	}
+	require.Equal(t, map[string]*string{"cleanup.policy": kmsg.StringPtr("compact")}, topicOverrides(configs))
+}
+
+func TestOffsetTranslator(t *testing.T) {
+	g1 := make(kadm.Offsets)
+	g1.Add(kadm.Offset{Topic: "foo", Partition: 0, At: 0})
+	g1.Add(kadm.Offset{Topic: "foo", Partition: 1, At: 12})
+	g2 := make(kadm.Offsets)
+	g2.Add(kadm.Offset{Topic: "foo", Partition: 0, At: 15})
+	g2.Add(kadm.Offset{Topic: "foo", Partition: 1, At: 30})
+	tr := newOffsetTranslator(map[string]kadm.Offsets{"g1": g1, "g2": g2})
+
+	// Partition 0 starts at 10 after retention, and the record at 12 is a
+	// transaction marker, which is not copied.
+	for offset := int64(10); offset < 20; offset++ {
+		tr.observe(0, offset)
+		if offset != 12 {
+			tr.copied(0)
+		}
+	}
+	// Partition 1 is compacted: only the even offsets remain.
+	for offset := int64(0); offset < 20; offset += 2 {
+		tr.observe(1, offset)
+		tr.copied(1)
+	}
+
+	for _, test := range []struct {
+		partition int32
+		offset    int64
+		exp       int64
+	}{
+		{0, 0, 0},   // before the start
+		{0, 15, 4},  // 10, 11, 13 and 14 are before it
+		{1, 12, 6},  // 0, 2, ..., 10 are before it
+		{1, 30, 10}, // past the end
+		{2, 5, 0},   // nothing copied
+	} {
+		require.Equal(t, test.exp, tr.translate(test.partition, test.offset), "partition %d offset %d", test.partition, test.offset)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"regexp"
@@ -22,9 +23,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/twmb/franz-go/pkg/kadm"
@@ -35,8 +38,9 @@ type consumer struct {
 	partitions []int32
 	regex      bool
 
-	group    string
-	balancer string
+	group     string
+	balancer  string
+	groupOpts groupOptions
 
 	fetchMaxBytes int32
 	fetchMaxWait  time.Duration
@@ -47,6 +51,18 @@ type consumer struct {
 	pretty   bool // specific to -f json
 	metaOnly bool // specific to -f json
 
+	// For CI, --exit-after-records requires num records to be consumed,
+	// --exit-after-idle quits once no record arrives for exitIdle, and
+	// --assert-jq fails on the first record the expression is false for.
+	exitRecords int
+	exitIdle    time.Duration
+	assert      *jqExpr
+
+	// If --decode is specified, record keys and values serialized with the
+	// schema registry wire format are decoded and printed as JSON.
+	registry   *schemaregistry.Client
+	decodeType string
+
 	resetOffset kgo.Offset // defaults to NoResetOffset, can be start or end
 
 	// If an end offset is specified, we immediately look up where we will
@@ -54,14 +70,29 @@ type consumer struct {
 	partEnds   map[string]map[int32]int64
 	partStarts map[string]map[int32]int64
 
+	// If --output is specified, records are written to a rotating file and,
+	// unless group consuming, the next offsets to consume are checkpointed
+	// after every batch so that consuming can resume where it left off.
+	out            io.Writer
+	output         *utils.RotatingFile
+	fs             afero.Fs
+	checkpointPath string
+	checkpoint     consumeCheckpoint
+	resumeParts    map[string]map[int32]kgo.Offset
+
 	cl *kgo.Client
 }
 
 func newConsumeCommand(fs afero.Fs) *cobra.Command {
 	var (
-		c      consumer
-		offset string
-		format string
+		c          consumer
+		offset     string
+		format     string
+		output     string
+		rotateSize string
+		decode     string
+		srURLs     []string
+		assertJQ   string
 	)
 
 	cmd := &cobra.Command{
@@ -79,7 +110,44 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 
 			err = c.parseOffset(offset, topics, adm)
 			out.MaybeDie(err, "invalid --offset %q: %v", offset, err)
+
+			c.out, c.fs = os.Stdout, fs
+			if output != "" {
+				err = c.setupOutput(output, rotateSize, topics, adm)
+				out.MaybeDieErr(err)
+				defer c.output.Close()
+			} else if rotateSize != "" {
+				out.DieUsage("invalid flags: --output-rotate-size requires --output")
+			}
+
+			if len(c.group) == 0 {
+				for _, f := range groupOnlyFlags {
+					if cmd.Flags().Changed(f) {
+						out.DieUsage("invalid flags: --%s requires --group", f)
+					}
+				}
+			}
+
+			if c.exitRecords > 0 {
+				if cmd.Flags().Changed("num") {
+					out.DieUsage("invalid flags: only one of --num and --exit-after-records can be specified")
+				}
+				c.num = c.exitRecords
+			} else if c.exitRecords < 0 {
+				out.DieUsage("invalid --exit-after-records %d: must be positive", c.exitRecords)
+			}
+			if c.exitIdle < 0 {
+				out.DieUsage("invalid --exit-after-idle %v: must be positive", c.exitIdle)
+			}
+			if assertJQ != "" {
+				c.assert, err = compileJQ(assertJQ)
+				out.MaybeDie(err, "invalid --assert-jq %q: %v", assertJQ, err)
+			}
+
 			if allEmpty := c.filterEmptyPartitions(); allEmpty {
+				if c.exitRecords > 0 {
+					out.Die("consumed 0 of the %d records expected by --exit-after-records: all partitions are empty in the --offset range", c.exitRecords)
+				}
 				return
 			}
 
@@ -91,21 +159,38 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 				out.MaybeDie(err, "invalid --format: %v", err)
 			}
 
+			if decode != "" {
+				var ok bool
+				if c.decodeType, ok = decodeTypes[decode]; !ok {
+					out.DieUsage("invalid --decode %q: must be avro or protobuf", decode)
+				}
+				c.registry, err = newSchemaRegistryClient(cfg, srURLs)
+				out.MaybeDie(err, "unable to initialize schema registry client: %v", err)
+			} else if len(srURLs) > 0 {
+				out.DieUsage("invalid flags: --schema-registry-urls requires --decode")
+			}
+
 			sigs := make(chan os.Signal, 2)
 			signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
 			c.cl, err = kafka.NewFranzClient(fs, p, cfg, opts...)
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
 
-			doneConsume := make(chan struct{})
+			var (
+				consumed    int
+				consumeErr  error
+				interrupted bool
+				doneConsume = make(chan struct{})
+			)
 			go func() {
 				defer close(doneConsume)
-				c.consume()
+				consumed, consumeErr = c.consume()
 				c.cl.LeaveGroup()
 			}()
 
 			select {
 			case <-sigs:
+				interrupted = true
 			case <-doneConsume:
 			}
 
@@ -119,6 +204,16 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 			case <-sigs:
 			case <-doneClose:
 			}
+
+			switch {
+			case interrupted && c.exitRecords > 0:
+				out.Die("interrupted before consuming the %d records expected by --exit-after-records", c.exitRecords)
+			case interrupted:
+			case consumeErr != nil:
+				out.Die("%v", consumeErr)
+			case consumed < c.exitRecords:
+				out.Die("consumed %d of the %d records expected by --exit-after-records", consumed, c.exitRecords)
+			}
 		},
 	}
 
@@ -128,6 +223,13 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 
 	cmd.Flags().StringVarP(&c.group, "group", "g", "", "Group to use for consuming (incompatible with -p)")
 	cmd.Flags().StringVarP(&c.balancer, "balancer", "b", "cooperative-sticky", "Group balancer to use if group consuming (range, roundrobin, sticky, cooperative-sticky)")
+	cmd.Flags().StringVar(&c.groupOpts.commitMode, "commit-mode", commitModeAuto, "How consumed records are committed if group consuming (auto, sync, none)")
+	cmd.Flags().DurationVar(&c.groupOpts.autoCommitInterval, "auto-commit-interval", 0, "Interval between commits with --commit-mode auto (default 5s)")
+	cmd.Flags().DurationVar(&c.groupOpts.sessionTimeout, "session-timeout", 0, "Group session timeout if group consuming (default 45s)")
+	cmd.Flags().DurationVar(&c.groupOpts.heartbeatInterval, "heartbeat-interval", 0, "Group heartbeat interval if group consuming (default 3s)")
+	cmd.Flags().DurationVar(&c.groupOpts.rebalanceTimeout, "rebalance-timeout", 0, "Group rebalance timeout if group consuming (default 60s)")
+	cmd.Flags().StringVar(&c.groupOpts.instanceID, "instance-id", "", "Group instance ID for static membership if group consuming")
+	cmd.Flags().BoolVar(&c.groupOpts.printRebalances, "print-rebalances", false, "Print the partitions assigned, revoked and lost on every rebalance to STDERR")
 
 	cmd.Flags().Int32Var(&c.fetchMaxBytes, "fetch-max-bytes", 1<<20, "Maximum amount of bytes per fetch request per broker")
 	cmd.Flags().DurationVar(&c.fetchMaxWait, "fetch-max-wait", 5*time.Second, "Maximum amount of time to wait when fetching from a broker before the broker replies")
@@ -135,8 +237,15 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 
 	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format (see --help for details)")
 	cmd.Flags().IntVarP(&c.num, "num", "n", 0, "Quit after consuming this number of records (0 is unbounded)")
+	cmd.Flags().IntVar(&c.exitRecords, "exit-after-records", 0, "Quit after consuming this number of records, failing if fewer are consumed (see --help)")
+	cmd.Flags().DurationVar(&c.exitIdle, "exit-after-idle", 0, "Quit when no record is consumed for this long, e.g. 10s (see --help)")
+	cmd.Flags().StringVar(&assertJQ, "assert-jq", "", "Fail on the first record this jq expression is false for (see --help)")
 	cmd.Flags().BoolVar(&c.pretty, "pretty-print", true, "Pretty print each record over multiple lines (for -f json)")
 	cmd.Flags().BoolVar(&c.metaOnly, "meta-only", false, "Print all record info except the record value (for -f json)")
+	cmd.Flags().StringVar(&output, "output", "", "Append records to this file rather than printing them, checkpointing offsets to resume from (see --help)")
+	cmd.Flags().StringVar(&rotateSize, "output-rotate-size", "", "Rotate the --output file when it reaches this size (e.g. 100MB)")
+	cmd.Flags().StringVar(&decode, "decode", "", "Decode keys and values with their schema from the schema registry and print them as JSON (avro, protobuf)")
+	cmd.Flags().StringSliceVar(&srURLs, "schema-registry-urls", nil, "Comma delimited list of schema registry URLs for --decode (defaults to the schema registry of the config, or 127.0.0.1:8081)")
 
 	// Deprecated.
 	cmd.Flags().BoolVar(new(bool), "commit", false, "")
@@ -145,17 +254,31 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 	return cmd
 }
 
-func (c *consumer) consume() {
+// consume consumes until interrupted or done, returning the number of records
+// consumed, and why an --assert-jq assertion failed.
+func (c *consumer) consume() (int, error) {
 	var (
-		buf   []byte
-		n     int
-		marks []*kgo.Record
-		done  bool
+		buf        []byte
+		n          int
+		marks      []*kgo.Record
+		done       bool
+		err        error
+		lastRecord = time.Now()
 	)
 	for !done {
-		fs := c.cl.PollFetches(context.Background())
+		ctx, cancel := context.Background(), func() {}
+		if c.exitIdle > 0 {
+			ctx, cancel = context.WithDeadline(ctx, lastRecord.Add(c.exitIdle))
+		}
+		fs := c.cl.PollFetches(ctx)
+		cancel()
 		if fs.IsClientClosed() {
-			return
+			return n, err
+		}
+		if !fs.RecordIter().Done() {
+			lastRecord = time.Now()
+		} else if ctx.Err() != nil {
+			return n, err // idle for --exit-after-idle
 		}
 
 		fs.EachError(func(t string, p int32, err error) {
@@ -177,11 +300,26 @@ func (c *consumer) consume() {
 
 			for _, r := range p.Records {
 				if !r.Attrs.IsControl() {
+					d := c.decodeRecord(r)
 					if c.f == nil {
-						c.writeRecordJSON(r)
+						c.writeRecordJSON(r, d)
 					} else {
-						buf = c.f.AppendPartitionRecord(buf[:0], &p.FetchPartition, r)
-						os.Stdout.Write(buf)
+						fr := r
+						if d.key != nil || d.value != nil {
+							cp := *r
+							if d.key != nil {
+								cp.Key = d.key
+							}
+							if d.value != nil {
+								cp.Value = d.value
+							}
+							fr = &cp
+						}
+						buf = c.f.AppendPartitionRecord(buf[:0], &p.FetchPartition, fr)
+						c.write(buf)
+					}
+					if err = c.check(r, d); err != nil {
+						done = true
 					}
 				}
 
@@ -190,7 +328,7 @@ func (c *consumer) consume() {
 				marks = append(marks, r)
 				n++
 
-				if done = c.num > 0 && n >= c.num; done {
+				if done = done || c.num > 0 && n >= c.num; done {
 					return
 				}
 
@@ -201,32 +339,55 @@ func (c *consumer) consume() {
 			}
 		})
 
-		// Before we poll, we mark everything we just processed to be
-		// available for autocommitting.
-		c.cl.MarkCommitRecords(marks...)
+		// Before we poll, we commit everything we just processed, or
+		// mark it available for autocommitting.
+		c.commit(marks)
+		c.saveCheckpoint(marks)
 	}
+	return n, err
+}
+
+// check returns an error if the --assert-jq expression is not true for the
+// record.
+func (c *consumer) check(r *kgo.Record, d decodedRecord) error {
+	if c.assert == nil {
+		return nil
+	}
+	ok, err := c.assert.matches(assertionInput(r, d))
+	switch {
+	case err != nil:
+		return fmt.Errorf("--assert-jq %q failed on topic %s partition %d offset %d: %w", c.assert.src, r.Topic, r.Partition, r.Offset, err)
+	case !ok:
+		return fmt.Errorf("--assert-jq %q is false for topic %s partition %d offset %d", c.assert.src, r.Topic, r.Partition, r.Offset)
+	}
+	return nil
 }
 
-func (c *consumer) writeRecordJSON(r *kgo.Record) {
+// write writes a formatted record to the output. Failing to write to an
+// output file is fatal, since consuming further would skip records.
+func (c *consumer) write(b []byte) {
+	_, err := c.out.Write(b)
+	out.MaybeDie(err, "unable to write record: %v", err)
+}
+
+func (c *consumer) writeRecordJSON(r *kgo.Record, d decodedRecord) {
 	type Header struct {
 		Key   string `json:"key"`
 		Value string `json:"value"`
 	}
 
 	m := struct {
-		Topic     string   `json:"topic"`
-		Key       string   `json:"key,omitempty"`
-		Value     string   `json:"value,omitempty"`
-		ValueSize *int     `json:"value_size,omitempty"` // non-nil if --meta-only
-		Headers   []Header `json:"headers,omitempty"`
-		Timestamp int64    `json:"timestamp"` // millis
+		Topic     string      `json:"topic"`
+		Key       interface{} `json:"key,omitempty"`        // string, or raw JSON if decoded
+		Value     interface{} `json:"value,omitempty"`      // string, or raw JSON if decoded
+		ValueSize *int        `json:"value_size,omitempty"` // non-nil if --meta-only
+		Headers   []Header    `json:"headers,omitempty"`
+		Timestamp int64       `json:"timestamp"` // millis
 
 		Partition int32 `json:"partition"`
 		Offset    int64 `json:"offset"`
 	}{
 		Topic:     r.Topic,
-		Key:       string(r.Key),
-		Value:     string(r.Value),
 		Headers:   make([]Header, 0, len(r.Headers)),
 		Timestamp: r.Timestamp.UnixNano() / 1e6,
 
@@ -234,10 +395,20 @@ func (c *consumer) writeRecordJSON(r *kgo.Record) {
 		Offset:    r.Offset,
 	}
 
-	if c.metaOnly {
-		size := len(m.Value)
-		m.Value = ""
+	switch {
+	case d.key != nil:
+		m.Key = d.key
+	case len(r.Key) > 0:
+		m.Key = string(r.Key)
+	}
+	switch {
+	case c.metaOnly:
+		size := len(r.Value)
 		m.ValueSize = &size
+	case d.value != nil:
+		m.Value = d.value
+	case len(r.Value) > 0:
+		m.Value = string(r.Value)
 	}
 
 	for _, h := range r.Headers {
@@ -255,12 +426,9 @@ func (c *consumer) writeRecordJSON(r *kgo.Record) {
 	} else {
 		out, _ = json.Marshal(m)
 	}
-	os.Stdout.Write(out)
-	os.Stdout.Write(newline)
+	c.write(append(out, '\n'))
 }
 
-var newline = []byte("\n")
-
 func (c *consumer) parseOffset(
 	offset string, topics []string, adm *kadm.Client,
 ) error {
@@ -630,8 +798,12 @@ func (c *consumer) intoOptions(topics []string) ([]kgo.Opt, error) {
 		} else if c.partEnds != nil {
 			return nil, errors.New("invalid flags: group consuming is not supported with consume-until-end offsets")
 		}
+		groupOpts, err := c.groupOpts.intoOptions()
+		if err != nil {
+			return nil, err
+		}
 		opts = append(opts, kgo.ConsumerGroup(c.group))
-		opts = append(opts, kgo.AutoCommitMarks())
+		opts = append(opts, groupOpts...)
 	}
 
 	// If we have ends, we have to consume control records because a
@@ -654,6 +826,11 @@ func (c *consumer) intoOptions(topics []string) ([]kgo.Opt, error) {
 		}
 		opts = append(opts, kgo.ConsumePartitions(offsets))
 
+	// If we resume from an --output checkpoint, we consume exactly the
+	// partitions we resolved when loading the checkpoint.
+	case c.resumeParts != nil:
+		opts = append(opts, kgo.ConsumePartitions(c.resumeParts))
+
 	// If no partitions were specified, we want to consume topics directly.
 	// If we did not specify an end offset, then we just consume them.
 	case len(c.partitions) == 0:
@@ -711,6 +888,65 @@ Consuming records reads from any amount of input topics, formats each record
 according to --format, and prints them to STDOUT. The output formatter
 understands a wide variety of formats.
 
+With --output, records are appended to a file instead of STDOUT. The file can
+be rotated with --output-rotate-size: once the file would exceed that size, it
+is renamed to FILE.1 (or the next free number) and a new FILE is started.
+After every fetch, the next offset of each partition is checkpointed in
+FILE.checkpoint; rerunning the same command resumes from the checkpoint, so
+rpk can be used as a simple archival sink that is stopped and restarted. The
+checkpoint is not used when group consuming, since the group commits are
+resumed from instead, nor when consuming topics with --regex.
+
+With --group, records are consumed as a member of a consumer group, which
+balances the partitions among its members with --balancer. This can be used to
+emulate production consumers, e.g. when debugging rebalance storms:
+
+  * --commit-mode auto commits the consumed records periodically (every
+    --auto-commit-interval) and when partitions are revoked; sync commits
+    them after every fetch, before fetching more; none never commits.
+  * --session-timeout, --heartbeat-interval and --rebalance-timeout set the
+    timeouts of the group membership, and --instance-id enables static
+    membership.
+  * --print-rebalances prints the partitions assigned, revoked and lost on
+    every rebalance to STDERR, with a timestamp.
+
+For example, to join a group as a static member with cooperative rebalancing
+and synchronous commits:
+
+    rpk topic consume foo -g my-group -b cooperative-sticky \
+        --commit-mode sync --instance-id member-1 --print-rebalances
+
+With --decode avro or --decode protobuf, keys and values serialized with the
+schema registry wire format (a zero byte followed by the schema ID) are decoded
+with their schema and printed as JSON. Schemas are fetched from the schema
+registry once and cached for the rest of the command. The schema registry is
+the one of --schema-registry-urls, or of the schema_registry section of the
+config, or 127.0.0.1:8081. With --format json, the decoded key and value are
+embedded as JSON rather than strings; with other formats, %k and %v print
+the decoded JSON. Records that cannot be decoded are printed as is, after a
+warning on STDERR.
+
+For CI, the exit flags make consuming end and fail on unmet expectations:
+
+    --exit-after-records N  quit after N records, exiting non-zero if fewer
+                            are consumed before quitting for another reason
+                            (idle, end of --offset, interrupted)
+    --exit-after-idle D     quit once no record is consumed for D, e.g. 10s
+    --assert-jq EXPR        exit non-zero on the first record EXPR is false
+                            or null for, or fails on
+
+Assertions see each record as with --format json: topic, key, value, headers,
+timestamp, partition and offset, with the key and value parsed as JSON if they
+are JSON (or the decoded JSON with --decode). A subset of jq is supported:
+paths (.value.id, .headers[0].key), literals, comparisons, and, or, not, | and
+the length, keys, has, type, tostring, tonumber, ascii_downcase, ascii_upcase,
+startswith, endswith, contains, test, any and all builtins. For example, to
+expect 100 orders with items, none of which arrives more than a minute after
+the previous one:
+
+    rpk topic consume orders --exit-after-records 100 --exit-after-idle 1m \
+        --assert-jq '.value.order_id != null and (.value.items | length) > 0'
+
 The default output format "--format json" is a special format that outputs each
 record as JSON. There may be more single-word-no-escapes formats added later.
 Outside of these special formats, formatting follows the rules described below.
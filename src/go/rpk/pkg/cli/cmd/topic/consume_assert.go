@@ -0,0 +1,872 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// This file implements the subset of jq used by --assert-jq: paths, literals,
+// comparisons, and, or, pipes and a few builtins. Expressions produce a single
+// value, so iterators (.[]), select and other generators are not supported;
+// any and all cover their common use in assertions.
+
+// jqFunc evaluates an expression against its input.
+type jqFunc func(in interface{}) (interface{}, error)
+
+// jqExpr is a compiled --assert-jq expression.
+type jqExpr struct {
+	src  string
+	eval jqFunc
+}
+
+// compileJQ compiles an expression of the supported subset of jq.
+func compileJQ(src string) (*jqExpr, error) {
+	toks, err := lexJQ(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &jqParser{toks: toks}
+	f, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+	return &jqExpr{src: src, eval: f}, nil
+}
+
+// matches returns whether the expression is true for the input: like jq, any
+// value but false and null is true.
+func (e *jqExpr) matches(in interface{}) (bool, error) {
+	v, err := e.eval(in)
+	if err != nil {
+		return false, err
+	}
+	return jqTruthy(v), nil
+}
+
+// assertionInput returns the record as --assert-jq sees it: the fields of
+// --format json, with the key and value parsed if they are JSON.
+func assertionInput(r *kgo.Record, d decodedRecord) interface{} {
+	parse := func(decoded json.RawMessage, raw []byte) interface{} {
+		if decoded != nil {
+			raw = decoded
+		}
+		if len(raw) == 0 {
+			return nil
+		}
+		var v interface{}
+		if json.Unmarshal(raw, &v) == nil {
+			return v
+		}
+		return string(raw)
+	}
+	headers := make([]interface{}, 0, len(r.Headers))
+	for _, h := range r.Headers {
+		headers = append(headers, map[string]interface{}{
+			"key":   h.Key,
+			"value": string(h.Value),
+		})
+	}
+	return map[string]interface{}{
+		"topic":     r.Topic,
+		"key":       parse(d.key, r.Key),
+		"value":     parse(d.value, r.Value),
+		"headers":   headers,
+		"timestamp": float64(r.Timestamp.UnixNano() / 1e6),
+		"partition": float64(r.Partition),
+		"offset":    float64(r.Offset),
+	}
+}
+
+type jqTokenKind int
+
+const (
+	jqPunct jqTokenKind = iota
+	jqIdent
+	jqString
+	jqNumber
+)
+
+type jqToken struct {
+	kind jqTokenKind
+	text string
+	str  string  // jqString
+	num  float64 // jqNumber
+}
+
+func lexJQ(src string) ([]jqToken, error) {
+	var toks []jqToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"':
+			end := i + 1
+			for ; end < len(src) && src[end] != '"'; end++ {
+				if src[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(src) {
+				return nil, errors.New("unterminated string")
+			}
+			text := src[i : end+1]
+			var s string
+			if err := json.Unmarshal([]byte(text), &s); err != nil {
+				return nil, fmt.Errorf("invalid string %s: %w", text, err)
+			}
+			toks = append(toks, jqToken{kind: jqString, text: text, str: s})
+			i = end + 1
+
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			end := i + 1
+			for end < len(src) && strings.IndexByte("0123456789.eE+-", src[end]) >= 0 {
+				if (src[end] == '+' || src[end] == '-') && src[end-1] != 'e' && src[end-1] != 'E' {
+					break
+				}
+				end++
+			}
+			n, err := strconv.ParseFloat(src[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %s", src[i:end])
+			}
+			toks = append(toks, jqToken{kind: jqNumber, text: src[i:end], num: n})
+			i = end
+
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			end := i + 1
+			for end < len(src) && (src[end] == '_' || src[end] >= 'a' && src[end] <= 'z' ||
+				src[end] >= 'A' && src[end] <= 'Z' || src[end] >= '0' && src[end] <= '9') {
+				end++
+			}
+			toks = append(toks, jqToken{kind: jqIdent, text: src[i:end]})
+			i = end
+
+		default:
+			text := string(c)
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "==", "!=", "<=", ">=":
+					text = two
+				}
+			}
+			if strings.IndexByte(".[](){}|;,:<>", c) < 0 && len(text) == 1 {
+				return nil, fmt.Errorf("unexpected %q", text)
+			}
+			toks = append(toks, jqToken{kind: jqPunct, text: text})
+			i += len(text)
+		}
+	}
+	return toks, nil
+}
+
+type jqParser struct {
+	toks []jqToken
+	pos  int
+}
+
+func (p *jqParser) peek() *jqToken {
+	if p.pos < len(p.toks) {
+		return &p.toks[p.pos]
+	}
+	return nil
+}
+
+// accept consumes the next token if it is the given punctuation or keyword.
+func (p *jqParser) accept(text string) bool {
+	if t := p.peek(); t != nil && (t.kind == jqPunct || t.kind == jqIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *jqParser) expect(text string) error {
+	if p.accept(text) {
+		return nil
+	}
+	if t := p.peek(); t != nil {
+		return fmt.Errorf("expected %q, got %q", text, t.text)
+	}
+	return fmt.Errorf("expected %q at the end of the expression", text)
+}
+
+// pipe := or ('|' or)*
+func (p *jqParser) parsePipe() (jqFunc, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("|") {
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		first, then := left, right
+		left = func(in interface{}) (interface{}, error) {
+			v, err := first(in)
+			if err != nil {
+				return nil, err
+			}
+			return then(v)
+		}
+	}
+	return left, nil
+}
+
+// or := and ('or' and)*
+func (p *jqParser) parseOr() (jqFunc, error) {
+	return p.parseBoolean("or", p.parseAnd, true)
+}
+
+// and := comparison ('and' comparison)*
+func (p *jqParser) parseAnd() (jqFunc, error) {
+	return p.parseBoolean("and", p.parseComparison, false)
+}
+
+// parseBoolean parses the left-associative and and or operators, which
+// short-circuit once the result is shortCircuit.
+func (p *jqParser) parseBoolean(
+	op string, operand func() (jqFunc, error), shortCircuit bool,
+) (jqFunc, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(op) {
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(in interface{}) (interface{}, error) {
+			v, err := l(in)
+			if err != nil {
+				return nil, err
+			}
+			if jqTruthy(v) == shortCircuit {
+				return shortCircuit, nil
+			}
+			if v, err = r(in); err != nil {
+				return nil, err
+			}
+			return jqTruthy(v), nil
+		}
+	}
+	return left, nil
+}
+
+// comparison := postfix (('=='|'!='|'<'|'<='|'>'|'>=') postfix)?
+func (p *jqParser) parseComparison() (jqFunc, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t == nil || t.kind != jqPunct {
+		return left, nil
+	}
+	var cmp func(c int) bool
+	switch t.text {
+	case "==":
+		cmp = func(c int) bool { return c == 0 }
+	case "!=":
+		cmp = func(c int) bool { return c != 0 }
+	case "<":
+		cmp = func(c int) bool { return c < 0 }
+	case "<=":
+		cmp = func(c int) bool { return c <= 0 }
+	case ">":
+		cmp = func(c int) bool { return c > 0 }
+	case ">=":
+		cmp = func(c int) bool { return c >= 0 }
+	default:
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	return func(in interface{}) (interface{}, error) {
+		l, err := left(in)
+		if err != nil {
+			return nil, err
+		}
+		r, err := right(in)
+		if err != nil {
+			return nil, err
+		}
+		return cmp(jqCompare(l, r)), nil
+	}, nil
+}
+
+// postfix := primary ('.' ident | '.'? '[' pipe ']')*
+func (p *jqParser) parsePostfix() (jqFunc, error) {
+	f, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			if f, err = p.parseSuffix(f); err != nil {
+				return nil, err
+			}
+		case p.accept("["):
+			if f, err = p.parseIndex(f); err != nil {
+				return nil, err
+			}
+		default:
+			return f, nil
+		}
+	}
+}
+
+// parseSuffix parses what follows a dot: a field name or an index.
+func (p *jqParser) parseSuffix(base jqFunc) (jqFunc, error) {
+	if p.accept("[") {
+		return p.parseIndex(base)
+	}
+	t := p.peek()
+	if t == nil || t.kind != jqIdent && t.kind != jqString {
+		return nil, errors.New("expected a field name after '.'")
+	}
+	p.pos++
+	name := t.text
+	if t.kind == jqString {
+		name = t.str
+	}
+	return func(in interface{}) (interface{}, error) {
+		v, err := base(in)
+		if err != nil {
+			return nil, err
+		}
+		return jqIndex(v, name)
+	}, nil
+}
+
+// parseIndex parses an index after its opening bracket. Like in jq, the
+// index is evaluated against the input of the whole path.
+func (p *jqParser) parseIndex(base jqFunc) (jqFunc, error) {
+	if p.accept("]") {
+		return nil, errors.New("iterating with .[] is not supported, use any or all")
+	}
+	idx, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return func(in interface{}) (interface{}, error) {
+		v, err := base(in)
+		if err != nil {
+			return nil, err
+		}
+		k, err := idx(in)
+		if err != nil {
+			return nil, err
+		}
+		return jqIndex(v, k)
+	}, nil
+}
+
+// primary := '.' | literal | '(' pipe ')' | array | object
+//
+//	| builtin ('(' pipe (';' pipe)* ')')?
+//
+// A path starting with a dot, e.g. .value, is parsed as the identity
+// followed by a suffix.
+func (p *jqParser) parsePrimary() (jqFunc, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("unexpected end of the expression")
+	}
+	p.pos++
+	identity := func(in interface{}) (interface{}, error) { return in, nil }
+	switch {
+	case t.kind == jqPunct && t.text == ".":
+		if n := p.peek(); n != nil && (n.kind == jqIdent || n.kind == jqString || n.text == "[") {
+			return p.parseSuffix(identity)
+		}
+		return identity, nil
+
+	case t.kind == jqPunct && t.text == "(":
+		f, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(")")
+
+	case t.kind == jqPunct && t.text == "[":
+		return p.parseArray()
+
+	case t.kind == jqPunct && t.text == "{":
+		return p.parseObject()
+
+	case t.kind == jqString:
+		s := t.str
+		return func(interface{}) (interface{}, error) { return s, nil }, nil
+
+	case t.kind == jqNumber:
+		n := t.num
+		return func(interface{}) (interface{}, error) { return n, nil }, nil
+
+	case t.kind == jqIdent:
+		switch t.text {
+		case "true", "false":
+			b := t.text == "true"
+			return func(interface{}) (interface{}, error) { return b, nil }, nil
+		case "null":
+			return func(interface{}) (interface{}, error) { return nil, nil }, nil
+		}
+		var args []jqFunc
+		if p.accept("(") {
+			for {
+				arg, err := p.parsePipe()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if !p.accept(";") {
+					break
+				}
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		return jqBuiltin(t.text, args)
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// array := '[' (pipe (',' pipe)*)? ']'
+func (p *jqParser) parseArray() (jqFunc, error) {
+	var elems []jqFunc
+	if !p.accept("]") {
+		for {
+			elem, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return func(in interface{}) (interface{}, error) {
+		arr := make([]interface{}, 0, len(elems))
+		for _, elem := range elems {
+			v, err := elem(in)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	}, nil
+}
+
+// object := '{' ((ident | string) ':' or (',' (ident | string) ':' or)*)? '}'
+func (p *jqParser) parseObject() (jqFunc, error) {
+	var (
+		keys   []string
+		values []jqFunc
+	)
+	if !p.accept("}") {
+		for {
+			t := p.peek()
+			if t == nil || t.kind != jqIdent && t.kind != jqString {
+				return nil, errors.New("expected an object key")
+			}
+			p.pos++
+			key := t.text
+			if t.kind == jqString {
+				key = t.str
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			keys, values = append(keys, key), append(values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+	}
+	return func(in interface{}) (interface{}, error) {
+		obj := make(map[string]interface{}, len(keys))
+		for i, key := range keys {
+			v, err := values[i](in)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+		return obj, nil
+	}, nil
+}
+
+// jqBuiltin returns the builtin function of the given name and arguments.
+// Arguments are evaluated against the input of the function, as in jq.
+func jqBuiltin(name string, args []jqFunc) (jqFunc, error) {
+	arity := map[string]int{
+		"length": 0, "not": 0, "keys": 0, "type": 0, "tostring": 0, "tonumber": 0,
+		"ascii_downcase": 0, "ascii_upcase": 0,
+		"has": 1, "startswith": 1, "endswith": 1, "contains": 1, "test": 1, "any": 1, "all": 1,
+	}
+	want, ok := arity[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function %s", name)
+	}
+	if len(args) != want {
+		return nil, fmt.Errorf("%s/%d is not defined, it takes %d arguments", name, len(args), want)
+	}
+
+	// String functions take a string input and argument.
+	str := func(fn func(s, arg string) (interface{}, error)) jqFunc {
+		return func(in interface{}) (interface{}, error) {
+			s, ok := in.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s input must be a string, got %s", name, jqType(in))
+			}
+			a, err := args[0](in)
+			if err != nil {
+				return nil, err
+			}
+			as, ok := a.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s argument must be a string, got %s", name, jqType(a))
+			}
+			return fn(s, as)
+		}
+	}
+	// Array functions apply their argument to every element of their input.
+	each := func(stopAt bool) jqFunc {
+		return func(in interface{}) (interface{}, error) {
+			arr, ok := in.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s input must be an array, got %s", name, jqType(in))
+			}
+			for _, e := range arr {
+				v, err := args[0](e)
+				if err != nil {
+					return nil, err
+				}
+				if jqTruthy(v) == stopAt {
+					return stopAt, nil
+				}
+			}
+			return !stopAt, nil
+		}
+	}
+
+	switch name {
+	case "length":
+		return func(in interface{}) (interface{}, error) {
+			switch v := in.(type) {
+			case nil:
+				return 0.0, nil
+			case bool:
+				return nil, errors.New("boolean has no length")
+			case float64:
+				return math.Abs(v), nil
+			case string:
+				return float64(utf8.RuneCountInString(v)), nil
+			case []interface{}:
+				return float64(len(v)), nil
+			default:
+				return float64(len(v.(map[string]interface{}))), nil
+			}
+		}, nil
+	case "not":
+		return func(in interface{}) (interface{}, error) { return !jqTruthy(in), nil }, nil
+	case "keys":
+		return func(in interface{}) (interface{}, error) {
+			switch v := in.(type) {
+			case map[string]interface{}:
+				ks := make([]string, 0, len(v))
+				for k := range v {
+					ks = append(ks, k)
+				}
+				sort.Strings(ks)
+				keys := make([]interface{}, 0, len(ks))
+				for _, k := range ks {
+					keys = append(keys, k)
+				}
+				return keys, nil
+			case []interface{}:
+				keys := make([]interface{}, 0, len(v))
+				for i := range v {
+					keys = append(keys, float64(i))
+				}
+				return keys, nil
+			}
+			return nil, fmt.Errorf("%s has no keys", jqType(in))
+		}, nil
+	case "type":
+		return func(in interface{}) (interface{}, error) { return jqType(in), nil }, nil
+	case "tostring":
+		return func(in interface{}) (interface{}, error) {
+			if s, ok := in.(string); ok {
+				return s, nil
+			}
+			b, err := json.Marshal(in)
+			return string(b), err
+		}, nil
+	case "tonumber":
+		return func(in interface{}) (interface{}, error) {
+			switch v := in.(type) {
+			case float64:
+				return v, nil
+			case string:
+				n, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse %q as a number", v)
+				}
+				return n, nil
+			}
+			return nil, fmt.Errorf("%s cannot be parsed as a number", jqType(in))
+		}, nil
+	case "ascii_downcase", "ascii_upcase":
+		return func(in interface{}) (interface{}, error) {
+			s, ok := in.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s input must be a string, got %s", name, jqType(in))
+			}
+			if name == "ascii_downcase" {
+				return strings.ToLower(s), nil
+			}
+			return strings.ToUpper(s), nil
+		}, nil
+	case "has":
+		return func(in interface{}) (interface{}, error) {
+			k, err := args[0](in)
+			if err != nil {
+				return nil, err
+			}
+			switch v := in.(type) {
+			case map[string]interface{}:
+				ks, ok := k.(string)
+				if !ok {
+					return nil, fmt.Errorf("cannot check whether an object has a %s key", jqType(k))
+				}
+				_, has := v[ks]
+				return has, nil
+			case []interface{}:
+				n, ok := k.(float64)
+				if !ok {
+					return nil, fmt.Errorf("cannot check whether an array has a %s key", jqType(k))
+				}
+				return n >= 0 && int(n) < len(v), nil
+			}
+			return nil, fmt.Errorf("cannot check whether %s has a key", jqType(in))
+		}, nil
+	case "startswith":
+		return str(func(s, arg string) (interface{}, error) { return strings.HasPrefix(s, arg), nil }), nil
+	case "endswith":
+		return str(func(s, arg string) (interface{}, error) { return strings.HasSuffix(s, arg), nil }), nil
+	case "test":
+		var (
+			lastPattern string
+			lastRe      *regexp.Regexp
+		)
+		return str(func(s, pattern string) (interface{}, error) {
+			if lastRe == nil || pattern != lastPattern {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+				}
+				lastPattern, lastRe = pattern, re
+			}
+			return lastRe.MatchString(s), nil
+		}), nil
+	case "contains":
+		return func(in interface{}) (interface{}, error) {
+			b, err := args[0](in)
+			if err != nil {
+				return nil, err
+			}
+			if jqType(in) != jqType(b) {
+				return nil, fmt.Errorf("%s cannot contain %s", jqType(in), jqType(b))
+			}
+			return jqContains(in, b), nil
+		}, nil
+	case "any":
+		return each(true), nil
+	default: // all
+		return each(false), nil
+	}
+}
+
+func jqTruthy(v interface{}) bool {
+	b, isBool := v.(bool)
+	return v != nil && (!isBool || b)
+}
+
+func jqType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// jqIndex indexes an object with a string or an array with a number. As in
+// jq, indexing null returns null, and so do indexes out of range.
+func jqIndex(v, k interface{}) (interface{}, error) {
+	switch container := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		if ks, ok := k.(string); ok {
+			return container[ks], nil
+		}
+	case []interface{}:
+		if n, ok := k.(float64); ok {
+			i := int(n)
+			if i < 0 {
+				i += len(container)
+			}
+			if i < 0 || i >= len(container) {
+				return nil, nil
+			}
+			return container[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", jqType(v), jqType(k))
+}
+
+// jqCompare orders values like jq: null < false < true < numbers < strings
+// < arrays < objects, then by value.
+func jqCompare(a, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v := v.(type) {
+		case nil:
+			return 0
+		case bool:
+			if v {
+				return 2
+			}
+			return 1
+		case float64:
+			return 3
+		case string:
+			return 4
+		case []interface{}:
+			return 5
+		default:
+			return 6
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	switch a := a.(type) {
+	case float64:
+		switch b := b.(float64); {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(a, b.(string))
+	case []interface{}:
+		b := b.([]interface{})
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if c := jqCompare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return len(a) - len(b)
+	case map[string]interface{}:
+		if reflect.DeepEqual(a, b) {
+			return 0
+		}
+		// Objects are only compared for equality in assertions: order
+		// them by their encoding, which sorts their keys.
+		ea, _ := json.Marshal(a)
+		eb, _ := json.Marshal(b)
+		if c := strings.Compare(string(ea), string(eb)); c != 0 {
+			return c
+		}
+		return 1
+	}
+	return 0 // null and booleans of the same rank
+}
+
+// jqContains returns whether a contains b, like jq: substrings, arrays whose
+// every element of b is contained by an element of a, and objects whose every
+// key of b is in a with a value that contains the value of b.
+func jqContains(a, b interface{}) bool {
+	switch a := a.(type) {
+	case string:
+		return strings.Contains(a, b.(string))
+	case []interface{}:
+	outer:
+		for _, eb := range b.([]interface{}) {
+			for _, ea := range a {
+				if jqType(ea) == jqType(eb) && jqContains(ea, eb) {
+					continue outer
+				}
+			}
+			return false
+		}
+		return true
+	case map[string]interface{}:
+		for k, vb := range b.(map[string]interface{}) {
+			va, ok := a[k]
+			if !ok || jqType(va) != jqType(vb) || !jqContains(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	return jqCompare(a, b) == 0
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestCompileJQ(t *testing.T) {
+	r := &kgo.Record{
+		Topic:     "orders",
+		Key:       []byte("order-1"),
+		Value:     []byte(`{"id":7,"status":"Paid","items":[{"sku":"a","qty":2},{"sku":"b","qty":0}],"note":null}`),
+		Headers:   []kgo.RecordHeader{{Key: "source", Value: []byte("web")}},
+		Timestamp: time.Unix(1, 0),
+		Partition: 3,
+		Offset:    42,
+	}
+	in := assertionInput(r, decodedRecord{})
+
+	for _, test := range []struct {
+		expr   string
+		exp    bool
+		expErr bool
+	}{
+		{expr: ".", exp: true},
+		{expr: `.topic == "orders"`, exp: true},
+		{expr: `.key == "order-1"`, exp: true},
+		{expr: ".partition == 3 and .offset >= 42", exp: true},
+		{expr: ".timestamp == 1000", exp: true},
+		{expr: ".value.id == 7", exp: true},
+		{expr: ".value.id != 7", exp: false},
+		{expr: ".value.id < -1", exp: false},
+		{expr: ".value.missing", exp: false},
+		{expr: ".value.note == null", exp: true},
+		{expr: ".value.missing.deeper == null", exp: true},
+		{expr: `.value["status"] | ascii_downcase == "paid"`, exp: true},
+		{expr: `.value."status" | startswith("Pa")`, exp: true},
+		{expr: `.value.status | test("^P.*d$")`, exp: true},
+		{expr: ".value.items | length == 2", exp: true},
+		{expr: ".value.items[1].qty == 0", exp: true},
+		{expr: ".value.items[-1].sku == \"b\"", exp: true},
+		{expr: ".value.items[5] == null", exp: true},
+		{expr: ".value.items | all(.qty > 0)", exp: false},
+		{expr: ".value.items | any(.qty > 0)", exp: true},
+		{expr: `.value | has("id") and (has("other") | not)`, exp: true},
+		{expr: `.value | keys == ["id", "items", "note", "status"]`, exp: true},
+		{expr: `.value | contains({"items": [{"sku": "a"}]})`, exp: true},
+		{expr: `.value.id | tostring == "7"`, exp: true},
+		{expr: `.value.id == ("7" | tonumber)`, exp: true},
+		{expr: `[.partition, .offset] == [3, 42]`, exp: true},
+		{expr: `{"id": .value.id, status: "Paid"} == (.value | {id: .id, status: .status})`, exp: true},
+		{expr: `.headers[0].value == "web"`, exp: true},
+		{expr: "false or .value.id > 1", exp: true},
+		{expr: "null", exp: false},
+		{expr: "(1 < 2) == true", exp: true},
+		{expr: `null < false and false < 0 and 0 < "" and "" < []`, exp: true},
+
+		{expr: ".value.id | length", exp: true},
+		{expr: ".key.field", expErr: true},
+		{expr: ".value.items | startswith(\"a\")", expErr: true},
+	} {
+		t.Run(test.expr, func(t *testing.T) {
+			e, err := compileJQ(test.expr)
+			require.NoError(t, err)
+			got, err := e.matches(in)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, got)
+		})
+	}
+
+	for _, invalid := range []string{
+		"",
+		".value.",
+		".value ==",
+		"(.value",
+		".value[]",
+		".value | select(.id)",
+		"has(1; 2)",
+		"length(1)",
+		`"unterminated`,
+		".value @ 1",
+		"[1, 2",
+		"{1: 2}",
+	} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := compileJQ(invalid)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAssertionInput(t *testing.T) {
+	r := &kgo.Record{Key: []byte("not json"), Value: []byte{0, 0, 0, 0, 1}}
+	in := assertionInput(r, decodedRecord{value: []byte(`{"decoded":true}`)}).(map[string]interface{})
+	require.Equal(t, "not json", in["key"])
+	require.Equal(t, map[string]interface{}{"decoded": true}, in["value"])
+
+	in = assertionInput(&kgo.Record{Value: []byte("12")}, decodedRecord{}).(map[string]interface{})
+	require.Nil(t, in["key"])
+	require.Equal(t, 12.0, in["value"])
+}
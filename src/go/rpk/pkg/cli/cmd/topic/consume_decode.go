@@ -0,0 +1,105 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// decodeTypes maps the values of --decode to schema registry schema types.
+var decodeTypes = map[string]string{
+	"avro":     schemaregistry.TypeAvro,
+	"protobuf": schemaregistry.TypeProtobuf,
+}
+
+// schemaRegistryURLs returns the schema registry URLs to use: the flag if
+// set, otherwise the schema registry listeners of the config, otherwise the
+// default local schema registry.
+func schemaRegistryURLs(cfg *config.Config, flag []string) []string {
+	if len(flag) > 0 {
+		return flag
+	}
+	var urls []string
+	if cfg.SchemaRegistry != nil {
+		for _, a := range cfg.SchemaRegistry.SchemaRegistryAPI {
+			host := a.Address
+			if host == "" || host == "0.0.0.0" {
+				host = "127.0.0.1"
+			}
+			port := a.Port
+			if port == 0 {
+				port = 8081
+			}
+			urls = append(urls, net.JoinHostPort(host, strconv.Itoa(port)))
+		}
+	}
+	if len(urls) == 0 {
+		urls = []string{"127.0.0.1:8081"}
+	}
+	return urls
+}
+
+// newSchemaRegistryClient returns the client used to decode records. The
+// schema registry authenticates with the same users as the Kafka API.
+func newSchemaRegistryClient(cfg *config.Config, urls []string) (*schemaregistry.Client, error) {
+	var user, pass string
+	if sasl := cfg.Rpk.KafkaAPI.SASL; sasl != nil {
+		user, pass = sasl.User, sasl.Password
+	}
+	return schemaregistry.NewClient(schemaRegistryURLs(cfg, urls), nil, user, pass)
+}
+
+// decodedRecord is the JSON of the key and value of a record decoded with
+// --decode; a field is nil if it was not decoded.
+type decodedRecord struct {
+	key, value json.RawMessage
+}
+
+// decodeRecord decodes the key and value of a record if they are serialized
+// with the schema registry wire format. Undecodable values are printed raw
+// after a warning; keys are commonly plain strings, so keys that do not
+// start with a schema ID are left as is without warning.
+func (c *consumer) decodeRecord(r *kgo.Record) decodedRecord {
+	var d decodedRecord
+	if c.registry == nil {
+		return d
+	}
+	decode := func(what string, b []byte) json.RawMessage {
+		v, err := c.registry.Decode(context.Background(), b, c.decodeType)
+		var raw []byte
+		if err == nil {
+			if raw, err = json.Marshal(v); err == nil {
+				return raw
+			}
+		}
+		if what == "value" || !errors.Is(err, schemaregistry.ErrNoSchemaID) {
+			fmt.Fprintf(os.Stderr, "ERR: unable to decode %s of topic %s partition %d offset %d: %v\n",
+				what, r.Topic, r.Partition, r.Offset, err)
+		}
+		return nil
+	}
+	if len(r.Key) > 0 {
+		d.key = decode("key", r.Key)
+	}
+	if len(r.Value) > 0 {
+		d.value = decode("value", r.Value)
+	}
+	return d
+}
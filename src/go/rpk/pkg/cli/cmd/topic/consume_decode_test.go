@@ -0,0 +1,31 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRegistryURLs(t *testing.T) {
+	cfg := &config.Config{}
+	require.Equal(t, []string{"127.0.0.1:8081"}, schemaRegistryURLs(cfg, nil))
+	require.Equal(t, []string{"sr:8081"}, schemaRegistryURLs(cfg, []string{"sr:8081"}))
+
+	cfg.SchemaRegistry = &config.SchemaRegistry{
+		SchemaRegistryAPI: []config.NamedSocketAddress{
+			{Address: "0.0.0.0", Port: 18081},
+			{Address: "::1"},
+		},
+	}
+	require.Equal(t, []string{"127.0.0.1:18081", "[::1]:8081"}, schemaRegistryURLs(cfg, nil))
+}
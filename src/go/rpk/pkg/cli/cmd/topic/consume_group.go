@@ -0,0 +1,135 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Commit modes of group consuming.
+const (
+	commitModeAuto = "auto" // commit the consumed records periodically and on rebalance
+	commitModeSync = "sync" // commit the consumed records after every fetch
+	commitModeNone = "none" // never commit
+)
+
+// groupOptions configure group consuming, to emulate the behavior of
+// production consumers.
+type groupOptions struct {
+	commitMode         string
+	autoCommitInterval time.Duration
+	sessionTimeout     time.Duration
+	heartbeatInterval  time.Duration
+	rebalanceTimeout   time.Duration
+	instanceID         string
+	printRebalances    bool
+}
+
+// groupOnlyFlags are the flags that only apply when group consuming.
+var groupOnlyFlags = []string{
+	"commit-mode",
+	"auto-commit-interval",
+	"session-timeout",
+	"heartbeat-interval",
+	"rebalance-timeout",
+	"instance-id",
+	"print-rebalances",
+}
+
+func (o groupOptions) intoOptions() ([]kgo.Opt, error) {
+	var opts []kgo.Opt
+	switch o.commitMode {
+	case commitModeAuto:
+		opts = append(opts, kgo.AutoCommitMarks())
+		if o.autoCommitInterval > 0 {
+			opts = append(opts, kgo.AutoCommitInterval(o.autoCommitInterval))
+		}
+	case commitModeSync, commitModeNone:
+		if o.autoCommitInterval > 0 {
+			return nil, fmt.Errorf("invalid flags: --auto-commit-interval requires --commit-mode %s", commitModeAuto)
+		}
+		opts = append(opts, kgo.DisableAutoCommit())
+	default:
+		return nil, fmt.Errorf("unrecognized --commit-mode %q, must be %s, %s or %s", o.commitMode, commitModeAuto, commitModeSync, commitModeNone)
+	}
+
+	if o.sessionTimeout > 0 {
+		opts = append(opts, kgo.SessionTimeout(o.sessionTimeout))
+	}
+	if o.heartbeatInterval > 0 {
+		opts = append(opts, kgo.HeartbeatInterval(o.heartbeatInterval))
+	}
+	if o.rebalanceTimeout > 0 {
+		opts = append(opts, kgo.RebalanceTimeout(o.rebalanceTimeout))
+	}
+	if o.instanceID != "" {
+		opts = append(opts, kgo.InstanceID(o.instanceID))
+	}
+	if o.printRebalances {
+		opts = append(opts,
+			kgo.OnPartitionsAssigned(printRebalance("assigned")),
+			kgo.OnPartitionsRevoked(printRebalance("revoked")),
+			kgo.OnPartitionsLost(printRebalance("lost")),
+		)
+	}
+	return opts, nil
+}
+
+// commit commits the records that were just consumed, according to the
+// commit mode. Outside of group consuming, this does nothing.
+func (c *consumer) commit(records []*kgo.Record) {
+	if len(c.group) == 0 || len(records) == 0 {
+		return
+	}
+	switch c.groupOpts.commitMode {
+	case commitModeAuto:
+		// Marked records are committed by the autocommitter.
+		c.cl.MarkCommitRecords(records...)
+	case commitModeSync:
+		if err := c.cl.CommitRecords(context.Background(), records...); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to commit: %v\n", err)
+		}
+	}
+}
+
+func printRebalance(event string) func(context.Context, *kgo.Client, map[string][]int32) {
+	return func(_ context.Context, _ *kgo.Client, ps map[string][]int32) {
+		fmt.Fprintln(os.Stderr, formatRebalance(time.Now(), event, ps))
+	}
+}
+
+// formatRebalance formats a rebalance event, with its partitions sorted, e.g.
+//
+//	2022-08-01T10:00:00.000Z revoked foo[0 2] bar[1]
+func formatRebalance(at time.Time, event string, ps map[string][]int32) string {
+	topics := make([]string, 0, len(ps))
+	for t := range ps {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	var sb strings.Builder
+	sb.WriteString(at.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	sb.WriteString(" ")
+	sb.WriteString(event)
+	for _, t := range topics {
+		partitions := append([]int32(nil), ps[t]...)
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+		fmt.Fprintf(&sb, " %s%v", t, partitions)
+	}
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupOptions(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		opts   groupOptions
+		nOpts  int
+		expErr bool
+	}{
+		{"auto", groupOptions{commitMode: commitModeAuto}, 1, false},
+		{"auto with interval", groupOptions{commitMode: commitModeAuto, autoCommitInterval: time.Second}, 2, false},
+		{"sync", groupOptions{commitMode: commitModeSync}, 1, false},
+		{"none with interval", groupOptions{commitMode: commitModeNone, autoCommitInterval: time.Second}, 0, true},
+		{"unknown mode", groupOptions{commitMode: "eventually"}, 0, true},
+		{
+			"everything",
+			groupOptions{
+				commitMode:        commitModeSync,
+				sessionTimeout:    10 * time.Second,
+				heartbeatInterval: time.Second,
+				rebalanceTimeout:  30 * time.Second,
+				instanceID:        "member-1",
+				printRebalances:   true,
+			},
+			8,
+			false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts, err := test.opts.intoOptions()
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, opts, test.nOpts)
+		})
+	}
+}
+
+func TestFormatRebalance(t *testing.T) {
+	at := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+	got := formatRebalance(at, "revoked", map[string][]int32{
+		"foo": {2, 0},
+		"bar": {1},
+	})
+	require.Equal(t, "2022-08-01T10:00:00.000Z revoked bar[1] foo[0 2]", got)
+}
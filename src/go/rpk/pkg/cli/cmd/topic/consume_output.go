@@ -0,0 +1,167 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
+	"github.com/spf13/afero"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// setupOutput opens the --output file and, unless group consuming (where the
+// group commits are resumed from) or consuming regular expressions, loads its
+// checkpoint to resume consuming where a previous run stopped.
+func (c *consumer) setupOutput(
+	output, rotateSize string, topics []string, adm *kadm.Client,
+) error {
+	var maxBytes int64
+	if rotateSize != "" {
+		var err error
+		maxBytes, err = units.FromHumanSize(rotateSize)
+		if err != nil || maxBytes <= 0 {
+			return fmt.Errorf("invalid --output-rotate-size %q", rotateSize)
+		}
+	}
+
+	if c.group == "" && !c.regex {
+		c.checkpointPath = output + ".checkpoint"
+		checkpoint, err := readConsumeCheckpoint(c.fs, c.checkpointPath)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			if err := c.resumeFrom(checkpoint, topics, adm); err != nil {
+				return err
+			}
+		} else {
+			checkpoint = make(consumeCheckpoint)
+		}
+		c.checkpoint = checkpoint
+	}
+
+	var err error
+	if c.output, err = utils.OpenRotatingFile(c.fs, output, maxBytes, utils.KeepAllRotations); err != nil {
+		return err
+	}
+	c.out = c.output
+	return nil
+}
+
+// resumeFrom starts consuming checkpointed partitions at their checkpoint.
+// If --offset defined where to start, the checkpoint is only used if it is
+// past that start; otherwise, partitions missing from the checkpoint start at
+// --offset.
+func (c *consumer) resumeFrom(
+	checkpoint consumeCheckpoint, topics []string, adm *kadm.Client,
+) error {
+	if c.partStarts != nil {
+		for t, ps := range c.partStarts {
+			for p, start := range ps {
+				if next, ok := checkpoint[t][p]; ok && next > start {
+					ps[p] = next
+				}
+			}
+		}
+		return nil
+	}
+
+	lend, err := adm.ListEndOffsets(context.Background(), topics...)
+	if err != nil {
+		return fmt.Errorf("unable to list end offsets: %v", err)
+	}
+	keep := make(map[int32]bool, len(c.partitions))
+	for _, p := range c.partitions {
+		keep[p] = true
+	}
+	c.resumeParts = make(map[string]map[int32]kgo.Offset)
+	for t, lps := range lend {
+		ps := make(map[int32]kgo.Offset)
+		for p := range lps {
+			if len(keep) > 0 && !keep[p] {
+				continue
+			}
+			ps[p] = c.resetOffset
+			if next, ok := checkpoint[t][p]; ok {
+				ps[p] = kgo.NewOffset().At(next)
+			}
+		}
+		c.resumeParts[t] = ps
+	}
+	return nil
+}
+
+// saveCheckpoint records the next offset to consume after the given records,
+// once they are safely written to the output file.
+func (c *consumer) saveCheckpoint(records []*kgo.Record) {
+	if c.checkpoint == nil || len(records) == 0 {
+		return
+	}
+	for _, r := range records {
+		c.checkpoint.set(r.Topic, r.Partition, r.Offset+1)
+	}
+	err := c.output.Sync()
+	out.MaybeDie(err, "unable to sync output: %v", err)
+	err = writeConsumeCheckpoint(c.fs, c.checkpointPath, c.checkpoint)
+	out.MaybeDieErr(err)
+}
+
+// consumeCheckpoint maps topics and partitions to the next offset to consume.
+type consumeCheckpoint map[string]map[int32]int64
+
+func (c consumeCheckpoint) set(topic string, partition int32, next int64) {
+	ps := c[topic]
+	if ps == nil {
+		ps = make(map[int32]int64)
+		c[topic] = ps
+	}
+	ps[partition] = next
+}
+
+// readConsumeCheckpoint returns the checkpoint at path, or nil if there is
+// none.
+func readConsumeCheckpoint(fs afero.Fs, path string) (consumeCheckpoint, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checkpoint %q: %v", path, err)
+	}
+	var c consumeCheckpoint
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("unable to decode checkpoint %q: %v", path, err)
+	}
+	return c, nil
+}
+
+// writeConsumeCheckpoint atomically replaces the checkpoint at path.
+func writeConsumeCheckpoint(fs afero.Fs, path string, c consumeCheckpoint) error {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("unable to encode checkpoint: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := afero.WriteFile(fs, tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("unable to write checkpoint %q: %v", tmp, err)
+	}
+	if err := fs.Rename(tmp, path); err != nil {
+		return fmt.Errorf("unable to rename checkpoint %q to %q: %v", tmp, path, err)
+	}
+	return nil
+}
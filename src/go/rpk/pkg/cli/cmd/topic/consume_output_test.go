@@ -0,0 +1,39 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeCheckpoint(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	c, err := readConsumeCheckpoint(fs, "out.checkpoint")
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	c = make(consumeCheckpoint)
+	c.set("foo", 0, 10)
+	c.set("foo", 2, 3)
+	c.set("bar", 1, 7)
+	require.NoError(t, writeConsumeCheckpoint(fs, "out.checkpoint", c))
+
+	got, err := readConsumeCheckpoint(fs, "out.checkpoint")
+	require.NoError(t, err)
+	require.Equal(t, c, got)
+
+	require.NoError(t, afero.WriteFile(fs, "bad.checkpoint", []byte("{"), 0o644))
+	_, err = readConsumeCheckpoint(fs, "bad.checkpoint")
+	require.Error(t, err)
+}
@@ -31,11 +31,21 @@ func newCreateCommand(fs afero.Fs) *cobra.Command {
 		replicas   int16
 		compact    bool
 		configKVs  []string
+		file       string
+		update     bool
 	)
 	cmd := &cobra.Command{
 		Use:   "create [TOPICS...]",
 		Short: "Create topics",
-		Args:  cobra.MinimumNArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if file != "" {
+				if len(args) > 0 {
+					return errors.New("topics cannot be specified both as arguments and with --file")
+				}
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		Long: `Create topics.
 
 All topics created with this command will have the same number of partitions,
@@ -47,6 +57,34 @@ For example,
 
 will create two topics, foo and bar, each with 20 partitions, 3 replicas, and
 the cleanup.policy=compact config option set.
+
+With --file, topics are instead created from a YAML file that defines the
+partitions, replicas, and configs of each topic. Topics can share settings
+through templates; a topic's own fields override its template, which overrides
+the -p, -r, and -c flags:
+
+    templates:
+      compacted:
+        replicas: 3
+        configs:
+          cleanup.policy: compact
+    topics:
+      - name: users
+        template: compacted
+        partitions: 12
+      - name: events
+        partitions: 24
+        configs:
+          retention.ms: "604800000"
+
+Topics that do not exist are created. Topics that exist are compared with the
+file and reported as EXISTS if they match, or DIFFERS with their differences.
+Using --update reconciles the differences: configs in the file are set and
+partitions are added. Configs that are not in the file are left alone, and
+partitions cannot be removed nor the replication factor changed.
+
+Using --dry with --file validates the creations and config changes without
+applying them.
 `,
 
 		Run: func(cmd *cobra.Command, topics []string) {
@@ -66,6 +104,22 @@ the cleanup.policy=compact config option set.
 				}
 			}
 
+			if file != "" {
+				fileTopics, err := readTopicsFile(fs, file, fileTopic{
+					partitions: partitions,
+					replicas:   replicas,
+					configs:    configs,
+				})
+				out.MaybeDieErr(err)
+				if createFromFile(context.Background(), cl, fileTopics, dry, update) {
+					os.Exit(1)
+				}
+				return
+			}
+			if update {
+				out.DieUsage("--update requires --file")
+			}
+
 			req := kmsg.NewPtrCreateTopicsRequest()
 			req.ValidateOnly = dry
 			req.TimeoutMillis = 5000 // TODO move to rpk.kafka
@@ -118,6 +172,8 @@ the cleanup.policy=compact config option set.
 	cmd.Flags().Int32VarP(&partitions, "partitions", "p", -1, "Number of partitions to create per topic; -1 defaults to the cluster's default_topic_partitions")
 	cmd.Flags().Int16VarP(&replicas, "replicas", "r", -1, "Replication factor (must be odd); -1 defaults to the cluster's default_topic_replications")
 	cmd.Flags().BoolVarP(&dry, "dry", "d", false, "Dry run: validate the topic creation request; do not create topics")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "YAML file of the topics to create, rather than topics as arguments")
+	cmd.Flags().BoolVar(&update, "update", false, "With --file, update the configs and partitions of existing topics to match the file")
 
 	// Sept 2021
 	cmd.Flags().BoolVar(&compact, "compact", false, "Alias for -c cleanup.policy=compact")
@@ -0,0 +1,381 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"gopkg.in/yaml.v3"
+)
+
+// topicsFile is the declarative file of 'rpk topic create -f'.
+type topicsFile struct {
+	Templates map[string]topicSpec `yaml:"templates"`
+	Topics    []topicSpec          `yaml:"topics"`
+}
+
+type topicSpec struct {
+	Name       string            `yaml:"name"`
+	Template   string            `yaml:"template"`
+	Partitions *int32            `yaml:"partitions"`
+	Replicas   *int16            `yaml:"replicas"`
+	Configs    map[string]string `yaml:"configs"`
+}
+
+// fileTopic is a topic of the file with its template and the command line
+// defaults applied. Partitions and replicas are -1 to use the cluster
+// defaults.
+type fileTopic struct {
+	name       string
+	partitions int32
+	replicas   int16
+	configs    map[string]string
+}
+
+// parseTopicsFile parses a topics file. Each topic starts from the command
+// line defaults, then its template, then its own fields; configs are merged
+// in the same order.
+func parseTopicsFile(raw []byte, defaults fileTopic) ([]fileTopic, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	var f topicsFile
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to decode topics file: %v", err)
+	}
+	if len(f.Topics) == 0 {
+		return nil, errors.New("topics file does not contain any topics")
+	}
+
+	apply := func(t *fileTopic, s topicSpec) {
+		if s.Partitions != nil {
+			t.partitions = *s.Partitions
+		}
+		if s.Replicas != nil {
+			t.replicas = *s.Replicas
+		}
+		for k, v := range s.Configs {
+			t.configs[k] = v
+		}
+	}
+
+	seen := make(map[string]bool)
+	topics := make([]fileTopic, 0, len(f.Topics))
+	for i, s := range f.Topics {
+		if s.Name == "" {
+			return nil, fmt.Errorf("topic %d is missing a name", i)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("topic %q is defined more than once", s.Name)
+		}
+		seen[s.Name] = true
+
+		t := fileTopic{
+			name:       s.Name,
+			partitions: defaults.partitions,
+			replicas:   defaults.replicas,
+			configs:    make(map[string]string),
+		}
+		for k, v := range defaults.configs {
+			t.configs[k] = v
+		}
+		if s.Template != "" {
+			tmpl, ok := f.Templates[s.Template]
+			if !ok {
+				return nil, fmt.Errorf("topic %q uses unknown template %q", s.Name, s.Template)
+			}
+			apply(&t, tmpl)
+		}
+		apply(&t, s)
+		topics = append(topics, t)
+	}
+	return topics, nil
+}
+
+// topicChange is a difference between a topic in the file and in the
+// cluster.
+type topicChange struct {
+	what     string
+	from, to string
+	// fixable is false for changes that --update cannot apply, such as
+	// removing partitions.
+	fixable bool
+}
+
+func (c topicChange) String() string {
+	s := fmt.Sprintf("%s: %s -> %s", c.what, c.from, c.to)
+	if !c.fixable {
+		s += " (cannot be changed)"
+	}
+	return s
+}
+
+// diffTopic compares a topic of the file with its current state. Configs set
+// in the file are compared with their current value; configs absent from the
+// file are left alone.
+func diffTopic(
+	want fileTopic, partitions int32, replicas int16, configs map[string]string,
+) []topicChange {
+	var changes []topicChange
+	if want.partitions > 0 && want.partitions != partitions {
+		changes = append(changes, topicChange{
+			what:    "partitions",
+			from:    fmt.Sprint(partitions),
+			to:      fmt.Sprint(want.partitions),
+			fixable: want.partitions > partitions,
+		})
+	}
+	if want.replicas > 0 && want.replicas != replicas {
+		changes = append(changes, topicChange{
+			what: "replicas",
+			from: fmt.Sprint(replicas),
+			to:   fmt.Sprint(want.replicas),
+		})
+	}
+	keys := make([]string, 0, len(want.configs))
+	for k := range want.configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		current, ok := configs[k]
+		if ok && current == want.configs[k] {
+			continue
+		}
+		if !ok {
+			current = "(unset)"
+		}
+		changes = append(changes, topicChange{
+			what:    k,
+			from:    current,
+			to:      want.configs[k],
+			fixable: true,
+		})
+	}
+	return changes
+}
+
+// createFromFile creates the topics of a topics file that do not exist yet
+// and reports how the existing ones differ from the file. If update is true,
+// the configs of existing topics are altered and partitions are added to
+// match the file. It returns whether any topic failed.
+func createFromFile(
+	ctx context.Context, cl *kgo.Client, topics []fileTopic, dry, update bool,
+) bool {
+	adm := kadm.NewClient(cl)
+
+	names := make([]string, 0, len(topics))
+	for _, t := range topics {
+		names = append(names, t.name)
+	}
+	listed, err := adm.ListTopics(ctx, names...)
+	out.MaybeDie(err, "unable to list topics: %v", err)
+
+	var missing, existing []fileTopic
+	for _, t := range topics {
+		if d, ok := listed[t.name]; !ok || errors.Is(d.Err, kerr.UnknownTopicOrPartition) {
+			missing = append(missing, t)
+		} else {
+			existing = append(existing, t)
+		}
+	}
+
+	type result struct {
+		status  string
+		details string
+		failed  bool
+	}
+	results := make(map[string]result)
+
+	if len(missing) > 0 {
+		req := kmsg.NewPtrCreateTopicsRequest()
+		req.ValidateOnly = dry
+		req.TimeoutMillis = 5000
+		for _, t := range missing {
+			reqTopic := kmsg.NewCreateTopicsRequestTopic()
+			reqTopic.Topic = t.name
+			reqTopic.NumPartitions = t.partitions
+			reqTopic.ReplicationFactor = t.replicas
+			for k, v := range t.configs {
+				reqConfig := kmsg.NewCreateTopicsRequestTopicConfig()
+				reqConfig.Name = k
+				reqConfig.Value = kmsg.StringPtr(v)
+				reqTopic.Configs = append(reqTopic.Configs, reqConfig)
+			}
+			req.Topics = append(req.Topics, reqTopic)
+		}
+		resp, err := req.RequestWith(ctx, cl)
+		out.MaybeDie(err, "unable to create topics: %v", err)
+		for _, t := range resp.Topics {
+			r := result{status: "CREATED"}
+			if dry {
+				r.status = "WOULD CREATE"
+			}
+			if err := kerr.ErrorForCode(t.ErrorCode); err != nil {
+				r = result{status: "ERROR", details: err.Error(), failed: true}
+				if t.ErrorMessage != nil {
+					r.details += ": " + *t.ErrorMessage
+				}
+			}
+			results[t.Topic] = r
+		}
+	}
+
+	if len(existing) > 0 {
+		existingNames := make([]string, 0, len(existing))
+		for _, t := range existing {
+			existingNames = append(existingNames, t.name)
+		}
+		described, err := adm.DescribeTopicConfigs(ctx, existingNames...)
+		out.MaybeDie(err, "unable to describe topic configs: %v", err)
+		configs := make(map[string]map[string]string)
+		describeErrs := make(map[string]error)
+		for _, rc := range described {
+			if rc.Err != nil {
+				describeErrs[rc.Name] = rc.Err
+				continue
+			}
+			kvs := make(map[string]string)
+			for _, c := range rc.Configs {
+				if c.Value != nil {
+					kvs[c.Key] = *c.Value
+				}
+			}
+			configs[rc.Name] = kvs
+		}
+
+		var (
+			alters   []kmsg.IncrementalAlterConfigsRequestResource
+			addParts = make(map[string]int)
+		)
+		for _, t := range existing {
+			d := listed[t.name]
+			if d.Err != nil {
+				results[t.name] = result{status: "ERROR", details: d.Err.Error(), failed: true}
+				continue
+			}
+			if err := describeErrs[t.name]; err != nil {
+				results[t.name] = result{status: "ERROR", details: err.Error(), failed: true}
+				continue
+			}
+			var replicas int16
+			for _, p := range d.Partitions {
+				replicas = int16(len(p.Replicas))
+				break
+			}
+			changes := diffTopic(t, int32(len(d.Partitions)), replicas, configs[t.name])
+			if len(changes) == 0 {
+				results[t.name] = result{status: "EXISTS"}
+				continue
+			}
+			details := make([]string, 0, len(changes))
+			for _, c := range changes {
+				details = append(details, c.String())
+			}
+			r := result{status: "DIFFERS", details: strings.Join(details, "; ")}
+			if update {
+				r.status = "UPDATED"
+				if dry {
+					r.status = "WOULD UPDATE"
+				}
+				resource := kmsg.NewIncrementalAlterConfigsRequestResource()
+				resource.ResourceType = kmsg.ConfigResourceTypeTopic
+				resource.ResourceName = t.name
+				for _, c := range changes {
+					switch {
+					case !c.fixable:
+						r.status, r.failed = "PARTIALLY UPDATED", true
+						if dry {
+							r.status = "WOULD PARTIALLY UPDATE"
+						}
+					case c.what == "partitions":
+						addParts[t.name] = int(t.partitions) - len(d.Partitions)
+					default:
+						config := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+						config.Name = c.what
+						config.Op = kmsg.IncrementalAlterConfigOpSet
+						config.Value = kmsg.StringPtr(c.to)
+						resource.Configs = append(resource.Configs, config)
+					}
+				}
+				if len(resource.Configs) > 0 {
+					alters = append(alters, resource)
+				}
+			}
+			results[t.name] = r
+		}
+
+		fail := func(topic string, err error) {
+			r := results[topic]
+			r.status, r.failed = "ERROR", true
+			r.details = fmt.Sprintf("%s (%v)", r.details, err)
+			results[topic] = r
+		}
+		if len(alters) > 0 {
+			req := kmsg.NewPtrIncrementalAlterConfigsRequest()
+			req.ValidateOnly = dry
+			req.Resources = alters
+			resp, err := req.RequestWith(ctx, cl)
+			out.MaybeDie(err, "unable to alter topic configs: %v", err)
+			for _, resource := range resp.Resources {
+				if err := kerr.ErrorForCode(resource.ErrorCode); err != nil {
+					fail(resource.ResourceName, err)
+				}
+			}
+		}
+		// CreatePartitions adds the same number of partitions to every
+		// topic it is given, so topics are grouped by how many they need.
+		byAdd := make(map[int][]string)
+		for topic, add := range addParts {
+			byAdd[add] = append(byAdd[add], topic)
+		}
+		for add, topics := range byAdd {
+			if dry {
+				break
+			}
+			resps, err := adm.CreatePartitions(ctx, add, topics...)
+			out.MaybeDie(err, "unable to add partitions: %v", err)
+			for _, resp := range resps {
+				if resp.Err != nil {
+					fail(resp.Topic, resp.Err)
+				}
+			}
+		}
+	}
+
+	tw := out.NewTable("TOPIC", "STATUS", "DETAILS")
+	defer tw.Flush()
+	var failed bool
+	for _, t := range topics {
+		r := results[t.name]
+		failed = failed || r.failed
+		tw.Print(t.name, r.status, r.details)
+	}
+	return failed
+}
+
+func readTopicsFile(fs afero.Fs, path string, defaults fileTopic) ([]fileTopic, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %q: %v", path, err)
+	}
+	return parseTopicsFile(raw, defaults)
+}
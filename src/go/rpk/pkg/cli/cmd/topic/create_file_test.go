@@ -0,0 +1,145 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTopicsFile(t *testing.T) {
+	defaults := fileTopic{
+		partitions: -1,
+		replicas:   -1,
+		configs:    map[string]string{"compression.type": "zstd"},
+	}
+
+	for _, test := range []struct {
+		name   string
+		in     string
+		exp    []fileTopic
+		expErr bool
+	}{
+		{
+			name: "templates and overrides",
+			in: `
+templates:
+  compacted:
+    replicas: 3
+    partitions: 6
+    configs:
+      cleanup.policy: compact
+topics:
+  - name: users
+    template: compacted
+    partitions: 12
+    configs:
+      compression.type: lz4
+  - name: events
+    configs:
+      retention.ms: 604800000
+`,
+			exp: []fileTopic{
+				{
+					name:       "users",
+					partitions: 12,
+					replicas:   3,
+					configs: map[string]string{
+						"cleanup.policy":   "compact",
+						"compression.type": "lz4",
+					},
+				},
+				{
+					name:       "events",
+					partitions: -1,
+					replicas:   -1,
+					configs: map[string]string{
+						"compression.type": "zstd",
+						"retention.ms":     "604800000",
+					},
+				},
+			},
+		},
+		{
+			name:   "no topics",
+			in:     "templates: {}",
+			expErr: true,
+		},
+		{
+			name:   "unknown template",
+			in:     "topics: [{name: foo, template: bar}]",
+			expErr: true,
+		},
+		{
+			name:   "duplicate topic",
+			in:     "topics: [{name: foo}, {name: foo}]",
+			expErr: true,
+		},
+		{
+			name:   "missing name",
+			in:     "topics: [{partitions: 3}]",
+			expErr: true,
+		},
+		{
+			name:   "unknown field",
+			in:     "topics: [{name: foo, partition: 3}]",
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseTopicsFile([]byte(test.in), defaults)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+func TestDiffTopic(t *testing.T) {
+	want := fileTopic{
+		name:       "foo",
+		partitions: 6,
+		replicas:   3,
+		configs: map[string]string{
+			"cleanup.policy": "compact",
+			"retention.ms":   "1000",
+			"segment.bytes":  "1048576",
+		},
+	}
+	current := map[string]string{
+		"cleanup.policy":  "compact",
+		"retention.ms":    "2000",
+		"retention.bytes": "-1",
+	}
+
+	require.Empty(t, diffTopic(want, 6, 3, map[string]string{
+		"cleanup.policy": "compact",
+		"retention.ms":   "1000",
+		"segment.bytes":  "1048576",
+	}))
+
+	require.Equal(t, []topicChange{
+		{what: "partitions", from: "3", to: "6", fixable: true},
+		{what: "replicas", from: "1", to: "3"},
+		{what: "retention.ms", from: "2000", to: "1000", fixable: true},
+		{what: "segment.bytes", from: "(unset)", to: "1048576", fixable: true},
+	}, diffTopic(want, 3, 1, current))
+
+	changes := diffTopic(want, 12, 3, current)
+	require.Equal(t, topicChange{what: "partitions", from: "12", to: "6"}, changes[0])
+	require.Equal(t, "partitions: 12 -> 6 (cannot be changed)", changes[0].String())
+
+	// Cluster defaults are never a difference.
+	require.Empty(t, diffTopic(fileTopic{partitions: -1, replicas: -1}, 3, 1, current))
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/types"
+)
+
+func newDescribeConfigCommand(fs afero.Fs) *cobra.Command {
+	var onlyOverrides bool
+	cmd := &cobra.Command{
+		Use:   "describe-config [TOPIC]",
+		Short: "Describe the configs of a topic, with their defaults and documentation",
+		Long: `Describe the configs of a topic, with their defaults and documentation.
+
+This command prints every config of a topic along with:
+
+  * its source: "dynamic" if it is set on the topic or on the cluster at
+    runtime, "static" if it is set in the broker configuration file, and
+    "default" if it is unset;
+  * its default value, i.e. the value the topic would have if the config was
+    deleted from the topic;
+  * a short description of the config.
+
+Use --only-overrides to print only the configs set on the topic itself.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, topicArg []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			req := kmsg.NewPtrDescribeConfigsRequest()
+			req.IncludeSynonyms = true
+			req.IncludeDocumentation = true
+			reqResource := kmsg.NewDescribeConfigsRequestResource()
+			reqResource.ResourceType = kmsg.ConfigResourceTypeTopic
+			reqResource.ResourceName = topicArg[0]
+			req.Resources = append(req.Resources, reqResource)
+
+			resp, err := req.RequestWith(context.Background(), cl)
+			out.MaybeDie(err, "unable to request configs: %v", err)
+			if len(resp.Resources) != 1 {
+				out.Die("config response returned %d resources when we asked for 1", len(resp.Resources))
+			}
+			err = kerr.ErrorForCode(resp.Resources[0].ErrorCode)
+			out.MaybeDie(err, "config response contained error: %v", err)
+
+			types.Sort(resp)
+			tw := out.NewTable("KEY", "VALUE", "SOURCE", "DEFAULT", "DESCRIPTION")
+			defer tw.Flush()
+			for _, row := range describeConfigRows(resp.Resources[0].Configs, onlyOverrides) {
+				tw.Print(row...)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&onlyOverrides, "only-overrides", false, "Print only the configs set on the topic")
+	return cmd
+}
+
+// describeConfigRows returns the KEY, VALUE, SOURCE, DEFAULT and DESCRIPTION
+// of each config. The default and the description are taken from the response
+// if the broker returned them, and from topicConfigDocs otherwise.
+func describeConfigRows(
+	configs []kmsg.DescribeConfigsResponseResourceConfig, onlyOverrides bool,
+) [][]interface{} {
+	var rows [][]interface{}
+	for _, c := range configs {
+		if onlyOverrides && c.Source != kmsg.ConfigSourceDynamicTopicConfig {
+			continue
+		}
+		doc := topicConfigDocs[c.Name]
+
+		val := configValue(c.Value, c.IsSensitive)
+		def := doc.def
+		if c.Source == kmsg.ConfigSourceDefaultConfig {
+			def = val
+		}
+		// Synonyms are ordered by precedence: the first one not set on
+		// the topic is the value the topic falls back to.
+		for _, s := range c.ConfigSynonyms {
+			if s.Source != kmsg.ConfigSourceDynamicTopicConfig {
+				def = configValue(s.Value, c.IsSensitive)
+				break
+			}
+		}
+		if def == "" {
+			def = "-"
+		}
+
+		desc := doc.desc
+		if c.Documentation != nil && *c.Documentation != "" {
+			desc = *c.Documentation
+		}
+
+		rows = append(rows, []interface{}{c.Name, val, configSourceName(c.Source), def, desc})
+	}
+	return rows
+}
+
+func configValue(v *string, sensitive bool) string {
+	switch {
+	case sensitive:
+		return "(sensitive)"
+	case v == nil:
+		return ""
+	default:
+		return *v
+	}
+}
+
+// configSourceName collapses the sources of a config to the three that
+// matter when configuring a topic.
+func configSourceName(s kmsg.ConfigSource) string {
+	switch s {
+	case kmsg.ConfigSourceDynamicTopicConfig,
+		kmsg.ConfigSourceDynamicBrokerConfig,
+		kmsg.ConfigSourceDynamicDefaultBrokerConfig:
+		return "dynamic"
+	case kmsg.ConfigSourceStaticBrokerConfig:
+		return "static"
+	case kmsg.ConfigSourceDefaultConfig:
+		return "default"
+	default:
+		return strings.ToLower(s.String())
+	}
+}
+
+type topicConfigDoc struct {
+	def  string
+	desc string
+}
+
+// topicConfigDocs documents the topic configs supported by Redpanda, for
+// brokers that do not return documentation with the configs.
+var topicConfigDocs = map[string]topicConfigDoc{
+	"cleanup.policy": {
+		"delete",
+		"Whether old segments are deleted, compacted, or both (delete, compact, compact,delete)",
+	},
+	"compression.type": {
+		"producer",
+		"Compression of the batches on disk; producer keeps the compression of the producer",
+	},
+	"max.message.bytes": {
+		"1048576",
+		"Largest batch size, in bytes, that the topic accepts",
+	},
+	"message.timestamp.type": {
+		"CreateTime",
+		"Whether record timestamps are set by the producer (CreateTime) or the broker (LogAppendTime)",
+	},
+	"redpanda.remote.read": {
+		"false",
+		"Whether the topic can be read from Tiered Storage",
+	},
+	"redpanda.remote.write": {
+		"false",
+		"Whether segments of the topic are uploaded to Tiered Storage",
+	},
+	"retention.bytes": {
+		"-1",
+		"Partition size, in bytes, above which old segments are removed; -1 for no limit",
+	},
+	"retention.local.target.bytes": {
+		"-1",
+		"With Tiered Storage, local partition size, in bytes, above which uploaded segments are removed; -1 for no limit",
+	},
+	"retention.local.target.ms": {
+		"86400000",
+		"With Tiered Storage, age, in ms, above which uploaded segments are removed locally",
+	},
+	"retention.ms": {
+		"604800000",
+		"Age, in ms, above which old segments are removed; -1 for no limit",
+	},
+	"segment.bytes": {
+		"1073741824",
+		"Size, in bytes, of the segments of each partition",
+	},
+	"segment.ms": {
+		"",
+		"Age, in ms, above which the active segment is rolled",
+	},
+}
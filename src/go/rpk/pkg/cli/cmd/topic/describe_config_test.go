@@ -0,0 +1,73 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestDescribeConfigRows(t *testing.T) {
+	configs := []kmsg.DescribeConfigsResponseResourceConfig{
+		{
+			Name:   "cleanup.policy",
+			Value:  kmsg.StringPtr("compact"),
+			Source: kmsg.ConfigSourceDynamicTopicConfig,
+			ConfigSynonyms: []kmsg.DescribeConfigsResponseResourceConfigConfigSynonym{
+				{Name: "cleanup.policy", Value: kmsg.StringPtr("compact"), Source: kmsg.ConfigSourceDynamicTopicConfig},
+				{Name: "log_cleanup_policy", Value: kmsg.StringPtr("delete"), Source: kmsg.ConfigSourceDefaultConfig},
+			},
+		},
+		{
+			Name:          "retention.ms",
+			Value:         kmsg.StringPtr("604800000"),
+			Source:        kmsg.ConfigSourceDefaultConfig,
+			Documentation: kmsg.StringPtr("Retention time"),
+		},
+		{
+			Name:   "segment.bytes",
+			Value:  kmsg.StringPtr("134217728"),
+			Source: kmsg.ConfigSourceStaticBrokerConfig,
+		},
+		{
+			Name:   "unknown.config",
+			Source: kmsg.ConfigSourceDynamicBrokerConfig,
+		},
+	}
+
+	for _, test := range []struct {
+		name          string
+		onlyOverrides bool
+		exp           [][]interface{}
+	}{
+		{
+			name: "all configs",
+			exp: [][]interface{}{
+				{"cleanup.policy", "compact", "dynamic", "delete", topicConfigDocs["cleanup.policy"].desc},
+				{"retention.ms", "604800000", "default", "604800000", "Retention time"},
+				{"segment.bytes", "134217728", "static", "1073741824", topicConfigDocs["segment.bytes"].desc},
+				{"unknown.config", "", "dynamic", "-", ""},
+			},
+		},
+		{
+			name:          "only overrides",
+			onlyOverrides: true,
+			exp: [][]interface{}{
+				{"cleanup.policy", "compact", "dynamic", "delete", topicConfigDocs["cleanup.policy"].desc},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, describeConfigRows(configs, test.onlyOverrides))
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	// defaultProbeTopic is the topic that the probe records are produced
+	// to, created on the first probe.
+	defaultProbeTopic = "_rpk_probe"
+
+	// probeHeader marks the probe records with the ID of their probe, so
+	// that a probe does not mistake the records of another for its own.
+	probeHeader = "rpk-probe"
+
+	// probeRetention keeps the probe topic small.
+	probeRetention = "3600000" // 1h
+)
+
+// partitionProbe is the outcome of probing one partition of the probe topic.
+type partitionProbe struct {
+	partition int32
+	leader    int32
+
+	start     time.Time
+	produce   time.Duration // until the record is acknowledged
+	roundTrip time.Duration // until the record is consumed back
+	err       error
+}
+
+// probeTopicHealth produces a record to every partition of the probe topic
+// and consumes it back, timing the produce request and the round trip. Each
+// partition is led by a single broker, so the latencies are those of its
+// leader. The probe topic is created with a partition per broker if it does
+// not exist.
+func probeTopicHealth(
+	ctx context.Context,
+	fs afero.Fs,
+	p *config.Params,
+	cfg *config.Config,
+	adm *kadm.Client,
+	topic string,
+) ([]partitionProbe, error) {
+	detail, err := ensureProbeTopic(ctx, adm, topic)
+	if err != nil {
+		return nil, err
+	}
+	ends, err := adm.ListEndOffsets(ctx, topic)
+	if err == nil {
+		err = ends.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the end offsets of the probe topic %q: %w", topic, err)
+	}
+
+	// The records are consumed from the current end offsets, which the
+	// consumer knows before the records are produced.
+	consumeAt := make(map[int32]kgo.Offset)
+	probes := make(map[int32]*partitionProbe)
+	ends.Each(func(o kadm.ListedOffset) {
+		consumeAt[o.Partition] = kgo.NewOffset().At(o.Offset)
+		probes[o.Partition] = &partitionProbe{
+			partition: o.Partition,
+			leader:    detail.Partitions[o.Partition].Leader,
+		}
+	})
+	cl, err := kafka.NewFranzClient(fs, p, cfg,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: consumeAt}),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize kafka client: %w", err)
+	}
+	defer cl.Close()
+
+	id := []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	var mu sync.Mutex
+	for _, probe := range probes {
+		probe := probe
+		probe.start = time.Now()
+		cl.Produce(ctx, &kgo.Record{
+			Topic:     topic,
+			Partition: probe.partition,
+			Headers:   []kgo.RecordHeader{{Key: probeHeader, Value: id}},
+			Value:     []byte("rpk topic health probe"),
+		}, func(_ *kgo.Record, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			probe.produce = time.Since(probe.start)
+			if err != nil && probe.err == nil {
+				probe.err = fmt.Errorf("unable to produce: %w", err)
+			}
+		})
+	}
+
+	// A probe ends once its record is consumed back or it fails. The polls
+	// are short so that the failures of the producer are noticed.
+	done := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, probe := range probes {
+			if probe.roundTrip == 0 && probe.err == nil {
+				return false
+			}
+		}
+		return true
+	}
+	for !done() && ctx.Err() == nil {
+		pollCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		fetches := cl.PollFetches(pollCtx)
+		cancel()
+		received := time.Now()
+		mu.Lock()
+		fetches.EachError(func(_ string, partition int32, err error) {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				return
+			}
+			if probe, ok := probes[partition]; ok && probe.err == nil {
+				probe.err = fmt.Errorf("unable to consume: %w", err)
+			}
+		})
+		fetches.EachRecord(func(r *kgo.Record) {
+			probe, ok := probes[r.Partition]
+			if ok && probe.roundTrip == 0 && isProbeRecord(r, id) {
+				probe.roundTrip = received.Sub(probe.start)
+			}
+		})
+		mu.Unlock()
+	}
+	// Wait for the acknowledgements of the records that were consumed
+	// before their produce request returned.
+	cl.Flush(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	results := make([]partitionProbe, 0, len(probes))
+	for _, probe := range probes {
+		if probe.roundTrip == 0 && probe.err == nil {
+			probe.err = errors.New("the probe record was not consumed before the timeout")
+		}
+		results = append(results, *probe)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].leader != results[j].leader {
+			return results[i].leader < results[j].leader
+		}
+		return results[i].partition < results[j].partition
+	})
+	return results, nil
+}
+
+func isProbeRecord(r *kgo.Record, id []byte) bool {
+	for _, h := range r.Headers {
+		if h.Key == probeHeader {
+			return string(h.Value) == string(id)
+		}
+	}
+	return false
+}
+
+// ensureProbeTopic returns the probe topic, creating it with a partition
+// per broker if it does not exist. The partitions of a new topic are spread
+// across the brokers, but their leaders may not be: brokers leading no
+// partition of the probe topic are not probed.
+func ensureProbeTopic(
+	ctx context.Context, adm *kadm.Client, topic string,
+) (kadm.TopicDetail, error) {
+	details, err := adm.ListTopics(ctx, topic)
+	if err != nil {
+		return kadm.TopicDetail{}, fmt.Errorf("unable to describe the probe topic %q: %w", topic, err)
+	}
+	d, ok := details[topic]
+	if ok && d.Err == nil {
+		return d, nil
+	}
+	if ok && !errors.Is(d.Err, kerr.UnknownTopicOrPartition) {
+		return kadm.TopicDetail{}, fmt.Errorf("unable to describe the probe topic %q: %w", topic, d.Err)
+	}
+
+	brokers, err := adm.ListBrokers(ctx)
+	if err != nil {
+		return kadm.TopicDetail{}, fmt.Errorf("unable to list brokers: %w", err)
+	}
+	replicas := int16(len(brokers))
+	if replicas > 3 {
+		replicas = 3
+	}
+	retention := probeRetention
+	created, err := adm.CreateTopics(ctx, int32(len(brokers)), replicas, map[string]*string{"retention.ms": &retention}, topic)
+	if err == nil && !errors.Is(created[topic].Err, kerr.TopicAlreadyExists) {
+		err = created[topic].Err
+	}
+	if err != nil {
+		return kadm.TopicDetail{}, fmt.Errorf("unable to create the probe topic %q: %w", topic, err)
+	}
+
+	// The partitions of a new topic have no leader for a short while.
+	for {
+		details, err := adm.ListTopics(ctx, topic)
+		if err == nil {
+			err = details[topic].Err
+		}
+		if err == nil && hasLeaders(details[topic]) {
+			return details[topic], nil
+		}
+		select {
+		case <-ctx.Done():
+			return kadm.TopicDetail{}, fmt.Errorf("the partitions of the probe topic %q have no leader yet: %w", topic, ctx.Err())
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+func hasLeaders(d kadm.TopicDetail) bool {
+	if len(d.Partitions) == 0 {
+		return false
+	}
+	for _, p := range d.Partitions {
+		if p.Leader < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func printTopicProbes(probes []partitionProbe) {
+	tw := out.NewTable("BROKER", "PARTITION", "PRODUCE-LATENCY", "ROUND-TRIP-LATENCY", "STATUS")
+	defer tw.Flush()
+	for _, p := range probes {
+		status := "OK"
+		if p.err != nil {
+			status = p.err.Error()
+		}
+		produce, roundTrip := "-", "-"
+		if p.produce > 0 {
+			produce = p.produce.Round(time.Microsecond).String()
+		}
+		if p.roundTrip > 0 {
+			roundTrip = p.roundTrip.Round(time.Microsecond).String()
+		}
+		tw.Print(p.leader, p.partition, produce, roundTrip, status)
+	}
+}
+
+// writeTopicProbeMetrics writes the outcome of the probes in the Prometheus
+// text exposition format.
+func writeTopicProbeMetrics(w io.Writer, topic string, probes []partitionProbe, success bool) {
+	gauge := func(name, help string, value func(partitionProbe) (float64, bool)) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, p := range probes {
+			if v, ok := value(p); ok {
+				fmt.Fprintf(w, "%s{topic=\"%s\",broker=\"%d\",partition=\"%d\"} %g\n",
+					name, probeLabelEscaper.Replace(topic), p.leader, p.partition, v)
+			}
+		}
+	}
+
+	gauge("rpk_topic_probe_produce_latency_seconds", "Latency of the produce request of the last probe record.",
+		func(p partitionProbe) (float64, bool) { return p.produce.Seconds(), p.produce > 0 && p.err == nil })
+	gauge("rpk_topic_probe_round_trip_latency_seconds", "Latency from producing the last probe record to consuming it back.",
+		func(p partitionProbe) (float64, bool) { return p.roundTrip.Seconds(), p.roundTrip > 0 && p.err == nil })
+	gauge("rpk_topic_probe_success", "Whether the last probe record was produced and consumed back.",
+		func(p partitionProbe) (float64, bool) {
+			if p.err != nil {
+				return 0, true
+			}
+			return 1, true
+		})
+
+	var ok int
+	if success {
+		ok = 1
+	}
+	fmt.Fprintf(w, "# HELP rpk_topic_probe_scrape_success Whether the probe could run.\n")
+	fmt.Fprintf(w, "# TYPE rpk_topic_probe_scrape_success gauge\n")
+	fmt.Fprintf(w, "rpk_topic_probe_scrape_success %d\n", ok)
+}
+
+var probeLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,51 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestIsProbeRecord(t *testing.T) {
+	id := []byte("abc")
+	require.True(t, isProbeRecord(&kgo.Record{Headers: []kgo.RecordHeader{{Key: "k", Value: []byte("v")}, {Key: probeHeader, Value: id}}}, id))
+	require.False(t, isProbeRecord(&kgo.Record{Headers: []kgo.RecordHeader{{Key: probeHeader, Value: []byte("other")}}}, id))
+	require.False(t, isProbeRecord(&kgo.Record{}, id))
+}
+
+func TestWriteTopicProbeMetrics(t *testing.T) {
+	probes := []partitionProbe{
+		{partition: 0, leader: 1, produce: 2 * time.Millisecond, roundTrip: 5 * time.Millisecond},
+		{partition: 1, leader: 2, produce: 3 * time.Second, err: errors.New("unable to produce: timeout")},
+	}
+	var buf bytes.Buffer
+	writeTopicProbeMetrics(&buf, "_rpk_probe", probes, true)
+	exp := `# HELP rpk_topic_probe_produce_latency_seconds Latency of the produce request of the last probe record.
+# TYPE rpk_topic_probe_produce_latency_seconds gauge
+rpk_topic_probe_produce_latency_seconds{topic="_rpk_probe",broker="1",partition="0"} 0.002
+# HELP rpk_topic_probe_round_trip_latency_seconds Latency from producing the last probe record to consuming it back.
+# TYPE rpk_topic_probe_round_trip_latency_seconds gauge
+rpk_topic_probe_round_trip_latency_seconds{topic="_rpk_probe",broker="1",partition="0"} 0.005
+# HELP rpk_topic_probe_success Whether the last probe record was produced and consumed back.
+# TYPE rpk_topic_probe_success gauge
+rpk_topic_probe_success{topic="_rpk_probe",broker="1",partition="0"} 1
+rpk_topic_probe_success{topic="_rpk_probe",broker="2",partition="1"} 0
+# HELP rpk_topic_probe_scrape_success Whether the probe could run.
+# TYPE rpk_topic_probe_scrape_success gauge
+rpk_topic_probe_scrape_success 1
+`
+	require.Equal(t, exp, buf.String())
+}
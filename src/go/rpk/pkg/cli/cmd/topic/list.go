@@ -10,7 +10,12 @@
 package topic
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -18,6 +23,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
 )
 
 func newListCommand(fs afero.Fs) *cobra.Command {
@@ -25,6 +31,11 @@ func newListCommand(fs afero.Fs) *cobra.Command {
 		detailed bool
 		internal bool
 		re       bool
+
+		health     bool
+		probeTopic string
+		exporter   string
+		timeout    time.Duration
 	)
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -46,8 +57,30 @@ expressions are wrapped with ^ and $ so that the expression must match the
 whole topic name. Regular expressions cannot be used to match internal topics,
 as such, specifying both -i and -r will exit with failure.
 
-Lastly, --detailed flag (-d) opts in to printing extra per-partition
+The --detailed flag (-d) opts in to printing extra per-partition
 information.
+
+Lastly, the --health flag opts in to an end-to-end check of the cluster after
+the listing: a record is produced to every partition of a probe topic and
+consumed back, timing the produce request and the round trip. The partitions
+of the probe topic are spread across the brokers, so the latencies are
+reported per broker, the leader of the partition. The probe topic (_rpk_probe
+by default, see --probe-topic) is created with a partition per broker if it
+does not exist, and keeps its records for an hour. Its records are marked with
+a header, so that concurrent probes do not mistake each other's records.
+
+With --health and --exporter, this command instead runs an HTTP server on the
+given address that probes the cluster on every scrape and exports the outcome
+in the Prometheus exposition format on /metrics:
+
+    rpk_topic_probe_produce_latency_seconds{topic,broker,partition}
+    rpk_topic_probe_round_trip_latency_seconds{topic,broker,partition}
+    rpk_topic_probe_success{topic,broker,partition}
+    rpk_topic_probe_scrape_success
+
+For example, to probe the cluster on every scrape of port 9103:
+
+    rpk topic list --health --exporter :9103
 `,
 		Run: func(cmd *cobra.Command, topics []string) {
 			// The purpose of the regex flag really is for users to
@@ -58,6 +91,9 @@ information.
 			if internal && re {
 				out.Exit("cannot list with internal topics and list by regular expression")
 			}
+			if exporter != "" && !health {
+				out.Exit("--exporter requires --health")
+			}
 
 			p := config.ParamsFromCommand(cmd)
 			cfg, err := p.Load(fs)
@@ -67,6 +103,11 @@ information.
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
 			defer adm.Close()
 
+			if exporter != "" {
+				serveTopicProbes(fs, p, cfg, adm, probeTopic, exporter, timeout)
+				return
+			}
+
 			if re {
 				topics, err = regexTopics(adm, topics)
 				out.MaybeDie(err, "unable to filter topics by regex: %v", err)
@@ -75,11 +116,59 @@ information.
 			listed, err := adm.ListTopicsWithInternal(context.Background(), topics...)
 			out.MaybeDie(err, "unable to request metadata: %v", err)
 			cluster.PrintTopics(listed, internal, detailed)
+
+			if health {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				defer cancel()
+				probes, err := probeTopicHealth(ctx, fs, p, cfg, adm, probeTopic)
+				out.MaybeDie(err, "unable to probe the cluster: %v", err)
+				fmt.Println()
+				out.Section("health")
+				printTopicProbes(probes)
+				for _, probe := range probes {
+					if probe.err != nil {
+						os.Exit(1)
+					}
+				}
+			}
 		},
 	}
 
 	cmd.Flags().BoolVarP(&detailed, "detailed", "d", false, "Print per-partition information for topics")
 	cmd.Flags().BoolVarP(&internal, "internal", "i", false, "Print internal topics")
 	cmd.Flags().BoolVarP(&re, "regex", "r", false, "Parse topics as regex; list any topic that matches any input topic expression")
+	cmd.Flags().BoolVar(&health, "health", false, "Probe the cluster by producing a record to every partition of the probe topic and consuming it back")
+	cmd.Flags().StringVar(&probeTopic, "probe-topic", defaultProbeTopic, "Topic to produce the probe records to, created if it does not exist")
+	cmd.Flags().StringVar(&exporter, "exporter", "", "Address to export the probe on in the Prometheus format (e.g. :9103), probing on every scrape rather than once")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "Timeout of the probe (or of each scrape, with --exporter)")
 	return cmd
 }
+
+// serveTopicProbes serves the probes of the cluster in the Prometheus format,
+// probing on every scrape.
+func serveTopicProbes(
+	fs afero.Fs,
+	p *config.Params,
+	cfg *config.Config,
+	adm *kadm.Client,
+	topic, addr string,
+	timeout time.Duration,
+) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		probes, err := probeTopicHealth(ctx, fs, p, cfg, adm, topic)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to probe the cluster: %v\n", err)
+		}
+		var buf bytes.Buffer
+		writeTopicProbeMetrics(&buf, topic, probes, err == nil)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(buf.Bytes())
+	})
+	fmt.Printf("Exporting the probes of topic %q on http://%s/metrics\n", topic, addr)
+	srv := &http.Server{Addr: addr, Handler: mux}
+	err := srv.ListenAndServe()
+	out.MaybeDie(err, "unable to serve probe metrics: %v", err)
+}
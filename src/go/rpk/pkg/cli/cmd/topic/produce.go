@@ -39,6 +39,10 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 		tombstone bool
 
 		timeout time.Duration
+
+		rateLimit string
+		burst     int
+		duration  time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -65,7 +69,7 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 			case "zstd":
 				opts = append(opts, kgo.ProducerBatchCompression(kgo.ZstdCompression()))
 			default:
-				out.Die("invalid compression codec %q", compression)
+				out.DieUsage("invalid compression codec %q", compression)
 			}
 
 			switch acks {
@@ -76,13 +80,13 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 			case 1:
 				opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
 			default:
-				out.Die("invalid acks %d, only -1, 0, and 1 are supported", acks)
+				out.DieUsage("invalid acks %d, only -1, 0, and 1 are supported", acks)
 			}
 
 			switch {
 			case timeout == 0:
 			case timeout < time.Second:
-				out.Die("invalid --delivery-timeout less than 1s")
+				out.DieUsage("invalid --delivery-timeout less than 1s")
 			default:
 				opts = append(opts, kgo.RecordDeliveryTimeout(timeout))
 			}
@@ -95,7 +99,16 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 				opts = append(opts, kgo.DefaultProduceTopic(defaultTopic))
 			}
 			if len(inFormat) == 0 {
-				out.Die("invalid empty format")
+				out.DieUsage("invalid empty format")
+			}
+			var limiter *produceLimiter
+			if rateLimit != "" {
+				var err error
+				limiter, err = newProduceLimiter(rateLimit, burst)
+				out.MaybeDieErr(err)
+			}
+			if duration < 0 {
+				out.DieUsage("invalid negative --duration")
 			}
 
 			// Parse our input/output formats.
@@ -124,6 +137,17 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 			cl, err := kafka.NewFranzClient(fs, p, cfg, opts...)
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
 			defer cl.Close()
+
+			ctx := context.Background()
+			if duration > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, duration)
+				defer cancel()
+			}
+			var stats produceStats
+			if limiter != nil || duration > 0 {
+				defer stats.print(time.Now())
+			}
 			defer cl.Flush(context.Background())
 
 			for {
@@ -141,13 +165,24 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 					return
 				}
 				if r.Topic == "" && defaultTopic == "" {
-					out.Die("topic to produce to is missing, check --help for produce syntax")
+					out.DieUsage("topic to produce to is missing, check --help for produce syntax")
 				}
 				if tombstone && len(r.Value) == 0 {
 					r.Value = nil
 				}
+				if limiter != nil {
+					// The limiter fails early if the wait would
+					// outlast the duration.
+					if err := limiter.wait(ctx, r); err != nil {
+						return
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
 				cl.Produce(context.Background(), r, func(r *kgo.Record, err error) {
 					out.MaybeDie(err, "unable to produce record: %v", err)
+					stats.add(r)
 					if outf != nil {
 						outfBuf = outf.AppendRecord(outfBuf[:0], r)
 						os.Stdout.Write(outfBuf)
@@ -175,6 +210,10 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 	cmd.Flags().StringVarP(&key, "key", "k", "", "A fixed key to use for each record (parsed input keys take precedence)")
 	cmd.Flags().BoolVarP(&tombstone, "tombstone", "Z", false, "Produce empty values as tombstones")
 
+	cmd.Flags().StringVar(&rateLimit, "rate", "", "Maximum rate to produce at, in records per second, or bytes per second with a size suffix (e.g. 10MiB)")
+	cmd.Flags().IntVar(&burst, "burst", 0, "Records or bytes that can be produced at once above --rate (default one second of traffic)")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "Stop producing after this duration, if non-zero")
+
 	// Deprecated
 	cmd.Flags().IntVarP(new(int), "num", "n", 1, "")
 	cmd.Flags().MarkDeprecated("num", "Invoke rpk multiple times if you wish to repeat records")
@@ -310,4 +349,21 @@ requirement for being able to directly control which partition to produce to.
 You can also specify an output format to write when a record is produced
 successfully. The output format follows the same formatting rules as the topic
 consume command. See that command's help text for a detailed description.
+
+TRAFFIC SHAPING
+
+The --rate flag limits how fast records are produced, either in records per
+second (--rate 1000), or in bytes of keys, values and headers per second
+(--rate 10MiB). Up to --burst records or bytes can be produced at once above
+the rate, which defaults to one second of traffic. The --duration flag stops
+producing after the given duration, even if more input is available.
+
+Combined with an endless input, this turns produce into a load generator for
+capacity tests and quota verification. For example, to produce one hundred
+records per second for five minutes:
+
+    yes | rpk topic produce foo --rate 100 --duration 5m -o ""
+
+When --rate or --duration is used, a summary of what was produced is printed
+to STDERR when producing stops.
 `
@@ -0,0 +1,110 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"golang.org/x/time/rate"
+)
+
+// produceLimiter shapes produced traffic to a rate of records or bytes per
+// second, with a token bucket allowing bursts above the rate.
+type produceLimiter struct {
+	l     *rate.Limiter
+	bytes bool
+}
+
+// newProduceLimiter parses a --rate, which is a number of records per second
+// or, with a size suffix, a number of bytes per second (e.g. 1MiB). The burst
+// is in the same unit as the rate, and defaults to one second of traffic.
+func newProduceLimiter(s string, burst int) (*produceLimiter, error) {
+	var (
+		limit float64
+		bytes bool
+		err   error
+	)
+	if strings.HasSuffix(strings.ToUpper(s), "B") {
+		var size int64
+		size, err = units.RAMInBytes(s)
+		limit, bytes = float64(size), true
+	} else {
+		limit, err = strconv.ParseFloat(s, 64)
+	}
+	if err != nil || limit <= 0 {
+		return nil, fmt.Errorf("invalid --rate %q, must be a positive number of records or bytes (e.g. 1MiB) per second", s)
+	}
+	if burst < 0 {
+		return nil, fmt.Errorf("invalid --burst %d, must be positive", burst)
+	}
+	if burst == 0 {
+		burst = int(limit)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return &produceLimiter{rate.NewLimiter(rate.Limit(limit), burst), bytes}, nil
+}
+
+// wait blocks until the record can be produced without exceeding the rate,
+// or the context is done. Records larger than the burst wait for a full
+// burst.
+func (p *produceLimiter) wait(ctx context.Context, r *kgo.Record) error {
+	n := 1
+	if p.bytes {
+		n = recordSize(r)
+		if burst := p.l.Burst(); n > burst {
+			n = burst
+		}
+	}
+	return p.l.WaitN(ctx, n)
+}
+
+// recordSize returns the number of bytes of the key, value and headers of
+// the record, which is what byte rates account for.
+func recordSize(r *kgo.Record) int {
+	n := len(r.Key) + len(r.Value)
+	for _, h := range r.Headers {
+		n += len(h.Key) + len(h.Value)
+	}
+	return n
+}
+
+// produceStats counts the records successfully produced.
+type produceStats struct {
+	records int64
+	bytes   int64
+}
+
+// add counts a produced record; it is called from produce promises, which
+// are serialized.
+func (s *produceStats) add(r *kgo.Record) {
+	s.records++
+	s.bytes += int64(recordSize(r))
+}
+
+func (s *produceStats) print(start time.Time) {
+	elapsed := time.Since(start)
+	secs := elapsed.Seconds()
+	fmt.Fprintf(os.Stderr, "Produced %d records (%s) in %v, %.1f records/s (%s/s).\n",
+		s.records,
+		units.BytesSize(float64(s.bytes)),
+		elapsed.Round(time.Millisecond),
+		float64(s.records)/secs,
+		units.BytesSize(float64(s.bytes)/secs),
+	)
+}
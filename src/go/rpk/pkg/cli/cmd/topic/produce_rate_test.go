@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"golang.org/x/time/rate"
+)
+
+func TestNewProduceLimiter(t *testing.T) {
+	for _, test := range []struct {
+		rate  string
+		burst int
+
+		expLimit rate.Limit
+		expBurst int
+		expBytes bool
+		expErr   bool
+	}{
+		{rate: "100", expLimit: 100, expBurst: 100},
+		{rate: "0.5", expLimit: 0.5, expBurst: 1},
+		{rate: "100", burst: 10, expLimit: 100, expBurst: 10},
+		{rate: "1MiB", expLimit: 1 << 20, expBurst: 1 << 20, expBytes: true},
+		{rate: "10kb", burst: 4096, expLimit: 10 << 10, expBurst: 4096, expBytes: true},
+
+		{rate: "0", expErr: true},
+		{rate: "-3", expErr: true},
+		{rate: "fast", expErr: true},
+		{rate: "MiB", expErr: true},
+		{rate: "100", burst: -1, expErr: true},
+	} {
+		t.Run(test.rate, func(t *testing.T) {
+			l, err := newProduceLimiter(test.rate, test.burst)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expLimit, l.l.Limit())
+			require.Equal(t, test.expBurst, l.l.Burst())
+			require.Equal(t, test.expBytes, l.bytes)
+		})
+	}
+}
+
+func TestRecordSize(t *testing.T) {
+	r := &kgo.Record{
+		Key:     []byte("key"),
+		Value:   []byte("value"),
+		Headers: []kgo.RecordHeader{{Key: "h", Value: []byte("vv")}},
+	}
+	require.Equal(t, 11, recordSize(r))
+
+	var s produceStats
+	s.add(r)
+	s.add(&kgo.Record{Value: []byte("v")})
+	require.Equal(t, produceStats{records: 2, bytes: 12}, s)
+}
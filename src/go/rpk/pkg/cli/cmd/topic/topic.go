@@ -37,10 +37,12 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 	command.AddCommand(
 		newAddPartitionsCommand(fs),
 		newAlterConfigCommand(fs),
+		newCloneCommand(fs),
 		newConsumeCommand(fs),
 		newCreateCommand(fs),
 		newDeleteCommand(fs),
 		newDescribeCommand(fs),
+		newDescribeConfigCommand(fs),
 		newListCommand(fs),
 		newProduceCommand(fs),
 	)
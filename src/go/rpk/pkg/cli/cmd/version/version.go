@@ -20,6 +20,12 @@ var (
 	rev     string
 )
 
+// Version returns the version rpk was built with, which is empty for
+// development builds.
+func Version() string {
+	return version
+}
+
 func Pretty() string {
 	return fmt.Sprintf("%s (rev %s)", version, rev)
 }
@@ -32,7 +32,7 @@ func newDeployCommand(fs afero.Fs) *cobra.Command {
 
 			path := args[0]
 			if filepath.Ext(path) != ".js" {
-				out.Die("cannot deploy %q: only .js files are supported", path)
+				out.DieUsage("cannot deploy %q: only .js files are supported", path)
 			}
 
 			err = checkCoprocType(coprocType)
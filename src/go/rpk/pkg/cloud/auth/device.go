@@ -0,0 +1,252 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package auth implements the OAuth 2.0 device authorization flow used by
+// "rpk cloud login", and the storage of the resulting tokens.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Endpoints configures the authorization server to log in against.
+type Endpoints struct {
+	// AuthURL is the base URL of the authorization server; the device
+	// code and token endpoints are AuthURL/oauth/device/code and
+	// AuthURL/oauth/token.
+	AuthURL string `json:"auth_url"`
+	// ClientID is the public OAuth client ID of rpk.
+	ClientID string `json:"client_id"`
+	// Audience is the API the tokens are requested for, if the server
+	// needs one.
+	Audience string `json:"audience,omitempty"`
+}
+
+// DeviceCode is the response of the device authorization request (RFC 8628
+// section 3.2). The user has to visit VerificationURI and enter UserCode,
+// or visit VerificationURIComplete, before the code expires.
+type DeviceCode struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresAt               time.Time
+	// Interval is how long to wait between token requests.
+	Interval time.Duration
+}
+
+// Token is an access token, and the refresh token to renew it if the server
+// issued one.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Valid returns whether the access token is set and does not expire within
+// the next minute.
+func (t Token) Valid() bool {
+	return t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(time.Minute).Before(t.Expiry))
+}
+
+// Errors returned while polling for a token when the user did not complete
+// the login in time, or denied it.
+var (
+	ErrExpiredToken = errors.New("the device code expired before the login was completed")
+	ErrAccessDenied = errors.New("the login was denied")
+)
+
+// defaultInterval is the polling interval when the server does not specify
+// one, per RFC 8628.
+const defaultInterval = 5 * time.Second
+
+// Client performs requests against the authorization server.
+type Client struct {
+	Endpoints Endpoints
+	HTTP      *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// RequestDeviceCode starts a device authorization.
+func (c *Client) RequestDeviceCode(ctx context.Context) (DeviceCode, error) {
+	form := url.Values{
+		"client_id": []string{c.Endpoints.ClientID},
+		// offline_access asks for a refresh token, so that the user does
+		// not have to log in again when the access token expires.
+		"scope": []string{"openid offline_access"},
+	}
+	if c.Endpoints.Audience != "" {
+		form.Set("audience", c.Endpoints.Audience)
+	}
+	var resp struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	if err := c.post(ctx, "/oauth/device/code", form, &resp); err != nil {
+		return DeviceCode{}, fmt.Errorf("unable to request a device code: %w", err)
+	}
+	if resp.DeviceCode == "" || resp.VerificationURI == "" {
+		return DeviceCode{}, errors.New("unable to request a device code: the response is missing the device code or the verification URI")
+	}
+	dc := DeviceCode{
+		DeviceCode:              resp.DeviceCode,
+		UserCode:                resp.UserCode,
+		VerificationURI:         resp.VerificationURI,
+		VerificationURIComplete: resp.VerificationURIComplete,
+		ExpiresAt:               time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+		Interval:                time.Duration(resp.Interval) * time.Second,
+	}
+	if dc.Interval <= 0 {
+		dc.Interval = defaultInterval
+	}
+	return dc, nil
+}
+
+// PollToken waits for the user to complete the login of the device code,
+// and returns the issued token.
+func (c *Client) PollToken(ctx context.Context, dc DeviceCode) (Token, error) {
+	form := url.Values{
+		"grant_type":  []string{"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": []string{dc.DeviceCode},
+		"client_id":   []string{c.Endpoints.ClientID},
+	}
+	interval := dc.Interval
+	for {
+		select {
+		case <-ctx.Done():
+			return Token{}, ctx.Err()
+		case <-time.After(interval):
+		}
+		if !dc.ExpiresAt.IsZero() && time.Now().After(dc.ExpiresAt) {
+			return Token{}, ErrExpiredToken
+		}
+
+		t, err := c.token(ctx, form)
+		var oe *oauthError
+		if !errors.As(err, &oe) {
+			return t, err
+		}
+		switch oe.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += defaultInterval
+		case "expired_token":
+			return Token{}, ErrExpiredToken
+		case "access_denied":
+			return Token{}, ErrAccessDenied
+		default:
+			return Token{}, err
+		}
+	}
+}
+
+// Refresh exchanges a refresh token for a new token. If the server does not
+// issue a new refresh token, the previous one is kept.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (Token, error) {
+	t, err := c.token(ctx, url.Values{
+		"grant_type":    []string{"refresh_token"},
+		"refresh_token": []string{refreshToken},
+		"client_id":     []string{c.Endpoints.ClientID},
+	})
+	if err != nil {
+		return Token{}, fmt.Errorf("unable to refresh the access token: %w", err)
+	}
+	if t.RefreshToken == "" {
+		t.RefreshToken = refreshToken
+	}
+	return t, nil
+}
+
+func (c *Client) token(ctx context.Context, form url.Values) (Token, error) {
+	var resp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := c.post(ctx, "/oauth/token", form, &resp); err != nil {
+		return Token{}, err
+	}
+	if resp.AccessToken == "" {
+		return Token{}, errors.New("the token response is missing the access token")
+	}
+	t := Token{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		TokenType:    resp.TokenType,
+	}
+	if resp.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return t, nil
+}
+
+// oauthError is an error response of the authorization server (RFC 6749
+// section 5.2).
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+func (c *Client) post(ctx context.Context, path string, form url.Values, into interface{}) error {
+	u := strings.TrimSuffix(c.Endpoints.AuthURL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read the response of %s: %w", u, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		oe := new(oauthError)
+		if json.Unmarshal(body, oe) == nil && oe.Code != "" {
+			return oe
+		}
+		return fmt.Errorf("%s returned %s: %s", u, resp.Status, body)
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("unable to decode the response of %s: %w", u, err)
+	}
+	return nil
+}
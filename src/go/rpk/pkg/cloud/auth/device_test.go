@@ -0,0 +1,92 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	var polls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "rpk", r.PostForm.Get("client_id"))
+		switch r.URL.Path {
+		case "/oauth/device/code":
+			require.Equal(t, "api", r.PostForm.Get("audience"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"device_code":      "dev",
+				"user_code":        "ABCD-EFGH",
+				"verification_uri": "https://example.com/activate",
+				"expires_in":       60,
+			})
+		case "/oauth/token":
+			if r.PostForm.Get("grant_type") == "refresh_token" {
+				require.Equal(t, "refresh", r.PostForm.Get("refresh_token"))
+				json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "renewed", "expires_in": 3600})
+				return
+			}
+			require.Equal(t, "dev", r.PostForm.Get("device_code"))
+			if atomic.AddInt32(&polls, 1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "access",
+				"refresh_token": "refresh",
+				"expires_in":    3600,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	cl := &Client{Endpoints: Endpoints{AuthURL: ts.URL, ClientID: "rpk", Audience: "api"}}
+	dc, err := cl.RequestDeviceCode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ABCD-EFGH", dc.UserCode)
+	require.Equal(t, defaultInterval, dc.Interval)
+
+	dc.Interval = time.Millisecond
+	tok, err := cl.PollToken(ctx, dc)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&polls))
+	require.Equal(t, "access", tok.AccessToken)
+	require.True(t, tok.Valid())
+
+	tok, err = cl.Refresh(ctx, "refresh")
+	require.NoError(t, err)
+	require.Equal(t, "renewed", tok.AccessToken)
+	require.Equal(t, "refresh", tok.RefreshToken)
+}
+
+func TestPollTokenDenied(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": "access_denied"})
+	}))
+	defer ts.Close()
+
+	cl := &Client{Endpoints: Endpoints{AuthURL: ts.URL}}
+	_, err := cl.PollToken(context.Background(), DeviceCode{Interval: time.Millisecond})
+	require.True(t, errors.Is(err, ErrAccessDenied))
+}
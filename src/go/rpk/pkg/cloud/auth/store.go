@@ -0,0 +1,289 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ErrNotLoggedIn is returned when no session is stored.
+var ErrNotLoggedIn = errors.New("not logged in, please run 'rpk cloud login'")
+
+// Session is what "rpk cloud login" stores: the token, and the servers it
+// is for.
+type Session struct {
+	Endpoints Endpoints `json:"endpoints"`
+	// APIURL is the base URL of the cloud API the token is used with.
+	APIURL string `json:"api_url"`
+	Token  Token  `json:"token"`
+}
+
+// Store persists the session between rpk invocations.
+type Store interface {
+	// Load returns the stored session, or ErrNotLoggedIn.
+	Load() (Session, error)
+	// Save replaces the stored session.
+	Save(Session) error
+	// Delete removes the stored session, if any.
+	Delete() error
+	// String describes where the session is stored.
+	String() string
+}
+
+const (
+	keychainService = "rpk-cloud"
+	keychainAccount = "default"
+)
+
+// NewStore returns the OS keychain store where rpk can use one: the login
+// keychain on macOS, or the Secret Service through secret-tool on Linux
+// desktops. Otherwise, the session is stored in a file only readable by the
+// user, in the rpk directory of the user config directory.
+func NewStore(fs afero.Fs) (Store, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return &keychainStore{macOSKeychain, runCommand}, nil
+		}
+	case "linux":
+		// secret-tool needs a session bus to reach the keyring.
+		if _, err := exec.LookPath("secret-tool"); err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+			return &keychainStore{secretService, runCommand}, nil
+		}
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find a directory to store the session in: %w", err)
+	}
+	return &FileStore{fs, filepath.Join(dir, "rpk", "cloud-session.json")}, nil
+}
+
+// FileStore stores the session in a file with 0600 permissions.
+type FileStore struct {
+	Fs   afero.Fs
+	Path string
+}
+
+func (s *FileStore) Load() (Session, error) {
+	raw, err := afero.ReadFile(s.Fs, s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Session{}, ErrNotLoggedIn
+		}
+		return Session{}, fmt.Errorf("unable to read %s: %w", s.Path, err)
+	}
+	return decodeSession(raw)
+}
+
+func (s *FileStore) Save(session Session) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if err := s.Fs.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("unable to create %s: %w", filepath.Dir(s.Path), err)
+	}
+	if err := afero.WriteFile(s.Fs, s.Path, raw, 0o600); err != nil {
+		return fmt.Errorf("unable to write %s: %w", s.Path, err)
+	}
+	// WriteFile does not change the permissions of an existing file.
+	return s.Fs.Chmod(s.Path, 0o600)
+}
+
+func (s *FileStore) Delete() error {
+	if err := s.Fs.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove %s: %w", s.Path, err)
+	}
+	return nil
+}
+
+func (s *FileStore) String() string { return s.Path }
+
+// keychain is the set of commands of an OS keychain tool.
+type keychain struct {
+	name string
+	// The commands to store, look up and delete the session. The secret is
+	// never an argument of a command, which any local user can read: the
+	// store command reads it from stdin, or, if interactive, the store
+	// command is itself written to the stdin of the interactive mode of the
+	// tool, with the secret appended in hex.
+	store       []string
+	interactive bool
+	lookup      []string
+	delete      []string
+	// notFound returns whether the lookup failed because there is no
+	// session, rather than because the keychain could not be used.
+	notFound func(*commandError) bool
+}
+
+var (
+	macOSKeychain = keychain{
+		name:        "macOS keychain",
+		store:       []string{"security", "add-generic-password", "-U", "-s", keychainService, "-a", keychainAccount, "-X"},
+		interactive: true,
+		lookup:      []string{"security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w"},
+		delete:      []string{"security", "delete-generic-password", "-s", keychainService, "-a", keychainAccount},
+		// errSecItemNotFound
+		notFound: func(err *commandError) bool { return err.code == 44 },
+	}
+	secretService = keychain{
+		name:   "Secret Service keyring",
+		store:  []string{"secret-tool", "store", "--label=rpk cloud session", "service", keychainService, "account", keychainAccount},
+		lookup: []string{"secret-tool", "lookup", "service", keychainService, "account", keychainAccount},
+		delete: []string{"secret-tool", "clear", "service", keychainService, "account", keychainAccount},
+		// secret-tool exits silently with 1 when there is no secret,
+		// and explains other failures.
+		notFound: func(err *commandError) bool { return err.code == 1 && err.stderr == "" },
+	}
+)
+
+// keychainStore stores the session as a secret of an OS keychain.
+type keychainStore struct {
+	kc  keychain
+	run func(stdin []byte, args []string) ([]byte, error)
+}
+
+// commandError is the failure of a keychain command.
+type commandError struct {
+	name   string
+	code   int // the exit status, or -1 if the command did not run
+	stderr string
+	err    error
+}
+
+func (e *commandError) Error() string {
+	if e.stderr == "" {
+		return fmt.Sprintf("%s failed: %v", e.name, e.err)
+	}
+	return fmt.Sprintf("%s failed: %v: %s", e.name, e.err, e.stderr)
+}
+
+func (e *commandError) Unwrap() error { return e.err }
+
+func runCommand(stdin []byte, args []string) ([]byte, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		code := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		}
+		return nil, &commandError{args[0], code, strings.TrimSpace(stderr.String()), err}
+	}
+	return out, nil
+}
+
+func (s *keychainStore) Load() (Session, error) {
+	out, err := s.run(nil, s.kc.lookup)
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) && s.kc.notFound(cmdErr) {
+		return Session{}, ErrNotLoggedIn
+	}
+	if err != nil {
+		return Session{}, fmt.Errorf("unable to load the session from the %s: %w", s.kc.name, err)
+	}
+	// secret-tool may also succeed with no output.
+	if len(bytes.TrimSpace(out)) == 0 {
+		return Session{}, ErrNotLoggedIn
+	}
+	return decodeSession(bytes.TrimSpace(out))
+}
+
+func (s *keychainStore) Save(session Session) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	args, stdin := s.kc.store, raw
+	if s.kc.interactive {
+		args = []string{s.kc.store[0], "-i"}
+		stdin = []byte(strings.Join(s.kc.store[1:], " ") + " " + hex.EncodeToString(raw) + "\n")
+	}
+	if _, err := s.run(stdin, args); err != nil {
+		return fmt.Errorf("unable to store the session in the %s: %w", s.kc.name, err)
+	}
+	if !s.kc.interactive {
+		return nil
+	}
+	// The interactive mode reports the failures of its commands, but does
+	// not exit with an error: the session is looked up to check it was
+	// stored.
+	stored, err := s.Load()
+	if err == nil && stored.Token.AccessToken != session.Token.AccessToken {
+		err = errors.New("the stored session is not the new one")
+	}
+	if err != nil {
+		return fmt.Errorf("unable to store the session in the %s: %w", s.kc.name, err)
+	}
+	return nil
+}
+
+func (s *keychainStore) Delete() error {
+	if _, err := s.Load(); errors.Is(err, ErrNotLoggedIn) {
+		return nil
+	}
+	if _, err := s.run(nil, s.kc.delete); err != nil {
+		return fmt.Errorf("unable to delete the session from the %s: %w", s.kc.name, err)
+	}
+	return nil
+}
+
+func (s *keychainStore) String() string { return s.kc.name }
+
+func decodeSession(raw []byte) (Session, error) {
+	var session Session
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return Session{}, fmt.Errorf("unable to decode the stored session: %w", err)
+	}
+	if session.Token.AccessToken == "" {
+		return Session{}, ErrNotLoggedIn
+	}
+	return session, nil
+}
+
+// LoadSession returns the stored session, refreshing and storing its token
+// if it expired.
+func LoadSession(ctx context.Context, store Store) (Session, error) {
+	session, err := store.Load()
+	if err != nil || session.Token.Valid() {
+		return session, err
+	}
+	if session.Token.RefreshToken == "" {
+		return Session{}, errors.New("the session expired, please run 'rpk cloud login'")
+	}
+	cl := &Client{Endpoints: session.Endpoints}
+	t, err := cl.Refresh(ctx, session.Token.RefreshToken)
+	if err != nil {
+		return Session{}, fmt.Errorf("%w; please run 'rpk cloud login'", err)
+	}
+	session.Token = t
+	if err := store.Save(session); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := &FileStore{fs, "/home/user/.config/rpk/cloud-session.json"}
+
+	_, err := s.Load()
+	require.True(t, errors.Is(err, ErrNotLoggedIn))
+
+	session := Session{APIURL: "https://api.example.com", Token: Token{AccessToken: "access"}}
+	require.NoError(t, s.Save(session))
+	info, err := fs.Stat(s.Path)
+	require.NoError(t, err)
+	require.Equal(t, "-rw-------", info.Mode().Perm().String())
+
+	loaded, err := s.Load()
+	require.NoError(t, err)
+	require.Equal(t, session, loaded)
+
+	require.NoError(t, s.Delete())
+	require.NoError(t, s.Delete())
+	_, err = s.Load()
+	require.True(t, errors.Is(err, ErrNotLoggedIn))
+}
+
+func TestKeychainStore(t *testing.T) {
+	var secret []byte
+	run := func(stdin []byte, args []string) ([]byte, error) {
+		switch args[1] {
+		case "store":
+			secret = stdin
+		case "lookup":
+			if secret == nil {
+				return nil, &commandError{"secret-tool", 1, "", errors.New("exit status 1")}
+			}
+			return secret, nil
+		case "clear":
+			secret = nil
+		}
+		return nil, nil
+	}
+	s := &keychainStore{secretService, run}
+
+	_, err := s.Load()
+	require.True(t, errors.Is(err, ErrNotLoggedIn))
+
+	session := Session{Token: Token{AccessToken: "access"}}
+	require.NoError(t, s.Save(session))
+	loaded, err := s.Load()
+	require.NoError(t, err)
+	require.Equal(t, session, loaded)
+
+	require.NoError(t, s.Delete())
+	_, err = s.Load()
+	require.True(t, errors.Is(err, ErrNotLoggedIn))
+}
+
+func TestKeychainStoreMacOS(t *testing.T) {
+	var secret []byte
+	run := func(stdin []byte, args []string) ([]byte, error) {
+		// The secret must never be an argument, which other users can
+		// read.
+		for _, arg := range args {
+			require.NotContains(t, arg, "access")
+		}
+		switch args[1] {
+		case "-i":
+			fields := strings.Fields(string(stdin))
+			require.Equal(t, macOSKeychain.store[1:], fields[:len(fields)-1])
+			var err error
+			secret, err = hex.DecodeString(fields[len(fields)-1])
+			require.NoError(t, err)
+		case "find-generic-password":
+			if secret == nil {
+				return nil, &commandError{"security", 44, "The specified item could not be found in the keychain.", errors.New("exit status 44")}
+			}
+			return secret, nil
+		}
+		return nil, nil
+	}
+	s := &keychainStore{macOSKeychain, run}
+
+	_, err := s.Load()
+	require.True(t, errors.Is(err, ErrNotLoggedIn))
+
+	session := Session{Token: Token{AccessToken: "access"}}
+	require.NoError(t, s.Save(session))
+	loaded, err := s.Load()
+	require.NoError(t, err)
+	require.Equal(t, session, loaded)
+}
+
+func TestKeychainStoreLoadFailure(t *testing.T) {
+	locked := &commandError{"security", 36, "User interaction is not allowed.", errors.New("exit status 36")}
+	s := &keychainStore{macOSKeychain, func([]byte, []string) ([]byte, error) { return nil, locked }}
+	_, err := s.Load()
+	require.False(t, errors.Is(err, ErrNotLoggedIn))
+	require.True(t, errors.Is(err, locked.err))
+
+	// The interactive mode does not fail with its commands: a session
+	// that cannot be looked up was not stored.
+	s = &keychainStore{macOSKeychain, func(_ []byte, args []string) ([]byte, error) {
+		if args[1] == "-i" {
+			return nil, nil
+		}
+		return nil, &commandError{"security", 44, "", errors.New("exit status 44")}
+	}}
+	require.Error(t, s.Save(Session{Token: Token{AccessToken: "access"}}))
+}
+
+func TestLoadSessionExpiredWithoutRefresh(t *testing.T) {
+	s := &FileStore{afero.NewMemMapFs(), "/session.json"}
+	require.NoError(t, s.Save(Session{Token: Token{AccessToken: "access", Expiry: time.Now().Add(-time.Hour)}}))
+	_, err := LoadSession(context.Background(), s)
+	require.Error(t, err)
+}
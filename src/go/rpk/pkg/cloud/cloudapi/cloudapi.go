@@ -0,0 +1,103 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package cloudapi is a client of the cloud API, which lists the managed
+// clusters of the logged in user.
+package cloudapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cluster is a managed cluster.
+type Cluster struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// KafkaAPI and AdminAPI are the addresses clients connect to. Managed
+	// clusters always use TLS.
+	KafkaAPI struct {
+		SeedBrokers []string `json:"seed_brokers"`
+	} `json:"kafka_api"`
+	AdminAPI struct {
+		URLs []string `json:"urls"`
+	} `json:"admin_api"`
+}
+
+// Client is a cloud API client authenticated with a bearer token.
+type Client struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+// Clusters returns the clusters the user has access to.
+func (c *Client) Clusters(ctx context.Context) ([]Cluster, error) {
+	var resp struct {
+		Clusters []Cluster `json:"clusters"`
+	}
+	if err := c.get(ctx, "/api/v1/clusters", &resp); err != nil {
+		return nil, fmt.Errorf("unable to list clusters: %w", err)
+	}
+	return resp.Clusters, nil
+}
+
+// ClusterByName returns the cluster with the given name or ID.
+func (c *Client) ClusterByName(ctx context.Context, name string) (Cluster, error) {
+	clusters, err := c.Clusters(ctx)
+	if err != nil {
+		return Cluster{}, err
+	}
+	for _, cl := range clusters {
+		if cl.Name == name || cl.ID == name {
+			return cl, nil
+		}
+	}
+	return Cluster{}, fmt.Errorf("cluster %q not found", name)
+}
+
+func (c *Client) get(ctx context.Context, path string, into interface{}) error {
+	u := strings.TrimSuffix(c.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+
+	cl := c.HTTP
+	if cl == nil {
+		cl = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read the response of %s: %w", u, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s: the session is not valid anymore, please run 'rpk cloud login'", resp.Status)
+	case resp.StatusCode/100 != 2:
+		return fmt.Errorf("%s returned %s: %s", u, resp.Status, body)
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("unable to decode the response of %s: %w", u, err)
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintIssue is a problem found in a configuration file.
+type LintIssue struct {
+	Line    int
+	Column  int
+	Path    string // the path of the offending key, e.g. redpanda.kafka_api[0].port
+	Message string
+	// Warning issues do not make the file invalid: rpk and Redpanda
+	// accept the file, but likely not the way it was intended.
+	Warning bool
+}
+
+func (i LintIssue) String() string {
+	severity := "error"
+	if i.Warning {
+		severity = "warning"
+	}
+	return fmt.Sprintf("%d:%d: %s: %s: %s", i.Line, i.Column, severity, i.Path, i.Message)
+}
+
+// deprecatedKeys are keys that are still accepted, with the reason they
+// should not be used. Indices of lists are written [].
+var deprecatedKeys = map[string]string{
+	"rpk.tls":                         "use rpk.kafka_api.tls and rpk.admin_api.tls",
+	"rpk.sasl":                        "use rpk.kafka_api.sasl",
+	"redpanda.seed_servers[].node_id": "seed servers are identified by their address, the node ID is unused",
+}
+
+// clusterProperties are common cluster properties that used to be set in
+// the redpanda section of redpanda.yaml. They are only read from the file
+// when the cluster configuration is first bootstrapped.
+var clusterProperties = map[string]bool{
+	"auto_create_topics_enabled":          true,
+	"default_topic_partitions":            true,
+	"default_topic_replications":          true,
+	"enable_idempotence":                  true,
+	"enable_rack_awareness":               true,
+	"enable_sasl":                         true,
+	"enable_transactions":                 true,
+	"group_topic_partitions":              true,
+	"id_allocator_replication":            true,
+	"log_cleanup_policy":                  true,
+	"log_retention_ms":                    true,
+	"log_segment_size":                    true,
+	"retention_bytes":                     true,
+	"superusers":                          true,
+	"transaction_coordinator_replication": true,
+}
+
+// lintTypes replace the types of the schema that accept more shapes than
+// their fields tell, for the purpose of linting.
+var lintTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(SeedServer{}): reflect.TypeOf(struct {
+		Address string        `yaml:"address"`
+		Port    int           `yaml:"port"`
+		Host    SocketAddress `yaml:"host"`
+		NodeID  *int          `yaml:"node_id"`
+	}{}),
+}
+
+// The authentication methods of Kafka API listeners.
+var authNMethods = map[string]bool{"none": true, "sasl": true, "mtls_identity": true}
+
+var listIndexRe = regexp.MustCompile(`\[\d+\]`)
+
+// Lint checks the contents of a redpanda.yaml against the schema known by
+// rpk: the types of the values, unknown and deprecated keys, and settings
+// that contradict each other, such as TLS configured for a listener that
+// does not exist. The issues are sorted by position in the file.
+//
+// Unknown keys are errors in the sections whose keys are all known to rpk,
+// such as the rpk section and listener addresses, and warnings in the
+// sections that also hold settings rpk does not manage.
+func Lint(raw []byte) ([]LintIssue, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse yaml: %v", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	l := &linter{nodes: make(map[string]*yaml.Node)}
+	l.walk(doc.Content[0], reflect.TypeOf(Config{}), "")
+
+	// The semantic checks need the whole file decoded, which only works
+	// if every value has the right type.
+	if !l.hasErrors() {
+		var cfg Config
+		if err := yaml.Unmarshal(raw, &cfg); err != nil {
+			l.add(doc.Content[0], "", false, "%v", err)
+		} else {
+			l.checkListeners(&cfg)
+			l.checkRpk(&cfg)
+		}
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.issues, nil
+}
+
+type linter struct {
+	issues []LintIssue
+	nodes  map[string]*yaml.Node // the value nodes by path
+}
+
+func (l *linter) add(n *yaml.Node, path string, warning bool, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{
+		Line:    n.Line,
+		Column:  n.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+		Warning: warning,
+	})
+}
+
+// addAt adds an issue at the node of path, or of its closest parent.
+func (l *linter) addAt(path string, warning bool, format string, args ...interface{}) {
+	for p := path; ; {
+		if n, ok := l.nodes[p]; ok {
+			l.add(n, path, warning, format, args...)
+			return
+		}
+		i := strings.LastIndexAny(p, ".[")
+		if i < 0 {
+			l.add(&yaml.Node{}, path, warning, format, args...)
+			return
+		}
+		p = p[:i]
+	}
+}
+
+func (l *linter) hasErrors() bool {
+	for _, i := range l.issues {
+		if !i.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *linter) walk(n *yaml.Node, t reflect.Type, path string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	l.nodes[path] = n
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if lt, ok := lintTypes[t]; ok {
+		t = lt
+	}
+	if n.Tag == "!!null" {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		l.walkStruct(n, t, path)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			l.decode(n, path, new(weakStringArray))
+			return
+		}
+		switch n.Kind {
+		case yaml.SequenceNode:
+			for i, c := range n.Content {
+				l.walk(c, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+			}
+		case yaml.MappingNode: // a single element is accepted as a list
+			l.walk(n, t.Elem(), path+"[0]")
+		default:
+			l.add(n, path, false, "expected a list, got %s", nodeKind(n))
+		}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return // unmanaged values
+		}
+		if n.Kind != yaml.MappingNode {
+			l.add(n, path, false, "expected a mapping, got %s", nodeKind(n))
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			l.walk(n.Content[i+1], t.Elem(), path+"."+n.Content[i].Value)
+		}
+	case reflect.Bool:
+		l.decode(n, path, new(weakBool))
+	case reflect.Int:
+		l.decode(n, path, new(weakInt))
+	case reflect.String:
+		l.decode(n, path, new(weakString))
+	}
+}
+
+func (l *linter) walkStruct(n *yaml.Node, t reflect.Type, path string) {
+	if n.Kind != yaml.MappingNode {
+		l.add(n, path, false, "expected a mapping, got %s", nodeKind(n))
+		return
+	}
+	fields := make(map[string]reflect.Type)
+	var open bool
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "Other" {
+			open = true
+			continue
+		}
+		if tag := strings.Split(f.Tag.Get("yaml"), ",")[0]; tag != "" {
+			fields[tag] = f.Type
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Value == "<<" { // merge keys are resolved by the decoder
+			continue
+		}
+		p := k.Value
+		if path != "" {
+			p = path + "." + k.Value
+		}
+		if seen[k.Value] {
+			l.add(k, p, false, "duplicate key %q", k.Value)
+			continue
+		}
+		seen[k.Value] = true
+
+		if reason, ok := deprecatedKeys[listIndexRe.ReplaceAllString(p, "[]")]; ok {
+			l.add(k, p, true, "deprecated: %s", reason)
+		}
+		ft, ok := fields[k.Value]
+		switch {
+		case ok:
+			l.walk(v, ft, p)
+		case path == "redpanda" && clusterProperties[k.Value]:
+			l.add(k, p, true, "%q is a cluster property, only read from redpanda.yaml when the cluster is first started; use 'rpk cluster config set' instead", k.Value)
+		case open:
+			l.add(k, p, true, "unknown key %q, it is not validated by rpk", k.Value)
+		default:
+			l.add(k, p, false, "unknown key %q", k.Value)
+		}
+	}
+}
+
+// decode decodes the node with the weak types rpk uses to read the file,
+// so that any value rpk accepts passes.
+func (l *linter) decode(n *yaml.Node, path string, into interface{}) {
+	if err := n.Decode(into); err != nil {
+		l.add(n, path, false, "%v", err)
+	}
+}
+
+func nodeKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.MappingNode:
+		return "a mapping"
+	default:
+		return fmt.Sprintf("%q", n.Value)
+	}
+}
+
+// listener is a listener of any of the APIs, for the checks common to all.
+type listener struct {
+	path    string
+	name    string
+	address string
+	port    int
+	authN   *string
+}
+
+func (l *linter) checkListeners(cfg *Config) {
+	rp := &cfg.Redpanda
+	bound := make(map[string]string) // address:port => path of the first listener
+	api := func(path string, ls []listener, tls []ServerTLS) {
+		names := make(map[string]bool)
+		for _, ln := range ls {
+			if names[ln.name] {
+				l.addAt(ln.path+".name", false, "duplicate listener name %q", ln.name)
+			}
+			names[ln.name] = true
+			if ln.port == 0 {
+				continue
+			}
+			addr := fmt.Sprintf("%s:%d", ln.address, ln.port)
+			if first, ok := bound[addr]; ok {
+				l.addAt(ln.path, false, "address %s is already used by %s", addr, first)
+			} else {
+				bound[addr] = ln.path
+			}
+		}
+		l.checkTLS(path+"_tls", names, tls)
+	}
+
+	var kafka []listener
+	for i, a := range rp.KafkaAPI {
+		kafka = append(kafka, listener{fmt.Sprintf("redpanda.kafka_api[%d]", i), a.Name, a.Address, a.Port, a.AuthN})
+	}
+	api("redpanda.kafka_api", kafka, rp.KafkaAPITLS)
+	l.checkAuthN(kafka, rp.KafkaAPITLS)
+	l.checkAdvertised("redpanda.advertised_kafka_api", kafka, rp.AdvertisedKafkaAPI)
+
+	api("redpanda.admin", namedListeners("redpanda.admin", rp.AdminAPI), rp.AdminAPITLS)
+	if rp.RPCServer.Port != 0 {
+		addr := fmt.Sprintf("%s:%d", rp.RPCServer.Address, rp.RPCServer.Port)
+		if first, ok := bound[addr]; ok {
+			l.addAt("redpanda.rpc_server", false, "address %s is already used by %s", addr, first)
+		}
+		bound[addr] = "redpanda.rpc_server"
+	}
+	if len(rp.RPCServerTLS) > 1 {
+		l.addAt("redpanda.rpc_server_tls", false, "only one TLS configuration is supported for the RPC server")
+	}
+	if pp := cfg.Pandaproxy; pp != nil {
+		ls := namedListeners("pandaproxy.pandaproxy_api", pp.PandaproxyAPI)
+		api("pandaproxy.pandaproxy_api", ls, pp.PandaproxyAPITLS)
+		l.checkAdvertised("pandaproxy.advertised_pandaproxy_api", ls, pp.AdvertisedPandaproxyAPI)
+	}
+	if sr := cfg.SchemaRegistry; sr != nil {
+		api("schema_registry.schema_registry_api", namedListeners("schema_registry.schema_registry_api", sr.SchemaRegistryAPI), sr.SchemaRegistryAPITLS)
+	}
+}
+
+func namedListeners(path string, as []NamedSocketAddress) []listener {
+	var ls []listener
+	for i, a := range as {
+		ls = append(ls, listener{fmt.Sprintf("%s[%d]", path, i), a.Name, a.Address, a.Port, nil})
+	}
+	return ls
+}
+
+// checkTLS checks that the enabled TLS configurations are complete and
+// apply to existing listeners.
+func (l *linter) checkTLS(path string, listeners map[string]bool, tls []ServerTLS) {
+	configured := make(map[string]bool)
+	for i, t := range tls {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		if !t.Enabled {
+			continue
+		}
+		if configured[t.Name] {
+			l.addAt(p+".name", false, "TLS is configured more than once for listener %q", t.Name)
+		}
+		configured[t.Name] = true
+		if !listeners[t.Name] {
+			l.addAt(p+".name", false, "TLS is configured for listener %q, which does not exist", t.Name)
+		}
+		if t.CertFile == "" || t.KeyFile == "" {
+			l.addAt(p, false, "TLS is enabled without both cert_file and key_file")
+		}
+		if t.RequireClientAuth && t.TruststoreFile == "" {
+			l.addAt(p, false, "require_client_auth is enabled without a truststore_file to verify the clients")
+		}
+	}
+}
+
+// checkAuthN checks the authentication methods of the Kafka API listeners:
+// mTLS identities need TLS with client authentication on the listener.
+func (l *linter) checkAuthN(listeners []listener, tls []ServerTLS) {
+	for _, ln := range listeners {
+		if ln.authN == nil {
+			continue
+		}
+		p := ln.path + ".authentication_method"
+		if !authNMethods[*ln.authN] {
+			l.addAt(p, false, "unknown authentication method %q, must be none, sasl or mtls_identity", *ln.authN)
+			continue
+		}
+		if *ln.authN != "mtls_identity" {
+			continue
+		}
+		var mtls bool
+		for _, t := range tls {
+			if t.Name == ln.name && t.Enabled && t.RequireClientAuth {
+				mtls = true
+			}
+		}
+		if !mtls {
+			l.addAt(p, false, "mtls_identity requires TLS with require_client_auth on listener %q, which is plaintext", ln.name)
+		}
+	}
+}
+
+// checkAdvertised checks that advertised addresses are named after
+// existing listeners.
+func (l *linter) checkAdvertised(path string, listeners []listener, advertised []NamedSocketAddress) {
+	names := make(map[string]bool)
+	for _, ln := range listeners {
+		names[ln.name] = true
+	}
+	for i, a := range advertised {
+		if !names[a.Name] {
+			l.addAt(fmt.Sprintf("%s[%d].name", path, i), false, "advertised address for listener %q, which does not exist", a.Name)
+		}
+	}
+}
+
+func (l *linter) checkRpk(cfg *Config) {
+	r := &cfg.Rpk
+	if r.TLS != nil && r.KafkaAPI.TLS != nil && r.AdminAPI.TLS != nil {
+		l.addAt("rpk.tls", true, "rpk.tls is ignored, rpk.kafka_api.tls and rpk.admin_api.tls are both set")
+	}
+	if r.SASL != nil && r.KafkaAPI.SASL != nil {
+		l.addAt("rpk.sasl", true, "rpk.sasl is ignored, rpk.kafka_api.sasl is set")
+	}
+	for host := range r.AdminAPI.HostTLS {
+		var found bool
+		for _, a := range r.AdminAPI.Addresses {
+			found = found || a == host
+		}
+		if !found {
+			l.addAt("rpk.admin_api.host_tls."+host, true, "TLS override for %q, which is not in rpk.admin_api.addresses", host)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		yaml string
+		exp  []string
+	}{
+		{
+			name: "valid file with weak types",
+			yaml: `redpanda:
+  node_id: "1"
+  developer_mode: "true"
+  seed_servers:
+    - address: 10.0.0.1
+      port: 33145
+  kafka_api:
+    address: 0.0.0.0
+    port: 9092
+  admin:
+    - address: 0.0.0.0
+      port: 9644
+rpk:
+  kafka_api:
+    brokers: 10.0.0.1:9092
+`,
+		},
+		{
+			name: "wrong types",
+			yaml: `redpanda:
+  node_id: one
+  developer_mode: [true]
+  kafka_api: 9092
+rpk:
+  tune_cpu: maybe
+`,
+			exp: []string{
+				`2:12: error: redpanda.node_id: cannot parse 'one' as an integer: strconv.Atoi: parsing "one": invalid syntax`,
+				`3:19: error: redpanda.developer_mode: type !!seq not supported as a boolean`,
+				`4:14: error: redpanda.kafka_api: expected a list, got "9092"`,
+				`6:13: error: rpk.tune_cpu: cannot parse 'maybe' as bool: strconv.ParseBool: parsing "maybe": invalid syntax`,
+			},
+		},
+		{
+			name: "unknown, duplicate and deprecated keys",
+			yaml: `redpanda:
+  enable_idempotence: true
+  some_new_property: 1
+  seed_servers:
+    - host: {address: 10.0.0.1, port: 33145}
+      node_id: 1
+  rpc_server:
+    adress: 0.0.0.0
+rpk:
+  tls: {}
+  tune_cpus: true
+  tune_cpu: true
+  tune_cpu: false
+`,
+			exp: []string{
+				`2:3: warning: redpanda.enable_idempotence: "enable_idempotence" is a cluster property, only read from redpanda.yaml when the cluster is first started; use 'rpk cluster config set' instead`,
+				`3:3: warning: redpanda.some_new_property: unknown key "some_new_property", it is not validated by rpk`,
+				`6:7: warning: redpanda.seed_servers[0].node_id: deprecated: seed servers are identified by their address, the node ID is unused`,
+				`8:5: error: redpanda.rpc_server.adress: unknown key "adress"`,
+				`10:3: warning: rpk.tls: deprecated: use rpk.kafka_api.tls and rpk.admin_api.tls`,
+				`11:3: error: rpk.tune_cpus: unknown key "tune_cpus"`,
+				`13:3: error: rpk.tune_cpu: duplicate key "tune_cpu"`,
+			},
+		},
+		{
+			name: "contradicting listeners",
+			yaml: `redpanda:
+  kafka_api:
+    - name: internal
+      address: 0.0.0.0
+      port: 9092
+      authentication_method: mtls_identity
+    - name: internal
+      address: 0.0.0.0
+      port: 9093
+      authentication_method: kerberos
+    - name: plain
+      address: 0.0.0.0
+      port: 9094
+      authentication_method: mtls_identity
+  kafka_api_tls:
+    - name: internal
+      enabled: true
+      cert_file: node.crt
+      require_client_auth: true
+    - name: external
+      enabled: true
+      cert_file: node.crt
+      key_file: node.key
+  advertised_kafka_api:
+    - name: external
+      address: redpanda.example.com
+      port: 9092
+  admin:
+    address: 0.0.0.0
+    port: 9092
+`,
+			exp: []string{
+				`7:13: error: redpanda.kafka_api[1].name: duplicate listener name "internal"`,
+				`10:30: error: redpanda.kafka_api[1].authentication_method: unknown authentication method "kerberos", must be none, sasl or mtls_identity`,
+				`14:30: error: redpanda.kafka_api[2].authentication_method: mtls_identity requires TLS with require_client_auth on listener "plain", which is plaintext`,
+				`16:7: error: redpanda.kafka_api_tls[0]: TLS is enabled without both cert_file and key_file`,
+				`16:7: error: redpanda.kafka_api_tls[0]: require_client_auth is enabled without a truststore_file to verify the clients`,
+				`20:13: error: redpanda.kafka_api_tls[1].name: TLS is configured for listener "external", which does not exist`,
+				`25:13: error: redpanda.advertised_kafka_api[0].name: advertised address for listener "external", which does not exist`,
+				`29:5: error: redpanda.admin[0]: address 0.0.0.0:9092 is already used by redpanda.kafka_api[0]`,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			issues, err := Lint([]byte(test.yaml))
+			require.NoError(t, err)
+			var got []string
+			for _, i := range issues {
+				got = append(got, i.String())
+			}
+			require.Equal(t, test.exp, got)
+		})
+	}
+
+	_, err := Lint([]byte("redpanda: [\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// ModeProfile is a custom mode, for organizations with their own baseline.
+// It is read from a YAML file such as:
+//
+//	name: hardened
+//	base: prod
+//	set:
+//	  rpk.tune_fstrim: true
+//	  rpk.coredump_dir: /mnt/coredump
+//
+// The profile applies the base mode, if any, and then sets every key of set
+// as `rpk redpanda config set` would.
+type ModeProfile struct {
+	Name string                 `yaml:"name"`
+	Base string                 `yaml:"base,omitempty"`
+	Set  map[string]interface{} `yaml:"set,omitempty"`
+}
+
+// LoadModeProfile reads and validates the mode profile at path.
+func LoadModeProfile(fs afero.Fs, path string) (*ModeProfile, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read mode profile %q: %v", path, err)
+	}
+	var p ModeProfile
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("unable to decode mode profile %q: %v", path, err)
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("mode profile %q has no name", path)
+	}
+	if p.Base != "" {
+		if _, err := NormalizeMode(p.Base); err != nil {
+			return nil, fmt.Errorf("invalid base of mode profile %q: %v", path, err)
+		}
+	}
+	if p.Base == "" && len(p.Set) == 0 {
+		return nil, fmt.Errorf("mode profile %q has neither a base mode nor keys to set", path)
+	}
+	return &p, nil
+}
+
+// Apply applies the profile to conf.
+func (p *ModeProfile) Apply(conf *Config) (*Config, error) {
+	if p.Base != "" {
+		var err error
+		if conf, err = SetMode(p.Base, conf); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(p.Set))
+	for k := range p.Set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := yaml.Marshal(p.Set[k])
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode the value of %q: %v", k, err)
+		}
+		if err := conf.Set(k, string(v), "yaml"); err != nil {
+			return nil, fmt.Errorf("unable to set %q: %v", k, err)
+		}
+	}
+	return conf, nil
+}
+
+// ModeChange is a change of a single configuration key when switching modes.
+type ModeChange struct {
+	Key string `json:"key"`
+	// Tuner is whether the key enables or disables a tuner.
+	Tuner bool        `json:"tuner"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ModeSnapshot captures the state of a configuration to diff it after it is
+// changed, see DiffMode.
+type ModeSnapshot map[string]interface{}
+
+// SnapshotMode returns the configuration keys of conf and their values.
+//
+// The configuration is encoded as JSON rather than YAML: the YAML encoding
+// omits the fields with zero values, which would hide e.g. disabled tuners.
+func SnapshotMode(conf *Config) (ModeSnapshot, error) {
+	raw, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	s := make(ModeSnapshot)
+	s.flatten("", m)
+	return s, nil
+}
+
+func (s ModeSnapshot) flatten(prefix string, m map[string]interface{}) {
+	for k, v := range m {
+		// Unknown fields are inlined in the YAML file.
+		if k == "Other" {
+			if other, ok := v.(map[string]interface{}); ok {
+				s.flatten(prefix, other)
+			}
+			continue
+		}
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			s.flatten(key, nested)
+			continue
+		}
+		s[key] = v
+	}
+}
+
+// DiffMode returns the keys that differ between the before and after
+// snapshots, sorted by key. Keys that exist in only one of the snapshots have
+// a nil value in the other.
+func DiffMode(before, after ModeSnapshot) []ModeChange {
+	keys := make(map[string]bool)
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	var changes []ModeChange
+	for k := range keys {
+		if reflect.DeepEqual(before[k], after[k]) {
+			continue
+		}
+		changes = append(changes, ModeChange{
+			Key:   k,
+			Tuner: strings.HasPrefix(k, "rpk.tune_"),
+			Old:   before[k],
+			New:   after[k],
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
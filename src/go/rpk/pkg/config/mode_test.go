@@ -0,0 +1,93 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadModeProfile(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		file   string
+		exp    *ModeProfile
+		expErr bool
+	}{
+		{
+			name: "base and keys",
+			file: "name: hardened\nbase: production\nset:\n  rpk.tune_fstrim: true\n",
+			exp: &ModeProfile{
+				Name: "hardened",
+				Base: "production",
+				Set:  map[string]interface{}{"rpk.tune_fstrim": true},
+			},
+		},
+		{
+			name:   "no name",
+			file:   "base: prod\n",
+			expErr: true,
+		},
+		{
+			name:   "unknown base",
+			file:   "name: hardened\nbase: staging\n",
+			expErr: true,
+		},
+		{
+			name:   "empty profile",
+			file:   "name: hardened\n",
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "/profile.yaml", []byte(test.file), 0o644))
+			p, err := LoadModeProfile(fs, "/profile.yaml")
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, p)
+		})
+	}
+}
+
+func TestDiffMode(t *testing.T) {
+	conf := Default()
+	before, err := SnapshotMode(conf)
+	require.NoError(t, err)
+
+	p := &ModeProfile{
+		Name: "hardened",
+		Base: ModeProd,
+		Set: map[string]interface{}{
+			"rpk.tune_cpu":     false,
+			"rpk.coredump_dir": "/mnt/coredump",
+		},
+	}
+	conf, err = p.Apply(conf)
+	require.NoError(t, err)
+	after, err := SnapshotMode(conf)
+	require.NoError(t, err)
+
+	changes := DiffMode(before, after)
+	byKey := make(map[string]ModeChange)
+	for _, c := range changes {
+		byKey[c.Key] = c
+	}
+	require.Equal(t, ModeChange{Key: "redpanda.developer_mode", Old: true, New: false}, byKey["redpanda.developer_mode"])
+	require.Equal(t, ModeChange{Key: "rpk.coredump_dir", Old: "/var/lib/redpanda/coredump", New: "/mnt/coredump"}, byKey["rpk.coredump_dir"])
+	require.Equal(t, ModeChange{Key: "rpk.tune_network", Tuner: true, Old: false, New: true}, byKey["rpk.tune_network"])
+	require.NotContains(t, byKey, "rpk.tune_cpu")
+	require.Empty(t, DiffMode(after, after))
+}
@@ -191,8 +191,43 @@ type RpkKafkaAPI struct {
 }
 
 type RpkAdminAPI struct {
-	Addresses []string `yaml:"addresses,omitempty" json:"addresses"`
-	TLS       *TLS     `yaml:"tls,omitempty" json:"tls"`
+	Addresses []string        `yaml:"addresses,omitempty" json:"addresses"`
+	TLS       *TLS            `yaml:"tls,omitempty" json:"tls"`
+	Signing   *RequestSigning `yaml:"signing,omitempty" json:"signing,omitempty"`
+	// HostTLS overrides the TLS settings for some of the addresses, keyed
+	// by address as in Addresses.
+	HostTLS map[string]*HostTLS `yaml:"host_tls,omitempty" json:"host_tls,omitempty"`
+}
+
+// HostTLS overrides the TLS settings of a single admin API address, for
+// brokers behind load balancers with mismatching certificates or reached
+// through split-horizon DNS.
+type HostTLS struct {
+	// ServerName is the name the certificate of the host is verified
+	// against, rather than the hostname of the address.
+	ServerName string `yaml:"server_name,omitempty" json:"server_name,omitempty"`
+	// InsecureSkipVerify disables the verification of the certificate of
+	// the host.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"`
+	// CAFile is a PEM file of additional CAs trusted for the host.
+	CAFile string `yaml:"ca_file,omitempty" json:"ca_file,omitempty"`
+}
+
+// RequestSigning configures the signing of admin API requests, for admin APIs
+// fronted by gateways that only accept signed requests.
+type RequestSigning struct {
+	// Type is the signing scheme, either hmac-sha256 (HTTP signatures) or
+	// aws-sigv4.
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// KeyID identifies the secret to the gateway: the key ID for
+	// hmac-sha256, or the access key ID for aws-sigv4.
+	KeyID string `yaml:"key_id,omitempty" json:"key_id,omitempty"`
+	// Secret is the shared secret, or the secret access key for aws-sigv4.
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+	// Region, Service and SessionToken are only used by aws-sigv4.
+	Region       string `yaml:"region,omitempty" json:"region,omitempty"`
+	Service      string `yaml:"service,omitempty" json:"service,omitempty"`
+	SessionToken string `yaml:"session_token,omitempty" json:"session_token,omitempty"`
 }
 
 type SASL struct {
@@ -457,14 +457,33 @@ func (r *RpkKafkaAPI) UnmarshalYAML(n *yaml.Node) error {
 
 func (r *RpkAdminAPI) UnmarshalYAML(n *yaml.Node) error {
 	var internal struct {
-		Addresses weakStringArray `yaml:"addresses"`
-		TLS       *TLS            `yaml:"tls"`
+		Addresses weakStringArray     `yaml:"addresses"`
+		TLS       *TLS                `yaml:"tls"`
+		Signing   *RequestSigning     `yaml:"signing"`
+		HostTLS   map[string]*HostTLS `yaml:"host_tls"`
 	}
 	if err := n.Decode(&internal); err != nil {
 		return err
 	}
 	r.Addresses = internal.Addresses
 	r.TLS = internal.TLS
+	r.Signing = internal.Signing
+	r.HostTLS = internal.HostTLS
+	return nil
+}
+
+func (h *HostTLS) UnmarshalYAML(n *yaml.Node) error {
+	var internal struct {
+		ServerName         weakString `yaml:"server_name"`
+		InsecureSkipVerify weakBool   `yaml:"insecure_skip_verify"`
+		CAFile             weakString `yaml:"ca_file"`
+	}
+	if err := n.Decode(&internal); err != nil {
+		return err
+	}
+	h.ServerName = string(internal.ServerName)
+	h.InsecureSkipVerify = bool(internal.InsecureSkipVerify)
+	h.CAFile = string(internal.CAFile)
 	return nil
 }
 
@@ -664,3 +683,24 @@ func (s *SASL) UnmarshalYAML(n *yaml.Node) error {
 
 	return nil
 }
+
+func (r *RequestSigning) UnmarshalYAML(n *yaml.Node) error {
+	var internal struct {
+		Type         weakString `yaml:"type"`
+		KeyID        weakString `yaml:"key_id"`
+		Secret       weakString `yaml:"secret"`
+		Region       weakString `yaml:"region"`
+		Service      weakString `yaml:"service"`
+		SessionToken weakString `yaml:"session_token"`
+	}
+	if err := n.Decode(&internal); err != nil {
+		return err
+	}
+	r.Type = string(internal.Type)
+	r.KeyID = string(internal.KeyID)
+	r.Secret = string(internal.Secret)
+	r.Region = string(internal.Region)
+	r.Service = string(internal.Service)
+	r.SessionToken = string(internal.SessionToken)
+	return nil
+}
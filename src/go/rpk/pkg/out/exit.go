@@ -0,0 +1,184 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package out
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// The exit codes of rpk, which tell wrappers and CI why a command failed
+// without parsing its message. Failures that are in no other category exit
+// with ExitError.
+const (
+	ExitError      = 1 // any other failure
+	ExitUsage      = 2 // invalid flags or arguments, or a rejected request
+	ExitNotFound   = 3 // the topic, group, user... does not exist
+	ExitTimeout    = 4 // the cluster did not answer in time
+	ExitServer     = 5 // the cluster failed to handle the request
+	ExitConnection = 6 // the cluster could not be reached
+)
+
+// The formats of the errors printed to stderr by Die and its variants.
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+var errorFormat = ErrorFormatText
+
+// SetErrorFormat sets the format of the errors printed by Die and its
+// variants, either ErrorFormatText or ErrorFormatJSON.
+func SetErrorFormat(format string) error {
+	switch format {
+	case ErrorFormatText, ErrorFormatJSON:
+		errorFormat = format
+		return nil
+	default:
+		return fmt.Errorf("unknown error format %q, valid formats are %q and %q", format, ErrorFormatText, ErrorFormatJSON)
+	}
+}
+
+// ErrorFormat returns the format of the errors printed by Die and its
+// variants.
+func ErrorFormat() string { return errorFormat }
+
+// ErrorEnvelope is an error printed to stderr in the json error format.
+type ErrorEnvelope struct {
+	Error    string `json:"error"`
+	Code     int    `json:"code"`
+	Category string `json:"category"`
+}
+
+var categories = map[int]string{
+	ExitError:      "error",
+	ExitUsage:      "usage",
+	ExitNotFound:   "not_found",
+	ExitTimeout:    "timeout",
+	ExitServer:     "server",
+	ExitConnection: "connection",
+}
+
+type usageError struct{ err error }
+
+func (ue *usageError) Error() string { return ue.err.Error() }
+func (ue *usageError) Unwrap() error { return ue.err }
+
+// UsageError returns err as an error of the user, which exits with
+// ExitUsage.
+func UsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &usageError{err}
+}
+
+// exitCoders are the ExitCoders registered with RegisterExitCoder.
+var exitCoders []ExitCoder
+
+// ExitCoder returns the exit code of err and true if err is an error of the
+// package it classifies, or false to let ExitCode classify err.
+type ExitCoder func(err error) (int, bool)
+
+// RegisterExitCoder adds an ExitCoder to the ones ExitCode tries, for errors
+// of packages that out does not depend on, such as the admin API client. It
+// must be called before any command runs.
+func RegisterExitCoder(fn ExitCoder) {
+	exitCoders = append(exitCoders, fn)
+}
+
+// ExitCode returns the exit code of a command that failed with err, or 0 if
+// err is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var ue *usageError
+	if errors.As(err, &ue) {
+		return ExitUsage
+	}
+
+	var ke *kerr.Error
+	if errors.As(err, &ke) {
+		switch ke {
+		case kerr.UnknownTopicOrPartition, kerr.UnknownTopicID, kerr.GroupIDNotFound:
+			return ExitNotFound
+		case kerr.RequestTimedOut:
+			return ExitTimeout
+		case kerr.InvalidRequest, kerr.InvalidConfig, kerr.InvalidTopicException,
+			kerr.InvalidPartitions, kerr.InvalidReplicationFactor, kerr.PolicyViolation,
+			kerr.TopicAlreadyExists:
+			return ExitUsage
+		}
+		if ke.Retriable {
+			return ExitServer
+		}
+		return ExitError
+	}
+
+	for _, fn := range exitCoders {
+		if code, ok := fn(err); ok {
+			return code
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return ExitTimeout
+		}
+		return ExitConnection
+	}
+	return ExitError
+}
+
+// printError prints msg, the message of a failure that exits with code, to w
+// in the error format.
+func printError(w io.Writer, code int, msg string) {
+	if errorFormat != ErrorFormatJSON {
+		fmt.Fprintln(w, msg)
+		return
+	}
+	json.NewEncoder(w).Encode(ErrorEnvelope{
+		Error:    msg,
+		Code:     code,
+		Category: categories[code],
+	})
+}
+
+// printShardError prints msg, the message of shard errors that exit with
+// code if all shards failed. It is printed to stdout among the output of the
+// command, unless the error format is json: it is then printed to stderr in
+// the error format.
+func printShardError(stdout, stderr io.Writer, code int, msg string) {
+	if errorFormat == ErrorFormatJSON {
+		printError(stderr, code, msg)
+		return
+	}
+	fmt.Fprintln(stdout, msg)
+}
+
+// DieCode prints the formatted message to stderr in the error format and
+// exits the process with code.
+func DieCode(code int, msg string, args ...interface{}) {
+	printError(os.Stderr, code, fmt.Sprintf(msg, args...))
+	os.Exit(code)
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package out
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestExitCode(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		err  error
+		exp  int
+	}{
+		{"nil", nil, 0},
+		{"other", errors.New("boom"), ExitError},
+		{"usage", UsageError(errors.New("unknown flag")), ExitUsage},
+		{"wrapped usage", fmt.Errorf("bad: %w", UsageError(errors.New("unknown flag"))), ExitUsage},
+
+		{"unknown topic", kerr.UnknownTopicOrPartition, ExitNotFound},
+		{"wrapped unknown group", fmt.Errorf("unable to describe: %w", kerr.GroupIDNotFound), ExitNotFound},
+		{"kafka timeout", kerr.RequestTimedOut, ExitTimeout},
+		{"invalid partitions", kerr.InvalidPartitions, ExitUsage},
+		{"retriable", kerr.NotLeaderForPartition, ExitServer},
+		{"not retriable", kerr.ClusterAuthorizationFailed, ExitError},
+
+		{"deadline", fmt.Errorf("request: %w", context.DeadlineExceeded), ExitTimeout},
+		{"net timeout", &net.OpError{Op: "dial", Err: timeoutError{}}, ExitTimeout},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ExitConnection},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, ExitCode(test.err))
+		})
+	}
+}
+
+func TestRegisterExitCoder(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	defer func(coders []ExitCoder) { exitCoders = coders }(exitCoders)
+	RegisterExitCoder(func(err error) (int, bool) {
+		return ExitServer, errors.Is(err, errQuota)
+	})
+	require.Equal(t, ExitServer, ExitCode(fmt.Errorf("unable to produce: %w", errQuota)))
+	require.Equal(t, ExitError, ExitCode(errors.New("boom")))
+}
+
+func TestPrintShardError(t *testing.T) {
+	defer SetErrorFormat(ErrorFormatText)
+
+	var stdout, stderr bytes.Buffer
+	printShardError(&stdout, &stderr, ExitServer, "1 ListOffsets request failures")
+	require.Equal(t, "1 ListOffsets request failures\n", stdout.String())
+	require.Empty(t, stderr.String())
+
+	require.NoError(t, SetErrorFormat(ErrorFormatJSON))
+	stdout.Reset()
+	printShardError(&stdout, &stderr, ExitServer, "1 ListOffsets request failures")
+	require.Empty(t, stdout.String())
+	require.JSONEq(t, `{"error":"1 ListOffsets request failures","code":5,"category":"server"}`, stderr.String())
+}
+
+func TestPrintError(t *testing.T) {
+	defer SetErrorFormat(ErrorFormatText)
+
+	var b bytes.Buffer
+	printError(&b, ExitNotFound, "topic foo does not exist")
+	require.Equal(t, "topic foo does not exist\n", b.String())
+
+	require.NoError(t, SetErrorFormat(ErrorFormatJSON))
+	b.Reset()
+	printError(&b, ExitNotFound, "topic foo does not exist")
+	require.JSONEq(t, `{"error":"topic foo does not exist","code":3,"category":"not_found"}`, b.String())
+
+	require.Error(t, SetErrorFormat("yaml"))
+	require.Equal(t, ErrorFormatJSON, ErrorFormat())
+}
@@ -49,23 +49,28 @@ func Pick(options []string, msg string, args ...interface{}) (string, error) {
 }
 
 // Die formats the message with a suffixed newline to stderr and exits the
-// process with 1.
+// process with ExitError.
 func Die(msg string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, msg+"\n", args...)
-	os.Exit(1)
+	DieCode(ExitError, msg, args...)
 }
 
-// MaybeDie calls Die if err is non-nil.
+// DieUsage is Die for invalid flags or arguments, exiting with ExitUsage.
+func DieUsage(msg string, args ...interface{}) {
+	DieCode(ExitUsage, msg, args...)
+}
+
+// MaybeDie calls Die if err is non-nil, exiting with the ExitCode of err.
 func MaybeDie(err error, msg string, args ...interface{}) {
 	if err != nil {
-		Die(msg, args...)
+		DieCode(ExitCode(err), msg, args...)
 	}
 }
 
-// MaybeDieErr calls Die if err is non-nil, with just the err as the message.
+// MaybeDieErr calls Die if err is non-nil, with just the err as the message
+// and exiting with the ExitCode of err.
 func MaybeDieErr(err error) {
 	if err != nil {
-		Die("%v", err)
+		DieCode(ExitCode(err), "%v", err)
 	}
 }
 
@@ -78,7 +83,8 @@ func Exit(msg string, args ...interface{}) {
 
 // HandleShardError prints a message and potentially exits depending on the
 // inner error. If the error is a shard error and not everything failed, this
-// allows the cli to continue.
+// allows the cli to continue. With the json error format, the message is
+// printed to stderr in an ErrorEnvelope.
 func HandleShardError(name string, err error) {
 	var se *kadm.ShardErrors
 	var ae *kadm.AuthError
@@ -86,19 +92,20 @@ func HandleShardError(name string, err error) {
 	case err == nil:
 
 	case errors.As(err, &se):
+		first := se.Errs[0].Err
 		if se.AllFailed {
-			fmt.Printf("all %d %s request failures, first error: %s\n", len(se.Errs), se.Name, se.Errs[0].Err)
-			os.Exit(1)
+			printShardError(os.Stdout, os.Stderr, ExitCode(first), fmt.Sprintf("all %d %s request failures, first error: %s", len(se.Errs), se.Name, first))
+			os.Exit(ExitCode(first))
 		}
-		fmt.Printf("%d %s request failures, first error: %s\n", len(se.Errs), se.Name, se.Errs[0].Err)
+		printShardError(os.Stdout, os.Stderr, ExitCode(first), fmt.Sprintf("%d %s request failures, first error: %s", len(se.Errs), se.Name, first))
 
 	case errors.As(err, &ae):
-		fmt.Printf("%s authorization problem: %s\n", name, err)
-		os.Exit(1)
+		printShardError(os.Stdout, os.Stderr, ExitError, fmt.Sprintf("%s authorization problem: %s", name, err))
+		os.Exit(ExitError)
 
 	default:
-		fmt.Printf("unable to issue %s request: %s\n", name, err)
-		os.Exit(1)
+		printShardError(os.Stdout, os.Stderr, ExitCode(err), fmt.Sprintf("unable to issue %s request: %s", name, err))
+		os.Exit(ExitCode(err))
 	}
 }
 
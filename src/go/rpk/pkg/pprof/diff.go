@@ -0,0 +1,105 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package pprof
+
+import (
+	"fmt"
+	"sort"
+)
+
+// unknownSite attributes the samples without a stack.
+const unknownSite = "<unknown>"
+
+// SiteDiff is the change of the value of a site between two profiles.
+type SiteDiff struct {
+	// Site is the leaf function of the samples, i.e. the allocation site in
+	// memory profiles.
+	Site   string
+	Before int64
+	After  int64
+	Delta  int64
+}
+
+// Diff is the change of the values of a sample type between two profiles.
+type Diff struct {
+	SampleType ValueType
+	// Sites are the sites whose value changed, sorted from the largest
+	// growth to the largest shrinkage.
+	Sites       []SiteDiff
+	TotalBefore int64
+	TotalAfter  int64
+}
+
+// Growing returns the n sites that grew the most, or all the growing sites
+// if n is not positive.
+func (d *Diff) Growing(n int) []SiteDiff {
+	var growing []SiteDiff
+	for _, s := range d.Sites {
+		if s.Delta <= 0 || n > 0 && len(growing) == n {
+			break
+		}
+		growing = append(growing, s)
+	}
+	return growing
+}
+
+// Compare compares the values of the named sample type between the before
+// and after profiles, attributing the samples to their leaf function. An
+// empty sample type selects the default sample type of the after profile.
+func Compare(before, after *Profile, sampleType string) (*Diff, error) {
+	ia, err := after.SampleIndex(sampleType)
+	if err != nil {
+		return nil, fmt.Errorf("after profile: %w", err)
+	}
+	typ := after.SampleTypes[ia]
+	ib, err := before.SampleIndex(typ.Type)
+	if err != nil {
+		return nil, fmt.Errorf("before profile: %w", err)
+	}
+	if unit := before.SampleTypes[ib].Unit; unit != typ.Unit {
+		return nil, fmt.Errorf("the %s samples are in %s in the before profile and in %s in the after profile", typ.Type, unit, typ.Unit)
+	}
+
+	d := &Diff{SampleType: typ}
+	sites := make(map[string]*SiteDiff)
+	site := func(s Sample) *SiteDiff {
+		name := unknownSite
+		if len(s.Stack) > 0 {
+			name = s.Stack[0]
+		}
+		sd, ok := sites[name]
+		if !ok {
+			sd = &SiteDiff{Site: name}
+			sites[name] = sd
+		}
+		return sd
+	}
+	for _, s := range before.Samples {
+		site(s).Before += s.Values[ib]
+		d.TotalBefore += s.Values[ib]
+	}
+	for _, s := range after.Samples {
+		site(s).After += s.Values[ia]
+		d.TotalAfter += s.Values[ia]
+	}
+
+	for _, sd := range sites {
+		if sd.Delta = sd.After - sd.Before; sd.Delta != 0 {
+			d.Sites = append(d.Sites, *sd)
+		}
+	}
+	sort.Slice(d.Sites, func(i, j int) bool {
+		if d.Sites[i].Delta != d.Sites[j].Delta {
+			return d.Sites[i].Delta > d.Sites[j].Delta
+		}
+		return d.Sites[i].Site < d.Sites[j].Site
+	})
+	return d, nil
+}
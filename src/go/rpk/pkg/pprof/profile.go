@@ -0,0 +1,335 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package pprof reads the samples of profiles in the pprof format, as
+// written by the Go runtime and the pprof tools, to compare them.
+//
+// Only the fields needed to attribute the samples to functions are decoded:
+// the sample types, the samples, their locations, and the functions.
+package pprof
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ValueType describes the values of the samples, e.g. inuse_space in bytes.
+type ValueType struct {
+	Type string
+	Unit string
+}
+
+// Sample is a value of each sample type, attributed to a stack of functions.
+type Sample struct {
+	// Stack are the functions of the call stack, starting from the leaf,
+	// i.e. from the function that allocated memory or was running.
+	Stack  []string
+	Values []int64
+}
+
+// Profile is a parsed pprof profile.
+type Profile struct {
+	SampleTypes []ValueType
+	// DefaultSampleType is the sample type that pprof shows by default,
+	// empty if the profile does not set it.
+	DefaultSampleType string
+	Samples           []Sample
+}
+
+// SampleIndex returns the index of the values of the named sample type. An
+// empty name selects the default sample type, which is the last one if the
+// profile has no default, as in pprof.
+func (p *Profile) SampleIndex(name string) (int, error) {
+	if len(p.SampleTypes) == 0 {
+		return 0, errors.New("the profile has no sample types")
+	}
+	if name == "" {
+		name = p.DefaultSampleType
+	}
+	if name == "" {
+		return len(p.SampleTypes) - 1, nil
+	}
+	for i, t := range p.SampleTypes {
+		if t.Type == name {
+			return i, nil
+		}
+	}
+	var types []string
+	for _, t := range p.SampleTypes {
+		types = append(types, t.Type)
+	}
+	return 0, fmt.Errorf("the profile has no sample type %q, available sample types: %v", name, types)
+}
+
+// Parse parses a pprof profile, which may be gzipped.
+func Parse(r io.Reader) (*Profile, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress the profile: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the profile: %w", err)
+	}
+	p, err := decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the profile: %w", err)
+	}
+	return p, nil
+}
+
+// Fields of the messages of profile.proto, see
+// https://github.com/google/pprof/blob/main/proto/profile.proto
+const (
+	profileSampleType        = 1
+	profileSample            = 2
+	profileLocation          = 4
+	profileFunction          = 5
+	profileStringTable       = 6
+	profileDefaultSampleType = 14
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+
+	functionID   = 1
+	functionName = 2
+)
+
+// Wire types of the protobuf encoding.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// decode decodes the profile in two passes, since the samples refer to
+// locations, functions and strings that may come after them.
+func decode(b []byte) (*Profile, error) {
+	type rawSample struct {
+		locations []uint64
+		values    []int64
+	}
+	var (
+		strings           []string
+		sampleTypes       [][2]uint64
+		defaultSampleType uint64
+		samples           []rawSample
+		locations         = make(map[uint64][]uint64) // location ID to function IDs, leaf first
+		functions         = make(map[uint64]uint64)   // function ID to name
+	)
+	err := fields(b, func(num, wire int, v uint64, data []byte) error {
+		switch num {
+		case profileStringTable:
+			strings = append(strings, string(data))
+		case profileDefaultSampleType:
+			defaultSampleType = v
+		case profileSampleType:
+			var t [2]uint64
+			err := fields(data, func(num, _ int, v uint64, _ []byte) error {
+				switch num {
+				case valueTypeType:
+					t[0] = v
+				case valueTypeUnit:
+					t[1] = v
+				}
+				return nil
+			})
+			sampleTypes = append(sampleTypes, t)
+			return err
+		case profileSample:
+			var s rawSample
+			err := fields(data, func(num, wire int, v uint64, data []byte) error {
+				switch num {
+				case sampleLocationID:
+					ids, err := varints(wire, v, data)
+					s.locations = append(s.locations, ids...)
+					return err
+				case sampleValue:
+					values, err := varints(wire, v, data)
+					for _, v := range values {
+						s.values = append(s.values, int64(v))
+					}
+					return err
+				}
+				return nil
+			})
+			samples = append(samples, s)
+			return err
+		case profileLocation:
+			var id uint64
+			var funcs []uint64
+			err := fields(data, func(num, _ int, v uint64, data []byte) error {
+				switch num {
+				case locationID:
+					id = v
+				case locationLine:
+					// The lines of inlined functions come first
+					return fields(data, func(num, _ int, v uint64, _ []byte) error {
+						if num == lineFunctionID {
+							funcs = append(funcs, v)
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+			locations[id] = funcs
+			return err
+		case profileFunction:
+			var id, name uint64
+			err := fields(data, func(num, _ int, v uint64, _ []byte) error {
+				switch num {
+				case functionID:
+					id = v
+				case functionName:
+					name = v
+				}
+				return nil
+			})
+			functions[id] = name
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	str := func(i uint64) (string, error) {
+		if i >= uint64(len(strings)) {
+			return "", fmt.Errorf("string index %d out of the string table of length %d", i, len(strings))
+		}
+		return strings[i], nil
+	}
+	p := new(Profile)
+	for _, t := range sampleTypes {
+		typ, err := str(t[0])
+		if err != nil {
+			return nil, err
+		}
+		unit, err := str(t[1])
+		if err != nil {
+			return nil, err
+		}
+		p.SampleTypes = append(p.SampleTypes, ValueType{Type: typ, Unit: unit})
+	}
+	if defaultSampleType != 0 {
+		if p.DefaultSampleType, err = str(defaultSampleType); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range samples {
+		if len(s.values) != len(p.SampleTypes) {
+			return nil, fmt.Errorf("sample has %d values for %d sample types", len(s.values), len(p.SampleTypes))
+		}
+		sample := Sample{Values: s.values}
+		for _, l := range s.locations {
+			funcs, ok := locations[l]
+			if !ok {
+				return nil, fmt.Errorf("sample refers to unknown location %d", l)
+			}
+			for _, f := range funcs {
+				name, err := str(functions[f])
+				if err != nil {
+					return nil, err
+				}
+				sample.Stack = append(sample.Stack, name)
+			}
+		}
+		p.Samples = append(p.Samples, sample)
+	}
+	return p, nil
+}
+
+// fields calls fn for each field of the protobuf message b, with the value
+// of varint and fixed fields, or the data of length delimited fields.
+func fields(b []byte, fn func(num, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		num, wire := int(key>>3), int(key&7)
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errTruncated
+			}
+			data, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", wire, num)
+		}
+		if err := fn(num, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// varints returns the values of a repeated varint field, which is either a
+// single value or packed.
+func varints(wire int, v uint64, data []byte) ([]uint64, error) {
+	if wire != wireBytes {
+		return []uint64{v}, nil
+	}
+	var values []uint64
+	for r := bytes.NewReader(data); r.Len() > 0; {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errTruncated
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package pprof
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// message encodes a protobuf message for the tests.
+type message []byte
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (m message) varint(num int, v uint64) message {
+	m = appendUvarint(m, uint64(num<<3|wireVarint))
+	return appendUvarint(m, v)
+}
+
+func (m message) bytes(num int, b []byte) message {
+	m = appendUvarint(m, uint64(num<<3|wireBytes))
+	m = appendUvarint(m, uint64(len(b)))
+	return append(m, b...)
+}
+
+func (m message) packed(num int, vs ...uint64) message {
+	var b []byte
+	for _, v := range vs {
+		b = appendUvarint(b, v)
+	}
+	return m.bytes(num, b)
+}
+
+// heapProfile encodes a memory profile with the given inuse_space of each
+// function, sampled from a call from main.
+func heapProfile(inuse map[string]int64) []byte {
+	strs := []string{"", "inuse_objects", "count", "inuse_space", "bytes", "main"}
+	index := func(s string) uint64 {
+		for i := range strs {
+			if strs[i] == s {
+				return uint64(i)
+			}
+		}
+		strs = append(strs, s)
+		return uint64(len(strs) - 1)
+	}
+
+	var p message
+	p = p.bytes(profileSampleType, message{}.varint(valueTypeType, 1).varint(valueTypeUnit, 2))
+	p = p.bytes(profileSampleType, message{}.varint(valueTypeType, 3).varint(valueTypeUnit, 4))
+	p = p.bytes(profileFunction, message{}.varint(functionID, 1).varint(functionName, 5))
+	p = p.bytes(profileLocation, message{}.varint(locationID, 1).bytes(locationLine, message{}.varint(lineFunctionID, 1)))
+	var id uint64 = 1
+	for fn, v := range inuse {
+		id++
+		p = p.bytes(profileFunction, message{}.varint(functionID, id).varint(functionName, index(fn)))
+		p = p.bytes(profileLocation, message{}.varint(locationID, id).bytes(locationLine, message{}.varint(lineFunctionID, id)))
+		// Unpacked location IDs, and packed values
+		p = p.bytes(profileSample, message{}.varint(sampleLocationID, id).varint(sampleLocationID, 1).packed(sampleValue, 1, uint64(v)))
+	}
+	for _, s := range strs {
+		p = p.bytes(profileStringTable, []byte(s))
+	}
+	return p
+}
+
+func TestParse(t *testing.T) {
+	raw := heapProfile(map[string]int64{"cache::put": 100})
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err := gz.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	for _, b := range [][]byte{raw, gzipped.Bytes()} {
+		p, err := Parse(bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Equal(t, []ValueType{{"inuse_objects", "count"}, {"inuse_space", "bytes"}}, p.SampleTypes)
+		require.Equal(t, []Sample{{Stack: []string{"cache::put", "main"}, Values: []int64{1, 100}}}, p.Samples)
+
+		i, err := p.SampleIndex("")
+		require.NoError(t, err)
+		require.Equal(t, 1, i)
+		_, err = p.SampleIndex("alloc_space")
+		require.Error(t, err)
+	}
+
+	_, err = Parse(bytes.NewReader(raw[:len(raw)-3]))
+	require.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	parse := func(inuse map[string]int64) *Profile {
+		p, err := Parse(bytes.NewReader(heapProfile(inuse)))
+		require.NoError(t, err)
+		return p
+	}
+	before := parse(map[string]int64{"cache::put": 100, "batch::append": 50, "log::write": 30})
+	after := parse(map[string]int64{"cache::put": 400, "batch::append": 40, "log::write": 30, "raft::replicate": 80})
+
+	d, err := Compare(before, after, "")
+	require.NoError(t, err)
+	require.Equal(t, ValueType{"inuse_space", "bytes"}, d.SampleType)
+	require.Equal(t, int64(180), d.TotalBefore)
+	require.Equal(t, int64(550), d.TotalAfter)
+	require.Equal(t, []SiteDiff{
+		{Site: "cache::put", Before: 100, After: 400, Delta: 300},
+		{Site: "raft::replicate", Before: 0, After: 80, Delta: 80},
+		{Site: "batch::append", Before: 50, After: 40, Delta: -10},
+	}, d.Sites)
+	require.Equal(t, d.Sites[:1], d.Growing(1))
+	require.Equal(t, d.Sites[:2], d.Growing(0))
+
+	d, err = Compare(before, after, "inuse_objects")
+	require.NoError(t, err)
+	require.Equal(t, []SiteDiff{{Site: "raft::replicate", After: 1, Delta: 1}}, d.Growing(0))
+}
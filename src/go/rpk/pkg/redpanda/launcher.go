@@ -52,6 +52,14 @@ func (*launcher) Start(installDir string, args *RedpandaArgs) error {
 	redpandaArgs := collectRedpandaArgs(args)
 	log.Debugf("Starting '%s' with arguments '%v'", binary, redpandaArgs)
 
+	rpEnv := redpandaEnv()
+	log.Infof("Running:\n%s %s %s", strings.Join(rpEnv, " "), binary, strings.Join(redpandaArgs, " "))
+	return unix.Exec(binary, redpandaArgs, rpEnv)
+}
+
+// redpandaEnv returns the environment of rpk without LD_LIBRARY_PATH, which
+// must not leak into redpanda.
+func redpandaEnv() []string {
 	var rpEnv []string
 	ldLibraryPathPattern := regexp.MustCompile("^LD_LIBRARY_PATH=.*$")
 	for _, ev := range os.Environ() {
@@ -59,8 +67,7 @@ func (*launcher) Start(installDir string, args *RedpandaArgs) error {
 			rpEnv = append(rpEnv, ev)
 		}
 	}
-	log.Infof("Running:\n%s %s %s", strings.Join(rpEnv, " "), binary, strings.Join(redpandaArgs, " "))
-	return unix.Exec(binary, redpandaArgs, rpEnv)
+	return rpEnv
 }
 
 func getBinary(installDir string) (string, error) {
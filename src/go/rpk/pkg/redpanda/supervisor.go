@@ -0,0 +1,281 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build !windows
+
+package redpanda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Supervisor is implemented by launchers that can run redpanda as a child
+// process, restarting it if it crashes.
+type Supervisor interface {
+	Supervise(installDir string, args *RedpandaArgs, cfg SuperviseConfig) error
+}
+
+// SuperviseConfig configures how redpanda is supervised.
+type SuperviseConfig struct {
+	// LogDir is the directory where the stdout and stderr of redpanda are
+	// written, to redpanda.log.
+	LogDir string
+	// LogRotateSize is the size at which redpanda.log is rotated to
+	// redpanda.log.1; 0 disables rotation.
+	LogRotateSize int64
+	// LogRotateKeep is the number of rotated log files that are kept.
+	LogRotateKeep int
+
+	// MinBackoff is the time to wait before the first restart after a
+	// crash, which doubles on every consecutive crash up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// StableAfter is the uptime after which redpanda is considered stable
+	// and the backoff is reset.
+	StableAfter time.Duration
+
+	// StatusAddr is the address of the status endpoint; empty disables it.
+	StatusAddr string
+}
+
+// SupervisorStatus is served by the status endpoint of the supervisor.
+type SupervisorStatus struct {
+	// State is one of starting, running, backoff, or stopped.
+	State       string          `json:"state"`
+	PID         int             `json:"pid,omitempty"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	Restarts    int             `json:"restarts"`
+	LastExit    *SupervisorExit `json:"last_exit,omitempty"`
+	NextStartAt *time.Time      `json:"next_start_at,omitempty"`
+}
+
+// SupervisorExit describes how redpanda last exited.
+type SupervisorExit struct {
+	Code   int       `json:"code"`
+	Signal string    `json:"signal,omitempty"`
+	At     time.Time `json:"at"`
+	Uptime string    `json:"uptime"`
+}
+
+func (*launcher) Supervise(
+	installDir string, args *RedpandaArgs, cfg SuperviseConfig,
+) error {
+	binary, err := getBinary(installDir)
+	if err != nil {
+		return err
+	}
+	if args.ConfigFilePath == "" {
+		return errors.New("Redpanda config file is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	s := &supervisor{
+		binary: binary,
+		args:   collectRedpandaArgs(args),
+		env:    redpandaEnv(),
+		cfg:    cfg,
+		status: SupervisorStatus{State: "starting"},
+	}
+	if cfg.StatusAddr != "" {
+		ln, err := net.Listen("tcp", cfg.StatusAddr)
+		if err != nil {
+			return fmt.Errorf("unable to listen on status address %q: %v", cfg.StatusAddr, err)
+		}
+		srv := &http.Server{Handler: s.statusHandler()}
+		go srv.Serve(ln)
+		defer srv.Close()
+		log.Infof("Serving the supervisor status on http://%s/status", ln.Addr())
+	}
+	return s.run(ctx)
+}
+
+type supervisor struct {
+	binary string
+	args   []string
+	env    []string
+	cfg    SuperviseConfig
+
+	mu     sync.Mutex
+	status SupervisorStatus
+}
+
+// run starts redpanda until it exits successfully or ctx is canceled, in which
+// case redpanda is stopped with SIGTERM.
+func (s *supervisor) run(ctx context.Context) error {
+	if err := os.MkdirAll(s.cfg.LogDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create log directory %q: %v", s.cfg.LogDir, err)
+	}
+	logs, err := utils.OpenRotatingFile(
+		afero.NewOsFs(),
+		filepath.Join(s.cfg.LogDir, "redpanda.log"),
+		s.cfg.LogRotateSize,
+		s.cfg.LogRotateKeep,
+	)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	backoff := s.cfg.MinBackoff
+	for {
+		cmd := exec.Command(s.binary, s.args[1:]...)
+		cmd.Env = s.env
+		cmd.Stdout = logs
+		cmd.Stderr = logs
+		// redpanda runs in its own process group so that only the supervisor
+		// receives terminal signals and forwards them once.
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+		log.Infof("Starting %s %v", s.binary, s.args[1:])
+		if err := cmd.Start(); err != nil {
+			s.setStopped(nil)
+			return fmt.Errorf("unable to start redpanda: %v", err)
+		}
+		started := time.Now()
+		s.setRunning(cmd.Process.Pid, started)
+
+		exited := make(chan error, 1)
+		go func() { exited <- cmd.Wait() }()
+
+		var waitErr error
+		select {
+		case waitErr = <-exited:
+		case <-ctx.Done():
+			log.Info("Stopping redpanda")
+			cmd.Process.Signal(syscall.SIGTERM)
+			waitErr = <-exited
+			s.setStopped(exitOf(cmd, waitErr, started))
+			return nil
+		}
+
+		exit := exitOf(cmd, waitErr, started)
+		if exit.Code == 0 && exit.Signal == "" {
+			log.Info("Redpanda exited successfully")
+			s.setStopped(exit)
+			return nil
+		}
+
+		if time.Since(started) >= s.cfg.StableAfter {
+			backoff = s.cfg.MinBackoff
+		}
+		log.Errorf("Redpanda exited after %s (code %d%s), restarting in %s", exit.Uptime, exit.Code, signalSuffix(exit.Signal), backoff)
+		s.setBackoff(exit, time.Now().Add(backoff))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			s.setStopped(exit)
+			return nil
+		}
+		backoff = nextBackoff(backoff, s.cfg.MaxBackoff)
+	}
+}
+
+func nextBackoff(cur, max time.Duration) time.Duration {
+	cur *= 2
+	if cur > max {
+		return max
+	}
+	return cur
+}
+
+func exitOf(cmd *exec.Cmd, waitErr error, started time.Time) *SupervisorExit {
+	exit := &SupervisorExit{
+		At:     time.Now(),
+		Uptime: time.Since(started).Round(time.Millisecond).String(),
+	}
+	ps := cmd.ProcessState
+	if ps == nil {
+		exit.Code = -1
+		return exit
+	}
+	exit.Code = ps.ExitCode()
+	if ws, ok := ps.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		exit.Signal = ws.Signal().String()
+	}
+	return exit
+}
+
+func signalSuffix(sig string) string {
+	if sig == "" {
+		return ""
+	}
+	return ", signal " + sig
+}
+
+func (s *supervisor) setRunning(pid int, started time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.State == "backoff" {
+		s.status.Restarts++
+	}
+	s.status.State = "running"
+	s.status.PID = pid
+	s.status.StartedAt = &started
+	s.status.NextStartAt = nil
+}
+
+func (s *supervisor) setBackoff(exit *SupervisorExit, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.State = "backoff"
+	s.status.PID = 0
+	s.status.StartedAt = nil
+	s.status.LastExit = exit
+	s.status.NextStartAt = &next
+}
+
+func (s *supervisor) setStopped(exit *SupervisorExit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.State = "stopped"
+	s.status.PID = 0
+	s.status.StartedAt = nil
+	s.status.NextStartAt = nil
+	if exit != nil {
+		s.status.LastExit = exit
+	}
+}
+
+func (s *supervisor) currentStatus() SupervisorStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *supervisor) statusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		status := s.currentStatus()
+		w.Header().Set("Content-Type", "application/json")
+		if status.State != "running" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+	return mux
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build !windows
+
+package redpanda
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupervisorRestartsOnCrash(t *testing.T) {
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	// The fake redpanda crashes twice, then exits successfully.
+	binary := filepath.Join(dir, "redpanda")
+	script := `#!/bin/sh
+echo run >> ` + runs + `
+echo "stdout $*"
+echo "stderr" >&2
+[ $(wc -l < ` + runs + `) -ge 3 ] || exit 3
+`
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0o755))
+
+	s := &supervisor{
+		binary: binary,
+		args:   []string{"redpanda", "--redpanda-cfg", "redpanda.yaml"},
+		cfg: SuperviseConfig{
+			LogDir:     filepath.Join(dir, "logs"),
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 2 * time.Millisecond,
+		},
+	}
+	require.NoError(t, s.run(context.Background()))
+
+	status := s.currentStatus()
+	require.Equal(t, "stopped", status.State)
+	require.Equal(t, 2, status.Restarts)
+	require.Equal(t, 0, status.LastExit.Code)
+
+	logs, err := os.ReadFile(filepath.Join(dir, "logs", "redpanda.log"))
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(string(logs), "stdout --redpanda-cfg redpanda.yaml\n"))
+	require.Equal(t, 3, strings.Count(string(logs), "stderr\n"))
+}
+
+func TestSupervisorStop(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "redpanda")
+	require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+
+	s := &supervisor{
+		binary: binary,
+		args:   []string{"redpanda"},
+		cfg:    SuperviseConfig{LogDir: dir, MinBackoff: time.Second, MaxBackoff: time.Second},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return s.currentStatus().State == "running"
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop redpanda")
+	}
+	status := s.currentStatus()
+	require.Equal(t, "stopped", status.State)
+	require.Equal(t, "terminated", status.LastExit.Signal)
+	require.Equal(t, 0, status.Restarts)
+}
+
+func TestNextBackoff(t *testing.T) {
+	require.Equal(t, 2*time.Second, nextBackoff(time.Second, time.Minute))
+	require.Equal(t, time.Minute, nextBackoff(40*time.Second, time.Minute))
+}
@@ -0,0 +1,427 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package selfupdate downloads and installs new rpk releases, for users who
+// install rpk as a standalone binary rather than through a package manager.
+//
+// Releases are described by a manifest per channel, which is signed with
+// ed25519: the manifest is only trusted if its signature matches the public
+// key compiled into rpk, and the downloaded binary is only installed if its
+// sha256 sum matches the one in the manifest.
+package selfupdate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultURLBase is where the release manifests and binaries are
+	// downloaded from, unless overridden with the URLBaseEnv environment
+	// variable.
+	DefaultURLBase = "https://vectorized-public.s3.us-west-2.amazonaws.com/rpk-releases"
+	URLBaseEnv     = "RPK_SELF_UPDATE_URL"
+
+	ChannelStable  = "stable"
+	ChannelNightly = "nightly"
+
+	// CheckInterval is the minimum interval between two startup checks.
+	CheckInterval = 24 * time.Hour
+
+	// The manifest version we understand.
+	manifestVersion = "2022-08-01"
+)
+
+// publicKey is the base64 encoded ed25519 public key that release manifests
+// are signed with. It is set at build time with -ldflags.
+var publicKey string
+
+// ErrNoPublicKey is returned when fetching a release with an rpk that was
+// built without a release public key, such as a development build.
+var ErrNoPublicKey = errors.New("this rpk was built without a release public key and cannot verify releases")
+
+// Channels returns the release channels.
+func Channels() []string { return []string{ChannelStable, ChannelNightly} }
+
+// The client we use for downloading manifests and binaries.
+var client = &http.Client{Timeout: 2 * time.Minute}
+
+// Manifest is the manifest of the latest release of a channel.
+type Manifest struct {
+	// Version is a YYYY-MM-DD version of the manifest, which we use to
+	// determine how to parse the manifest.
+	Version string `yaml:"api_version"`
+
+	// Channel is the release channel the manifest is for. It is part of
+	// the signed body so that a manifest of one channel cannot be served
+	// as the manifest of another.
+	Channel string `yaml:"channel"`
+
+	// Release is the rpk version of the release, e.g. v22.2.1.
+	Release string `yaml:"release"`
+
+	// Path is the base request path of the release binaries, such as
+	// stable/v22.2.1.
+	Path string `yaml:"path"`
+
+	// Compression indicates what type of compression is used on the
+	// binaries: either no compression ("") or "gzip".
+	Compression string `yaml:"compression"`
+
+	// OSArchShas contains the binaries that are available per
+	// ${os}_${arch}, and their sha256 sums once decompressed.
+	OSArchShas map[string]string `yaml:"os_arch_shas"`
+}
+
+// Updater fetches releases from BaseURL.
+type Updater struct {
+	BaseURL string
+
+	// PublicKey verifies the signature of the manifests. If it is nil,
+	// manifests are only fetched if SkipSignature is true.
+	PublicKey     ed25519.PublicKey
+	SkipSignature bool
+}
+
+// New returns an Updater fetching releases from the default URL, or the one
+// in the URLBaseEnv environment variable, and verifying them with the public
+// key rpk was built with.
+func New() (*Updater, error) {
+	base := os.Getenv(URLBaseEnv)
+	if base == "" {
+		base = DefaultURLBase
+	}
+	u := &Updater{BaseURL: strings.TrimSuffix(base, "/")}
+	if publicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid release public key %q built into rpk", publicKey)
+		}
+		u.PublicKey = key
+	}
+	return u, nil
+}
+
+// FetchManifest downloads the manifest of the channel and verifies its
+// signature, which is downloaded from the manifest path suffixed with ".sig".
+func (u *Updater) FetchManifest(ctx context.Context, channel string) (*Manifest, error) {
+	if !validChannel(channel) {
+		return nil, fmt.Errorf("unknown release channel %q, must be one of %s", channel, strings.Join(Channels(), ", "))
+	}
+	if u.PublicKey == nil && !u.SkipSignature {
+		return nil, ErrNoPublicKey
+	}
+
+	manifestURL := fmt.Sprintf("%s/%s/manifest.yaml", u.BaseURL, channel)
+	body, err := get(ctx, manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	if !u.SkipSignature {
+		sig, err := get(ctx, manifestURL+".sig")
+		if err != nil {
+			return nil, err
+		}
+		if err := verify(u.PublicKey, body, sig); err != nil {
+			return nil, fmt.Errorf("manifest %s: %v", manifestURL, err)
+		}
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest body: %v", err)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("release manifest indicates version %q, and we can only understand %q; please update rpk with your package manager", m.Version, manifestVersion)
+	}
+	if m.Channel != channel {
+		return nil, fmt.Errorf("release manifest %s is for channel %q, not %q", manifestURL, m.Channel, channel)
+	}
+	if m.Release == "" {
+		return nil, fmt.Errorf("release manifest %s has no release version", manifestURL)
+	}
+	return &m, nil
+}
+
+// CompareVersions compares two rpk versions such as v22.2.1 or v22.2.1-rc1,
+// returning -1, 0, or +1 if a is older than, the same as, or newer than b. A
+// pre-release is older than its release, and two pre-releases of the same
+// version are compared lexically.
+func CompareVersions(a, b string) (int, error) {
+	av, apre, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, bpre, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range av {
+		switch {
+		case av[i] < bv[i]:
+			return -1, nil
+		case av[i] > bv[i]:
+			return 1, nil
+		}
+	}
+	switch {
+	case apre == bpre:
+		return 0, nil
+	case apre == "":
+		return 1, nil
+	case bpre == "":
+		return -1, nil
+	case apre < bpre:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+// parseVersion parses vMAJOR.MINOR.PATCH[-PRERELEASE], ignoring any
+// +BUILD metadata.
+func parseVersion(v string) (nums [3]int, pre string, err error) {
+	s := strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, pre = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nums, "", fmt.Errorf("invalid version %q, expected vMAJOR.MINOR.PATCH", v)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nums, "", fmt.Errorf("invalid version %q, expected vMAJOR.MINOR.PATCH", v)
+		}
+		nums[i] = n
+	}
+	return nums, pre, nil
+}
+
+// verify checks that sig, the base64 encoded ed25519 signature of body,
+// matches the public key.
+func verify(key ed25519.PublicKey, body, sig []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("unable to decode signature: %v", err)
+	}
+	if !ed25519.Verify(key, body, raw) {
+		return errors.New("signature does not match the release public key")
+	}
+	return nil
+}
+
+// DownloadForUser is a shortcut for Download for the current user's
+// os / arch.
+func (u *Updater) DownloadForUser(ctx context.Context, m *Manifest) ([]byte, error) {
+	return u.Download(ctx, m, runtime.GOOS, runtime.GOARCH)
+}
+
+// Download downloads and decompresses the rpk binary of the release for the
+// given os and arch, and checks its sha256 sum against the manifest.
+func (u *Updater) Download(ctx context.Context, m *Manifest, goos, goarch string) ([]byte, error) {
+	var decompress bool
+	switch m.Compression {
+	case "":
+	case "gzip":
+		decompress = true
+	default:
+		return nil, fmt.Errorf("release uses compression %q, which is not supported", m.Compression)
+	}
+
+	need := fmt.Sprintf("%s_%s", goos, goarch)
+	sha, ok := m.OSArchShas[need]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for os / arch %s", m.Release, need)
+	}
+
+	binURL := fmt.Sprintf("%s/%s/%s/rpk", u.BaseURL, strings.Trim(m.Path, "/"), need)
+	body, err := get(ctx, binURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if decompress {
+		gzr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create gzip reader: %w", err)
+		}
+		if body, err = io.ReadAll(gzr); err != nil {
+			return nil, fmt.Errorf("unable to gzip decompress rpk: %w", err)
+		}
+		if err = gzr.Close(); err != nil {
+			return nil, fmt.Errorf("unable to close gzip reader: %w", err)
+		}
+	}
+
+	shasum := sha256.Sum256(body)
+	gotsha := hex.EncodeToString(shasum[:])
+	expsha := strings.ToLower(sha)
+	if gotsha != expsha {
+		return nil, fmt.Errorf("checksum of %s does not match what the manifest specifies (downloaded sha256sum: %s, manifest specified sha256sum: %s)", binURL, gotsha, expsha)
+	}
+	return body, nil
+}
+
+func get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request %s: %v", u, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to issue request to %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unsuccessful response from %s, status: %s", u, http.StatusText(resp.StatusCode))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response from %s: %v", u, err)
+	}
+	return body, nil
+}
+
+// Replace atomically replaces the binary at path with contents, keeping its
+// permissions: contents are written to a temporary file in the same
+// directory, which is then renamed over path.
+//
+// Windows does not allow renaming over a running binary, so there the
+// current binary is first moved aside to path.old.
+func Replace(fs afero.Fs, path string, contents []byte) error {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return fmt.Errorf("unable to stat %s: %v", path, err)
+	}
+	tmp, err := afero.TempFile(fs, filepath.Dir(path), "rpk-update-part-*")
+	if err != nil {
+		return fmt.Errorf("unable to create temp file next to %s: %v", path, err)
+	}
+	_, err = tmp.Write(contents)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = fs.Chmod(tmp.Name(), info.Mode().Perm()|0o111)
+	}
+	if err != nil {
+		fs.Remove(tmp.Name())
+		return fmt.Errorf("unable to write new rpk binary: %v", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		fs.Remove(old)
+		if err := fs.Rename(path, old); err != nil {
+			fs.Remove(tmp.Name())
+			return fmt.Errorf("unable to move current rpk binary aside to %s: %v", old, err)
+		}
+	}
+	if err := fs.Rename(tmp.Name(), path); err != nil {
+		fs.Remove(tmp.Name())
+		return fmt.Errorf("unable to rename new rpk binary %s to %s: %v", tmp.Name(), path, err)
+	}
+	return nil
+}
+
+// Settings are the self-update settings of the user.
+type Settings struct {
+	// CheckEnabled is whether rpk checks for a new release when it
+	// starts, at most once every CheckInterval.
+	CheckEnabled bool   `json:"check_enabled"`
+	Channel      string `json:"channel,omitempty"`
+
+	LastCheck time.Time `json:"last_check,omitempty"`
+	// Latest is the release version seen by the last check.
+	Latest string `json:"latest,omitempty"`
+}
+
+// SettingsPath returns where the settings are stored, in the rpk directory
+// of the user config directory.
+func SettingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find a directory to store self-update settings in: %w", err)
+	}
+	return filepath.Join(dir, "rpk", "self-update.json"), nil
+}
+
+// LoadSettings returns the settings stored at path. The startup check is
+// disabled and the channel is stable if the user never changed them.
+func LoadSettings(fs afero.Fs, path string) (Settings, error) {
+	s := Settings{Channel: ChannelStable}
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return s, fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+	if s.Channel == "" {
+		s.Channel = ChannelStable
+	}
+	return s, nil
+}
+
+// SaveSettings stores the settings at path.
+func SaveSettings(fs afero.Fs, path string, s Settings) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := afero.WriteFile(fs, path, raw, 0o600); err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// CheckDue returns whether the startup check is enabled and the last check
+// was more than CheckInterval ago.
+func (s Settings) CheckDue(now time.Time) bool {
+	return s.CheckEnabled && now.Sub(s.LastCheck) >= CheckInterval
+}
+
+func validChannel(channel string) bool {
+	for _, c := range Channels() {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package selfupdate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdater(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	binary := []byte("new rpk")
+	sum := sha256.Sum256(binary)
+	var gz bytes.Buffer
+	gzw := gzip.NewWriter(&gz)
+	gzw.Write(binary)
+	gzw.Close()
+
+	manifestFor := func(channel string) []byte {
+		return []byte(fmt.Sprintf(`
+api_version: 2022-08-01
+channel: %s
+release: v22.2.2
+path: stable/v22.2.2
+compression: gzip
+os_arch_shas:
+  linux_amd64: %s
+`, channel, hex.EncodeToString(sum[:])))
+	}
+	manifest := manifestFor(ChannelStable)
+	sign := func(body []byte) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, body)))
+	}
+
+	files := map[string][]byte{
+		"/stable/manifest.yaml":           manifest,
+		"/stable/manifest.yaml.sig":       sign(manifest),
+		"/stable/v22.2.2/linux_amd64/rpk": gz.Bytes(),
+		"/nightly/manifest.yaml":          manifestFor(ChannelNightly),
+		"/nightly/manifest.yaml.sig":      sign([]byte("other")),
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	}))
+	defer svr.Close()
+
+	ctx := context.Background()
+	u := &Updater{BaseURL: svr.URL, PublicKey: pub}
+
+	m, err := u.FetchManifest(ctx, ChannelStable)
+	require.NoError(t, err)
+	require.Equal(t, "v22.2.2", m.Release)
+
+	body, err := u.Download(ctx, m, "linux", "amd64")
+	require.NoError(t, err)
+	require.Equal(t, binary, body)
+
+	// No binary for the os / arch in the manifest.
+	_, err = u.Download(ctx, m, "linux", "arm64")
+	require.Error(t, err)
+
+	// The binary does not match the manifest sha256.
+	files["/stable/v22.2.2/linux_amd64/rpk"] = []byte("tampered")
+	_, err = u.Download(ctx, m, "linux", "amd64")
+	require.Error(t, err)
+
+	// The signature of the nightly manifest does not match, or is not
+	// checked if SkipSignature is set.
+	_, err = u.FetchManifest(ctx, ChannelNightly)
+	require.Error(t, err)
+	u.SkipSignature = true
+	_, err = u.FetchManifest(ctx, ChannelNightly)
+	require.NoError(t, err)
+	u.SkipSignature = false
+
+	// A validly signed manifest of another channel is rejected.
+	files["/nightly/manifest.yaml"] = manifest
+	files["/nightly/manifest.yaml.sig"] = sign(manifest)
+	_, err = u.FetchManifest(ctx, ChannelNightly)
+	require.Error(t, err)
+
+	// A manifest signed with another key is rejected.
+	other, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	u = &Updater{BaseURL: svr.URL, PublicKey: other}
+	_, err = u.FetchManifest(ctx, ChannelStable)
+	require.Error(t, err)
+
+	// Without a public key, manifests cannot be verified.
+	u = &Updater{BaseURL: svr.URL}
+	_, err = u.FetchManifest(ctx, ChannelStable)
+	require.ErrorIs(t, err, ErrNoPublicKey)
+
+	_, err = u.FetchManifest(ctx, "beta")
+	require.Error(t, err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		exp  int
+	}{
+		{"v22.2.1", "v22.2.1", 0},
+		{"v22.2.2", "v22.2.1", 1},
+		{"v22.2.1", "v22.2.2", -1},
+		{"v22.10.1", "v22.9.3", 1},
+		{"v21.11.20", "v22.1.1", -1},
+		{"v22.2.1", "v22.2.1-rc1", 1},
+		{"v22.2.1-rc1", "v22.2.1-rc2", -1},
+		{"22.2.1", "v22.2.1+abc", 0},
+	} {
+		got, err := CompareVersions(test.a, test.b)
+		require.NoError(t, err, "%s vs %s", test.a, test.b)
+		require.Equal(t, test.exp, got, "%s vs %s", test.a, test.b)
+	}
+
+	for _, bad := range []string{"", "latest", "v22.2", "v22.x.1"} {
+		_, err := CompareVersions(bad, "v22.2.1")
+		require.Error(t, err, "version %q", bad)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/usr/local/bin/rpk", []byte("old rpk"), 0o750))
+
+	require.NoError(t, Replace(fs, "/usr/local/bin/rpk", []byte("new rpk")))
+
+	body, err := afero.ReadFile(fs, "/usr/local/bin/rpk")
+	require.NoError(t, err)
+	require.Equal(t, "new rpk", string(body))
+	info, err := fs.Stat("/usr/local/bin/rpk")
+	require.NoError(t, err)
+	require.Equal(t, "-rwxr-x--x", info.Mode().String())
+
+	// No temporary file is left behind.
+	entries, err := afero.ReadDir(fs, "/usr/local/bin")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.Error(t, Replace(fs, "/usr/local/bin/missing", []byte("new rpk")))
+}
+
+func TestSettings(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	const path = "/config/rpk/self-update.json"
+
+	s, err := LoadSettings(fs, path)
+	require.NoError(t, err)
+	require.Equal(t, Settings{Channel: ChannelStable}, s)
+	require.False(t, s.CheckDue(time.Now()), "the startup check is opt-in")
+
+	s.CheckEnabled = true
+	s.Channel = ChannelNightly
+	require.NoError(t, SaveSettings(fs, path, s))
+	s, err = LoadSettings(fs, path)
+	require.NoError(t, err)
+	require.Equal(t, ChannelNightly, s.Channel)
+	require.True(t, s.CheckDue(time.Now()))
+
+	s.LastCheck = time.Now().Add(-time.Hour)
+	require.False(t, s.CheckDue(time.Now()))
+	require.True(t, s.CheckDue(time.Now().Add(CheckInterval)))
+}
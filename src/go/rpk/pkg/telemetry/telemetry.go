@@ -0,0 +1,275 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package telemetry records which rpk commands are used, if and only if the
+// user opted in with 'rpk telemetry enable'.
+//
+// Usage events are spooled in a local file and sent at most once a day.
+// Events contain the command that ran and the names of the flags that were
+// set, never arguments nor flag values: see Event for the full schema.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// SchemaVersion is the version of the Payload schema.
+	SchemaVersion = 1
+
+	// DefaultEndpoint is where the payloads are sent, unless overridden
+	// with the EndpointEnv environment variable.
+	DefaultEndpoint = "https://m.rp.vectorized.io/v1/rpk/usage"
+	EndpointEnv     = "RPK_TELEMETRY_ENDPOINT"
+
+	// MaxSpooled is the number of events kept in the spool, older events
+	// are dropped first.
+	MaxSpooled = 1000
+
+	// SendInterval is the minimum interval between two payloads.
+	SendInterval = 24 * time.Hour
+)
+
+// Event is a single invocation of rpk.
+type Event struct {
+	Time time.Time `json:"time"`
+	// Command is the path of the command, e.g. "rpk topic create".
+	Command string `json:"command"`
+	// Flags are the names of the flags that were set.
+	Flags   []string `json:"flags,omitempty"`
+	Version string   `json:"version"`
+	OS      string   `json:"os"`
+	Arch    string   `json:"arch"`
+}
+
+// Payload is what is sent to the telemetry endpoint, as JSON.
+type Payload struct {
+	SchemaVersion int `json:"schema_version"`
+	// ID is a random identifier generated when telemetry is enabled,
+	// which is not derived from the user nor the machine.
+	ID     string  `json:"id"`
+	Events []Event `json:"events"`
+}
+
+// Settings are the telemetry settings of the user.
+type Settings struct {
+	Enabled  bool      `json:"enabled"`
+	ID       string    `json:"id,omitempty"`
+	LastSent time.Time `json:"last_sent,omitempty"`
+}
+
+// Telemetry manages the settings and the spool, which are stored in Dir.
+type Telemetry struct {
+	Fs       afero.Fs
+	Dir      string
+	Endpoint string
+}
+
+// New returns a Telemetry storing its files in the rpk directory of the user
+// config directory.
+func New(fs afero.Fs) (*Telemetry, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find a directory to store telemetry settings in: %w", err)
+	}
+	endpoint := os.Getenv(EndpointEnv)
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Telemetry{fs, filepath.Join(dir, "rpk"), endpoint}, nil
+}
+
+func (t *Telemetry) SettingsPath() string { return filepath.Join(t.Dir, "telemetry.json") }
+
+func (t *Telemetry) SpoolPath() string { return filepath.Join(t.Dir, "telemetry-spool.jsonl") }
+
+// Settings returns the settings of the user. Telemetry is disabled if the
+// user never enabled it.
+func (t *Telemetry) Settings() (Settings, error) {
+	var s Settings
+	raw, err := afero.ReadFile(t.Fs, t.SettingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, fmt.Errorf("unable to read %s: %w", t.SettingsPath(), err)
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return s, fmt.Errorf("unable to decode %s: %w", t.SettingsPath(), err)
+	}
+	return s, nil
+}
+
+func (t *Telemetry) saveSettings(s Settings) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return t.write(t.SettingsPath(), raw)
+}
+
+// Enable opts in to telemetry, generating the ID of the user if needed.
+func (t *Telemetry) Enable() (Settings, error) {
+	s, err := t.Settings()
+	if err != nil {
+		return s, err
+	}
+	if s.ID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return s, fmt.Errorf("unable to generate telemetry ID: %w", err)
+		}
+		s.ID = hex.EncodeToString(id)
+	}
+	s.Enabled = true
+	return s, t.saveSettings(s)
+}
+
+// Disable opts out of telemetry and deletes the events that were not sent.
+func (t *Telemetry) Disable() error {
+	s, err := t.Settings()
+	if err != nil {
+		return err
+	}
+	s.Enabled = false
+	if err := t.saveSettings(s); err != nil {
+		return err
+	}
+	return t.clearSpool()
+}
+
+// Record spools the event if telemetry is enabled.
+func (t *Telemetry) Record(e Event) error {
+	s, err := t.Settings()
+	if err != nil || !s.Enabled {
+		return err
+	}
+	events, err := t.Spooled()
+	if err != nil {
+		return err
+	}
+	events = append(events, e)
+	if len(events) > MaxSpooled {
+		events = events[len(events)-MaxSpooled:]
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return t.write(t.SpoolPath(), buf.Bytes())
+}
+
+// Spooled returns the events that were not sent yet. Lines of the spool that
+// cannot be decoded are skipped.
+func (t *Telemetry) Spooled() ([]Event, error) {
+	f, err := t.Fs.Open(t.SpoolPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to open %s: %w", t.SpoolPath(), err)
+	}
+	defer f.Close()
+	var events []Event
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var e Event
+		if json.Unmarshal(s.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", t.SpoolPath(), err)
+	}
+	return events, nil
+}
+
+// Preview returns the payload that would be sent now.
+func (t *Telemetry) Preview() (Payload, error) {
+	s, err := t.Settings()
+	if err != nil {
+		return Payload{}, err
+	}
+	events, err := t.Spooled()
+	if err != nil {
+		return Payload{}, err
+	}
+	if events == nil {
+		events = []Event{}
+	}
+	return Payload{SchemaVersion, s.ID, events}, nil
+}
+
+// MaybeSend sends the spooled events if telemetry is enabled and if the last
+// payload was sent more than SendInterval ago. Sent events are removed from
+// the spool.
+func (t *Telemetry) MaybeSend(ctx context.Context, cl *http.Client) error {
+	s, err := t.Settings()
+	if err != nil || !s.Enabled || time.Since(s.LastSent) < SendInterval {
+		return err
+	}
+	p, err := t.Preview()
+	if err != nil || len(p.Events) == 0 {
+		return err
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := cl.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send telemetry: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unable to send telemetry: %s", resp.Status)
+	}
+	s.LastSent = time.Now()
+	if err := t.saveSettings(s); err != nil {
+		return err
+	}
+	return t.clearSpool()
+}
+
+func (t *Telemetry) clearSpool() error {
+	if err := t.Fs.Remove(t.SpoolPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove %s: %w", t.SpoolPath(), err)
+	}
+	return nil
+}
+
+func (t *Telemetry) write(path string, raw []byte) error {
+	if err := t.Fs.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := afero.WriteFile(t.Fs, path, raw, 0o600); err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	return nil
+}
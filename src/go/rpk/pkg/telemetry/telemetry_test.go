@@ -0,0 +1,88 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetry(t *testing.T) {
+	var received []Payload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		received = append(received, p)
+	}))
+	defer ts.Close()
+
+	tl := &Telemetry{afero.NewMemMapFs(), "/rpk", ts.URL}
+	ctx := context.Background()
+	e := Event{Command: "rpk topic create", Flags: []string{"partitions"}, Version: "v22.2.1", OS: "linux", Arch: "amd64"}
+
+	// Disabled by default: nothing is recorded nor sent.
+	require.NoError(t, tl.Record(e))
+	events, err := tl.Spooled()
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	s, err := tl.Enable()
+	require.NoError(t, err)
+	require.True(t, s.Enabled)
+	require.Len(t, s.ID, 32)
+
+	require.NoError(t, tl.Record(e))
+	require.NoError(t, tl.Record(e))
+	p, err := tl.Preview()
+	require.NoError(t, err)
+	require.Equal(t, Payload{SchemaVersion, s.ID, []Event{e, e}}, p)
+
+	require.NoError(t, tl.MaybeSend(ctx, ts.Client()))
+	require.Equal(t, []Payload{p}, received)
+	events, err = tl.Spooled()
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// Payloads are sent at most once per SendInterval.
+	require.NoError(t, tl.Record(e))
+	require.NoError(t, tl.MaybeSend(ctx, ts.Client()))
+	require.Len(t, received, 1)
+
+	// Disabling keeps the ID, and drops the spool.
+	require.NoError(t, tl.Disable())
+	events, err = tl.Spooled()
+	require.NoError(t, err)
+	require.Empty(t, events)
+	s2, err := tl.Settings()
+	require.NoError(t, err)
+	require.False(t, s2.Enabled)
+	require.Equal(t, s.ID, s2.ID)
+}
+
+func TestRecordDropsOldest(t *testing.T) {
+	tl := &Telemetry{afero.NewMemMapFs(), "/rpk", ""}
+	_, err := tl.Enable()
+	require.NoError(t, err)
+	start := time.Unix(1660000000, 0).UTC()
+	for i := 0; i < MaxSpooled+5; i++ {
+		require.NoError(t, tl.Record(Event{Time: start.Add(time.Duration(i) * time.Second)}))
+	}
+	events, err := tl.Spooled()
+	require.NoError(t, err)
+	require.Len(t, events, MaxSpooled)
+	require.Equal(t, start.Add(5*time.Second), events[0].Time)
+}
@@ -11,20 +11,36 @@ package tuners
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/iotune"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
 
+// IoTuneDeviceResult is the outcome of iotune for the evaluation directories
+// backed by the same physical devices.
+type IoTuneDeviceResult struct {
+	Directories []string
+	Devices     []string
+	Class       iotune.DeviceClass
+	Disks       []iotune.IoProperties
+}
+
 type ioTuner struct {
+	blockDevices    disk.BlockDevices // nil to evaluate all directories at once
 	duration        time.Duration
 	evalDirectories []string
 	fs              afero.Fs
 	ioConfigFile    string
 	timeout         time.Duration
+	newIoTune       func(time.Duration) iotune.IoTune
+	results         []IoTuneDeviceResult
 }
 
 func NewIoTuneTuner(
@@ -33,12 +49,51 @@ func NewIoTuneTuner(
 	ioConfigFile string,
 	duration, timeout time.Duration,
 ) Tunable {
+	return newIoTuner(fs, nil, evalDirectories, ioConfigFile, duration, timeout)
+}
+
+// DeviceIoTuner runs iotune once per set of physical devices backing the
+// evaluation directories, so that hosts mixing storage, e.g. NVMe devices
+// and md-RAID arrays, get the properties of each of them rather than of
+// whichever was evaluated. The IO properties file holds an entry per
+// mountpoint, and the results are reported per device.
+type DeviceIoTuner struct {
+	*ioTuner
+}
+
+func NewDeviceIoTuneTuner(
+	fs afero.Fs,
+	blockDevices disk.BlockDevices,
+	evalDirectories []string,
+	ioConfigFile string,
+	duration, timeout time.Duration,
+) *DeviceIoTuner {
+	return &DeviceIoTuner{newIoTuner(fs, blockDevices, evalDirectories, ioConfigFile, duration, timeout)}
+}
+
+// Results returns the results of the last Tune, in the order of the
+// evaluation directories.
+func (tuner *DeviceIoTuner) Results() []IoTuneDeviceResult {
+	return tuner.results
+}
+
+func newIoTuner(
+	fs afero.Fs,
+	blockDevices disk.BlockDevices,
+	evalDirectories []string,
+	ioConfigFile string,
+	duration, timeout time.Duration,
+) *ioTuner {
 	return &ioTuner{
+		blockDevices:    blockDevices,
 		duration:        duration,
 		evalDirectories: evalDirectories,
 		fs:              fs,
 		ioConfigFile:    ioConfigFile,
 		timeout:         timeout,
+		newIoTune: func(timeout time.Duration) iotune.IoTune {
+			return iotune.NewIoTune(os.NewProc(), timeout)
+		},
 	}
 }
 
@@ -50,20 +105,99 @@ func (tuner *ioTuner) CheckIfSupported() (bool, string) {
 }
 
 func (tuner *ioTuner) Tune() TuneResult {
-	ioTune := iotune.NewIoTune(os.NewProc(), tuner.timeout)
+	if tuner.blockDevices == nil {
+		if err := tuner.run(tuner.evalDirectories, tuner.ioConfigFile); err != nil {
+			return NewTuneError(err)
+		}
+		return NewTuneResult(false)
+	}
+
+	groups, err := tuner.groupByDevices()
+	if err != nil {
+		return NewTuneError(err)
+	}
+	tuner.results = nil
+	var disks []iotune.IoProperties
+	mountpoints := make(map[string]bool)
+	for i, group := range groups {
+		// Directories backed by the same devices share their filesystem,
+		// evaluating the first one is enough.
+		dir := group.Directories[0]
+		log.Infof("Evaluating '%s', backed by %s device(s) %q", dir, group.Class, group.Devices)
+		propertiesFile := fmt.Sprintf("%s.%d.tmp", tuner.ioConfigFile, i)
+		err := tuner.run([]string{dir}, propertiesFile)
+		var conf *iotune.IoConfig
+		if err == nil {
+			conf, err = iotune.ReadIoConfig(tuner.fs, propertiesFile)
+		}
+		if removeErr := tuner.fs.Remove(propertiesFile); removeErr != nil {
+			log.Debugf("Unable to remove '%s': %v", propertiesFile, removeErr)
+		}
+		if err != nil {
+			return NewTuneError(fmt.Errorf("unable to evaluate '%s': %w", dir, err))
+		}
+		group.Disks = conf.Disks
+		for _, d := range conf.Disks {
+			if !mountpoints[d.MountPoint] {
+				mountpoints[d.MountPoint] = true
+				disks = append(disks, d)
+			}
+		}
+		tuner.results = append(tuner.results, group)
+	}
+
+	yaml, err := iotune.ToYamlDisks(disks)
+	if err != nil {
+		return NewTuneError(err)
+	}
+	if _, err := utils.WriteBytes(tuner.fs, []byte(yaml), tuner.ioConfigFile); err != nil {
+		return NewTuneError(err)
+	}
+	return NewTuneResult(false)
+}
+
+func (tuner *ioTuner) run(dirs []string, propertiesFile string) error {
 	args := iotune.IoTuneArgs{
-		Dirs:           tuner.evalDirectories,
+		Dirs:           dirs,
 		Format:         iotune.Seastar,
-		PropertiesFile: tuner.ioConfigFile,
+		PropertiesFile: propertiesFile,
 		Duration:       tuner.duration,
 		FsCheck:        false,
 	}
-	output, err := ioTune.Run(args)
+	output, err := tuner.newIoTune(tuner.timeout).Run(args)
 	for _, outLine := range output {
 		log.Debug(outLine)
 	}
-	if err != nil {
-		return NewTuneError(err)
+	return err
+}
+
+// groupByDevices groups the evaluation directories by the physical devices
+// backing them, resolving the members of RAID arrays. Directories whose
+// devices cannot be resolved are evaluated on their own.
+func (tuner *ioTuner) groupByDevices() ([]IoTuneDeviceResult, error) {
+	var groups []IoTuneDeviceResult
+	byDevices := make(map[string]int)
+	for _, dir := range tuner.evalDirectories {
+		devices, err := tuner.blockDevices.GetDirectoryDevices(dir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the devices of '%s': %w", dir, err)
+		}
+		devices = append([]string(nil), devices...)
+		sort.Strings(devices)
+		key := strings.Join(devices, ",")
+		if len(devices) == 0 {
+			key = "dir:" + dir
+		}
+		if i, ok := byDevices[key]; ok {
+			groups[i].Directories = append(groups[i].Directories, dir)
+			continue
+		}
+		byDevices[key] = len(groups)
+		groups = append(groups, IoTuneDeviceResult{
+			Directories: []string{dir},
+			Devices:     devices,
+			Class:       iotune.ClassifyDevices(devices),
+		})
 	}
-	return NewTuneResult(false)
+	return groups, nil
 }
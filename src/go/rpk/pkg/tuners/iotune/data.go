@@ -14,6 +14,7 @@ import (
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud/vendor"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
 	"gopkg.in/yaml.v3"
 )
 
@@ -27,6 +28,12 @@ type IoProperties struct {
 
 type io = IoProperties
 
+// IoConfig is the content of an IO properties file, with the properties of
+// each mountpoint.
+type IoConfig struct {
+	Disks []IoProperties `yaml:"disks"`
+}
+
 func DataFor(mountPoint, v, vm, storage string) (*IoProperties, error) {
 	data := precompiledData()
 	vms, ok := data[v]
@@ -57,16 +64,31 @@ func DataForVendor(
 }
 
 func ToYaml(props IoProperties) (string, error) {
-	type ioPropertiesWrapper struct {
-		Disks []IoProperties `yaml:"disks"`
-	}
-	yaml, err := yaml.Marshal(ioPropertiesWrapper{[]io{props}})
+	return ToYamlDisks([]IoProperties{props})
+}
+
+// ToYamlDisks returns the IO properties file for the given mountpoints.
+func ToYamlDisks(disks []IoProperties) (string, error) {
+	yaml, err := yaml.Marshal(IoConfig{disks})
 	if err != nil {
 		return "", err
 	}
 	return string(yaml), nil
 }
 
+// ReadIoConfig reads the IO properties file at the given path.
+func ReadIoConfig(fs afero.Fs, path string) (*IoConfig, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var conf IoConfig
+	if err := yaml.Unmarshal(raw, &conf); err != nil {
+		return nil, fmt.Errorf("unable to decode IO properties file %q: %w", path, err)
+	}
+	return &conf, nil
+}
+
 func precompiledData() map[string]map[string]map[string]io {
 	return map[string]map[string]map[string]io{
 		"aws": {
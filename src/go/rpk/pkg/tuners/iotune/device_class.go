@@ -0,0 +1,53 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package iotune
+
+import "strings"
+
+// DeviceClass is the kind of storage backing an evaluation directory. Classes
+// ending in "-raid" are for directories spanning several physical devices,
+// e.g. through md-RAID or LVM.
+type DeviceClass string
+
+const (
+	NvmeClass        DeviceClass = "nvme"
+	NonNvmeClass     DeviceClass = "non-nvme"
+	NvmeRaidClass    DeviceClass = "nvme-raid"
+	NonNvmeRaidClass DeviceClass = "non-nvme-raid"
+	MixedRaidClass   DeviceClass = "mixed-raid"
+	UnknownClass     DeviceClass = "unknown"
+)
+
+// ClassifyDevices returns the class of the storage made of the given physical
+// devices, as named in /dev, e.g. nvme0n1 or sda1.
+func ClassifyDevices(devices []string) DeviceClass {
+	var nvme, nonNvme int
+	for _, d := range devices {
+		if strings.HasPrefix(d, "nvme") {
+			nvme++
+		} else {
+			nonNvme++
+		}
+	}
+	switch {
+	case len(devices) == 0:
+		return UnknownClass
+	case len(devices) == 1 && nvme == 1:
+		return NvmeClass
+	case len(devices) == 1:
+		return NonNvmeClass
+	case nonNvme == 0:
+		return NvmeRaidClass
+	case nvme == 0:
+		return NonNvmeRaidClass
+	default:
+		return MixedRaidClass
+	}
+}
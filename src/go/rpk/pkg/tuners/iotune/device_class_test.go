@@ -0,0 +1,38 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package iotune_test
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/iotune"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyDevices(t *testing.T) {
+	tests := []struct {
+		name    string
+		devices []string
+		exp     iotune.DeviceClass
+	}{
+		{"no devices", nil, iotune.UnknownClass},
+		{"single nvme", []string{"nvme0n1"}, iotune.NvmeClass},
+		{"single nvme partition", []string{"nvme0n1p2"}, iotune.NvmeClass},
+		{"single sata", []string{"sda1"}, iotune.NonNvmeClass},
+		{"nvme raid", []string{"nvme0n1", "nvme1n1"}, iotune.NvmeRaidClass},
+		{"sata raid", []string{"sda", "sdb", "sdc"}, iotune.NonNvmeRaidClass},
+		{"mixed raid", []string{"nvme0n1", "sda"}, iotune.MixedRaidClass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.exp, iotune.ClassifyDevices(tt.devices))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tuners
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/iotune"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// ioTuneMock writes the properties of the mountpoint of the evaluated
+// directory, as iotune does.
+type ioTuneMock struct {
+	fs          afero.Fs
+	mountpoints map[string]string
+	props       map[string]iotune.IoProperties
+	runs        [][]string
+}
+
+func (m *ioTuneMock) Run(args iotune.IoTuneArgs) ([]string, error) {
+	m.runs = append(m.runs, args.Dirs)
+	var disks []iotune.IoProperties
+	for _, dir := range args.Dirs {
+		mountpoint, ok := m.mountpoints[dir]
+		if !ok {
+			return nil, fmt.Errorf("iotune failed on %s", dir)
+		}
+		p := m.props[mountpoint]
+		p.MountPoint = mountpoint
+		disks = append(disks, p)
+	}
+	yaml, err := iotune.ToYamlDisks(disks)
+	if err != nil {
+		return nil, err
+	}
+	return nil, afero.WriteFile(m.fs, args.PropertiesFile, []byte(yaml), 0o644)
+}
+
+func TestDeviceIoTuner(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	devices := map[string][]string{
+		"/mnt/raid/data":  {"nvme1n1", "nvme0n1"},
+		"/mnt/raid/cache": {"nvme0n1", "nvme1n1"},
+		"/mnt/sata/data":  {"sda1"},
+	}
+	ioTune := &ioTuneMock{
+		fs: fs,
+		mountpoints: map[string]string{
+			"/mnt/raid/data":  "/mnt/raid",
+			"/mnt/raid/cache": "/mnt/raid",
+			"/mnt/sata/data":  "/mnt/sata",
+		},
+		props: map[string]iotune.IoProperties{
+			"/mnt/raid": {ReadIops: 800000, ReadBandwidth: 6000000000, WriteIops: 400000, WriteBandwidth: 3000000000},
+			"/mnt/sata": {ReadIops: 90000, ReadBandwidth: 500000000, WriteIops: 80000, WriteBandwidth: 450000000},
+		},
+	}
+	tuner := NewDeviceIoTuneTuner(
+		fs,
+		&blockDevicesMock{
+			getDirectoryDevices: func(dir string) ([]string, error) {
+				return devices[dir], nil
+			},
+		},
+		[]string{"/mnt/raid/data", "/mnt/sata/data", "/mnt/raid/cache"},
+		"/etc/redpanda/io-config.yaml",
+		time.Minute,
+		time.Hour,
+	)
+	tuner.newIoTune = func(time.Duration) iotune.IoTune { return ioTune }
+
+	res := tuner.Tune()
+	require.NoError(t, res.Error())
+
+	// The directories on the RAID array are evaluated once.
+	require.Equal(t, [][]string{{"/mnt/raid/data"}, {"/mnt/sata/data"}}, ioTune.runs)
+
+	results := tuner.Results()
+	require.Len(t, results, 2)
+	require.Equal(t, []string{"/mnt/raid/data", "/mnt/raid/cache"}, results[0].Directories)
+	require.Equal(t, []string{"nvme0n1", "nvme1n1"}, results[0].Devices)
+	require.Equal(t, iotune.NvmeRaidClass, results[0].Class)
+	require.Equal(t, "/mnt/raid", results[0].Disks[0].MountPoint)
+	require.Equal(t, []string{"/mnt/sata/data"}, results[1].Directories)
+	require.Equal(t, iotune.NonNvmeClass, results[1].Class)
+
+	conf, err := iotune.ReadIoConfig(fs, "/etc/redpanda/io-config.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []iotune.IoProperties{
+		{MountPoint: "/mnt/raid", ReadIops: 800000, ReadBandwidth: 6000000000, WriteIops: 400000, WriteBandwidth: 3000000000},
+		{MountPoint: "/mnt/sata", ReadIops: 90000, ReadBandwidth: 500000000, WriteIops: 80000, WriteBandwidth: 450000000},
+	}, conf.Disks)
+
+	// The temporary properties files are removed.
+	files, err := afero.ReadDir(fs, "/etc/redpanda")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestDeviceIoTunerFailure(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	tuner := NewDeviceIoTuneTuner(
+		fs,
+		&blockDevicesMock{
+			getDirectoryDevices: func(string) ([]string, error) {
+				return []string{"sda1"}, nil
+			},
+		},
+		[]string{"/unknown"},
+		"/etc/redpanda/io-config.yaml",
+		time.Minute,
+		time.Hour,
+	)
+	tuner.newIoTune = func(time.Duration) iotune.IoTune {
+		return &ioTuneMock{fs: fs}
+	}
+	res := tuner.Tune()
+	require.EqualError(t, res.Error(), "unable to evaluate '/unknown': iotune failed on /unknown")
+	exists, _ := afero.Exists(fs, "/etc/redpanda/io-config.yaml")
+	require.False(t, exists)
+}
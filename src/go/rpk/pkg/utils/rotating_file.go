@@ -0,0 +1,135 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// KeepAllRotations is the keep argument of OpenRotatingFile to never delete
+// rotated files.
+const KeepAllRotations = -1
+
+// RotatingFile is an io.WriteCloser appending to a file, which is rotated
+// when a write would grow it past a maximum size. A single write is never
+// split across files. It is safe for concurrent use.
+type RotatingFile struct {
+	mu       sync.Mutex
+	fs       afero.Fs
+	path     string
+	maxBytes int64 // 0 disables rotation
+	keep     int
+
+	f    afero.File
+	size int64
+}
+
+// OpenRotatingFile opens the file at path for appending, and rotates it once
+// it reaches maxBytes, 0 disabling rotation. The rotated files are:
+//
+//   - shifted from path.1, the newest, up to path.<keep> if keep is positive,
+//   - deleted if keep is 0,
+//   - all kept if keep is KeepAllRotations, each renamed to the first free
+//     path.N, so that the numbers increase with the age of the content.
+func OpenRotatingFile(
+	fs afero.Fs, path string, maxBytes int64, keep int,
+) (*RotatingFile, error) {
+	r := &RotatingFile{fs: fs, path: path, maxBytes: maxBytes, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := r.fs.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open %q: %v", r.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("unable to stat %q: %v", r.path, err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// Write writes p to the file, rotating the file first if p does not fit.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return fmt.Errorf("unable to close %q: %v", r.path, err)
+	}
+	switch {
+	case r.keep == 0:
+		if err := r.fs.Remove(r.path); err != nil {
+			return fmt.Errorf("unable to remove %q: %v", r.path, err)
+		}
+
+	case r.keep < 0:
+		for n := 1; ; n++ {
+			rotated := fmt.Sprintf("%s.%d", r.path, n)
+			if _, err := r.fs.Stat(rotated); err == nil {
+				continue
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("unable to stat %q: %v", rotated, err)
+			}
+			if err := r.fs.Rename(r.path, rotated); err != nil {
+				return fmt.Errorf("unable to rotate %q to %q: %v", r.path, rotated, err)
+			}
+			break
+		}
+
+	default:
+		for i := r.keep - 1; i >= 1; i-- {
+			from := fmt.Sprintf("%s.%d", r.path, i)
+			to := fmt.Sprintf("%s.%d", r.path, i+1)
+			if err := r.fs.Rename(from, to); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("unable to rotate %q to %q: %v", from, to, err)
+			}
+		}
+		if err := r.fs.Rename(r.path, r.path+".1"); err != nil {
+			return fmt.Errorf("unable to rotate %q: %v", r.path, err)
+		}
+	}
+	return r.open()
+}
+
+// Sync flushes the file to disk.
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Sync()
+}
+
+// Close closes the file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileKeepAll(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "out.1", []byte("old rotation\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "out", []byte("resumed\n"), 0o644))
+
+	r, err := OpenRotatingFile(fs, "out", 16, KeepAllRotations)
+	require.NoError(t, err)
+	for _, rec := range []string{"aaaa\n", "bbbb\n", "a record larger than the limit\n", "cccc\n"} {
+		_, err := r.Write([]byte(rec))
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Close())
+
+	for path, exp := range map[string]string{
+		"out.1": "old rotation\n",
+		"out.2": "resumed\naaaa\n",
+		"out.3": "bbbb\n",
+		"out.4": "a record larger than the limit\n",
+		"out":   "cccc\n",
+	} {
+		got, err := afero.ReadFile(fs, path)
+		require.NoError(t, err, path)
+		require.Equal(t, exp, string(got), path)
+	}
+}
+
+func TestRotatingFileKeep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redpanda.log")
+	l, err := OpenRotatingFile(afero.NewOsFs(), path, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n", "six\n"} {
+		_, err := l.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, l.Close())
+
+	for file, exp := range map[string]string{
+		path:        "six\n",
+		path + ".1": "four\nfive\n",
+		path + ".2": "three\n",
+	} {
+		got, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, exp, string(got), file)
+	}
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileKeepNone(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	r, err := OpenRotatingFile(fs, "log", 8, 0)
+	require.NoError(t, err)
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err := r.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Close())
+
+	got, err := afero.ReadFile(fs, "log")
+	require.NoError(t, err)
+	require.Equal(t, "second\n", string(got))
+	_, err = fs.Stat("log.1")
+	require.Error(t, err, "no rotated file is kept")
+}